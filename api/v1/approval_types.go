@@ -37,6 +37,10 @@ const (
 	ApprovalResultError    ApprovalResult = "Error"
 )
 
+// ApproverGitPrefix is a prefix of the approvers' names which are git users, who can approve/reject the Approvals by
+// commenting on the pull requests, e.g., git:octocat
+const ApproverGitPrefix = "git:"
+
 // Condition keys for Approval
 const (
 	ApprovalConditionSentRequestMail = "SentRequestMail"
@@ -0,0 +1,187 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ChatOpsCommandPermission is a permission required to call a custom chat-ops command
+type ChatOpsCommandPermission string
+
+// ChatOpsCommandPermissions
+const (
+	// ChatOpsCommandPermissionAuthor allows the pull request's author and the users who can write to the repository
+	ChatOpsCommandPermissionAuthor = ChatOpsCommandPermission("author")
+	// ChatOpsCommandPermissionWrite allows the users who can write to the repository
+	ChatOpsCommandPermissionWrite = ChatOpsCommandPermission("write")
+	// ChatOpsCommandPermissionAdmin allows the admins of the repository
+	ChatOpsCommandPermissionAdmin = ChatOpsCommandPermission("admin")
+)
+
+// ChatOpsCommand is a custom chat-ops command, which triggers the jobs for the pull request it's commented on,
+// e.g., `/deploy staging`
+type ChatOpsCommand struct {
+	// Name of the command, i.e., the command is called by commenting /<name>. The built-in commands take precedence
+	// over the custom command with the same name
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9-]*$`
+	Name string `json:"name"`
+
+	// Description of the command, listed by /help
+	Description string `json:"description,omitempty"`
+
+	// Jobs are the names of the preSubmit jobs (including the manual ones) triggered by the command. The jobs they
+	// depend on are triggered together
+	// +kubebuilder:validation:MinItems=1
+	Jobs []string `json:"jobs"`
+
+	// Args are the parameters set by the arguments of the command, in order. The parameters should be defined in the
+	// paramConfig.paramDefine. An array parameter takes all the remaining arguments, so it should be the last one
+	Args []ChatOpsCommandArg `json:"args,omitempty"`
+
+	// Permission is a permission required to call the command. Default is write
+	// +kubebuilder:validation:Enum=author;write;admin
+	Permission ChatOpsCommandPermission `json:"permission,omitempty"`
+
+	// Users are the users allowed to call the command, regardless of the permission
+	Users []string `json:"users,omitempty"`
+}
+
+// ChatOpsCommandArg is an argument of a custom chat-ops command
+type ChatOpsCommandArg struct {
+	// Param is a name of the parameter set by the argument
+	Param string `json:"param"`
+
+	// Required makes the argument required. The parameter's default value is used if an optional argument is omitted
+	Required bool `json:"required,omitempty"`
+}
+
+// GetPermission returns the permission required to call the command
+func (c *ChatOpsCommand) GetPermission() ChatOpsCommandPermission {
+	if c.Permission == "" {
+		return ChatOpsCommandPermissionWrite
+	}
+	return c.Permission
+}
+
+// GetUsage returns the usage of the command, e.g., /deploy <env> [<version>]
+func (c *ChatOpsCommand) GetUsage() string {
+	tokens := []string{"/" + c.Name}
+	for _, a := range c.Args {
+		if a.Required {
+			tokens = append(tokens, fmt.Sprintf("<%s>", a.Param))
+		} else {
+			tokens = append(tokens, fmt.Sprintf("[<%s>]", a.Param))
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// GetParamValues converts the arguments of the command into the parameter values, validating them against the
+// parameters' definitions
+func (c *ChatOpsCommand) GetParamValues(args []string, paramConfig *ParameterConfig) ([]ParameterValue, error) {
+	defines := map[string]ParameterDefine{}
+	if paramConfig != nil {
+		for _, d := range paramConfig.ParamDefine {
+			defines[d.Name] = d
+		}
+	}
+
+	var values []ParameterValue
+	for i, a := range c.Args {
+		if i >= len(args) {
+			if a.Required {
+				return nil, fmt.Errorf("argument %s is required", a.Param)
+			}
+			continue
+		}
+
+		d, exist := defines[a.Param]
+		if !exist {
+			return nil, fmt.Errorf("parameter %s is not defined", a.Param)
+		}
+		v := ParameterValue{Name: a.Param}
+		if d.GetType() == ParameterTypeArray {
+			v.ArrayVal = args[i:]
+		} else {
+			v.StringVal = args[i]
+		}
+		if err := d.ValidateValue(v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	// Only the array argument can take more than one argument
+	if len(args) > len(c.Args) && (len(values) == 0 || values[len(values)-1].ArrayVal == nil) {
+		return nil, fmt.Errorf("too many arguments")
+	}
+	return values, nil
+}
+
+// ChatOpsCommands are custom chat-ops commands
+type ChatOpsCommands []ChatOpsCommand
+
+var chatOpsCommandNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Validate checks if the commands have valid and unique names, and their arguments set the defined parameters
+func (c ChatOpsCommands) Validate(paramConfig *ParameterConfig) error {
+	defines := map[string]ParameterDefine{}
+	if paramConfig != nil {
+		for _, d := range paramConfig.ParamDefine {
+			defines[d.Name] = d
+		}
+	}
+
+	names := map[string]struct{}{}
+	for i, cmd := range c {
+		if !chatOpsCommandNameRegexp.MatchString(cmd.Name) {
+			return fmt.Errorf("commands[%d] has an invalid name %s", i, cmd.Name)
+		}
+		if _, exist := names[cmd.Name]; exist {
+			return fmt.Errorf("command %s is defined more than once", cmd.Name)
+		}
+		names[cmd.Name] = struct{}{}
+
+		if len(cmd.Jobs) == 0 {
+			return fmt.Errorf("command %s should trigger at least one job", cmd.Name)
+		}
+
+		for j, a := range cmd.Args {
+			d, exist := defines[a.Param]
+			if !exist {
+				return fmt.Errorf("command %s's argument %s is not defined in paramConfig.paramDefine", cmd.Name, a.Param)
+			}
+			if d.GetType() == ParameterTypeArray && j != len(cmd.Args)-1 {
+				return fmt.Errorf("command %s's array argument %s should be the last one", cmd.Name, a.Param)
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the command of the name. Returns nil if it does not exist
+func (c ChatOpsCommands) Find(name string) *ChatOpsCommand {
+	for i := range c {
+		if c[i].Name == name {
+			return &c[i]
+		}
+	}
+	return nil
+}
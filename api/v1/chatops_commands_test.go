@@ -0,0 +1,163 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testChatOpsParamConfig() *ParameterConfig {
+	return &ParameterConfig{
+		ParamDefine: []ParameterDefine{
+			{Name: "env", Type: ParameterTypeString, Enum: []string{"staging", "production"}},
+			{Name: "dry-run", Type: ParameterTypeBoolean},
+			{Name: "tags", Type: ParameterTypeArray},
+		},
+	}
+}
+
+func TestChatOpsCommands_Validate(t *testing.T) {
+	tc := map[string]struct {
+		commands ChatOpsCommands
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"noCommand": {},
+		"valid": {
+			commands: ChatOpsCommands{
+				{Name: "deploy", Jobs: []string{"deploy"}, Args: []ChatOpsCommandArg{{Param: "env", Required: true}, {Param: "tags"}}},
+				{Name: "build-all", Jobs: []string{"build"}},
+			},
+		},
+		"invalidName": {
+			commands:     ChatOpsCommands{{Name: "Deploy", Jobs: []string{"deploy"}}},
+			errorOccurs:  true,
+			errorMessage: "commands[0] has an invalid name Deploy",
+		},
+		"duplicatedName": {
+			commands:     ChatOpsCommands{{Name: "deploy", Jobs: []string{"deploy"}}, {Name: "deploy", Jobs: []string{"build"}}},
+			errorOccurs:  true,
+			errorMessage: "command deploy is defined more than once",
+		},
+		"noJob": {
+			commands:     ChatOpsCommands{{Name: "deploy"}},
+			errorOccurs:  true,
+			errorMessage: "command deploy should trigger at least one job",
+		},
+		"undefinedParam": {
+			commands:     ChatOpsCommands{{Name: "deploy", Jobs: []string{"deploy"}, Args: []ChatOpsCommandArg{{Param: "version"}}}},
+			errorOccurs:  true,
+			errorMessage: "command deploy's argument version is not defined in paramConfig.paramDefine",
+		},
+		"arrayNotLast": {
+			commands:     ChatOpsCommands{{Name: "deploy", Jobs: []string{"deploy"}, Args: []ChatOpsCommandArg{{Param: "tags"}, {Param: "env"}}}},
+			errorOccurs:  true,
+			errorMessage: "command deploy's array argument tags should be the last one",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.commands.Validate(testChatOpsParamConfig())
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChatOpsCommand_GetParamValues(t *testing.T) {
+	cmd := &ChatOpsCommand{
+		Name: "deploy",
+		Args: []ChatOpsCommandArg{{Param: "env", Required: true}, {Param: "dry-run"}, {Param: "tags"}},
+	}
+
+	tc := map[string]struct {
+		command *ChatOpsCommand
+		args    []string
+
+		expectedValues []ParameterValue
+		errorOccurs    bool
+		errorMessage   string
+	}{
+		"all": {
+			command: cmd,
+			args:    []string{"staging", "true", "v1", "v2"},
+			expectedValues: []ParameterValue{
+				{Name: "env", StringVal: "staging"},
+				{Name: "dry-run", StringVal: "true"},
+				{Name: "tags", ArrayVal: []string{"v1", "v2"}},
+			},
+		},
+		"optionalOmitted": {
+			command:        cmd,
+			args:           []string{"production"},
+			expectedValues: []ParameterValue{{Name: "env", StringVal: "production"}},
+		},
+		"requiredOmitted": {
+			command:      cmd,
+			errorOccurs:  true,
+			errorMessage: "argument env is required",
+		},
+		"invalidValue": {
+			command:      cmd,
+			args:         []string{"staging", "yes"},
+			errorOccurs:  true,
+			errorMessage: "parameter dry-run should be a boolean (true or false), but got \"yes\"",
+		},
+		"tooManyArgs": {
+			command:      &ChatOpsCommand{Name: "deploy", Args: []ChatOpsCommandArg{{Param: "env"}}},
+			args:         []string{"staging", "production"},
+			errorOccurs:  true,
+			errorMessage: "too many arguments",
+		},
+		"noArgs": {
+			command:      &ChatOpsCommand{Name: "build"},
+			args:         []string{"now"},
+			errorOccurs:  true,
+			errorMessage: "too many arguments",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			values, err := c.command.GetParamValues(c.args, testChatOpsParamConfig())
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expectedValues, values)
+			}
+		})
+	}
+}
+
+func TestChatOpsCommand_GetUsage(t *testing.T) {
+	cmd := &ChatOpsCommand{
+		Name: "deploy",
+		Args: []ChatOpsCommandArg{{Param: "env", Required: true}, {Param: "tags"}},
+	}
+	require.Equal(t, "/deploy <env> [<tags>]", cmd.GetUsage())
+	require.Equal(t, ChatOpsCommandPermissionWrite, cmd.GetPermission())
+}
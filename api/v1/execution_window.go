@@ -0,0 +1,98 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	cron "gopkg.in/robfig/cron.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExecutionWindow is a time window in which the IntegrationJobs are allowed to run
+type ExecutionWindow struct {
+	// Cron is a cron expression of the times the window opens at, e.g., "0 22 * * 1-5" for 22:00 on weekdays
+	Cron string `json:"cron"`
+
+	// Duration is how long the window is open after it opens, e.g., 8h
+	Duration metav1.Duration `json:"duration"`
+
+	// Timezone is a time zone name (e.g., Asia/Seoul) the cron is interpreted in. Default is UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// GetCronSpec returns a cron spec with the timezone prefix
+func (w *ExecutionWindow) GetCronSpec() string {
+	tz := w.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return fmt.Sprintf("TZ=%s %s", tz, w.Cron)
+}
+
+// ExecutionWindows are the time windows in which the IntegrationJobs are allowed to run. The IntegrationJobs can run at
+// any time if there is no window
+type ExecutionWindows []ExecutionWindow
+
+// Validate validates the crons, timezones and durations of the windows
+func (e ExecutionWindows) Validate() error {
+	for i, w := range e {
+		if _, err := cron.Parse(w.GetCronSpec()); err != nil {
+			return fmt.Errorf("executionWindows[%d] has an invalid cron: %s", i, err.Error())
+		}
+		if w.Duration.Duration <= 0 {
+			return fmt.Errorf("executionWindows[%d] should have a positive duration", i)
+		}
+	}
+	return nil
+}
+
+// IsOpen returns if any window is open at the time. The invalid windows are ignored
+func (e ExecutionWindows) IsOpen(now time.Time) bool {
+	if len(e) == 0 {
+		return true
+	}
+	for _, w := range e {
+		schedule, err := cron.Parse(w.GetCronSpec())
+		if err != nil {
+			continue
+		}
+		// The window is open if it has opened within the duration
+		if !schedule.Next(now.Add(-w.Duration.Duration)).After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpening returns the earliest time any window opens after the time. It returns zero time if there is no valid
+// window
+func (e ExecutionWindows) NextOpening(now time.Time) time.Time {
+	var next time.Time
+	for _, w := range e {
+		schedule, err := cron.Parse(w.GetCronSpec())
+		if err != nil {
+			continue
+		}
+		t := schedule.Next(now)
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
@@ -0,0 +1,117 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExecutionWindows_Validate(t *testing.T) {
+	tc := map[string]struct {
+		windows ExecutionWindows
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"noWindow": {},
+		"valid": {
+			windows: ExecutionWindows{{Cron: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 8 * time.Hour}, Timezone: "Asia/Seoul"}},
+		},
+		"invalidCron": {
+			windows:      ExecutionWindows{{Cron: "0 22 * *", Duration: metav1.Duration{Duration: time.Hour}}},
+			errorOccurs:  true,
+			errorMessage: "executionWindows[0] has an invalid cron: Expected 5 or 6 fields, found 4: 0 22 * *",
+		},
+		"invalidTimezone": {
+			windows:      ExecutionWindows{{Cron: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}, Timezone: "Nowhere/City"}},
+			errorOccurs:  true,
+			errorMessage: "executionWindows[0] has an invalid cron: Provided bad location Nowhere/City: unknown time zone Nowhere/City",
+		},
+		"noDuration": {
+			windows:      ExecutionWindows{{Cron: "0 22 * * *"}},
+			errorOccurs:  true,
+			errorMessage: "executionWindows[0] should have a positive duration",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.windows.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExecutionWindows_IsOpen(t *testing.T) {
+	// 2022-03-02 (Wed) 23:30 in Asia/Seoul
+	now := time.Date(2022, 3, 2, 14, 30, 0, 0, time.UTC)
+	nightly := ExecutionWindow{Cron: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 8 * time.Hour}, Timezone: "Asia/Seoul"}
+	morning := ExecutionWindow{Cron: "0 6 * * *", Duration: metav1.Duration{Duration: time.Hour}}
+
+	tc := map[string]struct {
+		windows ExecutionWindows
+		now     time.Time
+
+		expectedOpen bool
+		expectedNext time.Time
+	}{
+		"noWindow": {
+			now:          now,
+			expectedOpen: true,
+		},
+		"open": {
+			windows:      ExecutionWindows{nightly},
+			now:          now,
+			expectedOpen: true,
+			expectedNext: time.Date(2022, 3, 3, 13, 0, 0, 0, time.UTC),
+		},
+		"closed": {
+			windows:      ExecutionWindows{nightly},
+			now:          now.Add(10 * time.Hour),
+			expectedOpen: false,
+			expectedNext: time.Date(2022, 3, 3, 13, 0, 0, 0, time.UTC),
+		},
+		"anyOpen": {
+			windows:      ExecutionWindows{morning, nightly},
+			now:          time.Date(2022, 3, 3, 6, 30, 0, 0, time.UTC),
+			expectedOpen: true,
+			expectedNext: time.Date(2022, 3, 3, 13, 0, 0, 0, time.UTC),
+		},
+		"earliestNext": {
+			windows:      ExecutionWindows{nightly, morning},
+			now:          time.Date(2022, 3, 3, 0, 0, 0, 0, time.UTC),
+			expectedOpen: false,
+			expectedNext: time.Date(2022, 3, 3, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedOpen, c.windows.IsOpen(c.now))
+			require.True(t, c.expectedNext.Equal(c.windows.NextOpening(c.now)), c.windows.NextOpening(c.now).String())
+		})
+	}
+}
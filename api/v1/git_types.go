@@ -43,6 +43,12 @@ type GitConfig struct {
 	// +kubebuilder:validation:Pattern=.+/.+
 	Repository string `json:"repository"`
 
+	// Repositories are additional repositories (in <org>/<repo> form) sharing the same configuration with Repository.
+	// Webhooks are registered to each of them and the events are routed by the repository name, so that many similar
+	// repositories can be served by a single IntegrationConfig. The repositories should be in the same git server and
+	// be accessible with the same Token
+	Repositories []string `json:"repositories,omitempty"`
+
 	// APIUrl for api server (e.g., https://api.github.com for github type),
 	// for the case where the git repository is self-hosted (should contain specific protocol otherwise webhook server returns error)
 	// Also, it should *NOT* contain repository path (e.g., tmax-cloud/cicd-operator)
@@ -51,6 +57,14 @@ type GitConfig struct {
 	// Token is a token for accessing the remote git server. It can be empty, if you don't want to register a webhook
 	// to the git server
 	Token *GitToken `json:"token,omitempty"`
+
+	// SkipDraftPR skips preSubmit jobs for draft pull requests. The jobs are triggered when the pull request is marked as ready for review
+	SkipDraftPR bool `json:"skipDraftPR,omitempty"`
+
+	// CheckRuns reports the jobs as check runs of the Checks API, instead of commit statuses, with markdown summaries
+	// and file/line annotations of the jobs' lint issues. Re-running a check run from the UI re-runs the job
+	// Only supported for GitHub, and the Token should be an installation token of a GitHub App
+	CheckRuns bool `json:"checkRuns,omitempty"`
 }
 
 // GetGitHost gets git host
@@ -69,6 +83,28 @@ func (config *GitConfig) GetGitHost() (string, error) {
 	return fmt.Sprintf("%s://%s", gitU.Scheme, gitU.Host), nil
 }
 
+// GetRepositories returns all the repositories of the config, starting with Repository
+func (config *GitConfig) GetRepositories() []string {
+	repos := []string{config.Repository}
+	for _, r := range config.Repositories {
+		if r == "" || strings.EqualFold(r, config.Repository) {
+			continue
+		}
+		repos = append(repos, r)
+	}
+	return repos
+}
+
+// HasRepository checks if the repository is one of the repositories of the config
+func (config *GitConfig) HasRepository(repo string) bool {
+	for _, r := range config.GetRepositories() {
+		if strings.EqualFold(r, repo) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAPIUrl returns APIUrl for api server
 func (config *GitConfig) GetAPIUrl() string {
 	if config.Type == GitTypeGitHub && config.APIUrl == "" {
@@ -136,3 +136,34 @@ type gitTypeTestCase struct {
 	Input          GitRef
 	ExpectedOutput string
 }
+
+func TestGitConfig_GetRepositories(t *testing.T) {
+	tc := map[string]struct {
+		cfg *GitConfig
+
+		expectedRepos []string
+	}{
+		"single": {
+			cfg:           &GitConfig{Repository: "tmax-cloud/cicd-operator"},
+			expectedRepos: []string{"tmax-cloud/cicd-operator"},
+		},
+		"multiple": {
+			cfg:           &GitConfig{Repository: "tmax-cloud/cicd-operator", Repositories: []string{"tmax-cloud/a", "", "Tmax-Cloud/CICD-Operator", "tmax-cloud/b"}},
+			expectedRepos: []string{"tmax-cloud/cicd-operator", "tmax-cloud/a", "tmax-cloud/b"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedRepos, c.cfg.GetRepositories())
+		})
+	}
+}
+
+func TestGitConfig_HasRepository(t *testing.T) {
+	cfg := &GitConfig{Repository: "tmax-cloud/cicd-operator", Repositories: []string{"tmax-cloud/a"}}
+	require.True(t, cfg.HasRepository("tmax-cloud/cicd-operator"))
+	require.True(t, cfg.HasRepository("Tmax-Cloud/A"))
+	require.False(t, cfg.HasRepository("tmax-cloud/b"))
+	require.False(t, cfg.HasRepository(""))
+}
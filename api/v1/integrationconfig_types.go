@@ -20,11 +20,13 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/expression"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,8 +41,9 @@ const (
 
 // Condition keys for IntegrationConfig
 const (
-	IntegrationConfigConditionWebhookRegistered = "webhook-registered"
-	IntegrationConfigConditionReady             = "ready"
+	IntegrationConfigConditionWebhookRegistered      = "webhook-registered"
+	IntegrationConfigConditionReady                  = "ready"
+	IntegrationConfigConditionBranchProtectionSynced = "branch-protection-synced"
 )
 
 // IntegrationConfigConditionReasonNoGitToken is a Reason key
@@ -68,16 +71,128 @@ type IntegrationConfigSpec struct {
 	// PodTemplate for the TaskRun pods. Same as tekton's pod template. Refer to https://github.com/tektoncd/pipeline/blob/master/docs/podtemplates.md
 	PodTemplate *pod.Template `json:"podTemplate,omitempty"`
 
+	// Env is a list of environment variables set for every job. The ones set in the jobs take precedence
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// VolumeMounts are mounted to every job, e.g., for the volumes specified in the podTemplate
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// SecurityContext is a security context of every step of the jobs, including the git checkout step. The ones set in
+	// the jobs take precedence. Pod-level security context can be set in the podTemplate
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Checkout configures the git checkout step of every job. The options set in the jobs take precedence
+	Checkout *JobCheckout `json:"checkout,omitempty"`
+
+	// ResourceQuota is a budget (e.g., cpu, memory) for the aggregate resource requests of the jobs of the
+	// IntegrationJobs running at the same time. IntegrationJobs exceeding the budget are not scheduled until the running
+	// ones are completed
+	ResourceQuota corev1.ResourceList `json:"resourceQuota,omitempty"`
+
+	// Concurrency limits the number of the IntegrationJobs running at the same time
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
+
+	// Parallelism limits the number of the jobs of an IntegrationJob running at the same time, e.g., for the jobs sharing
+	// an external resource like a license server. There is no limit if it is 0
+	// +kubebuilder:validation:Minimum=0
+	Parallelism int32 `json:"parallelism,omitempty"`
+
+	// ExecutionWindows are the time windows in which the IntegrationJobs are allowed to run, e.g., for running heavyweight
+	// jobs only off-peak. IntegrationJobs triggered outside the windows wait until any of them opens
+	ExecutionWindows ExecutionWindows `json:"executionWindows,omitempty"`
+
+	// CancelSuperseded cancels the IntegrationJobs still running for the older commits of a pull request or a branch,
+	// when a new commit is pushed to it
+	CancelSuperseded bool `json:"cancelSuperseded,omitempty"`
+
+	// BranchProtection keeps the required status checks of the branches on the git server in sync with the preSubmit
+	// jobs
+	BranchProtection *BranchProtection `json:"branchProtection,omitempty"`
+
+	// AggregateCommitStatus reports a single commit status summarizing all the jobs of an IntegrationJob, instead of a
+	// commit status for each job
+	AggregateCommitStatus *AggregateCommitStatus `json:"aggregateCommitStatus,omitempty"`
+
+	// RemoteCluster is a cluster the PipelineRuns are run in, instead of the operator's cluster. The statuses are still
+	// reported by the operator
+	RemoteCluster *RemoteCluster `json:"remoteCluster,omitempty"`
+
 	// IJManageSpec defines variables to manage created integration jobs
 	IJManageSpec IntegrationJobManageSpec `json:"ijManageSpec,omitempty"`
 
 	// ParamConfig specifies parameter
 	ParamConfig *ParameterConfig `json:"paramConfig,omitempty"`
 
+	// Commands are custom chat-ops commands triggering the jobs for the pull requests, e.g., /deploy staging
+	Commands ChatOpsCommands `json:"commands,omitempty"`
+
+	// Stale labels the inactive pull requests as stale and closes them if they stay inactive
+	Stale *Stale `json:"stale,omitempty"`
+
 	// TLSConfig set tls configurations
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 }
 
+// ConcurrencyGroup is a key grouping the IntegrationJobs
+type ConcurrencyGroup string
+
+// Concurrency groups
+const (
+	ConcurrencyGroupBranch      = ConcurrencyGroup("branch")
+	ConcurrencyGroupPullRequest = ConcurrencyGroup("pullRequest")
+)
+
+// Concurrency limits the number of the IntegrationJobs running at the same time
+type Concurrency struct {
+	// Max is the maximum number of the IntegrationJobs running at the same time. Zero means no limit
+	// +kubebuilder:validation:Minimum=0
+	Max int `json:"max,omitempty"`
+
+	// Group groups the IntegrationJobs by their branches or pull requests, to limit the number of the running
+	// IntegrationJobs of each group by maxPerGroup
+	// +kubebuilder:validation:Enum=branch;pullRequest
+	Group ConcurrencyGroup `json:"group,omitempty"`
+
+	// MaxPerGroup is the maximum number of the IntegrationJobs of each group running at the same time. Default is 1
+	// +kubebuilder:validation:Minimum=0
+	MaxPerGroup int `json:"maxPerGroup,omitempty"`
+}
+
+// GetMaxPerGroup returns the maximum number of the running IntegrationJobs of each group, defaulting to 1
+func (c *Concurrency) GetMaxPerGroup() int {
+	if c.MaxPerGroup <= 0 {
+		return 1
+	}
+	return c.MaxPerGroup
+}
+
+// BranchProtection configures the required status checks of the branches on the git server
+type BranchProtection struct {
+	// Branches whose required status checks are synchronized with the names of the preSubmit jobs for the pull requests
+	// to them
+	// +kubebuilder:validation:MinItems=1
+	Branches []string `json:"branches"`
+}
+
+// DefaultAggregateCommitStatusContext is a default context of the aggregated commit status
+const DefaultAggregateCommitStatusContext = "cicd/summary"
+
+// AggregateCommitStatus reports a single commit status for an IntegrationJob
+type AggregateCommitStatus struct {
+	// Context is a context of the aggregated commit status. Default is cicd/summary
+	Context string `json:"context,omitempty"`
+}
+
+// RemoteClusterKubeconfigKey is a key of the kubeconfig in the secret of the RemoteCluster
+const RemoteClusterKubeconfigKey = "kubeconfig"
+
+// RemoteCluster is a cluster the PipelineRuns are run in. The PipelineRuns are created in the namespace with the same
+// name as the IntegrationConfig's one
+type RemoteCluster struct {
+	// KubeconfigSecret is a name of the secret containing the kubeconfig of the cluster in the kubeconfig key
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
 // TLSConfig is parameters for tls connection
 type TLSConfig struct {
 	// InsecureSkipVerify is flag for accepting any certificate presented by the server and any host name in that certificate.
@@ -92,34 +207,161 @@ type ParameterConfig struct {
 	ParamValue []ParameterValue `json:"paramValue,omitempty"`
 }
 
+// Validate checks if the parameters are defined only once, and their default values and values are valid
+func (p *ParameterConfig) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	defines := map[string]*ParameterDefine{}
+	for i := range p.ParamDefine {
+		d := &p.ParamDefine[i]
+		if _, exist := defines[d.Name]; exist {
+			return fmt.Errorf("parameter %s is defined more than once", d.Name)
+		}
+		if err := d.ValidateDefault(); err != nil {
+			return err
+		}
+		defines[d.Name] = d
+	}
+
+	for _, v := range p.ParamValue {
+		d, exist := defines[v.Name]
+		if !exist {
+			continue
+		}
+		if err := d.ValidateValue(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParameterType is a type of parameter
+type ParameterType string
+
+// Parameter types
+const (
+	ParameterTypeString  = ParameterType("string")
+	ParameterTypeArray   = ParameterType("array")
+	ParameterTypeBoolean = ParameterType("boolean")
+)
+
 // ParameterDefine defines a parameter's name, description & default values
 type ParameterDefine struct {
-	Name         string   `json:"name"`
+	Name string `json:"name"`
+
+	// Type of the parameter. If it's not set, the parameter is an array if DefaultArray is set, or a string otherwise,
+	// and its values are not validated
+	// Boolean parameters are passed to the jobs as strings, i.e., "true" or "false" (default)
+	// +kubebuilder:validation:Enum=string;array;boolean
+	Type ParameterType `json:"type,omitempty"`
+
 	DefaultStr   string   `json:"defaultStr,omitempty"`
 	DefaultArray []string `json:"defaultArray,omitempty"`
 	Description  string   `json:"description,omitempty"`
+
+	// Enum is a list of allowed values. For array parameters, each item of the value should be one of them
+	Enum []string `json:"enum,omitempty"`
+}
+
+// GetType returns the parameter's type, inferring it from the default values if it's not set
+func (p *ParameterDefine) GetType() ParameterType {
+	if p.Type != "" {
+		return p.Type
+	}
+	if p.DefaultArray != nil {
+		return ParameterTypeArray
+	}
+	return ParameterTypeString
+}
+
+// ValidateDefault checks if the default value matches the type and the enum
+func (p *ParameterDefine) ValidateDefault() error {
+	switch p.Type {
+	case "":
+		return nil
+	case ParameterTypeArray:
+		if p.DefaultStr != "" {
+			return fmt.Errorf("parameter %s is an array, but defaultStr is set", p.Name)
+		}
+		return p.ValidateValue(ParameterValue{Name: p.Name, ArrayVal: p.DefaultArray})
+	default:
+		if p.DefaultArray != nil {
+			return fmt.Errorf("parameter %s is a %s, but defaultArray is set", p.Name, p.Type)
+		}
+		// Empty default means that there's no default value
+		if p.DefaultStr == "" {
+			return nil
+		}
+		return p.ValidateValue(ParameterValue{Name: p.Name, StringVal: p.DefaultStr})
+	}
+}
+
+// ValidateValue checks if the value matches the parameter's type and enum
+// Values of parameters without a type are not validated
+func (p *ParameterDefine) ValidateValue(v ParameterValue) error {
+	switch p.Type {
+	case "":
+		return nil
+	case ParameterTypeArray:
+		if v.StringVal != "" {
+			return fmt.Errorf("parameter %s should be an array", p.Name)
+		}
+		for _, item := range v.ArrayVal {
+			if err := p.validateEnum(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ParameterTypeBoolean:
+		if v.ArrayVal != nil {
+			return fmt.Errorf("parameter %s should be a boolean", p.Name)
+		}
+		if v.StringVal != "true" && v.StringVal != "false" {
+			return fmt.Errorf("parameter %s should be a boolean (true or false), but got %q", p.Name, v.StringVal)
+		}
+		return p.validateEnum(v.StringVal)
+	default:
+		if v.ArrayVal != nil {
+			return fmt.Errorf("parameter %s should be a string", p.Name)
+		}
+		return p.validateEnum(v.StringVal)
+	}
+}
+
+func (p *ParameterDefine) validateEnum(val string) error {
+	if len(p.Enum) == 0 {
+		return nil
+	}
+	for _, e := range p.Enum {
+		if e == val {
+			return nil
+		}
+	}
+	return fmt.Errorf("parameter %s should be one of [%s], but got %q", p.Name, strings.Join(p.Enum, ", "), val)
 }
 
 // ConvertToTektonParamSpecs converts ParameterDefine array to tekton ParamSpec array
 func ConvertToTektonParamSpecs(params []ParameterDefine) []tektonv1beta1.ParamSpec {
 	var tektonParamSpecs []tektonv1beta1.ParamSpec
 	for _, p := range params {
-		if p.DefaultArray != nil {
-			tektonParamSpecs = append(tektonParamSpecs, tektonv1beta1.ParamSpec{
-				Name:        p.Name,
-				Type:        tektonv1beta1.ParamTypeArray,
-				Description: p.Description,
-				Default:     tektonv1beta1.NewArrayOrString(p.DefaultArray[0], p.DefaultArray[1:]...),
-			})
+		spec := tektonv1beta1.ParamSpec{
+			Name:        p.Name,
+			Type:        tektonv1beta1.ParamTypeString,
+			Description: p.Description,
+		}
+		if p.GetType() == ParameterTypeArray {
+			spec.Type = tektonv1beta1.ParamTypeArray
+			if len(p.DefaultArray) > 0 {
+				spec.Default = tektonv1beta1.NewArrayOrString(p.DefaultArray[0], p.DefaultArray[1:]...)
+			}
+		} else if p.Type == ParameterTypeBoolean && p.DefaultStr == "" {
+			spec.Default = tektonv1beta1.NewArrayOrString("false")
 		} else {
-			tektonParamSpecs = append(tektonParamSpecs, tektonv1beta1.ParamSpec{
-				Name:        p.Name,
-				Type:        tektonv1beta1.ParamTypeString,
-				Description: p.Description,
-				Default:     tektonv1beta1.NewArrayOrString(p.DefaultStr),
-			})
+			spec.Default = tektonv1beta1.NewArrayOrString(p.DefaultStr)
 		}
-
+		tektonParamSpecs = append(tektonParamSpecs, spec)
 	}
 	return tektonParamSpecs
 }
@@ -157,6 +399,100 @@ func ConvertToTektonParams(params []ParameterValue) []tektonv1beta1.Param {
 type IntegrationJobManageSpec struct {
 	// Timeout for pending integration job gc
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TTLAfterFinished is a duration for which the completed IntegrationJobs are kept. The IntegrationJobs are deleted
+	// with their PipelineRuns by the garbage collector, after the duration. Default is integrationJobTTL of the
+	// controller config
+	TTLAfterFinished *metav1.Duration `json:"ttlAfterFinished,omitempty"`
+
+	// SuccessfulJobsHistoryLimit is the number of the successful IntegrationJobs to be kept for each branch, regardless
+	// of ttlAfterFinished. Default is no limit
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+
+	// FailedJobsHistoryLimit is the number of the failed (or cancelled) IntegrationJobs to be kept for each branch,
+	// regardless of ttlAfterFinished. Default is no limit
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+
+	// Priority is a default priority of the IntegrationJobs. Pending IntegrationJobs with higher priorities are
+	// scheduled first, and the ones with the same priority are scheduled in the order of their creation. Default is 0
+	Priority int32 `json:"priority,omitempty"`
+
+	// PriorityRules override the priority for the IntegrationJobs of the matching events. If multiple rules match, the
+	// highest priority among them is used
+	PriorityRules []PriorityRule `json:"priorityRules,omitempty"`
+
+	// SchedulingWeight is added to the priorities of the IntegrationJobs when the pending IntegrationJobs are ordered
+	// for scheduling, so that the IntegrationJobs of a more important IntegrationConfig are scheduled first, keeping
+	// their relative priorities. Default is 0
+	SchedulingWeight int32 `json:"schedulingWeight,omitempty"`
+
+	// Backpressure limits the pending IntegrationJobs, not to grow the queue unboundedly with the preSubmit runs
+	Backpressure *Backpressure `json:"backpressure,omitempty"`
+
+	// GangScheduling admits the IntegrationJobs only when all of their jobs fit in the free resources of the cluster at
+	// once, not to leave some of the jobs pending while the others are running
+	GangScheduling bool `json:"gangScheduling,omitempty"`
+}
+
+// BackpressureAction is an action for a new preSubmit run when the pending IntegrationJobs reach the threshold
+type BackpressureAction string
+
+// Backpressure actions
+const (
+	BackpressureActionCoalesce = BackpressureAction("coalesce")
+	BackpressureActionReject   = BackpressureAction("reject")
+)
+
+// Backpressure limits the pending IntegrationJobs of the IntegrationConfig
+type Backpressure struct {
+	// MaxPendingJobs is the number of the pending IntegrationJobs, from which the new preSubmit runs are coalesced or
+	// rejected
+	// +kubebuilder:validation:Minimum=1
+	MaxPendingJobs int `json:"maxPendingJobs"`
+
+	// Action is an action for a new preSubmit run when the pending IntegrationJobs reach the threshold. coalesce
+	// replaces the pending IntegrationJobs of the same pull request with the new one (or rejects it if there is none),
+	// and reject rejects it. Default is coalesce
+	// +kubebuilder:validation:Enum=coalesce;reject
+	Action BackpressureAction `json:"action,omitempty"`
+}
+
+// GetAction returns the action, defaulting to coalesce
+func (b *Backpressure) GetAction() BackpressureAction {
+	if b.Action == "" {
+		return BackpressureActionCoalesce
+	}
+	return b.Action
+}
+
+// PriorityRule gives a priority to the IntegrationJobs of the events matching the expression
+type PriorityRule struct {
+	// Expression is a boolean expression evaluated against the event, e.g., branch =~ "^release-" || "urgent" in labels
+	// The variables are the same as the job's when.expression, except for changedFiles
+	Expression string `json:"expression"`
+
+	// Priority of the IntegrationJobs matching the expression
+	Priority int32 `json:"priority"`
+}
+
+// Validate checks if the expressions of the priority rules are valid
+func (m *IntegrationJobManageSpec) Validate() error {
+	for i, rule := range m.PriorityRules {
+		if err := validateExpression(rule.Expression); err != nil {
+			return fmt.Errorf("priorityRules[%d] has an invalid expression: %s", i, err.Error())
+		}
+		// The priority is decided before the changed files are fetched
+		if e, _ := expression.Parse(rule.Expression); e != nil {
+			for _, ident := range e.Identifiers() {
+				if ident == JobWhenVarChangedFiles {
+					return fmt.Errorf("priorityRules[%d] has an invalid expression: %s is not available", i, ident)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // IntegrationConfigJobs categorizes jobs into three types (pre-submit, post-submit and periodic jobs)
@@ -169,6 +505,57 @@ type IntegrationConfigJobs struct {
 
 	// Periodic are Periodicjobs can be run periodically
 	Periodic Periodics `json:"periodic,omitempty"`
+
+	// SkipDirectives are the directives for skipping preSubmit/postSubmit jobs, in addition to [skip ci] and [ci skip]
+	// Jobs are skipped if the head commit's message or the pull request's title contains any of them (case-insensitive)
+	SkipDirectives []string `json:"skipDirectives,omitempty"`
+
+	// ConfigFile loads the preSubmit and postSubmit jobs from a file in the repository, instead of the ones above
+	ConfigFile *JobsConfigFile `json:"configFile,omitempty"`
+}
+
+// DefaultJobsConfigFilePath is a default path of the jobs config file
+const DefaultJobsConfigFilePath = ".cicd/config.yaml"
+
+// JobsConfigFile is a file in the repository the jobs are loaded from
+// The file is read at the commit being tested, i.e., the pull request's head or the pushed commit
+type JobsConfigFile struct {
+	// Path of the file in the repository. Default is .cicd/config.yaml
+	Path string `json:"path,omitempty"`
+
+	// AllowedServiceAccounts are the ServiceAccounts the jobs in the file can run with, by serviceAccountName.
+	// As the file can be changed by anyone opening a pull request, the jobs cannot set serviceAccountName if it's empty
+	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
+}
+
+// GetPath returns the path of the config file
+func (f *JobsConfigFile) GetPath() string {
+	if f.Path == "" {
+		return DefaultJobsConfigFilePath
+	}
+	return f.Path
+}
+
+// ValidateServiceAccounts checks if the jobs run with the allowed ServiceAccounts only, so that a pull request cannot
+// run its jobs with a privileged ServiceAccount
+// The jobs referring to job templates should be rendered first, as the templates can set serviceAccountName
+func (f *JobsConfigFile) ValidateServiceAccounts(jobs Jobs) error {
+	for _, j := range jobs {
+		if j.ServiceAccountName == "" {
+			continue
+		}
+		isAllowed := false
+		for _, sa := range f.AllowedServiceAccounts {
+			if sa == j.ServiceAccountName {
+				isAllowed = true
+				break
+			}
+		}
+		if !isAllowed {
+			return fmt.Errorf("job %s cannot run with service account %s, which is not in configFile.allowedServiceAccounts", j.Name, j.ServiceAccountName)
+		}
+	}
+	return nil
 }
 
 // IntegrationConfigStatus defines the observed state of IntegrationConfig
@@ -176,6 +563,37 @@ type IntegrationConfigStatus struct {
 	// Conditions of IntegrationConfig
 	Conditions []metav1.Condition `json:"conditions"`
 	Secrets    string             `json:"secrets,omitempty"`
+
+	// Periodics are statuses of the periodic jobs
+	Periodics []PeriodicStatus `json:"periodics,omitempty"`
+
+	// WebhookRepositories are the repositories the webhook is registered to. It's set only if spec.git.repositories
+	// is specified
+	WebhookRepositories []string `json:"webhookRepositories,omitempty"`
+
+	// BranchProtections are the required status checks synchronized to the branches
+	BranchProtections []BranchProtectionStatus `json:"branchProtections,omitempty"`
+}
+
+// BranchProtectionStatus is the required status checks synchronized to a branch
+type BranchProtectionStatus struct {
+	// Repository of the branch
+	Repository string `json:"repository"`
+
+	// Branch is a name of the branch
+	Branch string `json:"branch"`
+
+	// RequiredChecks are the status check contexts required for the branch
+	RequiredChecks []string `json:"requiredChecks,omitempty"`
+}
+
+// PeriodicStatus is a status of a periodic job
+type PeriodicStatus struct {
+	// Name is a name of the periodic job
+	Name string `json:"name"`
+
+	// LastScheduleTime is the last time the job was scheduled
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -238,6 +656,18 @@ func (i *IntegrationConfig) GetToken(c client.Client) (string, error) {
 	return string(token), nil
 }
 
+// ForRepository returns the IntegrationConfig for one of its repositories, i.e., a copy whose Spec.Git.Repository is
+// the repository. It returns the IntegrationConfig itself for the primary repository
+func (i *IntegrationConfig) ForRepository(repo string) *IntegrationConfig {
+	if repo == "" || strings.EqualFold(repo, i.Spec.Git.Repository) {
+		return i
+	}
+	config := i.DeepCopy()
+	config.Spec.Git.Repository = repo
+	config.Spec.Git.Repositories = nil
+	return config
+}
+
 // GetServiceAccountName returns the name of the related ServiceAccount
 func GetServiceAccountName(configName string) string {
 	return fmt.Sprintf("%s-sa", configName)
@@ -263,6 +693,26 @@ func (i *IntegrationConfig) GetDuration() *metav1.Duration {
 	}
 }
 
+// GetHistoryLimit returns the number of the IntegrationJobs in the state to be kept for each branch. Nil means no limit
+func (i *IntegrationConfig) GetHistoryLimit(state IntegrationJobState) *int32 {
+	if state == IntegrationJobStateCompleted {
+		return i.Spec.IJManageSpec.SuccessfulJobsHistoryLimit
+	}
+	return i.Spec.IJManageSpec.FailedJobsHistoryLimit
+}
+
+// GetAggregateCommitStatusContext returns the context of the aggregated commit status, or an empty string if the
+// commit statuses are reported for each job
+func (i *IntegrationConfig) GetAggregateCommitStatusContext() string {
+	if i.Spec.AggregateCommitStatus == nil {
+		return ""
+	}
+	if i.Spec.AggregateCommitStatus.Context == "" {
+		return DefaultAggregateCommitStatusContext
+	}
+	return i.Spec.AggregateCommitStatus.Context
+}
+
 // GetTLSConfig returns tls config from integration configs' tlsConfig
 func (i *IntegrationConfig) GetTLSConfig() *tls.Config {
 	if i.Spec.TLSConfig != nil {
@@ -278,19 +728,26 @@ const (
 	IntegrationConfigAPIRunPre     = "runpre"
 	IntegrationConfigAPIRunPost    = "runpost"
 	IntegrationConfigAPIWebhookURL = "webhookurl"
+	IntegrationConfigAPIMergePool  = "mergepool"
 )
 
 // IntegrationConfigAPIReqRunPreBody is a body struct for IntegrationConfig's api request
 // +kubebuilder:object:generate=false
 type IntegrationConfigAPIReqRunPreBody struct {
-	BaseBranch string `json:"base_branch"`
-	HeadBranch string `json:"head_branch"`
+	BaseBranch string           `json:"base_branch"`
+	HeadBranch string           `json:"head_branch"`
+	HeadSha    string           `json:"head_sha,omitempty"`
+	Params     []ParameterValue `json:"params,omitempty"`
+	// FailedOnly runs only the jobs whose last commit statuses for the head commit are failures or errors
+	FailedOnly bool `json:"failed_only,omitempty"`
 }
 
 // IntegrationConfigAPIReqRunPostBody is a body struct for IntegrationConfig's api request
 // +kubebuilder:object:generate=false
 type IntegrationConfigAPIReqRunPostBody struct {
-	Branch string `json:"branch"`
+	Branch string           `json:"branch"`
+	Sha    string           `json:"sha,omitempty"`
+	Params []ParameterValue `json:"params,omitempty"`
 }
 
 // IntegrationConfigAPIReqWebhookURL is a body struct for IntegrationConfig's api request
@@ -299,3 +756,39 @@ type IntegrationConfigAPIReqWebhookURL struct {
 	URL    string `json:"url"`
 	Secret string `json:"secret"`
 }
+
+// MergePoolStatus is a status of the blocker's merge pool for a repository of an IntegrationConfig
+// +kubebuilder:object:generate=false
+type MergePoolStatus struct {
+	Repository string `json:"repository"`
+	// LastSyncTime is the time the statuses of the PRs are synchronized at
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// BatchJob is a name of the IntegrationJob testing the current batch of the PRs
+	BatchJob string `json:"batchJob,omitempty"`
+	// DryRun is true if the merge config is in the dry-run mode, i.e., the PRs are not merged
+	DryRun       bool                   `json:"dryRun,omitempty"`
+	PullRequests []MergePoolPullRequest `json:"pullRequests"`
+}
+
+// MergePoolPullRequest is a status of an open PR considered by the blocker
+// +kubebuilder:object:generate=false
+type MergePoolPullRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Base   string `json:"base"`
+	Sha    string `json:"sha"`
+	// InMergePool is true if the PR meets the labels, authors and branches conditions of the merge query
+	InMergePool bool `json:"inMergePool"`
+	// Status is the blocker status of the PR, i.e., pending or success
+	Status string `json:"status"`
+	// Description is the blocker description of the PR, listing the conditions the PR fails
+	Description string `json:"description"`
+	// QueuePosition is the position of the PR in the merge order, starting from 1. It's 0 if the PR is not ready to be
+	// merged
+	QueuePosition int `json:"queuePosition,omitempty"`
+	// Batched is true if the PR is being tested in the current batch
+	Batched bool `json:"batched,omitempty"`
+	// DryRunResult is what the blocker would do for the PR, in the dry-run mode
+	DryRunResult string `json:"dryRunResult,omitempty"`
+}
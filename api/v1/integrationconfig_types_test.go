@@ -141,6 +141,25 @@ func TestIntegrationConfig_GetWebhookServerAddress(t *testing.T) {
 	require.Equal(t, "http://test.host.com/webhook/test-ns/test-ic", ic.GetWebhookServerAddress())
 }
 
+func TestIntegrationConfig_ForRepository(t *testing.T) {
+	ic := &IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+		Spec: IntegrationConfigSpec{
+			Git: GitConfig{Repository: "tmax-cloud/cicd-operator", Repositories: []string{"tmax-cloud/a"}},
+		},
+	}
+
+	require.Same(t, ic, ic.ForRepository("tmax-cloud/cicd-operator"))
+	require.Same(t, ic, ic.ForRepository(""))
+
+	repoIC := ic.ForRepository("tmax-cloud/a")
+	require.Equal(t, "tmax-cloud/a", repoIC.Spec.Git.Repository)
+	require.Empty(t, repoIC.Spec.Git.Repositories)
+	require.Equal(t, ic.Name, repoIC.Name)
+	require.Equal(t, "tmax-cloud/cicd-operator", ic.Spec.Git.Repository)
+	require.Equal(t, ic.GetWebhookServerAddress(), repoIC.GetWebhookServerAddress())
+}
+
 func TestGetServiceAccountName(t *testing.T) {
 	require.Equal(t, "test-cfg-sa", GetServiceAccountName("test-cfg"))
 }
@@ -202,6 +221,150 @@ func TestGetDuration(t *testing.T) {
 	}
 }
 
+func TestIntegrationConfig_GetAggregateCommitStatusContext(t *testing.T) {
+	tc := map[string]struct {
+		aggregate *AggregateCommitStatus
+		expected  string
+	}{
+		"notAggregated": {},
+		"default": {
+			aggregate: &AggregateCommitStatus{},
+			expected:  "cicd/summary",
+		},
+		"custom": {
+			aggregate: &AggregateCommitStatus{Context: "ci"},
+			expected:  "ci",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &IntegrationConfig{Spec: IntegrationConfigSpec{AggregateCommitStatus: c.aggregate}}
+			require.Equal(t, c.expected, ic.GetAggregateCommitStatusContext())
+		})
+	}
+}
+
+func TestParameterConfig_Validate(t *testing.T) {
+	tc := map[string]struct {
+		paramConfig *ParameterConfig
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"nil": {},
+		"untyped": {
+			paramConfig: &ParameterConfig{
+				ParamDefine: []ParameterDefine{{Name: "p", DefaultStr: "v"}},
+				ParamValue:  []ParameterValue{{Name: "p", ArrayVal: []string{"a"}}, {Name: "undefined", StringVal: "v"}},
+			},
+		},
+		"typed": {
+			paramConfig: &ParameterConfig{
+				ParamDefine: []ParameterDefine{
+					{Name: "str", Type: ParameterTypeString, Enum: []string{"dev", "prod"}, DefaultStr: "dev"},
+					{Name: "arr", Type: ParameterTypeArray, Enum: []string{"a", "b"}, DefaultArray: []string{"a"}},
+					{Name: "bool", Type: ParameterTypeBoolean},
+				},
+				ParamValue: []ParameterValue{{Name: "str", StringVal: "prod"}, {Name: "arr", ArrayVal: []string{"a", "b"}}, {Name: "bool", StringVal: "true"}},
+			},
+		},
+		"duplicated": {
+			paramConfig:  &ParameterConfig{ParamDefine: []ParameterDefine{{Name: "p"}, {Name: "p"}}},
+			errorOccurs:  true,
+			errorMessage: "parameter p is defined more than once",
+		},
+		"invalidDefaultType": {
+			paramConfig:  &ParameterConfig{ParamDefine: []ParameterDefine{{Name: "p", Type: ParameterTypeString, DefaultArray: []string{"a"}}}},
+			errorOccurs:  true,
+			errorMessage: "parameter p is a string, but defaultArray is set",
+		},
+		"invalidDefaultEnum": {
+			paramConfig:  &ParameterConfig{ParamDefine: []ParameterDefine{{Name: "p", Type: ParameterTypeString, Enum: []string{"dev", "prod"}, DefaultStr: "test"}}},
+			errorOccurs:  true,
+			errorMessage: `parameter p should be one of [dev, prod], but got "test"`,
+		},
+		"invalidBoolean": {
+			paramConfig: &ParameterConfig{
+				ParamDefine: []ParameterDefine{{Name: "p", Type: ParameterTypeBoolean}},
+				ParamValue:  []ParameterValue{{Name: "p", StringVal: "yes"}},
+			},
+			errorOccurs:  true,
+			errorMessage: `parameter p should be a boolean (true or false), but got "yes"`,
+		},
+		"invalidArray": {
+			paramConfig: &ParameterConfig{
+				ParamDefine: []ParameterDefine{{Name: "p", Type: ParameterTypeArray}},
+				ParamValue:  []ParameterValue{{Name: "p", StringVal: "a"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "parameter p should be an array",
+		},
+		"invalidArrayEnum": {
+			paramConfig: &ParameterConfig{
+				ParamDefine: []ParameterDefine{{Name: "p", Type: ParameterTypeArray, Enum: []string{"a"}}},
+				ParamValue:  []ParameterValue{{Name: "p", ArrayVal: []string{"a", "c"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: `parameter p should be one of [a], but got "c"`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.paramConfig.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIntegrationJobManageSpec_Validate(t *testing.T) {
+	tc := map[string]struct {
+		rules []PriorityRule
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"noRules": {},
+		"valid": {
+			rules: []PriorityRule{{Expression: `branch == "master" || branch =~ "^release-"`, Priority: 100}, {Expression: `"urgent" in labels`, Priority: 200}},
+		},
+		"invalidSyntax": {
+			rules:        []PriorityRule{{Expression: `branch ==`, Priority: 100}},
+			errorOccurs:  true,
+			errorMessage: "priorityRules[0] has an invalid expression: unexpected end of expression",
+		},
+		"undefinedVariable": {
+			rules:        []PriorityRule{{Expression: `branch == "master"`}, {Expression: `repo == "test"`, Priority: 100}},
+			errorOccurs:  true,
+			errorMessage: "priorityRules[1] has an invalid expression: undefined variable repo",
+		},
+		"changedFiles": {
+			rules:        []PriorityRule{{Expression: `changedFiles < 10`, Priority: 100}},
+			errorOccurs:  true,
+			errorMessage: "priorityRules[0] has an invalid expression: changedFiles is not available",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			spec := &IntegrationJobManageSpec{PriorityRules: c.rules}
+			err := spec.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestConvertToTektonParamSpecs(t *testing.T) {
 	tc := map[string]struct {
 		params            []ParameterDefine
@@ -235,6 +398,20 @@ func TestConvertToTektonParamSpecs(t *testing.T) {
 				},
 			},
 		},
+		"typed": {
+			params: []ParameterDefine{
+				{Name: "array-no-default", Type: ParameterTypeArray},
+				{Name: "bool", Type: ParameterTypeBoolean, DefaultStr: "true"},
+				{Name: "bool-no-default", Type: ParameterTypeBoolean},
+				{Name: "string", Type: ParameterTypeString, Enum: []string{"a", "b"}, DefaultStr: "a"},
+			},
+			expectedParamSpec: []tektonv1beta1.ParamSpec{
+				{Name: "array-no-default", Type: "array"},
+				{Name: "bool", Type: "string", Default: tektonv1beta1.NewArrayOrString("true")},
+				{Name: "bool-no-default", Type: "string", Default: tektonv1beta1.NewArrayOrString("false")},
+				{Name: "string", Type: "string", Default: tektonv1beta1.NewArrayOrString("a")},
+			},
+		},
 		"nil": {
 			params:            nil,
 			expectedParamSpec: nil,
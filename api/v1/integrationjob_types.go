@@ -18,13 +18,45 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// IntegrationJobKind is kind string
+const (
+	IntegrationJobKind = "integrationjobs"
+)
+
+// IntegrationJob's API kinds
+const (
+	IntegrationJobAPILog    = "log"
+	IntegrationJobAPIRetry  = "retry"
+	IntegrationJobAPIPause  = "pause"
+	IntegrationJobAPIResume = "resume"
+)
+
+// Query parameters of IntegrationJob's log API
+const (
+	IntegrationJobAPILogParamJob       = "job"
+	IntegrationJobAPILogParamStep      = "step"
+	IntegrationJobAPILogParamFollow    = "follow"
+	IntegrationJobAPILogParamTailLines = "tailLines"
+)
+
+// IntegrationJobAPIReqRetryBody is a body struct for IntegrationJob's retry api request
+// +kubebuilder:object:generate=false
+type IntegrationJobAPIReqRetryBody struct {
+	// Job is a name of the failed job to be retried
+	Job string `json:"job"`
+}
+
 // IntegrationJobState is a state of the IntegrationJob
 type IntegrationJobState string
 
@@ -34,6 +66,23 @@ const (
 	IntegrationJobStateRunning   = IntegrationJobState("Running")
 	IntegrationJobStateCompleted = IntegrationJobState("Completed")
 	IntegrationJobStateFailed    = IntegrationJobState("Failed")
+	IntegrationJobStateCancelled = IntegrationJobState("Cancelled")
+)
+
+// IntegrationJobReason is a reason of the IntegrationJob's state
+type IntegrationJobReason string
+
+// IntegrationJob's reasons
+const (
+	IntegrationJobReasonQuotaExceeded      = IntegrationJobReason("QuotaExceeded")
+	IntegrationJobReasonConcurrencyLimited = IntegrationJobReason("ConcurrencyLimited")
+	IntegrationJobReasonSuperseded         = IntegrationJobReason("Superseded")
+	IntegrationJobReasonStuck              = IntegrationJobReason("Stuck")
+	IntegrationJobReasonPaused             = IntegrationJobReason("Paused")
+	IntegrationJobReasonWaitingForCapacity = IntegrationJobReason("WaitingForCapacity")
+	IntegrationJobReasonUnschedulable      = IntegrationJobReason("Unschedulable")
+	IntegrationJobReasonWaitingForWindow   = IntegrationJobReason("WaitingForWindow")
+	IntegrationJobReasonTimedOut           = IntegrationJobReason("TimedOut")
 )
 
 // IntegrationJobSpec defines the desired state of IntegrationJob
@@ -56,11 +105,44 @@ type IntegrationJobSpec struct {
 	// PodTemplate for the TaskRun pods. Same as tekton's pod template
 	PodTemplate *pod.Template `json:"podTemplate,omitempty"`
 
+	// Env is a list of environment variables set for every job
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// VolumeMounts are mounted to every job
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// SecurityContext is a security context of every step of the jobs
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Checkout configures the git checkout step of every job
+	Checkout *JobCheckout `json:"checkout,omitempty"`
+
+	// Parallelism limits the number of the jobs running at the same time. There is no limit if it is 0
+	Parallelism int32 `json:"parallelism,omitempty"`
+
+	// RemoteCluster is a cluster the PipelineRun is run in, instead of the operator's cluster
+	RemoteCluster *RemoteCluster `json:"remoteCluster,omitempty"`
+
+	// GangScheduling admits the IntegrationJob only when all of its jobs fit in the free resources of the cluster at once
+	GangScheduling bool `json:"gangScheduling,omitempty"`
+
 	// Timeout for pending status garbage collection
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 
+	// TTLAfterFinished is a duration for which the IntegrationJob is kept after it is completed
+	TTLAfterFinished *metav1.Duration `json:"ttlAfterFinished,omitempty"`
+
 	// ParamConfig specifies parameter
 	ParamConfig *ParameterConfig `json:"paramConfig,omitempty"`
+
+	// Priority of the IntegrationJob. Pending IntegrationJobs with higher priorities are scheduled first
+	Priority int32 `json:"priority,omitempty"`
+
+	// Cancelled cancels the IntegrationJob. Its PipelineRun is cancelled and its state is set as Cancelled
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// Paused pauses the pending IntegrationJob. Its PipelineRun is not created until it is resumed
+	Paused bool `json:"paused,omitempty"`
 }
 
 // IntegrationJobConfigRef refers to the IntegrationConfig
@@ -121,6 +203,9 @@ type IntegrationJobStatus struct {
 	// State is a current state of the IntegrationJob
 	State IntegrationJobState `json:"state"`
 
+	// Reason is a reason of the state, e.g., QuotaExceeded if it is pending or failed due to the resource quota
+	Reason IntegrationJobReason `json:"reason,omitempty"`
+
 	// Message is a message for the IntegrationJob (normally an error string)
 	Message string `json:"message,omitempty"`
 
@@ -132,6 +217,24 @@ type IntegrationJobStatus struct {
 
 	// Jobs are status list for each Job in the IntegrationJob
 	Jobs []JobStatus `json:"jobs,omitempty"`
+
+	// Queue is the last position of the IntegrationJob in the scheduling queue, while it's pending
+	Queue *IntegrationJobQueueStatus `json:"queue,omitempty"`
+}
+
+// IntegrationJobQueueStatus is a position of the pending IntegrationJob in the scheduling queue
+// The pending IntegrationJobs are ordered by their effective priorities (higher first), and then by their creation
+// timestamps (older first)
+type IntegrationJobQueueStatus struct {
+	// Position of the IntegrationJob in the queue, starting from 1
+	Position int32 `json:"position"`
+
+	// Length is the number of the pending IntegrationJobs in the queue
+	Length int32 `json:"length"`
+
+	// Priority is the effective priority of the IntegrationJob, i.e., spec.priority plus the IntegrationConfig's
+	// ijManageSpec.schedulingWeight
+	Priority int32 `json:"priority"`
 }
 
 // +kubebuilder:object:root=true
@@ -176,6 +279,54 @@ func (i *IntegrationJob) GetReportServerAddress(jobName string) string {
 	return fmt.Sprintf("http://%s/report/%s/%s/%s", configs.CurrentExternalHostName, i.Namespace, i.Name, jobName)
 }
 
+// GetGroupKey returns a key of the IntegrationJob's group, i.e., its repository and branch or pull request
+// IntegrationJobs not for pull requests are grouped by their branches, even for the pullRequest group
+func (i *IntegrationJob) GetGroupKey(group ConcurrencyGroup) string {
+	refs := i.Spec.Refs
+	if len(refs.Pulls) == 0 {
+		// Tags are grouped by themselves
+		ref := refs.Base.Ref.GetBranch()
+		if ref == "" {
+			ref = refs.Base.Ref.String()
+		}
+		return fmt.Sprintf("%s@%s", refs.Repository, ref)
+	}
+	if group == ConcurrencyGroupPullRequest {
+		var ids []string
+		for _, p := range refs.Pulls {
+			ids = append(ids, strconv.Itoa(p.ID))
+		}
+		return fmt.Sprintf("%s#%s", refs.Repository, strings.Join(ids, ","))
+	}
+	return fmt.Sprintf("%s@%s", refs.Repository, refs.Pulls[0].Ref.GetBranch())
+}
+
+// GetHeadSha returns the SHA of the head commit, i.e., of the pull request or of the pushed branch
+func (i *IntegrationJob) GetHeadSha() string {
+	if len(i.Spec.Refs.Pulls) > 0 {
+		return i.Spec.Refs.Pulls[0].Sha
+	}
+	return i.Spec.Refs.Base.Sha
+}
+
+// GetTTLAfterFinished returns the duration for which the IntegrationJob is kept after it is completed, defaulting to
+// integrationJobTTL of the controller config
+func (i *IntegrationJob) GetTTLAfterFinished() time.Duration {
+	if i.Spec.TTLAfterFinished != nil {
+		return i.Spec.TTLAfterFinished.Duration
+	}
+	return time.Duration(configs.IntegrationJobTTL) * time.Hour
+}
+
+// GetQueueSequence returns the sequence at which the IntegrationJob entered the scheduling queue. The creation time is
+// used if the sequence is not recorded
+func (i *IntegrationJob) GetQueueSequence() int64 {
+	if seq, err := strconv.ParseInt(i.Annotations[JobAnnotationQueueSequence], 10, 64); err == nil {
+		return seq
+	}
+	return i.CreationTimestamp.UnixNano()
+}
+
 // IsCompleted returns whether or not a job have been completed
 func (i *IntegrationJob) IsCompleted() bool {
 	return i.Status.CompletionTime != nil
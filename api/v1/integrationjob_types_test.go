@@ -57,3 +57,48 @@ func TestIntegrationJob_GetReportServerAddress(t *testing.T) {
 	}
 	require.Equal(t, "http://test.host.com/report/test-ns/test-ij/test-job", ij.GetReportServerAddress("test-job"))
 }
+
+func TestIntegrationJob_GetGroupKey(t *testing.T) {
+	tc := map[string]struct {
+		refs  IntegrationJobRefs
+		group ConcurrencyGroup
+
+		expectedKey string
+	}{
+		"push": {
+			refs:        IntegrationJobRefs{Repository: "tmax-cloud/cicd-operator", Base: IntegrationJobRefsBase{Ref: "refs/heads/master"}},
+			group:       ConcurrencyGroupPullRequest,
+			expectedKey: "tmax-cloud/cicd-operator@master",
+		},
+		"tag": {
+			refs:        IntegrationJobRefs{Repository: "tmax-cloud/cicd-operator", Base: IntegrationJobRefsBase{Ref: "refs/tags/v0.1.0"}},
+			group:       ConcurrencyGroupBranch,
+			expectedKey: "tmax-cloud/cicd-operator@refs/tags/v0.1.0",
+		},
+		"pullRequestBranch": {
+			refs: IntegrationJobRefs{
+				Repository: "tmax-cloud/cicd-operator",
+				Base:       IntegrationJobRefsBase{Ref: "master"},
+				Pulls:      []IntegrationJobRefsPull{{ID: 3, Ref: "feat/a"}},
+			},
+			group:       ConcurrencyGroupBranch,
+			expectedKey: "tmax-cloud/cicd-operator@feat/a",
+		},
+		"pullRequest": {
+			refs: IntegrationJobRefs{
+				Repository: "tmax-cloud/cicd-operator",
+				Base:       IntegrationJobRefsBase{Ref: "master"},
+				Pulls:      []IntegrationJobRefsPull{{ID: 3, Ref: "feat/a"}, {ID: 5, Ref: "feat/b"}},
+			},
+			group:       ConcurrencyGroupPullRequest,
+			expectedKey: "tmax-cloud/cicd-operator#3,5",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := &IntegrationJob{Spec: IntegrationJobSpec{Refs: c.refs}}
+			require.Equal(t, c.expectedKey, ij.GetGroupKey(c.group))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JobTemplateKind is a kind of the job template
+type JobTemplateKind string
+
+// Job template kinds
+const (
+	JobTemplateKindNamespaced = JobTemplateKind("IntegrationJobTemplate")
+	JobTemplateKindCluster    = JobTemplateKind("ClusterIntegrationJobTemplate")
+)
+
+// JobTemplateRef refers to an IntegrationJobTemplate or a ClusterIntegrationJobTemplate
+type JobTemplateRef struct {
+	// Name of the template
+	Name string `json:"name"`
+
+	// Kind of the template. Default is IntegrationJobTemplate, which is in the same namespace as the IntegrationConfig
+	// +kubebuilder:validation:Enum=IntegrationJobTemplate;ClusterIntegrationJobTemplate
+	Kind JobTemplateKind `json:"kind,omitempty"`
+
+	// Params are values for the template's parameters
+	Params []ParameterValue `json:"params,omitempty"`
+}
+
+// GetKind returns the kind of the template, defaulting to IntegrationJobTemplate
+func (r *JobTemplateRef) GetKind() JobTemplateKind {
+	if r.Kind == "" {
+		return JobTemplateKindNamespaced
+	}
+	return r.Kind
+}
+
+// IntegrationJobTemplateSpec defines a reusable job
+type IntegrationJobTemplateSpec struct {
+	// Params are parameters of the template. $(template.<name>) in the job is replaced with the parameter's value
+	// Only string and boolean parameters are supported
+	Params []ParameterDefine `json:"params,omitempty"`
+
+	// Job is a job definition. Its name, when, after, matrix, notification and approvalRequired are ignored, as they are
+	// specified by the referring job
+	Job Job `json:"job"`
+}
+
+// +kubebuilder:object:root=true
+
+// IntegrationJobTemplate is the Schema for the integrationjobtemplates API
+// +kubebuilder:resource:shortName="ijt"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Creation time"
+type IntegrationJobTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IntegrationJobTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// IntegrationJobTemplateList contains a list of IntegrationJobTemplate
+type IntegrationJobTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IntegrationJobTemplate `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterIntegrationJobTemplate is the Schema for the clusterintegrationjobtemplates API
+// +kubebuilder:resource:scope=Cluster,shortName="cijt"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Creation time"
+type ClusterIntegrationJobTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IntegrationJobTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterIntegrationJobTemplateList contains a list of ClusterIntegrationJobTemplate
+type ClusterIntegrationJobTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterIntegrationJobTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IntegrationJobTemplate{}, &IntegrationJobTemplateList{})
+	SchemeBuilder.Register(&ClusterIntegrationJobTemplate{}, &ClusterIntegrationJobTemplateList{})
+}
+
+// Validate checks if the template's parameters are valid
+func (t *IntegrationJobTemplateSpec) Validate() error {
+	names := map[string]struct{}{}
+	for _, p := range t.Params {
+		if _, exist := names[p.Name]; exist {
+			return fmt.Errorf("parameter %s is duplicated", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.GetType() == ParameterTypeArray {
+			return fmt.Errorf("parameter %s is an array, which is not supported for templates", p.Name)
+		}
+		if err := p.ValidateDefault(); err != nil {
+			return err
+		}
+	}
+	if t.Job.Template != nil {
+		return fmt.Errorf("template cannot refer to another template")
+	}
+	return nil
+}
+
+// Render generates a job from the template, for the job referring to the template
+// Image, script, checkout options, security contexts, affinity, timeout, retries, service account, workspaces,
+// artifacts, test reports and coverage of the referring job take precedence over the template's, its env, envFrom and
+// tolerations are appended to the template's, and its node selector is merged to the template's
+func (t *IntegrationJobTemplateSpec) Render(job *Job) (*Job, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	defines := map[string]struct{}{}
+	for _, p := range t.Params {
+		defines[p.Name] = struct{}{}
+	}
+	values := map[string]ParameterValue{}
+	if job.Template != nil {
+		for _, v := range job.Template.Params {
+			if _, exist := defines[v.Name]; !exist {
+				return nil, fmt.Errorf("parameter %s is not defined in the template", v.Name)
+			}
+			values[v.Name] = v
+		}
+	}
+
+	var replacements []string
+	for _, p := range t.Params {
+		v, exist := values[p.Name]
+		if !exist {
+			v = ParameterValue{Name: p.Name, StringVal: p.DefaultStr}
+			if p.Type == ParameterTypeBoolean && p.DefaultStr == "" {
+				v.StringVal = "false"
+			} else if p.DefaultStr == "" {
+				return nil, fmt.Errorf("parameter %s is required", p.Name)
+			}
+		}
+		if err := p.ValidateValue(v); err != nil {
+			return nil, err
+		}
+		replacements = append(replacements, fmt.Sprintf("$(template.%s)", p.Name), v.StringVal)
+	}
+
+	rendered := t.Job.DeepCopy()
+	replaceJobVariables(rendered, strings.NewReplacer(replacements...))
+
+	rendered.Name = job.Name
+	rendered.When = job.When.DeepCopy()
+	rendered.After = append([]string(nil), job.After...)
+	rendered.Matrix = nil
+	rendered.TektonWhen = job.TektonWhen.DeepCopy()
+	rendered.Notification = job.Notification.DeepCopy()
+	rendered.ApprovalRequired = job.ApprovalRequired
+	if job.ApprovalRequired {
+		rendered.Approval = job.Approval.DeepCopy()
+	}
+
+	if job.Image != "" {
+		rendered.Image = job.Image
+	}
+	if job.Script != "" {
+		rendered.Script = job.Script
+	}
+	if job.Checkout != nil {
+		rendered.Checkout = MergeCheckout(rendered.Checkout, job.Checkout)
+	}
+	rendered.Env = append(rendered.Env, job.Env...)
+	rendered.EnvFrom = append(rendered.EnvFrom, job.EnvFrom...)
+	if job.SecurityContext != nil {
+		rendered.SecurityContext = job.SecurityContext.DeepCopy()
+	}
+	if job.PodSecurityContext != nil {
+		rendered.PodSecurityContext = job.PodSecurityContext.DeepCopy()
+	}
+	if len(job.NodeSelector) > 0 && rendered.NodeSelector == nil {
+		rendered.NodeSelector = map[string]string{}
+	}
+	for k, v := range job.NodeSelector {
+		rendered.NodeSelector[k] = v
+	}
+	rendered.Tolerations = append(rendered.Tolerations, job.Tolerations...)
+	if job.Affinity != nil {
+		rendered.Affinity = job.Affinity.DeepCopy()
+	}
+	if job.Timeout != nil {
+		rendered.Timeout = job.Timeout.DeepCopy()
+	}
+	if job.Retries != 0 {
+		rendered.Retries = job.Retries
+	}
+	if job.ServiceAccountName != "" {
+		rendered.ServiceAccountName = job.ServiceAccountName
+	}
+	if job.Artifacts != nil {
+		rendered.Artifacts = job.Artifacts.DeepCopy()
+	}
+	if job.TestReports != nil {
+		rendered.TestReports = job.TestReports.DeepCopy()
+	}
+	if job.Coverage != nil {
+		rendered.Coverage = job.Coverage.DeepCopy()
+	}
+	if job.Lint != nil {
+		rendered.Lint = job.Lint.DeepCopy()
+	}
+	if len(job.Workspaces) > 0 {
+		rendered.Workspaces = append([]JobWorkspace(nil), job.Workspaces...)
+	}
+
+	return rendered, nil
+}
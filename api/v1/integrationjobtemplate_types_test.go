@@ -0,0 +1,192 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIntegrationJobTemplateSpec_Render(t *testing.T) {
+	template := IntegrationJobTemplateSpec{
+		Params: []ParameterDefine{
+			{Name: "GO_VERSION", DefaultStr: "1.17"},
+			{Name: "TARGET", Type: ParameterTypeString},
+			{Name: "RACE", Type: ParameterTypeBoolean},
+		},
+		Job: Job{
+			Container: corev1.Container{
+				Name:  "ignored",
+				Image: "golang:$(template.GO_VERSION)",
+				Env:   []corev1.EnvVar{{Name: "RACE", Value: "$(template.RACE)"}},
+			},
+			Script:       "go test $(template.TARGET)",
+			After:        []string{"ignored"},
+			Retries:      1,
+			Timeout:      &metav1.Duration{Duration: time.Hour},
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+		},
+	}
+
+	tc := map[string]struct {
+		job Job
+
+		errorOccurs  bool
+		errorMessage string
+		expected     *Job
+	}{
+		"default": {
+			job: Job{
+				Container: corev1.Container{Name: "test"},
+				After:     []string{"lint"},
+				Template:  &JobTemplateRef{Name: "go-test", Params: []ParameterValue{{Name: "TARGET", StringVal: "./..."}}},
+			},
+			expected: &Job{
+				Container: corev1.Container{
+					Name:  "test",
+					Image: "golang:1.17",
+					Env:   []corev1.EnvVar{{Name: "RACE", Value: "false"}},
+				},
+				Script:       "go test ./...",
+				After:        []string{"lint"},
+				Retries:      1,
+				Timeout:      &metav1.Duration{Duration: time.Hour},
+				NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			},
+		},
+		"override": {
+			job: Job{
+				Container: corev1.Container{
+					Name:            "test",
+					Image:           "golang:1.16-alpine",
+					Env:             []corev1.EnvVar{{Name: "CGO_ENABLED", Value: "0"}},
+					EnvFrom:         []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "test-db"}}}},
+					SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+				},
+				PodSecurityContext: &corev1.PodSecurityContext{SupplementalGroups: []int64{1000}},
+				Timeout:            &metav1.Duration{Duration: time.Minute},
+				ServiceAccountName: "tester",
+				NodeSelector:       map[string]string{"kubernetes.io/arch": "arm64"},
+				Tolerations:        []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				Template: &JobTemplateRef{Name: "go-test", Params: []ParameterValue{
+					{Name: "TARGET", StringVal: "./pkg/..."},
+					{Name: "RACE", StringVal: "true"},
+				}},
+			},
+			expected: &Job{
+				Container: corev1.Container{
+					Name:            "test",
+					Image:           "golang:1.16-alpine",
+					Env:             []corev1.EnvVar{{Name: "RACE", Value: "true"}, {Name: "CGO_ENABLED", Value: "0"}},
+					EnvFrom:         []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "test-db"}}}},
+					SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+				},
+				PodSecurityContext: &corev1.PodSecurityContext{SupplementalGroups: []int64{1000}},
+				Script:             "go test ./pkg/...",
+				Retries:            1,
+				Timeout:            &metav1.Duration{Duration: time.Minute},
+				ServiceAccountName: "tester",
+				NodeSelector:       map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "arm64"},
+				Tolerations:        []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+			},
+		},
+		"missingParam": {
+			job: Job{
+				Container: corev1.Container{Name: "test"},
+				Template:  &JobTemplateRef{Name: "go-test"},
+			},
+			errorOccurs:  true,
+			errorMessage: "parameter TARGET is required",
+		},
+		"undefinedParam": {
+			job: Job{
+				Container: corev1.Container{Name: "test"},
+				Template:  &JobTemplateRef{Name: "go-test", Params: []ParameterValue{{Name: "TARGET", StringVal: "./..."}, {Name: "TAGS", StringVal: "e2e"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "parameter TAGS is not defined in the template",
+		},
+		"invalidParam": {
+			job: Job{
+				Container: corev1.Container{Name: "test"},
+				Template:  &JobTemplateRef{Name: "go-test", Params: []ParameterValue{{Name: "TARGET", StringVal: "./..."}, {Name: "RACE", StringVal: "yes"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: `parameter RACE should be a boolean (true or false), but got "yes"`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			rendered, err := template.Render(&c.job)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expected, rendered)
+			}
+		})
+	}
+
+	// Template should not be modified
+	require.Equal(t, "golang:$(template.GO_VERSION)", template.Job.Image)
+}
+
+func TestIntegrationJobTemplateSpec_Validate(t *testing.T) {
+	tc := map[string]struct {
+		template IntegrationJobTemplateSpec
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"normal": {
+			template: IntegrationJobTemplateSpec{Params: []ParameterDefine{{Name: "A"}, {Name: "B", Type: ParameterTypeBoolean, DefaultStr: "true"}}},
+		},
+		"duplicated": {
+			template:     IntegrationJobTemplateSpec{Params: []ParameterDefine{{Name: "A"}, {Name: "A"}}},
+			errorOccurs:  true,
+			errorMessage: "parameter A is duplicated",
+		},
+		"array": {
+			template:     IntegrationJobTemplateSpec{Params: []ParameterDefine{{Name: "A", DefaultArray: []string{"a"}}}},
+			errorOccurs:  true,
+			errorMessage: "parameter A is an array, which is not supported for templates",
+		},
+		"nested": {
+			template:     IntegrationJobTemplateSpec{Job: Job{Template: &JobTemplateRef{Name: "other"}}},
+			errorOccurs:  true,
+			errorMessage: "template cannot refer to another template",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.template.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
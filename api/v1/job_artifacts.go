@@ -0,0 +1,103 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArtifactStorageType is a type of the object storage the artifacts are uploaded to
+type ArtifactStorageType string
+
+// Artifact storage types
+const (
+	ArtifactStorageTypeS3  = ArtifactStorageType("s3")
+	ArtifactStorageTypeGCS = ArtifactStorageType("gcs")
+)
+
+// Keys of the artifact storage's credentials secret
+const (
+	ArtifactCredentialsAccessKeyID       = "accessKeyId"
+	ArtifactCredentialsSecretAccessKey   = "secretAccessKey"
+	ArtifactCredentialsServiceAccountKey = "serviceAccountKey"
+)
+
+// JobArtifacts configures the artifacts of the job, which are uploaded to an object storage after the job's script
+// succeeds
+type JobArtifacts struct {
+	// Paths of the artifacts, relative to the working directory. Glob patterns of the shell can be used, and the
+	// directories are uploaded recursively
+	// +kubebuilder:validation:MinItems=1
+	Paths []string `json:"paths"`
+
+	// Storage is an object storage the artifacts are uploaded to
+	Storage ArtifactStorage `json:"storage"`
+
+	// Comment posts the URLs of the uploaded artifacts to the pull request as a comment
+	Comment bool `json:"comment,omitempty"`
+}
+
+// ArtifactStorage is an object storage, i.e., S3 (or S3-compatible storages like MinIO) or GCS
+type ArtifactStorage struct {
+	// Type of the storage
+	// +kubebuilder:validation:Enum=s3;gcs
+	Type ArtifactStorageType `json:"type"`
+
+	// Bucket the artifacts are uploaded to
+	Bucket string `json:"bucket"`
+
+	// Prefix of the artifacts' object keys. Default is <namespace>/<IntegrationJob name>/<job name>
+	Prefix string `json:"prefix,omitempty"`
+
+	// Endpoint of the S3-compatible storage, e.g., http://minio.minio-system:9000. Default is AWS S3
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region of the S3 bucket. Default is us-east-1
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecret is a name of the secret containing the credentials of the storage
+	// It should have accessKeyId and secretAccessKey for s3, and serviceAccountKey (JSON key file) for gcs
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// GetPrefix returns the prefix of the artifacts' object keys, defaulting to <namespace>/<IntegrationJob name>/<job name>
+func (s *ArtifactStorage) GetPrefix(ij *IntegrationJob, jobName string) string {
+	if s.Prefix != "" {
+		return strings.Trim(s.Prefix, "/")
+	}
+	return fmt.Sprintf("%s/%s/%s", ij.Namespace, ij.Name, jobName)
+}
+
+// GetRegion returns the region of the S3 bucket, defaulting to us-east-1
+func (s *ArtifactStorage) GetRegion() string {
+	if s.Region == "" {
+		return "us-east-1"
+	}
+	return s.Region
+}
+
+// GetURL returns the URL of the object in the bucket
+func (s *ArtifactStorage) GetURL(key string) string {
+	switch {
+	case s.Type == ArtifactStorageTypeGCS:
+		return fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.Bucket, key)
+	case s.Endpoint != "":
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.Endpoint, "/"), s.Bucket, key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.GetRegion(), key)
+}
@@ -0,0 +1,65 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestArtifactStorage_GetPrefix(t *testing.T) {
+	ij := &IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}
+
+	storage := &ArtifactStorage{}
+	require.Equal(t, "default/test-ij/build", storage.GetPrefix(ij, "build"))
+
+	storage.Prefix = "/builds/latest/"
+	require.Equal(t, "builds/latest", storage.GetPrefix(ij, "build"))
+}
+
+func TestArtifactStorage_GetURL(t *testing.T) {
+	tc := map[string]struct {
+		storage ArtifactStorage
+
+		expectedURL string
+	}{
+		"s3": {
+			storage:     ArtifactStorage{Type: ArtifactStorageTypeS3, Bucket: "artifacts"},
+			expectedURL: "https://artifacts.s3.us-east-1.amazonaws.com/default/test-ij/build",
+		},
+		"s3Region": {
+			storage:     ArtifactStorage{Type: ArtifactStorageTypeS3, Bucket: "artifacts", Region: "ap-northeast-2"},
+			expectedURL: "https://artifacts.s3.ap-northeast-2.amazonaws.com/default/test-ij/build",
+		},
+		"minio": {
+			storage:     ArtifactStorage{Type: ArtifactStorageTypeS3, Bucket: "artifacts", Endpoint: "http://minio.minio-system:9000/"},
+			expectedURL: "http://minio.minio-system:9000/artifacts/default/test-ij/build",
+		},
+		"gcs": {
+			storage:     ArtifactStorage{Type: ArtifactStorageTypeGCS, Bucket: "artifacts"},
+			expectedURL: "https://storage.googleapis.com/artifacts/default/test-ij/build",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedURL, c.storage.GetURL("default/test-ij/build"))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+// JobCache configures the result cache of the job
+// The job is skipped as a cached success, if the same job already succeeded for the same contents of the paths
+type JobCache struct {
+	// Paths are glob patterns of the files the job depends on, e.g., **/*.go, go.mod
+	// +kubebuilder:validation:MinItems=1
+	Paths []string `json:"paths"`
+}
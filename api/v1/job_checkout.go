@@ -0,0 +1,81 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+// JobCheckout configures the git checkout step of the jobs
+type JobCheckout struct {
+	// Submodules initializes the submodules recursively, using the same credentials as the repository. Relative and
+	// ssh urls of the submodules on the same git server are fetched over the repository's url. Default is false
+	Submodules *bool `json:"submodules,omitempty"`
+
+	// FetchDepth limits the number of commits fetched from the tip of each ref, i.e., makes a shallow clone. Zero means
+	// the full history. Pull requests whose merge base is beyond the depth are merged after fetching the full history
+	// +kubebuilder:validation:Minimum=0
+	FetchDepth *int `json:"fetchDepth,omitempty"`
+
+	// LFS pulls the git LFS objects of the checked out commit, using the same credentials as the repository. The git
+	// image should have git-lfs installed. Default is false
+	LFS *bool `json:"lfs,omitempty"`
+}
+
+// MergeCheckout merges the job's checkout options to the IntegrationConfig-level ones. The options set in the job take
+// precedence
+func MergeCheckout(base, job *JobCheckout) *JobCheckout {
+	merged := &JobCheckout{}
+	for _, c := range []*JobCheckout{base, job} {
+		if c == nil {
+			continue
+		}
+		if c.Submodules != nil {
+			submodules := *c.Submodules
+			merged.Submodules = &submodules
+		}
+		if c.FetchDepth != nil {
+			depth := *c.FetchDepth
+			merged.FetchDepth = &depth
+		}
+		if c.LFS != nil {
+			lfs := *c.LFS
+			merged.LFS = &lfs
+		}
+	}
+	return merged
+}
+
+// GetSubmodules returns whether to initialize the submodules, defaulting to false
+func (c *JobCheckout) GetSubmodules() bool {
+	if c == nil || c.Submodules == nil {
+		return false
+	}
+	return *c.Submodules
+}
+
+// GetFetchDepth returns the number of commits to be fetched, defaulting to zero (i.e., the full history)
+func (c *JobCheckout) GetFetchDepth() int {
+	if c == nil || c.FetchDepth == nil {
+		return 0
+	}
+	return *c.FetchDepth
+}
+
+// GetLFS returns whether to pull the LFS objects, defaulting to false
+func (c *JobCheckout) GetLFS() bool {
+	if c == nil || c.LFS == nil {
+		return false
+	}
+	return *c.LFS
+}
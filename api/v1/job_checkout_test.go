@@ -0,0 +1,63 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeCheckout(t *testing.T) {
+	disabled := false
+	enabled := true
+	depth := 10
+
+	tc := map[string]struct {
+		base *JobCheckout
+		job  *JobCheckout
+
+		expectedSubmodules bool
+		expectedFetchDepth int
+		expectedLFS        bool
+	}{
+		"nil": {
+			expectedSubmodules: false,
+		},
+		"base": {
+			base:               &JobCheckout{Submodules: &disabled, FetchDepth: &depth},
+			job:                &JobCheckout{},
+			expectedSubmodules: false,
+			expectedFetchDepth: 10,
+		},
+		"job": {
+			base:               &JobCheckout{Submodules: &disabled},
+			job:                &JobCheckout{Submodules: &enabled, LFS: &enabled},
+			expectedSubmodules: true,
+			expectedLFS:        true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			merged := MergeCheckout(c.base, c.job)
+			require.Equal(t, c.expectedSubmodules, merged.GetSubmodules())
+			require.Equal(t, c.expectedFetchDepth, merged.GetFetchDepth())
+			require.Equal(t, c.expectedLFS, merged.GetLFS())
+		})
+	}
+}
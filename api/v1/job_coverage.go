@@ -0,0 +1,106 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CoverageFormat is a format of the coverage report
+type CoverageFormat string
+
+// Coverage formats
+const (
+	CoverageFormatCobertura = CoverageFormat("cobertura")
+	CoverageFormatLcov      = CoverageFormat("lcov")
+)
+
+// JobCoverage configures the coverage report of the job, which is parsed after the job's script, even if it fails
+type JobCoverage struct {
+	// Path of the coverage report, relative to the working directory
+	Path string `json:"path"`
+
+	// Format of the coverage report
+	// +kubebuilder:validation:Enum=cobertura;lcov
+	Format CoverageFormat `json:"format"`
+
+	// Comment posts the line coverage and its delta versus the base branch to the pull request as a comment
+	Comment bool `json:"comment,omitempty"`
+
+	// Threshold is a minimum line coverage (in percent). If it's set, a commit status named <job name>/coverage is set
+	// as failure if the coverage is lower than the threshold
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Threshold int32 `json:"threshold,omitempty"`
+}
+
+// JobCoverageReport is a line coverage of the job
+type JobCoverageReport struct {
+	// Covered is the number of the covered lines
+	Covered int `json:"covered"`
+
+	// Total is the number of the lines
+	Total int `json:"total"`
+
+	// Percentage is the line coverage in percent, e.g., 83.25
+	Percentage string `json:"percentage"`
+
+	// BasePercentage is the line coverage of the same job of the latest postSubmit IntegrationJob for the base branch
+	// It is only set for the pull requests
+	BasePercentage string `json:"basePercentage,omitempty"`
+}
+
+// FormatCoveragePercentage formats the coverage in percent, with two decimal places
+func FormatCoveragePercentage(percentage float64) string {
+	return strconv.FormatFloat(percentage, 'f', 2, 64)
+}
+
+// GetDelta returns the difference between the coverage and the base branch's, e.g., +1.50 or -0.25
+// It returns an empty string if the base branch's coverage is unknown
+func (r *JobCoverageReport) GetDelta() string {
+	cur, err := strconv.ParseFloat(r.Percentage, 64)
+	if err != nil {
+		return ""
+	}
+	base, err := strconv.ParseFloat(r.BasePercentage, 64)
+	if err != nil {
+		return ""
+	}
+	delta := cur - base
+	if delta >= 0 {
+		return "+" + FormatCoveragePercentage(delta)
+	}
+	return FormatCoveragePercentage(delta)
+}
+
+// IsBelow checks if the coverage is lower than the threshold
+func (r *JobCoverageReport) IsBelow(threshold int32) bool {
+	cur, err := strconv.ParseFloat(r.Percentage, 64)
+	if err != nil {
+		return true
+	}
+	return cur < float64(threshold)
+}
+
+// String returns the coverage in a form of '83.25% (333/400 lines)', or '83.25%' if the numbers of the lines are unknown
+func (r *JobCoverageReport) String() string {
+	if r.Total == 0 {
+		return r.Percentage + "%"
+	}
+	return fmt.Sprintf("%s%% (%d/%d lines)", r.Percentage, r.Covered, r.Total)
+}
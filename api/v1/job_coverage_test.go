@@ -0,0 +1,65 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobCoverageReport_GetDelta(t *testing.T) {
+	tc := map[string]struct {
+		report JobCoverageReport
+
+		expectedDelta string
+	}{
+		"increased": {
+			report:        JobCoverageReport{Percentage: "83.25", BasePercentage: "81.75"},
+			expectedDelta: "+1.50",
+		},
+		"decreased": {
+			report:        JobCoverageReport{Percentage: "80.00", BasePercentage: "80.25"},
+			expectedDelta: "-0.25",
+		},
+		"same": {
+			report:        JobCoverageReport{Percentage: "80.00", BasePercentage: "80.00"},
+			expectedDelta: "+0.00",
+		},
+		"noBase": {
+			report:        JobCoverageReport{Percentage: "80.00"},
+			expectedDelta: "",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedDelta, c.report.GetDelta())
+		})
+	}
+}
+
+func TestJobCoverageReport_IsBelow(t *testing.T) {
+	report := &JobCoverageReport{Covered: 333, Total: 400, Percentage: "83.25"}
+	require.False(t, report.IsBelow(0))
+	require.False(t, report.IsBelow(83))
+	require.True(t, report.IsBelow(84))
+	require.Equal(t, "83.25% (333/400 lines)", report.String())
+
+	report = &JobCoverageReport{Percentage: "83.25"}
+	require.Equal(t, "83.25%", report.String())
+}
@@ -34,3 +34,24 @@ const (
 	RunLabelPullRequestSha = JobLabelPrefix + "pull-request-sha"
 	RunLabelSender         = JobLabelPrefix + "sender"
 )
+
+// Annotations for IntegrationJobs
+const (
+	// JobAnnotationSupersededBy is a name of the IntegrationJob superseding the IntegrationJob, i.e., the one for a
+	// newer commit of the same pull request or branch
+	JobAnnotationSupersededBy = JobLabelPrefix + "superseded-by"
+
+	// JobAnnotationRetryOf is a name of the IntegrationJob whose failed job is retried by the IntegrationJob
+	JobAnnotationRetryOf = JobLabelPrefix + "retry-of"
+
+	// JobAnnotationQueueSequence is a sequence (the time in nanoseconds) at which the IntegrationJob entered the
+	// scheduling queue. It keeps the order of the queue across the restarts of the operator
+	JobAnnotationQueueSequence = JobLabelPrefix + "queue-sequence"
+)
+
+// Annotations for TaskRuns
+const (
+	// TaskRunAnnotationRetryBackoff is a delay (in second) before the next attempt of the TaskRun's job. It's updated
+	// while an attempt is running, and the next attempt's pod sleeps for it before running the steps
+	TaskRunAnnotationRetryBackoff = JobLabelPrefix + "retry-backoff"
+)
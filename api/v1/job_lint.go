@@ -0,0 +1,48 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+// JobLint configures the lint output of the job, which is parsed after the job's script, even if it fails
+type JobLint struct {
+	// Path of the lint output, relative to the working directory. Each issue should be a line in a form of
+	// '<file>:<line>[:<column>]: <message>', as printed by most linters (e.g., go vet, golangci-lint, eslint -f unix)
+	Path string `json:"path"`
+}
+
+// JobLintReport is a summary of the lint issues of the job
+type JobLintReport struct {
+	// Total is the number of the lint issues
+	Total int `json:"total"`
+
+	// Issues are the lint issues. At most 10 issues are kept
+	Issues []JobLintIssue `json:"issues,omitempty"`
+}
+
+// JobLintIssue is a lint issue at a line of a file
+type JobLintIssue struct {
+	// Path of the file, relative to the working directory
+	Path string `json:"path"`
+
+	// Line is the line number of the issue
+	Line int `json:"line"`
+
+	// Column is the column number of the issue. It is 0 if it's unknown
+	Column int `json:"column,omitempty"`
+
+	// Message is a description of the issue
+	Message string `json:"message"`
+}
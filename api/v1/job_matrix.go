@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MatrixParam is a dimension of the job matrix
+type MatrixParam struct {
+	// Name of the parameter. $(matrix.<name>) in the job is replaced with each of the values
+	Name string `json:"name"`
+
+	// Values of the parameter
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
+}
+
+var matrixNameInvalidChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// ExpandMatrix expands the jobs having a matrix into a job for each combination of the matrix values
+// Each combination is named as <job name>-<value 1>-<value 2>-..., and the jobs running after the matrix job are made
+// to run after all the combinations
+func (j *Jobs) ExpandMatrix() Jobs {
+	if j == nil {
+		return nil
+	}
+
+	expandedNames := map[string][]string{}
+	var expanded Jobs
+	for _, job := range *j {
+		if len(job.Matrix) == 0 {
+			expanded = append(expanded, job)
+			continue
+		}
+		for _, combination := range matrixCombinations(job.Matrix) {
+			e := expandJob(job, combination)
+			expandedNames[job.Name] = append(expandedNames[job.Name], e.Name)
+			expanded = append(expanded, e)
+		}
+	}
+
+	if len(expandedNames) == 0 {
+		return expanded
+	}
+
+	// Replace dependencies to the matrix jobs with the dependencies to all the combinations
+	for i := range expanded {
+		if len(expanded[i].After) == 0 {
+			continue
+		}
+		var after []string
+		for _, a := range expanded[i].After {
+			if names, isMatrix := expandedNames[a]; isMatrix {
+				after = append(after, names...)
+			} else {
+				after = append(after, a)
+			}
+		}
+		expanded[i].After = after
+	}
+	return expanded
+}
+
+// matrixCombinations returns all the combinations of the matrix values, in the order of the parameters
+func matrixCombinations(matrix []MatrixParam) [][]ParameterValue {
+	combinations := [][]ParameterValue{{}}
+	for _, param := range matrix {
+		var next [][]ParameterValue
+		for _, c := range combinations {
+			for _, v := range param.Values {
+				combination := append(append([]ParameterValue{}, c...), ParameterValue{Name: param.Name, StringVal: v})
+				next = append(next, combination)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// expandJob generates a job for the combination, replacing $(matrix.<name>) with the values
+func expandJob(job Job, combination []ParameterValue) Job {
+	e := *job.DeepCopy()
+	e.Matrix = nil
+
+	var replacements []string
+	nameParts := []string{job.Name}
+	for _, p := range combination {
+		replacements = append(replacements, fmt.Sprintf("$(matrix.%s)", p.Name), p.StringVal)
+		nameParts = append(nameParts, strings.Trim(matrixNameInvalidChars.ReplaceAllString(strings.ToLower(p.StringVal), "-"), "-"))
+	}
+	e.Name = strings.Join(nameParts, "-")
+
+	replaceJobVariables(&e, strings.NewReplacer(replacements...))
+	return e
+}
@@ -0,0 +1,95 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobs_ExpandMatrix(t *testing.T) {
+	jobs := Jobs{
+		{Container: corev1.Container{Name: "lint"}},
+		{
+			Container: corev1.Container{
+				Name:  "test",
+				Image: "golang:$(matrix.go)",
+				Env:   []corev1.EnvVar{{Name: "GOOS", Value: "$(matrix.os)"}},
+				EnvFrom: []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db-$(matrix.os)"}}},
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}, Prefix: "GO_$(matrix.go)_"},
+				},
+			},
+			Script:       "go test ./... # $(matrix.go)/$(matrix.os)",
+			NodeSelector: map[string]string{"kubernetes.io/os": "$(matrix.os)"},
+			After:        []string{"lint"},
+			Matrix: []MatrixParam{
+				{Name: "go", Values: []string{"1.16", "1.17"}},
+				{Name: "os", Values: []string{"linux", "Windows"}},
+			},
+		},
+		{Container: corev1.Container{Name: "package"}, After: []string{"test", "lint"}},
+	}
+
+	expanded := jobs.ExpandMatrix()
+
+	var names []string
+	for _, j := range expanded {
+		names = append(names, j.Name)
+		require.Nil(t, j.Matrix)
+	}
+	require.Equal(t, []string{"lint", "test-1-16-linux", "test-1-16-windows", "test-1-17-linux", "test-1-17-windows", "package"}, names)
+
+	require.Equal(t, "golang:1.17", expanded[3].Image)
+	require.Equal(t, "linux", expanded[3].Env[0].Value)
+	require.Equal(t, "go test ./... # 1.17/linux", expanded[3].Script)
+	require.Equal(t, "db-linux", expanded[3].EnvFrom[0].SecretRef.Name)
+	require.Equal(t, "GO_1.17_", expanded[3].EnvFrom[1].Prefix)
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, expanded[3].NodeSelector)
+	require.Equal(t, []string{"lint"}, expanded[3].After)
+	require.Equal(t, []string{"test-1-16-linux", "test-1-16-windows", "test-1-17-linux", "test-1-17-windows", "lint"}, expanded[5].After)
+
+	// Original jobs should not be modified
+	require.Equal(t, "golang:$(matrix.go)", jobs[1].Image)
+	require.Equal(t, "$(matrix.os)", jobs[1].Env[0].Value)
+	require.Equal(t, "db-$(matrix.os)", jobs[1].EnvFrom[0].SecretRef.Name)
+	require.Equal(t, "$(matrix.os)", jobs[1].NodeSelector["kubernetes.io/os"])
+	require.Equal(t, []string{"test", "lint"}, jobs[2].After)
+}
+
+func TestJobs_ExpandMatrix_tektonTask(t *testing.T) {
+	jobs := Jobs{
+		{
+			Container: corev1.Container{Name: "build"},
+			TektonTask: &TektonTask{
+				Params: []ParameterValue{
+					{Name: "IMAGE", StringVal: "registry/app:$(matrix.arch)"},
+					{Name: "ARGS", ArrayVal: []string{"--arch=$(matrix.arch)"}},
+				},
+			},
+			Matrix: []MatrixParam{{Name: "arch", Values: []string{"amd64", "arm64"}}},
+		},
+	}
+
+	expanded := jobs.ExpandMatrix()
+	require.Len(t, expanded, 2)
+	require.Equal(t, "build-arm64", expanded[1].Name)
+	require.Equal(t, "registry/app:arm64", expanded[1].TektonTask.Params[0].StringVal)
+	require.Equal(t, []string{"--arch=arm64"}, expanded[1].TektonTask.Params[1].ArrayVal)
+}
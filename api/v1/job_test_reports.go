@@ -0,0 +1,45 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+// JobTestReports configures the JUnit XML test reports of the job, which are summarized after the job's script
+type JobTestReports struct {
+	// Paths of the JUnit XML reports, relative to the working directory. Glob patterns of the shell can be used
+	// +kubebuilder:validation:MinItems=1
+	Paths []string `json:"paths"`
+
+	// Comment posts a summary of the test results, with the names of the failed tests, to the pull request as a comment
+	Comment bool `json:"comment,omitempty"`
+}
+
+// JobTestReport is a summary of the test reports of the job
+type JobTestReport struct {
+	// Total is the number of the test cases
+	Total int `json:"total"`
+
+	// Passed is the number of the passed test cases
+	Passed int `json:"passed"`
+
+	// Failed is the number of the failed (or errored) test cases
+	Failed int `json:"failed"`
+
+	// Skipped is the number of the skipped test cases
+	Skipped int `json:"skipped"`
+
+	// FailedTests are names of the failed test cases. At most 20 names are kept
+	FailedTests []string `json:"failedTests,omitempty"`
+}
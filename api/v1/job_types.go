@@ -18,8 +18,10 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tmax-cloud/cicd-operator/pkg/expression"
 	"github.com/tmax-cloud/cicd-operator/pkg/structs"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -48,6 +50,9 @@ const (
 	CommitStatusStatePending = CommitStatusState("pending")
 )
 
+// ApprovalGateSuffix is a suffix of the approval task inserted before the job requiring an approval
+const ApprovalGateSuffix = "-approval-gate"
+
 // Job is a specification of the job to be executed for specific events
 // Same level of task of tekton
 type Job struct {
@@ -59,18 +64,84 @@ type Job struct {
 	// SkipCheckout describes whether or not to checkout from git before
 	SkipCheckout bool `json:"skipCheckout,omitempty"`
 
+	// Checkout configures the git checkout step. The options set here take precedence over the IntegrationConfig's
+	Checkout *JobCheckout `json:"checkout,omitempty"`
+
 	// When is condition for running the job
 	When *JobWhen `json:"when,omitempty"`
 
 	// After configures which jobs should be executed before this job runs
 	After []string `json:"after,omitempty"`
 
+	// Manual jobs are not triggered automatically, but only by '/test <job>' comment command. Only for preSubmit jobs
+	Manual bool `json:"manual,omitempty"`
+
+	// Timeout is a maximum duration of the job's execution. The IntegrationJob fails if the job is not completed in time
+	// It is bounded by the IntegrationJob's timeout (i.e., spec.ijManageSpec.timeout of the IntegrationConfig)
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries is the number of times the job is retried when it fails
+	// +kubebuilder:validation:Minimum=0
+	Retries int `json:"retries,omitempty"`
+
+	// PodSecurityContext is a security context of the job's pod, overriding the one in the IntegrationConfig's podTemplate
+	// Container-level security context can be set in securityContext
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// NodeSelector is merged to the IntegrationConfig's podTemplate.nodeSelector for the job's pod, overriding the
+	// values of the same keys
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are appended to the IntegrationConfig's podTemplate.tolerations for the job's pod
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity overrides the IntegrationConfig's podTemplate.affinity for the job's pod
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// ServiceAccountName is a name of the ServiceAccount running the job, instead of the one generated for the
+	// IntegrationConfig (i.e., <IntegrationConfig name>-sa)
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Workspaces are the workspaces (i.e., spec.workspaces of the IntegrationConfig) the job uses
+	// Every workspace is mounted at its default path if it's not set
+	Workspaces []JobWorkspace `json:"workspaces,omitempty"`
+
+	// Artifacts are uploaded to an object storage after the job's script succeeds
+	Artifacts *JobArtifacts `json:"artifacts,omitempty"`
+
+	// TestReports are JUnit XML reports summarized after the job's script, even if the script fails
+	TestReports *JobTestReports `json:"testReports,omitempty"`
+
+	// Coverage is a coverage report parsed after the job's script, even if the script fails
+	Coverage *JobCoverage `json:"coverage,omitempty"`
+
+	// Lint is a lint output parsed after the job's script, even if the script fails. The issues are reported as
+	// file/line annotations of the check run, if spec.git.checkRuns is set
+	Lint *JobLint `json:"lint,omitempty"`
+
+	// Cache skips the job as a cached success, if the same job already succeeded for the same contents of the paths
+	Cache *JobCache `json:"cache,omitempty"`
+
+	// Matrix runs the job for each combination of the parameters' values, e.g., go version x OS
+	Matrix []MatrixParam `json:"matrix,omitempty"`
+
+	// Template refers to an IntegrationJobTemplate or a ClusterIntegrationJobTemplate the job is generated from
+	Template *JobTemplateRef `json:"template,omitempty"`
+
 	// TektonTask is for referring local Tasks or the Tasks registered in tekton catalog github repo.
 	TektonTask *TektonTask `json:"tektonTask,omitempty"`
 
+	// PipelineRef refers to an existing Pipeline, which is run instead of the pipeline generated from the jobs
+	// A job referring to a pipeline should be the only job of its kind (i.e., preSubmit or postSubmit)
+	PipelineRef *JobPipelineRef `json:"pipelineRef,omitempty"`
+
 	// Approval
 	Approval *JobApproval `json:"approval,omitempty"`
 
+	// ApprovalRequired pauses the pipeline before the job, until the job is approved. If it's set, approval specifies
+	// the approvers of the job, instead of making the job an approval job
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
 	// NotificationMethods sends noti, not running the tasks
 	NotificationMethods `json:",inline"`
 
@@ -84,12 +155,83 @@ type Job struct {
 	Results []tektonv1beta1.TaskResult `json:"results,omitempty"`
 }
 
+// JobWorkspace is a workspace used by a job
+type JobWorkspace struct {
+	// Name of the workspace, which should be one of spec.workspaces of the IntegrationConfig
+	Name string `json:"name"`
+
+	// MountPath is a path the workspace is mounted at. Default is /workspace/<name>
+	MountPath string `json:"mountPath,omitempty"`
+
+	// ReadOnly mounts the workspace as read-only
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// replaceJobVariables replaces variables (e.g., $(matrix.<name>)) in the job's image, script, working directory,
+// command, args, env, envFrom and parameters
+func replaceJobVariables(j *Job, r *strings.Replacer) {
+	j.Image = r.Replace(j.Image)
+	j.Script = r.Replace(j.Script)
+	j.WorkingDir = r.Replace(j.WorkingDir)
+	for i := range j.Command {
+		j.Command[i] = r.Replace(j.Command[i])
+	}
+	for i := range j.Args {
+		j.Args[i] = r.Replace(j.Args[i])
+	}
+	for i := range j.Env {
+		j.Env[i].Value = r.Replace(j.Env[i].Value)
+	}
+	for i := range j.EnvFrom {
+		j.EnvFrom[i].Prefix = r.Replace(j.EnvFrom[i].Prefix)
+		if j.EnvFrom[i].SecretRef != nil {
+			j.EnvFrom[i].SecretRef.Name = r.Replace(j.EnvFrom[i].SecretRef.Name)
+		}
+		if j.EnvFrom[i].ConfigMapRef != nil {
+			j.EnvFrom[i].ConfigMapRef.Name = r.Replace(j.EnvFrom[i].ConfigMapRef.Name)
+		}
+	}
+	for k, v := range j.NodeSelector {
+		j.NodeSelector[k] = r.Replace(v)
+	}
+	if j.TektonTask != nil {
+		replaceParamVariables(j.TektonTask.Params, r)
+	}
+	if j.Template != nil {
+		replaceParamVariables(j.Template.Params, r)
+	}
+}
+
+func replaceParamVariables(params []ParameterValue, r *strings.Replacer) {
+	for i := range params {
+		params[i].StringVal = r.Replace(params[i].StringVal)
+		for k := range params[i].ArrayVal {
+			params[i].ArrayVal[k] = r.Replace(params[i].ArrayVal[k])
+		}
+	}
+}
+
 // Periodic runs on a time-basis, unrelated to git changes.
 type Periodic struct {
 	Job `json:",inline"`
 
 	// Cron representation of job trigger time
 	Cron string `json:"cron,omitempty"`
+
+	// Timezone is a time zone name (e.g., Asia/Seoul) the cron is interpreted in. Default is UTC
+	Timezone string `json:"timezone,omitempty"`
+
+	// Branch is a branch to be checked out for the job. Default branch of the repository is used if it's not set
+	Branch string `json:"branch,omitempty"`
+}
+
+// GetCronSpec returns a cron spec with the timezone prefix
+func (p *Periodic) GetCronSpec() string {
+	tz := p.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return fmt.Sprintf("TZ=%s %s", tz, p.Cron)
 }
 
 // TektonTask refers to an existing tekton task, rather than using job's script or command
@@ -115,6 +257,105 @@ type JobTaskRef struct {
 	// Catalog is a name of the task @ tekton catalog github repo. (e.g., s2i@0.2)
 	// FYI: https://github.com/tektoncd/catalog
 	Catalog string `json:"catalog,omitempty"`
+
+	// Bundle refers to a task in a Tekton bundle, i.e., an OCI image. Tekton's enable-tekton-oci-bundles feature flag
+	// should be enabled
+	Bundle *JobTaskBundle `json:"bundle,omitempty"`
+
+	// Resolver resolves a task from the remote source (i.e., git, hub or cluster)
+	Resolver *JobTaskResolver `json:"resolver,omitempty"`
+}
+
+// JobTaskBundle refers to a task in a Tekton bundle
+type JobTaskBundle struct {
+	// Image is a reference of the bundle image (e.g., docker.io/tektoncd/catalog:v0.1)
+	Image string `json:"image"`
+
+	// Name of the task in the bundle
+	Name string `json:"name"`
+}
+
+// TaskResolverType is a type of the task resolver
+type TaskResolverType string
+
+// Task resolver types
+const (
+	TaskResolverTypeGit     = TaskResolverType("git")
+	TaskResolverTypeHub     = TaskResolverType("hub")
+	TaskResolverTypeCluster = TaskResolverType("cluster")
+)
+
+// taskResolverParam is a parameter of a resolver type
+type taskResolverParam struct {
+	name     string
+	required bool
+}
+
+// taskResolverParams are parameters of each resolver type
+var taskResolverParams = map[TaskResolverType][]taskResolverParam{
+	// repository: <org>/<repo> in the IntegrationConfig's git server, default is the IntegrationJob's repository
+	// revision: default is the IntegrationJob's head sha
+	// pathInRepo: path of the task's yaml file
+	TaskResolverTypeGit: {{name: "repository"}, {name: "revision"}, {name: "pathInRepo", required: true}},
+	// catalog: default is tekton
+	TaskResolverTypeHub: {{name: "catalog"}, {name: "name", required: true}, {name: "version", required: true}},
+	// namespace: default is the IntegrationConfig's namespace
+	TaskResolverTypeCluster: {{name: "namespace"}, {name: "name", required: true}},
+}
+
+// JobTaskResolver resolves a task from the remote source, in the same manner as Tekton's remote resolution
+// The task is fetched when the IntegrationJob is scheduled, and is embedded into the pipeline
+type JobTaskResolver struct {
+	// Type of the resolver
+	// git fetches a task from the repository in the IntegrationConfig's git server (params: repository, revision, pathInRepo)
+	// hub fetches a task from Tekton Hub (params: catalog, name, version)
+	// cluster fetches a Task in the cluster (params: namespace, name)
+	// +kubebuilder:validation:Enum=git;hub;cluster
+	Type TaskResolverType `json:"type"`
+
+	// Params are parameters of the resolver
+	Params []ParameterValue `json:"params,omitempty"`
+}
+
+// GetParam returns the resolver's parameter value
+func (r *JobTaskResolver) GetParam(name string) string {
+	for _, p := range r.Params {
+		if p.Name == name {
+			return p.StringVal
+		}
+	}
+	return ""
+}
+
+// Validate checks if the resolver's type and parameters are valid
+func (r *JobTaskResolver) Validate() error {
+	params, ok := taskResolverParams[r.Type]
+	if !ok {
+		return fmt.Errorf("resolver type %s is not supported", r.Type)
+	}
+	names := map[string]struct{}{}
+	for _, p := range params {
+		names[p.name] = struct{}{}
+		if p.required && r.GetParam(p.name) == "" {
+			return fmt.Errorf("%s resolver requires parameter %s", r.Type, p.name)
+		}
+	}
+	for _, p := range r.Params {
+		if _, exist := names[p.Name]; !exist {
+			return fmt.Errorf("%s resolver does not have parameter %s", r.Type, p.Name)
+		}
+	}
+	return nil
+}
+
+// JobPipelineRef refers to an existing Pipeline in the IntegrationConfig's namespace
+type JobPipelineRef struct {
+	// Name of the Pipeline
+	Name string `json:"name"`
+
+	// Params are input params for the pipeline. The default environment variables (e.g., $(CI_HEAD_SHA)) in the values
+	// are replaced with the values of the IntegrationJob
+	Params []ParameterValue `json:"params,omitempty"`
 }
 
 // JobApproval describes who can approve it
@@ -132,15 +373,51 @@ type JobApproval struct {
 }
 
 // JobWhen describes when the Job should be executed
-// All fields should be regular expressions
+// All fields should be regular expressions, except for Paths and SkipPaths
 type JobWhen struct {
-	Branch     []string `json:"branch,omitempty"`
+	// Branch is a list of regular expressions of the branch. The job is triggered only if the branch matches any of them
+	Branch []string `json:"branch,omitempty"`
+	// SkipBranch is a list of regular expressions of the branch. The job is not triggered if the branch matches any of them
 	SkipBranch []string `json:"skipBranch,omitempty"`
 
 	Tag     []string `json:"tag,omitempty"`
 	SkipTag []string `json:"skipTag,omitempty"`
+
+	// Release is a list of regular expressions of the release's tag. If it's set, the job is triggered only by release events
+	Release []string `json:"release,omitempty"`
+
+	// Paths is a list of glob patterns of the changed files, e.g., docs/**, **/*.go
+	// If it's set, the job is triggered only if any of the changed files matches
+	Paths []string `json:"paths,omitempty"`
+
+	// SkipPaths is a list of glob patterns of the changed files. The job is not triggered if all the changed files match
+	SkipPaths []string `json:"skipPaths,omitempty"`
+
+	// Expression is a boolean expression evaluated against the event, e.g., "ok-to-test" in labels && changedFiles < 100
+	// If it is evaluated to false, the job is skipped but still reported as a successful commit status
+	// Available variables are listed in JobWhenExpressionVariables
+	Expression string `json:"expression,omitempty"`
 }
 
+// Variables available in JobWhen.Expression
+const (
+	// JobWhenVarEvent is the event type, i.e., pull_request or push
+	JobWhenVarEvent = "event"
+	// JobWhenVarBranch is the target branch of the pull request or the pushed branch
+	JobWhenVarBranch = "branch"
+	// JobWhenVarTag is the pushed tag
+	JobWhenVarTag = "tag"
+	// JobWhenVarAuthor is the author of the pull request or the sender of the push event
+	JobWhenVarAuthor = "author"
+	// JobWhenVarLabels is a list of the pull request's labels
+	JobWhenVarLabels = "labels"
+	// JobWhenVarChangedFiles is the number of the changed files. It is -1 if it cannot be determined
+	JobWhenVarChangedFiles = "changedFiles"
+)
+
+// JobWhenExpressionVariables is a list of variables available in JobWhen.Expression
+var JobWhenExpressionVariables = []string{JobWhenVarEvent, JobWhenVarBranch, JobWhenVarTag, JobWhenVarAuthor, JobWhenVarLabels, JobWhenVarChangedFiles}
+
 // JobStatus is a current status for each job
 type JobStatus struct {
 	// Name is a job name
@@ -160,11 +437,26 @@ type JobStatus struct {
 	// It is actually tekton task run's Status.Conditions[0].Message
 	Message string `json:"message"`
 
+	// Attempts is the number of attempts to run the job, including the retries
+	Attempts int `json:"attempts,omitempty"`
+
 	// PodName is a name of pod where the job is running
 	PodName string `json:"podName,omitempty"`
 
 	// Containers is status list for each step in the job
 	Containers []tektonv1beta1.StepState `json:"containers,omitempty"`
+
+	// Artifacts are URLs of the uploaded artifacts
+	Artifacts []string `json:"artifacts,omitempty"`
+
+	// TestReport is a summary of the job's test reports
+	TestReport *JobTestReport `json:"testReport,omitempty"`
+
+	// Coverage is a line coverage of the job
+	Coverage *JobCoverageReport `json:"coverage,omitempty"`
+
+	// Lint is a summary of the job's lint issues
+	Lint *JobLintReport `json:"lint,omitempty"`
 }
 
 // Equals checks if i is equal to j
@@ -172,7 +464,8 @@ func (j *JobStatus) Equals(i *JobStatus) bool {
 	return j.State == i.State &&
 		j.Message == i.Message &&
 		j.StartTime.Equal(i.StartTime) &&
-		j.CompletionTime.Equal(i.CompletionTime)
+		j.CompletionTime.Equal(i.CompletionTime) &&
+		j.Attempts == i.Attempts
 }
 
 // Jobs is an array of Job
@@ -195,5 +488,232 @@ func (j *Jobs) GetGraph() (structs.Graph, error) {
 	return graph, nil
 }
 
+// Validate checks if the job names are unique, the jobs' dependencies (i.e., after) form a valid DAG
+// and the jobs' matrix, approval gates, when.expression, artifacts, test reports, coverage and cache are valid
+func (j *Jobs) Validate() error {
+	names := map[string]struct{}{}
+	for _, job := range *j {
+		if _, exist := names[job.Name]; exist {
+			return fmt.Errorf("job %s is duplicated", job.Name)
+		}
+		names[job.Name] = struct{}{}
+	}
+
+	manualJobs := map[string]struct{}{}
+	for _, job := range *j {
+		if job.Manual {
+			manualJobs[job.Name] = struct{}{}
+		}
+	}
+
+	for _, job := range *j {
+		for _, after := range job.After {
+			if after == job.Name {
+				return fmt.Errorf("job %s cannot run after itself", job.Name)
+			}
+			if _, exist := names[after]; !exist {
+				return fmt.Errorf("job %s cannot run after %s, which does not exist", job.Name, after)
+			}
+			if _, manual := manualJobs[after]; manual && !job.Manual {
+				return fmt.Errorf("job %s cannot run after %s, which is a manual job", job.Name, after)
+			}
+		}
+	}
+
+	if err := j.validateMatrix(); err != nil {
+		return err
+	}
+
+	if err := j.validatePipelineRef(); err != nil {
+		return err
+	}
+
+	if err := j.validateApprovalRequired(names); err != nil {
+		return err
+	}
+
+	for _, job := range *j {
+		if job.TektonTask == nil || job.TektonTask.TaskRef.Resolver == nil {
+			continue
+		}
+		if err := job.TektonTask.TaskRef.Resolver.Validate(); err != nil {
+			return fmt.Errorf("job %s has an invalid resolver: %s", job.Name, err.Error())
+		}
+	}
+
+	for _, job := range *j {
+		if job.When == nil || job.When.Expression == "" {
+			continue
+		}
+		if err := validateExpression(job.When.Expression); err != nil {
+			return fmt.Errorf("job %s has an invalid expression: %s", job.Name, err.Error())
+		}
+	}
+
+	for _, job := range *j {
+		if job.runsScript() {
+			continue
+		}
+		if job.Artifacts != nil {
+			return fmt.Errorf("job %s does not run a script, so it cannot upload artifacts", job.Name)
+		}
+		if job.TestReports != nil {
+			return fmt.Errorf("job %s does not run a script, so it cannot have test reports", job.Name)
+		}
+		if job.Coverage != nil {
+			return fmt.Errorf("job %s does not run a script, so it cannot have a coverage report", job.Name)
+		}
+		if job.Lint != nil {
+			return fmt.Errorf("job %s does not run a script, so it cannot have a lint output", job.Name)
+		}
+	}
+
+	for _, job := range *j {
+		if job.Cache != nil && job.Template != nil {
+			return fmt.Errorf("job %s refers to a template, so it cannot be cached", job.Name)
+		}
+	}
+
+	if _, err := j.GetGraph(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateWorkspaces checks if the jobs use only the workspaces declared
+func (j *Jobs) ValidateWorkspaces(workspaces []tektonv1beta1.WorkspaceBinding) error {
+	declared := map[string]struct{}{}
+	for _, w := range workspaces {
+		declared[w.Name] = struct{}{}
+	}
+
+	for _, job := range *j {
+		used := map[string]struct{}{}
+		for _, w := range job.Workspaces {
+			if _, exist := declared[w.Name]; !exist {
+				return fmt.Errorf("job %s uses workspace %s, which is not declared", job.Name, w.Name)
+			}
+			if _, exist := used[w.Name]; exist {
+				return fmt.Errorf("job %s uses workspace %s more than once", job.Name, w.Name)
+			}
+			used[w.Name] = struct{}{}
+		}
+		if job.TektonTask == nil {
+			continue
+		}
+		for _, w := range job.TektonTask.Workspaces {
+			if _, exist := declared[w.Workspace]; !exist {
+				return fmt.Errorf("job %s uses workspace %s, which is not declared", job.Name, w.Workspace)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMatrix checks if the matrix parameters are valid and the expanded jobs' names are unique
+func (j *Jobs) validateMatrix() error {
+	for _, job := range *j {
+		params := map[string]struct{}{}
+		for _, p := range job.Matrix {
+			if p.Name == "" {
+				return fmt.Errorf("job %s has a matrix parameter without a name", job.Name)
+			}
+			if _, exist := params[p.Name]; exist {
+				return fmt.Errorf("job %s has a duplicated matrix parameter %s", job.Name, p.Name)
+			}
+			if len(p.Values) == 0 {
+				return fmt.Errorf("job %s has a matrix parameter %s without values", job.Name, p.Name)
+			}
+			params[p.Name] = struct{}{}
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, job := range j.ExpandMatrix() {
+		if _, exist := names[job.Name]; exist {
+			return fmt.Errorf("job %s is duplicated after expanding the matrix", job.Name)
+		}
+		names[job.Name] = struct{}{}
+	}
+	return nil
+}
+
+// validatePipelineRef checks if the job referring to a pipeline is the only job and has nothing else to run
+func (j *Jobs) validatePipelineRef() error {
+	for _, job := range *j {
+		if job.PipelineRef == nil {
+			continue
+		}
+		if job.PipelineRef.Name == "" {
+			return fmt.Errorf("job %s refers to a pipeline without a name", job.Name)
+		}
+		if len(*j) > 1 {
+			return fmt.Errorf("job %s refers to a pipeline, so it should be the only job", job.Name)
+		}
+		if job.Image != "" || job.Script != "" || job.TektonTask != nil || job.Approval != nil || job.Email != nil || job.Slack != nil || job.Template != nil || len(job.Matrix) > 0 {
+			return fmt.Errorf("job %s refers to a pipeline, so it cannot have image, script, tektonTask, approval, email, slack, template or matrix", job.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateRemoteCluster checks if the jobs can run in a remote cluster, i.e., none of them is an approval, email or
+// slack job, or requires an approval, as they are run by the operator in its cluster
+func (j *Jobs) ValidateRemoteCluster() error {
+	for _, job := range *j {
+		if job.Approval != nil || job.ApprovalRequired || job.Email != nil || job.Slack != nil {
+			return fmt.Errorf("job %s is an approval, email or slack job or requires an approval, so it cannot run in the remote cluster", job.Name)
+		}
+	}
+	return nil
+}
+
+// GetApprovalGateName returns the name of the approval task inserted before the job, if it requires an approval
+func (j *Job) GetApprovalGateName() string {
+	return j.Name + ApprovalGateSuffix
+}
+
+// validateApprovalRequired checks if the approval gates of the jobs requiring approvals can be inserted
+func (j *Jobs) validateApprovalRequired(names map[string]struct{}) error {
+	for _, job := range *j {
+		if !job.ApprovalRequired {
+			continue
+		}
+		if job.PipelineRef != nil {
+			return fmt.Errorf("job %s refers to a pipeline, so it cannot require an approval", job.Name)
+		}
+		if _, exist := names[job.GetApprovalGateName()]; exist {
+			return fmt.Errorf("job %s requires an approval, so job %s conflicts with its approval gate", job.Name, job.GetApprovalGateName())
+		}
+	}
+	return nil
+}
+
+// runsScript checks if the job runs its script (or command), i.e., it is not a Tekton Task, Pipeline, approval or
+// notification job
+func (j *Job) runsScript() bool {
+	return j.TektonTask == nil && j.PipelineRef == nil && (j.Approval == nil || j.ApprovalRequired) && j.Email == nil && j.Slack == nil
+}
+
+func validateExpression(expr string) error {
+	e, err := expression.Parse(expr)
+	if err != nil {
+		return err
+	}
+	for _, ident := range e.Identifiers() {
+		known := false
+		for _, v := range JobWhenExpressionVariables {
+			if ident == v {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("undefined variable %s", ident)
+		}
+	}
+	return nil
+}
+
 // Periodics is an array of PeriodicJob
 type Periodics []Periodic
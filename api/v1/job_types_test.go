@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -79,6 +80,21 @@ func TestJobStatus_Equals(t *testing.T) {
 			},
 			equals: false,
 		},
+		"notEqualAttempts": {
+			job1: &JobStatus{
+				State:     CommitStatusStatePending,
+				Message:   "message1",
+				StartTime: &metav1.Time{Time: time1},
+				Attempts:  1,
+			},
+			job2: &JobStatus{
+				State:     CommitStatusStatePending,
+				Message:   "message1",
+				StartTime: &metav1.Time{Time: time1},
+				Attempts:  2,
+			},
+			equals: false,
+		},
 	}
 
 	for name, c := range tc {
@@ -88,6 +104,367 @@ func TestJobStatus_Equals(t *testing.T) {
 	}
 }
 
+func TestJobs_Validate(t *testing.T) {
+	tc := map[string]struct {
+		jobs Jobs
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"normal": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}},
+				{Container: corev1.Container{Name: "test-1"}, After: []string{"build"}},
+				{Container: corev1.Container{Name: "test-2"}, After: []string{"build"}},
+				{Container: corev1.Container{Name: "package"}, After: []string{"test-1", "test-2"}},
+			},
+		},
+		"duplicated": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}},
+				{Container: corev1.Container{Name: "build"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job build is duplicated",
+		},
+		"self": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, After: []string{"build"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job build cannot run after itself",
+		},
+		"notExist": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test cannot run after build, which does not exist",
+		},
+		"afterManual": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, Manual: true},
+				{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test cannot run after build, which is a manual job",
+		},
+		"manualAfterManual": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, Manual: true},
+				{Container: corev1.Container{Name: "test"}, After: []string{"build"}, Manual: true},
+			},
+		},
+		"cyclic": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, After: []string{"package"}},
+				{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+				{Container: corev1.Container{Name: "package"}, After: []string{"test"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job graph is cyclic",
+		},
+		"matrix": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, Matrix: []MatrixParam{{Name: "go", Values: []string{"1.16", "1.17"}}}},
+				{Container: corev1.Container{Name: "package"}, After: []string{"test"}},
+			},
+		},
+		"matrixDuplicatedParam": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, Matrix: []MatrixParam{{Name: "go", Values: []string{"1.16"}}, {Name: "go", Values: []string{"1.17"}}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test has a duplicated matrix parameter go",
+		},
+		"matrixNoValues": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, Matrix: []MatrixParam{{Name: "go"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test has a matrix parameter go without values",
+		},
+		"matrixDuplicatedName": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, Matrix: []MatrixParam{{Name: "go", Values: []string{"1.16", "1_16"}}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test-1-16 is duplicated after expanding the matrix",
+		},
+		"expression": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, When: &JobWhen{Expression: `"ok-to-test" in labels && changedFiles < 100`}},
+			},
+		},
+		"expressionSyntaxError": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, When: &JobWhen{Expression: `branch ==`}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test has an invalid expression: unexpected end of expression",
+		},
+		"expressionUndefinedVariable": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, When: &JobWhen{Expression: `brnch == "master"`}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test has an invalid expression: undefined variable brnch",
+		},
+		"pipelineRef": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "pipeline"}, PipelineRef: &JobPipelineRef{Name: "build-and-test"}},
+			},
+		},
+		"pipelineRefNoName": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "pipeline"}, PipelineRef: &JobPipelineRef{}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job pipeline refers to a pipeline without a name",
+		},
+		"pipelineRefNotOnlyJob": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "lint"}},
+				{Container: corev1.Container{Name: "pipeline"}, PipelineRef: &JobPipelineRef{Name: "build-and-test"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job pipeline refers to a pipeline, so it should be the only job",
+		},
+		"pipelineRefWithScript": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "pipeline"}, Script: "make test", PipelineRef: &JobPipelineRef{Name: "build-and-test"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job pipeline refers to a pipeline, so it cannot have image, script, tektonTask, approval, email, slack, template or matrix",
+		},
+		"approvalRequired": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "deploy"}, ApprovalRequired: true},
+			},
+		},
+		"approvalRequiredConflict": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "deploy"}, ApprovalRequired: true},
+				{Container: corev1.Container{Name: "deploy-approval-gate"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job deploy requires an approval, so job deploy-approval-gate conflicts with its approval gate",
+		},
+		"approvalRequiredPipelineRef": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "pipeline"}, PipelineRef: &JobPipelineRef{Name: "build-and-test"}, ApprovalRequired: true},
+			},
+			errorOccurs:  true,
+			errorMessage: "job pipeline refers to a pipeline, so it cannot require an approval",
+		},
+		"resolverInvalid": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, TektonTask: &TektonTask{TaskRef: JobTaskRef{Resolver: &JobTaskResolver{Type: TaskResolverTypeGit}}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job build has an invalid resolver: git resolver requires parameter pathInRepo",
+		},
+		"artifacts": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, Artifacts: &JobArtifacts{Paths: []string{"bin/*"}}},
+				{Container: corev1.Container{Name: "release"}, Artifacts: &JobArtifacts{Paths: []string{"bin/*"}}, Approval: &JobApproval{}, ApprovalRequired: true},
+			},
+		},
+		"artifactsNotScript": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "build"}, TektonTask: &TektonTask{}, Artifacts: &JobArtifacts{Paths: []string{"bin/*"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job build does not run a script, so it cannot upload artifacts",
+		},
+		"testReportsNotScript": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "notify"}, NotificationMethods: NotificationMethods{Slack: &NotiSlack{}}, TestReports: &JobTestReports{Paths: []string{"report.xml"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job notify does not run a script, so it cannot have test reports",
+		},
+		"coverageNotScript": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "notify"}, NotificationMethods: NotificationMethods{Slack: &NotiSlack{}}, Coverage: &JobCoverage{Path: "coverage.xml", Format: CoverageFormatCobertura}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job notify does not run a script, so it cannot have a coverage report",
+		},
+		"lintNotScript": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "notify"}, NotificationMethods: NotificationMethods{Slack: &NotiSlack{}}, Lint: &JobLint{Path: "lint.txt"}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job notify does not run a script, so it cannot have a lint output",
+		},
+		"cacheTemplate": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "test"}, Template: &JobTemplateRef{Name: "go-test"}, Cache: &JobCache{Paths: []string{"**/*.go"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job test refers to a template, so it cannot be cached",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.jobs.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJobTaskResolver_Validate(t *testing.T) {
+	tc := map[string]struct {
+		resolver JobTaskResolver
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"git": {
+			resolver: JobTaskResolver{Type: TaskResolverTypeGit, Params: []ParameterValue{{Name: "pathInRepo", StringVal: "task.yaml"}, {Name: "revision", StringVal: "main"}}},
+		},
+		"hub": {
+			resolver: JobTaskResolver{Type: TaskResolverTypeHub, Params: []ParameterValue{{Name: "name", StringVal: "git-clone"}, {Name: "version", StringVal: "0.5"}}},
+		},
+		"cluster": {
+			resolver: JobTaskResolver{Type: TaskResolverTypeCluster, Params: []ParameterValue{{Name: "name", StringVal: "build"}}},
+		},
+		"unknownType": {
+			resolver:     JobTaskResolver{Type: "bundles"},
+			errorOccurs:  true,
+			errorMessage: "resolver type bundles is not supported",
+		},
+		"requiredParam": {
+			resolver:     JobTaskResolver{Type: TaskResolverTypeHub, Params: []ParameterValue{{Name: "name", StringVal: "git-clone"}}},
+			errorOccurs:  true,
+			errorMessage: "hub resolver requires parameter version",
+		},
+		"unknownParam": {
+			resolver:     JobTaskResolver{Type: TaskResolverTypeCluster, Params: []ParameterValue{{Name: "name", StringVal: "build"}, {Name: "kind", StringVal: "task"}}},
+			errorOccurs:  true,
+			errorMessage: "cluster resolver does not have parameter kind",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.resolver.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJobs_ValidateWorkspaces(t *testing.T) {
+	workspaces := []tektonv1beta1.WorkspaceBinding{{Name: "build"}, {Name: "cache"}}
+
+	tc := map[string]struct {
+		jobs Jobs
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"normal": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "compile"}, Workspaces: []JobWorkspace{{Name: "build"}, {Name: "cache", MountPath: "/root/.cache"}}},
+				{Container: corev1.Container{Name: "test"}, Workspaces: []JobWorkspace{{Name: "build", ReadOnly: true}}},
+				{Container: corev1.Container{Name: "lint"}},
+				{Container: corev1.Container{Name: "s2i"}, TektonTask: &TektonTask{Workspaces: []tektonv1beta1.WorkspacePipelineTaskBinding{{Name: "source", Workspace: "build"}}}},
+			},
+		},
+		"notDeclared": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "compile"}, Workspaces: []JobWorkspace{{Name: "output"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job compile uses workspace output, which is not declared",
+		},
+		"duplicated": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "compile"}, Workspaces: []JobWorkspace{{Name: "build"}, {Name: "build", MountPath: "/build"}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job compile uses workspace build more than once",
+		},
+		"tektonTaskNotDeclared": {
+			jobs: Jobs{
+				{Container: corev1.Container{Name: "s2i"}, TektonTask: &TektonTask{Workspaces: []tektonv1beta1.WorkspacePipelineTaskBinding{{Name: "source", Workspace: "s2i"}}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "job s2i uses workspace s2i, which is not declared",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.jobs.ValidateWorkspaces(workspaces)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJobs_ValidateRemoteCluster(t *testing.T) {
+	tc := map[string]struct {
+		job Job
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"normal": {
+			job: Job{Container: corev1.Container{Name: "test"}},
+		},
+		"approval": {
+			job:          Job{Container: corev1.Container{Name: "approval"}, Approval: &JobApproval{}},
+			errorOccurs:  true,
+			errorMessage: "job approval is an approval, email or slack job or requires an approval, so it cannot run in the remote cluster",
+		},
+		"approvalRequired": {
+			job:          Job{Container: corev1.Container{Name: "deploy"}, ApprovalRequired: true, Approval: &JobApproval{}},
+			errorOccurs:  true,
+			errorMessage: "job deploy is an approval, email or slack job or requires an approval, so it cannot run in the remote cluster",
+		},
+		"email": {
+			job:          Job{Container: corev1.Container{Name: "email"}, NotificationMethods: NotificationMethods{Email: &NotiEmail{}}},
+			errorOccurs:  true,
+			errorMessage: "job email is an approval, email or slack job or requires an approval, so it cannot run in the remote cluster",
+		},
+		"slack": {
+			job:          Job{Container: corev1.Container{Name: "slack"}, NotificationMethods: NotificationMethods{Slack: &NotiSlack{}}},
+			errorOccurs:  true,
+			errorMessage: "job slack is an approval, email or slack job or requires an approval, so it cannot run in the remote cluster",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			jobs := Jobs{{Container: corev1.Container{Name: "build"}}, c.job}
+			err := jobs.ValidateRemoteCluster()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestJobs_GetGraph(t *testing.T) {
 	tc := map[string]struct {
 		jobs Jobs
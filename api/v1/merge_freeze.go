@@ -0,0 +1,130 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultFreezeOverrideLabel is a default label which lets a PR be merged during a merge freeze
+const DefaultFreezeOverrideLabel = "ci/freeze-override"
+
+// MergeFreeze is the periods during which the mergeable PRs are not merged
+type MergeFreeze struct {
+	// Periods are the freeze periods
+	Periods []FreezePeriod `json:"periods"`
+
+	// OverrideLabel is a label which lets a PR be merged during the freeze, e.g., for emergency fixes.
+	// Default is ci/freeze-override
+	OverrideLabel string `json:"overrideLabel,omitempty"`
+}
+
+// FreezePeriod is a period during which the PRs are not merged. It's either a fixed period (start/end) or a recurring
+// period (window)
+type FreezePeriod struct {
+	// Branches are the base branches frozen during the period. Every branch is frozen if it's empty
+	Branches []string `json:"branches,omitempty"`
+
+	// Start is the time the freeze starts at. The freeze starts right away if it's not set
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the time the freeze ends at. The freeze lasts until it's removed if it's not set
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Window is a recurring freeze period, e.g., {cron: "0 18 * * 5", duration: 62h} for weekends
+	Window *ExecutionWindow `json:"window,omitempty"`
+}
+
+// GetOverrideLabel returns the override label, or the default one if it's not set
+func (f *MergeFreeze) GetOverrideLabel() string {
+	if f.OverrideLabel == "" {
+		return DefaultFreezeOverrideLabel
+	}
+	return f.OverrideLabel
+}
+
+// Validate validates the periods of the freeze
+func (f *MergeFreeze) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for i, p := range f.Periods {
+		if p.Window != nil {
+			if p.Start != nil || p.End != nil {
+				return fmt.Errorf("mergeConfig.freeze.periods[%d] cannot have both a window and start/end", i)
+			}
+			if err := (ExecutionWindows{*p.Window}).Validate(); err != nil {
+				return fmt.Errorf("mergeConfig.freeze.periods[%d] has an invalid window: %s", i, err.Error())
+			}
+			continue
+		}
+		if p.Start == nil && p.End == nil {
+			return fmt.Errorf("mergeConfig.freeze.periods[%d] should have a window or start/end", i)
+		}
+		if p.Start != nil && p.End != nil && !p.End.After(p.Start.Time) {
+			return fmt.Errorf("mergeConfig.freeze.periods[%d] should end after it starts", i)
+		}
+	}
+	return nil
+}
+
+// ActivePeriod returns the freeze period active for the base branch at the time. It returns nil if the branch is not
+// frozen. The invalid periods are ignored
+func (f *MergeFreeze) ActivePeriod(branch string, now time.Time) *FreezePeriod {
+	if f == nil {
+		return nil
+	}
+	for i, p := range f.Periods {
+		if p.AppliesTo(branch) && p.IsActive(now) {
+			return &f.Periods[i]
+		}
+	}
+	return nil
+}
+
+// AppliesTo returns if the period freezes the base branch
+func (p *FreezePeriod) AppliesTo(branch string) bool {
+	if len(p.Branches) == 0 {
+		return true
+	}
+	for _, b := range p.Branches {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive returns if the period is active at the time
+func (p *FreezePeriod) IsActive(now time.Time) bool {
+	if p.Window != nil {
+		return (ExecutionWindows{*p.Window}).IsOpen(now)
+	}
+	if p.Start == nil && p.End == nil {
+		return false
+	}
+	if p.Start != nil && now.Before(p.Start.Time) {
+		return false
+	}
+	if p.End != nil && !now.Before(p.End.Time) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeFreeze_Validate(t *testing.T) {
+	start := metav1.NewTime(time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC))
+	window := &ExecutionWindow{Cron: "0 18 * * 5", Duration: metav1.Duration{Duration: 62 * time.Hour}}
+
+	tc := map[string]struct {
+		freeze *MergeFreeze
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"nil": {},
+		"valid": {
+			freeze: &MergeFreeze{Periods: []FreezePeriod{{Start: &start, End: &end}, {Window: window}, {Start: &start}}},
+		},
+		"empty": {
+			freeze:       &MergeFreeze{Periods: []FreezePeriod{{Branches: []string{"master"}}}},
+			errorOccurs:  true,
+			errorMessage: "mergeConfig.freeze.periods[0] should have a window or start/end",
+		},
+		"windowAndStart": {
+			freeze:       &MergeFreeze{Periods: []FreezePeriod{{Start: &start, Window: window}}},
+			errorOccurs:  true,
+			errorMessage: "mergeConfig.freeze.periods[0] cannot have both a window and start/end",
+		},
+		"invalidWindow": {
+			freeze:       &MergeFreeze{Periods: []FreezePeriod{{Window: &ExecutionWindow{Cron: "0 18 * * 5"}}}},
+			errorOccurs:  true,
+			errorMessage: "mergeConfig.freeze.periods[0] has an invalid window: executionWindows[0] should have a positive duration",
+		},
+		"endBeforeStart": {
+			freeze:       &MergeFreeze{Periods: []FreezePeriod{{Start: &end, End: &start}}},
+			errorOccurs:  true,
+			errorMessage: "mergeConfig.freeze.periods[0] should end after it starts",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.freeze.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMergeFreeze_ActivePeriod(t *testing.T) {
+	start := metav1.NewTime(time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC))
+	holidays := FreezePeriod{Branches: []string{"master"}, Start: &start, End: &end}
+	weekends := FreezePeriod{Window: &ExecutionWindow{Cron: "0 18 * * 5", Duration: metav1.Duration{Duration: 62 * time.Hour}}}
+
+	tc := map[string]struct {
+		freeze *MergeFreeze
+		branch string
+		now    time.Time
+
+		expectedPeriod *FreezePeriod
+	}{
+		"nil": {
+			branch: "master",
+			now:    time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC),
+		},
+		"fixedPeriod": {
+			freeze:         &MergeFreeze{Periods: []FreezePeriod{holidays}},
+			branch:         "master",
+			now:            time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC),
+			expectedPeriod: &holidays,
+		},
+		"fixedPeriodEnded": {
+			freeze: &MergeFreeze{Periods: []FreezePeriod{holidays}},
+			branch: "master",
+			now:    end.Time,
+		},
+		"otherBranch": {
+			freeze: &MergeFreeze{Periods: []FreezePeriod{holidays}},
+			branch: "release",
+			now:    time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC),
+		},
+		"window": {
+			freeze:         &MergeFreeze{Periods: []FreezePeriod{holidays, weekends}},
+			branch:         "release",
+			now:            time.Date(2022, 1, 8, 12, 0, 0, 0, time.UTC),
+			expectedPeriod: &weekends,
+		},
+		"windowClosed": {
+			freeze: &MergeFreeze{Periods: []FreezePeriod{holidays, weekends}},
+			branch: "release",
+			now:    time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedPeriod, c.freeze.ActivePeriod(c.branch, c.now))
+		})
+	}
+}
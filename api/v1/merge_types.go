@@ -16,28 +16,146 @@
 
 package v1
 
-import "github.com/tmax-cloud/cicd-operator/pkg/git"
+import (
+	"fmt"
+	"path"
+
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // MergeConfig is a config struct of the merge automation feature
 type MergeConfig struct {
 	// Method is a merge method
-	// +kubebuilder:validation:Enum=squash;merge
+	// +kubebuilder:validation:Enum=squash;merge;rebase
 	Method git.MergeMethod `json:"method,omitempty"`
 
+	// BranchMethods override the merge method for the PRs into specific base branches
+	BranchMethods []BranchMergeMethod `json:"branchMethods,omitempty"`
+
+	// Squash is options for the squash merge
+	Squash *SquashOptions `json:"squash,omitempty"`
+
 	// CommitTemplate is a message template for a merge commit.
 	// The commit message is compiled as a go template using blocker.PullRequest object.
 	CommitTemplate string `json:"commitTemplate,omitempty"`
 
 	// Query is conditions for a open PR to be merged
 	Query MergeQuery `json:"query"`
+
+	// BranchQueries override the query for the PRs into specific base branches (e.g., stricter conditions for the
+	// release branches). The first one matching the base branch is used
+	BranchQueries []BranchMergeQuery `json:"branchQueries,omitempty"`
+
+	// UpdateBranch updates the branch of a mergeable PR via the git server, if it's behind the base branch, instead of
+	// testing it again in a batch. GitHub merges the base branch into the PR's branch, and GitLab rebases the PR's branch
+	// onto the base branch. The updated PR is merged after its checks pass again.
+	UpdateBranch bool `json:"updateBranch,omitempty"`
+
+	// StaleAfter is how old the successful checks of a mergeable PR can be. If any of them is older, the PR is tested
+	// again by a new IntegrationJob and is merged only when it passes
+	StaleAfter *metav1.Duration `json:"staleAfter,omitempty"`
+
+	// DeleteBranch deletes the head branch of a PR after it's merged. Head branches of the PRs from forked repositories
+	// are never deleted
+	DeleteBranch bool `json:"deleteBranch,omitempty"`
+
+	// DryRun only reports what the blocker would do for the mergeable PRs, via the comments, the blocker commit status,
+	// and the merge pool status, without merging them, updating their branches, or testing them in batches
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// PriorityLabels are the labels moving the PRs to the front of the merge pool (e.g., priority/critical). The former
+	// label has the higher priority, and the PRs with the same priority are merged in order of their IDs
+	PriorityLabels []string `json:"priorityLabels,omitempty"`
+
+	// Sync tunes how often the blocker retests the PRs and how many PRs it synchronizes at once, to save the git API
+	Sync *MergeSync `json:"sync,omitempty"`
+
+	// Queue tests the mergeable PRs together in batches and merges all of them at once, rather than one by one
+	Queue *MergeQueue `json:"queue,omitempty"`
+
+	// Freeze is the periods during which the mergeable PRs are not merged, per IntegrationConfig or per base branch
+	Freeze *MergeFreeze `json:"freeze,omitempty"`
+}
+
+// Validate validates the merge config
+func (m *MergeConfig) Validate() error {
+	if m == nil {
+		return nil
+	}
+	for i, q := range m.BranchQueries {
+		if _, err := path.Match(q.Branch, ""); err != nil {
+			return fmt.Errorf("mergeConfig.branchQueries[%d] has an invalid branch pattern: %s", i, err.Error())
+		}
+	}
+	return m.Freeze.Validate()
+}
+
+// GetQuery returns the query for the PRs into the base branch, i.e., the first branch query matching it or the default
+// query
+func (m *MergeConfig) GetQuery(baseRef string) MergeQuery {
+	branch := GitRef(baseRef).GetBranch()
+	for _, q := range m.BranchQueries {
+		if matched, _ := path.Match(q.Branch, branch); matched {
+			return q.Query
+		}
+	}
+	return m.Query
+}
+
+// BranchMergeMethod is a merge method for the PRs into a base branch
+type BranchMergeMethod struct {
+	// Branch is a name of the base branch
+	Branch string `json:"branch"`
+
+	// Method is a merge method
+	// +kubebuilder:validation:Enum=squash;merge;rebase
+	Method git.MergeMethod `json:"method"`
+}
+
+// BranchMergeQuery is conditions for the PRs into base branches to be merged
+type BranchMergeQuery struct {
+	// Branch is a name of the base branch. Wildcards are supported (e.g., release/*)
+	Branch string `json:"branch"`
+
+	// Query is conditions for a open PR into the branch to be merged
+	Query MergeQuery `json:"query"`
+}
+
+// SquashOptions is options for the squash merge
+type SquashOptions struct {
+	// IncludeCommits lists the messages of the squashed commits in the body of the squash commit, if commitTemplate is
+	// not set. GitHub lists them by default, but GitLab uses only the title of the merge request.
+	IncludeCommits bool `json:"includeCommits,omitempty"`
+}
+
+// MergeQueue is a queue of the mergeable PRs, which are tested together in batches (i.e., merge trains)
+type MergeQueue struct {
+	// BatchSize is the maximum number of the PRs tested together. Default is 10
+	// +kubebuilder:validation:Minimum=1
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// MergeSync tunes the git API consumption of the blocker for an IntegrationConfig
+type MergeSync struct {
+	// RetestPeriod is the minimum interval between the retests of a PR. Default is mergeRetestPeriod of the blocker config
+	RetestPeriod *metav1.Duration `json:"retestPeriod,omitempty"`
+
+	// Budget is the maximum number of the PRs in the merge pool whose statuses are synchronized per sync. The others are
+	// synchronized in the next syncs, the least recently synchronized ones first. Default is mergeSyncBudget of the
+	// blocker config
+	// +kubebuilder:validation:Minimum=1
+	Budget int `json:"budget,omitempty"`
 }
 
 // MergeQuery defines conditions for a open PR to be merged
 type MergeQuery struct {
 	// Labels specify the required labels of PR to be merged
+	// Wildcards are supported (e.g., kind/*), which require at least one of the labels matching it
 	Labels []string `json:"labels,omitempty"`
 
 	// BlockLabels specify the required labels of PR to be blocked for merge
+	// Wildcards are supported (e.g., do-not-merge/*), with which any of the labels matching it blocks the merge
 	BlockLabels []string `json:"blockLabels,omitempty"`
 
 	// Authors specify the required authors of PR to be merged
@@ -56,6 +174,12 @@ type MergeQuery struct {
 	// Branches and SkipBranches are mutually exclusive
 	SkipBranches []string `json:"skipBranches,omitempty"`
 
+	// MilestoneRequired requires the PR to have a milestone to be merged
+	MilestoneRequired bool `json:"milestoneRequired,omitempty"`
+
+	// AssigneeRequired requires the PR to have at least one assignee to be merged
+	AssigneeRequired bool `json:"assigneeRequired,omitempty"`
+
 	// Checks are checks needed to be passed for the PR to be merged.
 	// Checks and OptionalChecks are mutually exclusive
 	Checks []string `json:"checks,omitempty"`
@@ -66,4 +190,25 @@ type MergeQuery struct {
 
 	// ApproveRequired specifies whether to check github/gitlab's approval
 	ApproveRequired bool `json:"approveRequired,omitempty"`
+
+	// LGTMRequired requires the 'lgtm' label, set by the lgtm plugin
+	LGTMRequired bool `json:"lgtmRequired,omitempty"`
+
+	// Approvals specifies the number of distinct approvals required for the PR to be merged
+	Approvals *ApprovalsQuery `json:"approvals,omitempty"`
+
+	// CodeOwners requires approvals from the owners of every changed file, listed in the CODEOWNERS file of the base
+	// branch. The approve plugin doesn't set the approved label either, until they approve the PR.
+	CodeOwners bool `json:"codeOwners,omitempty"`
+}
+
+// ApprovalsQuery defines the approvals required for a PR to be merged
+// The approval of the PR's author is never counted
+type ApprovalsQuery struct {
+	// Count is the number of distinct approvals required
+	// +kubebuilder:validation:Minimum=1
+	Count int `json:"count"`
+
+	// WriteAccessOnly counts only the approvals from the users with write access to the repository
+	WriteAccessOnly bool `json:"writeAccessOnly,omitempty"`
 }
@@ -0,0 +1,98 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfig_Validate(t *testing.T) {
+	tc := map[string]struct {
+		config *MergeConfig
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"nil": {},
+		"valid": {
+			config: &MergeConfig{BranchQueries: []BranchMergeQuery{{Branch: "release/*"}, {Branch: "master"}}},
+		},
+		"invalidBranchPattern": {
+			config:       &MergeConfig{BranchQueries: []BranchMergeQuery{{Branch: "release/["}}},
+			errorOccurs:  true,
+			errorMessage: "mergeConfig.branchQueries[0] has an invalid branch pattern: syntax error in pattern",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMergeConfig_GetQuery(t *testing.T) {
+	config := &MergeConfig{
+		Query: MergeQuery{Labels: []string{"lgtm"}},
+		BranchQueries: []BranchMergeQuery{
+			{Branch: "release/*", Query: MergeQuery{Labels: []string{"lgtm", "release-approved"}, Checks: []string{"test-e2e"}}},
+			{Branch: "release/legacy", Query: MergeQuery{Labels: []string{"legacy"}}},
+			{Branch: "hotfix", Query: MergeQuery{Approvals: &ApprovalsQuery{Count: 2}}},
+		},
+	}
+
+	tc := map[string]struct {
+		baseRef string
+
+		expectedQuery MergeQuery
+	}{
+		"default": {
+			baseRef:       "master",
+			expectedQuery: MergeQuery{Labels: []string{"lgtm"}},
+		},
+		"wildcard": {
+			baseRef:       "refs/heads/release/v1.0",
+			expectedQuery: MergeQuery{Labels: []string{"lgtm", "release-approved"}, Checks: []string{"test-e2e"}},
+		},
+		"firstMatch": {
+			baseRef:       "release/legacy",
+			expectedQuery: MergeQuery{Labels: []string{"lgtm", "release-approved"}, Checks: []string{"test-e2e"}},
+		},
+		"exact": {
+			baseRef:       "hotfix",
+			expectedQuery: MergeQuery{Approvals: &ApprovalsQuery{Count: 2}},
+		},
+		"nested": {
+			baseRef:       "release/v1/patch",
+			expectedQuery: MergeQuery{Labels: []string{"lgtm"}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedQuery, config.GetQuery(c.baseRef))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+// DefaultStaleLabel is a default label set to the stale pull requests
+const DefaultStaleLabel = "lifecycle/stale"
+
+// Stale configures the lifecycle of the inactive pull requests. The pull requests inactive for DaysUntilStale days are
+// labeled as stale, and closed if they stay inactive for DaysUntilClose more days
+type Stale struct {
+	// DaysUntilStale is the number of days of inactivity before a pull request is labeled as stale
+	// +kubebuilder:validation:Minimum=1
+	DaysUntilStale int `json:"daysUntilStale"`
+
+	// DaysUntilClose is the number of days of inactivity after a pull request is labeled as stale, before it's closed.
+	// Stale pull requests are never closed if it's 0
+	// +kubebuilder:validation:Minimum=0
+	DaysUntilClose int `json:"daysUntilClose,omitempty"`
+
+	// Label is a label set to the stale pull requests. Default is lifecycle/stale
+	Label string `json:"label,omitempty"`
+
+	// ExemptLabels are the labels which exempt the pull requests from being stale, e.g., lifecycle/frozen
+	ExemptLabels []string `json:"exemptLabels,omitempty"`
+}
+
+// GetLabel returns the stale label, or the default one if it's not set
+func (s *Stale) GetLabel() string {
+	if s.Label == "" {
+		return DefaultStaleLabel
+	}
+	return s.Label
+}
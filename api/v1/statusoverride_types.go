@@ -0,0 +1,74 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StatusOverrideSpec defines the overridden commit status
+type StatusOverrideSpec struct {
+	// IntegrationConfig is a name of the IntegrationConfig whose repository's commit status is overridden
+	IntegrationConfig string `json:"integrationConfig"`
+
+	// PullRequest is an ID of the pull request whose commit status is overridden
+	PullRequest int `json:"pullRequest"`
+
+	// SHA is a commit SHA whose status is overridden
+	SHA string `json:"sha"`
+
+	// Context is a context of the overridden commit status
+	Context string `json:"context"`
+
+	// PreviousState is the state of the commit status before it's overridden
+	PreviousState git.CommitStatusState `json:"previousState,omitempty"`
+
+	// PreviousDescription is the description of the commit status before it's overridden
+	PreviousDescription string `json:"previousDescription,omitempty"`
+
+	// User is a user who overrode the commit status
+	User string `json:"user"`
+}
+
+// +kubebuilder:object:root=true
+
+// StatusOverride is an audit record of a commit status overridden by /override command
+// +kubebuilder:printcolumn:name="Config",type="string",JSONPath=".spec.integrationConfig",description="IntegrationConfig"
+// +kubebuilder:printcolumn:name="PullRequest",type="integer",JSONPath=".spec.pullRequest",description="Pull request ID"
+// +kubebuilder:printcolumn:name="Context",type="string",JSONPath=".spec.context",description="Overridden context"
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.user",description="User who overrode the status"
+// +kubebuilder:printcolumn:name="Created",type="date",JSONPath=".metadata.creationTimestamp",description="Created time"
+type StatusOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StatusOverrideSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// StatusOverrideList contains a list of StatusOverride
+type StatusOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StatusOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StatusOverride{}, &StatusOverrideList{})
+}
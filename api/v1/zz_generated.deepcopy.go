@@ -29,6 +29,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregateCommitStatus) DeepCopyInto(out *AggregateCommitStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregateCommitStatus.
+func (in *AggregateCommitStatus) DeepCopy() *AggregateCommitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregateCommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Approval) DeepCopyInto(out *Approval) {
 	*out = *in
@@ -155,249 +170,207 @@ func (in *ApprovalUser) DeepCopy() *ApprovalUser {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitConfig) DeepCopyInto(out *GitConfig) {
+func (in *ApprovalsQuery) DeepCopyInto(out *ApprovalsQuery) {
 	*out = *in
-	if in.Token != nil {
-		in, out := &in.Token, &out.Token
-		*out = new(GitToken)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
-func (in *GitConfig) DeepCopy() *GitConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalsQuery.
+func (in *ApprovalsQuery) DeepCopy() *ApprovalsQuery {
 	if in == nil {
 		return nil
 	}
-	out := new(GitConfig)
+	out := new(ApprovalsQuery)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitToken) DeepCopyInto(out *GitToken) {
+func (in *ArtifactStorage) DeepCopyInto(out *ArtifactStorage) {
 	*out = *in
-	if in.ValueFrom != nil {
-		in, out := &in.ValueFrom, &out.ValueFrom
-		*out = new(GitTokenFrom)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitToken.
-func (in *GitToken) DeepCopy() *GitToken {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactStorage.
+func (in *ArtifactStorage) DeepCopy() *ArtifactStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(GitToken)
+	out := new(ArtifactStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTokenFrom) DeepCopyInto(out *GitTokenFrom) {
+func (in *Backpressure) DeepCopyInto(out *Backpressure) {
 	*out = *in
-	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTokenFrom.
-func (in *GitTokenFrom) DeepCopy() *GitTokenFrom {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backpressure.
+func (in *Backpressure) DeepCopy() *Backpressure {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTokenFrom)
+	out := new(Backpressure)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationConfig) DeepCopyInto(out *IntegrationConfig) {
+func (in *BranchMergeMethod) DeepCopyInto(out *BranchMergeMethod) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfig.
-func (in *IntegrationConfig) DeepCopy() *IntegrationConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchMergeMethod.
+func (in *BranchMergeMethod) DeepCopy() *BranchMergeMethod {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationConfig)
+	out := new(BranchMergeMethod)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchMergeQuery) DeepCopyInto(out *BranchMergeQuery) {
+	*out = *in
+	in.Query.DeepCopyInto(&out.Query)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchMergeQuery.
+func (in *BranchMergeQuery) DeepCopy() *BranchMergeQuery {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(BranchMergeQuery)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationConfigJobs) DeepCopyInto(out *IntegrationConfigJobs) {
+func (in *BranchProtection) DeepCopyInto(out *BranchProtection) {
 	*out = *in
-	if in.PreSubmit != nil {
-		in, out := &in.PreSubmit, &out.PreSubmit
-		*out = make(Jobs, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.PostSubmit != nil {
-		in, out := &in.PostSubmit, &out.PostSubmit
-		*out = make(Jobs, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Periodic != nil {
-		in, out := &in.Periodic, &out.Periodic
-		*out = make(Periodics, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigJobs.
-func (in *IntegrationConfigJobs) DeepCopy() *IntegrationConfigJobs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtection.
+func (in *BranchProtection) DeepCopy() *BranchProtection {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationConfigJobs)
+	out := new(BranchProtection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationConfigList) DeepCopyInto(out *IntegrationConfigList) {
+func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]IntegrationConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RequiredChecks != nil {
+		in, out := &in.RequiredChecks, &out.RequiredChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigList.
-func (in *IntegrationConfigList) DeepCopy() *IntegrationConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionStatus.
+func (in *BranchProtectionStatus) DeepCopy() *BranchProtectionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationConfigList)
+	out := new(BranchProtectionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationConfigSpec) DeepCopyInto(out *IntegrationConfigSpec) {
+func (in *ChatOpsCommand) DeepCopyInto(out *ChatOpsCommand) {
 	*out = *in
-	in.Git.DeepCopyInto(&out.Git)
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Workspaces != nil {
-		in, out := &in.Workspaces, &out.Workspaces
-		*out = make([]v1beta1.WorkspaceBinding, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Jobs.DeepCopyInto(&out.Jobs)
-	if in.MergeConfig != nil {
-		in, out := &in.MergeConfig, &out.MergeConfig
-		*out = new(MergeConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodTemplate != nil {
-		in, out := &in.PodTemplate, &out.PodTemplate
-		*out = new(pod.Template)
-		(*in).DeepCopyInto(*out)
-	}
-	in.IJManageSpec.DeepCopyInto(&out.IJManageSpec)
-	if in.ParamConfig != nil {
-		in, out := &in.ParamConfig, &out.ParamConfig
-		*out = new(ParameterConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]ChatOpsCommandArg, len(*in))
+		copy(*out, *in)
 	}
-	if in.TLSConfig != nil {
-		in, out := &in.TLSConfig, &out.TLSConfig
-		*out = new(TLSConfig)
-		**out = **in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigSpec.
-func (in *IntegrationConfigSpec) DeepCopy() *IntegrationConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChatOpsCommand.
+func (in *ChatOpsCommand) DeepCopy() *ChatOpsCommand {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationConfigSpec)
+	out := new(ChatOpsCommand)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationConfigStatus) DeepCopyInto(out *IntegrationConfigStatus) {
+func (in *ChatOpsCommandArg) DeepCopyInto(out *ChatOpsCommandArg) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChatOpsCommandArg.
+func (in *ChatOpsCommandArg) DeepCopy() *ChatOpsCommandArg {
+	if in == nil {
+		return nil
+	}
+	out := new(ChatOpsCommandArg)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ChatOpsCommands) DeepCopyInto(out *ChatOpsCommands) {
+	{
+		in := &in
+		*out = make(ChatOpsCommands, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigStatus.
-func (in *IntegrationConfigStatus) DeepCopy() *IntegrationConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChatOpsCommands.
+func (in ChatOpsCommands) DeepCopy() ChatOpsCommands {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationConfigStatus)
+	out := new(ChatOpsCommands)
 	in.DeepCopyInto(out)
-	return out
+	return *out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJob) DeepCopyInto(out *IntegrationJob) {
+func (in *ClusterIntegrationJobTemplate) DeepCopyInto(out *ClusterIntegrationJobTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJob.
-func (in *IntegrationJob) DeepCopy() *IntegrationJob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterIntegrationJobTemplate.
+func (in *ClusterIntegrationJobTemplate) DeepCopy() *ClusterIntegrationJobTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJob)
+	out := new(ClusterIntegrationJobTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationJob) DeepCopyObject() runtime.Object {
+func (in *ClusterIntegrationJobTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -405,46 +378,31 @@ func (in *IntegrationJob) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobConfigRef) DeepCopyInto(out *IntegrationJobConfigRef) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobConfigRef.
-func (in *IntegrationJobConfigRef) DeepCopy() *IntegrationJobConfigRef {
-	if in == nil {
-		return nil
-	}
-	out := new(IntegrationJobConfigRef)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobList) DeepCopyInto(out *IntegrationJobList) {
+func (in *ClusterIntegrationJobTemplateList) DeepCopyInto(out *ClusterIntegrationJobTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IntegrationJob, len(*in))
+		*out = make([]ClusterIntegrationJobTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobList.
-func (in *IntegrationJobList) DeepCopy() *IntegrationJobList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterIntegrationJobTemplateList.
+func (in *ClusterIntegrationJobTemplateList) DeepCopy() *ClusterIntegrationJobTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobList)
+	out := new(ClusterIntegrationJobTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationJobList) DeepCopyObject() runtime.Object {
+func (in *ClusterIntegrationJobTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -452,91 +410,613 @@ func (in *IntegrationJobList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobManageSpec) DeepCopyInto(out *IntegrationJobManageSpec) {
+func (in *Concurrency) DeepCopyInto(out *Concurrency) {
 	*out = *in
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(metav1.Duration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobManageSpec.
-func (in *IntegrationJobManageSpec) DeepCopy() *IntegrationJobManageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Concurrency.
+func (in *Concurrency) DeepCopy() *Concurrency {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobManageSpec)
+	out := new(Concurrency)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobRefs) DeepCopyInto(out *IntegrationJobRefs) {
+func (in *ExecutionWindow) DeepCopyInto(out *ExecutionWindow) {
 	*out = *in
-	if in.Sender != nil {
-		in, out := &in.Sender, &out.Sender
-		*out = new(IntegrationJobSender)
-		**out = **in
-	}
-	out.Base = in.Base
-	if in.Pulls != nil {
-		in, out := &in.Pulls, &out.Pulls
-		*out = make([]IntegrationJobRefsPull, len(*in))
-		copy(*out, *in)
-	}
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefs.
-func (in *IntegrationJobRefs) DeepCopy() *IntegrationJobRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionWindow.
+func (in *ExecutionWindow) DeepCopy() *ExecutionWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobRefs)
+	out := new(ExecutionWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobRefsBase) DeepCopyInto(out *IntegrationJobRefsBase) {
-	*out = *in
+func (in ExecutionWindows) DeepCopyInto(out *ExecutionWindows) {
+	{
+		in := &in
+		*out = make(ExecutionWindows, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsBase.
-func (in *IntegrationJobRefsBase) DeepCopy() *IntegrationJobRefsBase {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionWindows.
+func (in ExecutionWindows) DeepCopy() ExecutionWindows {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobRefsBase)
+	out := new(ExecutionWindows)
 	in.DeepCopyInto(out)
-	return out
+	return *out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobRefsPull) DeepCopyInto(out *IntegrationJobRefsPull) {
+func (in *FreezePeriod) DeepCopyInto(out *FreezePeriod) {
 	*out = *in
-	out.Author = in.Author
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(ExecutionWindow)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsPull.
-func (in *IntegrationJobRefsPull) DeepCopy() *IntegrationJobRefsPull {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezePeriod.
+func (in *FreezePeriod) DeepCopy() *FreezePeriod {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobRefsPull)
+	out := new(FreezePeriod)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationJobRefsPullAuthor) DeepCopyInto(out *IntegrationJobRefsPullAuthor) {
+func (in *GitConfig) DeepCopyInto(out *GitConfig) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsPullAuthor.
-func (in *IntegrationJobRefsPullAuthor) DeepCopy() *IntegrationJobRefsPullAuthor {
-	if in == nil {
-		return nil
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(GitToken)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
+func (in *GitConfig) DeepCopy() *GitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitToken) DeepCopyInto(out *GitToken) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(GitTokenFrom)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitToken.
+func (in *GitToken) DeepCopy() *GitToken {
+	if in == nil {
+		return nil
+	}
+	out := new(GitToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTokenFrom) DeepCopyInto(out *GitTokenFrom) {
+	*out = *in
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTokenFrom.
+func (in *GitTokenFrom) DeepCopy() *GitTokenFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTokenFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationConfig) DeepCopyInto(out *IntegrationConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfig.
+func (in *IntegrationConfig) DeepCopy() *IntegrationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationConfigJobs) DeepCopyInto(out *IntegrationConfigJobs) {
+	*out = *in
+	if in.PreSubmit != nil {
+		in, out := &in.PreSubmit, &out.PreSubmit
+		*out = make(Jobs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostSubmit != nil {
+		in, out := &in.PostSubmit, &out.PostSubmit
+		*out = make(Jobs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Periodic != nil {
+		in, out := &in.Periodic, &out.Periodic
+		*out = make(Periodics, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SkipDirectives != nil {
+		in, out := &in.SkipDirectives, &out.SkipDirectives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigFile != nil {
+		in, out := &in.ConfigFile, &out.ConfigFile
+		*out = new(JobsConfigFile)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigJobs.
+func (in *IntegrationConfigJobs) DeepCopy() *IntegrationConfigJobs {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationConfigJobs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationConfigList) DeepCopyInto(out *IntegrationConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IntegrationConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigList.
+func (in *IntegrationConfigList) DeepCopy() *IntegrationConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationConfigSpec) DeepCopyInto(out *IntegrationConfigSpec) {
+	*out = *in
+	in.Git.DeepCopyInto(&out.Git)
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]v1beta1.WorkspaceBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Jobs.DeepCopyInto(&out.Jobs)
+	if in.MergeConfig != nil {
+		in, out := &in.MergeConfig, &out.MergeConfig
+		*out = new(MergeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(pod.Template)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checkout != nil {
+		in, out := &in.Checkout, &out.Checkout
+		*out = new(JobCheckout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(Concurrency)
+		**out = **in
+	}
+	if in.ExecutionWindows != nil {
+		in, out := &in.ExecutionWindows, &out.ExecutionWindows
+		*out = make(ExecutionWindows, len(*in))
+		copy(*out, *in)
+	}
+	if in.BranchProtection != nil {
+		in, out := &in.BranchProtection, &out.BranchProtection
+		*out = new(BranchProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AggregateCommitStatus != nil {
+		in, out := &in.AggregateCommitStatus, &out.AggregateCommitStatus
+		*out = new(AggregateCommitStatus)
+		**out = **in
+	}
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(RemoteCluster)
+		**out = **in
+	}
+	in.IJManageSpec.DeepCopyInto(&out.IJManageSpec)
+	if in.ParamConfig != nil {
+		in, out := &in.ParamConfig, &out.ParamConfig
+		*out = new(ParameterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make(ChatOpsCommands, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Stale != nil {
+		in, out := &in.Stale, &out.Stale
+		*out = new(Stale)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigSpec.
+func (in *IntegrationConfigSpec) DeepCopy() *IntegrationConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationConfigStatus) DeepCopyInto(out *IntegrationConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Periodics != nil {
+		in, out := &in.Periodics, &out.Periodics
+		*out = make([]PeriodicStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WebhookRepositories != nil {
+		in, out := &in.WebhookRepositories, &out.WebhookRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BranchProtections != nil {
+		in, out := &in.BranchProtections, &out.BranchProtections
+		*out = make([]BranchProtectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationConfigStatus.
+func (in *IntegrationConfigStatus) DeepCopy() *IntegrationConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJob) DeepCopyInto(out *IntegrationJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJob.
+func (in *IntegrationJob) DeepCopy() *IntegrationJob {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobConfigRef) DeepCopyInto(out *IntegrationJobConfigRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobConfigRef.
+func (in *IntegrationJobConfigRef) DeepCopy() *IntegrationJobConfigRef {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobConfigRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobList) DeepCopyInto(out *IntegrationJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IntegrationJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobList.
+func (in *IntegrationJobList) DeepCopy() *IntegrationJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobManageSpec) DeepCopyInto(out *IntegrationJobManageSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SuccessfulJobsHistoryLimit != nil {
+		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PriorityRules != nil {
+		in, out := &in.PriorityRules, &out.PriorityRules
+		*out = make([]PriorityRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backpressure != nil {
+		in, out := &in.Backpressure, &out.Backpressure
+		*out = new(Backpressure)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobManageSpec.
+func (in *IntegrationJobManageSpec) DeepCopy() *IntegrationJobManageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobManageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobQueueStatus) DeepCopyInto(out *IntegrationJobQueueStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobQueueStatus.
+func (in *IntegrationJobQueueStatus) DeepCopy() *IntegrationJobQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobRefs) DeepCopyInto(out *IntegrationJobRefs) {
+	*out = *in
+	if in.Sender != nil {
+		in, out := &in.Sender, &out.Sender
+		*out = new(IntegrationJobSender)
+		**out = **in
+	}
+	out.Base = in.Base
+	if in.Pulls != nil {
+		in, out := &in.Pulls, &out.Pulls
+		*out = make([]IntegrationJobRefsPull, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefs.
+func (in *IntegrationJobRefs) DeepCopy() *IntegrationJobRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobRefsBase) DeepCopyInto(out *IntegrationJobRefsBase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsBase.
+func (in *IntegrationJobRefsBase) DeepCopy() *IntegrationJobRefsBase {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobRefsBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobRefsPull) DeepCopyInto(out *IntegrationJobRefsPull) {
+	*out = *in
+	out.Author = in.Author
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsPull.
+func (in *IntegrationJobRefsPull) DeepCopy() *IntegrationJobRefsPull {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobRefsPull)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobRefsPullAuthor) DeepCopyInto(out *IntegrationJobRefsPullAuthor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobRefsPullAuthor.
+func (in *IntegrationJobRefsPullAuthor) DeepCopy() *IntegrationJobRefsPullAuthor {
+	if in == nil {
+		return nil
 	}
 	out := new(IntegrationJobRefsPullAuthor)
 	in.DeepCopyInto(out)
@@ -582,11 +1062,45 @@ func (in *IntegrationJobSpec) DeepCopyInto(out *IntegrationJobSpec) {
 		*out = new(pod.Template)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checkout != nil {
+		in, out := &in.Checkout, &out.Checkout
+		*out = new(JobCheckout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(RemoteCluster)
+		**out = **in
+	}
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ParamConfig != nil {
 		in, out := &in.ParamConfig, &out.ParamConfig
 		*out = new(ParameterConfig)
@@ -622,14 +1136,100 @@ func (in *IntegrationJobStatus) DeepCopyInto(out *IntegrationJobStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = new(IntegrationJobQueueStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobStatus.
+func (in *IntegrationJobStatus) DeepCopy() *IntegrationJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobTemplate) DeepCopyInto(out *IntegrationJobTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobTemplate.
+func (in *IntegrationJobTemplate) DeepCopy() *IntegrationJobTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationJobTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobTemplateList) DeepCopyInto(out *IntegrationJobTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IntegrationJobTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobTemplateList.
+func (in *IntegrationJobTemplateList) DeepCopy() *IntegrationJobTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationJobTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationJobTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationJobTemplateSpec) DeepCopyInto(out *IntegrationJobTemplateSpec) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]ParameterDefine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Job.DeepCopyInto(&out.Job)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobStatus.
-func (in *IntegrationJobStatus) DeepCopy() *IntegrationJobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationJobTemplateSpec.
+func (in *IntegrationJobTemplateSpec) DeepCopy() *IntegrationJobTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationJobStatus)
+	out := new(IntegrationJobTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -638,6 +1238,11 @@ func (in *IntegrationJobStatus) DeepCopy() *IntegrationJobStatus {
 func (in *Job) DeepCopyInto(out *Job) {
 	*out = *in
 	in.Container.DeepCopyInto(&out.Container)
+	if in.Checkout != nil {
+		in, out := &in.Checkout, &out.Checkout
+		*out = new(JobCheckout)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.When != nil {
 		in, out := &in.When, &out.When
 		*out = new(JobWhen)
@@ -648,11 +1253,87 @@ func (in *Job) DeepCopyInto(out *Job) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]JobWorkspace, len(*in))
+		copy(*out, *in)
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(JobArtifacts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TestReports != nil {
+		in, out := &in.TestReports, &out.TestReports
+		*out = new(JobTestReports)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Coverage != nil {
+		in, out := &in.Coverage, &out.Coverage
+		*out = new(JobCoverage)
+		**out = **in
+	}
+	if in.Lint != nil {
+		in, out := &in.Lint, &out.Lint
+		*out = new(JobLint)
+		**out = **in
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(JobCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make([]MatrixParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(JobTemplateRef)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TektonTask != nil {
 		in, out := &in.TektonTask, &out.TektonTask
 		*out = new(TektonTask)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PipelineRef != nil {
+		in, out := &in.PipelineRef, &out.PipelineRef
+		*out = new(JobPipelineRef)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Approval != nil {
 		in, out := &in.Approval, &out.Approval
 		*out = new(JobApproval)
@@ -713,6 +1394,179 @@ func (in *JobApproval) DeepCopy() *JobApproval {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobArtifacts) DeepCopyInto(out *JobArtifacts) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Storage = in.Storage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobArtifacts.
+func (in *JobArtifacts) DeepCopy() *JobArtifacts {
+	if in == nil {
+		return nil
+	}
+	out := new(JobArtifacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCache) DeepCopyInto(out *JobCache) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCache.
+func (in *JobCache) DeepCopy() *JobCache {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCheckout) DeepCopyInto(out *JobCheckout) {
+	*out = *in
+	if in.Submodules != nil {
+		in, out := &in.Submodules, &out.Submodules
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FetchDepth != nil {
+		in, out := &in.FetchDepth, &out.FetchDepth
+		*out = new(int)
+		**out = **in
+	}
+	if in.LFS != nil {
+		in, out := &in.LFS, &out.LFS
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCheckout.
+func (in *JobCheckout) DeepCopy() *JobCheckout {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCheckout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCoverage) DeepCopyInto(out *JobCoverage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCoverage.
+func (in *JobCoverage) DeepCopy() *JobCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCoverageReport) DeepCopyInto(out *JobCoverageReport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCoverageReport.
+func (in *JobCoverageReport) DeepCopy() *JobCoverageReport {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCoverageReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobLint) DeepCopyInto(out *JobLint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobLint.
+func (in *JobLint) DeepCopy() *JobLint {
+	if in == nil {
+		return nil
+	}
+	out := new(JobLint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobLintIssue) DeepCopyInto(out *JobLintIssue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobLintIssue.
+func (in *JobLintIssue) DeepCopy() *JobLintIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(JobLintIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobLintReport) DeepCopyInto(out *JobLintReport) {
+	*out = *in
+	if in.Issues != nil {
+		in, out := &in.Issues, &out.Issues
+		*out = make([]JobLintIssue, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobLintReport.
+func (in *JobLintReport) DeepCopy() *JobLintReport {
+	if in == nil {
+		return nil
+	}
+	out := new(JobLintReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobPipelineRef) DeepCopyInto(out *JobPipelineRef) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]ParameterValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobPipelineRef.
+func (in *JobPipelineRef) DeepCopy() *JobPipelineRef {
+	if in == nil {
+		return nil
+	}
+	out := new(JobPipelineRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobStatus) DeepCopyInto(out *JobStatus) {
 	*out = *in
@@ -731,34 +1585,163 @@ func (in *JobStatus) DeepCopyInto(out *JobStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TestReport != nil {
+		in, out := &in.TestReport, &out.TestReport
+		*out = new(JobTestReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Coverage != nil {
+		in, out := &in.Coverage, &out.Coverage
+		*out = new(JobCoverageReport)
+		**out = **in
+	}
+	if in.Lint != nil {
+		in, out := &in.Lint, &out.Lint
+		*out = new(JobLintReport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
+func (in *JobStatus) DeepCopy() *JobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTaskBundle) DeepCopyInto(out *JobTaskBundle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTaskBundle.
+func (in *JobTaskBundle) DeepCopy() *JobTaskBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTaskBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTaskRef) DeepCopyInto(out *JobTaskRef) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(v1beta1.TaskRef)
+		**out = **in
+	}
+	if in.Bundle != nil {
+		in, out := &in.Bundle, &out.Bundle
+		*out = new(JobTaskBundle)
+		**out = **in
+	}
+	if in.Resolver != nil {
+		in, out := &in.Resolver, &out.Resolver
+		*out = new(JobTaskResolver)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTaskRef.
+func (in *JobTaskRef) DeepCopy() *JobTaskRef {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTaskRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTaskResolver) DeepCopyInto(out *JobTaskResolver) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]ParameterValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTaskResolver.
+func (in *JobTaskResolver) DeepCopy() *JobTaskResolver {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTaskResolver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTemplateRef) DeepCopyInto(out *JobTemplateRef) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]ParameterValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateRef.
+func (in *JobTemplateRef) DeepCopy() *JobTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTemplateRef)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
-func (in *JobStatus) DeepCopy() *JobStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTestReport) DeepCopyInto(out *JobTestReport) {
+	*out = *in
+	if in.FailedTests != nil {
+		in, out := &in.FailedTests, &out.FailedTests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTestReport.
+func (in *JobTestReport) DeepCopy() *JobTestReport {
 	if in == nil {
 		return nil
 	}
-	out := new(JobStatus)
+	out := new(JobTestReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobTaskRef) DeepCopyInto(out *JobTaskRef) {
+func (in *JobTestReports) DeepCopyInto(out *JobTestReports) {
 	*out = *in
-	if in.Local != nil {
-		in, out := &in.Local, &out.Local
-		*out = new(v1beta1.TaskRef)
-		**out = **in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTaskRef.
-func (in *JobTaskRef) DeepCopy() *JobTaskRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTestReports.
+func (in *JobTestReports) DeepCopy() *JobTestReports {
 	if in == nil {
 		return nil
 	}
-	out := new(JobTaskRef)
+	out := new(JobTestReports)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -786,6 +1769,21 @@ func (in *JobWhen) DeepCopyInto(out *JobWhen) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Release != nil {
+		in, out := &in.Release, &out.Release
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipPaths != nil {
+		in, out := &in.SkipPaths, &out.SkipPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobWhen.
@@ -798,6 +1796,21 @@ func (in *JobWhen) DeepCopy() *JobWhen {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobWorkspace) DeepCopyInto(out *JobWorkspace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobWorkspace.
+func (in *JobWorkspace) DeepCopy() *JobWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(JobWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Jobs) DeepCopyInto(out *Jobs) {
 	{
@@ -819,10 +1832,92 @@ func (in Jobs) DeepCopy() Jobs {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobsConfigFile) DeepCopyInto(out *JobsConfigFile) {
+	*out = *in
+	if in.AllowedServiceAccounts != nil {
+		in, out := &in.AllowedServiceAccounts, &out.AllowedServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobsConfigFile.
+func (in *JobsConfigFile) DeepCopy() *JobsConfigFile {
+	if in == nil {
+		return nil
+	}
+	out := new(JobsConfigFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixParam) DeepCopyInto(out *MatrixParam) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixParam.
+func (in *MatrixParam) DeepCopy() *MatrixParam {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MergeConfig) DeepCopyInto(out *MergeConfig) {
 	*out = *in
+	if in.BranchMethods != nil {
+		in, out := &in.BranchMethods, &out.BranchMethods
+		*out = make([]BranchMergeMethod, len(*in))
+		copy(*out, *in)
+	}
+	if in.Squash != nil {
+		in, out := &in.Squash, &out.Squash
+		*out = new(SquashOptions)
+		**out = **in
+	}
 	in.Query.DeepCopyInto(&out.Query)
+	if in.BranchQueries != nil {
+		in, out := &in.BranchQueries, &out.BranchQueries
+		*out = make([]BranchMergeQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaleAfter != nil {
+		in, out := &in.StaleAfter, &out.StaleAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PriorityLabels != nil {
+		in, out := &in.PriorityLabels, &out.PriorityLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(MergeSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = new(MergeQueue)
+		**out = **in
+	}
+	if in.Freeze != nil {
+		in, out := &in.Freeze, &out.Freeze
+		*out = new(MergeFreeze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeConfig.
@@ -835,6 +1930,28 @@ func (in *MergeConfig) DeepCopy() *MergeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeFreeze) DeepCopyInto(out *MergeFreeze) {
+	*out = *in
+	if in.Periods != nil {
+		in, out := &in.Periods, &out.Periods
+		*out = make([]FreezePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeFreeze.
+func (in *MergeFreeze) DeepCopy() *MergeFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MergeQuery) DeepCopyInto(out *MergeQuery) {
 	*out = *in
@@ -878,6 +1995,11 @@ func (in *MergeQuery) DeepCopyInto(out *MergeQuery) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Approvals != nil {
+		in, out := &in.Approvals, &out.Approvals
+		*out = new(ApprovalsQuery)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeQuery.
@@ -890,6 +2012,41 @@ func (in *MergeQuery) DeepCopy() *MergeQuery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeQueue) DeepCopyInto(out *MergeQueue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeQueue.
+func (in *MergeQueue) DeepCopy() *MergeQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeSync) DeepCopyInto(out *MergeSync) {
+	*out = *in
+	if in.RetestPeriod != nil {
+		in, out := &in.RetestPeriod, &out.RetestPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeSync.
+func (in *MergeSync) DeepCopy() *MergeSync {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotiEmail) DeepCopyInto(out *NotiEmail) {
 	*out = *in
@@ -1012,6 +2169,11 @@ func (in *ParameterDefine) DeepCopyInto(out *ParameterDefine) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterDefine.
@@ -1060,6 +2222,25 @@ func (in *Periodic) DeepCopy() *Periodic {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeriodicStatus) DeepCopyInto(out *PeriodicStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeriodicStatus.
+func (in *PeriodicStatus) DeepCopy() *PeriodicStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeriodicStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Periodics) DeepCopyInto(out *Periodics) {
 	{
@@ -1081,6 +2262,144 @@ func (in Periodics) DeepCopy() Periodics {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityRule) DeepCopyInto(out *PriorityRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityRule.
+func (in *PriorityRule) DeepCopy() *PriorityRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SquashOptions) DeepCopyInto(out *SquashOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SquashOptions.
+func (in *SquashOptions) DeepCopy() *SquashOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SquashOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Stale) DeepCopyInto(out *Stale) {
+	*out = *in
+	if in.ExemptLabels != nil {
+		in, out := &in.ExemptLabels, &out.ExemptLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stale.
+func (in *Stale) DeepCopy() *Stale {
+	if in == nil {
+		return nil
+	}
+	out := new(Stale)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusOverride) DeepCopyInto(out *StatusOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusOverride.
+func (in *StatusOverride) DeepCopy() *StatusOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StatusOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusOverrideList) DeepCopyInto(out *StatusOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StatusOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusOverrideList.
+func (in *StatusOverrideList) DeepCopy() *StatusOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StatusOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusOverrideSpec) DeepCopyInto(out *StatusOverrideSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusOverrideSpec.
+func (in *StatusOverrideSpec) DeepCopy() *StatusOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
 	*out = *in
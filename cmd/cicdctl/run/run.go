@@ -19,6 +19,7 @@ package run
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
@@ -36,8 +37,12 @@ type command struct {
 	Config *cli.Configs
 
 	branch     string
+	sha        string
 	headBranch string
+	headSha    string
 	baseBranch string
+	params     []string
+	failedOnly bool
 }
 
 // New is a constructor of a run sub-command
@@ -56,6 +61,9 @@ func New(c *cli.Configs) cli.Command {
 	}
 	preCommand.Flags().StringVar(&cmd.baseBranch, "base-branch", "", "Base branch for the PullRequest event")
 	preCommand.Flags().StringVar(&cmd.headBranch, "head-branch", "", "Head branch for the PullRequest event")
+	preCommand.Flags().StringVar(&cmd.headSha, "head-sha", "", "Head commit SHA for the PullRequest event")
+	preCommand.Flags().StringArrayVar(&cmd.params, "param", nil, "Parameter value to be overridden, in form of <name>=<value>")
+	preCommand.Flags().BoolVar(&cmd.failedOnly, "failed-only", false, "Runs only the jobs failed for the head commit")
 	cmd.Command.AddCommand(preCommand)
 
 	postCommand := &cobra.Command{
@@ -65,6 +73,8 @@ func New(c *cli.Configs) cli.Command {
 		RunE:  cmd.runPost,
 	}
 	postCommand.Flags().StringVar(&cmd.branch, "branch", "", "Branch for the Push event")
+	postCommand.Flags().StringVar(&cmd.sha, "sha", "", "Commit SHA for the Push event")
+	postCommand.Flags().StringArrayVar(&cmd.params, "param", nil, "Parameter value to be overridden, in form of <name>=<value>")
 	cmd.Command.AddCommand(postCommand)
 
 	return cmd
@@ -74,9 +84,15 @@ func (command *command) runPre(_ *cobra.Command, args []string) error {
 	if command.branch != "" {
 		return fmt.Errorf("branch option cannot be used for pre")
 	}
+	if command.sha != "" {
+		return fmt.Errorf("sha option cannot be used for pre")
+	}
 	if command.headBranch == "" {
 		return fmt.Errorf("head-branch option should be set for pre")
 	}
+	if command.failedOnly && command.headSha == "" {
+		return fmt.Errorf("head-sha option should be set for failed-only")
+	}
 	return command.RunCommand(args, subTypePre)
 }
 
@@ -84,6 +100,9 @@ func (command *command) runPost(_ *cobra.Command, args []string) error {
 	if command.headBranch != "" || command.baseBranch != "" {
 		return fmt.Errorf("head-branch and base-branch options cannot be used for post")
 	}
+	if command.headSha != "" {
+		return fmt.Errorf("head-sha option cannot be used for post")
+	}
 	return command.RunCommand(args, subTypePost)
 }
 
@@ -97,17 +116,27 @@ func (command *command) RunCommand(args []string, subType string) error {
 	var subResource string
 	var obj interface{}
 
+	params, err := parseParams(command.params)
+	if err != nil {
+		return err
+	}
+
 	switch subType {
 	case subTypePre:
 		subResource = cicdv1.IntegrationConfigAPIRunPre
 		obj = cicdv1.IntegrationConfigAPIReqRunPreBody{
 			BaseBranch: command.baseBranch,
 			HeadBranch: command.headBranch,
+			HeadSha:    command.headSha,
+			Params:     params,
+			FailedOnly: command.failedOnly,
 		}
 	case subTypePost:
 		subResource = cicdv1.IntegrationConfigAPIRunPost
 		obj = cicdv1.IntegrationConfigAPIReqRunPostBody{
 			Branch: command.branch,
+			Sha:    command.sha,
+			Params: params,
 		}
 	}
 
@@ -132,3 +161,16 @@ func (command *command) RunCommand(args []string, subType string) error {
 		return nil
 	})
 }
+
+// parseParams parses <name>=<value> formed parameters
+func parseParams(params []string) ([]cicdv1.ParameterValue, error) {
+	var values []cicdv1.ParameterValue
+	for _, p := range params {
+		tokens := strings.SplitN(p, "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" {
+			return nil, fmt.Errorf("param %s is malformed, it should be in form of <name>=<value>", p)
+		}
+		values = append(values, cicdv1.ParameterValue{Name: tokens[0], StringVal: tokens[1]})
+	}
+	return values, nil
+}
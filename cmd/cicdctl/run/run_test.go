@@ -24,6 +24,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/cli"
 )
 
@@ -72,6 +73,19 @@ func Test_command_runPre(t *testing.T) {
 			errorOccurs:  true,
 			errorMessage: "branch option cannot be used for pre",
 		},
+		"haveSha": {
+			cmd: &command{
+				Config: &cli.Configs{
+					APIServer: srv.URL,
+					Namespace: "default",
+					Insecure:  true,
+				},
+				headBranch: "feat/test",
+				sha:        "ed1d7e2d3f1a",
+			},
+			errorOccurs:  true,
+			errorMessage: "sha option cannot be used for pre",
+		},
 		"noHeadBranch": {
 			cmd: &command{
 				Config: &cli.Configs{
@@ -83,6 +97,19 @@ func Test_command_runPre(t *testing.T) {
 			errorOccurs:  true,
 			errorMessage: "head-branch option should be set for pre",
 		},
+		"failedOnlyNoHeadSha": {
+			cmd: &command{
+				Config: &cli.Configs{
+					APIServer: srv.URL,
+					Namespace: "default",
+					Insecure:  true,
+				},
+				headBranch: "feat/test",
+				failedOnly: true,
+			},
+			errorOccurs:  true,
+			errorMessage: "head-sha option should be set for failed-only",
+		},
 	}
 
 	for name, c := range tc {
@@ -130,6 +157,18 @@ func Test_command_runPost(t *testing.T) {
 			errorOccurs:  true,
 			errorMessage: "head-branch and base-branch options cannot be used for post",
 		},
+		"haveHeadSha": {
+			cmd: &command{
+				Config: &cli.Configs{
+					APIServer: srv.URL,
+					Namespace: "default",
+					Insecure:  true,
+				},
+				headSha: "ed1d7e2d3f1a",
+			},
+			errorOccurs:  true,
+			errorMessage: "head-sha option cannot be used for post",
+		},
 	}
 
 	for name, c := range tc {
@@ -223,3 +262,36 @@ func Test_command_RunCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseParams(t *testing.T) {
+	tc := map[string]struct {
+		params []string
+
+		errorOccurs    bool
+		errorMessage   string
+		expectedParams []cicdv1.ParameterValue
+	}{
+		"normal": {
+			params:         []string{"a=b", "c=d=e", "f="},
+			expectedParams: []cicdv1.ParameterValue{{Name: "a", StringVal: "b"}, {Name: "c", StringVal: "d=e"}, {Name: "f", StringVal: ""}},
+		},
+		"malformed": {
+			params:       []string{"a"},
+			errorOccurs:  true,
+			errorMessage: "param a is malformed, it should be in form of <name>=<value>",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			params, err := parseParams(c.params)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expectedParams, params)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/logrotate"
 	"github.com/tmax-cloud/cicd-operator/pkg/collector"
 	"github.com/tmax-cloud/cicd-operator/pkg/notification/mail"
+	"github.com/tmax-cloud/cicd-operator/pkg/plugins/stale"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -138,17 +139,21 @@ func main() {
 	}
 	go gc.Start()
 
+	// Start stale plugin
+	go stale.New(mgr.GetClient()).Start()
+
 	// Controllers
 	if err = (&controllers.IntegrationConfigReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("IntegrationConfig"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("IntegrationConfig"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("integrationconfig-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IntegrationConfig")
 		os.Exit(1)
 	}
 
-	if err = controllers.NewIntegrationJobReconciler(mgr.GetClient(), mgr.GetScheme(), ctrl.Log.WithName("controllers").WithName("IntegrationJob")).SetupWithManager(mgr); err != nil {
+	if err = controllers.NewIntegrationJobReconciler(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), mgr.GetEventRecorderFor("integrationjob-controller"), ctrl.Log.WithName("controllers").WithName("IntegrationJob")).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IntegrationJob")
 		os.Exit(1)
 	}
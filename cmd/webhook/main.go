@@ -9,12 +9,25 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"github.com/tmax-cloud/cicd-operator/internal/logrotate"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/approval"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/approve"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/assign"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/cc"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/cherrypick"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/help"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/hold"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/label"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/lgtm"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/lifecycle"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/merge"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/milestone"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/override"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/trigger"
 	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"github.com/tmax-cloud/cicd-operator/pkg/plugins/size"
+	"github.com/tmax-cloud/cicd-operator/pkg/plugins/welcome"
+	"github.com/tmax-cloud/cicd-operator/pkg/plugins/wip"
 	"github.com/tmax-cloud/cicd-operator/pkg/server"
 	"io"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -89,6 +102,8 @@ func main() {
 	}
 	go cfgCtrl.Start()
 	cfgCtrl.Add(configs.ConfigMapNameCICDConfig, configs.ApplyControllerConfigChange)
+	cfgCtrl.Add(configs.ConfigMapNamePluginConfig, configs.ApplyPluginConfigChange)
+	cfgCtrl.Add(configs.ConfigMapNameBlockerConfig, configs.ApplyBlockerConfigChange)
 	// Wait for initial config reconcile
 	<-configs.ControllerInitCh
 
@@ -103,20 +118,53 @@ func main() {
 	approveHandler := &approve.Handler{Client: mgr.GetClient()}
 	triggerHandler := &trigger.Handler{Client: mgr.GetClient()}
 	holdHandler := &hold.Handler{Client: mgr.GetClient()}
+	lgtmHandler := &lgtm.Handler{Client: mgr.GetClient()}
+	assignHandler := &assign.Handler{Client: mgr.GetClient()}
+	ccHandler := &cc.Handler{Client: mgr.GetClient()}
+	labelHandler := &label.Handler{Client: mgr.GetClient()}
+	milestoneHandler := &milestone.Handler{Client: mgr.GetClient()}
+	cherryPickHandler := &cherrypick.Handler{Client: mgr.GetClient()}
+	mergeHandler := &merge.Handler{Client: mgr.GetClient()}
+	overrideHandler := &override.Handler{Client: mgr.GetClient()}
+	lifecycleHandler := &lifecycle.Handler{Client: mgr.GetClient()}
+	approvalHandler := &approval.Handler{Client: mgr.GetClient()}
+	helpHandler := &help.Handler{Client: mgr.GetClient(), Lister: co}
 
-	co.RegisterCommandHandler(approve.CommandTypeApprove, approveHandler.HandleChatOps)
-	co.RegisterCommandHandler(approve.CommandTypeGitLabApprove, approveHandler.HandleChatOps)
-	co.RegisterCommandHandler(trigger.CommandTypeTest, triggerHandler.HandleChatOps)
-	co.RegisterCommandHandler(trigger.CommandTypeRetest, triggerHandler.HandleChatOps)
-	co.RegisterCommandHandler(hold.CommandTypeHold, holdHandler.HandleChatOps)
+	co.RegisterCommandHandler(approve.CommandTypeApprove, approveHandler.HandleChatOps, approve.HelpApprove...)
+	co.RegisterCommandHandler(approve.CommandTypeGitLabApprove, approveHandler.HandleChatOps, approve.HelpGitLabApprove...)
+	co.RegisterCommandHandler(trigger.CommandTypeTest, triggerHandler.HandleChatOps, trigger.HelpTest...)
+	co.RegisterCommandHandler(trigger.CommandTypeRetest, triggerHandler.HandleChatOps, trigger.HelpRetest...)
+	co.RegisterCommandHandler(hold.CommandTypeHold, holdHandler.HandleChatOps, hold.HelpHold...)
+	co.RegisterCommandHandler(hold.CommandTypeUnhold, holdHandler.HandleChatOps, hold.HelpUnhold...)
+	co.RegisterCommandHandler(lgtm.CommandTypeLGTM, lgtmHandler.HandleChatOps, lgtm.HelpLGTM...)
+	co.RegisterCommandHandler(assign.CommandTypeAssign, assignHandler.HandleChatOps, assign.HelpAssign...)
+	co.RegisterCommandHandler(assign.CommandTypeUnassign, assignHandler.HandleChatOps, assign.HelpUnassign...)
+	co.RegisterCommandHandler(cc.CommandTypeCC, ccHandler.HandleChatOps, cc.HelpCC...)
+	co.RegisterCommandHandler(cc.CommandTypeUnCC, ccHandler.HandleChatOps, cc.HelpUnCC...)
+	co.RegisterCommandHandler(label.CommandTypeLabel, labelHandler.HandleChatOps, label.HelpLabel...)
+	co.RegisterCommandHandler(label.CommandTypeRemoveLabel, labelHandler.HandleChatOps, label.HelpRemoveLabel...)
+	co.RegisterCommandHandler(milestone.CommandTypeMilestone, milestoneHandler.HandleChatOps, milestone.HelpMilestone...)
+	co.RegisterCommandHandler(cherrypick.CommandTypeCherryPick, cherryPickHandler.HandleChatOps, cherrypick.HelpCherryPick...)
+	co.RegisterCommandHandler(merge.CommandTypeMerge, mergeHandler.HandleChatOps, merge.HelpMerge...)
+	co.RegisterCommandHandler(override.CommandTypeOverride, overrideHandler.HandleChatOps, override.HelpOverride...)
+	co.RegisterCommandHandler(lifecycle.CommandTypeClose, lifecycleHandler.HandleChatOps, lifecycle.HelpClose...)
+	co.RegisterCommandHandler(lifecycle.CommandTypeReopen, lifecycleHandler.HandleChatOps, lifecycle.HelpReopen...)
+	co.RegisterCommandHandler(approval.CommandTypeApproveJob, approvalHandler.HandleChatOps, approval.HelpApproveJob...)
+	co.RegisterCommandHandler(approval.CommandTypeRejectJob, approvalHandler.HandleChatOps, approval.HelpRejectJob...)
+	co.RegisterCommandHandler(help.CommandTypeHelp, helpHandler.HandleChatOps, help.HelpHelp...)
+	co.RegisterCustomCommandHandler(triggerHandler.HandleCustomCommand)
 
 	// Create and start webhook server
 	srv := server.New(mgr.GetClient(), mgr.GetConfig())
 	// Add plugins for webhook
-	server.AddPlugin([]git.EventType{git.EventTypePullRequest, git.EventTypePush}, &dispatcher.Dispatcher{Client: mgr.GetClient()})
+	server.AddPlugin([]git.EventType{git.EventTypePullRequest, git.EventTypePush, git.EventTypeRelease, git.EventTypeCheckRun}, &dispatcher.Dispatcher{Client: mgr.GetClient()})
 	server.AddPlugin([]git.EventType{git.EventTypeIssueComment, git.EventTypePullRequestReview, git.EventTypePullRequestReviewComment}, co)
 	server.AddPlugin([]git.EventType{git.EventTypePullRequest, git.EventTypePullRequestReview}, approveHandler)
+	server.AddPlugin([]git.EventType{git.EventTypePullRequest}, lgtmHandler)
+	server.AddPlugin([]git.EventType{git.EventTypePullRequest}, cherryPickHandler)
 	server.AddPlugin([]git.EventType{git.EventTypePullRequest}, &size.Size{Client: mgr.GetClient()})
+	server.AddPlugin([]git.EventType{git.EventTypePullRequest}, &wip.WIP{Client: mgr.GetClient()})
+	server.AddPlugin([]git.EventType{git.EventTypePullRequest}, &welcome.Welcome{Client: mgr.GetClient()})
 	go srv.Start()
 
 	setupLog.Info("starting manager")
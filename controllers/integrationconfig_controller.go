@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"time"
@@ -31,11 +32,14 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
+	"github.com/tmax-cloud/cicd-operator/pkg/events"
 	"github.com/tmax-cloud/cicd-operator/pkg/periodictrigger"
 )
 
@@ -48,8 +52,9 @@ const (
 // IntegrationConfigReconciler reconciles a IntegrationConfig object
 type IntegrationConfigReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 var periodicTriggers = map[string]*periodictrigger.PeriodicTrigger{}
@@ -114,10 +119,31 @@ func (r *IntegrationConfigReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	} else {
 		re = ctrl.Result{}
 	}
+	r.recordWebhookRegisterFailure(instance, original)
+
+	// Synchronize the required status checks of the protected branches
+	if resetTime := r.setBranchProtectionSyncedCond(instance); resetTime > 0 && !re.Requeue {
+		re = ctrl.Result{RequeueAfter: time.Duration(git.GetGapTime(resetTime)) * time.Second, Requeue: true}
+	}
 
 	// Set ready
 	r.setReadyCond(instance)
 
+	// Validate jobs and parameters
+	if err := validateJobs(instance); err != nil {
+		setInvalidCond(instance, "InvalidJobs", err)
+	} else if err := instance.Spec.ParamConfig.Validate(); err != nil {
+		setInvalidCond(instance, "InvalidParamConfig", err)
+	} else if err := instance.Spec.IJManageSpec.Validate(); err != nil {
+		setInvalidCond(instance, "InvalidIJManageSpec", err)
+	} else if err := instance.Spec.ExecutionWindows.Validate(); err != nil {
+		setInvalidCond(instance, "InvalidExecutionWindows", err)
+	} else if err := instance.Spec.MergeConfig.Validate(); err != nil {
+		setInvalidCond(instance, "InvalidMergeConfig", err)
+	} else if err := instance.Spec.Commands.Validate(instance.Spec.ParamConfig); err != nil {
+		setInvalidCond(instance, "InvalidCommands", err)
+	}
+
 	if instance.Spec.Jobs.Periodic != nil {
 		r.setPeriodicTrigger(instance)
 	}
@@ -186,22 +212,8 @@ func (r *IntegrationConfigReconciler) handleFinalizer(instance *cicdv1.Integrati
 	if instance.DeletionTimestamp != nil && idx >= 0 {
 		// Delete webhook only if it has git token
 		if instance.Spec.Git.Token != nil {
-			gitCli, err := utils.GetGitCli(instance, r.Client)
-			if err != nil {
-				r.Log.Error(err, "")
-			} else {
-				hookList, err := gitCli.ListWebhook()
-				if err != nil {
-					r.Log.Error(err, "")
-				}
-				for _, h := range hookList {
-					if h.URL == instance.GetWebhookServerAddress() {
-						r.Log.Info("Deleting webhook " + h.URL)
-						if err := gitCli.DeleteWebhook(h.ID); err != nil {
-							r.Log.Error(err, "")
-						}
-					}
-				}
+			for _, repo := range instance.Spec.Git.GetRepositories() {
+				r.deleteWebhook(instance.ForRepository(repo))
 			}
 		}
 
@@ -255,6 +267,14 @@ func (r *IntegrationConfigReconciler) setWebhookRegisteredCond(instance *cicdv1.
 		return 0
 	}
 
+	// Delete the webhooks of the repositories removed from the IntegrationConfig
+	r.deleteRemovedWebhooks(instance)
+
+	// Register to each repository, if there are multiple repositories
+	if len(instance.Spec.Git.Repositories) > 0 {
+		return r.setRepositoriesWebhookRegisteredCond(instance, webhookRegistered)
+	}
+
 	// Register only if the condition is false
 	if webhookRegistered.Status == metav1.ConditionFalse {
 		webhookRegistered.Status = metav1.ConditionFalse
@@ -300,6 +320,230 @@ func (r *IntegrationConfigReconciler) setWebhookRegisteredCond(instance *cicdv1.
 	return 0
 }
 
+// recordWebhookRegisterFailure records a warning event if the webhook registration failed, only if the failure is
+// different from the one of the original IntegrationConfig
+func (r *IntegrationConfigReconciler) recordWebhookRegisterFailure(instance, original *cicdv1.IntegrationConfig) {
+	cond := meta.FindStatusCondition(instance.Status.Conditions, cicdv1.IntegrationConfigConditionWebhookRegistered)
+	if cond == nil || cond.Status != metav1.ConditionFalse || (cond.Reason != "gitCliErr" && cond.Reason != "webhookRegisterFailed") {
+		return
+	}
+	if old := meta.FindStatusCondition(original.Status.Conditions, cicdv1.IntegrationConfigConditionWebhookRegistered); old != nil && old.Reason == cond.Reason && old.Message == cond.Message {
+		return
+	}
+	r.Recorder.Event(instance, corev1.EventTypeWarning, events.ReasonWebhookRegisterFailed, cond.Message)
+}
+
+// setRepositoriesWebhookRegisteredCond registers the webhook to each of the repositories, which is not registered yet
+// Unlike a single repository, the webhook already registered to a repository is regarded as registered, as the
+// repositories can be added to the IntegrationConfig afterwards
+func (r *IntegrationConfigReconciler) setRepositoriesWebhookRegisteredCond(instance *cicdv1.IntegrationConfig, webhookRegistered *metav1.Condition) int {
+	registered := map[string]struct{}{}
+	for _, repo := range instance.Status.WebhookRepositories {
+		registered[repo] = struct{}{}
+	}
+
+	for _, repo := range instance.Spec.Git.GetRepositories() {
+		if _, exist := registered[repo]; exist {
+			continue
+		}
+		if reason, err := r.registerWebhook(instance.ForRepository(repo)); err != nil {
+			webhookRegistered.Status = metav1.ConditionFalse
+			webhookRegistered.Reason = reason
+			webhookRegistered.Message = fmt.Sprintf("cannot register webhook to %s: %s", repo, err.Error())
+			return git.CheckRateLimitGetResetTime(err)
+		}
+		instance.Status.WebhookRepositories = append(instance.Status.WebhookRepositories, repo)
+	}
+
+	webhookRegistered.Status = metav1.ConditionTrue
+	webhookRegistered.Reason = "Registered"
+	webhookRegistered.Message = "Webhook is registered"
+	return 0
+}
+
+// registerWebhook registers the webhook to the IntegrationConfig's repository, if it's not registered yet
+// It returns the reason of the failure with the error
+func (r *IntegrationConfigReconciler) registerWebhook(instance *cicdv1.IntegrationConfig) (string, error) {
+	gitCli, err := utils.GetGitCli(instance, r.Client)
+	if err != nil {
+		return "gitCliErr", err
+	}
+	addr := instance.GetWebhookServerAddress()
+	entries, err := gitCli.ListWebhook()
+	if err != nil {
+		return "webhookRegisterFailed", err
+	}
+	for _, e := range entries {
+		if addr == e.URL {
+			return "", nil
+		}
+	}
+	r.Log.Info("Registering webhook " + addr + " to " + instance.Spec.Git.Repository)
+	if err := gitCli.RegisterWebhook(addr); err != nil {
+		return "webhookRegisterFailed", err
+	}
+	return "", nil
+}
+
+// deleteRemovedWebhooks deletes the webhooks of the repositories, which are removed from the IntegrationConfig
+func (r *IntegrationConfigReconciler) deleteRemovedWebhooks(instance *cicdv1.IntegrationConfig) {
+	var remaining []string
+	for _, repo := range instance.Status.WebhookRepositories {
+		if instance.Spec.Git.HasRepository(repo) {
+			remaining = append(remaining, repo)
+			continue
+		}
+		r.deleteWebhook(instance.ForRepository(repo))
+	}
+	if len(instance.Spec.Git.Repositories) == 0 {
+		remaining = nil
+	}
+	instance.Status.WebhookRepositories = remaining
+}
+
+// deleteWebhook deletes the webhook from the IntegrationConfig's repository
+func (r *IntegrationConfigReconciler) deleteWebhook(instance *cicdv1.IntegrationConfig) {
+	gitCli, err := utils.GetGitCli(instance, r.Client)
+	if err != nil {
+		r.Log.Error(err, "")
+		return
+	}
+	hookList, err := gitCli.ListWebhook()
+	if err != nil {
+		r.Log.Error(err, "")
+	}
+	for _, h := range hookList {
+		if h.URL == instance.GetWebhookServerAddress() {
+			r.Log.Info("Deleting webhook " + h.URL)
+			if err := gitCli.DeleteWebhook(h.ID); err != nil {
+				r.Log.Error(err, "")
+			}
+		}
+	}
+}
+
+// setBranchProtectionSyncedCond synchronizes the required status checks of the branches with the preSubmit jobs and
+// sets branch-protection-synced condition. It returns the time the rate limit is reset at, if it's exceeded
+// The git server is requested only for the branches whose required status checks are changed
+func (r *IntegrationConfigReconciler) setBranchProtectionSyncedCond(instance *cicdv1.IntegrationConfig) int {
+	if instance.Spec.BranchProtection == nil {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced)
+		instance.Status.BranchProtections = nil
+		return 0
+	}
+
+	cond := metav1.Condition{
+		Type:    cicdv1.IntegrationConfigConditionBranchProtectionSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Synced",
+		Message: "Required status checks are synchronized",
+	}
+	defer func() {
+		meta.SetStatusCondition(&instance.Status.Conditions, cond)
+	}()
+
+	// Required status checks are supported only for GitHub
+	if instance.Spec.Git.Type == cicdv1.GitTypeGitLab {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NotSupported"
+		cond.Message = fmt.Sprintf("Required status checks are not supported for %s", instance.Spec.Git.Type)
+		instance.Status.BranchProtections = nil
+		return 0
+	}
+	if instance.Spec.Git.Token == nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = cicdv1.IntegrationConfigConditionReasonNoGitToken
+		cond.Message = "Skipped to synchronize required status checks"
+		return 0
+	}
+	if err := validateJobs(instance); err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "InvalidJobs"
+		cond.Message = err.Error()
+		return 0
+	}
+
+	synced := map[string][]string{}
+	for _, s := range instance.Status.BranchProtections {
+		synced[s.Repository+"/"+s.Branch] = s.RequiredChecks
+	}
+	defer func() {
+		var statuses []cicdv1.BranchProtectionStatus
+		for _, repo := range instance.Spec.Git.GetRepositories() {
+			for _, branch := range instance.Spec.BranchProtection.Branches {
+				if checks, exist := synced[repo+"/"+branch]; exist {
+					statuses = append(statuses, cicdv1.BranchProtectionStatus{Repository: repo, Branch: branch, RequiredChecks: checks})
+				}
+			}
+		}
+		instance.Status.BranchProtections = statuses
+	}()
+
+	for _, repo := range instance.Spec.Git.GetRepositories() {
+		config := instance.ForRepository(repo)
+		gitCli, err := utils.GetGitCli(config, r.Client)
+		if err != nil {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "gitCliErr"
+			cond.Message = err.Error()
+			return 0
+		}
+		for _, branch := range instance.Spec.BranchProtection.Branches {
+			checks, err := requiredStatusChecks(config, gitCli, branch)
+			if err != nil {
+				cond.Status = metav1.ConditionFalse
+				cond.Reason = "CannotGetRequiredChecks"
+				cond.Message = fmt.Sprintf("cannot get required status checks of %s of %s: %s", branch, repo, err.Error())
+				return git.CheckRateLimitGetResetTime(err)
+			}
+
+			prev, exist := synced[repo+"/"+branch]
+			if exist && equalStrings(prev, checks) {
+				continue
+			}
+			r.Log.Info(fmt.Sprintf("Setting required status checks of %s of %s to %v", branch, repo, checks))
+			if err := gitCli.SetRequiredStatusChecks(branch, checks); err != nil {
+				cond.Status = metav1.ConditionFalse
+				cond.Reason = "SyncFailed"
+				cond.Message = fmt.Sprintf("cannot set required status checks of %s of %s: %s", branch, repo, err.Error())
+				return git.CheckRateLimitGetResetTime(err)
+			}
+			synced[repo+"/"+branch] = checks
+		}
+	}
+	return 0
+}
+
+// requiredStatusChecks returns the names of the preSubmit jobs run for the pull requests to the branch
+// The jobs are loaded from the branch, if spec.jobs.configFile is set
+// Only the aggregated commit status is required, if spec.aggregateCommitStatus is set
+func requiredStatusChecks(instance *cicdv1.IntegrationConfig, gitCli git.Client, branch string) ([]string, error) {
+	config, err := dispatcher.LoadConfigFile(instance, gitCli, branch)
+	if err != nil {
+		return nil, err
+	}
+	var checks []string
+	for _, j := range dispatcher.FilterJobs(config.Spec.Jobs.PreSubmit, git.EventTypePullRequest, branch) {
+		checks = append(checks, j.Name)
+	}
+	if aggregated := instance.GetAggregateCommitStatusContext(); aggregated != "" && len(checks) > 0 {
+		return []string{aggregated}, nil
+	}
+	return checks, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Set ready condition, return if it's changed or not
 func (r *IntegrationConfigReconciler) setReadyCond(instance *cicdv1.IntegrationConfig) {
 	cond := meta.FindStatusCondition(instance.Status.Conditions, cicdv1.IntegrationConfigConditionReady)
@@ -312,6 +556,46 @@ func (r *IntegrationConfigReconciler) setReadyCond(instance *cicdv1.IntegrationC
 	}
 }
 
+// setInvalidCond sets the ready condition false, as the spec is invalid
+func setInvalidCond(instance *cicdv1.IntegrationConfig, reason string, err error) {
+	cond := meta.FindStatusCondition(instance.Status.Conditions, cicdv1.IntegrationConfigConditionReady)
+	cond.Status = metav1.ConditionFalse
+	cond.Reason = reason
+	cond.Message = err.Error()
+}
+
+// validateJobs validates preSubmit/postSubmit jobs' dependencies, and if the jobs can run in the remote cluster
+func validateJobs(instance *cicdv1.IntegrationConfig) error {
+	if err := instance.Spec.Jobs.PreSubmit.Validate(); err != nil {
+		return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+	}
+	if err := instance.Spec.Jobs.PostSubmit.Validate(); err != nil {
+		return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+	}
+	if err := instance.Spec.Jobs.PreSubmit.ValidateWorkspaces(instance.Spec.Workspaces); err != nil {
+		return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+	}
+	if err := instance.Spec.Jobs.PostSubmit.ValidateWorkspaces(instance.Spec.Workspaces); err != nil {
+		return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+	}
+	if instance.Spec.RemoteCluster != nil {
+		if err := instance.Spec.Jobs.PreSubmit.ValidateRemoteCluster(); err != nil {
+			return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+		}
+		if err := instance.Spec.Jobs.PostSubmit.ValidateRemoteCluster(); err != nil {
+			return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+		}
+		var periodic cicdv1.Jobs
+		for _, p := range instance.Spec.Jobs.Periodic {
+			periodic = append(periodic, p.Job)
+		}
+		if err := periodic.ValidateRemoteCluster(); err != nil {
+			return fmt.Errorf("periodic is invalid: %s", err.Error())
+		}
+	}
+	return nil
+}
+
 func (r *IntegrationConfigReconciler) setPeriodicTrigger(instance *cicdv1.IntegrationConfig) {
 	// Check if periodicTrigger exists
 	nameAndNamespace := instance.Name + instance.Namespace
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -274,7 +275,7 @@ func TestIntegrationConfigReconciler_Reconcile(t *testing.T) {
 				gitfake.Repos["test-repo"].Webhooks[i] = &git.WebhookEntry{ID: i, URL: w}
 			}
 
-			reconciler := &IntegrationConfigReconciler{Log: &test.FakeLogger{}, Scheme: c.scheme, Client: fakeCli}
+			reconciler := &IntegrationConfigReconciler{Log: &test.FakeLogger{}, Scheme: c.scheme, Client: fakeCli, Recorder: record.NewFakeRecorder(10)}
 
 			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: c.ic.Name, Namespace: c.ic.Namespace}})
 			if c.errorOccurs {
@@ -715,6 +716,195 @@ func TestIntegrationConfigReconciler_setWebhookRegisteredCond(t *testing.T) {
 	}
 }
 
+func TestIntegrationConfigReconciler_setWebhookRegisteredCond_repositories(t *testing.T) {
+	configs.CurrentExternalHostName = "cicd-webhook.com"
+	addr := "http://cicd-webhook.com/webhook/test-ns/test-ic"
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test-repo":  {Webhooks: map[int]*git.WebhookEntry{32: {ID: 32, URL: addr}}},
+		"test-repo2": {Webhooks: map[int]*git.WebhookEntry{}},
+		"test-repo3": {Webhooks: map[int]*git.WebhookEntry{}},
+	}
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:         cicdv1.GitTypeFake,
+				Repository:   "test-repo",
+				Repositories: []string{"test-repo2", "test-repo4"},
+				Token:        &cicdv1.GitToken{Value: "test-tkn"},
+			},
+		},
+	}
+	reconciler := &IntegrationConfigReconciler{Log: &test.FakeLogger{}}
+
+	// test-repo4 does not exist
+	reconciler.setWebhookRegisteredCond(ic)
+	cond := meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionWebhookRegistered)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, "webhookRegisterFailed", cond.Reason)
+	require.Equal(t, "cannot register webhook to test-repo4: 404 no such repository", cond.Message)
+	require.Equal(t, []string{"test-repo", "test-repo2"}, ic.Status.WebhookRepositories)
+	require.Len(t, gitfake.Repos["test-repo"].Webhooks, 1)
+	require.Len(t, gitfake.Repos["test-repo2"].Webhooks, 1)
+
+	// Replace test-repo4 with test-repo3, and remove test-repo2
+	ic.Spec.Git.Repositories = []string{"test-repo3"}
+	reconciler.setWebhookRegisteredCond(ic)
+	cond = meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionWebhookRegistered)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, "Registered", cond.Reason)
+	require.Equal(t, []string{"test-repo", "test-repo3"}, ic.Status.WebhookRepositories)
+	require.Len(t, gitfake.Repos["test-repo2"].Webhooks, 0)
+	require.Len(t, gitfake.Repos["test-repo3"].Webhooks, 1)
+
+	// Back to a single repository
+	ic.Spec.Git.Repositories = nil
+	reconciler.setWebhookRegisteredCond(ic)
+	require.Empty(t, ic.Status.WebhookRepositories)
+	require.Len(t, gitfake.Repos["test-repo"].Webhooks, 1)
+	require.Len(t, gitfake.Repos["test-repo3"].Webhooks, 0)
+}
+
+func TestIntegrationConfigReconciler_recordWebhookRegisterFailure(t *testing.T) {
+	cond := func(status metav1.ConditionStatus, reason, message string) []metav1.Condition {
+		return []metav1.Condition{{Type: cicdv1.IntegrationConfigConditionWebhookRegistered, Status: status, Reason: reason, Message: message}}
+	}
+
+	tc := map[string]struct {
+		original []metav1.Condition
+		current  []metav1.Condition
+
+		expected []string
+	}{
+		"failed": {
+			original: cond(metav1.ConditionFalse, "NotRegistered", ""),
+			current:  cond(metav1.ConditionFalse, "webhookRegisterFailed", "401 Unauthorized"),
+			expected: []string{"Warning WebhookRegisterFailed 401 Unauthorized"},
+		},
+		"gitCliErr": {
+			current:  cond(metav1.ConditionFalse, "gitCliErr", "git type is not supported"),
+			expected: []string{"Warning WebhookRegisterFailed git type is not supported"},
+		},
+		"failedAgain": {
+			original: cond(metav1.ConditionFalse, "webhookRegisterFailed", "401 Unauthorized"),
+			current:  cond(metav1.ConditionFalse, "webhookRegisterFailed", "401 Unauthorized"),
+		},
+		"registered": {
+			original: cond(metav1.ConditionFalse, "webhookRegisterFailed", "401 Unauthorized"),
+			current:  cond(metav1.ConditionTrue, "Registered", "Webhook is registered"),
+		},
+		"noGitToken": {
+			current: cond(metav1.ConditionFalse, cicdv1.IntegrationConfigConditionReasonNoGitToken, "Skipped to register webhook"),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &IntegrationConfigReconciler{Recorder: recorder}
+			original := &cicdv1.IntegrationConfig{Status: cicdv1.IntegrationConfigStatus{Conditions: c.original}}
+			instance := &cicdv1.IntegrationConfig{Status: cicdv1.IntegrationConfigStatus{Conditions: c.current}}
+
+			reconciler.recordWebhookRegisterFailure(instance, original)
+
+			close(recorder.Events)
+			var recorded []string
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			require.Equal(t, c.expected, recorded)
+		})
+	}
+}
+
+func TestIntegrationConfigReconciler_setBranchProtectionSyncedCond(t *testing.T) {
+	gitfake.Repos = map[string]*gitfake.Repo{"test-repo": {}}
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test-repo", Token: &cicdv1.GitToken{Value: "test-tkn"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit: cicdv1.Jobs{
+					{Container: corev1.Container{Name: "lint"}},
+					{Container: corev1.Container{Name: "test"}, Matrix: []cicdv1.MatrixParam{{Name: "go", Values: []string{"1.16", "1.17"}}}},
+					{Container: corev1.Container{Name: "release-only"}, When: &cicdv1.JobWhen{Branch: []string{"release"}}},
+				},
+			},
+			BranchProtection: &cicdv1.BranchProtection{Branches: []string{"master", "release"}},
+		},
+	}
+	reconciler := &IntegrationConfigReconciler{Log: &test.FakeLogger{}}
+
+	require.Equal(t, 0, reconciler.setBranchProtectionSyncedCond(ic))
+	cond := meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, "Synced", cond.Reason)
+	require.Equal(t, map[string][]string{
+		"master":  {"lint", "test-1-16", "test-1-17"},
+		"release": {"lint", "test-1-16", "test-1-17", "release-only"},
+	}, gitfake.Repos["test-repo"].RequiredChecks)
+	require.Equal(t, []cicdv1.BranchProtectionStatus{
+		{Repository: "test-repo", Branch: "master", RequiredChecks: []string{"lint", "test-1-16", "test-1-17"}},
+		{Repository: "test-repo", Branch: "release", RequiredChecks: []string{"lint", "test-1-16", "test-1-17", "release-only"}},
+	}, ic.Status.BranchProtections)
+
+	// Only the changed branches are synchronized
+	gitfake.Repos["test-repo"].RequiredChecks = nil
+	ic.Spec.Jobs.PreSubmit[2].Name = "release-test"
+	reconciler.setBranchProtectionSyncedCond(ic)
+	require.Equal(t, map[string][]string{
+		"release": {"lint", "test-1-16", "test-1-17", "release-test"},
+	}, gitfake.Repos["test-repo"].RequiredChecks)
+
+	// Only the aggregated commit status is required
+	gitfake.Repos["test-repo"].RequiredChecks = nil
+	ic.Spec.AggregateCommitStatus = &cicdv1.AggregateCommitStatus{}
+	reconciler.setBranchProtectionSyncedCond(ic)
+	require.Equal(t, map[string][]string{
+		"master":  {cicdv1.DefaultAggregateCommitStatusContext},
+		"release": {cicdv1.DefaultAggregateCommitStatusContext},
+	}, gitfake.Repos["test-repo"].RequiredChecks)
+	ic.Spec.AggregateCommitStatus = nil
+
+	// Failed to synchronize
+	delete(gitfake.Repos, "test-repo")
+	ic.Spec.Jobs.PreSubmit[0].Name = "lint2"
+	reconciler.setBranchProtectionSyncedCond(ic)
+	cond = meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, "SyncFailed", cond.Reason)
+	require.Equal(t, "cannot set required status checks of master of test-repo: 404 no such repository", cond.Message)
+	require.Len(t, ic.Status.BranchProtections, 2)
+
+	// Failed to get the required status checks, i.e., the config file
+	gitfake.Repos["test-repo"] = &gitfake.Repo{}
+	ic.Spec.Jobs.ConfigFile = &cicdv1.JobsConfigFile{}
+	reconciler.setBranchProtectionSyncedCond(ic)
+	cond = meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, "CannotGetRequiredChecks", cond.Reason)
+	require.Equal(t, "cannot get required status checks of master of test-repo: cannot get .cicd/config.yaml: 404 no such file (.cicd/config.yaml) at master", cond.Message)
+	ic.Spec.Jobs.ConfigFile = nil
+
+	// Not supported for GitLab
+	ic.Spec.Git.Type = cicdv1.GitTypeGitLab
+	reconciler.setBranchProtectionSyncedCond(ic)
+	cond = meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, "NotSupported", cond.Reason)
+	require.Equal(t, "Required status checks are not supported for gitlab", cond.Message)
+	require.Empty(t, ic.Status.BranchProtections)
+	ic.Spec.Git.Type = cicdv1.GitTypeFake
+
+	// Disabled
+	ic.Spec.BranchProtection = nil
+	reconciler.setBranchProtectionSyncedCond(ic)
+	require.Nil(t, meta.FindStatusCondition(ic.Status.Conditions, cicdv1.IntegrationConfigConditionBranchProtectionSynced))
+	require.Empty(t, ic.Status.BranchProtections)
+}
+
 func TestIntegrationConfigReconciler_setReadyCond(t *testing.T) {
 	tc := map[string]struct {
 		ic *cicdv1.IntegrationConfig
@@ -18,14 +18,22 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/events"
 	"github.com/tmax-cloud/cicd-operator/pkg/pipelinemanager"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
 	"github.com/tmax-cloud/cicd-operator/pkg/scheduler"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -33,6 +41,9 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 )
 
+// remoteClusterResyncPeriod is a period of checking the PipelineRuns running in remote clusters
+const remoteClusterResyncPeriod = 30 * time.Second
+
 // IntegrationJobReconciler is an interface for integrationJobReconciler
 type IntegrationJobReconciler interface {
 	SetupWithManager(mgr ctrl.Manager) error
@@ -44,27 +55,34 @@ type integrationJobReconciler struct {
 	Log    logr.Logger
 	Scheme *runtime.Scheme
 
+	recorder  record.EventRecorder
 	scheduler scheduler.Scheduler
 	pm        pipelinemanager.PipelineManager
 }
 
 // NewIntegrationJobReconciler is a constructor of integrationJobReconciler
-func NewIntegrationJobReconciler(cli client.Client, scheme *runtime.Scheme, log logr.Logger) *integrationJobReconciler {
-	pm := pipelinemanager.NewPipelineManager(cli, scheme)
+func NewIntegrationJobReconciler(cli client.Client, apiReader client.Reader, scheme *runtime.Scheme, recorder record.EventRecorder, log logr.Logger) *integrationJobReconciler {
+	pm := pipelinemanager.NewPipelineManager(cli, apiReader, scheme)
 	return &integrationJobReconciler{
 		Client: cli,
 		Scheme: scheme,
 		Log:    log,
 
+		recorder:  recorder,
 		pm:        pm,
-		scheduler: scheduler.New(cli, scheme, pm),
+		scheduler: scheduler.New(cli, apiReader, scheme, recorder, pm),
 	}
 }
 
 // +kubebuilder:rbac:groups=cicd.tmax.io,resources=integrationjobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cicd.tmax.io,resources=integrationjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cicd.tmax.io,resources=integrationjobtemplates;clusterintegrationjobtemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns/status,verbs=get
+// +kubebuilder:rbac:groups=tekton.dev,resources=taskruns,verbs=patch
+// +kubebuilder:rbac:groups=tekton.dev,resources=tasks,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile reconciles IntegrationJob
 func (r *integrationJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -106,10 +124,18 @@ func (r *integrationJobReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		r.patchJobFailed(instance, original, err.Error())
 		return ctrl.Result{}, nil
 	}
+	// Use the configuration for the IntegrationJob's repository, as the IntegrationConfig may have multiple repositories
+	config = config.ForRepository(instance.Spec.Refs.Repository)
 
-	// Get PipelineRun
+	// Get PipelineRun, from the cluster it runs in
+	prClient, err := remotecluster.ClientFor(r.Client, instance)
+	if err != nil {
+		log.Error(err, "")
+		r.patchJobFailed(instance, original, err.Error())
+		return ctrl.Result{}, nil
+	}
 	pr := &tektonv1beta1.PipelineRun{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: pipelinemanager.Name(instance), Namespace: instance.Namespace}, pr); err != nil {
+	if err := prClient.Get(ctx, types.NamespacedName{Name: pipelinemanager.Name(instance), Namespace: instance.Namespace}, pr); err != nil {
 		if !errors.IsNotFound(err) {
 			log.Error(err, "")
 			r.patchJobFailed(instance, original, err.Error())
@@ -118,6 +144,16 @@ func (r *integrationJobReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		pr = nil
 	}
 
+	// Cancel the PipelineRun of the cancelled or superseded IntegrationJob
+	cancelled := instance.Spec.Cancelled || instance.Annotations[cicdv1.JobAnnotationSupersededBy] != ""
+	if cancelled && pr != nil && !pr.IsDone() && !pr.IsCancelled() {
+		if err := cancelPipelineRun(prClient, pr); err != nil {
+			log.Error(err, "")
+			r.patchJobFailed(instance, original, err.Error())
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Set default values for IntegrationJob.status
 	instance.Status.SetDefaults()
 
@@ -134,6 +170,26 @@ func (r *integrationJobReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		log.Error(err, "")
 		return ctrl.Result{}, err
 	}
+	events.RecordStateChange(r.recorder, instance, original.Status.State)
+
+	// Clean up the PipelineRun of the stuck IntegrationJob, with its TaskRuns and pods
+	if instance.Status.Reason == cicdv1.IntegrationJobReasonStuck && pr != nil {
+		if err := prClient.Delete(ctx, pr); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// PipelineRuns in remote clusters are not watched, so check them again later
+	if instance.Spec.RemoteCluster != nil {
+		return ctrl.Result{RequeueAfter: remoteClusterResyncPeriod}, nil
+	}
+
+	// Check again later if the running IntegrationJob is stuck, as the PipelineRun may not be updated
+	if instance.Status.State == cicdv1.IntegrationJobStateRunning && configs.StuckJobTimeout > 0 {
+		return ctrl.Result{RequeueAfter: time.Duration(configs.StuckJobTimeout) * time.Minute}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
@@ -155,6 +211,7 @@ func (r *integrationJobReconciler) handleFinalizer(instance, original *cicdv1.In
 		if err := r.Client.Patch(context.Background(), instance, p); err != nil {
 			return false, err
 		}
+		r.recorder.Event(instance, corev1.EventTypeNormal, events.ReasonIntegrationJobCreated, fmt.Sprintf("%s IntegrationJob is created", instance.Spec.ConfigRef.Type))
 		return true, nil
 	}
 
@@ -163,6 +220,11 @@ func (r *integrationJobReconciler) handleFinalizer(instance, original *cicdv1.In
 		// Notify scheduler
 		r.scheduler.Notify(instance)
 
+		// Delete the PipelineRun in the remote cluster, as it's not garbage collected with the IntegrationJob
+		if err := r.deleteRemotePipelineRun(instance); err != nil {
+			return false, err
+		}
+
 		// Delete finalizer
 		if len(instance.Finalizers) == 1 {
 			instance.Finalizers = nil
@@ -184,13 +246,37 @@ func (r *integrationJobReconciler) handleFinalizer(instance, original *cicdv1.In
 	return false, nil
 }
 
+func cancelPipelineRun(cli client.Client, pr *tektonv1beta1.PipelineRun) error {
+	original := pr.DeepCopy()
+	pr.Spec.Status = tektonv1beta1.PipelineRunSpecStatusCancelled
+	return cli.Patch(context.Background(), pr, client.MergeFrom(original))
+}
+
+// deleteRemotePipelineRun deletes the IntegrationJob's PipelineRun, if it runs in a remote cluster
+func (r *integrationJobReconciler) deleteRemotePipelineRun(instance *cicdv1.IntegrationJob) error {
+	if instance.Spec.RemoteCluster == nil {
+		return nil
+	}
+	cli, err := remotecluster.ClientFor(r.Client, instance)
+	if err != nil {
+		return err
+	}
+	pr := &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: pipelinemanager.Name(instance), Namespace: instance.Namespace}}
+	if err := cli.Delete(context.Background(), pr); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func (r *integrationJobReconciler) patchJobFailed(instance *cicdv1.IntegrationJob, original *cicdv1.IntegrationJob, message string) {
 	instance.Status.State = cicdv1.IntegrationJobStateFailed
 	instance.Status.Message = message
 	p := client.MergeFrom(original)
 	if err := r.Client.Status().Patch(context.Background(), instance, p); err != nil {
 		r.Log.Error(err, "")
+		return
 	}
+	events.RecordStateChange(r.recorder, instance, original.Status.State)
 }
 
 // SetupWithManager sets integrationJobReconciler to the manager
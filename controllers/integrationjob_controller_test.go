@@ -27,10 +27,12 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/test"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -40,11 +42,13 @@ func TestNewIntegrationJobReconciler(t *testing.T) {
 	s := runtime.NewScheme()
 	fakeCli := fake.NewClientBuilder().WithScheme(s).Build()
 	logger := &test.FakeLogger{Infos: []string{"hi"}}
-	reconciler := NewIntegrationJobReconciler(fakeCli, s, logger)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewIntegrationJobReconciler(fakeCli, fakeCli, s, recorder, logger)
 
 	require.Equal(t, s, reconciler.Scheme)
 	require.Equal(t, fakeCli, reconciler.Client)
 	require.Equal(t, logger, reconciler.Log)
+	require.Equal(t, recorder, reconciler.recorder)
 }
 
 func TestIntegrationJobReconciler_Reconcile(t *testing.T) {
@@ -242,7 +246,7 @@ func TestIntegrationJobReconciler_Reconcile(t *testing.T) {
 		},
 	}
 
-	reconciler := &integrationJobReconciler{pm: &fakePipelineManager{}, Log: logger, scheduler: &fakeScheduler{}}
+	reconciler := &integrationJobReconciler{pm: &fakePipelineManager{}, Log: logger, recorder: record.NewFakeRecorder(100), scheduler: &fakeScheduler{}}
 
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
@@ -275,16 +279,110 @@ func TestIntegrationJobReconciler_Reconcile(t *testing.T) {
 	}
 }
 
+func TestIntegrationJobReconciler_Reconcile_cancel(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	tc := map[string]struct {
+		annotations map[string]string
+		cancelled   bool
+	}{
+		"superseded": {
+			annotations: map[string]string{cicdv1.JobAnnotationSupersededBy: "new-ij"},
+		},
+		"cancelled": {
+			cancelled: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-ij",
+					Namespace:   "test-ns",
+					Finalizers:  []string{finalizer},
+					Annotations: c.annotations,
+				},
+				Spec: cicdv1.IntegrationJobSpec{
+					ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic"},
+					Cancelled: c.cancelled,
+				},
+			}
+			ic := &cicdv1.IntegrationConfig{ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"}}
+			pr := &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "test-ns"}}
+
+			reconciler := &integrationJobReconciler{
+				Client:    fake.NewClientBuilder().WithScheme(s).WithObjects(ij, ic, pr).Build(),
+				pm:        &fakePipelineManager{},
+				Log:       &test.FakeLogger{},
+				recorder:  record.NewFakeRecorder(10),
+				scheduler: &fakeScheduler{},
+			}
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-ij", Namespace: "test-ns"}})
+			require.NoError(t, err)
+
+			result := &tektonv1beta1.PipelineRun{}
+			require.NoError(t, reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "test-ij", Namespace: "test-ns"}, result))
+			require.True(t, result.IsCancelled())
+		})
+	}
+}
+
+func TestIntegrationJobReconciler_Reconcile_stuck(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "stuck-ij", Namespace: "test-ns", Finalizers: []string{finalizer}},
+		Spec:       cicdv1.IntegrationJobSpec{ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic"}},
+	}
+	ic := &cicdv1.IntegrationConfig{ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"}}
+	pr := &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "stuck-ij", Namespace: "test-ns"}}
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &integrationJobReconciler{
+		Client:    fake.NewClientBuilder().WithScheme(s).WithObjects(ij, ic, pr).Build(),
+		pm:        &fakePipelineManager{},
+		Log:       &test.FakeLogger{},
+		recorder:  recorder,
+		scheduler: &fakeScheduler{},
+	}
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "stuck-ij", Namespace: "test-ns"}})
+	require.NoError(t, err)
+
+	result := &tektonv1beta1.PipelineRun{}
+	err = reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "stuck-ij", Namespace: "test-ns"}, result)
+	require.True(t, errors.IsNotFound(err))
+
+	resultIJ := &cicdv1.IntegrationJob{}
+	require.NoError(t, reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "stuck-ij", Namespace: "test-ns"}, resultIJ))
+	require.Equal(t, cicdv1.IntegrationJobReasonStuck, resultIJ.Status.Reason)
+	require.Equal(t, "Warning Failed IntegrationJob is Failed", <-recorder.Events)
+}
+
 type fakePipelineManager struct{}
 
 func (f *fakePipelineManager) Generate(_ *cicdv1.IntegrationJob) (*tektonv1beta1.PipelineRun, error) {
 	return nil, nil
 }
 
+func (f *fakePipelineManager) ResolveJobs(job *cicdv1.IntegrationJob) (cicdv1.Jobs, error) {
+	return job.Spec.Jobs, nil
+}
+
 func (f *fakePipelineManager) ReflectStatus(_ *tektonv1beta1.PipelineRun, job *cicdv1.IntegrationJob, _ *cicdv1.IntegrationConfig) error {
 	if job.Name == "reflect-fail" {
 		return fmt.Errorf("expected-error")
 	}
+	if job.Name == "stuck-ij" {
+		job.Status.State = cicdv1.IntegrationJobStateFailed
+		job.Status.Reason = cicdv1.IntegrationJobReasonStuck
+	}
 	if job.Annotations == nil {
 		job.Annotations = map[string]string{}
 	}
@@ -363,6 +461,7 @@ func TestIntegrationJobReconciler_handleFinalizer(t *testing.T) {
 			logger := &test.FakeLogger{}
 			reconciler.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(original).Build()
 			reconciler.Log = logger
+			reconciler.recorder = record.NewFakeRecorder(10)
 			reconciler.scheduler = &fakeScheduler{}
 
 			ij := original.DeepCopy()
@@ -433,8 +532,10 @@ func TestIntegrationJobReconciler_patchJobFailed(t *testing.T) {
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
 			logger := &test.FakeLogger{}
+			recorder := record.NewFakeRecorder(10)
 			reconciler.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(original).Build()
 			reconciler.Log = logger
+			reconciler.recorder = recorder
 
 			ij := original.DeepCopy()
 			c.ijModifier(ij)
@@ -443,7 +544,10 @@ func TestIntegrationJobReconciler_patchJobFailed(t *testing.T) {
 			if c.errorOccurs {
 				require.Len(t, logger.Errors, 1)
 				require.Equal(t, c.errorMessage, logger.Errors[0].Error())
+				require.Len(t, recorder.Events, 0)
 			} else {
+				require.Equal(t, "Warning Failed "+c.message, <-recorder.Events)
+
 				result := &cicdv1.IntegrationJob{}
 				require.NoError(t, reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "test-ij", Namespace: "test-ns"}, result))
 
@@ -28,10 +28,15 @@ const (
 // ApplyBlockerConfigChange is a configmap handler for blocker-config configmap
 func ApplyBlockerConfigChange(cm *corev1.ConfigMap) error {
 	getVars(cm.Data, map[string]operatorConfig{
-		"mergeSyncPeriod":      {Type: cfgTypeInt, IntVal: &MergeSyncPeriod, IntDefault: 1},                               // Merge automation sync period
-		"mergeBlockLabel":      {Type: cfgTypeString, StringVal: &MergeBlockLabel, StringDefault: "ci/hold"},              // Merge automation block label
-		"mergeKindSquashLabel": {Type: cfgTypeString, StringVal: &MergeKindSquashLabel, StringDefault: "ci/merge-squash"}, // Merge kind squash label
-		"mergeKindMergeLabel":  {Type: cfgTypeString, StringVal: &MergeKindMergeLabel, StringDefault: "ci/merge-merge"},   // Merge kind squash label
+		"mergeSyncPeriod":      {Type: cfgTypeInt, IntVal: &MergeSyncPeriod, IntDefault: 1},                                      // Merge automation sync period
+		"mergeBlockLabel":      {Type: cfgTypeString, StringVal: &MergeBlockLabel, StringDefault: "ci/hold"},                     // Merge automation block label
+		"mergeHoldLabel":       {Type: cfgTypeString, StringVal: &MergeHoldLabel, StringDefault: "do-not-merge/hold"},            // Hold label
+		"mergeKindSquashLabel": {Type: cfgTypeString, StringVal: &MergeKindSquashLabel, StringDefault: "ci/merge-squash"},        // Merge kind squash label
+		"mergeKindMergeLabel":  {Type: cfgTypeString, StringVal: &MergeKindMergeLabel, StringDefault: "ci/merge-merge"},          // Merge kind squash label
+		"mergeWIPLabel":        {Type: cfgTypeString, StringVal: &MergeWIPLabel, StringDefault: "do-not-merge/work-in-progress"}, // Work-in-progress label
+		"mergeConflictLabel":   {Type: cfgTypeString, StringVal: &MergeConflictLabel, StringDefault: "needs-rebase"},             // Merge conflict label
+		"mergeRetestPeriod":    {Type: cfgTypeInt, IntVal: &MergeRetestPeriod, IntDefault: 0},                                    // Minimum retest interval of a PR
+		"mergeSyncBudget":      {Type: cfgTypeInt, IntVal: &MergeSyncBudget, IntDefault: 0},                                      // Maximum PRs synced per sync
 	})
 
 	// Init
@@ -53,9 +58,26 @@ var (
 	// MergeBlockLabel is a label name which blocks a PR to be merged
 	MergeBlockLabel string
 
+	// MergeHoldLabel is a label set by the hold plugin. It blocks a PR to be merged, as MergeBlockLabel does
+	MergeHoldLabel string
+
+	// MergeWIPLabel is a label set to the work-in-progress PRs. It blocks a PR to be merged, as MergeBlockLabel does
+	MergeWIPLabel string
+
 	// MergeKindSquashLabel is a label to make a PR to be merged by 'squash'
 	MergeKindSquashLabel string
 
 	// MergeKindMergeLabel is a label to make a PR to be merged by 'merge'
 	MergeKindMergeLabel string
+
+	// MergeConflictLabel is a label set to the PRs with merge conflicts. Conflicts are not notified if it's empty
+	MergeConflictLabel string
+
+	// MergeRetestPeriod is the minimum interval between the retests of a PR in minute. PRs are retested whenever needed
+	// if it's 0
+	MergeRetestPeriod int
+
+	// MergeSyncBudget is the maximum number of the PRs in a merge pool whose statuses are synchronized per sync. Every
+	// PR is synchronized if it's 0
+	MergeSyncBudget int
 )
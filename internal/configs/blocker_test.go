@@ -32,31 +32,47 @@ func TestApplyBlockerConfigChange(t *testing.T) {
 
 			require.Equal(t, 1, MergeSyncPeriod)
 			require.Equal(t, "ci/hold", MergeBlockLabel)
+			require.Equal(t, "do-not-merge/hold", MergeHoldLabel)
 			require.Equal(t, "ci/merge-squash", MergeKindSquashLabel)
 			require.Equal(t, "ci/merge-merge", MergeKindMergeLabel)
+			require.Equal(t, "needs-rebase", MergeConflictLabel)
+			require.Equal(t, 0, MergeRetestPeriod)
+			require.Equal(t, 0, MergeSyncBudget)
 		}},
 		"normal": {ConfigMap: &corev1.ConfigMap{
 			Data: map[string]string{
 				"mergeSyncPeriod":      "1",
 				"mergeBlockLabel":      "test-block",
+				"mergeHoldLabel":       "test-hold",
 				"mergeKindSquashLabel": "test-squash",
 				"mergeKindMergeLabel":  "test-merge",
+				"mergeConflictLabel":   "test-conflict",
+				"mergeRetestPeriod":    "10",
+				"mergeSyncBudget":      "20",
 			},
 		}, AssertFunc: func(t *testing.T, err error) {
 			require.NoError(t, err)
 
 			require.Equal(t, 1, MergeSyncPeriod)
 			require.Equal(t, "test-block", MergeBlockLabel)
+			require.Equal(t, "test-hold", MergeHoldLabel)
 			require.Equal(t, "test-squash", MergeKindSquashLabel)
 			require.Equal(t, "test-merge", MergeKindMergeLabel)
+			require.Equal(t, "test-conflict", MergeConflictLabel)
+			require.Equal(t, 10, MergeRetestPeriod)
+			require.Equal(t, 20, MergeSyncBudget)
 		}},
 	}
 
 	for name, c := range tc {
 		MergeSyncPeriod = 0
 		MergeBlockLabel = ""
+		MergeHoldLabel = ""
 		MergeKindSquashLabel = ""
 		MergeKindMergeLabel = ""
+		MergeConflictLabel = ""
+		MergeRetestPeriod = 0
+		MergeSyncBudget = 0
 		t.Run(name, func(t *testing.T) {
 			err := ApplyBlockerConfigChange(c.ConfigMap)
 			c.AssertFunc(t, err)
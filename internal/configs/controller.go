@@ -18,6 +18,8 @@ package configs
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 )
@@ -39,22 +41,40 @@ func RegisterControllerConfigUpdateChan(ch chan struct{}) {
 // ApplyControllerConfigChange is a configmap handler for cicd-config configmap
 func ApplyControllerConfigChange(cm *corev1.ConfigMap) error {
 	getVars(cm.Data, map[string]operatorConfig{
-		"maxPipelineRun":            {Type: cfgTypeInt, IntVal: &MaxPipelineRun, IntDefault: 5},                                // Max PipelineRun count
-		"enableMail":                {Type: cfgTypeBool, BoolVal: &EnableMail, BoolDefault: false},                             // Enable Mail
-		"externalHostName":          {Type: cfgTypeString, StringVal: &ExternalHostName},                                       // External Hostname
-		"exposeMode":                {Type: cfgTypeString, StringVal: &ExposeMode, StringDefault: "Ingress"},                   // Expose mode
-		"reportRedirectUriTemplate": {Type: cfgTypeString, StringVal: &ReportRedirectURITemplate},                              // RedirectUriTemplate for report access
-		"smtpHost":                  {Type: cfgTypeString, StringVal: &SMTPHost},                                               // SMTP Host
-		"smtpUserSecret":            {Type: cfgTypeString, StringVal: &SMTPUserSecret},                                         // SMTP Cred
-		"collectPeriod":             {Type: cfgTypeInt, IntVal: &CollectPeriod, IntDefault: 120},                               // GC period
-		"integrationJobTTL":         {Type: cfgTypeInt, IntVal: &IntegrationJobTTL, IntDefault: 120},                           // GC threshold
-		"ingressClass":              {Type: cfgTypeString, StringVal: &IngressClass, StringDefault: ""},                        // Ingress class
-		"ingressHost":               {Type: cfgTypeString, StringVal: &IngressHost, StringDefault: ""},                         // Ingress host
-		"gitImage":                  {Type: cfgTypeString, StringVal: &GitImage, StringDefault: "docker.io/alpine/git:1.0.30"}, // Git image
-		"gitCheckoutStepCPURequest": {Type: cfgTypeString, StringVal: &GitCheckoutStepCPURequest, StringDefault: "30m"},        // Git checkout step CPU request
-		"gitCheckoutStepMemRequest": {Type: cfgTypeString, StringVal: &GitCheckoutStepMemRequest, StringDefault: "100Mi"},      // Git checkout step Memory request
+		"maxPipelineRun":                {Type: cfgTypeInt, IntVal: &MaxPipelineRun, IntDefault: 5},                                      // Max PipelineRun count
+		"enableMail":                    {Type: cfgTypeBool, BoolVal: &EnableMail, BoolDefault: false},                                   // Enable Mail
+		"externalHostName":              {Type: cfgTypeString, StringVal: &ExternalHostName},                                             // External Hostname
+		"exposeMode":                    {Type: cfgTypeString, StringVal: &ExposeMode, StringDefault: "Ingress"},                         // Expose mode
+		"reportRedirectUriTemplate":     {Type: cfgTypeString, StringVal: &ReportRedirectURITemplate},                                    // RedirectUriTemplate for report access
+		"smtpHost":                      {Type: cfgTypeString, StringVal: &SMTPHost},                                                     // SMTP Host
+		"smtpUserSecret":                {Type: cfgTypeString, StringVal: &SMTPUserSecret},                                               // SMTP Cred
+		"collectPeriod":                 {Type: cfgTypeInt, IntVal: &CollectPeriod, IntDefault: 120},                                     // GC period
+		"integrationJobTTL":             {Type: cfgTypeInt, IntVal: &IntegrationJobTTL, IntDefault: 120},                                 // GC threshold
+		"orphanAuditPeriod":             {Type: cfgTypeInt, IntVal: &OrphanAuditPeriod, IntDefault: 60},                                  // Orphan resource audit period
+		"ingressClass":                  {Type: cfgTypeString, StringVal: &IngressClass, StringDefault: ""},                              // Ingress class
+		"ingressHost":                   {Type: cfgTypeString, StringVal: &IngressHost, StringDefault: ""},                               // Ingress host
+		"gitImage":                      {Type: cfgTypeString, StringVal: &GitImage, StringDefault: "docker.io/alpine/git:1.0.30"},       // Git image
+		"gitCheckoutStepCPURequest":     {Type: cfgTypeString, StringVal: &GitCheckoutStepCPURequest, StringDefault: "30m"},              // Git checkout step CPU request
+		"gitCheckoutStepMemRequest":     {Type: cfgTypeString, StringVal: &GitCheckoutStepMemRequest, StringDefault: "100Mi"},            // Git checkout step Memory request
+		"artifactImage":                 {Type: cfgTypeString, StringVal: &ArtifactImage, StringDefault: "docker.io/rclone/rclone:1.57"}, // Artifact upload image
+		"testReportImage":               {Type: cfgTypeString, StringVal: &TestReportImage, StringDefault: "docker.io/alpine:3.15"},      // Test report/coverage image
+		"stuckJobTimeout":               {Type: cfgTypeInt, IntVal: &StuckJobTimeout, IntDefault: 30},                                    // Stuck IntegrationJob timeout
+		"jobRetryBackoff":               {Type: cfgTypeInt, IntVal: &JobRetryBackoff, IntDefault: 10},                                    // Delay before the first retry of a job
+		"commitStatusTargetUrlTemplate": {Type: cfgTypeString, StringVal: &CommitStatusTargetURLTemplate},                                // Target url template for commit statuses
+		"duplicateTriggerWindow":        {Type: cfgTypeInt, IntVal: &DuplicateTriggerWindow, IntDefault: 60},                             // Duplicate trigger window
+		"capacityAwareAdmission":        {Type: cfgTypeBool, BoolVal: &CapacityAwareAdmission, BoolDefault: false},                       // Cluster-capacity-aware admission
+		"namespaceMaxPipelineRun":       {Type: cfgTypeString, StringVal: &namespaceMaxPipelineRun},                                      // Max PipelineRun count of each namespace
+		"priorityAgingInterval":         {Type: cfgTypeInt, IntVal: &PriorityAgingInterval, IntDefault: 0},                               // Priority aging interval
+		"schedulerPolicy":               {Type: cfgTypeString, StringVal: &SchedulerPolicy, StringDefault: "priority"},                   // Scheduling policy
 	})
 
+	// Parse the namespaces' max PipelineRun counts
+	limits, err := parseNamespaceMaxPipelineRun(namespaceMaxPipelineRun)
+	if err != nil {
+		return err
+	}
+	NamespaceMaxPipelineRun = limits
+
 	// Check SMTP config.s
 	if EnableMail && (SMTPHost == "" || SMTPUserSecret == "") {
 		return fmt.Errorf("email is enaled but smtp access info. is not given")
@@ -83,6 +103,24 @@ var (
 	// MaxPipelineRun is the number of PipelineRuns that can run simultaneously
 	MaxPipelineRun int
 
+	// NamespaceMaxPipelineRun is the number of PipelineRuns that can run simultaneously in each namespace. The key "*"
+	// is for the namespaces not specified. Namespaces without the limit are only limited by MaxPipelineRun
+	NamespaceMaxPipelineRun map[string]int
+
+	// namespaceMaxPipelineRun is a raw config value of NamespaceMaxPipelineRun, formatted as <namespace>=<count>,...
+	namespaceMaxPipelineRun string
+
+	// PriorityAgingInterval is an interval (in minute) by which the pending IntegrationJobs' effective priorities are
+	// raised by one while they are waiting. It is disabled if it is 0
+	PriorityAgingInterval int
+
+	// SchedulerPolicy is a policy for scheduling the pending IntegrationJobs (priority/fairShare)
+	SchedulerPolicy string
+
+	// CapacityAwareAdmission is whether to delay creating PipelineRuns until the cluster (i.e., the nodes and the
+	// ResourceQuotas of the namespace) has enough free resources for them
+	CapacityAwareAdmission bool
+
 	// ExternalHostName to be used for webhook server (default is ingress host name)
 	ExternalHostName string
 
@@ -93,6 +131,9 @@ var (
 	// ReportRedirectURITemplate is a uri template for report page redirection
 	ReportRedirectURITemplate string
 
+	// CommitStatusTargetURLTemplate is a url template for the target url of the jobs' commit statuses
+	CommitStatusTargetURLTemplate string
+
 	// CollectPeriod is a garbage collection period (in hour)
 	CollectPeriod int
 
@@ -100,6 +141,10 @@ var (
 	// If IntegrationJob's .status.completionTime + TTL < now, it's collected
 	IntegrationJobTTL int
 
+	// OrphanAuditPeriod is a period (in minute) of the audit deleting PipelineRuns, Secrets and ServiceAccounts whose
+	// owner IntegrationConfigs or IntegrationJobs do not exist anymore. It is disabled if it is 0
+	OrphanAuditPeriod int
+
 	// EnableMail is whether to enable mail feature or not
 	EnableMail bool
 
@@ -126,4 +171,52 @@ var (
 
 	// GitCheckoutStepMemRequest is a memory request of a git checkout step
 	GitCheckoutStepMemRequest string
+
+	// ArtifactImage is an image url for the artifact upload step. It should have rclone installed
+	ArtifactImage string
+
+	// TestReportImage is an image url for the test report and coverage steps. It should have sh and awk installed
+	TestReportImage string
+
+	// StuckJobTimeout is a duration (in minute) after which a running IntegrationJob is failed, if its PipelineRun or a
+	// job has been pending or a job with a missing pod has made no progress. It is disabled if it is 0
+	StuckJobTimeout int
+
+	// JobRetryBackoff is a delay (in second) before the first retry of a failed job. It's doubled for each retry, up to
+	// 5 minutes. The retries are not delayed if it is 0
+	JobRetryBackoff int
+
+	// DuplicateTriggerWindow is a duration (in second) within which the jobs triggered again for the same commits are
+	// coalesced into the existing IntegrationJob. It is disabled if it is 0
+	DuplicateTriggerWindow int
 )
+
+// GetNamespaceMaxPipelineRun returns the number of PipelineRuns that can run simultaneously in the namespace
+// Zero means there is no limit for the namespace
+func GetNamespaceMaxPipelineRun(namespace string) int {
+	if limit, exist := NamespaceMaxPipelineRun[namespace]; exist {
+		return limit
+	}
+	return NamespaceMaxPipelineRun["*"]
+}
+
+// parseNamespaceMaxPipelineRun parses <namespace>=<count>,... into a map
+func parseNamespaceMaxPipelineRun(value string) (map[string]int, error) {
+	limits := map[string]int{}
+	for _, token := range strings.Split(value, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		kv := strings.SplitN(token, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("namespaceMaxPipelineRun %s is not in <namespace>=<count> format", token)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("namespaceMaxPipelineRun %s does not have a non-negative count", token)
+		}
+		limits[strings.TrimSpace(kv[0])] = limit
+	}
+	return limits, nil
+}
@@ -47,6 +47,27 @@ func TestApplyControllerConfigChange(t *testing.T) {
 			require.Equal(t, 120, IntegrationJobTTL)
 			require.Equal(t, "", IngressClass)
 			require.Equal(t, "", IngressHost)
+			require.Empty(t, NamespaceMaxPipelineRun)
+		}},
+		"namespaceMaxPipelineRun": {ConfigMap: &corev1.ConfigMap{
+			Data: map[string]string{
+				"namespaceMaxPipelineRun": "*=2, team-a=5,team-b=0",
+			},
+		}, AssertFunc: func(t *testing.T, err error) {
+			require.NoError(t, err)
+
+			require.Equal(t, map[string]int{"*": 2, "team-a": 5, "team-b": 0}, NamespaceMaxPipelineRun)
+			require.Equal(t, 5, GetNamespaceMaxPipelineRun("team-a"))
+			require.Equal(t, 0, GetNamespaceMaxPipelineRun("team-b"))
+			require.Equal(t, 2, GetNamespaceMaxPipelineRun("team-c"))
+		}},
+		"namespaceMaxPipelineRunError": {ConfigMap: &corev1.ConfigMap{
+			Data: map[string]string{
+				"namespaceMaxPipelineRun": "team-a=five",
+			},
+		}, AssertFunc: func(t *testing.T, err error) {
+			require.Error(t, err)
+			require.Equal(t, "namespaceMaxPipelineRun team-a=five does not have a non-negative count", err.Error())
 		}},
 		"noError": {ConfigMap: &corev1.ConfigMap{
 			Data: map[string]string{
@@ -103,6 +124,7 @@ func TestApplyControllerConfigChange(t *testing.T) {
 			IntegrationJobTTL = 0
 			IngressClass = ""
 			IngressHost = ""
+			NamespaceMaxPipelineRun = nil
 
 			ch := make(chan struct{}, 1)
 			controllerConfigUpdateChan = append(controllerConfigUpdateChan, ch)
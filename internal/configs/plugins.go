@@ -16,7 +16,11 @@
 
 package configs
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
 
 // ConfigMapNamePluginConfig is a name of plugin config map
 const (
@@ -31,10 +35,28 @@ func ApplyPluginConfigChange(cm *corev1.ConfigMap) error {
 		"sizeL":   {Type: cfgTypeInt, IntVal: &PluginSizeL, IntDefault: 100},
 		"sizeXL":  {Type: cfgTypeInt, IntVal: &PluginSizeXL, IntDefault: 500},
 		"sizeXXL": {Type: cfgTypeInt, IntVal: &PluginSizeXXL, IntDefault: 1000},
+
+		"labelAllowlist": {Type: cfgTypeString, StringVal: &pluginLabelAllowlist},
+
+		"welcomeMessage": {Type: cfgTypeString, StringVal: &PluginWelcomeMessage, StringDefault: ""},
+
+		"staleSyncPeriod": {Type: cfgTypeInt, IntVal: &PluginStaleSyncPeriod, IntDefault: 60},
 	})
+
+	PluginLabelAllowlist = parseLabelAllowlist(pluginLabelAllowlist)
 	return nil
 }
 
+func parseLabelAllowlist(raw string) []string {
+	var labels []string
+	for _, token := range strings.Split(raw, ",") {
+		if l := strings.TrimSpace(token); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
 // Configs for Size plugin
 var (
 	PluginSizeS   = 10
@@ -43,3 +65,26 @@ var (
 	PluginSizeXL  = 500
 	PluginSizeXXL = 1000
 )
+
+// Configs for Label plugin
+var (
+	// PluginLabelAllowlist is a list of the labels which can be added/removed by the label plugin. Any label can be
+	// added/removed if it's empty
+	PluginLabelAllowlist []string
+
+	// pluginLabelAllowlist is a raw config value of PluginLabelAllowlist, formatted as <label>,<label>,...
+	pluginLabelAllowlist string
+)
+
+// Configs for Welcome plugin
+var (
+	// PluginWelcomeMessage is a template of the comment posted to the first pull request of a contributor. The welcome
+	// plugin is disabled if it's empty, which is the default
+	PluginWelcomeMessage string
+)
+
+// Configs for Stale plugin
+var (
+	// PluginStaleSyncPeriod is a period of synchronizing the stale pull requests in minute
+	PluginStaleSyncPeriod = 60
+)
@@ -33,6 +33,7 @@ func TestApplyPluginConfigChange(t *testing.T) {
 			require.Equal(t, 100, PluginSizeL)
 			require.Equal(t, 500, PluginSizeXL)
 			require.Equal(t, 1000, PluginSizeXXL)
+			require.Empty(t, PluginLabelAllowlist)
 		}},
 		"normal": {ConfigMap: &corev1.ConfigMap{
 			Data: map[string]string{
@@ -41,6 +42,8 @@ func TestApplyPluginConfigChange(t *testing.T) {
 				"sizeL":   "300",
 				"sizeXL":  "500",
 				"sizeXXL": "800",
+
+				"labelAllowlist": "kind/bug, kind/feature,,area/ci",
 			},
 		}, AssertFunc: func(t *testing.T, err error) {
 			require.NoError(t, err)
@@ -49,6 +52,7 @@ func TestApplyPluginConfigChange(t *testing.T) {
 			require.Equal(t, 300, PluginSizeL)
 			require.Equal(t, 500, PluginSizeXL)
 			require.Equal(t, 800, PluginSizeXXL)
+			require.Equal(t, []string{"kind/bug", "kind/feature", "area/ci"}, PluginLabelAllowlist)
 		}},
 	}
 
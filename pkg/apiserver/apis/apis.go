@@ -25,6 +25,7 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	v1 "github.com/tmax-cloud/cicd-operator/pkg/apiserver/apis/v1"
 	authorization "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,7 +38,7 @@ type handler struct {
 }
 
 // NewHandler instantiates a new apis handler
-func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authorization.AuthorizationV1Interface, logger logr.Logger) (apiserver.APIHandler, error) {
+func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authorization.AuthorizationV1Interface, podsGetter typedcorev1.PodsGetter, logger logr.Logger) (apiserver.APIHandler, error) {
 	handler := &handler{}
 
 	//apis
@@ -47,7 +48,7 @@ func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authori
 	}
 
 	// /apis/v1
-	v1Handler, err := v1.NewHandler(apiWrapper, cli, authCli, logger)
+	v1Handler, err := v1.NewHandler(apiWrapper, cli, authCli, podsGetter, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -31,13 +31,13 @@ func TestNewHandler(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		p := wrapper.New("/", nil, nil)
 		p.SetRouter(mux.NewRouter())
-		_, err := NewHandler(p, nil, nil, nil)
+		_, err := NewHandler(p, nil, nil, nil, nil)
 		require.NoError(t, err)
 	})
 
 	t.Run("apisErr", func(t *testing.T) {
 		p := wrapper.New("/", nil, nil)
-		_, err := NewHandler(p, nil, nil, nil)
+		_, err := NewHandler(p, nil, nil, nil, nil)
 		require.Error(t, err)
 		require.Equal(t, "parent does not have a router", err.Error())
 	})
@@ -74,5 +74,11 @@ func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authori
 		return nil, err
 	}
 
+	// /integrationconfigs/<integrationconfig>/mergepool
+	mergePoolWrapper := wrapper.New("/"+cicdv1.IntegrationConfigAPIMergePool, []string{http.MethodGet}, handler.mergePoolHandler)
+	if err := icWrapper.Add(mergePoolWrapper); err != nil {
+		return nil, err
+	}
+
 	return handler, nil
 }
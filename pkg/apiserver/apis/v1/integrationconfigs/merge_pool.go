@@ -0,0 +1,96 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationconfigs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/blocker"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// blockerStatusURL is an address of the blocker's status server
+var blockerStatusURL = fmt.Sprintf("http://blocker.%s:%d", utils.Namespace(), blocker.StatusPort)
+
+var blockerClient = &http.Client{Timeout: 10 * time.Second}
+
+func (h *handler) mergePoolHandler(w http.ResponseWriter, req *http.Request) {
+	reqID := utils.RandomString(10)
+	log := h.log.WithValues("request", reqID)
+
+	// Get ns/resource name
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars[apiserver.NamespaceParamKey]
+	resName, nameExist := vars[icParamKey]
+	if !nsExist || !nameExist {
+		log.Info("url is malformed")
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	// Get IntegrationConfig
+	ic := &cicdv1.IntegrationConfig{}
+	if err := h.k8sClient.Get(context.Background(), types.NamespacedName{Name: resName, Namespace: ns}, ic); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot get IntegrationConfig %s/%s", reqID, ns, resName))
+		return
+	}
+	if ic.Spec.MergeConfig == nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, IntegrationConfig %s/%s has no mergeConfig", reqID, ns, resName))
+		return
+	}
+
+	// Get the merge pools from the blocker
+	resp, err := blockerClient.Get(fmt.Sprintf("%s/mergepools/%s/%s", blockerStatusURL, ns, resName))
+	if err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot get merge pools from blocker", reqID))
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// The pools are not made yet, until the blocker synchronizes the PRs
+	if resp.StatusCode == http.StatusNotFound {
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("req: %s, merge pools of IntegrationConfig %s/%s are not synchronized yet", reqID, ns, resName))
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Info(fmt.Sprintf("blocker responded %d", resp.StatusCode))
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot get merge pools from blocker", reqID))
+		return
+	}
+
+	var pools []cicdv1.MergePoolStatus
+	if err := json.NewDecoder(resp.Body).Decode(&pools); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot decode merge pools", reqID))
+		return
+	}
+
+	_ = utils.RespondJSON(w, pools)
+}
@@ -0,0 +1,122 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationconfigs
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_handler_mergePoolHandler(t *testing.T) {
+	blockerSrv := httptest.NewServer(func() http.Handler {
+		router := mux.NewRouter()
+		router.HandleFunc("/mergepools/test-ns/test-ic", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"repository":"test-repo","pullRequests":[{"id":3,"title":"test","author":"user","base":"master","sha":"sha","inMergePool":true,"status":"success","description":"In merge pool.","queuePosition":1}]}]`))
+		})
+		router.HandleFunc("/mergepools/test-ns/{name}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		return router
+	}())
+	defer blockerSrv.Close()
+	blockerStatusURL = blockerSrv.URL
+
+	mergeIC := func(name string) *cicdv1.IntegrationConfig {
+		return &cicdv1.IntegrationConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec: cicdv1.IntegrationConfigSpec{
+				Git:         cicdv1.GitConfig{Type: cicdv1.GitTypeGitHub, Repository: "test-repo"},
+				MergeConfig: &cicdv1.MergeConfig{},
+			},
+		}
+	}
+
+	tc := map[string]struct {
+		ic   *cicdv1.IntegrationConfig
+		vars map[string]string
+
+		expectedCode    int
+		expectedMessage string
+	}{
+		"normal": {
+			ic:              mergeIC("test-ic"),
+			vars:            map[string]string{"namespace": "test-ns", "icName": "test-ic"},
+			expectedCode:    200,
+			expectedMessage: `[{"repository":"test-repo","pullRequests":[{"id":3,"title":"test","author":"user","base":"master","sha":"sha","inMergePool":true,"status":"success","description":"In merge pool.","queuePosition":1}]}]`,
+		},
+		"noVars": {
+			ic:              mergeIC("test-ic"),
+			expectedCode:    400,
+			expectedMessage: "url is malformed",
+		},
+		"icGetErr": {
+			vars:            map[string]string{"namespace": "test-ns", "icName": "test-ic"},
+			expectedCode:    500,
+			expectedMessage: "cannot get IntegrationConfig test-ns/test-ic",
+		},
+		"noMergeConfig": {
+			ic: &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+				Spec:       cicdv1.IntegrationConfigSpec{Git: cicdv1.GitConfig{Type: cicdv1.GitTypeGitHub, Repository: "test-repo"}},
+			},
+			vars:            map[string]string{"namespace": "test-ns", "icName": "test-ic"},
+			expectedCode:    400,
+			expectedMessage: "IntegrationConfig test-ns/test-ic has no mergeConfig",
+		},
+		"notSynced": {
+			ic:              mergeIC("new-ic"),
+			vars:            map[string]string{"namespace": "test-ns", "icName": "new-ic"},
+			expectedCode:    404,
+			expectedMessage: "merge pools of IntegrationConfig test-ns/new-ic are not synchronized yet",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			require.NoError(t, cicdv1.AddToScheme(s))
+
+			fakeCli := fake.NewClientBuilder().WithScheme(s).Build()
+			if c.ic != nil {
+				require.NoError(t, fakeCli.Create(context.Background(), c.ic))
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = mux.SetURLVars(req, c.vars)
+
+			handler := &handler{log: &test.FakeLogger{}, k8sClient: fakeCli}
+			handler.mergePoolHandler(w, req)
+
+			require.Equal(t, c.expectedCode, w.Result().StatusCode)
+			b, err := ioutil.ReadAll(w.Result().Body)
+			require.NoError(t, err)
+			require.Contains(t, string(b), c.expectedMessage)
+		})
+	}
+}
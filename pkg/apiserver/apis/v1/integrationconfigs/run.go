@@ -28,6 +28,7 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"github.com/tmax-cloud/cicd-operator/pkg/server"
 	"k8s.io/apimachinery/pkg/types"
@@ -93,28 +94,51 @@ func (h *handler) runHandler(w http.ResponseWriter, req *http.Request, et git.Ev
 		},
 	}
 
+	var params []cicdv1.ParameterValue
+	failedOnly := false
 	switch et {
 	case git.EventTypePullRequest:
-		pr, err := buildPullRequestWebhook(req.Body, userEscaped)
+		pr, userReq, err := buildPullRequestWebhook(req.Body, userEscaped)
 		if err != nil {
 			log.Info(err.Error())
 			_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, cannot build pull_request webhook", reqID))
 			return
 		}
 		wh.PullRequest = pr
+		params = userReq.Params
+		failedOnly = userReq.FailedOnly
 	case git.EventTypePush:
-		push, err := buildPushWebhook(req.Body)
+		push, pushParams, err := buildPushWebhook(req.Body)
 		if err != nil {
 			log.Info(err.Error())
 			_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, cannot build push webhook", reqID))
 			return
 		}
 		wh.Push = push
+		params = pushParams
 	}
 	wh.Sender = git.User{
 		Name: fmt.Sprintf("trigger-%s-end", userEscaped),
 	}
 
+	// Override parameter values
+	if err := overrideParams(ic, params); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, cannot override parameters, err : %s", reqID, err.Error()))
+		return
+	}
+
+	// Retest only the failed jobs
+	if failedOnly {
+		if err := h.retestFailed(wh, ic); err != nil {
+			log.Info(err.Error())
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot retest failed jobs, err : %s", reqID, err.Error()))
+			return
+		}
+		_ = utils.RespondJSON(w, struct{}{})
+		return
+	}
+
 	// Trigger Run!
 	if err := server.HandleEvent(wh, ic, "dispatcher"); err != nil {
 		log.Info(err.Error())
@@ -125,20 +149,41 @@ func (h *handler) runHandler(w http.ResponseWriter, req *http.Request, et git.Ev
 	_ = utils.RespondJSON(w, struct{}{})
 }
 
-func buildPullRequestWebhook(body io.Reader, user string) (*git.PullRequest, error) {
+// retestFailed creates an IntegrationJob running only the jobs failed for the pull request's head commit
+func (h *handler) retestFailed(wh *git.Webhook, ic *cicdv1.IntegrationConfig) error {
+	gitCli, err := utils.GetGitCli(ic, h.k8sClient)
+	if err != nil {
+		return err
+	}
+	config, err := dispatcher.LoadConfigFile(ic, gitCli, wh.PullRequest.Head.Sha)
+	if err != nil {
+		return err
+	}
+	_, err = dispatcher.RetestFailed(h.k8sClient, gitCli, wh.PullRequest, &wh.Repo, &wh.Sender, config)
+	return err
+}
+
+func buildPullRequestWebhook(body io.Reader, user string) (*git.PullRequest, *cicdv1.IntegrationConfigAPIReqRunPreBody, error) {
 	userReq := &cicdv1.IntegrationConfigAPIReqRunPreBody{}
 	decoder := json.NewDecoder(body)
 	if err := decoder.Decode(userReq); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	baseBranch := userReq.BaseBranch
 	headBranch := userReq.HeadBranch
+	headSha := userReq.HeadSha
 	if baseBranch == "" {
 		baseBranch = defaultBranch
 	}
 	if headBranch == "" {
-		return nil, fmt.Errorf("head_branch must be set")
+		return nil, nil, fmt.Errorf("head_branch must be set")
+	}
+	if headSha == "" {
+		if userReq.FailedOnly {
+			return nil, nil, fmt.Errorf("head_sha must be set to run only the failed jobs")
+		}
+		headSha = git.FakeSha
 	}
 
 	return &git.PullRequest{
@@ -153,25 +198,69 @@ func buildPullRequestWebhook(body io.Reader, user string) (*git.PullRequest, err
 		},
 		Head: git.Head{
 			Ref: headBranch,
-			Sha: git.FakeSha,
+			Sha: headSha,
 		},
-	}, nil
+	}, userReq, nil
 }
 
-func buildPushWebhook(body io.Reader) (*git.Push, error) {
+func buildPushWebhook(body io.Reader) (*git.Push, []cicdv1.ParameterValue, error) {
 	userReq := &cicdv1.IntegrationConfigAPIReqRunPostBody{}
 	decoder := json.NewDecoder(body)
 	if err := decoder.Decode(userReq); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	branch := userReq.Branch
+	sha := userReq.Sha
 	if branch == "" {
 		branch = defaultBranch
 	}
+	if sha == "" {
+		sha = git.FakeSha
+	}
 
 	return &git.Push{
 		Ref: branch,
-		Sha: git.FakeSha,
-	}, nil
+		Sha: sha,
+	}, userReq.Params, nil
+}
+
+// overrideParams overrides IntegrationConfig's parameter values with the user-supplied values
+// Only the parameters defined in the paramDefine can be overridden, with the values matching their types and enums
+func overrideParams(ic *cicdv1.IntegrationConfig, params []cicdv1.ParameterValue) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if ic.Spec.ParamConfig == nil {
+		ic.Spec.ParamConfig = &cicdv1.ParameterConfig{}
+	}
+
+	defined := map[string]cicdv1.ParameterDefine{}
+	for _, d := range ic.Spec.ParamConfig.ParamDefine {
+		defined[d.Name] = d
+	}
+
+	for _, param := range params {
+		d, exist := defined[param.Name]
+		if !exist {
+			return fmt.Errorf("parameter %s is not defined", param.Name)
+		}
+		if err := d.ValidateValue(param); err != nil {
+			return err
+		}
+
+		overridden := false
+		for i, v := range ic.Spec.ParamConfig.ParamValue {
+			if v.Name == param.Name {
+				ic.Spec.ParamConfig.ParamValue[i] = param
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			ic.Spec.ParamConfig.ParamValue = append(ic.Spec.ParamConfig.ParamValue, param)
+		}
+	}
+	return nil
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/test"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"github.com/tmax-cloud/cicd-operator/pkg/server"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -151,6 +152,33 @@ func Test_handler_runHandler(t *testing.T) {
 			expectedCode:    200,
 			expectedMessage: "{}",
 		},
+		"prFailedOnlyErr": {
+			event: git.EventTypePullRequest,
+			body:  bytes.NewBuffer([]byte(`{"base_branch": "master", "head_branch": "feat/test", "head_sha": "ed1d7e2d3f1a", "failed_only": true}`)),
+			vars: map[string]string{
+				"namespace": "test-ns",
+				"icName":    "test-ic",
+			},
+			header: map[string][]string{
+				"X-Remote-User":  {"test-user"},
+				"X-Remote-Group": {"test-group"},
+			},
+			ic: &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+				Spec: cicdv1.IntegrationConfigSpec{
+					Git: cicdv1.GitConfig{
+						Type:       "fake",
+						APIUrl:     "https://test.git.com",
+						Repository: "test/no-repo",
+					},
+					Jobs: cicdv1.IntegrationConfigJobs{
+						PreSubmit: []cicdv1.Job{{Container: corev1.Container{Name: "test"}}},
+					},
+				},
+			},
+			expectedCode:    500,
+			expectedMessage: "cannot retest failed jobs",
+		},
 		"push": {
 			event: git.EventTypePush,
 			body:  bytes.NewBuffer([]byte(`{"branch": "master"}`)),
@@ -301,6 +329,30 @@ func Test_handler_runHandler(t *testing.T) {
 			expectedCode:    400,
 			expectedMessage: "cannot build push webhook",
 		},
+		"paramErr": {
+			event: git.EventTypePush,
+			body:  bytes.NewBuffer([]byte(`{"branch": "master", "params": [{"name": "undefined", "stringVal": "v"}]}`)),
+			vars: map[string]string{
+				"namespace": "test-ns",
+				"icName":    "test-ic",
+			},
+			header: map[string][]string{
+				"X-Remote-User":  {"test-user"},
+				"X-Remote-Group": {"test-group"},
+			},
+			ic: &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "test-ns"},
+				Spec: cicdv1.IntegrationConfigSpec{
+					Git: cicdv1.GitConfig{
+						Type:       "fake",
+						APIUrl:     "https://test.git.com",
+						Repository: "test/test",
+					},
+				},
+			},
+			expectedCode:    400,
+			expectedMessage: "cannot override parameters, err : parameter undefined is not defined",
+		},
 		"triggerErr": {
 			event: git.EventTypePush,
 			body:  bytes.NewBuffer([]byte(`{"branch": "master"}`)),
@@ -368,9 +420,10 @@ func Test_buildPullRequestWebhook(t *testing.T) {
 	tc := map[string]struct {
 		body io.Reader
 
-		errorOccurs  bool
-		errorMessage string
-		expectedPR   *git.PullRequest
+		errorOccurs    bool
+		errorMessage   string
+		expectedPR     *git.PullRequest
+		expectedParams []cicdv1.ParameterValue
 	}{
 		"normal": {
 			body: bytes.NewBuffer([]byte(`{"base_branch": "master", "head_branch": "feat/test"}`)),
@@ -418,17 +471,42 @@ func Test_buildPullRequestWebhook(t *testing.T) {
 			errorOccurs:  true,
 			errorMessage: "head_branch must be set",
 		},
+		"failedOnlyNoSha": {
+			body:         bytes.NewBuffer([]byte(`{"head_branch": "feat/test", "failed_only": true}`)),
+			errorOccurs:  true,
+			errorMessage: "head_sha must be set to run only the failed jobs",
+		},
+		"shaAndParams": {
+			body: bytes.NewBuffer([]byte(`{"head_branch": "feat/test", "head_sha": "ed1d7e2d3f1a", "params": [{"name": "p", "stringVal": "v"}]}`)),
+			expectedPR: &git.PullRequest{
+				State:  git.PullRequestStateOpen,
+				Action: git.PullRequestActionOpen,
+				Author: git.User{
+					Name: "trigger-test-user-end",
+				},
+				Base: git.Base{
+					Ref: "master",
+					Sha: git.FakeSha,
+				},
+				Head: git.Head{
+					Ref: "feat/test",
+					Sha: "ed1d7e2d3f1a",
+				},
+			},
+			expectedParams: []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}},
+		},
 	}
 
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
-			pr, err := buildPullRequestWebhook(c.body, "test-user")
+			pr, userReq, err := buildPullRequestWebhook(c.body, "test-user")
 			if c.errorOccurs {
 				require.Error(t, err)
 				require.Equal(t, c.errorMessage, err.Error())
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, c.expectedPR, pr)
+				require.Equal(t, c.expectedParams, userReq.Params)
 			}
 		})
 	}
@@ -438,9 +516,10 @@ func Test_buildPushWebhook(t *testing.T) {
 	tc := map[string]struct {
 		body io.Reader
 
-		errorOccurs  bool
-		errorMessage string
-		expectedPush *git.Push
+		errorOccurs    bool
+		errorMessage   string
+		expectedPush   *git.Push
+		expectedParams []cicdv1.ParameterValue
 	}{
 		"normal": {
 			body: bytes.NewBuffer([]byte(`{"branch": "master"}`)),
@@ -461,17 +540,84 @@ func Test_buildPushWebhook(t *testing.T) {
 				Sha: "0000000000000000000000000000000000000000",
 			},
 		},
+		"shaAndParams": {
+			body: bytes.NewBuffer([]byte(`{"branch": "main", "sha": "ed1d7e2d3f1a", "params": [{"name": "p", "arrayVal": ["a", "b"]}]}`)),
+			expectedPush: &git.Push{
+				Ref: "main",
+				Sha: "ed1d7e2d3f1a",
+			},
+			expectedParams: []cicdv1.ParameterValue{{Name: "p", ArrayVal: []string{"a", "b"}}},
+		},
 	}
 
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
-			push, err := buildPushWebhook(c.body)
+			push, params, err := buildPushWebhook(c.body)
 			if c.errorOccurs {
 				require.Error(t, err)
 				require.Equal(t, c.errorMessage, err.Error())
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, c.expectedPush, push)
+				require.Equal(t, c.expectedParams, params)
+			}
+		})
+	}
+}
+
+func Test_overrideParams(t *testing.T) {
+	tc := map[string]struct {
+		paramConfig *cicdv1.ParameterConfig
+		params      []cicdv1.ParameterValue
+
+		errorOccurs         bool
+		errorMessage        string
+		expectedParamConfig *cicdv1.ParameterConfig
+	}{
+		"noParams": {
+			paramConfig:         &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}}},
+			expectedParamConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}}},
+		},
+		"override": {
+			paramConfig: &cicdv1.ParameterConfig{
+				ParamDefine: []cicdv1.ParameterDefine{{Name: "p"}, {Name: "q"}},
+				ParamValue:  []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}},
+			},
+			params: []cicdv1.ParameterValue{{Name: "p", StringVal: "overridden"}, {Name: "q", ArrayVal: []string{"a"}}},
+			expectedParamConfig: &cicdv1.ParameterConfig{
+				ParamDefine: []cicdv1.ParameterDefine{{Name: "p"}, {Name: "q"}},
+				ParamValue:  []cicdv1.ParameterValue{{Name: "p", StringVal: "overridden"}, {Name: "q", ArrayVal: []string{"a"}}},
+			},
+		},
+		"notDefined": {
+			params:       []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}},
+			errorOccurs:  true,
+			errorMessage: "parameter p is not defined",
+		},
+		"invalidType": {
+			paramConfig:  &cicdv1.ParameterConfig{ParamDefine: []cicdv1.ParameterDefine{{Name: "p", Type: cicdv1.ParameterTypeBoolean}}},
+			params:       []cicdv1.ParameterValue{{Name: "p", StringVal: "v"}},
+			errorOccurs:  true,
+			errorMessage: `parameter p should be a boolean (true or false), but got "v"`,
+		},
+		"invalidEnum": {
+			paramConfig:  &cicdv1.ParameterConfig{ParamDefine: []cicdv1.ParameterDefine{{Name: "p", Type: cicdv1.ParameterTypeString, Enum: []string{"dev", "prod"}}}},
+			params:       []cicdv1.ParameterValue{{Name: "p", StringVal: "test"}},
+			errorOccurs:  true,
+			errorMessage: `parameter p should be one of [dev, prod], but got "test"`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{ParamConfig: c.paramConfig}}
+			err := overrideParams(ic, c.params)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expectedParamConfig, ic.Spec.ParamConfig)
 			}
 		})
 	}
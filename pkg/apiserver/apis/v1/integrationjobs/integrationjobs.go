@@ -0,0 +1,95 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
+	"github.com/tmax-cloud/cicd-operator/internal/wrapper"
+	authorization "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// APIVersion of the api
+	APIVersion = "v1"
+
+	ijParamKey = "ijName"
+)
+
+type handler struct {
+	k8sClient  client.Client
+	podsGetter typedcorev1.PodsGetter
+	log        logr.Logger
+
+	authorizer       apiserver.Authorizer
+	retryAuthorizer  apiserver.Authorizer
+	updateAuthorizer apiserver.Authorizer
+}
+
+// NewHandler instantiates a new integration jobs api handler
+func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authorization.AuthorizationV1Interface, podsGetter typedcorev1.PodsGetter, logger logr.Logger) (apiserver.APIHandler, error) {
+	handler := &handler{k8sClient: cli, podsGetter: podsGetter, log: logger}
+
+	// Authorizers. Retrying a job creates a new IntegrationJob and pausing one updates it, so they require different
+	// verbs from reading logs
+	handler.authorizer = apiserver.NewAuthorizer(authCli, apiserver.APIGroup, APIVersion, "get")
+	handler.retryAuthorizer = apiserver.NewAuthorizer(authCli, apiserver.APIGroup, APIVersion, "create")
+	handler.updateAuthorizer = apiserver.NewAuthorizer(authCli, apiserver.APIGroup, APIVersion, "update")
+
+	// /integrationjobs/<integrationjob>
+	ijWrapper := wrapper.New(fmt.Sprintf("/%s/{%s}", cicdv1.IntegrationJobKind, ijParamKey), nil, nil)
+	if err := parent.Add(ijWrapper); err != nil {
+		return nil, err
+	}
+
+	// /integrationjobs/<integrationjob>/log
+	logWrapper := wrapper.New("/"+cicdv1.IntegrationJobAPILog, []string{http.MethodGet}, authorized(handler.authorizer, handler.logHandler))
+	if err := ijWrapper.Add(logWrapper); err != nil {
+		return nil, err
+	}
+
+	// /integrationjobs/<integrationjob>/retry
+	retryWrapper := wrapper.New("/"+cicdv1.IntegrationJobAPIRetry, []string{http.MethodPost}, authorized(handler.retryAuthorizer, handler.retryHandler))
+	if err := ijWrapper.Add(retryWrapper); err != nil {
+		return nil, err
+	}
+
+	// /integrationjobs/<integrationjob>/pause
+	pauseWrapper := wrapper.New("/"+cicdv1.IntegrationJobAPIPause, []string{http.MethodPost}, authorized(handler.updateAuthorizer, handler.pauseHandler))
+	if err := ijWrapper.Add(pauseWrapper); err != nil {
+		return nil, err
+	}
+
+	// /integrationjobs/<integrationjob>/resume
+	resumeWrapper := wrapper.New("/"+cicdv1.IntegrationJobAPIResume, []string{http.MethodPost}, authorized(handler.updateAuthorizer, handler.resumeHandler))
+	if err := ijWrapper.Add(resumeWrapper); err != nil {
+		return nil, err
+	}
+
+	return handler, nil
+}
+
+// authorized wraps the handler function with the authorizer
+func authorized(authorizer apiserver.Authorizer, h http.HandlerFunc) http.HandlerFunc {
+	return authorizer.Authorize(h).ServeHTTP
+}
@@ -0,0 +1,209 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// tektonStepPrefix is a prefix of the containers' names of Tekton steps
+const tektonStepPrefix = "step-"
+
+// +kubebuilder:rbac:groups="",resources=pods;pods/log,verbs=get;list;watch
+
+// logHandler responds with the logs of the job's pod, i.e., the logs of all the steps in order or of a single step
+// If follow is set, the logs are streamed until the steps are terminated
+func (h *handler) logHandler(w http.ResponseWriter, req *http.Request) {
+	reqID := utils.RandomString(10)
+	log := h.log.WithValues("request", reqID)
+
+	// Get ns/resource name
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars[apiserver.NamespaceParamKey]
+	ijName, nameExist := vars[ijParamKey]
+	if !nsExist || !nameExist {
+		log.Info("url is malformed")
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	query := req.URL.Query()
+	jobName := query.Get(cicdv1.IntegrationJobAPILogParamJob)
+	if jobName == "" {
+		log.Info("job is not set")
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, query parameter %s must be set", reqID, cicdv1.IntegrationJobAPILogParamJob))
+		return
+	}
+	opts, err := parseLogOptions(query)
+	if err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, %s", reqID, err.Error()))
+		return
+	}
+
+	// Get IntegrationJob
+	ij := &cicdv1.IntegrationJob{}
+	if err := h.k8sClient.Get(context.Background(), types.NamespacedName{Name: ijName, Namespace: ns}, ij); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, errorCode(err), fmt.Sprintf("req: %s, cannot get IntegrationJob %s/%s", reqID, ns, ijName))
+		return
+	}
+
+	// Get the job's pod
+	podName := ""
+	jobExist := false
+	for _, j := range ij.Status.Jobs {
+		if j.Name == jobName {
+			podName = j.PodName
+			jobExist = true
+			break
+		}
+	}
+	if !jobExist {
+		log.Info(fmt.Sprintf("job %s does not exist", jobName))
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("req: %s, job %s does not exist in IntegrationJob %s/%s", reqID, jobName, ns, ijName))
+		return
+	}
+	if podName == "" {
+		log.Info(fmt.Sprintf("job %s does not have a pod", jobName))
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("req: %s, job %s does not have a pod yet", reqID, jobName))
+		return
+	}
+	podsGetter, err := remotecluster.PodsGetterFor(h.k8sClient, h.podsGetter, ij)
+	if err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot get the pods of IntegrationJob %s/%s: %s", reqID, ns, ijName, err.Error()))
+		return
+	}
+	pod, err := podsGetter.Pods(ns).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, errorCode(err), fmt.Sprintf("req: %s, cannot get pod %s/%s of job %s", reqID, ns, podName, jobName))
+		return
+	}
+
+	step := query.Get(cicdv1.IntegrationJobAPILogParamStep)
+	containers := selectContainers(pod, step)
+	if len(containers) == 0 {
+		log.Info(fmt.Sprintf("step %s does not exist", step))
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("req: %s, step %s does not exist in job %s", reqID, step, jobName))
+		return
+	}
+
+	// Stream the logs of the steps, in order
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fw := &flushWriter{w: w}
+	for _, c := range containers {
+		if len(containers) > 1 {
+			_, _ = fmt.Fprintf(fw, "# Step : %s\n", c)
+		}
+		containerOpts := opts.DeepCopy()
+		containerOpts.Container = c
+		if err := streamLog(req.Context(), fw, podsGetter, ns, podName, containerOpts); err != nil {
+			log.Info(err.Error())
+			_, _ = fmt.Fprintf(fw, "cannot get the log of %s: %s\n", c, err.Error())
+		}
+		if len(containers) > 1 {
+			_, _ = fmt.Fprint(fw, "\n")
+		}
+	}
+}
+
+// streamLog copies the log of the container to the writer
+func streamLog(ctx context.Context, w io.Writer, podsGetter typedcorev1.PodsGetter, ns, podName string, opts *corev1.PodLogOptions) error {
+	stream, err := podsGetter.Pods(ns).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	_, err = io.Copy(w, stream)
+	return err
+}
+
+// parseLogOptions parses follow and tailLines query parameters
+func parseLogOptions(query url.Values) (*corev1.PodLogOptions, error) {
+	opts := &corev1.PodLogOptions{}
+	if follow := query.Get(cicdv1.IntegrationJobAPILogParamFollow); follow != "" {
+		f, err := strconv.ParseBool(follow)
+		if err != nil {
+			return nil, fmt.Errorf("query parameter %s should be a boolean", cicdv1.IntegrationJobAPILogParamFollow)
+		}
+		opts.Follow = f
+	}
+	if tailLines := query.Get(cicdv1.IntegrationJobAPILogParamTailLines); tailLines != "" {
+		n, err := strconv.ParseInt(tailLines, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("query parameter %s should be a non-negative integer", cicdv1.IntegrationJobAPILogParamTailLines)
+		}
+		opts.TailLines = &n
+	}
+	return opts, nil
+}
+
+// selectContainers returns the containers of the step, or all the step containers if the step is not specified
+// Steps can be specified either with their names or with their containers' names (i.e., step-<step name>)
+func selectContainers(pod *corev1.Pod, step string) []string {
+	var containers []string
+	for _, c := range pod.Spec.Containers {
+		if step == "" {
+			containers = append(containers, c.Name)
+		} else if c.Name == step || c.Name == tektonStepPrefix+step {
+			return []string{c.Name}
+		}
+	}
+	return containers
+}
+
+func errorCode(err error) int {
+	if errors.IsNotFound(err) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// flushWriter flushes every write, so that the followed logs are sent to the client immediately
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,178 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/test"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_handler_logHandler(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, cicdv1.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "test-ns"},
+		Status: cicdv1.IntegrationJobStatus{
+			Jobs: []cicdv1.JobStatus{
+				{Name: "test", PodName: "test-ij-test-pod"},
+				{Name: "pending"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij-test-pod", Namespace: "test-ns"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "step-git-clone"}, {Name: "step-step-0"}},
+		},
+	}
+	remoteIJ := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-ij", Namespace: "test-ns"},
+		Spec:       cicdv1.IntegrationJobSpec{RemoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"}},
+		Status: cicdv1.IntegrationJobStatus{
+			Jobs: []cicdv1.JobStatus{{Name: "test", PodName: "test-ij-test-pod"}},
+		},
+	}
+	vars := map[string]string{"namespace": "test-ns", "ijName": "test-ij"}
+
+	tc := map[string]struct {
+		vars  map[string]string
+		query string
+
+		expectedCode    int
+		expectedMessage string
+	}{
+		"allSteps": {
+			vars:            vars,
+			query:           "job=test",
+			expectedCode:    200,
+			expectedMessage: "# Step : step-git-clone\nfake logs\n# Step : step-step-0\nfake logs\n",
+		},
+		"step": {
+			vars:            vars,
+			query:           "job=test&step=git-clone&follow=true&tailLines=10",
+			expectedCode:    200,
+			expectedMessage: "fake logs",
+		},
+		"stepContainerName": {
+			vars:            vars,
+			query:           "job=test&step=step-step-0",
+			expectedCode:    200,
+			expectedMessage: "fake logs",
+		},
+		"noParam": {
+			query:           "job=test",
+			expectedCode:    400,
+			expectedMessage: "url is malformed",
+		},
+		"noJob": {
+			vars:            vars,
+			expectedCode:    400,
+			expectedMessage: "query parameter job must be set",
+		},
+		"invalidFollow": {
+			vars:            vars,
+			query:           "job=test&follow=maybe",
+			expectedCode:    400,
+			expectedMessage: "query parameter follow should be a boolean",
+		},
+		"invalidTailLines": {
+			vars:            vars,
+			query:           "job=test&tailLines=-1",
+			expectedCode:    400,
+			expectedMessage: "query parameter tailLines should be a non-negative integer",
+		},
+		"ijNotFound": {
+			vars:            map[string]string{"namespace": "test-ns", "ijName": "no-ij"},
+			query:           "job=test",
+			expectedCode:    404,
+			expectedMessage: "cannot get IntegrationJob test-ns/no-ij",
+		},
+		"jobNotFound": {
+			vars:            vars,
+			query:           "job=lint",
+			expectedCode:    404,
+			expectedMessage: "job lint does not exist in IntegrationJob test-ns/test-ij",
+		},
+		"noPod": {
+			vars:            vars,
+			query:           "job=pending",
+			expectedCode:    404,
+			expectedMessage: "job pending does not have a pod yet",
+		},
+		"stepNotFound": {
+			vars:            vars,
+			query:           "job=test&step=build",
+			expectedCode:    404,
+			expectedMessage: "step build does not exist in job test",
+		},
+		"remoteClusterNoKubeconfig": {
+			vars:            map[string]string{"namespace": "test-ns", "ijName": "remote-ij"},
+			query:           "job=test",
+			expectedCode:    500,
+			expectedMessage: "cannot get the pods of IntegrationJob test-ns/remote-ij: cannot get the kubeconfig of the remote cluster",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			h := &handler{
+				log:        &test.FakeLogger{},
+				k8sClient:  fake.NewClientBuilder().WithScheme(s).WithObjects(ij, remoteIJ).Build(),
+				podsGetter: k8sfake.NewSimpleClientset(pod).CoreV1(),
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/?"+c.query, nil)
+			req = mux.SetURLVars(req, c.vars)
+			h.logHandler(w, req)
+
+			require.Equal(t, c.expectedCode, w.Result().StatusCode)
+			b, err := ioutil.ReadAll(w.Result().Body)
+			require.NoError(t, err)
+			if c.expectedCode == 200 {
+				require.Equal(t, c.expectedMessage, string(b))
+			} else {
+				require.Contains(t, string(b), c.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestParseLogOptions(t *testing.T) {
+	opts, err := parseLogOptions(map[string][]string{"follow": {"true"}, "tailLines": {"100"}})
+	require.NoError(t, err)
+	tailLines := int64(100)
+	require.Equal(t, &corev1.PodLogOptions{Follow: true, TailLines: &tailLines}, opts)
+
+	opts, err = parseLogOptions(map[string][]string{})
+	require.NoError(t, err)
+	require.Equal(t, &corev1.PodLogOptions{}, opts)
+}
@@ -0,0 +1,93 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pauseHandler pauses the pending IntegrationJob, so that its PipelineRun is not created until it is resumed
+func (h *handler) pauseHandler(w http.ResponseWriter, req *http.Request) {
+	h.setPaused(w, req, true)
+}
+
+// resumeHandler resumes the paused IntegrationJob
+func (h *handler) resumeHandler(w http.ResponseWriter, req *http.Request) {
+	h.setPaused(w, req, false)
+}
+
+func (h *handler) setPaused(w http.ResponseWriter, req *http.Request, paused bool) {
+	reqID := utils.RandomString(10)
+	log := h.log.WithValues("request", reqID)
+
+	// Get ns/resource name
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars[apiserver.NamespaceParamKey]
+	ijName, nameExist := vars[ijParamKey]
+	if !nsExist || !nameExist {
+		log.Info("url is malformed")
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	// Get IntegrationJob
+	ij := &cicdv1.IntegrationJob{}
+	if err := h.k8sClient.Get(context.Background(), types.NamespacedName{Name: ijName, Namespace: ns}, ij); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, errorCode(err), fmt.Sprintf("req: %s, cannot get IntegrationJob %s/%s", reqID, ns, ijName))
+		return
+	}
+
+	// Only the pending IntegrationJobs can be paused, as the PipelineRuns cannot be suspended once they are created
+	if paused && ij.Status.State != "" && ij.Status.State != cicdv1.IntegrationJobStatePending {
+		log.Info("IntegrationJob is not pending")
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, IntegrationJob %s/%s is not pending", reqID, ns, ijName))
+		return
+	}
+
+	original := ij.DeepCopy()
+	ij.Spec.Paused = paused
+	if err := h.k8sClient.Patch(context.Background(), ij, client.MergeFrom(original)); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot patch IntegrationJob %s/%s", reqID, ns, ijName))
+		return
+	}
+
+	// Clear the paused reason, until the scheduler sets a new one
+	if !paused && ij.Status.Reason == cicdv1.IntegrationJobReasonPaused {
+		original := ij.DeepCopy()
+		ij.Status.Reason = ""
+		ij.Status.Message = ""
+		if err := h.k8sClient.Status().Patch(context.Background(), ij, client.MergeFrom(original)); err != nil {
+			log.Info(err.Error())
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("req: %s, cannot patch IntegrationJob %s/%s's status", reqID, ns, ijName))
+			return
+		}
+	}
+
+	_ = utils.RespondJSON(w, struct{}{})
+}
@@ -0,0 +1,129 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_handler_setPaused(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, cicdv1.AddToScheme(s))
+
+	tc := map[string]struct {
+		state  cicdv1.IntegrationJobState
+		reason cicdv1.IntegrationJobReason
+		paused bool
+		pause  bool
+		vars   map[string]string
+
+		expectedCode    int
+		expectedMessage string
+		expectedPaused  bool
+		expectedReason  cicdv1.IntegrationJobReason
+	}{
+		"pause": {
+			state:          cicdv1.IntegrationJobStatePending,
+			pause:          true,
+			expectedCode:   200,
+			expectedPaused: true,
+		},
+		"pauseRunning": {
+			state:           cicdv1.IntegrationJobStateRunning,
+			pause:           true,
+			expectedCode:    400,
+			expectedMessage: "IntegrationJob test-ns/test-ij is not pending",
+		},
+		"resume": {
+			state:          cicdv1.IntegrationJobStatePending,
+			reason:         cicdv1.IntegrationJobReasonPaused,
+			paused:         true,
+			expectedCode:   200,
+			expectedPaused: false,
+		},
+		"resumeConcurrencyLimited": {
+			state:          cicdv1.IntegrationJobStatePending,
+			reason:         cicdv1.IntegrationJobReasonConcurrencyLimited,
+			paused:         true,
+			expectedCode:   200,
+			expectedPaused: false,
+			expectedReason: cicdv1.IntegrationJobReasonConcurrencyLimited,
+		},
+		"ijNotFound": {
+			state:           cicdv1.IntegrationJobStatePending,
+			pause:           true,
+			vars:            map[string]string{"namespace": "test-ns", "ijName": "no-ij"},
+			expectedCode:    404,
+			expectedMessage: "cannot get IntegrationJob test-ns/no-ij",
+		},
+		"malformedURL": {
+			pause:           true,
+			vars:            map[string]string{"namespace": "test-ns"},
+			expectedCode:    400,
+			expectedMessage: "url is malformed",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "test-ns"},
+				Spec:       cicdv1.IntegrationJobSpec{Paused: c.paused},
+				Status:     cicdv1.IntegrationJobStatus{State: c.state, Reason: c.reason},
+			}
+			h := &handler{
+				log:       &test.FakeLogger{},
+				k8sClient: fake.NewClientBuilder().WithScheme(s).WithObjects(ij).Build(),
+			}
+			vars := c.vars
+			if vars == nil {
+				vars = map[string]string{"namespace": "test-ns", "ijName": "test-ij"}
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = mux.SetURLVars(req, vars)
+			h.setPaused(w, req, c.pause)
+
+			require.Equal(t, c.expectedCode, w.Result().StatusCode)
+			if c.expectedCode != 200 {
+				b, err := ioutil.ReadAll(w.Result().Body)
+				require.NoError(t, err)
+				require.Contains(t, string(b), c.expectedMessage)
+				return
+			}
+
+			result := &cicdv1.IntegrationJob{}
+			require.NoError(t, h.k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-ij", Namespace: "test-ns"}, result))
+			require.Equal(t, c.expectedPaused, result.Spec.Paused)
+			require.Equal(t, c.expectedReason, result.Status.Reason)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// retryHandler creates an IntegrationJob re-running only the failed job of the completed IntegrationJob
+// It responds with the created IntegrationJob
+func (h *handler) retryHandler(w http.ResponseWriter, req *http.Request) {
+	reqID := utils.RandomString(10)
+	log := h.log.WithValues("request", reqID)
+
+	// Get ns/resource name
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars[apiserver.NamespaceParamKey]
+	ijName, nameExist := vars[ijParamKey]
+	if !nsExist || !nameExist {
+		log.Info("url is malformed")
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	userReq := &cicdv1.IntegrationJobAPIReqRetryBody{}
+	if err := json.NewDecoder(req.Body).Decode(userReq); err != nil || userReq.Job == "" {
+		log.Info("job is not set")
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, job must be set", reqID))
+		return
+	}
+
+	// Get IntegrationJob
+	ij := &cicdv1.IntegrationJob{}
+	if err := h.k8sClient.Get(context.Background(), types.NamespacedName{Name: ijName, Namespace: ns}, ij); err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, errorCode(err), fmt.Sprintf("req: %s, cannot get IntegrationJob %s/%s", reqID, ns, ijName))
+		return
+	}
+
+	retry, err := dispatcher.RetryJob(h.k8sClient, ij, userReq.Job)
+	if err != nil {
+		log.Info(err.Error())
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, cannot retry job %s, err : %s", reqID, userReq.Job, err.Error()))
+		return
+	}
+
+	_ = utils.RespondJSON(w, retry)
+}
@@ -0,0 +1,122 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package integrationjobs
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/test"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_handler_retryHandler(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, cicdv1.AddToScheme(s))
+
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "test-ns"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+			Jobs:      cicdv1.Jobs{{Container: corev1.Container{Name: "test"}}},
+			Refs:      cicdv1.IntegrationJobRefs{Base: cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/master", Sha: "1111111111"}},
+		},
+		Status: cicdv1.IntegrationJobStatus{
+			CompletionTime: &metav1.Time{Time: time.Now()},
+			Jobs:           []cicdv1.JobStatus{{Name: "test", State: cicdv1.CommitStatusStateFailure}},
+		},
+	}
+	vars := map[string]string{"namespace": "test-ns", "ijName": "test-ij"}
+
+	tc := map[string]struct {
+		vars map[string]string
+		body string
+
+		expectedCode    int
+		expectedMessage string
+	}{
+		"normal": {
+			vars:         vars,
+			body:         `{"job": "test"}`,
+			expectedCode: 200,
+		},
+		"malformedURL": {
+			vars:            map[string]string{"namespace": "test-ns"},
+			body:            `{"job": "test"}`,
+			expectedCode:    400,
+			expectedMessage: "url is malformed",
+		},
+		"noJob": {
+			vars:            vars,
+			body:            `{}`,
+			expectedCode:    400,
+			expectedMessage: "job must be set",
+		},
+		"ijNotFound": {
+			vars:            map[string]string{"namespace": "test-ns", "ijName": "no-ij"},
+			body:            `{"job": "test"}`,
+			expectedCode:    404,
+			expectedMessage: "cannot get IntegrationJob test-ns/no-ij",
+		},
+		"jobNotFound": {
+			vars:            vars,
+			body:            `{"job": "lint"}`,
+			expectedCode:    400,
+			expectedMessage: "cannot retry job lint, err : there is no job lint in IntegrationJob test-ij",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			h := &handler{
+				log:       &test.FakeLogger{},
+				k8sClient: fake.NewClientBuilder().WithScheme(s).WithObjects(ij).Build(),
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body))
+			req = mux.SetURLVars(req, c.vars)
+			h.retryHandler(w, req)
+
+			require.Equal(t, c.expectedCode, w.Result().StatusCode)
+			b, err := ioutil.ReadAll(w.Result().Body)
+			require.NoError(t, err)
+			if c.expectedCode != 200 {
+				require.Contains(t, string(b), c.expectedMessage)
+				return
+			}
+
+			retry := &cicdv1.IntegrationJob{}
+			require.NoError(t, json.Unmarshal(b, retry))
+			require.NoError(t, h.k8sClient.Get(context.Background(), types.NamespacedName{Name: retry.Name, Namespace: "test-ns"}, retry))
+			require.Equal(t, "test-ij", retry.Annotations[cicdv1.JobAnnotationRetryOf])
+		})
+	}
+}
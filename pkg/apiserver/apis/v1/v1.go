@@ -27,8 +27,10 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/wrapper"
 	"github.com/tmax-cloud/cicd-operator/pkg/apiserver/apis/v1/approvals"
 	"github.com/tmax-cloud/cicd-operator/pkg/apiserver/apis/v1/integrationconfigs"
+	"github.com/tmax-cloud/cicd-operator/pkg/apiserver/apis/v1/integrationjobs"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	authorization "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -40,10 +42,11 @@ const (
 type handler struct {
 	approvalsHandler apiserver.APIHandler
 	icHandler        apiserver.APIHandler
+	ijHandler        apiserver.APIHandler
 }
 
 // NewHandler instantiates a new v1 api handler
-func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authorization.AuthorizationV1Interface, logger logr.Logger) (apiserver.APIHandler, error) {
+func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authorization.AuthorizationV1Interface, podsGetter typedcorev1.PodsGetter, logger logr.Logger) (apiserver.APIHandler, error) {
 	handler := &handler{}
 
 	// /v1
@@ -72,6 +75,13 @@ func NewHandler(parent wrapper.RouterWrapper, cli client.Client, authCli authori
 	}
 	handler.icHandler = icHandler
 
+	// /v1/namespaces/<namespace>/integrationjobs
+	ijHandler, err := integrationjobs.NewHandler(namespaceWrapper, cli, authCli, podsGetter, logger)
+	if err != nil {
+		return nil, err
+	}
+	handler.ijHandler = ijHandler
+
 	return handler, nil
 }
 
@@ -102,6 +112,26 @@ func (h *handler) versionHandler(w http.ResponseWriter, _ *http.Request) {
 			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationConfigKind, cicdv1.IntegrationConfigAPIWebhookURL),
 			Namespaced: true,
 		},
+		{
+			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationConfigKind, cicdv1.IntegrationConfigAPIMergePool),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationJobKind, cicdv1.IntegrationJobAPILog),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationJobKind, cicdv1.IntegrationJobAPIRetry),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationJobKind, cicdv1.IntegrationJobAPIPause),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/%s", cicdv1.IntegrationJobKind, cicdv1.IntegrationJobAPIResume),
+			Namespaced: true,
+		},
 	}
 
 	_ = utils.RespondJSON(w, apiResourceList)
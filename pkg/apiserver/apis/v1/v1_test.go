@@ -31,13 +31,13 @@ func TestNewHandler(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		p := wrapper.New("/", nil, nil)
 		p.SetRouter(mux.NewRouter())
-		_, err := NewHandler(p, nil, nil, nil)
+		_, err := NewHandler(p, nil, nil, nil, nil)
 		require.NoError(t, err)
 	})
 
 	t.Run("versionErr", func(t *testing.T) {
 		p := wrapper.New("/", nil, nil)
-		_, err := NewHandler(p, nil, nil, nil)
+		_, err := NewHandler(p, nil, nil, nil, nil)
 		require.Error(t, err)
 		require.Equal(t, "parent does not have a router", err.Error())
 	})
@@ -53,5 +53,5 @@ func Test_handler_versionHandler(t *testing.T) {
 	require.Equal(t, 200, w.Result().StatusCode)
 	b, err := ioutil.ReadAll(w.Result().Body)
 	require.NoError(t, err)
-	require.Equal(t, "{\"kind\":\"APIResourceList\",\"apiVersion\":\"v1\",\"groupVersion\":\"cicdapi.tmax.io/v1\",\"resources\":[{\"name\":\"approvals/approve\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"approvals/reject\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/runpre\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/runpost\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/webhookurl\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null}]}", string(b))
+	require.Equal(t, "{\"kind\":\"APIResourceList\",\"apiVersion\":\"v1\",\"groupVersion\":\"cicdapi.tmax.io/v1\",\"resources\":[{\"name\":\"approvals/approve\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"approvals/reject\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/runpre\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/runpost\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/webhookurl\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationconfigs/mergepool\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationjobs/log\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationjobs/retry\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationjobs/pause\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null},{\"name\":\"integrationjobs/resume\",\"singularName\":\"\",\"namespaced\":true,\"kind\":\"\",\"verbs\":null}]}", string(b))
 }
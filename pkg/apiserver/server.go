@@ -25,6 +25,7 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/apiserver"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	authorization "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 
@@ -49,6 +50,7 @@ type server struct {
 	wrapper wrapper.RouterWrapper
 	client  client.Client
 	authCli authorization.AuthorizationV1Interface
+	coreCli typedcorev1.CoreV1Interface
 	cache   cache.Cache
 
 	apisHandler apiserver.APIHandler
@@ -70,9 +72,13 @@ func New(cli client.Client, cfg *rest.Config, cache cache.Cache) (Server, error)
 	if err != nil {
 		return nil, err
 	}
+	srv.coreCli, err = typedcorev1.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set apisHandler
-	apisHandler, err := apis.NewHandler(srv.wrapper, srv.client, srv.authCli, log)
+	apisHandler, err := apis.NewHandler(srv.wrapper, srv.client, srv.authCli, srv.coreCli, log)
 	if err != nil {
 		return nil, err
 	}
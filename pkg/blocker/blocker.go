@@ -17,6 +17,7 @@
 package blocker
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/go-logr/logr"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,10 +39,11 @@ const (
 
 // blocker blocks PRs to be merged. TODO - Need a cool name
 // There are 3 main roles for blocker.
-//   1. (Pool Syncer) Sync Pools with github/gitlab's open PullRequests list for each v1.IntegrationConfig.
-//   2. (Status Syncer) Check commit statuses/merge conflicts for PRs which meet all the conditions of v1.MergeQuery.
-//      (We say the PRs are in 'MergePool')
-//   3. (Merger) Merge PRs in the merge pool with successful commit statuses and no merge conflicts.
+//  1. (Pool Syncer) Sync Pools with github/gitlab's open PullRequests list for each v1.IntegrationConfig.
+//  2. (Status Syncer) Check commit statuses/merge conflicts for PRs which meet all the conditions of v1.MergeQuery.
+//     (We say the PRs are in 'MergePool')
+//  3. (Merger) Merge PRs in the merge pool with successful commit statuses and no merge conflicts.
+//
 // These three roles run in their own goroutine, periodically.
 type blocker struct {
 	client client.Client
@@ -49,6 +52,8 @@ type blocker struct {
 	// Pools contains PR pools for each IntegrationConfigs existing in the cluster.
 	// It is kind of a cache of PRs
 	Pools map[poolKey]*PRPool
+	// poolsLock guards Pools, which is written by the pool syncer and read by the others
+	poolsLock sync.RWMutex
 
 	lastPoolSync time.Time
 
@@ -78,6 +83,25 @@ func (b *blocker) Start() {
 	go b.loopMerge()
 }
 
+// getPool returns the PR pool of the key
+func (b *blocker) getPool(key poolKey) (*PRPool, bool) {
+	b.poolsLock.RLock()
+	defer b.poolsLock.RUnlock()
+	pool, exist := b.Pools[key]
+	return pool, exist
+}
+
+// listPools returns a snapshot of the PR pools, which can be iterated while the pools are synced
+func (b *blocker) listPools() map[poolKey]*PRPool {
+	b.poolsLock.RLock()
+	defer b.poolsLock.RUnlock()
+	pools := make(map[poolKey]*PRPool, len(b.Pools))
+	for key, pool := range b.Pools {
+		pools[key] = pool
+	}
+	return pools
+}
+
 // poolKey is a key for PR pools.
 type poolKey string
 
@@ -95,6 +119,9 @@ type PRPool struct {
 	// NamespacedName stores a name and a namespace of source IntegrationConfig
 	types.NamespacedName
 
+	// Repository is a repository of the pool, as an IntegrationConfig may have multiple repositories
+	Repository string
+
 	// PullRequests store all open PullRequests for an IntegrationConfig, including those who does not meet conditions.
 	PullRequests map[int]*PullRequest
 
@@ -104,6 +131,9 @@ type PRPool struct {
 	// CurrentBatch is a batch of PRs, waiting for a block-merge.
 	// If it's non-nil, maybe merger is retesting the PRs.
 	CurrentBatch *Batch
+
+	// LastSyncTime is the time the statuses of the PRs in the merge pool are synchronized at
+	LastSyncTime time.Time
 }
 
 // Batch is a batch of PRs, waiting for a block-merge.
@@ -116,11 +146,15 @@ type Batch struct {
 
 	// Processing is an indicator that the batch is under process
 	Processing bool
+
+	// Rest are the PRs of a failed batch, not tested yet while the batch is bisected by the merge queue.
+	// They're tested in the next batch, after the PRs are merged or kicked out
+	Rest []*PullRequest
 }
 
-// Contains checks if a PR is in the batch
+// Contains checks if a PR is in the batch, including the rest of the bisected batch
 func (b *Batch) Contains(id int) bool {
-	for _, pr := range b.PRs {
+	for _, pr := range append(b.PRs, b.Rest...) {
 		if pr.ID == id {
 			return true
 		}
@@ -142,6 +176,15 @@ func NewPRPool(ns, name string) *PRPool {
 	}
 }
 
+// getIntegrationConfig gets the source IntegrationConfig of the pool, for the pool's repository
+func (b *blocker) getIntegrationConfig(pool *PRPool) (*cicdv1.IntegrationConfig, error) {
+	ic := &cicdv1.IntegrationConfig{}
+	if err := b.client.Get(context.Background(), pool.NamespacedName, ic); err != nil {
+		return nil, err
+	}
+	return ic.ForRepository(pool.Repository), nil
+}
+
 // MergePool is a pool for PRs.
 // Keys are git.CommitStatusState (same as PullRequest.BlockerStatus) - pr.ID
 type MergePool map[git.CommitStatusState]map[int]*PullRequest
@@ -194,10 +237,57 @@ type PullRequest struct {
 	// blockerCacheDirty specifies if the commit status should be updated
 	blockerCacheDirty bool
 
+	// mergeableFetched specifies if the mergeability of the PR is fetched by a single PR request, not by listing PRs
+	mergeableFetched bool
+
 	// Statuses stores whole commit statuses of the PR
 	Statuses map[string]git.CommitStatus
 
+	// statusesSHA is the head SHA of the PR, when Statuses are fetched
+	// The PR's head may be changed by the pool syncer, before its statuses are synchronized again
+	statusesSHA string
+
+	// Approvers are the users whose approvals are counted for the approvals query
+	Approvers []string
+
+	// MissingCodeOwners are the changed files not approved by their code owners yet
+	MissingCodeOwners []codeowners.Requirement
+
+	// codeOwnersChecked specifies if MissingCodeOwners is successfully checked
+	codeOwnersChecked bool
+
+	// UnmergedDependencies are the PRs in the Depends-On footers of the PR, not merged yet
+	UnmergedDependencies []string
+
+	// dependenciesChecked specifies if UnmergedDependencies is successfully checked
+	dependenciesChecked bool
+
 	// Commits are the list of commits in the PR
 	// Only set right before merging it, only if mergeConfig's commitTemplate is not empty
 	Commits []git.Commit
+
+	// BranchUpdatedFrom is the head SHA of the PR, when its branch is updated with the base branch
+	// The merger waits until the head is changed from it, for branchUpdateTimeout at most
+	BranchUpdatedFrom string
+
+	// branchUpdatedTime is the time the branch of the PR is updated by the merger
+	branchUpdatedTime time.Time
+
+	// lastRetestTime is the time the PR is retested in a batch by the merger
+	lastRetestTime time.Time
+
+	// lastStatusSyncTime is the time the status of the PR is synchronized by the status syncer
+	lastStatusSyncTime time.Time
+
+	// DryRunResult is what the merger would do for the PR, if the merge config is in the dry-run mode
+	DryRunResult string
+
+	// dryRunReportedSHA is the head SHA of the PR, when DryRunResult is commented
+	dryRunReportedSHA string
+}
+
+// statusesOutdated checks if the PR's head is changed since its statuses are fetched, i.e., the statuses are not the
+// ones of the head commit, which is not validated yet
+func (p *PullRequest) statusesOutdated() bool {
+	return p.statusesSHA != p.Head.Sha
 }
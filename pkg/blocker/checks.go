@@ -21,11 +21,14 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"path"
 	"sort"
 	"strings"
+	"time"
 )
 
-// checkConditionsSimple checks labels, approved, author, branch conditions for a PR to be in a merge pool
+// checkConditionsSimple checks labels, approved, lgtm, author, branch, milestone, assignee conditions for a PR to be in a
+// merge pool
 func checkConditionsSimple(q cicdv1.MergeQuery, pr *git.PullRequest) (bool, string) {
 	var messages []string
 
@@ -37,11 +40,20 @@ func checkConditionsSimple(q cicdv1.MergeQuery, pr *git.PullRequest) (bool, stri
 	if q.ApproveRequired { // Check 'approved' label if approval is required
 		q.Labels = append(q.Labels, "approved")
 	}
+	if q.LGTMRequired { // Check 'lgtm' label if lgtm is required
+		q.Labels = append(q.Labels, "lgtm")
+	}
 
 	// add global block label
 	if configs.MergeBlockLabel != "" {
 		q.BlockLabels = append(q.BlockLabels, configs.MergeBlockLabel)
 	}
+	if configs.MergeHoldLabel != "" {
+		q.BlockLabels = append(q.BlockLabels, configs.MergeHoldLabel)
+	}
+	if configs.MergeWIPLabel != "" {
+		q.BlockLabels = append(q.BlockLabels, configs.MergeWIPLabel)
+	}
 
 	passLabelChecks, labelCheckMsg := checkLabels(labels, q)
 	if labelCheckMsg != "" {
@@ -60,7 +72,19 @@ func checkConditionsSimple(q cicdv1.MergeQuery, pr *git.PullRequest) (bool, stri
 		messages = append(messages, branchCheckMsg)
 	}
 
-	return passLabelChecks && passAuthorCheck && passBranchCheck, strings.Join(messages, " ")
+	// Check milestone
+	passMilestoneCheck, milestoneCheckMsg := checkMilestone(pr.Milestone, q)
+	if milestoneCheckMsg != "" {
+		messages = append(messages, milestoneCheckMsg)
+	}
+
+	// Check assignees
+	passAssigneeCheck, assigneeCheckMsg := checkAssignees(pr.Assignees, q)
+	if assigneeCheckMsg != "" {
+		messages = append(messages, assigneeCheckMsg)
+	}
+
+	return passLabelChecks && passAuthorCheck && passBranchCheck && passMilestoneCheck && passAssigneeCheck, strings.Join(messages, " ")
 }
 
 // checkConditionsFull is a checkConditionsSimple + commit status check + merge conflict check
@@ -83,13 +107,90 @@ func checkConditionsFull(q cicdv1.MergeQuery, pr *PullRequest) (bool, bool, stri
 		messages = append(messages, "Merge conflicts exist.")
 	}
 
+	// Check approvals
+	passApprovals, approvalsMsg := checkApprovals(pr.Approvers, q)
+	if approvalsMsg != "" {
+		messages = append(messages, approvalsMsg)
+	}
+
+	// Check code owners
+	passCodeOwners, codeOwnersMsg := checkCodeOwners(pr, q)
+	if codeOwnersMsg != "" {
+		messages = append(messages, codeOwnersMsg)
+	}
+
+	// Check dependencies
+	passDependencies, dependenciesMsg := checkDependencies(pr)
+	if dependenciesMsg != "" {
+		messages = append(messages, dependenciesMsg)
+	}
+
 	// Check commit statuses
 	passCommitStatus, commitStatusMsg := checkChecks(pr.Statuses, q)
 	if commitStatusMsg != "" {
 		messages = append(messages, commitStatusMsg)
 	}
 
-	return simpleResult && passMergeConflict && passCommitStatus, false, strings.Join(messages, " ")
+	return simpleResult && passMergeConflict && passApprovals && passCodeOwners && passDependencies && passCommitStatus, false, strings.Join(messages, " ")
+}
+
+// checkFreeze checks if the base branch of the PR is frozen. The PRs with the override label are never frozen
+func checkFreeze(freeze *cicdv1.MergeFreeze, pr *PullRequest, now time.Time) (bool, string) {
+	if freeze == nil {
+		return true, ""
+	}
+	for _, l := range pr.Labels {
+		if l.Name == freeze.GetOverrideLabel() {
+			return true, ""
+		}
+	}
+	period := freeze.ActivePeriod(cicdv1.GitRef(pr.Base.Ref).GetBranch(), now)
+	if period == nil {
+		return true, ""
+	}
+	if period.End != nil {
+		return false, fmt.Sprintf("Merge is frozen until %s.", period.End.UTC().Format(time.RFC3339))
+	}
+	return false, "Merge is frozen."
+}
+
+func checkCodeOwners(pr *PullRequest, q cicdv1.MergeQuery) (bool, string) {
+	if !q.CodeOwners {
+		return true, ""
+	}
+	if !pr.codeOwnersChecked {
+		return false, "Code owners cannot be checked."
+	}
+	if len(pr.MissingCodeOwners) == 0 {
+		return true, ""
+	}
+
+	var files []string
+	for _, r := range pr.MissingCodeOwners {
+		files = append(files, r.Files...)
+	}
+	return false, fmt.Sprintf("Approvals from code owners are required for [%s].", strings.Join(files, ","))
+}
+
+// checkDependencies checks if the PRs in the Depends-On footers of the PR are merged
+func checkDependencies(pr *PullRequest) (bool, string) {
+	if len(parseDependencies(pr.Body)) == 0 {
+		return true, ""
+	}
+	if !pr.dependenciesChecked {
+		return false, "Dependencies cannot be checked."
+	}
+	if len(pr.UnmergedDependencies) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf("Dependencies [%s] are not merged yet.", strings.Join(pr.UnmergedDependencies, ","))
+}
+
+func checkApprovals(approvers []string, q cicdv1.MergeQuery) (bool, string) {
+	if q.Approvals == nil || len(approvers) >= q.Approvals.Count {
+		return true, ""
+	}
+	return false, fmt.Sprintf("Approvals [%d/%d] are required.", len(approvers), q.Approvals.Count)
 }
 
 func checkBranch(b string, q cicdv1.MergeQuery) (bool, string) {
@@ -108,6 +209,20 @@ func checkBranch(b string, q cicdv1.MergeQuery) (bool, string) {
 	return isProperBranch, msg
 }
 
+func checkMilestone(milestone string, q cicdv1.MergeQuery) (bool, string) {
+	if !q.MilestoneRequired || milestone != "" {
+		return true, ""
+	}
+	return false, "Milestone is required."
+}
+
+func checkAssignees(assignees []git.User, q cicdv1.MergeQuery) (bool, string) {
+	if !q.AssigneeRequired || len(assignees) > 0 {
+		return true, ""
+	}
+	return false, "Assignee is required."
+}
+
 func checkAuthor(author string, q cicdv1.MergeQuery) (bool, string) {
 	isProperAuthor := true
 	msg := ""
@@ -130,8 +245,7 @@ func checkLabels(labels map[string]struct{}, q cicdv1.MergeQuery) (bool, string)
 	if len(q.Labels) > 0 {
 		var missing []string
 		for _, l := range q.Labels {
-			_, exist := labels[l]
-			if !exist {
+			if len(matchLabels(labels, l)) == 0 {
 				isProperLabels = false
 				missing = append(missing, l)
 			}
@@ -142,18 +256,21 @@ func checkLabels(labels map[string]struct{}, q cicdv1.MergeQuery) (bool, string)
 		}
 	}
 	if len(q.BlockLabels) > 0 {
-		var blocking []string
+		blockingMap := map[string]struct{}{}
 		for _, l := range q.BlockLabels {
-			_, exist := labels[l]
-			if exist {
+			for _, matched := range matchLabels(labels, l) {
 				isProperLabels = false
-				blocking = append(blocking, l)
+				blockingMap[matched] = struct{}{}
 			}
 		}
-		if len(blocking) > 0 {
+		if len(blockingMap) > 0 {
 			if msg != "" {
 				msg += " "
 			}
+			var blocking []string
+			for l := range blockingMap {
+				blocking = append(blocking, l)
+			}
 			sort.Strings(blocking)
 			msg += fmt.Sprintf("Label [%s] is blocking the merge.", strings.Join(blocking, ","))
 		}
@@ -162,6 +279,23 @@ func checkLabels(labels map[string]struct{}, q cicdv1.MergeQuery) (bool, string)
 	return isProperLabels, msg
 }
 
+// matchLabels returns the labels matching the pattern, which may contain wildcards (e.g., kind/*)
+func matchLabels(labels map[string]struct{}, pattern string) []string {
+	if _, exist := labels[pattern]; exist {
+		return []string{pattern}
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return nil
+	}
+	var matched []string
+	for l := range labels {
+		if ok, _ := path.Match(pattern, l); ok {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
+
 func checkChecks(statuses map[string]git.CommitStatus, q cicdv1.MergeQuery) (bool, string) {
 	var unmetChecks []string
 	passAllRequiredChecks := true
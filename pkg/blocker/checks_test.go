@@ -20,9 +20,11 @@ import (
 	"github.com/bmizerany/assert"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
+	"time"
 )
 
 const (
@@ -93,6 +95,34 @@ func TestCheckConditions(t *testing.T) {
 			ExpectedResult:  false,
 			ExpectedMessage: "Label [approved] is required.",
 		},
+		"failLGTM": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "approved"}},
+				Mergeable: true,
+			},
+			Query: cicdv1.MergeQuery{
+				ApproveRequired: true,
+				LGTMRequired:    true,
+			},
+			ExpectedResult:  false,
+			ExpectedMessage: "Label [lgtm] is required.",
+		},
+		"successLGTM": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "approved"}, {Name: "lgtm"}},
+				Mergeable: true,
+			},
+			Query: cicdv1.MergeQuery{
+				ApproveRequired: true,
+				LGTMRequired:    true,
+			},
+			ExpectedResult:  true,
+			ExpectedMessage: "",
+		},
 		"failGlobalBlock": {
 			PR: &git.PullRequest{
 				Author:    git.User{Name: "cqbqdd11519"},
@@ -108,10 +138,74 @@ func TestCheckConditions(t *testing.T) {
 			ExpectedResult:  false,
 			ExpectedMessage: "Label [global/block-label] is blocking the merge.",
 		},
+		"failGlobalHold": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "lgtm"}, {Name: "global/hold-label"}},
+				Mergeable: true,
+			},
+			Query: cicdv1.MergeQuery{
+				Branches:        []string{"master", "newnew"},
+				Labels:          []string{"lgtm"},
+				ApproveRequired: false,
+			},
+			ExpectedResult:  false,
+			ExpectedMessage: "Label [global/hold-label] is blocking the merge.",
+		},
+		"failGlobalWIP": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "lgtm"}, {Name: "global/wip-label"}},
+				Mergeable: true,
+			},
+			Query: cicdv1.MergeQuery{
+				Branches:        []string{"master", "newnew"},
+				Labels:          []string{"lgtm"},
+				ApproveRequired: false,
+			},
+			ExpectedResult:  false,
+			ExpectedMessage: "Label [global/wip-label] is blocking the merge.",
+		},
+		"failMilestoneAssignee": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "lgtm"}},
+				Mergeable: true,
+			},
+			Query: cicdv1.MergeQuery{
+				MilestoneRequired: true,
+				AssigneeRequired:  true,
+			},
+			ExpectedResult:  false,
+			ExpectedMessage: "Milestone is required. Assignee is required.",
+		},
+		"successMilestoneAssignee": {
+			PR: &git.PullRequest{
+				Author:    git.User{Name: "cqbqdd11519"},
+				Base:      git.Base{Ref: "refs/heads/newnew"},
+				Labels:    []git.IssueLabel{{Name: "lgtm"}},
+				Mergeable: true,
+				Milestone: "v0.1.0",
+				Assignees: []git.User{{ID: 1, Name: "cqbqdd11519"}},
+			},
+			Query: cicdv1.MergeQuery{
+				MilestoneRequired: true,
+				AssigneeRequired:  true,
+			},
+			ExpectedResult:  true,
+			ExpectedMessage: "",
+		},
 	}
 
 	// For test 'failGlobalBlock'
 	configs.MergeBlockLabel = "global/block-label"
+	// For test 'failGlobalHold'
+	configs.MergeHoldLabel = "global/hold-label"
+	// For test 'failGlobalWIP'
+	configs.MergeWIPLabel = "global/wip-label"
 
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
@@ -170,6 +264,184 @@ func TestCheckConditionsFull(t *testing.T) {
 	}
 }
 
+func TestCheckDependencies(t *testing.T) {
+	tc := map[string]struct {
+		pr *PullRequest
+
+		expectedResult  bool
+		expectedMessage string
+	}{
+		"noDependency": {
+			pr:             &PullRequest{},
+			expectedResult: true,
+		},
+		"notChecked": {
+			pr:              &PullRequest{PullRequest: git.PullRequest{Body: "Depends-On: tmax-cloud/cicd-test2#3"}},
+			expectedResult:  false,
+			expectedMessage: "Dependencies cannot be checked.",
+		},
+		"notMerged": {
+			pr: &PullRequest{
+				PullRequest:          git.PullRequest{Body: "Depends-On: tmax-cloud/cicd-test2#3\nDepends-On: tmax-cloud/cicd-test3#4"},
+				UnmergedDependencies: []string{"tmax-cloud/cicd-test2#3", "tmax-cloud/cicd-test3#4"},
+				dependenciesChecked:  true,
+			},
+			expectedResult:  false,
+			expectedMessage: "Dependencies [tmax-cloud/cicd-test2#3,tmax-cloud/cicd-test3#4] are not merged yet.",
+		},
+		"merged": {
+			pr: &PullRequest{
+				PullRequest:         git.PullRequest{Body: "Depends-On: tmax-cloud/cicd-test2#3"},
+				dependenciesChecked: true,
+			},
+			expectedResult: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			result, msg := checkDependencies(c.pr)
+			assert.Equal(t, c.expectedResult, result)
+			assert.Equal(t, c.expectedMessage, msg)
+		})
+	}
+}
+
+func TestCheckApprovals(t *testing.T) {
+	tc := map[string]struct {
+		approvers []string
+		query     cicdv1.MergeQuery
+
+		expectedResult  bool
+		expectedMessage string
+	}{
+		"noQuery": {
+			query:          cicdv1.MergeQuery{},
+			expectedResult: true,
+		},
+		"enough": {
+			approvers:      []string{"user1", "user2"},
+			query:          cicdv1.MergeQuery{Approvals: &cicdv1.ApprovalsQuery{Count: 2}},
+			expectedResult: true,
+		},
+		"notEnough": {
+			approvers:       []string{"user1"},
+			query:           cicdv1.MergeQuery{Approvals: &cicdv1.ApprovalsQuery{Count: 2}},
+			expectedResult:  false,
+			expectedMessage: "Approvals [1/2] are required.",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			result, msg := checkApprovals(c.approvers, c.query)
+			assert.Equal(t, c.expectedResult, result)
+			assert.Equal(t, c.expectedMessage, msg)
+		})
+	}
+}
+
+func TestCheckFreeze(t *testing.T) {
+	now := time.Date(2021, 12, 24, 12, 0, 0, 0, time.UTC)
+	start := metav1.NewTime(now.Add(-time.Hour))
+	end := metav1.NewTime(now.Add(time.Hour))
+
+	tc := map[string]struct {
+		freeze *cicdv1.MergeFreeze
+		labels []git.IssueLabel
+
+		expectedResult  bool
+		expectedMessage string
+	}{
+		"noFreeze": {
+			expectedResult: true,
+		},
+		"frozen": {
+			freeze:          &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{Start: &start, End: &end}}},
+			expectedResult:  false,
+			expectedMessage: "Merge is frozen until 2021-12-24T13:00:00Z.",
+		},
+		"frozenWindow": {
+			freeze: &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{
+				Window: &cicdv1.ExecutionWindow{Cron: "0 0 * * 5", Duration: metav1.Duration{Duration: 24 * time.Hour}},
+			}}},
+			expectedResult:  false,
+			expectedMessage: "Merge is frozen.",
+		},
+		"otherBranch": {
+			freeze:         &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{Branches: []string{"release"}, Start: &start}}},
+			expectedResult: true,
+		},
+		"ended": {
+			freeze:         &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{End: &start}}},
+			expectedResult: true,
+		},
+		"override": {
+			freeze:         &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{Start: &start}}},
+			labels:         []git.IssueLabel{{Name: "ci/freeze-override"}},
+			expectedResult: true,
+		},
+		"customOverride": {
+			freeze:          &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{Start: &start}}, OverrideLabel: "hotfix"},
+			labels:          []git.IssueLabel{{Name: "ci/freeze-override"}},
+			expectedResult:  false,
+			expectedMessage: "Merge is frozen.",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			pr := &PullRequest{PullRequest: git.PullRequest{Base: git.Base{Ref: "refs/heads/master"}, Labels: c.labels}}
+			result, msg := checkFreeze(c.freeze, pr, now)
+			assert.Equal(t, c.expectedResult, result)
+			assert.Equal(t, c.expectedMessage, msg)
+		})
+	}
+}
+
+func TestCheckCodeOwners(t *testing.T) {
+	tc := map[string]struct {
+		pr    *PullRequest
+		query cicdv1.MergeQuery
+
+		expectedResult  bool
+		expectedMessage string
+	}{
+		"notRequired": {
+			pr:             &PullRequest{},
+			expectedResult: true,
+		},
+		"notChecked": {
+			pr:              &PullRequest{},
+			query:           cicdv1.MergeQuery{CodeOwners: true},
+			expectedResult:  false,
+			expectedMessage: "Code owners cannot be checked.",
+		},
+		"approved": {
+			pr:             &PullRequest{codeOwnersChecked: true},
+			query:          cicdv1.MergeQuery{CodeOwners: true},
+			expectedResult: true,
+		},
+		"missing": {
+			pr: &PullRequest{codeOwnersChecked: true, MissingCodeOwners: []codeowners.Requirement{
+				{Owners: []string{"doc-owner"}, Files: []string{"docs/a.md", "docs/b.md"}},
+				{Owners: []string{"go-owner"}, Files: []string{"main.go"}},
+			}},
+			query:           cicdv1.MergeQuery{CodeOwners: true},
+			expectedResult:  false,
+			expectedMessage: "Approvals from code owners are required for [docs/a.md,docs/b.md,main.go].",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			result, msg := checkCodeOwners(c.pr, c.query)
+			assert.Equal(t, c.expectedResult, result)
+			assert.Equal(t, c.expectedMessage, msg)
+		})
+	}
+}
+
 type checkBranchAuthorTestCase struct {
 	Value string
 	Query cicdv1.MergeQuery
@@ -283,6 +555,32 @@ type checkLabelsTestCase struct {
 
 func TestCheckLabels(t *testing.T) {
 	tc := map[string]checkLabelsTestCase{
+		"successWildcard": {
+			Labels: map[string]struct{}{
+				"lgtm":     {},
+				"kind/bug": {},
+			},
+			Query: cicdv1.MergeQuery{
+				Labels:      []string{"lgtm", "kind/*"},
+				BlockLabels: []string{"do-not-merge/*"},
+			},
+			ExpectedResult:  true,
+			ExpectedMessage: "",
+		},
+		"failWildcard": {
+			Labels: map[string]struct{}{
+				"lgtm":                          {},
+				"do-not-merge/hold":             {},
+				"do-not-merge/work-in-progress": {},
+				"needs-rebase":                  {},
+			},
+			Query: cicdv1.MergeQuery{
+				Labels:      []string{"lgtm", "kind/*"},
+				BlockLabels: []string{"do-not-merge/*", "needs-rebase", "hold"},
+			},
+			ExpectedResult:  false,
+			ExpectedMessage: "Label [kind/*] is required. Label [do-not-merge/hold,do-not-merge/work-in-progress,needs-rebase] is blocking the merge.",
+		},
 		"success": {
 			Labels: map[string]struct{}{
 				"lgtm": {},
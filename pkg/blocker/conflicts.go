@@ -0,0 +1,105 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// syncConflicts labels the conflicted PRs in the merge pool with the conflict label and notifies their authors, and
+// unlabels the PRs once their conflicts are resolved. The PRs with the label, but not in the merge pool, are fetched
+// again to check if their conflicts are resolved.
+// It only acts on the mergeability fetched by a single PR request and decided by the git server, as the listed PRs do
+// not have it. The PRs in the merge pool not synced by the status syncer (e.g., due to the sync budget) are skipped.
+func (b *blocker) syncConflicts(pool *PRPool, ic *cicdv1.IntegrationConfig, gitCli git.Client) {
+	if configs.MergeConflictLabel == "" {
+		return
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	log := b.log.WithName("conflict").WithValues("repo", genPoolKey(ic))
+
+	for prID, pr := range pool.PullRequests {
+		labeled := hasLabel(pr.Labels, configs.MergeConflictLabel)
+		inMergePool := pool.MergePool.Search(prID) != nil
+		if !inMergePool && !labeled {
+			continue
+		}
+
+		// Mergeable of the PRs in the merge pool is already synced by the status syncer
+		if !inMergePool {
+			latest, err := gitCli.GetPullRequest(prID)
+			if err != nil {
+				log.Error(err, "")
+				continue
+			}
+			pr.Mergeable = latest.Mergeable
+			pr.Conflicted = latest.Conflicted
+			pr.mergeableFetched = true
+		}
+		if !pr.mergeableFetched {
+			continue
+		}
+
+		switch {
+		case pr.Conflicted && !labeled:
+			log.Info(fmt.Sprintf("PR #%d has merge conflicts. Labeling it with %s", prID, configs.MergeConflictLabel))
+			if err := gitCli.SetLabel(git.IssueTypePullRequest, prID, configs.MergeConflictLabel); err != nil {
+				log.Error(err, "")
+				continue
+			}
+			pr.Labels = append(pr.Labels, git.IssueLabel{Name: configs.MergeConflictLabel})
+			if err := gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateConflictComment(pr)); err != nil {
+				log.Error(err, "")
+			}
+		case pr.Mergeable && labeled:
+			log.Info(fmt.Sprintf("Merge conflicts of PR #%d are resolved. Removing label %s", prID, configs.MergeConflictLabel))
+			if err := gitCli.DeleteLabel(git.IssueTypePullRequest, prID, configs.MergeConflictLabel); err != nil {
+				log.Error(err, "")
+				continue
+			}
+			var labels []git.IssueLabel
+			for _, l := range pr.Labels {
+				if l.Name != configs.MergeConflictLabel {
+					labels = append(labels, l)
+				}
+			}
+			pr.Labels = labels
+		}
+	}
+}
+
+func hasLabel(labels []git.IssueLabel, label string) bool {
+	for _, l := range labels {
+		if l.Name == label {
+			return true
+		}
+	}
+	return false
+}
+
+func generateConflictComment(pr *PullRequest) string {
+	return fmt.Sprintf("[MERGE ALERT]\n\nThis pull request has merge conflicts with the base branch `%s`.\n\n"+
+		"Please rebase it or resolve the conflicts. The label `%s` is removed automatically once the conflicts are resolved.",
+		cicdv1.GitRef(pr.Base.Ref).GetBranch(), configs.MergeConflictLabel)
+}
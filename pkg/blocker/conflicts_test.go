@@ -0,0 +1,96 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+)
+
+func TestBlocker_syncConflicts(t *testing.T) {
+	fakeCli, ic := syncStatusTestEnv()
+	b := New(fakeCli)
+	gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+	pool := NewPRPool(ic.Namespace, ic.Name)
+	pool.MergePool = NewMergePool()
+	pr := &PullRequest{
+		PullRequest: git.PullRequest{
+			ID:   testPRID,
+			Base: git.Base{Ref: "master"},
+		},
+		BlockerStatus: git.CommitStatusStatePending,
+	}
+	pool.PullRequests[testPRID] = pr
+	pool.MergePool.Add(pr)
+	repo := gitfake.Repos[testRepo]
+
+	defer func() {
+		configs.MergeConflictLabel = ""
+	}()
+
+	// Disabled
+	configs.MergeConflictLabel = ""
+	b.syncConflicts(pool, ic, gitCli)
+	require.Empty(t, repo.PullRequests[testPRID].Labels)
+	require.Empty(t, repo.Comments[testPRID])
+
+	// Mergeability is not fetched by a single PR request
+	configs.MergeConflictLabel = "needs-rebase"
+	pr.Conflicted = true
+	b.syncConflicts(pool, ic, gitCli)
+	require.Empty(t, repo.PullRequests[testPRID].Labels)
+	require.Empty(t, repo.Comments[testPRID])
+
+	// Mergeability is not decided yet
+	pr.Conflicted = false
+	pr.mergeableFetched = true
+	b.syncConflicts(pool, ic, gitCli)
+	require.Empty(t, repo.PullRequests[testPRID].Labels)
+	require.Empty(t, repo.Comments[testPRID])
+
+	// Conflicted
+	pr.Conflicted = true
+	b.syncConflicts(pool, ic, gitCli)
+	require.Equal(t, []git.IssueLabel{{Name: "needs-rebase"}}, repo.PullRequests[testPRID].Labels)
+	require.Len(t, repo.Comments[testPRID], 1)
+	require.Equal(t, "[MERGE ALERT]\n\nThis pull request has merge conflicts with the base branch `master`.\n\n"+
+		"Please rebase it or resolve the conflicts. The label `needs-rebase` is removed automatically once the conflicts are resolved.",
+		repo.Comments[testPRID][0].Comment.Body)
+
+	// Still conflicted - not labeled or commented again
+	b.syncConflicts(pool, ic, gitCli)
+	require.Len(t, repo.PullRequests[testPRID].Labels, 1)
+	require.Len(t, repo.Comments[testPRID], 1)
+
+	// Not decided yet, after removed from the merge pool - the label is kept
+	pool.MergePool.Delete(testPRID)
+	repo.PullRequests[testPRID].Mergeable = false
+	b.syncConflicts(pool, ic, gitCli)
+	require.Len(t, repo.PullRequests[testPRID].Labels, 1)
+
+	// Resolved
+	repo.PullRequests[testPRID].Mergeable = true
+	b.syncConflicts(pool, ic, gitCli)
+	require.Empty(t, repo.PullRequests[testPRID].Labels)
+	require.Empty(t, pr.Labels)
+	require.Len(t, repo.Comments[testPRID], 1)
+}
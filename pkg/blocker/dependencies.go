@@ -0,0 +1,120 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dependsOnRe matches the Depends-On footers in the description of a PR (e.g., Depends-On: org/repo#123)
+var dependsOnRe = regexp.MustCompile(`(?m)^Depends-On:\s*(\S+/\S+)#(\d+)\s*$`)
+
+// Dependency is a PR which should be merged before the PR depending on it
+type Dependency struct {
+	Repository string
+	ID         int
+}
+
+func (d Dependency) String() string {
+	return fmt.Sprintf("%s#%d", d.Repository, d.ID)
+}
+
+// parseDependencies parses the Depends-On footers of a PR's description
+func parseDependencies(body string) []Dependency {
+	var deps []Dependency
+	found := map[Dependency]struct{}{}
+	for _, m := range dependsOnRe.FindAllStringSubmatch(body, -1) {
+		id, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		dep := Dependency{Repository: m[1], ID: id}
+		if _, exist := found[dep]; exist {
+			continue
+		}
+		found[dep] = struct{}{}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// reflectDependencies checks the dependencies of the PR not merged yet
+// The dependencies in other repositories are fetched using the IntegrationConfigs managing the repositories, only in
+// the same namespace as the IntegrationConfig of the PR, not to use the other tenants' credentials
+func (b *blocker) reflectDependencies(pull *PullRequest, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
+	pull.UnmergedDependencies = nil
+	pull.dependenciesChecked = false
+
+	deps := parseDependencies(pull.Body)
+	if len(deps) == 0 {
+		return nil
+	}
+
+	var ics *cicdv1.IntegrationConfigList
+	for _, dep := range deps {
+		depCli := gitCli
+		if !strings.EqualFold(dep.Repository, ic.Spec.Git.Repository) {
+			if ics == nil {
+				ics = &cicdv1.IntegrationConfigList{}
+				if err := b.client.List(context.Background(), ics, client.InNamespace(ic.Namespace)); err != nil {
+					return err
+				}
+			}
+			var err error
+			depCli, err = b.getDependencyGitCli(dep, ic, ics)
+			if err != nil {
+				return err
+			}
+		}
+
+		pr, err := depCli.GetPullRequest(dep.ID)
+		if err != nil {
+			return err
+		}
+		if !pr.Merged {
+			pull.UnmergedDependencies = append(pull.UnmergedDependencies, dep.String())
+		}
+	}
+	pull.dependenciesChecked = true
+	return nil
+}
+
+// getDependencyGitCli returns a git client for the repository of the dependency, using the IntegrationConfig managing
+// the repository on the same git server
+func (b *blocker) getDependencyGitCli(dep Dependency, ic *cicdv1.IntegrationConfig, ics *cicdv1.IntegrationConfigList) (git.Client, error) {
+	for i := range ics.Items {
+		depIC := &ics.Items[i]
+		if depIC.Spec.Git.Token == nil || depIC.Spec.Git.Type != ic.Spec.Git.Type || depIC.Spec.Git.GetAPIUrl() != ic.Spec.Git.GetAPIUrl() {
+			continue
+		}
+		for _, repo := range depIC.Spec.Git.GetRepositories() {
+			if strings.EqualFold(repo, dep.Repository) {
+				return utils.GetGitCli(depIC.ForRepository(repo), b.client)
+			}
+		}
+	}
+	return nil, fmt.Errorf("repository %s of the dependency %s is not managed by any IntegrationConfig in namespace %s", dep.Repository, dep.String(), ic.Namespace)
+}
@@ -0,0 +1,122 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseDependencies(t *testing.T) {
+	tc := map[string]struct {
+		body string
+
+		expectedDependencies []Dependency
+	}{
+		"noFooter": {
+			body: "Add a feature",
+		},
+		"footers": {
+			body: "Add a feature\n\nDepends-On: tmax-cloud/cicd-test2#3\nDepends-On:  group/sub/repo#12 \r\n",
+			expectedDependencies: []Dependency{
+				{Repository: "tmax-cloud/cicd-test2", ID: 3},
+				{Repository: "group/sub/repo", ID: 12},
+			},
+		},
+		"duplicated": {
+			body:                 "Depends-On: tmax-cloud/cicd-test2#3\nDepends-On: tmax-cloud/cicd-test2#3",
+			expectedDependencies: []Dependency{{Repository: "tmax-cloud/cicd-test2", ID: 3}},
+		},
+		"invalid": {
+			body: "Depends-On: #3\nDepends-On: tmax-cloud/cicd-test2\n  Depends-On: tmax-cloud/cicd-test2#3",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedDependencies, parseDependencies(c.body))
+		})
+	}
+}
+
+func TestBlocker_reflectDependencies(t *testing.T) {
+	fakeCli, ic := syncStatusTestEnv()
+	b := New(fakeCli)
+	gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+	gitfake.Repos[testRepo].PullRequests[3] = &git.PullRequest{ID: 3, Merged: true}
+	gitfake.Repos["tmax-cloud/cicd-test2"] = &gitfake.Repo{
+		PullRequests: map[int]*git.PullRequest{
+			5: {ID: 5},
+		},
+	}
+
+	pr := &PullRequest{PullRequest: git.PullRequest{ID: testPRID}}
+
+	// No dependency
+	require.NoError(t, b.reflectDependencies(pr, ic, gitCli))
+	require.False(t, pr.dependenciesChecked)
+	require.Empty(t, pr.UnmergedDependencies)
+
+	// Repository not managed by any IntegrationConfig
+	pr.Body = "Depends-On: tmax-cloud/cicd-test#3\nDepends-On: tmax-cloud/cicd-test2#5"
+	require.Error(t, b.reflectDependencies(pr, ic, gitCli))
+	require.False(t, pr.dependenciesChecked)
+
+	// Repository managed by an IntegrationConfig in another namespace
+	otherIC := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: "other"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: "tmax-cloud/cicd-test2",
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+	require.NoError(t, fakeCli.Create(context.Background(), otherIC))
+	require.Error(t, b.reflectDependencies(pr, ic, gitCli))
+	require.False(t, pr.dependenciesChecked)
+
+	// Not merged
+	depIC := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: ic.Namespace},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: "tmax-cloud/cicd-test2",
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+	require.NoError(t, fakeCli.Create(context.Background(), depIC))
+	require.NoError(t, b.reflectDependencies(pr, ic, gitCli))
+	require.True(t, pr.dependenciesChecked)
+	require.Equal(t, []string{"tmax-cloud/cicd-test2#5"}, pr.UnmergedDependencies)
+
+	// Merged
+	gitfake.Repos["tmax-cloud/cicd-test2"].PullRequests[5].Merged = true
+	require.NoError(t, b.reflectDependencies(pr, ic, gitCli))
+	require.True(t, pr.dependenciesChecked)
+	require.Empty(t, pr.UnmergedDependencies)
+}
@@ -24,6 +24,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/go-logr/logr"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
@@ -38,6 +39,9 @@ var log = logf.Log.WithName("blocker")
 
 const (
 	maxBatchSize = 10
+
+	// branchUpdateTimeout is how long the merger waits for the updated branch of a PR to be synced
+	branchUpdateTimeout = 10 * time.Minute
 )
 
 func (b *blocker) loopMerge() {
@@ -48,7 +52,7 @@ func (b *blocker) loopMerge() {
 }
 
 func (b *blocker) retestAndMerge() {
-	for _, pool := range b.Pools {
+	for _, pool := range b.listPools() {
 		go b.retestAndMergeOnePool(pool)
 	}
 }
@@ -57,8 +61,8 @@ func (b *blocker) retestAndMergeOnePool(pool *PRPool) {
 	pool.lock.Lock()
 	defer pool.lock.Unlock()
 
-	ic := &cicdv1.IntegrationConfig{}
-	if err := b.client.Get(context.Background(), pool.NamespacedName, ic); err != nil {
+	ic, err := b.getIntegrationConfig(pool)
+	if err != nil {
 		b.log.WithName("merger").Error(err, "")
 		return
 	}
@@ -92,10 +96,35 @@ func (b *blocker) retestAndMergeOnePool(pool *PRPool) {
 		return
 	}
 
-	// Sort PRs - older (low id) one is prioritized
-	candidates := sortPullRequestByID(pool.MergePool[git.CommitStatusStateSuccess])
+	// Sort PRs - the ones with priority labels first, and then older (low id) one is prioritized
+	candidates := sortPullRequestByPriority(pool.MergePool[git.CommitStatusStateSuccess], ic.Spec.MergeConfig.PriorityLabels)
+
+	// Skip the PRs waiting for their updated branches to be synced
+	candidates = excludeBranchUpdating(candidates, time.Now(), log)
+
+	// Skip the PRs whose heads are changed since their statuses are synced, as the new heads are not validated yet
+	candidates = excludeStatusesOutdated(candidates, log)
+	if len(candidates) == 0 {
+		return
+	}
 
-	// PR with the highest priority (the oldest one)
+	// Only report what would be done for each candidate in the dry-run mode
+	if ic.Spec.MergeConfig.DryRun {
+		for i, pr := range candidates {
+			result, err := getDryRunResult(pr, ic, candidates, gitCli)
+			if err != nil {
+				log.Error(err, "")
+				continue
+			}
+			if i > 0 {
+				result = fmt.Sprintf("%s, after the %d pull request(s) ahead of it in the merge order", result, i)
+			}
+			b.reportDryRun(pr, ic, gitCli, result)
+		}
+		return
+	}
+
+	// PR with the highest priority (the oldest one, if priorities are the same)
 	pr := candidates[0]
 	branch := cicdv1.GitRef(pr.Base.Ref).GetBranch()
 
@@ -106,15 +135,41 @@ func (b *blocker) retestAndMergeOnePool(pool *PRPool) {
 		return
 	}
 
-	// Merge it if the tests are done based on the latest commit
-	if isBaseLatest {
+	// Checks older than the staleness threshold are not trusted, even if they're based on the latest commit
+	isStale := checkStale(ic, pr, time.Now())
+
+	queued := shouldQueue(ic, candidates, branch)
+
+	// Merge it if the tests are done based on the latest commit, unless the merge queue tests it with the others
+	if isBaseLatest && !isStale && !queued {
 		if err := b.mergePullRequest(pr, ic, gitCli); err != nil {
 			log.Error(err, "")
 			return
 		}
+	} else if !isBaseLatest && !queued && ic.Spec.MergeConfig.UpdateBranch {
+		// Update the branch, so that it's tested again based on the latest commit
+		log.Info(fmt.Sprintf("PR #%d is not tested based on the latest commit of %s. Updating the branch", pr.ID, branch))
+		if err := gitCli.UpdatePullRequestBranch(pr.ID, pr.Head.Sha); err != nil {
+			log.Error(err, "")
+			return
+		}
+		pr.BranchUpdatedFrom = pr.Head.Sha
+		pr.branchUpdatedTime = time.Now()
 	} else {
+		// Wait until the retest period passes since the last retest of the PR
+		if retestPeriod := getRetestPeriod(ic); !pr.lastRetestTime.IsZero() && time.Since(pr.lastRetestTime) < retestPeriod {
+			log.Info(fmt.Sprintf("PR #%d was retested at %s.. waiting for the retest period", pr.ID, pr.lastRetestTime.Format(time.RFC3339)))
+			return
+		}
+
 		// If not, retest it!
-		log.Info(fmt.Sprintf("PR #%d is not tested based on the latest commit of %s. Retesting", pr.ID, branch))
+		if isStale {
+			log.Info(fmt.Sprintf("Checks of PR #%d are stale. Retesting", pr.ID))
+		} else if isBaseLatest {
+			log.Info(fmt.Sprintf("PR #%d is queued with the other PRs into %s. Testing them together", pr.ID, branch))
+		} else {
+			log.Info(fmt.Sprintf("PR #%d is not tested based on the latest commit of %s. Retesting", pr.ID, branch))
+		}
 		pool.CurrentBatch = &Batch{}
 
 		// Collect batches, with same base branch
@@ -125,7 +180,7 @@ func (b *blocker) retestAndMergeOnePool(pool *PRPool) {
 			}
 			pool.CurrentBatch.PRs = append(pool.CurrentBatch.PRs, p)
 			prIDs = append(prIDs, p.ID)
-			if len(pool.CurrentBatch.PRs) == maxBatchSize {
+			if len(pool.CurrentBatch.PRs) == getBatchSize(ic) {
 				break
 			}
 		}
@@ -137,9 +192,45 @@ func (b *blocker) retestAndMergeOnePool(pool *PRPool) {
 			log.Error(err, "Fail to create integrationJob for batch.")
 			return
 		}
+		now := time.Now()
+		for _, p := range pool.CurrentBatch.PRs {
+			p.lastRetestTime = now
+		}
 	}
 }
 
+// excludeBranchUpdating excludes the PRs whose branches are updated but the new commits are not synced yet. If a PR
+// waits for longer than branchUpdateTimeout, its marker is cleared and it's not excluded anymore
+func excludeBranchUpdating(candidates []*PullRequest, now time.Time, log logr.Logger) []*PullRequest {
+	var result []*PullRequest
+	for _, p := range candidates {
+		if p.BranchUpdatedFrom != "" && p.BranchUpdatedFrom == p.Head.Sha {
+			if now.Sub(p.branchUpdatedTime) < branchUpdateTimeout {
+				log.Info(fmt.Sprintf("Branch of PR #%d is updated.. waiting for the new commit", p.ID))
+				continue
+			}
+			log.Info(fmt.Sprintf("Branch of PR #%d is not synced for %s since the update.. giving up waiting", p.ID, branchUpdateTimeout))
+			p.BranchUpdatedFrom = ""
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// excludeStatusesOutdated excludes the PRs whose heads are changed since their statuses are fetched
+// They're merged or batched after the status syncer fetches the statuses of the new heads
+func excludeStatusesOutdated(candidates []*PullRequest, log logr.Logger) []*PullRequest {
+	var result []*PullRequest
+	for _, p := range candidates {
+		if p.statusesOutdated() {
+			log.Info(fmt.Sprintf("Head of PR #%d is changed to %s.. waiting for its statuses to be synced", p.ID, p.Head.Sha))
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
 func (b *blocker) handleBatch(pool *PRPool, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
 	pool.CurrentBatch.Processing = true
 	defer func() {
@@ -157,9 +248,35 @@ func (b *blocker) handleBatch(pool *PRPool, ic *cicdv1.IntegrationConfig, gitCli
 	switch ij.Status.State {
 	case cicdv1.IntegrationJobStateCompleted:
 		// If batch test is successful, merge them all, sequentially
+		// The batch waits for the freeze to end, if its base branch is frozen
+		for _, pr := range pool.CurrentBatch.PRs {
+			if passFreeze, _ := checkFreeze(ic.Spec.MergeConfig.Freeze, pr, time.Now()); !passFreeze {
+				log.Info(fmt.Sprintf("Merge of PR #%d is frozen.. waiting for the freeze to end", pr.ID))
+				return nil
+			}
+		}
+		// The batch is dropped without merging the PRs, if the dry-run mode is turned on during the test
+		if ic.Spec.MergeConfig.DryRun {
+			for _, pr := range pool.CurrentBatch.PRs {
+				b.reportDryRun(pr, ic, gitCli, fmt.Sprintf("would be merged into `%s` by `%s` method", cicdv1.GitRef(pr.Base.Ref).GetBranch(), getMergeMethod(pr, ic)))
+			}
+			pool.CurrentBatch = nil
+			return nil
+		}
+		// The PRs whose heads are changed during the test are not merged, as the new heads are not tested
+		testedSHAs := map[int]string{}
+		for _, pull := range ij.Spec.Refs.Pulls {
+			testedSHAs[pull.ID] = pull.Sha
+		}
 		// TODO - what if the target branch is updated during the test...? (manually by a user)
 		for len(pool.CurrentBatch.PRs) > 0 {
-			if err := b.tryMerge(pool.CurrentBatch.PRs[0], ic, gitCli); err != nil {
+			pr := pool.CurrentBatch.PRs[0]
+			if tested, exist := testedSHAs[pr.ID]; exist && tested != pr.Head.Sha {
+				log.Info(fmt.Sprintf("Head of PR #%d is changed from %s during the batch test.. not merging it", pr.ID, tested))
+				pool.CurrentBatch.PRs = pool.CurrentBatch.PRs[1:]
+				continue
+			}
+			if err := b.tryMerge(pr, ic, gitCli); err != nil {
 				return err
 			}
 			pool.CurrentBatch.PRs = pool.CurrentBatch.PRs[1:]
@@ -169,26 +286,79 @@ func (b *blocker) handleBatch(pool *PRPool, ic *cicdv1.IntegrationConfig, gitCli
 				time.Sleep(5 * time.Second)
 			}
 		}
-		pool.CurrentBatch = nil
+		return b.testRestOfBatch(pool, ic)
 	case cicdv1.IntegrationJobStateFailed:
-		// If batch test fails, test again with one less PR in the batch
-		// But if the length is 1 and fails...? Kick it out from the merge pool
+		// If batch test fails, test again with one less PR in the batch. The rest are batched again after the PRs are merged
+		// If the merge queue is used, bisect the batch instead, i.e., test again with the first half of the batch, and test
+		// the second half after the first half is merged or kicked out
 		if pool.CurrentBatch.Len() <= 1 {
-			pool.CurrentBatch = nil
+			// A single PR failing the test in the merge queue is the offending one. Kick it out from the merge pool
+			if ic.Spec.MergeConfig.Queue != nil && pool.CurrentBatch.Len() == 1 {
+				b.kickOutFromMergePool(pool, pool.CurrentBatch.PRs[0], ic, gitCli)
+			}
+			return b.testRestOfBatch(pool, ic)
+		}
+		if ic.Spec.MergeConfig.Queue != nil {
+			half := len(pool.CurrentBatch.PRs) / 2
+			pool.CurrentBatch.Rest = append(append([]*PullRequest{}, pool.CurrentBatch.PRs[half:]...), pool.CurrentBatch.Rest...)
+			pool.CurrentBatch.PRs = pool.CurrentBatch.PRs[:half]
 		} else {
 			pool.CurrentBatch.PRs = pool.CurrentBatch.PRs[:len(pool.CurrentBatch.PRs)-1]
-			gitPRs := getGitPRsFromPRs(pool.CurrentBatch.PRs)
-			if err := b.createIntegrationJobForBatch(gitPRs, ic, &pool.CurrentBatch.Job); err != nil {
-				log.Error(err, "Fail to create integrationJob for batch.")
-				return err
-			}
 		}
+		gitPRs := getGitPRsFromPRs(pool.CurrentBatch.PRs)
+		if err := b.createIntegrationJobForBatch(gitPRs, ic, &pool.CurrentBatch.Job); err != nil {
+			log.Error(err, "Fail to create integrationJob for batch.")
+			return err
+		}
+	case cicdv1.IntegrationJobStateCancelled:
+		// If batch test is cancelled by a user, drop the batch. A new batch is composed from the merge pool
+		pool.CurrentBatch = nil
 	default:
 		// Do nothing if it's still running
 	}
 	return nil
 }
 
+// testRestOfBatch tests the rest of the bisected batch, if any. Otherwise, the batch is cleared
+func (b *blocker) testRestOfBatch(pool *PRPool, ic *cicdv1.IntegrationConfig) error {
+	if len(pool.CurrentBatch.Rest) == 0 {
+		pool.CurrentBatch = nil
+		return nil
+	}
+	pool.CurrentBatch.PRs = pool.CurrentBatch.Rest
+	pool.CurrentBatch.Rest = nil
+
+	gitPRs := getGitPRsFromPRs(pool.CurrentBatch.PRs)
+	if err := b.createIntegrationJobForBatch(gitPRs, ic, &pool.CurrentBatch.Job); err != nil {
+		log.Error(err, "Fail to create integrationJob for batch.")
+		pool.CurrentBatch = nil
+		return err
+	}
+	return nil
+}
+
+// kickOutFromMergePool moves the PR failing the batch test to the pending state, and comments it on the PR
+// The status syncer makes it successful again, once its checks pass
+func (b *blocker) kickOutFromMergePool(pool *PRPool, pr *PullRequest, ic *cicdv1.IntegrationConfig, gitCli git.Client) {
+	log := b.log.WithName("merger").WithValues("repo", genPoolKey(ic))
+	log.Info(fmt.Sprintf("PR #%d failed the batch test. Kicking it out from the merge pool", pr.ID))
+
+	pool.MergePool.Delete(pr.ID)
+	pr.BlockerStatus = git.CommitStatusStatePending
+	pr.BlockerDescription = defaultBlockerMessage + " Failed the batch test."
+	pr.blockerCacheDirty = true
+	pool.MergePool.Add(pr)
+
+	if err := gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateKickOutComment(pool.CurrentBatch.Job.Name)); err != nil {
+		log.Error(err, "")
+	}
+}
+
+func generateKickOutComment(jobName string) string {
+	return fmt.Sprintf("[MERGE ALERT]\n\nThis pull request failed the batch test of the merge queue (IntegrationJob `%s`), and is kicked out from the merge pool. "+
+		"It will be merged once its checks pass again.", jobName)
+}
+
 func (b *blocker) tryMerge(pr *PullRequest, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
 	var err error
 	const maxRetry = 3
@@ -220,6 +390,76 @@ func (b *blocker) tryMerge(pr *PullRequest, ic *cicdv1.IntegrationConfig, gitCli
 	return err
 }
 
+// reportDryRun comments what the merger would do for the PR, only if it's changed since the last report
+// getDryRunResult evaluates what the merger would do for the candidate, just as it does for the first candidate
+func getDryRunResult(pr *PullRequest, ic *cicdv1.IntegrationConfig, candidates []*PullRequest, gitCli git.Client) (string, error) {
+	branch := cicdv1.GitRef(pr.Base.Ref).GetBranch()
+	isBaseLatest, err := checkBaseSHA(branch, ic, pr, gitCli)
+	if err != nil {
+		return "", err
+	}
+	isStale := checkStale(ic, pr, time.Now())
+	queued := shouldQueue(ic, candidates, branch)
+
+	if isBaseLatest && !isStale && !queued {
+		return fmt.Sprintf("would be merged into `%s` by `%s` method", branch, getMergeMethod(pr, ic)), nil
+	}
+	if !isBaseLatest && !queued && ic.Spec.MergeConfig.UpdateBranch {
+		return fmt.Sprintf("would have its branch updated with `%s`, and be merged after the checks pass again", branch), nil
+	}
+	return "would be tested again in a batch, and be merged if the test passes", nil
+}
+
+func (b *blocker) reportDryRun(pr *PullRequest, ic *cicdv1.IntegrationConfig, gitCli git.Client, result string) {
+	log := b.log.WithName("merger").WithValues("repo", genPoolKey(ic))
+	log.Info(fmt.Sprintf("[dry-run] PR #%d %s", pr.ID, result))
+
+	if pr.DryRunResult == result && pr.dryRunReportedSHA == pr.Head.Sha {
+		return
+	}
+	if err := gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateDryRunComment(result)); err != nil {
+		log.Error(err, "")
+		return
+	}
+	pr.DryRunResult = result
+	pr.dryRunReportedSHA = pr.Head.Sha
+}
+
+func generateDryRunComment(result string) string {
+	return fmt.Sprintf("[MERGE ALERT]\n\nMerge automation is in the dry-run mode. This pull request %s.", result)
+}
+
+// shouldQueue checks if the PRs into the branch should be tested together by the merge queue, i.e., the merge queue is
+// configured to batch multiple PRs and there are multiple PRs into the branch
+func shouldQueue(ic *cicdv1.IntegrationConfig, candidates []*PullRequest, branch string) bool {
+	if ic.Spec.MergeConfig.Queue == nil || getBatchSize(ic) <= 1 {
+		return false
+	}
+	cnt := 0
+	for _, p := range candidates {
+		if cicdv1.GitRef(p.Base.Ref).GetBranch() == branch {
+			cnt++
+		}
+	}
+	return cnt > 1
+}
+
+// getBatchSize returns the maximum number of the PRs tested together
+func getBatchSize(ic *cicdv1.IntegrationConfig) int {
+	if ic.Spec.MergeConfig.Queue != nil && ic.Spec.MergeConfig.Queue.BatchSize > 0 {
+		return ic.Spec.MergeConfig.Queue.BatchSize
+	}
+	return maxBatchSize
+}
+
+// getRetestPeriod returns the minimum interval between the retests of a PR
+func getRetestPeriod(ic *cicdv1.IntegrationConfig) time.Duration {
+	if ic.Spec.MergeConfig.Sync != nil && ic.Spec.MergeConfig.Sync.RetestPeriod != nil {
+		return ic.Spec.MergeConfig.Sync.RetestPeriod.Duration
+	}
+	return time.Duration(configs.MergeRetestPeriod) * time.Minute
+}
+
 func getGitPRsFromPRs(prs []*PullRequest) []git.PullRequest {
 	gitPRs := []git.PullRequest{}
 	for _, p := range prs {
@@ -231,6 +471,18 @@ func getGitPRsFromPRs(prs []*PullRequest) []git.PullRequest {
 func (b *blocker) createIntegrationJobForBatch(prs []git.PullRequest, ic *cicdv1.IntegrationConfig, batchJob *types.NamespacedName) error {
 	// The PRs in batch are assumed to have the same 'repo'.
 	dummy := git.User{Name: "tmax-cicd-bot", Email: "bot@cicd.tmax.io"}
+
+	// Jobs in the config file of the first pull request are used for the batch
+	if ic.Spec.Jobs.ConfigFile != nil {
+		gitCli, err := utils.GetGitCli(ic, b.client)
+		if err != nil {
+			return err
+		}
+		ic, err = dispatcher.LoadConfigFile(ic, gitCli, prs[0].Head.Sha)
+		if err != nil {
+			return err
+		}
+	}
 	ij := dispatcher.GeneratePreSubmit(prs, &git.Repository{Name: ic.Spec.Git.Repository, URL: prs[0].URL}, &dummy, ic)
 	*batchJob = types.NamespacedName{Name: ij.Name, Namespace: ij.Namespace}
 	if err := b.client.Create(context.Background(), ij); err != nil {
@@ -244,6 +496,8 @@ func (b *blocker) mergePullRequest(pr *PullRequest, ic *cicdv1.IntegrationConfig
 	log := b.log.WithName("merger").WithValues("repo", genPoolKey(ic))
 	log.Info(fmt.Sprintf("Merging PR #%d into %s", pr.ID, cicdv1.GitRef(pr.Base.Ref).GetBranch()))
 
+	method := getMergeMethod(pr, ic)
+
 	// Compile commit message
 	commitMsg := ""
 	if ic.Spec.MergeConfig.CommitTemplate != "" {
@@ -264,19 +518,50 @@ func (b *blocker) mergePullRequest(pr *PullRequest, ic *cicdv1.IntegrationConfig
 			return err
 		}
 		commitMsg = buf.String()
+	} else if method == git.MergeMethodSquash && ic.Spec.MergeConfig.Squash != nil && ic.Spec.MergeConfig.Squash.IncludeCommits {
+		commits, err := gitCli.ListPullRequestCommits(pr.ID)
+		if err != nil {
+			return err
+		}
+		commitMsg = squashCommitMessage(pr, commits)
 	}
-	if err := gitCli.MergePullRequest(pr.ID, pr.Head.Sha, getMergeMethod(pr, ic), commitMsg); err != nil {
+	if err := gitCli.MergePullRequest(pr.ID, pr.Head.Sha, method, commitMsg); err != nil {
 		return err
 	}
+
+	// Delete the head branch. The PR is already merged, so the failure is not returned not to merge it again
+	if ic.Spec.MergeConfig.DeleteBranch && !pr.Fork {
+		if err := gitCli.DeleteBranch(cicdv1.GitRef(pr.Head.Ref).GetBranch()); err != nil {
+			log.Error(err, fmt.Sprintf("cannot delete the branch of PR #%d", pr.ID))
+		}
+	}
 	return nil
 }
 
+// squashCommitMessage lists the messages of the squashed commits in the body, like GitHub does
+func squashCommitMessage(pr *PullRequest, commits []git.Commit) string {
+	msg := fmt.Sprintf("%s (#%d)\n", pr.Title, pr.ID)
+	for _, c := range commits {
+		msg += fmt.Sprintf("\n* %s\n", c.Message)
+	}
+	return msg
+}
+
 func getMergeMethod(pr *PullRequest, ic *cicdv1.IntegrationConfig) git.MergeMethod {
 	method := ic.Spec.MergeConfig.Method
 	if method == "" {
 		method = git.MergeMethodMerge
 	}
 
+	// Check per-branch override
+	branch := cicdv1.GitRef(pr.Base.Ref).GetBranch()
+	for _, m := range ic.Spec.MergeConfig.BranchMethods {
+		if m.Branch == branch {
+			method = m.Method
+			break
+		}
+	}
+
 	// Check squash/merge label
 	for _, l := range pr.Labels {
 		if configs.MergeKindSquashLabel != "" && l.Name == configs.MergeKindSquashLabel {
@@ -298,6 +583,20 @@ func checkBaseSHA(baseBranch string, ic *cicdv1.IntegrationConfig, pr *PullReque
 	}
 	latest := branch.CommitID
 
+	ic, err = dispatcher.LoadConfigFile(ic, gitCli, pr.Head.Sha)
+	if err != nil {
+		return false, err
+	}
+
+	// The jobs' statuses are summarized into a single commit status, if it's aggregated
+	if aggregateContext := ic.GetAggregateCommitStatusContext(); aggregateContext != "" {
+		status, exist := pr.Statuses[aggregateContext]
+		if !exist {
+			return false, nil
+		}
+		return pipelinemanager.ParseBaseFromDescription(status.Description) == latest, nil
+	}
+
 	jobs := dispatcher.FilterJobs(ic.Spec.Jobs.PreSubmit, git.EventTypePullRequest, pr.Base.Ref)
 	for _, j := range jobs {
 		status, exist := pr.Statuses[j.Name]
@@ -313,6 +612,23 @@ func checkBaseSHA(baseBranch string, ic *cicdv1.IntegrationConfig, pr *PullReque
 	return true, nil
 }
 
+// checkStale checks if any successful check of the PR is older than the staleness threshold. The checks without the
+// update time are not considered stale
+func checkStale(ic *cicdv1.IntegrationConfig, pr *PullRequest, now time.Time) bool {
+	if ic.Spec.MergeConfig.StaleAfter == nil {
+		return false
+	}
+	for context, s := range pr.Statuses {
+		if context == blockerContext || s.State != git.CommitStatusStateSuccess || s.UpdatedAt == nil {
+			continue
+		}
+		if now.Sub(s.UpdatedAt.Time) > ic.Spec.MergeConfig.StaleAfter.Duration {
+			return true
+		}
+	}
+	return false
+}
+
 func sortPullRequestByID(prs map[int]*PullRequest) PullRequestByID {
 	var candidates PullRequestByID
 	for _, pr := range prs {
@@ -322,6 +638,26 @@ func sortPullRequestByID(prs map[int]*PullRequest) PullRequestByID {
 	return candidates
 }
 
+// sortPullRequestByPriority sorts the PRs by the priority labels they have, and then by their IDs
+func sortPullRequestByPriority(prs map[int]*PullRequest, priorityLabels []string) []*PullRequest {
+	candidates := sortPullRequestByID(prs)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return getPriority(candidates[i], priorityLabels) < getPriority(candidates[j], priorityLabels)
+	})
+	return candidates
+}
+
+// getPriority returns the index of the first priority label the PR has. The lower is prioritized, and the PRs without
+// any of them have the lowest priority
+func getPriority(pr *PullRequest, priorityLabels []string) int {
+	for i, label := range priorityLabels {
+		if hasLabel(pr.Labels, label) {
+			return i
+		}
+	}
+	return len(priorityLabels)
+}
+
 // PullRequestByID is a PR id, sorted by ID
 type PullRequestByID []*PullRequest
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/bmizerany/assert"
 	"github.com/stretchr/testify/require"
@@ -59,17 +60,189 @@ func TestSortPullRequestByID(t *testing.T) {
 	assert.Equal(t, 72, sorted[2].ID, "3rd PR")
 }
 
+func TestSortPullRequestByPriority(t *testing.T) {
+	prs := map[int]*PullRequest{
+		13: {PullRequest: git.PullRequest{ID: 13, Labels: []git.IssueLabel{{Name: "priority/high"}}}},
+		6:  {PullRequest: git.PullRequest{ID: 6}},
+		72: {PullRequest: git.PullRequest{ID: 72, Labels: []git.IssueLabel{{Name: "priority/high"}, {Name: "priority/critical"}}}},
+		21: {PullRequest: git.PullRequest{ID: 21, Labels: []git.IssueLabel{{Name: "priority/high"}}}},
+	}
+
+	sorted := sortPullRequestByPriority(prs, []string{"priority/critical", "priority/high"})
+
+	assert.Equal(t, 4, len(sorted), "Length")
+	assert.Equal(t, 72, sorted[0].ID, "1st PR")
+	assert.Equal(t, 13, sorted[1].ID, "2nd PR")
+	assert.Equal(t, 21, sorted[2].ID, "3rd PR")
+	assert.Equal(t, 6, sorted[3].ID, "4th PR")
+
+	// No priority labels
+	sorted = sortPullRequestByPriority(prs, nil)
+	assert.Equal(t, 6, sorted[0].ID, "1st PR")
+	assert.Equal(t, 13, sorted[1].ID, "2nd PR")
+	assert.Equal(t, 21, sorted[2].ID, "3rd PR")
+	assert.Equal(t, 72, sorted[3].ID, "4th PR")
+}
+
 func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 	tc := map[string]struct {
 		prs           []*PullRequest
 		baseSHA       string
 		existingBatch *Batch
 		existingJob   *cicdv1.IntegrationJob
+		queue         *cicdv1.MergeQueue
+		updateBranch  bool
+		staleAfter    *metav1.Duration
+		dryRun        bool
+		retestPeriod  *metav1.Duration
+		// statusesOutdated sets the PRs' statuses as fetched for their old heads
+		statusesOutdated bool
 
-		expectedIJRefPulls   []cicdv1.IntegrationJobRefsPull
-		expectedBatchCreated bool
-		expectedPRMerged     bool
+		expectedIJRefPulls      []cicdv1.IntegrationJobRefsPull
+		expectedBatchCreated    bool
+		expectedPRMerged        bool
+		expectedBranchesUpdated []int
+		expectedDryRunComments  map[int][]string
 	}{
+		"dryRun": {
+			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			dryRun:                 true,
+			expectedDryRunComments: map[int][]string{12: {"[MERGE ALERT]\n\nMerge automation is in the dry-run mode. This pull request would be merged into `master` by `merge` method."}},
+		},
+		"dryRunAllCandidates": {
+			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+				{
+					PullRequest: git.PullRequest{
+						ID:        13,
+						Base:      git.Base{Ref: "master", Sha: "32cd89e8d07e37ab26d8c735090ae763884283db"},
+						Head:      git.Head{Ref: "newnew2", Sha: "3bede531bd0bbe8d3735f2642193fb33800149e0"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:32cd89e8d07e37ab26d8c735090ae763884283db"},
+					},
+				},
+			},
+			dryRun: true,
+			expectedDryRunComments: map[int][]string{
+				12: {"[MERGE ALERT]\n\nMerge automation is in the dry-run mode. This pull request would be merged into `master` by `merge` method."},
+				13: {"[MERGE ALERT]\n\nMerge automation is in the dry-run mode. This pull request would be tested again in a batch, and be merged if the test passes, after the 1 pull request(s) ahead of it in the merge order."},
+			},
+		},
+		"dryRunBaseUpdates": {
+			baseSHA: "32cd89e8d07e37ab26d8c735090ae763884283db",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			dryRun:                 true,
+			expectedDryRunComments: map[int][]string{12: {"[MERGE ALERT]\n\nMerge automation is in the dry-run mode. This pull request would be tested again in a batch, and be merged if the test passes."}},
+		},
+		"statusesOutdated": {
+			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			statusesOutdated: true,
+		},
+		"retestPeriod": {
+			baseSHA: "32cd89e8d07e37ab26d8c735090ae763884283db",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+					lastRetestTime: time.Now().Add(-time.Minute),
+				},
+			},
+			retestPeriod: &metav1.Duration{Duration: time.Hour},
+		},
+		"retestPeriodPassed": {
+			baseSHA: "32cd89e8d07e37ab26d8c735090ae763884283db",
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+					lastRetestTime: time.Now().Add(-2 * time.Hour),
+				},
+			},
+			retestPeriod: &metav1.Duration{Duration: time.Hour},
+			expectedIJRefPulls: []cicdv1.IntegrationJobRefsPull{
+				{ID: 12, Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9", Author: cicdv1.IntegrationJobRefsPullAuthor{}},
+			},
+			expectedBatchCreated: true,
+		},
 		"successful": {
 			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
 			prs: []*PullRequest{
@@ -111,6 +284,112 @@ func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 			},
 			expectedBatchCreated: true,
 		},
+		"stale": {
+			baseSHA:    "22ccae53032027186ba739dfaa473ee61a82b298",
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298", UpdatedAt: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}},
+					},
+				},
+			},
+			expectedIJRefPulls: []cicdv1.IntegrationJobRefsPull{
+				{ID: 12, Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9", Author: cicdv1.IntegrationJobRefsPullAuthor{}},
+			},
+			expectedBatchCreated: true,
+		},
+		"notStale": {
+			baseSHA:    "22ccae53032027186ba739dfaa473ee61a82b298",
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298", UpdatedAt: &metav1.Time{Time: time.Now().Add(-time.Minute)}},
+					},
+				},
+			},
+			expectedPRMerged: true,
+		},
+		"updateBranch": {
+			baseSHA:      "32cd89e8d07e37ab26d8c735090ae763884283db",
+			updateBranch: true,
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			expectedBranchesUpdated: []int{12},
+		},
+		"updateBranchWaiting": {
+			baseSHA:      "32cd89e8d07e37ab26d8c735090ae763884283db",
+			updateBranch: true,
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+					BranchUpdatedFrom: "3196ccc37bcae94852079b04fcbfaf928341d6e9",
+					branchUpdatedTime: time.Now(),
+				},
+			},
+		},
+		"updateBranchWaitingTimeout": {
+			baseSHA:      "32cd89e8d07e37ab26d8c735090ae763884283db",
+			updateBranch: true,
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+					BranchUpdatedFrom: "3196ccc37bcae94852079b04fcbfaf928341d6e9",
+					branchUpdatedTime: time.Now().Add(-time.Hour),
+				},
+			},
+			expectedBranchesUpdated: []int{12},
+		},
 		"multiplePRsRetest": {
 			baseSHA: "32cd89e8d07e37ab26d8c735090ae763884283db",
 			prs: []*PullRequest{
@@ -147,6 +426,63 @@ func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 			},
 			expectedBatchCreated: true,
 		},
+		"queue": {
+			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
+			queue:   &cicdv1.MergeQueue{},
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "fix/1", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+				{
+					PullRequest: git.PullRequest{
+						ID:        13,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "fix/2", Sha: "3bede531bd0bbe8d3735f2642193fb33800149e0"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			expectedIJRefPulls: []cicdv1.IntegrationJobRefsPull{
+				{ID: 12, Ref: "fix/1", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9", Author: cicdv1.IntegrationJobRefsPullAuthor{}},
+				{ID: 13, Ref: "fix/2", Sha: "3bede531bd0bbe8d3735f2642193fb33800149e0", Author: cicdv1.IntegrationJobRefsPullAuthor{}},
+			},
+			expectedBatchCreated: true,
+		},
+		"queueSinglePR": {
+			baseSHA: "22ccae53032027186ba739dfaa473ee61a82b298",
+			queue:   &cicdv1.MergeQueue{},
+			prs: []*PullRequest{
+				{
+					PullRequest: git.PullRequest{
+						ID:        12,
+						Base:      git.Base{Ref: "master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+						Head:      git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+						Mergeable: true,
+						State:     git.PullRequestStateOpen,
+					},
+					BlockerStatus: git.CommitStatusStateSuccess,
+					Statuses: map[string]git.CommitStatus{
+						"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+					},
+				},
+			},
+			expectedPRMerged: true,
+		},
 		"batchSuccessful": {
 			baseSHA: "32cd89e8d07e37ab26d8c735090ae763884283db",
 			prs: []*PullRequest{
@@ -221,15 +557,28 @@ func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			// Init
 			ic, cli := mergeTestConfig()
+			if c.queue != nil || c.updateBranch || c.staleAfter != nil || c.dryRun || c.retestPeriod != nil {
+				ic.Spec.MergeConfig.Queue = c.queue
+				ic.Spec.MergeConfig.UpdateBranch = c.updateBranch
+				ic.Spec.MergeConfig.StaleAfter = c.staleAfter
+				ic.Spec.MergeConfig.DryRun = c.dryRun
+				if c.retestPeriod != nil {
+					ic.Spec.MergeConfig.Sync = &cicdv1.MergeSync{RetestPeriod: c.retestPeriod}
+				}
+				require.NoError(t, cli.Update(context.Background(), ic))
+			}
 			b := New(cli)
 			gitfake.Repos = map[string]*gitfake.Repo{
-				ic.Spec.Git.Repository: {PullRequests: map[int]*git.PullRequest{}, Commits: map[string][]git.Commit{}},
+				ic.Spec.Git.Repository: {PullRequests: map[int]*git.PullRequest{}, Commits: map[string][]git.Commit{}, Comments: map[int][]git.IssueComment{}},
 			}
 			gitfake.Branches = map[string]*git.Branch{
 				"master": {CommitID: c.baseSHA},
 			}
 			pool := NewPRPool(testICNamespace, testICName)
 			for _, pr := range c.prs {
+				if !c.statusesOutdated {
+					pr.statusesSHA = pr.Head.Sha
+				}
 				pool.PullRequests[pr.ID] = pr
 				pool.MergePool.Add(pr)
 				gitfake.Repos[ic.Spec.Git.Repository].PullRequests[pr.ID] = &pr.PullRequest
@@ -268,6 +617,19 @@ func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 				require.Nil(t, pool.CurrentBatch, "Current batch")
 			}
 
+			require.Equal(t, c.expectedBranchesUpdated, gitfake.Repos[ic.Spec.Git.Repository].UpdatedBranches)
+
+			var dryRunComments map[int][]string
+			for _, pr := range c.prs {
+				for _, comment := range gitfake.Repos[ic.Spec.Git.Repository].Comments[pr.ID] {
+					if dryRunComments == nil {
+						dryRunComments = map[int][]string{}
+					}
+					dryRunComments[pr.ID] = append(dryRunComments[pr.ID], comment.Comment.Body)
+				}
+			}
+			require.Equal(t, c.expectedDryRunComments, dryRunComments)
+
 			for _, pr := range c.prs {
 				if c.expectedPRMerged {
 					require.False(t, gitfake.Repos[ic.Spec.Git.Repository].PullRequests[pr.ID].Mergeable)
@@ -281,6 +643,206 @@ func TestBlocker_retestAndMergeOnePool(t *testing.T) {
 	}
 }
 
+func TestCheckBaseSHA(t *testing.T) {
+	tc := map[string]struct {
+		aggregate bool
+		statuses  map[string]git.CommitStatus
+
+		expected bool
+	}{
+		"latest": {
+			statuses: map[string]git.CommitStatus{
+				"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+			},
+			expected: true,
+		},
+		"outdated": {
+			statuses: map[string]git.CommitStatus{
+				"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, Description: "Job is successful    BaseSHA:32cd89e8d07e37ab26d8c735090ae763884283db"},
+			},
+			expected: false,
+		},
+		"aggregateLatest": {
+			aggregate: true,
+			statuses: map[string]git.CommitStatus{
+				cicdv1.DefaultAggregateCommitStatusContext: {Context: cicdv1.DefaultAggregateCommitStatusContext, State: git.CommitStatusStateSuccess, Description: "1/1 jobs succeeded    BaseSHA:22ccae53032027186ba739dfaa473ee61a82b298"},
+			},
+			expected: true,
+		},
+		"aggregateOutdated": {
+			aggregate: true,
+			statuses: map[string]git.CommitStatus{
+				cicdv1.DefaultAggregateCommitStatusContext: {Context: cicdv1.DefaultAggregateCommitStatusContext, State: git.CommitStatusStateSuccess, Description: "1/1 jobs succeeded    BaseSHA:32cd89e8d07e37ab26d8c735090ae763884283db"},
+			},
+			expected: false,
+		},
+		"aggregateNotExist": {
+			aggregate: true,
+			statuses:  map[string]git.CommitStatus{},
+			expected:  false,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic, cli := mergeTestConfig()
+			if c.aggregate {
+				ic.Spec.AggregateCommitStatus = &cicdv1.AggregateCommitStatus{}
+			}
+			gitCli, err := utils.GetGitCli(ic, cli)
+			require.NoError(t, err)
+			gitfake.Branches = map[string]*git.Branch{
+				"master": {CommitID: "22ccae53032027186ba739dfaa473ee61a82b298"},
+			}
+
+			pr := &PullRequest{
+				PullRequest: git.PullRequest{
+					ID:   12,
+					Base: git.Base{Ref: "master"},
+					Head: git.Head{Ref: "newnew", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+				},
+				Statuses: c.statuses,
+			}
+			isLatest, err := checkBaseSHA("master", ic, pr, gitCli)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, isLatest)
+		})
+	}
+}
+
+func TestBlocker_reportDryRun(t *testing.T) {
+	ic, cli := mergeTestConfig()
+	gitCli, err := utils.GetGitCli(ic, cli)
+	require.NoError(t, err)
+	b := New(cli)
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		ic.Spec.Git.Repository: {Comments: map[int][]git.IssueComment{}},
+	}
+	pr := &PullRequest{PullRequest: git.PullRequest{ID: 5, Head: git.Head{Sha: testSHA}}}
+
+	// Reported
+	b.reportDryRun(pr, ic, gitCli, "would be merged")
+	require.Len(t, gitfake.Repos[ic.Spec.Git.Repository].Comments[5], 1)
+	require.Equal(t, "would be merged", pr.DryRunResult)
+
+	// Not reported again for the same result
+	b.reportDryRun(pr, ic, gitCli, "would be merged")
+	require.Len(t, gitfake.Repos[ic.Spec.Git.Repository].Comments[5], 1)
+
+	// Reported again for the new commit
+	pr.Head.Sha = "3196ccc37bcae94852079b04fcbfaf928341d6e9"
+	b.reportDryRun(pr, ic, gitCli, "would be merged")
+	require.Len(t, gitfake.Repos[ic.Spec.Git.Repository].Comments[5], 2)
+
+	// Reported again for the new result
+	b.reportDryRun(pr, ic, gitCli, "would be tested again")
+	require.Len(t, gitfake.Repos[ic.Spec.Git.Repository].Comments[5], 3)
+	require.Equal(t, "would be tested again", pr.DryRunResult)
+}
+
+func TestExcludeBranchUpdating(t *testing.T) {
+	now := time.Now()
+	waiting := &PullRequest{PullRequest: git.PullRequest{ID: 1, Head: git.Head{Sha: "sha1"}}, BranchUpdatedFrom: "sha1", branchUpdatedTime: now.Add(-time.Minute)}
+	timedOut := &PullRequest{PullRequest: git.PullRequest{ID: 2, Head: git.Head{Sha: "sha2"}}, BranchUpdatedFrom: "sha2", branchUpdatedTime: now.Add(-time.Hour)}
+	synced := &PullRequest{PullRequest: git.PullRequest{ID: 3, Head: git.Head{Sha: "sha3-new"}}, BranchUpdatedFrom: "sha3", branchUpdatedTime: now.Add(-time.Minute)}
+	normal := &PullRequest{PullRequest: git.PullRequest{ID: 4, Head: git.Head{Sha: "sha4"}}}
+
+	result := excludeBranchUpdating([]*PullRequest{waiting, timedOut, synced, normal}, now, log)
+	require.Equal(t, []*PullRequest{timedOut, synced, normal}, result)
+	require.Equal(t, "sha1", waiting.BranchUpdatedFrom)
+	require.Empty(t, timedOut.BranchUpdatedFrom)
+}
+
+func TestCheckStale(t *testing.T) {
+	now := time.Date(2021, 12, 24, 12, 0, 0, 0, time.UTC)
+	old := &metav1.Time{Time: now.Add(-2 * time.Hour)}
+	recent := &metav1.Time{Time: now.Add(-time.Minute)}
+
+	tc := map[string]struct {
+		staleAfter *metav1.Duration
+		statuses   map[string]git.CommitStatus
+
+		expectedStale bool
+	}{
+		"noThreshold": {
+			statuses: map[string]git.CommitStatus{"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, UpdatedAt: old}},
+		},
+		"recent": {
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			statuses:   map[string]git.CommitStatus{"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, UpdatedAt: recent}},
+		},
+		"stale": {
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			statuses: map[string]git.CommitStatus{
+				"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess, UpdatedAt: recent},
+				"test-2": {Context: "test-2", State: git.CommitStatusStateSuccess, UpdatedAt: old},
+			},
+			expectedStale: true,
+		},
+		"noUpdateTime": {
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			statuses:   map[string]git.CommitStatus{"test-1": {Context: "test-1", State: git.CommitStatusStateSuccess}},
+		},
+		"oldBlocker": {
+			staleAfter: &metav1.Duration{Duration: time.Hour},
+			statuses:   map[string]git.CommitStatus{blockerContext: {Context: blockerContext, State: git.CommitStatusStateSuccess, UpdatedAt: old}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{MergeConfig: &cicdv1.MergeConfig{StaleAfter: c.staleAfter}}}
+			require.Equal(t, c.expectedStale, checkStale(ic, &PullRequest{Statuses: c.statuses}, now))
+		})
+	}
+}
+
+func TestShouldQueue(t *testing.T) {
+	prs := []*PullRequest{
+		{PullRequest: git.PullRequest{ID: 12, Base: git.Base{Ref: "master"}}},
+		{PullRequest: git.PullRequest{ID: 13, Base: git.Base{Ref: "release"}}},
+		{PullRequest: git.PullRequest{ID: 14, Base: git.Base{Ref: "master"}}},
+	}
+
+	tc := map[string]struct {
+		queue  *cicdv1.MergeQueue
+		branch string
+
+		expectedQueue     bool
+		expectedBatchSize int
+	}{
+		"noQueue": {
+			branch:            "master",
+			expectedBatchSize: 10,
+		},
+		"queue": {
+			queue:             &cicdv1.MergeQueue{},
+			branch:            "master",
+			expectedQueue:     true,
+			expectedBatchSize: 10,
+		},
+		"singlePR": {
+			queue:             &cicdv1.MergeQueue{BatchSize: 5},
+			branch:            "release",
+			expectedBatchSize: 5,
+		},
+		"batchSizeOne": {
+			queue:             &cicdv1.MergeQueue{BatchSize: 1},
+			branch:            "master",
+			expectedBatchSize: 1,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{MergeConfig: &cicdv1.MergeConfig{Queue: c.queue}}}
+			require.Equal(t, c.expectedQueue, shouldQueue(ic, prs, c.branch))
+			require.Equal(t, c.expectedBatchSize, getBatchSize(ic))
+		})
+	}
+}
+
 func TestBlocker_handleBatch(t *testing.T) {
 	ic, cli := mergeTestConfig()
 	gitCli, _ := utils.GetGitCli(ic, cli)
@@ -318,6 +880,10 @@ func TestBlocker_handleBatch(t *testing.T) {
 		ij2.Status.State = cicdv1.IntegrationJobStateCompleted
 		_ = cli.Status().Update(context.Background(), ij2)
 
+		gitfake.Repos = map[string]*gitfake.Repo{
+			ic.Spec.Git.Repository: {PullRequests: map[int]*git.PullRequest{12: {ID: 12}}, Commits: map[string][]git.Commit{}},
+		}
+
 		pool.CurrentBatch = &Batch{
 			PRs: []*PullRequest{{PullRequest: git.PullRequest{ID: 12}}},
 			Job: types.NamespacedName{Name: "test-ij-2", Namespace: testICNamespace},
@@ -327,6 +893,127 @@ func TestBlocker_handleBatch(t *testing.T) {
 		}
 		assert.Equal(t, true, pool.CurrentBatch == nil, "CurrentBatch cleared")
 	})
+
+	// TEST 2-1 - Head of a PR is changed during the batch test
+	t.Run("batch_head_changed", func(t *testing.T) {
+		ij := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ij-2-1", Namespace: testICNamespace},
+			Spec: cicdv1.IntegrationJobSpec{Refs: cicdv1.IntegrationJobRefs{Pulls: []cicdv1.IntegrationJobRefsPull{
+				{ID: 12, Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+				{ID: 13, Sha: "3bede531bd0bbe8d3735f2642193fb33800149e0"},
+			}}},
+		}
+		_ = cli.Create(context.Background(), ij)
+		ij.Status.State = cicdv1.IntegrationJobStateCompleted
+		_ = cli.Status().Update(context.Background(), ij)
+
+		gitfake.Repos = map[string]*gitfake.Repo{
+			ic.Spec.Git.Repository: {PullRequests: map[int]*git.PullRequest{12: {ID: 12}, 13: {ID: 13}}, Commits: map[string][]git.Commit{}},
+		}
+
+		pool.CurrentBatch = &Batch{
+			PRs: []*PullRequest{
+				{PullRequest: git.PullRequest{ID: 12, Head: git.Head{Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"}}},
+				{PullRequest: git.PullRequest{ID: 13, Head: git.Head{Sha: "a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3"}}},
+			},
+			Job: types.NamespacedName{Name: "test-ij-2-1", Namespace: testICNamespace},
+		}
+		require.NoError(t, b.handleBatch(pool, ic, gitCli))
+		require.Nil(t, pool.CurrentBatch)
+		require.True(t, gitfake.Repos[ic.Spec.Git.Repository].PullRequests[12].Merged)
+		require.False(t, gitfake.Repos[ic.Spec.Git.Repository].PullRequests[13].Merged, "Untested head is not merged")
+	})
+
+	// TEST 3 - Batch IJ of the merge queue fails
+	t.Run("queue_batch_ij_fails", func(t *testing.T) {
+		ij3 := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ij-3", Namespace: testICNamespace},
+		}
+		_ = cli.Create(context.Background(), ij3)
+		ij3.Status.State = cicdv1.IntegrationJobStateFailed
+		_ = cli.Status().Update(context.Background(), ij3)
+
+		queueIC := ic.DeepCopy()
+		queueIC.Spec.MergeConfig.Queue = &cicdv1.MergeQueue{}
+		pool.CurrentBatch = &Batch{
+			PRs: []*PullRequest{{PullRequest: git.PullRequest{ID: 12}},
+				{PullRequest: git.PullRequest{ID: 23}},
+				{PullRequest: git.PullRequest{ID: 37}},
+				{PullRequest: git.PullRequest{ID: 41}},
+				{PullRequest: git.PullRequest{ID: 52}}},
+			Job: types.NamespacedName{Name: "test-ij-3", Namespace: testICNamespace},
+		}
+		require.NoError(t, b.handleBatch(pool, queueIC, gitCli))
+		require.Len(t, pool.CurrentBatch.PRs, 2, "CurrentBatch is bisected")
+		require.Equal(t, 23, pool.CurrentBatch.PRs[1].ID)
+		require.Len(t, pool.CurrentBatch.Rest, 3, "The second half is tested later")
+		require.Equal(t, 37, pool.CurrentBatch.Rest[0].ID)
+		require.True(t, pool.CurrentBatch.Contains(52))
+	})
+
+	// TEST 4 - A single PR fails the test while the batch is bisected
+	t.Run("queue_single_pr_fails", func(t *testing.T) {
+		ij4 := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ij-4", Namespace: testICNamespace},
+		}
+		_ = cli.Create(context.Background(), ij4)
+		ij4.Status.State = cicdv1.IntegrationJobStateFailed
+		_ = cli.Status().Update(context.Background(), ij4)
+
+		gitfake.Repos = map[string]*gitfake.Repo{
+			ic.Spec.Git.Repository: {Comments: map[int][]git.IssueComment{}},
+		}
+
+		queueIC := ic.DeepCopy()
+		queueIC.Spec.MergeConfig.Queue = &cicdv1.MergeQueue{}
+		offending := &PullRequest{PullRequest: git.PullRequest{ID: 37}, BlockerStatus: git.CommitStatusStateSuccess}
+		pool.MergePool = NewMergePool()
+		pool.MergePool.Add(offending)
+		pool.CurrentBatch = &Batch{
+			PRs:  []*PullRequest{offending},
+			Rest: []*PullRequest{{PullRequest: git.PullRequest{ID: 41, Head: git.Head{Sha: testSHA}}}, {PullRequest: git.PullRequest{ID: 52, Head: git.Head{Sha: testSHA}}}},
+			Job:  types.NamespacedName{Name: "test-ij-4", Namespace: testICNamespace},
+		}
+		require.NoError(t, b.handleBatch(pool, queueIC, gitCli))
+
+		require.NotNil(t, pool.MergePool[git.CommitStatusStatePending][37], "The offending PR is kicked out")
+		require.Nil(t, pool.MergePool[git.CommitStatusStateSuccess][37])
+		require.Len(t, gitfake.Repos[ic.Spec.Git.Repository].Comments[37], 1)
+		require.Equal(t, "[MERGE ALERT]\n\nThis pull request failed the batch test of the merge queue (IntegrationJob `test-ij-4`), "+
+			"and is kicked out from the merge pool. It will be merged once its checks pass again.", gitfake.Repos[ic.Spec.Git.Repository].Comments[37][0].Comment.Body)
+
+		require.Len(t, pool.CurrentBatch.PRs, 2, "The rest of the batch is tested")
+		require.Equal(t, 41, pool.CurrentBatch.PRs[0].ID)
+		require.Empty(t, pool.CurrentBatch.Rest)
+	})
+
+	// TEST 5 - The first half of the bisected batch succeeds
+	t.Run("queue_bisected_batch_succeeds", func(t *testing.T) {
+		ij5 := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ij-5", Namespace: testICNamespace},
+		}
+		_ = cli.Create(context.Background(), ij5)
+		ij5.Status.State = cicdv1.IntegrationJobStateCompleted
+		_ = cli.Status().Update(context.Background(), ij5)
+
+		gitfake.Repos = map[string]*gitfake.Repo{
+			ic.Spec.Git.Repository: {PullRequests: map[int]*git.PullRequest{12: {ID: 12}}, Commits: map[string][]git.Commit{}},
+		}
+
+		queueIC := ic.DeepCopy()
+		queueIC.Spec.MergeConfig.Queue = &cicdv1.MergeQueue{}
+		pool.CurrentBatch = &Batch{
+			PRs:  []*PullRequest{{PullRequest: git.PullRequest{ID: 12}}},
+			Rest: []*PullRequest{{PullRequest: git.PullRequest{ID: 23, Head: git.Head{Sha: testSHA}}}},
+			Job:  types.NamespacedName{Name: "test-ij-5", Namespace: testICNamespace},
+		}
+		require.NoError(t, b.handleBatch(pool, queueIC, gitCli))
+
+		require.True(t, gitfake.Repos[ic.Spec.Git.Repository].PullRequests[12].Merged)
+		require.Len(t, pool.CurrentBatch.PRs, 1, "The rest of the batch is tested")
+		require.Equal(t, 23, pool.CurrentBatch.PRs[0].ID)
+		require.Empty(t, pool.CurrentBatch.Rest)
+	})
 }
 
 func TestBlocker_mergePullRequest(t *testing.T) {
@@ -334,10 +1021,14 @@ func TestBlocker_mergePullRequest(t *testing.T) {
 		pr             git.PullRequest
 		commits        []git.Commit
 		commitTemplate string
+		method         git.MergeMethod
+		squash         *cicdv1.SquashOptions
+		deleteBranch   bool
 
-		expectedCommitMessage string
-		errorOccurs           bool
-		errorMessage          string
+		expectedCommitMessage   string
+		expectedDeletedBranches []string
+		errorOccurs             bool
+		errorMessage            string
 	}{
 		"default": {
 			pr: git.PullRequest{
@@ -379,6 +1070,57 @@ Author: author2(author2@tmax.co.kr)
 Committer: committer2(committer2@tmax.co.kr)
 `,
 		},
+		"squashIncludeCommits": {
+			pr: git.PullRequest{
+				ID:    5,
+				Title: "[feat] Add feature",
+				Head:  git.Head{Sha: testSHA},
+				Base:  git.Base{Ref: "master"},
+			},
+			commits: []git.Commit{
+				{SHA: "7523ffa4cc506f4ab2a346a5c2d8eb369d0fdb30", Message: "[fix] Fix bugs"},
+				{SHA: "3bede531bd0bbe8d3735f2642193fb33800149e0", Message: "[feat] Add features"},
+			},
+			method:                git.MergeMethodSquash,
+			squash:                &cicdv1.SquashOptions{IncludeCommits: true},
+			expectedCommitMessage: "[feat] Add feature (#5)\n\n* [fix] Fix bugs\n\n* [feat] Add features\n",
+		},
+		"squashIncludeCommitsMerge": {
+			pr: git.PullRequest{
+				ID:    5,
+				Title: "[feat] Add feature",
+				Head:  git.Head{Sha: testSHA},
+				Base:  git.Base{Ref: "master"},
+			},
+			commits: []git.Commit{
+				{SHA: "7523ffa4cc506f4ab2a346a5c2d8eb369d0fdb30", Message: "[fix] Fix bugs"},
+			},
+			method:                git.MergeMethodMerge,
+			squash:                &cicdv1.SquashOptions{IncludeCommits: true},
+			expectedCommitMessage: "[feat] Add feature(#5)",
+		},
+		"deleteBranch": {
+			pr: git.PullRequest{
+				ID:    5,
+				Title: "[feat] Add feature",
+				Head:  git.Head{Ref: "feat/new", Sha: testSHA},
+				Base:  git.Base{Ref: "master"},
+			},
+			deleteBranch:            true,
+			expectedCommitMessage:   "[feat] Add feature(#5)",
+			expectedDeletedBranches: []string{"feat/new"},
+		},
+		"deleteBranchFork": {
+			pr: git.PullRequest{
+				ID:    5,
+				Title: "[feat] Add feature",
+				Head:  git.Head{Ref: "feat/new", Sha: testSHA},
+				Base:  git.Base{Ref: "master"},
+				Fork:  true,
+			},
+			deleteBranch:          true,
+			expectedCommitMessage: "[feat] Add feature(#5)",
+		},
 		"commitTemplateError": {
 			pr: git.PullRequest{
 				ID:    5,
@@ -401,6 +1143,9 @@ Committer: committer2(committer2@tmax.co.kr)
 		t.Run(name, func(t *testing.T) {
 			ic, cli := mergeTestConfig()
 			ic.Spec.MergeConfig.CommitTemplate = c.commitTemplate
+			ic.Spec.MergeConfig.Method = c.method
+			ic.Spec.MergeConfig.Squash = c.squash
+			ic.Spec.MergeConfig.DeleteBranch = c.deleteBranch
 
 			gitCli, err := utils.GetGitCli(ic, cli)
 			require.NoError(t, err)
@@ -424,6 +1169,10 @@ Committer: committer2(committer2@tmax.co.kr)
 				require.Equal(t, c.errorMessage, err.Error())
 			} else {
 				require.NoError(t, err)
+				commits := gitfake.Repos[ic.Spec.Git.Repository].Commits["master"]
+				require.Len(t, commits, 1)
+				require.Equal(t, c.expectedCommitMessage, commits[0].Message)
+				require.Equal(t, c.expectedDeletedBranches, gitfake.Repos[ic.Spec.Git.Repository].DeletedBranches)
 			}
 		})
 	}
@@ -477,6 +1226,8 @@ func TestBlocker_tryMerge(t *testing.T) {
 type getMergeMethodTestCase struct {
 	Labels         []git.IssueLabel
 	ICMethod       git.MergeMethod
+	BaseRef        string
+	BranchMethods  []cicdv1.BranchMergeMethod
 	ExpectedMethod git.MergeMethod
 }
 
@@ -512,6 +1263,31 @@ func TestGetMergeMethod(t *testing.T) {
 			ICMethod:       git.MergeMethodMerge,
 			ExpectedMethod: git.MergeMethodSquash,
 		},
+		"default": {
+			Labels:         []git.IssueLabel{},
+			ExpectedMethod: git.MergeMethodMerge,
+		},
+		"branchOverride": {
+			Labels:         []git.IssueLabel{},
+			ICMethod:       git.MergeMethodMerge,
+			BaseRef:        "refs/heads/release",
+			BranchMethods:  []cicdv1.BranchMergeMethod{{Branch: "master", Method: git.MergeMethodSquash}, {Branch: "release", Method: git.MergeMethodRebase}},
+			ExpectedMethod: git.MergeMethodRebase,
+		},
+		"branchNotOverridden": {
+			Labels:         []git.IssueLabel{},
+			ICMethod:       git.MergeMethodMerge,
+			BaseRef:        "dev",
+			BranchMethods:  []cicdv1.BranchMergeMethod{{Branch: "master", Method: git.MergeMethodSquash}},
+			ExpectedMethod: git.MergeMethodMerge,
+		},
+		"branchOverrideLabel": {
+			Labels:         []git.IssueLabel{{Name: "global/merge-merge"}},
+			ICMethod:       git.MergeMethodSquash,
+			BaseRef:        "master",
+			BranchMethods:  []cicdv1.BranchMergeMethod{{Branch: "master", Method: git.MergeMethodRebase}},
+			ExpectedMethod: git.MergeMethodMerge,
+		},
 	}
 
 	configs.MergeKindMergeLabel = "global/merge-merge"
@@ -521,8 +1297,9 @@ func TestGetMergeMethod(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			pr := &PullRequest{}
 			pr.Labels = c.Labels
+			pr.Base.Ref = c.BaseRef
 			ic := &cicdv1.IntegrationConfig{}
-			ic.Spec.MergeConfig = &cicdv1.MergeConfig{Method: c.ICMethod}
+			ic.Spec.MergeConfig = &cicdv1.MergeConfig{Method: c.ICMethod, BranchMethods: c.BranchMethods}
 
 			method := getMergeMethod(pr, ic)
 
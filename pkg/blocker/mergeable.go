@@ -0,0 +1,132 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CheckMergeable checks if the PR can be merged right away (e.g., by the /merge command), with the same conditions as
+// the merge automation. Moreover, the required checks should exist, i.e., the checks of the PR's jobs if the required
+// checks are not specified. It returns the reason if the PR cannot be merged
+func CheckMergeable(c client.Client, ic *cicdv1.IntegrationConfig, gitCli git.Client, pull *git.PullRequest) (bool, string, error) {
+	b := &blocker{client: c}
+	pr := &PullRequest{PullRequest: *pull, Statuses: map[string]git.CommitStatus{}}
+
+	statuses, err := gitCli.ListCommitStatuses(pr.Head.Sha)
+	if err != nil {
+		return false, "", err
+	}
+	for _, s := range statuses {
+		pr.Statuses[s.Context] = s
+	}
+
+	q := cicdv1.MergeQuery{}
+	var freeze *cicdv1.MergeFreeze
+	if ic.Spec.MergeConfig != nil {
+		q = ic.Spec.MergeConfig.GetQuery(pr.Base.Ref)
+		freeze = ic.Spec.MergeConfig.Freeze
+	}
+
+	if err := b.reflectApprovers(pr, q.Approvals, gitCli); err != nil {
+		return false, "", err
+	}
+	if err := b.reflectCodeOwners(pr, q.CodeOwners, gitCli); err != nil {
+		return false, "", err
+	}
+	if err := b.reflectDependencies(pr, ic, gitCli); err != nil {
+		return false, "", err
+	}
+
+	pass, _, msg := checkConditionsFull(q, pr)
+	var messages []string
+	if msg != "" {
+		messages = append(messages, msg)
+	}
+
+	passFreeze, freezeMsg := checkFreeze(freeze, pr, time.Now())
+	if freezeMsg != "" {
+		messages = append(messages, freezeMsg)
+	}
+
+	// The required checks are already checked to exist, if specified
+	passExist := true
+	if len(q.Checks) == 0 {
+		var existMsg string
+		passExist, existMsg, err = checkChecksExist(ic, q, pr, gitCli)
+		if err != nil {
+			return false, "", err
+		}
+		if existMsg != "" {
+			messages = append(messages, existMsg)
+		}
+	}
+
+	return pass && passFreeze && passExist, strings.Join(messages, " "), nil
+}
+
+// MergeMethod returns the merge method for the PR, as the merge automation does
+func MergeMethod(ic *cicdv1.IntegrationConfig, pull *git.PullRequest) git.MergeMethod {
+	if ic.Spec.MergeConfig == nil {
+		return git.MergeMethodMerge
+	}
+	return getMergeMethod(&PullRequest{PullRequest: *pull}, ic)
+}
+
+// checkChecksExist checks if the checks of the PR's jobs (or the aggregated check) are reported, except for the
+// optional ones. At least one check should be reported, even if the PR has no jobs
+func checkChecksExist(ic *cicdv1.IntegrationConfig, q cicdv1.MergeQuery, pr *PullRequest, gitCli git.Client) (bool, string, error) {
+	ic, err := dispatcher.LoadConfigFile(ic, gitCli, pr.Head.Sha)
+	if err != nil {
+		return false, "", err
+	}
+
+	var contexts []string
+	if aggregateContext := ic.GetAggregateCommitStatusContext(); aggregateContext != "" {
+		contexts = append(contexts, aggregateContext)
+	} else {
+		for _, j := range dispatcher.FilterJobs(ic.Spec.Jobs.PreSubmit, git.EventTypePullRequest, pr.Base.Ref) {
+			contexts = append(contexts, j.Name)
+		}
+	}
+
+	var missing []string
+	for _, context := range contexts {
+		if _, exist := pr.Statuses[context]; !exist && !containsString(context, q.OptionalChecks) {
+			missing = append(missing, context)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return false, fmt.Sprintf("Checks [%s] are not reported yet.", strings.Join(missing, ",")), nil
+	}
+
+	for context := range pr.Statuses {
+		if context != blockerContext {
+			return true, "", nil
+		}
+	}
+	return false, "No checks are reported yet.", nil
+}
@@ -0,0 +1,92 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package blocker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+)
+
+func TestCheckMergeable(t *testing.T) {
+	tc := map[string]struct {
+		labels    []git.IssueLabel
+		statuses  []git.CommitStatus
+		aggregate bool
+
+		expectedMergeable bool
+		expectedReason    string
+	}{
+		"mergeable": {
+			labels:            []git.IssueLabel{{Name: "approved"}},
+			statuses:          []git.CommitStatus{{Context: "test-1", State: git.CommitStatusStateSuccess}},
+			expectedMergeable: true,
+		},
+		"notApproved": {
+			statuses:       []git.CommitStatus{{Context: "test-1", State: git.CommitStatusStateSuccess}},
+			expectedReason: "Label [approved] is required.",
+		},
+		"checkFailed": {
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			statuses:       []git.CommitStatus{{Context: "test-1", State: git.CommitStatusStateFailure}},
+			expectedReason: "Checks [test-1] are not successful.",
+		},
+		"checkNotReported": {
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			expectedReason: "Checks [test-1] are not successful.",
+		},
+		"aggregateNotReported": {
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			statuses:       []git.CommitStatus{{Context: "test-1", State: git.CommitStatusStateSuccess}},
+			aggregate:      true,
+			expectedReason: "Checks [" + cicdv1.DefaultAggregateCommitStatusContext + "] are not reported yet.",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic, cli := mergeTestConfig()
+			if c.aggregate {
+				// Required checks are not specified, so the aggregated check is required
+				ic.Spec.MergeConfig.Query.Checks = nil
+				ic.Spec.AggregateCommitStatus = &cicdv1.AggregateCommitStatus{}
+			}
+			gitCli, err := utils.GetGitCli(ic, cli)
+			require.NoError(t, err)
+			gitfake.Repos = map[string]*gitfake.Repo{
+				ic.Spec.Git.Repository: {CommitStatuses: map[string][]git.CommitStatus{"sha": c.statuses}},
+			}
+
+			pr := &git.PullRequest{
+				ID:        12,
+				Base:      git.Base{Ref: "master"},
+				Head:      git.Head{Ref: "newnew", Sha: "sha"},
+				Labels:    c.labels,
+				Mergeable: true,
+				State:     git.PullRequestStateOpen,
+			}
+			mergeable, reason, err := CheckMergeable(cli, ic, gitCli, pr)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedMergeable, mergeable)
+			require.Equal(t, c.expectedReason, reason)
+		})
+	}
+}
@@ -19,10 +19,14 @@ package blocker
 import (
 	"fmt"
 	"github.com/gorilla/mux"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -40,13 +44,14 @@ func (b *blocker) newRouter() *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/status", b.handleStatusList)
 	router.PathPrefix("/status").HandlerFunc(b.handleStatus)
+	router.HandleFunc("/mergepools/{namespace}/{name}", b.handleMergePools)
 	return router
 }
 
 func (b *blocker) handleStatusList(w http.ResponseWriter, _ *http.Request) {
 	var list []statusListEntity
 
-	for key, pool := range b.Pools {
+	for key, pool := range b.listPools() {
 		list = append(list, statusListEntity{
 			Key:               string(key),
 			PullRequestLength: len(pool.PullRequests),
@@ -66,7 +71,7 @@ type statusListEntity struct {
 func (b *blocker) handleStatus(w http.ResponseWriter, req *http.Request) {
 	key := strings.TrimPrefix(req.URL.Path, "/status/")
 
-	pool, exist := b.Pools[poolKey(key)]
+	pool, exist := b.getPool(poolKey(key))
 	if !exist {
 		_ = utils.RespondError(w, http.StatusNotFound, "there is no pr pool for "+key)
 		return
@@ -110,3 +115,70 @@ type statusEntity struct {
 	Retesting      bool  `json:"retesting"`
 	RetestingBatch []int `json:"retesting_batch"`
 }
+
+// handleMergePools lists the merge pools of an IntegrationConfig, with the conditions each PR fails and the merge order
+func (b *blocker) handleMergePools(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	icName := types.NamespacedName{Namespace: vars["namespace"], Name: vars["name"]}
+
+	var pools []cicdv1.MergePoolStatus
+	for _, pool := range b.listPools() {
+		if pool.NamespacedName != icName {
+			continue
+		}
+		// The PRs are ordered only by their IDs if the IntegrationConfig cannot be fetched
+		mergeConfig := &cicdv1.MergeConfig{}
+		if ic, err := b.getIntegrationConfig(pool); err == nil && ic.Spec.MergeConfig != nil {
+			mergeConfig = ic.Spec.MergeConfig
+		}
+		pools = append(pools, getMergePoolStatus(pool, mergeConfig))
+	}
+	if len(pools) == 0 {
+		_ = utils.RespondError(w, http.StatusNotFound, "there is no pr pool for "+icName.String())
+		return
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].Repository < pools[j].Repository
+	})
+
+	_ = utils.RespondJSON(w, pools)
+}
+
+func getMergePoolStatus(pool *PRPool, mergeConfig *cicdv1.MergeConfig) cicdv1.MergePoolStatus {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	status := cicdv1.MergePoolStatus{Repository: pool.Repository, DryRun: mergeConfig.DryRun, PullRequests: []cicdv1.MergePoolPullRequest{}}
+	if !pool.LastSyncTime.IsZero() {
+		status.LastSyncTime = &metav1.Time{Time: pool.LastSyncTime}
+	}
+	if pool.CurrentBatch != nil {
+		status.BatchJob = pool.CurrentBatch.Job.Name
+	}
+
+	// The merger merges the successful PRs, the ones with priority labels and then the older ones first
+	positions := map[int]int{}
+	for i, pr := range sortPullRequestByPriority(pool.MergePool[git.CommitStatusStateSuccess], mergeConfig.PriorityLabels) {
+		positions[pr.ID] = i + 1
+	}
+
+	for _, pr := range sortPullRequestByID(pool.PullRequests) {
+		prStatus := cicdv1.MergePoolPullRequest{
+			ID:            pr.ID,
+			Title:         pr.Title,
+			Author:        pr.Author.Name,
+			Base:          cicdv1.GitRef(pr.Base.Ref).GetBranch(),
+			Sha:           pr.Head.Sha,
+			InMergePool:   pool.MergePool.Search(pr.ID) != nil,
+			Status:        string(pr.BlockerStatus),
+			Description:   pr.BlockerDescription,
+			QueuePosition: positions[pr.ID],
+			Batched:       pool.CurrentBatch != nil && pool.CurrentBatch.Contains(pr.ID),
+		}
+		if mergeConfig.DryRun {
+			prStatus.DryRunResult = pr.DryRunResult
+		}
+		status.PullRequests = append(status.PullRequests, prStatus)
+	}
+	return status
+}
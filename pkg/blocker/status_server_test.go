@@ -20,9 +20,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/bmizerany/assert"
+	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"io/ioutil"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"net/http"
 	"net/http/httptest"
@@ -65,6 +68,47 @@ func TestBlocker_statusServer(t *testing.T) {
 
 	t.Log(string(resultBytes))
 	assert.Equal(t, 200, resp.StatusCode, "Successful request")
+
+	// TEST 3 - merge pools
+	pool := b.Pools["api.github.com/tmax-cloud/cicd-operator"]
+	pool.Repository = "tmax-cloud/cicd-operator"
+	pending := &PullRequest{
+		PullRequest:        git.PullRequest{ID: 3, Title: "pending", Base: git.Base{Ref: "refs/heads/master"}},
+		BlockerStatus:      git.CommitStatusStatePending,
+		BlockerDescription: "Merge conflicts exist.",
+	}
+	first := &PullRequest{PullRequest: git.PullRequest{ID: 5, Title: "first"}, BlockerStatus: git.CommitStatusStateSuccess, BlockerDescription: "In merge pool."}
+	second := &PullRequest{PullRequest: git.PullRequest{ID: 7, Title: "second"}, BlockerStatus: git.CommitStatusStateSuccess, BlockerDescription: "In merge pool."}
+	notCandidate := &PullRequest{PullRequest: git.PullRequest{ID: 1, Title: "not candidate"}, BlockerStatus: git.CommitStatusStatePending, BlockerDescription: "Not mergeable. Label [lgtm] is required."}
+	for _, pr := range []*PullRequest{pending, first, second, notCandidate} {
+		pool.PullRequests[pr.ID] = pr
+	}
+	pool.MergePool.Add(pending)
+	pool.MergePool.Add(first)
+	pool.MergePool.Add(second)
+	pool.CurrentBatch = &Batch{PRs: []*PullRequest{first}, Job: types.NamespacedName{Name: "batch-job", Namespace: testICNamespace}}
+
+	resp, err = http.Get(fmt.Sprintf("%s/mergepools/%s/%s", srv.URL, testICNamespace, testICName))
+	require.NoError(t, err)
+	resultBytes, _ = ioutil.ReadAll(resp.Body)
+	var pools []cicdv1.MergePoolStatus
+	require.NoError(t, json.Unmarshal(resultBytes, &pools))
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, []cicdv1.MergePoolStatus{{
+		Repository: "tmax-cloud/cicd-operator",
+		BatchJob:   "batch-job",
+		PullRequests: []cicdv1.MergePoolPullRequest{
+			{ID: 1, Title: "not candidate", Status: "pending", Description: "Not mergeable. Label [lgtm] is required."},
+			{ID: 3, Title: "pending", Base: "master", InMergePool: true, Status: "pending", Description: "Merge conflicts exist."},
+			{ID: 5, Title: "first", InMergePool: true, Status: "success", Description: "In merge pool.", QueuePosition: 1, Batched: true},
+			{ID: 7, Title: "second", InMergePool: true, Status: "success", Description: "In merge pool.", QueuePosition: 2},
+		},
+	}}, pools)
+
+	// TEST 4 - no merge pool
+	resp, err = http.Get(fmt.Sprintf("%s/mergepools/%s/no-ic", srv.URL, testICNamespace))
+	require.NoError(t, err)
+	require.Equal(t, 404, resp.StatusCode)
 }
 
 func statusServerTestConfig() client.Client {
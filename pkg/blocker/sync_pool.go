@@ -60,22 +60,27 @@ func (b *blocker) syncPRs() {
 		if ic.Spec.Git.Token == nil || ic.Spec.MergeConfig == nil {
 			continue
 		}
-		key := string(genPoolKey(&ic))
-		_, done := doneKeys[key]
-		if done {
-			continue
-		}
-		doneKeys[key] = struct{}{}
+		for _, repo := range ic.Spec.Git.GetRepositories() {
+			repoIC := ic.ForRepository(repo)
+			key := string(genPoolKey(repoIC))
+			_, done := doneKeys[key]
+			if done {
+				continue
+			}
+			doneKeys[key] = struct{}{}
 
-		b.syncOnePool(&ic)
+			b.syncOnePool(repoIC)
+		}
 	}
 
 	// Delete redundant pools (i.e., pools for deleted IntegrationConfigs)
+	b.poolsLock.Lock()
 	for key := range b.Pools {
 		if _, done := doneKeys[string(key)]; !done {
 			delete(b.Pools, key)
 		}
 	}
+	b.poolsLock.Unlock()
 
 	// Notify that a sync is done
 	if len(b.poolSynced) < cap(b.poolSynced) {
@@ -94,11 +99,13 @@ func (b *blocker) syncOnePool(ic *cicdv1.IntegrationConfig) {
 
 	// Init PRPool
 	key := genPoolKey(ic)
+	b.poolsLock.Lock()
 	if b.Pools[key] == nil {
 		b.Pools[key] = NewPRPool(ic.Namespace, ic.Name)
+		b.Pools[key].Repository = ic.Spec.Git.Repository
 	}
-
 	pool := b.Pools[key]
+	b.poolsLock.Unlock()
 
 	prs, err := gitCli.ListPullRequests(true)
 	if err != nil {
@@ -126,9 +133,10 @@ func (b *blocker) syncOnePool(ic *cicdv1.IntegrationConfig) {
 			pool.PullRequests[rawPR.ID] = pr
 		}
 		pr.PullRequest = rawPR
+		pr.mergeableFetched = false
 
 		// Check conditions (labels, author, branch, conflict)
-		isCandidate, addMsg := checkConditionsSimple(ic.Spec.MergeConfig.Query, &rawPR)
+		isCandidate, addMsg := checkConditionsSimple(ic.Spec.MergeConfig.GetQuery(rawPR.Base.Ref), &rawPR)
 
 		// If it's a re-test from merge pool (i.e., in the merge pool and is in WaitingBatchTest),
 		// set it as a candidate and keep it in the merge pool.
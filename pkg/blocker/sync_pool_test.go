@@ -18,6 +18,9 @@ package blocker
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -98,6 +101,75 @@ func TestBlocker_syncPRs(t *testing.T) {
 	assert.Equal(t, 0, len(pools), "IC length")
 }
 
+func TestBlocker_syncPRs_concurrentMergePools(t *testing.T) {
+	fakeCli, ic := syncPoolTestEnv()
+	blocker := New(fakeCli)
+	srv := httptest.NewServer(blocker.newRouter())
+	defer srv.Close()
+
+	// Pools are added and deleted while the merge pools are listed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			blocker.syncPRs()
+			blocker.poolsLock.Lock()
+			blocker.Pools = map[poolKey]*PRPool{}
+			blocker.poolsLock.Unlock()
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		resp, err := http.Get(fmt.Sprintf("%s/mergepools/%s/%s", srv.URL, ic.Namespace, ic.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	<-done
+}
+
+func TestBlocker_syncPRs_repositories(t *testing.T) {
+	fakeCli, ic := syncPoolTestEnv()
+	blocker := New(fakeCli)
+
+	otherRepo := "tmax-cloud/cicd-test-2"
+	gitfake.Repos[otherRepo] = &gitfake.Repo{
+		PullRequests: map[int]*git.PullRequest{
+			3: {ID: 3, State: "open", Base: git.Base{Ref: "master"}, Labels: []git.IssueLabel{{Name: "lgtm"}}},
+			4: {ID: 4, State: "open", Base: git.Base{Ref: "master"}},
+		},
+		CommitStatuses: map[string][]git.CommitStatus{},
+	}
+	ic.Spec.Git.Repositories = []string{otherRepo}
+	if err := fakeCli.Update(context.Background(), ic); err != nil {
+		t.Fatal(err)
+	}
+
+	blocker.syncPRs()
+	assert.Equal(t, 2, len(blocker.Pools), "Pool length")
+	assert.Equal(t, 1, len(blocker.Pools[genPoolKey(ic)].PullRequests), "PRList length")
+
+	pool := blocker.Pools[genPoolKey(ic.ForRepository(otherRepo))]
+	assert.Equal(t, otherRepo, pool.Repository)
+	assert.Equal(t, 2, len(pool.PullRequests), "PRList length")
+	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStatePending]), "Pending merge pool length")
+
+	poolIC, err := blocker.getIntegrationConfig(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, otherRepo, poolIC.Spec.Git.Repository)
+
+	// Removed repository
+	ic.Spec.Git.Repositories = nil
+	if err := fakeCli.Update(context.Background(), ic); err != nil {
+		t.Fatal(err)
+	}
+	blocker.syncPRs()
+	assert.Equal(t, 1, len(blocker.Pools), "Pool length")
+}
+
 func syncPoolTestEnv() (client.Client, *cicdv1.IntegrationConfig) {
 	if _, exist := os.LookupEnv("CI"); !exist {
 		ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -17,11 +17,15 @@
 package blocker
 
 import (
-	"context"
 	"fmt"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sort"
+	"strings"
+	"time"
 )
 
 // sync_pool.go includes methods for synchronizing PR's commit status/merge conflicts status
@@ -38,12 +42,12 @@ func (b *blocker) syncMergePoolStatus() {
 	log := b.log.WithName("status")
 	log.Info("Synchronizing merge pool status")
 
-	for _, pool := range b.Pools {
+	for _, pool := range b.listPools() {
 		log := b.log.WithName("status").WithValues("repo", pool.NamespacedName)
 
 		// Get IC
-		ic := &cicdv1.IntegrationConfig{}
-		if err := b.client.Get(context.Background(), pool.NamespacedName, ic); err != nil {
+		ic, err := b.getIntegrationConfig(pool)
+		if err != nil {
 			log.Error(err, "")
 			continue
 		}
@@ -60,6 +64,9 @@ func (b *blocker) syncMergePoolStatus() {
 		// Get PRs' status for each repository
 		b.syncOneMergePoolStatus(pool, ic, gitCli)
 
+		// Label the PRs with merge conflicts, and unlabel the resolved ones
+		b.syncConflicts(pool, ic, gitCli)
+
 		// Set PRs' commit status for each repository
 		b.reportCommitStatus(pool, ic, gitCli)
 	}
@@ -76,50 +83,119 @@ func (b *blocker) syncOneMergePoolStatus(pool *PRPool, ic *cicdv1.IntegrationCon
 
 	log := b.log.WithName("status").WithValues("repo", genPoolKey(ic))
 
-	// Loop merge pool per blocker status (pending, success)
-	for oldStatus := range pool.MergePool {
-		// For each PR
-		for prID, pr := range pool.MergePool[oldStatus] {
-			// Fetch PR's status, commit statuses
-			if err := b.reflectPRStatus(pr, gitCli); err != nil {
-				log.Error(err, "")
-				continue
-			}
-			newStatusB, removeFromMergePool, newDescription := checkConditionsFull(ic.Spec.MergeConfig.Query, pr)
+	// For each PR in the merge pool, within the budget
+	for _, entry := range selectPRsToSync(pool.MergePool, getSyncBudget(ic)) {
+		oldStatus, prID, pr := entry.status, entry.pr.ID, entry.pr
+		pr.lastStatusSyncTime = time.Now()
 
-			var newStatus git.CommitStatusState
-			if newStatusB {
-				newStatus = git.CommitStatusStateSuccess
-				newDescription = "In merge pool."
-			} else {
-				newStatus = git.CommitStatusStatePending
-			}
+		// Fetch PR's status, commit statuses
+		if err := b.reflectPRStatus(pr, gitCli); err != nil {
+			log.Error(err, "")
+			continue
+		}
+		query := ic.Spec.MergeConfig.GetQuery(pr.Base.Ref)
+		if err := b.reflectApprovers(pr, query.Approvals, gitCli); err != nil {
+			log.Error(err, "")
+			continue
+		}
+		// The PR is kept pending if the code owners cannot be checked
+		if err := b.reflectCodeOwners(pr, query.CodeOwners, gitCli); err != nil {
+			log.Error(err, "")
+		}
+		// The PR is kept pending if its dependencies cannot be checked
+		if err := b.reflectDependencies(pr, ic, gitCli); err != nil {
+			log.Error(err, "")
+		}
+		newStatusB, removeFromMergePool, newDescription := checkConditionsFull(query, pr)
 
-			// Remove from merge pool if simple test fails
-			// But, if the PR is being re-tested by merger, keep it in the merge pool
-			if removeFromMergePool && (pool.CurrentBatch == nil || !pool.CurrentBatch.Contains(prID)) {
-				delete(pool.MergePool[oldStatus], prID)
-			}
+		// Keep the PR pending while its base branch is frozen
+		if passFreeze, freezeMsg := checkFreeze(ic.Spec.MergeConfig.Freeze, pr, time.Now()); !passFreeze {
+			newStatusB = false
+			newDescription = strings.TrimSpace(newDescription + " " + freezeMsg)
+		}
 
-			// Move PR status in the pool
-			if newStatus != oldStatus {
-				delete(pool.MergePool[oldStatus], prID)
-				pool.MergePool[newStatus][prID] = pr
+		var newStatus git.CommitStatusState
+		if newStatusB {
+			newStatus = git.CommitStatusStateSuccess
+			newDescription = "In merge pool."
+			if ic.Spec.MergeConfig.DryRun {
+				newDescription = "In merge pool. (dry-run)"
 			}
+		} else {
+			newStatus = git.CommitStatusStatePending
+		}
 
-			// Update status cache
-			if newStatus != pr.BlockerStatus {
-				pr.BlockerStatus = newStatus
-				pr.blockerCacheDirty = true
-			}
-			if newDescription != pr.BlockerDescription {
-				pr.BlockerDescription = newDescription
-				pr.blockerCacheDirty = true
+		// Remove from merge pool if simple test fails
+		// But, if the PR is being re-tested by merger, keep it in the merge pool
+		if removeFromMergePool && (pool.CurrentBatch == nil || !pool.CurrentBatch.Contains(prID)) {
+			delete(pool.MergePool[oldStatus], prID)
+		}
+
+		// Move PR status in the pool
+		if newStatus != oldStatus {
+			delete(pool.MergePool[oldStatus], prID)
+			pool.MergePool[newStatus][prID] = pr
+		}
+
+		// Update status cache
+		if newStatus != pr.BlockerStatus {
+			pr.BlockerStatus = newStatus
+			pr.blockerCacheDirty = true
+		}
+		if newDescription != pr.BlockerDescription {
+			pr.BlockerDescription = newDescription
+			pr.blockerCacheDirty = true
+		}
+
+		log.Info(fmt.Sprintf("\t[#%d](%.20s) - %s/%s", pr.ID, pr.Title, pr.BlockerStatus, pr.BlockerDescription))
+	}
+	pool.LastSyncTime = time.Now()
+}
+
+// mergePoolEntry is a PR in the merge pool, with the blocker status it's stored with
+type mergePoolEntry struct {
+	status git.CommitStatusState
+	pr     *PullRequest
+}
+
+// selectPRsToSync selects the PRs in the merge pool to be synchronized, the least recently synchronized ones first
+// Every PR is selected if the budget is 0. The PRs whose heads are changed since their statuses are fetched are always
+// selected ahead of the budget, not to be merged with the statuses of the old heads
+func selectPRsToSync(mergePool MergePool, budget int) []mergePoolEntry {
+	var outdated, entries []mergePoolEntry
+	for status, prs := range mergePool {
+		for _, pr := range prs {
+			if pr.statusesOutdated() {
+				outdated = append(outdated, mergePoolEntry{status: status, pr: pr})
+			} else {
+				entries = append(entries, mergePoolEntry{status: status, pr: pr})
 			}
+		}
+	}
+	sortMergePoolEntries(outdated)
+	sortMergePoolEntries(entries)
+	if budget > 0 && len(entries) > budget {
+		entries = entries[:budget]
+	}
+	return append(outdated, entries...)
+}
 
-			log.Info(fmt.Sprintf("\t[#%d](%.20s) - %s/%s", pr.ID, pr.Title, pr.BlockerStatus, pr.BlockerDescription))
+func sortMergePoolEntries(entries []mergePoolEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		ti, tj := entries[i].pr.lastStatusSyncTime, entries[j].pr.lastStatusSyncTime
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
 		}
+		return entries[i].pr.ID < entries[j].pr.ID
+	})
+}
+
+// getSyncBudget returns the maximum number of the PRs in the merge pool synchronized per sync
+func getSyncBudget(ic *cicdv1.IntegrationConfig) int {
+	if ic.Spec.MergeConfig.Sync != nil && ic.Spec.MergeConfig.Sync.Budget > 0 {
+		return ic.Spec.MergeConfig.Sync.Budget
 	}
+	return configs.MergeSyncBudget
 }
 
 func (b *blocker) reflectPRStatus(pull *PullRequest, gitCli git.Client) error {
@@ -129,6 +205,7 @@ func (b *blocker) reflectPRStatus(pull *PullRequest, gitCli git.Client) error {
 		return err
 	}
 	pull.PullRequest = *pr
+	pull.mergeableFetched = true
 
 	// GET PR statuses
 	checksSlice, err := gitCli.ListCommitStatuses(pr.Head.Sha)
@@ -139,6 +216,54 @@ func (b *blocker) reflectPRStatus(pull *PullRequest, gitCli git.Client) error {
 	for _, c := range checksSlice {
 		pull.Statuses[c.Context] = c
 	}
+	pull.statusesSHA = pr.Head.Sha
+	return nil
+}
+
+// reflectApprovers lists the approvers of the PR counted for the approvals query
+func (b *blocker) reflectApprovers(pull *PullRequest, q *cicdv1.ApprovalsQuery, gitCli git.Client) error {
+	pull.Approvers = nil
+	if q == nil {
+		return nil
+	}
+
+	approvers, err := gitCli.ListPullRequestApprovers(pull.ID)
+	if err != nil {
+		return err
+	}
+	for _, u := range approvers {
+		// Author cannot approve its own PR
+		if u.Name == pull.Author.Name {
+			continue
+		}
+		if q.WriteAccessOnly {
+			canWrite, err := gitCli.CanUserWriteToRepo(u)
+			if err != nil {
+				return err
+			}
+			if !canWrite {
+				continue
+			}
+		}
+		pull.Approvers = append(pull.Approvers, u.Name)
+	}
+	return nil
+}
+
+// reflectCodeOwners checks the changed files of the PR not approved by their code owners yet
+func (b *blocker) reflectCodeOwners(pull *PullRequest, required bool, gitCli git.Client) error {
+	pull.MissingCodeOwners = nil
+	pull.codeOwnersChecked = false
+	if !required {
+		return nil
+	}
+
+	missing, err := codeowners.Check(gitCli, &pull.PullRequest)
+	if err != nil {
+		return err
+	}
+	pull.MissingCodeOwners = missing
+	pull.codeOwnersChecked = true
 	return nil
 }
 
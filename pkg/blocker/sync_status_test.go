@@ -17,11 +17,15 @@
 package blocker
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/bmizerany/assert"
+	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -90,6 +94,135 @@ func TestBlocker_syncMergePoolStatus(t *testing.T) {
 	assert.Equal(t, 0, len(pool.MergePool[git.CommitStatusStatePending]), "Pending length")
 	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStateSuccess]), "Success length")
 	assert.Equal(t, "In merge pool.", pool.PullRequests[25].BlockerDescription, "Blocker status description")
+
+	// Test 4 - merge freeze
+	ic.Spec.MergeConfig.Freeze = &cicdv1.MergeFreeze{Periods: []cicdv1.FreezePeriod{{Start: &metav1.Time{Time: time.Now().Add(-time.Hour)}}}}
+	require.NoError(t, fakeCli.Update(context.Background(), ic))
+	blocker.syncMergePoolStatus()
+	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStatePending]), "Pending length")
+	assert.Equal(t, 0, len(pool.MergePool[git.CommitStatusStateSuccess]), "Success length")
+	assert.Equal(t, "Merge is frozen.", pool.PullRequests[25].BlockerDescription, "Blocker status description")
+
+	// Test 5 - branch query
+	ic.Spec.MergeConfig.Freeze = nil
+	ic.Spec.MergeConfig.BranchQueries = []cicdv1.BranchMergeQuery{
+		{Branch: "release/*", Query: cicdv1.MergeQuery{Labels: []string{"release-approved"}}},
+		{Branch: "mas*", Query: cicdv1.MergeQuery{Approvals: &cicdv1.ApprovalsQuery{Count: 1}}},
+	}
+	require.NoError(t, fakeCli.Update(context.Background(), ic))
+	blocker.syncMergePoolStatus()
+	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStatePending]), "Pending length")
+	assert.Equal(t, 0, len(pool.MergePool[git.CommitStatusStateSuccess]), "Success length")
+	assert.Equal(t, "Approvals [0/1] are required.", pool.PullRequests[25].BlockerDescription, "Blocker status description")
+
+	// Test 6 - dependency
+	ic.Spec.MergeConfig.BranchQueries = nil
+	require.NoError(t, fakeCli.Update(context.Background(), ic))
+	gitfake.Repos[testRepo].PullRequests[3] = &git.PullRequest{ID: 3}
+	gitfake.Repos[testRepo].PullRequests[testPRID].Body = "Depends-On: " + testRepo + "#3"
+	blocker.syncMergePoolStatus()
+	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStatePending]), "Pending length")
+	assert.Equal(t, 0, len(pool.MergePool[git.CommitStatusStateSuccess]), "Success length")
+	assert.Equal(t, "Dependencies [tmax-cloud/cicd-test#3] are not merged yet.", pool.PullRequests[25].BlockerDescription, "Blocker status description")
+
+	gitfake.Repos[testRepo].PullRequests[3].Merged = true
+	blocker.syncMergePoolStatus()
+	assert.Equal(t, 0, len(pool.MergePool[git.CommitStatusStatePending]), "Pending length")
+	assert.Equal(t, 1, len(pool.MergePool[git.CommitStatusStateSuccess]), "Success length")
+	assert.Equal(t, "In merge pool.", pool.PullRequests[25].BlockerDescription, "Blocker status description")
+}
+
+func TestSelectPRsToSync(t *testing.T) {
+	now := time.Now()
+	mergePool := NewMergePool()
+	mergePool.Add(&PullRequest{PullRequest: git.PullRequest{ID: 3}, BlockerStatus: git.CommitStatusStatePending, lastStatusSyncTime: now})
+	mergePool.Add(&PullRequest{PullRequest: git.PullRequest{ID: 5}, BlockerStatus: git.CommitStatusStateSuccess, lastStatusSyncTime: now.Add(-time.Minute)})
+	mergePool.Add(&PullRequest{PullRequest: git.PullRequest{ID: 7}, BlockerStatus: git.CommitStatusStatePending})
+	mergePool.Add(&PullRequest{PullRequest: git.PullRequest{ID: 9}, BlockerStatus: git.CommitStatusStateSuccess})
+	// Statuses of PR 11 are fetched for its old head
+	mergePool.Add(&PullRequest{PullRequest: git.PullRequest{ID: 11, Head: git.Head{Sha: "new"}}, BlockerStatus: git.CommitStatusStateSuccess, lastStatusSyncTime: now, statusesSHA: "old"})
+
+	ids := func(entries []mergePoolEntry) []int {
+		var result []int
+		for _, e := range entries {
+			result = append(result, e.pr.ID)
+			assert.Equal(t, e.pr.BlockerStatus, e.status)
+		}
+		return result
+	}
+
+	// No budget
+	assert.Equal(t, []int{11, 7, 9, 5, 3}, ids(selectPRsToSync(mergePool, 0)))
+
+	// Least recently synced ones first, after the ones with outdated statuses
+	assert.Equal(t, []int{11, 7, 9}, ids(selectPRsToSync(mergePool, 2)))
+	assert.Equal(t, []int{11, 7, 9, 5}, ids(selectPRsToSync(mergePool, 3)))
+}
+
+func TestBlocker_reflectApprovers(t *testing.T) {
+	tc := map[string]struct {
+		query *cicdv1.ApprovalsQuery
+
+		expectedApprovers []string
+	}{
+		"noQuery": {},
+		"excludeAuthor": {
+			query:             &cicdv1.ApprovalsQuery{Count: 2},
+			expectedApprovers: []string{"reviewer", "contributor"},
+		},
+		"writeAccessOnly": {
+			query:             &cicdv1.ApprovalsQuery{Count: 2, WriteAccessOnly: true},
+			expectedApprovers: []string{"reviewer"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			fakeCli, ic := syncStatusTestEnv()
+			b := New(fakeCli)
+			gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+			repo := gitfake.Repos[testRepo]
+			repo.UserCanWrite = map[string]bool{"author": true, "reviewer": true, "contributor": false}
+			repo.Approvers = map[int][]git.User{
+				testPRID: {{ID: 1, Name: "author"}, {ID: 2, Name: "reviewer"}, {ID: 3, Name: "contributor"}},
+			}
+
+			pr := &PullRequest{PullRequest: git.PullRequest{ID: testPRID, Author: git.User{ID: 1, Name: "author"}}, Approvers: []string{"stale"}}
+			require.NoError(t, b.reflectApprovers(pr, c.query, gitCli))
+			require.Equal(t, c.expectedApprovers, pr.Approvers)
+		})
+	}
+}
+
+func TestBlocker_reflectCodeOwners(t *testing.T) {
+	fakeCli, ic := syncStatusTestEnv()
+	b := New(fakeCli)
+	gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+	repo := gitfake.Repos[testRepo]
+	repo.PullRequestDiffs = map[int]*git.Diff{testPRID: {Changes: []git.Change{{Filename: "main.go"}}}}
+	pr := &PullRequest{PullRequest: git.PullRequest{ID: testPRID, Base: git.Base{Ref: "master"}}}
+
+	// Not required
+	require.NoError(t, b.reflectCodeOwners(pr, false, gitCli))
+	require.False(t, pr.codeOwnersChecked)
+
+	// No CODEOWNERS file
+	require.Error(t, b.reflectCodeOwners(pr, true, gitCli))
+	require.False(t, pr.codeOwnersChecked)
+
+	// Not approved
+	repo.Files = map[string]string{"master:CODEOWNERS": "*.go @go-owner"}
+	require.NoError(t, b.reflectCodeOwners(pr, true, gitCli))
+	require.True(t, pr.codeOwnersChecked)
+	require.Equal(t, []codeowners.Requirement{{Owners: []string{"go-owner"}, Files: []string{"main.go"}}}, pr.MissingCodeOwners)
+
+	// Approved
+	repo.Approvers = map[int][]git.User{testPRID: {{Name: "go-owner"}}}
+	require.NoError(t, b.reflectCodeOwners(pr, true, gitCli))
+	require.True(t, pr.codeOwnersChecked)
+	require.Empty(t, pr.MissingCodeOwners)
 }
 
 func syncStatusTestEnv() (client.Client, *cicdv1.IntegrationConfig) {
@@ -17,9 +17,11 @@
 package chatops
 
 import (
+	"fmt"
 	"strings"
 
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -28,6 +30,13 @@ import (
 type chatOps struct {
 	client   client.Client
 	handlers map[string]CommandHandler
+
+	// commands are the registered command types, in the registered order
+	commands []string
+	helps    map[string][]CommandHelp
+
+	// customHandler handles the custom commands defined in the IntegrationConfigs
+	customHandler CommandHandler
 }
 
 // New is a constructor fo chatOps
@@ -35,6 +44,7 @@ func New(c client.Client) *chatOps {
 	co := &chatOps{
 		client:   c,
 		handlers: map[string]CommandHandler{},
+		helps:    map[string][]CommandHelp{},
 	}
 
 	return co
@@ -56,7 +66,13 @@ func (c *chatOps) Handle(webhook *git.Webhook, config *cicdv1.IntegrationConfig)
 	commands := ExtractCommands(issueComment.Comment.Body)
 	for _, command := range commands {
 		handler, ok := c.handlers[command.Type]
+		if !ok && c.customHandler != nil && config.Spec.Commands.Find(command.Type) != nil {
+			handler, ok = c.customHandler, true
+		}
 		if !ok {
+			if err := c.hintUnknownCommand(command, webhook, config); err != nil {
+				return err
+			}
 			continue
 		}
 		if err := handler(command, webhook, config); err != nil {
@@ -86,6 +102,107 @@ func ExtractCommands(comment string) []Command {
 	return commands
 }
 
-func (c *chatOps) RegisterCommandHandler(command string, handler CommandHandler) {
+// RegisterCommandHandler registers a handler for the command type, with the usages of the command listed by /help
+func (c *chatOps) RegisterCommandHandler(command string, handler CommandHandler, helps ...CommandHelp) {
+	if _, exist := c.handlers[command]; !exist {
+		c.commands = append(c.commands, command)
+	}
 	c.handlers[command] = handler
+	c.helps[command] = helps
+}
+
+// RegisterCustomCommandHandler registers a handler for the custom commands defined in the IntegrationConfigs. The
+// registered commands take precedence over the custom commands with the same names
+func (c *chatOps) RegisterCustomCommandHandler(handler CommandHandler) {
+	c.customHandler = handler
+}
+
+// ListCommandHelps lists the usages of the registered commands, in the registered order
+func (c *chatOps) ListCommandHelps() []CommandHelp {
+	var helps []CommandHelp
+	for _, command := range c.commands {
+		helps = append(helps, c.helps[command]...)
+	}
+	return helps
+}
+
+// hintUnknownCommand comments the similar command if the unknown command seems to be a typo of a registered one,
+// e.g., /tset for /test. Other unknown commands are ignored, as the comment may just contain a path-like line
+func (c *chatOps) hintUnknownCommand(command Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	if issueComment.Issue.PullRequest == nil || config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	similar := c.findSimilarCommand(command.Type, config)
+	if similar == "" {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, c.client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateUnknownCommandComment(command.Type, similar))
+}
+
+// findSimilarCommand finds the registered (or the IntegrationConfig's custom) command whose edit distance from the
+// command is less than maxCommandDistance. Returns an empty string if there is no such command
+func (c *chatOps) findSimilarCommand(command string, config *cicdv1.IntegrationConfig) string {
+	candidates := append([]string{}, c.commands...)
+	if c.customHandler != nil {
+		for _, custom := range config.Spec.Commands {
+			candidates = append(candidates, custom.Name)
+		}
+	}
+
+	similar := ""
+	minDistance := maxCommandDistance
+	for _, registered := range candidates {
+		// Too short to be regarded as a typo
+		if len(registered) <= maxCommandDistance {
+			continue
+		}
+		if d := editDistance(command, registered); d < minDistance {
+			similar = registered
+			minDistance = d
+		}
+	}
+	return similar
+}
+
+// maxCommandDistance is an upper bound (exclusive) of the edit distance of a typo from the command
+const maxCommandDistance = 3
+
+// editDistance calculates the Levenshtein distance between the two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func generateUnknownCommandComment(command, similar string) string {
+	return fmt.Sprintf("[CHATOPS ALERT]\n\nCommand `/%s` does not exist. Did you mean `/%s`?\n\n"+
+		"Comment `/help` to list the available commands.", command, similar)
 }
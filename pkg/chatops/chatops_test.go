@@ -0,0 +1,174 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package chatops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+)
+
+func TestChatOps_Handle(t *testing.T) {
+	tc := map[string]struct {
+		comment string
+
+		expectedCommands []string
+		expectedComment  string
+	}{
+		"registered": {
+			comment:          "/test\n/retest failed",
+			expectedCommands: []string{"test", "retest"},
+		},
+		"typo": {
+			comment:         "/tset",
+			expectedComment: generateUnknownCommandComment("tset", "test"),
+		},
+		"unknown": {
+			comment: "/root/path/to/file",
+		},
+		"custom": {
+			comment:          "/deploy staging",
+			expectedCommands: []string{"custom:deploy"},
+		},
+		"customTypo": {
+			comment:         "/deplyo staging",
+			expectedComment: generateUnknownCommandComment("deplyo", "deploy"),
+		},
+		"customOverridden": {
+			comment:          "/retest",
+			expectedCommands: []string{"retest"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {Comments: map[int][]git.IssueComment{}},
+			}
+
+			var handled []string
+			handler := func(command Command, _ *git.Webhook, _ *cicdv1.IntegrationConfig) error {
+				handled = append(handled, command.Type)
+				return nil
+			}
+			co := New(nil)
+			co.RegisterCommandHandler("test", handler)
+			co.RegisterCommandHandler("retest", handler)
+			co.RegisterCommandHandler("cc", handler)
+			co.RegisterCustomCommandHandler(func(command Command, _ *git.Webhook, _ *cicdv1.IntegrationConfig) error {
+				handled = append(handled, "custom:"+command.Type)
+				return nil
+			})
+
+			ic := buildTestConfig()
+			ic.Spec.Commands = cicdv1.ChatOpsCommands{{Name: "deploy"}, {Name: "retest"}}
+			require.NoError(t, co.Handle(buildTestWebhookComment(c.comment), ic))
+			require.Equal(t, c.expectedCommands, handled)
+
+			comments := gitfake.Repos[testRepo].Comments[testPRID]
+			if c.expectedComment == "" {
+				require.Empty(t, comments)
+			} else {
+				require.Len(t, comments, 1)
+				require.Equal(t, c.expectedComment, comments[0].Comment.Body)
+			}
+		})
+	}
+}
+
+func TestChatOps_ListCommandHelps(t *testing.T) {
+	co := New(nil)
+	co.RegisterCommandHandler("test", nil, CommandHelp{Usage: "/test"}, CommandHelp{Usage: "/test <job>"})
+	co.RegisterCommandHandler("hold", nil, CommandHelp{Usage: "/hold"})
+	co.RegisterCommandHandler("retest", nil)
+	// Re-registration keeps the order
+	co.RegisterCommandHandler("test", nil, CommandHelp{Usage: "/test [job]"})
+
+	require.Equal(t, []CommandHelp{{Usage: "/test [job]"}, {Usage: "/hold"}}, co.ListCommandHelps())
+}
+
+func TestChatOps_findSimilarCommand(t *testing.T) {
+	co := New(nil)
+	for _, command := range []string{"test", "retest", "hold", "unhold", "cc", "label", "remove-label"} {
+		co.RegisterCommandHandler(command, nil)
+	}
+
+	tc := map[string]struct {
+		command         string
+		expectedSimilar string
+	}{
+		"swapped":     {command: "tset", expectedSimilar: "test"},
+		"missing":     {command: "retst", expectedSimilar: "retest"},
+		"closest":     {command: "unhod", expectedSimilar: "unhold"},
+		"plural":      {command: "labels", expectedSimilar: "label"},
+		"tooShort":    {command: "c", expectedSimilar: ""},
+		"notSimilar":  {command: "deploy", expectedSimilar: ""},
+		"pathLike":    {command: "usr/bin/test", expectedSimilar: ""},
+		"removeLabel": {command: "remove-labl", expectedSimilar: "remove-label"},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedSimilar, co.findSimilarCommand(c.command, buildTestConfig()))
+		})
+	}
+}
+
+func buildTestConfig() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ic",
+			Namespace: "default",
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookComment(body string) *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo:      git.Repository{Name: testRepo},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				Body:      body,
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					State: git.PullRequestStateOpen,
+				},
+			},
+		},
+	}
+}
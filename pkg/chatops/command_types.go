@@ -29,3 +29,16 @@ type Command struct {
 
 // CommandHandler is a handler function type for chat ops events
 type CommandHandler func(command Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error
+
+// CommandHelp is a usage of a command, listed by the /help command
+type CommandHelp struct {
+	// Usage is a form of the command, e.g., /test <job>
+	Usage string
+	// Description describes what the command does
+	Description string
+}
+
+// HelpLister lists the usages of the registered commands
+type HelpLister interface {
+	ListCommandHelps() []CommandHelp
+}
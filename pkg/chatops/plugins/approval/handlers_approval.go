@@ -0,0 +1,212 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/events"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Command types for approval handler
+const (
+	CommandTypeApproveJob = "approve-job"
+	CommandTypeRejectJob  = "reject-job"
+)
+
+// HelpApproveJob are the usages of the /approve-job command
+var HelpApproveJob = []chatops.CommandHelp{
+	{Usage: "/approve-job <job> [reason]", Description: "Approves a job waiting for an approval, so that it runs. Only the approvers of the job listed as `git:<login>` who have write access to the repo can call this command."},
+}
+
+// HelpRejectJob are the usages of the /reject-job command
+var HelpRejectJob = []chatops.CommandHelp{
+	{Usage: "/reject-job <job> [reason]", Description: "Rejects a job waiting for an approval, so that it fails without running. Only the approvers of the job listed as `git:<login>` who have write access to the repo can call this command."},
+}
+
+var log = logf.Log.WithName("approval-plugin")
+
+// Handler is an implementation of a ChatOps Handler, deciding the Approvals of the jobs requiring approvals
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /approve-job and /reject-job comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	prID := issueComment.Issue.PullRequest.ID
+
+	// Default - malformed comment
+	if len(command.Args) == 0 || command.Args[0] == "" {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateHelpComment())
+	}
+
+	// Authorize or exit
+	ok, err := gitCli.CanUserWriteToRepo(webhook.Sender)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateUserUnauthorizedComment(webhook.Sender.Name))
+	}
+
+	decision := cicdv1.ApprovalResultApproved
+	if command.Type == CommandTypeRejectJob {
+		decision = cicdv1.ApprovalResultRejected
+	}
+	jobName := command.Args[0]
+	reason := strings.Join(command.Args[1:], " ")
+
+	approvals, err := h.listAwaitingApprovals(config, webhook.Repo.Name, prID, jobName)
+	if err != nil {
+		return err
+	}
+	if len(approvals) == 0 {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateNoApprovalComment(jobName))
+	}
+
+	// Check if the user is in the approver list, as the API server does
+	for i := range approvals {
+		if !isApprover(&approvals[i], webhook.Sender) {
+			log.Info(fmt.Sprintf("requested user (%s) is not an approver of %s", webhook.Sender.Name, approvals[i].Name))
+			_ = events.Emit(h.Client, &approvals[i], corev1.EventTypeWarning, "ApproveNotAllowed", fmt.Sprintf("User: %s", webhook.Sender.Name))
+			return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateNotApproverComment(webhook.Sender.Name, jobName))
+		}
+	}
+
+	for i := range approvals {
+		if err := h.decide(&approvals[i], decision, cicdv1.ApproverGitPrefix+webhook.Sender.Name, reason); err != nil {
+			return err
+		}
+	}
+
+	log.Info(fmt.Sprintf("%s %s job %s of %s", webhook.Sender.Name, strings.ToLower(string(decision)), jobName, issueComment.Issue.PullRequest.URL))
+	return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateDecidedComment(webhook.Sender.Name, decision, jobName))
+}
+
+// listAwaitingApprovals lists the Approvals of the job, which are not decided yet, for the pull request
+func (h *Handler) listAwaitingApprovals(config *cicdv1.IntegrationConfig, repo string, prID int, jobName string) ([]cicdv1.Approval, error) {
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := h.Client.List(context.Background(), ijList, client.InNamespace(config.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: config.Name}); err != nil {
+		return nil, err
+	}
+	jobs := map[string]struct{}{}
+	for _, ij := range ijList.Items {
+		if len(ij.Spec.Refs.Pulls) == 0 || ij.Spec.Refs.Pulls[0].ID != prID || !strings.EqualFold(ij.Spec.Refs.Repository, repo) {
+			continue
+		}
+		jobs[ij.Name] = struct{}{}
+	}
+
+	approvalList := &cicdv1.ApprovalList{}
+	if err := h.Client.List(context.Background(), approvalList, client.InNamespace(config.Namespace)); err != nil {
+		return nil, err
+	}
+	var approvals []cicdv1.Approval
+	for _, a := range approvalList.Items {
+		if _, exist := jobs[a.Spec.IntegrationJob]; !exist || a.Spec.JobName != jobName {
+			continue
+		}
+		if a.Status.Result == cicdv1.ApprovalResultApproved || a.Status.Result == cicdv1.ApprovalResultRejected {
+			continue
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
+
+// isApprover decides if the git user is one of the approvers of the Approval, i.e., the approvers named git:<login>
+// The approvers without the prefix are Kubernetes users, who are never matched with the git users. Nobody can approve
+// an Approval without any approver by commenting
+func isApprover(approval *cicdv1.Approval, user git.User) bool {
+	for _, a := range approval.Spec.Users {
+		if strings.HasPrefix(a.Name, cicdv1.ApproverGitPrefix) && strings.EqualFold(strings.TrimPrefix(a.Name, cicdv1.ApproverGitPrefix), user.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide updates the Approval's decision, as the API server does
+func (h *Handler) decide(approval *cicdv1.Approval, decision cicdv1.ApprovalResult, user, reason string) error {
+	original := approval.DeepCopy()
+
+	approval.Status.Result = decision
+	approval.Status.Reason = reason
+	approval.Status.Approver = user
+	approval.Status.DecisionTime = &metav1.Time{Time: time.Now()}
+
+	if err := h.Client.Status().Patch(context.Background(), approval, client.MergeFrom(original)); err != nil {
+		return err
+	}
+
+	_ = events.Emit(h.Client, approval, corev1.EventTypeNormal, string(decision), fmt.Sprintf("User: %s, Reason: %s", user, reason))
+	return nil
+}
+
+func generateHelpComment() string {
+	return "[APPROVAL ALERT]\n\nApproval comment is malformed\n\n" +
+		"You can approve or reject the job waiting for an approval by commenting...\n" +
+		"- `/approve-job <job name> [reason]`\n" +
+		"- `/reject-job <job name> [reason]`\n"
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[APPROVAL ALERT]\n\nUser `%s` is not allowed to approve/reject the jobs of this pull request.\n\n"+
+		"Users who meet the following conditions can approve/reject the jobs.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateNotApproverComment(user, job string) string {
+	return fmt.Sprintf("[APPROVAL ALERT]\n\nUser `%s` is not an approver of job `%s`.\n\n"+
+		"Only the git users listed in the job's `approval.approvers` as `%s<login>` can approve/reject it.\n", user, job, cicdv1.ApproverGitPrefix)
+}
+
+func generateNoApprovalComment(job string) string {
+	return fmt.Sprintf("[APPROVAL ALERT]\n\nJob `%s` is not waiting for an approval.", job)
+}
+
+func generateDecidedComment(user string, decision cicdv1.ApprovalResult, job string) string {
+	return fmt.Sprintf("[APPROVAL ALERT]\n\nUser `%s` %s job `%s`!", user, strings.ToLower(string(decision)), job)
+}
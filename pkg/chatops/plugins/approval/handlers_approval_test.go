@@ -0,0 +1,199 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testNamespace},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: testRepo, Token: &cicdv1.GitToken{Value: "dummy"}},
+		},
+	}
+
+	tc := map[string]struct {
+		command     chatops.Command
+		sender      string
+		senderEmail string
+		approvers   []cicdv1.ApprovalUser
+
+		expectedResult  cicdv1.ApprovalResult
+		expectedReason  string
+		expectedComment string
+	}{
+		"approve": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "maintainer",
+			expectedResult:  cicdv1.ApprovalResultApproved,
+			expectedComment: "[APPROVAL ALERT]\n\nUser `maintainer` approved job `deploy`!",
+		},
+		"reject": {
+			command:         chatops.Command{Type: CommandTypeRejectJob, Args: []string{"deploy", "not", "now"}},
+			sender:          "maintainer",
+			expectedResult:  cicdv1.ApprovalResultRejected,
+			expectedReason:  "not now",
+			expectedComment: "[APPROVAL ALERT]\n\nUser `maintainer` rejected job `deploy`!",
+		},
+		"unauthorized": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "guest",
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateUserUnauthorizedComment("guest"),
+		},
+		"caseInsensitiveLogin": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "Maintainer",
+			expectedResult:  cicdv1.ApprovalResultApproved,
+			expectedComment: "[APPROVAL ALERT]\n\nUser `Maintainer` approved job `deploy`!",
+		},
+		"kubernetesUserEmail": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "writer",
+			senderEmail:     "writer@tmax.co.kr",
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateNotApproverComment("writer", "deploy"),
+		},
+		"kubernetesUserName": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "developer",
+			approvers:       []cicdv1.ApprovalUser{{Name: "developer"}},
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateNotApproverComment("developer", "deploy"),
+		},
+		"noApprovers": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "maintainer",
+			approvers:       []cicdv1.ApprovalUser{},
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateNotApproverComment("maintainer", "deploy"),
+		},
+		"notApprover": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"deploy"}},
+			sender:          "developer",
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateNotApproverComment("developer", "deploy"),
+		},
+		"noApproval": {
+			command:         chatops.Command{Type: CommandTypeApproveJob, Args: []string{"test"}},
+			sender:          "maintainer",
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: "[APPROVAL ALERT]\n\nJob `test` is not waiting for an approval.",
+		},
+		"malformed": {
+			command:         chatops.Command{Type: CommandTypeApproveJob},
+			sender:          "maintainer",
+			expectedResult:  cicdv1.ApprovalResultAwaiting,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {
+					UserCanWrite: map[string]bool{"maintainer": true, "Maintainer": true, "writer": true, "developer": true, "guest": false},
+					Comments:     map[int][]git.IssueComment{},
+				},
+			}
+
+			ij := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: testNamespace, Labels: map[string]string{cicdv1.JobLabelConfig: testConfigName}},
+				Spec: cicdv1.IntegrationJobSpec{
+					Refs: cicdv1.IntegrationJobRefs{Repository: testRepo, Pulls: []cicdv1.IntegrationJobRefsPull{{ID: testPRID}}},
+				},
+			}
+			otherIJ := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-ij", Namespace: testNamespace, Labels: map[string]string{cicdv1.JobLabelConfig: testConfigName}},
+				Spec: cicdv1.IntegrationJobSpec{
+					Refs: cicdv1.IntegrationJobRefs{Repository: testRepo, Pulls: []cicdv1.IntegrationJobRefsPull{{ID: testPRID + 1}}},
+				},
+			}
+			approval := &cicdv1.Approval{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-approval", Namespace: testNamespace},
+				Spec: cicdv1.ApprovalSpec{IntegrationJob: ij.Name, JobName: "deploy", Users: []cicdv1.ApprovalUser{
+					{Name: "git:maintainer"},
+					{Name: "git:guest"},
+					{Name: "writer@tmax.co.kr", Email: "writer@tmax.co.kr"},
+				}},
+				Status: cicdv1.ApprovalStatus{Result: cicdv1.ApprovalResultAwaiting},
+			}
+			if c.approvers != nil {
+				approval.Spec.Users = c.approvers
+			}
+			otherApproval := &cicdv1.Approval{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-approval", Namespace: testNamespace},
+				Spec:       cicdv1.ApprovalSpec{IntegrationJob: otherIJ.Name, JobName: "deploy"},
+				Status:     cicdv1.ApprovalStatus{Result: cicdv1.ApprovalResultAwaiting},
+			}
+
+			handler := &Handler{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic, ij, otherIJ, approval, otherApproval).Build()}
+			wh := &git.Webhook{
+				EventType: git.EventTypeIssueComment,
+				Repo:      git.Repository{Name: testRepo},
+				Sender:    git.User{Name: c.sender, Email: c.senderEmail},
+				IssueComment: &git.IssueComment{
+					Issue: git.Issue{PullRequest: &git.PullRequest{ID: testPRID, State: git.PullRequestStateOpen}},
+				},
+			}
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			result := &cicdv1.Approval{}
+			require.NoError(t, handler.Client.Get(context.Background(), types.NamespacedName{Name: approval.Name, Namespace: testNamespace}, result))
+			require.Equal(t, c.expectedResult, result.Status.Result)
+			require.Equal(t, c.expectedReason, result.Status.Reason)
+			if c.expectedResult != cicdv1.ApprovalResultAwaiting {
+				require.Equal(t, "git:"+c.sender, result.Status.Approver)
+				require.NotNil(t, result.Status.DecisionTime)
+			}
+
+			// Approvals of the other pull requests are not decided
+			require.NoError(t, handler.Client.Get(context.Background(), types.NamespacedName{Name: otherApproval.Name, Namespace: testNamespace}, result))
+			require.Equal(t, cicdv1.ApprovalResultAwaiting, result.Status.Result)
+
+			require.Len(t, gitfake.Repos[testRepo].Comments[testPRID], 1)
+			require.Equal(t, c.expectedComment, gitfake.Repos[testRepo].Comments[testPRID][0].Comment.Body)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -35,6 +36,18 @@ const (
 	CommandTypeGitLabApprove = "ci-approve"
 )
 
+// HelpApprove are the usages of the /approve command
+var HelpApprove = []chatops.CommandHelp{
+	{Usage: "/approve", Description: "Approves the pull request. For GitHub. Only those who have write access to the repo can call this command."},
+	{Usage: "/approve cancel", Description: "Cancels an approval on the pull request."},
+}
+
+// HelpGitLabApprove are the usages of the /ci-approve command
+var HelpGitLabApprove = []chatops.CommandHelp{
+	{Usage: "/ci-approve", Description: "Approves the merge request. For GitLab. Only those who have write access to the repo can call this command."},
+	{Usage: "/ci-approve cancel", Description: "Cancels an approval on the merge request."},
+}
+
 const approvedLabel = "approved"
 
 // Handler is an implementation of both ChatOps Handler and Webhook Plugin for approve
@@ -82,7 +95,7 @@ func (h *Handler) Handle(wh *git.Webhook, ic *cicdv1.IntegrationConfig) error {
 	// For approve/cancel event
 	switch wh.IssueComment.ReviewState {
 	case git.PullRequestReviewStateApproved:
-		return h.handleApproveCommand(wh.IssueComment, gitCli)
+		return h.handleApproveCommand(wh.IssueComment, ic, gitCli)
 	case git.PullRequestReviewStateUnapproved:
 		return h.handleApproveCancelCommand(wh.IssueComment, gitCli)
 	}
@@ -123,7 +136,7 @@ func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, c
 
 	// /approve
 	if len(command.Args) == 0 {
-		return h.handleApproveCommand(issueComment, gitCli)
+		return h.handleApproveCommand(issueComment, config, gitCli)
 	}
 
 	// /approve cancel
@@ -133,7 +146,7 @@ func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, c
 
 	// /approve check
 	if len(command.Args) == 1 && command.Args[0] == "check" {
-		return h.handleApproveCheckCommand(issueComment, gitCli)
+		return h.handleApproveCheckCommand(issueComment, config, gitCli)
 	}
 
 	// Default - malformed comment
@@ -199,8 +212,20 @@ func (h *Handler) handleLabelEvent(wh *git.Webhook, ic *cicdv1.IntegrationConfig
 }
 
 // handleApproveCommand handles '/approve' command
-func (h *Handler) handleApproveCommand(issueComment *git.IssueComment, gitCli git.Client) error {
+func (h *Handler) handleApproveCommand(issueComment *git.IssueComment, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
 	log.Info(fmt.Sprintf("%s approved %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
+
+	// Check if the code owners approved it
+	if ic.Spec.MergeConfig != nil && ic.Spec.MergeConfig.GetQuery(issueComment.Issue.PullRequest.Base.Ref).CodeOwners {
+		missing, err := codeowners.Check(gitCli, issueComment.Issue.PullRequest, issueComment.Author.Name)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateCodeOwnersRequiredComment(issueComment.Author.Name, missing))
+		}
+	}
+
 	// Register approved label
 	if err := gitCli.SetLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, approvedLabel); err != nil {
 		return err
@@ -228,7 +253,7 @@ func (h *Handler) handleApproveCancelCommand(issueComment *git.IssueComment, git
 	return nil
 }
 
-func (h *Handler) handleApproveCheckCommand(issueComment *git.IssueComment, gitCli git.Client) error {
+func (h *Handler) handleApproveCheckCommand(issueComment *git.IssueComment, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
 	log.Info(fmt.Sprintf("%s check approval status on %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
 	// Check approved label
 	labels, err := gitCli.ListLabels(issueComment.Issue.PullRequest.ID)
@@ -255,15 +280,15 @@ func (h *Handler) handleApproveCheckCommand(issueComment *git.IssueComment, gitC
 
 	approvedComment := checkApproval(comments)
 	// Sync approval label with comments
-	if err = h.syncApproval(approveLabel, approvedComment, issueComment, gitCli); err != nil {
+	if err = h.syncApproval(approveLabel, approvedComment, issueComment, ic, gitCli); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (h *Handler) syncApproval(label, comment bool, issueComment *git.IssueComment, gitCli git.Client) error {
+func (h *Handler) syncApproval(label, comment bool, issueComment *git.IssueComment, ic *cicdv1.IntegrationConfig, gitCli git.Client) error {
 	if comment && !label {
-		if err := h.handleApproveCommand(issueComment, gitCli); err != nil {
+		if err := h.handleApproveCommand(issueComment, ic, gitCli); err != nil {
 			return err
 		}
 	}
@@ -327,6 +352,11 @@ func generateApprovedComment(user string) string {
 	return fmt.Sprintf("[APPROVE ALERT]\n\nUser `%s` approved this pull request!", user)
 }
 
+func generateCodeOwnersRequiredComment(user string, missing []codeowners.Requirement) string {
+	return fmt.Sprintf("[APPROVE ALERT]\n\nUser `%s` approved this pull request, but approvals from the code owners are still required.\n\n"+
+		"One of the owners should approve each group of the files.\n%s\n", user, codeowners.Describe(missing))
+}
+
 func generateApproveCanceledComment(user string) string {
 	return fmt.Sprintf("[APPROVE ALERT]\n\nUser `%s` canceled the approval.", user)
 }
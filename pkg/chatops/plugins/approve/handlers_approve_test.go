@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/codeowners"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -305,6 +306,69 @@ func TestChatOps_handleApprove(t *testing.T) {
 	}
 }
 
+func TestChatOps_handleApprove_codeOwners(t *testing.T) {
+	if _, exist := os.LookupEnv("CI"); !exist {
+		ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	}
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForApprove()
+	ic.Spec.MergeConfig = &cicdv1.MergeConfig{Query: cicdv1.MergeQuery{CodeOwners: true}}
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		codeOwners string
+		approvers  []git.User
+
+		expectedComment string
+		expectedLabeled bool
+	}{
+		"ownerApproves": {
+			codeOwners:      "*.go @" + testUser2Name,
+			expectedComment: generateApprovedComment(testUser2Name),
+			expectedLabeled: true,
+		},
+		"ownersMissing": {
+			codeOwners: "*.go @" + testUser2Name + "\ndocs/ @doc-owner @doc-owner2",
+			expectedComment: generateCodeOwnersRequiredComment(testUser2Name, []codeowners.Requirement{
+				{Owners: []string{"doc-owner", "doc-owner2"}, Files: []string{"docs/README.md"}},
+			}),
+		},
+		"ownersApprovedBefore": {
+			codeOwners:      "*.go @" + testUser2Name + "\ndocs/ @doc-owner @doc-owner2",
+			approvers:       []git.User{{Name: "doc-owner2"}},
+			expectedComment: generateApprovedComment(testUser2Name),
+			expectedLabeled: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			repo := gitfake.Repos[testRepo]
+			repo.UserCanWrite[testUser2Name] = true
+			repo.Files = map[string]string{"master:.github/CODEOWNERS": c.codeOwners}
+			repo.PullRequestDiffs = map[int]*git.Diff{testPRID: {Changes: []git.Change{{Filename: "main.go"}, {Filename: "docs/README.md"}}}}
+			repo.Approvers = map[int][]git.User{testPRID: c.approvers}
+
+			wh := buildTestWebhookCommentApprove()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(chatops.Command{Type: "approve"}, wh, ic))
+			require.Len(t, repo.Comments[testPRID], 1, "Comment length")
+			require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			if c.expectedLabeled {
+				require.Len(t, repo.PullRequests[testPRID].Labels, 1, "Label length")
+			} else {
+				require.Len(t, repo.PullRequests[testPRID].Labels, 0, "Label length")
+			}
+		})
+	}
+}
+
 func initFakeGit() {
 	gitfake.Users = map[string]*git.User{
 		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
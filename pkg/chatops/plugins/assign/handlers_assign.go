@@ -0,0 +1,145 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package assign
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Command types for assign handler
+const (
+	CommandTypeAssign   = "assign"
+	CommandTypeUnassign = "unassign"
+)
+
+// HelpAssign are the usages of the /assign command
+var HelpAssign = []chatops.CommandHelp{
+	{Usage: "/assign [@user ...]", Description: "Assigns the users (or the commenter) to the pull request. Only those who have write access to the repo can assign the other users."},
+}
+
+// HelpUnassign are the usages of the /unassign command
+var HelpUnassign = []chatops.CommandHelp{
+	{Usage: "/unassign [@user ...]", Description: "Unassigns the users (or the commenter) from the pull request. Only those who have write access to the repo can unassign the other users."},
+}
+
+var log = logf.Log.WithName("assign-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /assign and /unassign comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	users, ok := parseUsers(command.Args, webhook.Sender.Name)
+	if !ok {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, users, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// /unassign
+	if command.Type == CommandTypeUnassign {
+		log.Info(fmt.Sprintf("%s unassigned %v from %s", webhook.Sender.Name, users, issueComment.Issue.PullRequest.URL))
+		return gitCli.RemoveAssignees(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, users)
+	}
+
+	// /assign
+	log.Info(fmt.Sprintf("%s assigned %v to %s", webhook.Sender.Name, users, issueComment.Issue.PullRequest.URL))
+	return gitCli.AddAssignees(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, users)
+}
+
+// authorize decides if the sender is authorized to (un)assign the users
+// Anyone can (un)assign oneself, but only the maintainers can (un)assign the others
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, users []string, gitCli git.Client) error {
+	if len(users) == 1 && users[0] == sender.Name {
+		return nil
+	}
+
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+// parseUsers parses the users from the command arguments, i.e., '@user1 @user2'
+// The sender is returned if no user is specified
+func parseUsers(args []string, sender string) ([]string, bool) {
+	if len(args) == 0 {
+		return []string{sender}, true
+	}
+
+	var users []string
+	for _, arg := range args {
+		user := strings.TrimPrefix(arg, "@")
+		if user == "" || strings.Contains(user, "@") {
+			return nil, false
+		}
+		users = append(users, user)
+	}
+	return users, true
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[ASSIGN ALERT]\n\nUser `%s` is not allowed to assign/unassign other users to/from this pull request.\n\n"+
+		"Users who meet the following conditions can assign/unassign the other users.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateHelpComment() string {
+	return "[ASSIGN ALERT]\n\nAssign comment is malformed\n\n" +
+		"You can assign or unassign users to/from the pull request by commenting...\n" +
+		"- `/assign` or `/unassign` (yourself)\n" +
+		"- `/assign @user1 @user2` or `/unassign @user1 @user2`\n"
+}
@@ -0,0 +1,113 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cc
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Command types for cc handler
+const (
+	CommandTypeCC   = "cc"
+	CommandTypeUnCC = "uncc"
+)
+
+// HelpCC are the usages of the /cc command
+var HelpCC = []chatops.CommandHelp{
+	{Usage: "/cc @user ...", Description: "Requests reviews of the pull request to the users."},
+}
+
+// HelpUnCC are the usages of the /uncc command
+var HelpUnCC = []chatops.CommandHelp{
+	{Usage: "/uncc @user ...", Description: "Removes the review requests of the pull request from the users."},
+}
+
+var log = logf.Log.WithName("cc-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /cc and /uncc comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	users, ok := parseUsers(command.Args)
+	if !ok {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateHelpComment())
+	}
+
+	// /uncc
+	if command.Type == CommandTypeUnCC {
+		log.Info(fmt.Sprintf("%s removed review requests of %s from %v", webhook.Sender.Name, issueComment.Issue.PullRequest.URL, users))
+		return gitCli.RemoveReviewRequests(issueComment.Issue.PullRequest.ID, users)
+	}
+
+	// /cc
+	log.Info(fmt.Sprintf("%s requested reviews of %s to %v", webhook.Sender.Name, issueComment.Issue.PullRequest.URL, users))
+	return gitCli.RequestReviewers(issueComment.Issue.PullRequest.ID, users)
+}
+
+// parseUsers parses the mentioned users from the command arguments, i.e., '@user1 @user2'
+func parseUsers(args []string) ([]string, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+
+	var users []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") {
+			return nil, false
+		}
+		user := strings.TrimPrefix(arg, "@")
+		if user == "" || strings.Contains(user, "@") {
+			return nil, false
+		}
+		users = append(users, user)
+	}
+	return users, true
+}
+
+func generateHelpComment() string {
+	return "[CC ALERT]\n\nCc comment is malformed\n\n" +
+		"You can request or remove reviews of the pull request by commenting...\n" +
+		"- `/cc @user1 @user2`\n" +
+		"- `/uncc @user1 @user2`\n"
+}
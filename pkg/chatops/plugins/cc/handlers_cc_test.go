@@ -0,0 +1,179 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForCC()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		command      chatops.Command
+		preReviewers []git.User
+
+		expectedReviewers []string
+		expectedComment   string
+	}{
+		"cc": {
+			command:           chatops.Command{Type: "cc", Args: []string{"@" + testUser2Name}},
+			expectedReviewers: []string{testUser2Name},
+		},
+		"ccMultiple": {
+			command:      chatops.Command{Type: "cc", Args: []string{"@" + testUserName, "@" + testUser2Name}},
+			preReviewers: []git.User{{Name: testUser2Name}},
+
+			expectedReviewers: []string{testUser2Name, testUserName},
+		},
+		"uncc": {
+			command:      chatops.Command{Type: "uncc", Args: []string{"@" + testUserName}},
+			preReviewers: []git.User{{Name: testUserName}, {Name: testUser2Name}},
+
+			expectedReviewers: []string{testUser2Name},
+		},
+		"failNoUser": {
+			command:         chatops.Command{Type: "cc"},
+			expectedComment: generateHelpComment(),
+		},
+		"failNotMentioned": {
+			command:         chatops.Command{Type: "cc", Args: []string{testUser2Name}},
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].PullRequests[testPRID].Reviewers = c.preReviewers
+
+			wh := buildTestWebhookCommentCC()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			if c.expectedComment == "" {
+				require.Empty(t, repo.Comments[testPRID])
+			} else {
+				require.Len(t, repo.Comments[testPRID], 1)
+				require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			}
+
+			var reviewers []string
+			for _, r := range repo.PullRequests[testPRID].Reviewers {
+				reviewers = append(reviewers, r.Name)
+			}
+			require.Equal(t, c.expectedReviewers, reviewers)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {},
+			},
+			Comments: map[int][]git.IssueComment{},
+		},
+	}
+}
+
+func buildTestConfigForCC() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentCC() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
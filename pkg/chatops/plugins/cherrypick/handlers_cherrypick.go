@@ -0,0 +1,249 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cherrypick
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandTypeCherryPick is a cherry-pick command type
+const (
+	CommandTypeCherryPick = "cherry-pick"
+)
+
+// HelpCherryPick are the usages of the /cherry-pick command
+var HelpCherryPick = []chatops.CommandHelp{
+	{Usage: "/cherry-pick <branch>", Description: "Cherry-picks the pull request onto the branch once it is merged, and opens a new pull request for it. Only those who have write access to the repo can call this command."},
+}
+
+// LabelPrefix is a prefix of the labels set to the open pull requests to be cherry-picked once they are merged.
+// The label is followed by the branch name, i.e., cherry-pick/<branch>
+const LabelPrefix = "cherry-pick/"
+
+var log = logf.Log.WithName("cherry-pick-plugin")
+
+// Handler is an implementation of both ChatOps Handler and Webhook Plugin for cherry-pick
+type Handler struct {
+	Client client.Client
+}
+
+// Name returns a name of the cherry-pick plugin
+func (h *Handler) Name() string {
+	return "cherry-pick"
+}
+
+// Handle handles a raw webhook, to cherry-pick the merged pull request onto the branches requested before it's merged
+func (h *Handler) Handle(wh *git.Webhook, ic *cicdv1.IntegrationConfig) error {
+	// Skip if token is empty
+	if ic.Spec.Git.Token == nil {
+		return nil
+	}
+
+	pr := wh.PullRequest
+	if wh.EventType != git.EventTypePullRequest || pr == nil || pr.Action != git.PullRequestActionClose || !pr.Merged {
+		return nil
+	}
+
+	branches := getRequestedBranches(pr.Labels)
+	if len(branches) == 0 {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(ic, h.Client)
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range branches {
+		if err := h.cherryPick(pr, branch, gitCli); err != nil {
+			return err
+		}
+		if err := gitCli.DeleteLabel(git.IssueTypePullRequest, pr.ID, LabelPrefix+branch); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleChatOps handles /cherry-pick <branch> comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment
+	if issueComment.Issue.PullRequest == nil {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	// Malformed comment
+	if len(command.Args) != 1 {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateHelpComment())
+	}
+	branch := command.Args[0]
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// Check if the branch exists
+	if _, err := gitCli.GetBranch(branch); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateBranchNotFoundComment(branch))
+		}
+		return err
+	}
+
+	// Issue comment webhooks may not have the merged state of the pull request
+	pr, err := gitCli.GetPullRequest(issueComment.Issue.PullRequest.ID)
+	if err != nil {
+		return err
+	}
+
+	// Cherry-pick right now if it's already merged
+	if pr.Merged {
+		return h.cherryPick(pr, branch, gitCli)
+	}
+
+	// Do nothing if it's closed without being merged
+	if pr.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Cherry-pick once it's merged
+	log.Info(fmt.Sprintf("%s requested to cherry-pick %s onto %s", webhook.Sender.Name, issueComment.Issue.PullRequest.URL, branch))
+	if err := gitCli.SetLabel(git.IssueTypePullRequest, pr.ID, LabelPrefix+branch); err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateCherryPickReservedComment(branch))
+}
+
+// cherryPick creates a new branch with the commits of the pull request cherry-picked onto the branch and opens a new
+// pull request for it. The result is commented on the original pull request
+func (h *Handler) cherryPick(pr *git.PullRequest, branch string, gitCli git.Client) error {
+	log.Info(fmt.Sprintf("Cherry-picking %s onto %s", pr.URL, branch))
+
+	target, err := gitCli.GetBranch(branch)
+	if err != nil {
+		return err
+	}
+	commits, err := gitCli.ListPullRequestCommits(pr.ID)
+	if err != nil {
+		return err
+	}
+	var shas []string
+	for _, c := range commits {
+		shas = append(shas, c.SHA)
+	}
+
+	newBranch := fmt.Sprintf("cherry-pick-%d-to-%s", pr.ID, branch)
+	if err := gitCli.CreateBranch(newBranch, target.CommitID); err != nil {
+		return err
+	}
+	if err := gitCli.CherryPick(newBranch, shas); err != nil {
+		if delErr := gitCli.DeleteBranch(newBranch); delErr != nil {
+			log.Error(delErr, fmt.Sprintf("cannot delete branch %s", newBranch))
+		}
+		if conflictErr, ok := err.(*git.CherryPickConflictError); ok {
+			return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateConflictComment(branch, conflictErr.Commit))
+		}
+		return err
+	}
+
+	newPR, err := gitCli.CreatePullRequest(fmt.Sprintf("[%s] %s", branch, pr.Title), generateCherryPickPullRequestBody(pr), newBranch, branch)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateCherryPickedComment(branch, newPR))
+}
+
+// authorize decides if the sender is authorized to cherry-pick the pull request
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, gitCli git.Client) error {
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func getRequestedBranches(labels []git.IssueLabel) []string {
+	var branches []string
+	for _, l := range labels {
+		if strings.HasPrefix(l.Name, LabelPrefix) {
+			branches = append(branches, strings.TrimPrefix(l.Name, LabelPrefix))
+		}
+	}
+	return branches
+}
+
+func generateCherryPickPullRequestBody(pr *git.PullRequest) string {
+	return fmt.Sprintf("This is an automated cherry-pick of #%d\n\n%s", pr.ID, pr.Body)
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[CHERRY-PICK ALERT]\n\nUser `%s` is not allowed to cherry-pick this pull request.\n\n"+
+		"Users who meet the following conditions can cherry-pick the pull request.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateBranchNotFoundComment(branch string) string {
+	return fmt.Sprintf("[CHERRY-PICK ALERT]\n\nBranch `%s` does not exist in the repository.", branch)
+}
+
+func generateCherryPickReservedComment(branch string) string {
+	return fmt.Sprintf("[CHERRY-PICK ALERT]\n\nThis pull request will be cherry-picked onto `%s` once it's merged.", branch)
+}
+
+func generateCherryPickedComment(branch string, newPR *git.PullRequest) string {
+	return fmt.Sprintf("[CHERRY-PICK ALERT]\n\nThis pull request is cherry-picked onto `%s`: %s", branch, newPR.URL)
+}
+
+func generateConflictComment(branch, commit string) string {
+	return fmt.Sprintf("[CHERRY-PICK ALERT]\n\nThis pull request cannot be cherry-picked onto `%s`, because commit `%s` conflicts with it. "+
+		"Please cherry-pick it manually.", branch, commit)
+}
+
+func generateHelpComment() string {
+	return "[CHERRY-PICK ALERT]\n\nCherry-pick comment is malformed\n\n" +
+		"You can cherry-pick the pull request onto another branch by commenting...\n" +
+		"- `/cherry-pick <branch>`\n"
+}
@@ -0,0 +1,286 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cherrypick
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+
+	testBranch    = "release-1.0"
+	testNewBranch = "cherry-pick-11-to-release-1.0"
+	testNewPRURL  = "https://github.com/test/repo/pull/12"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForCherryPick()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		action git.PullRequestAction
+		merged bool
+		labels []git.IssueLabel
+
+		expectedCherryPicks []string
+		expectedLabels      int
+		expectedComments    []string
+	}{
+		"merged": {
+			action:              git.PullRequestActionClose,
+			merged:              true,
+			labels:              []git.IssueLabel{{Name: LabelPrefix + testBranch}, {Name: "approved"}},
+			expectedCherryPicks: []string{"sha-1", "sha-2"},
+			expectedLabels:      1,
+			expectedComments:    []string{"[CHERRY-PICK ALERT]\n\nThis pull request is cherry-picked onto `release-1.0`: " + testNewPRURL},
+		},
+		"mergedNotRequested": {
+			action:         git.PullRequestActionClose,
+			merged:         true,
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			expectedLabels: 1,
+		},
+		"closed": {
+			action:         git.PullRequestActionClose,
+			labels:         []git.IssueLabel{{Name: LabelPrefix + testBranch}},
+			expectedLabels: 1,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].PullRequests[testPRID].Labels = c.labels
+
+			wh := &git.Webhook{
+				EventType: git.EventTypePullRequest,
+				Repo:      git.Repository{Name: testRepo},
+				PullRequest: &git.PullRequest{
+					ID:     testPRID,
+					Title:  "test-pull-request",
+					Action: c.action,
+					Merged: c.merged,
+					Labels: c.labels,
+				},
+			}
+			require.NoError(t, handler.Handle(wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			require.Equal(t, c.expectedCherryPicks, repo.CherryPicks[testNewBranch])
+			require.Len(t, repo.PullRequests[testPRID].Labels, c.expectedLabels)
+			var comments []string
+			for _, comment := range repo.Comments[testPRID] {
+				comments = append(comments, comment.Comment.Body)
+			}
+			require.Equal(t, c.expectedComments, comments)
+		})
+	}
+}
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForCherryPick()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		command   chatops.Command
+		canWrite  bool
+		merged    bool
+		conflicts []string
+
+		expectedCherryPicks []string
+		expectedLabels      []git.IssueLabel
+		expectedNewPR       bool
+		expectedComment     string
+	}{
+		"reserve": {
+			command:         chatops.Command{Type: "cherry-pick", Args: []string{testBranch}},
+			canWrite:        true,
+			expectedLabels:  []git.IssueLabel{{Name: LabelPrefix + testBranch}},
+			expectedComment: generateCherryPickReservedComment(testBranch),
+		},
+		"merged": {
+			command:             chatops.Command{Type: "cherry-pick", Args: []string{testBranch}},
+			canWrite:            true,
+			merged:              true,
+			expectedCherryPicks: []string{"sha-1", "sha-2"},
+			expectedNewPR:       true,
+			expectedComment:     "[CHERRY-PICK ALERT]\n\nThis pull request is cherry-picked onto `release-1.0`: " + testNewPRURL,
+		},
+		"conflict": {
+			command:             chatops.Command{Type: "cherry-pick", Args: []string{testBranch}},
+			canWrite:            true,
+			merged:              true,
+			conflicts:           []string{"sha-2"},
+			expectedCherryPicks: []string{"sha-1"},
+			expectedComment:     generateConflictComment(testBranch, "sha-2"),
+		},
+		"failBranchNotFound": {
+			command:         chatops.Command{Type: "cherry-pick", Args: []string{"release-2.0"}},
+			canWrite:        true,
+			expectedComment: generateBranchNotFoundComment("release-2.0"),
+		},
+		"failUnauthorized": {
+			command:         chatops.Command{Type: "cherry-pick", Args: []string{testBranch}},
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "cherry-pick"},
+			canWrite:        true,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			repo := gitfake.Repos[testRepo]
+			repo.UserCanWrite[testUser2Name] = c.canWrite
+			repo.ConflictCommits = c.conflicts
+			if c.merged {
+				repo.PullRequests[testPRID].State = git.PullRequestStateClosed
+				repo.PullRequests[testPRID].Merged = true
+			}
+
+			wh := buildTestWebhookCommentCherryPick()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			require.Len(t, repo.Comments[testPRID], 1)
+			require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			require.Equal(t, c.expectedLabels, repo.PullRequests[testPRID].Labels)
+			require.Equal(t, c.expectedCherryPicks, repo.CherryPicks[testNewBranch])
+
+			newPR, exist := repo.PullRequests[testPRID+1]
+			require.Equal(t, c.expectedNewPR, exist)
+			if c.expectedNewPR {
+				require.Equal(t, "[release-1.0] test-pull-request", newPR.Title)
+				require.Equal(t, testNewBranch, newPR.Head.Ref)
+				require.Equal(t, testBranch, newPR.Base.Ref)
+			}
+
+			// The branch is deleted if the cherry-pick fails
+			_, branchExist := gitfake.Branches[testNewBranch]
+			require.Equal(t, c.expectedNewPR, branchExist)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Branches = map[string]*git.Branch{
+		testBranch: {Name: testBranch, CommitID: "release-sha"},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+				},
+			},
+			PullRequestCommits: map[int][]git.Commit{
+				testPRID: {{SHA: "sha-1"}, {SHA: "sha-2"}},
+			},
+			Comments: map[int][]git.IssueComment{},
+		},
+	}
+}
+
+func buildTestConfigForCherryPick() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentCherryPick() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
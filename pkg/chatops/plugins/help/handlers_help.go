@@ -0,0 +1,94 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package help
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CommandTypeHelp is a help command type
+const (
+	CommandTypeHelp = "help"
+)
+
+// HelpHelp are the usages of the help command
+var HelpHelp = []chatops.CommandHelp{
+	{Usage: "/help", Description: "Lists the available commands."},
+}
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+	Lister chatops.HelpLister
+}
+
+// HandleChatOps handles /help comment commands
+func (h *Handler) HandleChatOps(_ chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment
+	if issueComment.Issue.PullRequest == nil {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateHelpComment(listHelps(h.Lister, config)))
+}
+
+// listHelps lists the usages of the registered commands and the IntegrationConfig's custom commands. The custom
+// commands overridden by the registered ones are not listed
+func listHelps(lister chatops.HelpLister, config *cicdv1.IntegrationConfig) []chatops.CommandHelp {
+	helps := lister.ListCommandHelps()
+
+	registered := map[string]struct{}{}
+	for _, h := range helps {
+		if tokens := strings.Fields(h.Usage); len(tokens) > 0 {
+			registered[tokens[0]] = struct{}{}
+		}
+	}
+	for _, c := range config.Spec.Commands {
+		if _, exist := registered["/"+c.Name]; exist {
+			continue
+		}
+		helps = append(helps, chatops.CommandHelp{Usage: c.GetUsage(), Description: c.Description})
+	}
+	return helps
+}
+
+func generateHelpComment(helps []chatops.CommandHelp) string {
+	var rows []string
+	for _, h := range helps {
+		rows = append(rows, fmt.Sprintf("|`%s`|%s|", strings.ReplaceAll(h.Usage, "|", "\\|"), h.Description))
+	}
+	return "[HELP]\n\nYou can use the following commands by commenting on the pull request.\n\n" +
+		"|Command|Description|\n|---|---|\n" + strings.Join(rows, "\n") + "\n"
+}
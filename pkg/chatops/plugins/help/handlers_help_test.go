@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package help
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+)
+
+type testLister []chatops.CommandHelp
+
+func (l testLister) ListCommandHelps() []chatops.CommandHelp {
+	return l
+}
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+			Commands: cicdv1.ChatOpsCommands{
+				{Name: "deploy", Description: "Deploys the pull request.", Args: []cicdv1.ChatOpsCommandArg{{Param: "env", Required: true}}},
+				{Name: "test", Description: "Overridden by the built-in command."},
+			},
+		},
+	}
+	handler := &Handler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build(),
+		Lister: testLister{
+			{Usage: "/test", Description: "Triggers all the jobs."},
+			{Usage: "/merge [merge|squash|rebase]", Description: "Merges the pull request."},
+		},
+	}
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {Comments: map[int][]git.IssueComment{}},
+	}
+	wh := &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		IssueComment: &git.IssueComment{
+			Issue: git.Issue{PullRequest: &git.PullRequest{ID: testPRID, State: git.PullRequestStateOpen}},
+		},
+	}
+
+	require.NoError(t, handler.HandleChatOps(chatops.Command{Type: "help"}, wh, ic))
+
+	comments := gitfake.Repos[testRepo].Comments[testPRID]
+	require.Len(t, comments, 1)
+	require.Equal(t, "[HELP]\n\nYou can use the following commands by commenting on the pull request.\n\n"+
+		"|Command|Description|\n|---|---|\n"+
+		"|`/test`|Triggers all the jobs.|\n"+
+		"|`/merge [merge\\|squash\\|rebase]`|Merges the pull request.|\n"+
+		"|`/deploy <env>`|Deploys the pull request.|\n", comments[0].Comment.Body)
+}
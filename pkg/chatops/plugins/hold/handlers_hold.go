@@ -28,11 +28,23 @@ import (
 	"strings"
 )
 
-// CommandTypeHold is a hold command type
+// Command types for hold handler
 const (
-	CommandTypeHold = "hold"
+	CommandTypeHold   = "hold"
+	CommandTypeUnhold = "unhold"
 )
 
+// HelpHold are the usages of the /hold command
+var HelpHold = []chatops.CommandHelp{
+	{Usage: "/hold", Description: "Holds the pull request not to be merged automatically."},
+	{Usage: "/hold cancel", Description: "Unholds the pull request."},
+}
+
+// HelpUnhold are the usages of the /unhold command
+var HelpUnhold = []chatops.CommandHelp{
+	{Usage: "/unhold", Description: "Unholds the pull request. Same as `/hold cancel`."},
+}
+
 var log = logf.Log.WithName("hold-plugin")
 
 // Handler is an implementation of a ChatOps Handler
@@ -40,7 +52,7 @@ type Handler struct {
 	Client client.Client
 }
 
-// HandleChatOps handles /hold and /hold cancel comment commands
+// HandleChatOps handles /hold, /hold cancel and /unhold comment commands
 func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
 	issueComment := webhook.IssueComment
 	// Do nothing if it's not pull request's comment or it's closed
@@ -58,13 +70,18 @@ func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, c
 		return err
 	}
 
+	// /unhold
+	if command.Type == CommandTypeUnhold && len(command.Args) == 0 {
+		return h.handleHoldCancelCommand(issueComment, gitCli)
+	}
+
 	// /hold
-	if len(command.Args) == 0 {
+	if command.Type == CommandTypeHold && len(command.Args) == 0 {
 		return h.handleHoldCommand(issueComment, gitCli)
 	}
 
 	// /hold cancel
-	if len(command.Args) == 1 && command.Args[0] == "cancel" {
+	if command.Type == CommandTypeHold && len(command.Args) == 1 && command.Args[0] == "cancel" {
 		return h.handleHoldCancelCommand(issueComment, gitCli)
 	}
 
@@ -80,7 +97,7 @@ func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, c
 func (h *Handler) handleHoldCommand(issueComment *git.IssueComment, gitCli git.Client) error {
 	log.Info(fmt.Sprintf("%s held %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
 	// Register hold label
-	if err := gitCli.SetLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, configs.MergeBlockLabel); err != nil {
+	if err := gitCli.SetLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, configs.MergeHoldLabel); err != nil {
 		return err
 	}
 	return nil
@@ -89,9 +106,14 @@ func (h *Handler) handleHoldCommand(issueComment *git.IssueComment, gitCli git.C
 // handleHoldCancelCommand handles '/hold cancel' command
 func (h *Handler) handleHoldCancelCommand(issueComment *git.IssueComment, gitCli git.Client) error {
 	log.Info(fmt.Sprintf("%s canceled hold on %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
-	// Delete hold label
-	if err := gitCli.DeleteLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, configs.MergeBlockLabel); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
-		return err
+	// Delete hold label, and the block label set by /hold in the former versions
+	for _, label := range []string{configs.MergeHoldLabel, configs.MergeBlockLabel} {
+		if label == "" {
+			continue
+		}
+		if err := gitCli.DeleteLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, label); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
+			return err
+		}
 	}
 	return nil
 }
@@ -100,5 +122,5 @@ func generateHelpComment() string {
 	return "[HOLD ALERT]\n\nHold comment is malformed\n\n" +
 		"You can hold or cancel hold the pull request by commenting...\n" +
 		"- `/hold`\n" +
-		"- `/hold cancel`\n"
+		"- `/hold cancel` or `/unhold`\n"
 }
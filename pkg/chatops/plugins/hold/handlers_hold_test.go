@@ -66,6 +66,7 @@ func TestHandler_HandleChatOps(t *testing.T) {
 
 	// Set configs value
 	configs.MergeBlockLabel = "ci/hold"
+	configs.MergeHoldLabel = "do-not-merge/hold"
 
 	tc := map[string]chatOpsHoldTestCase{
 		"hold": {
@@ -73,10 +74,28 @@ func TestHandler_HandleChatOps(t *testing.T) {
 			preFunc: func(wh *git.Webhook) {},
 			verifyFunc: func(t *testing.T) {
 				require.Len(t, gitfake.Repos[testRepo].PullRequests[testPRID].Labels, 1)
-				require.Equal(t, configs.MergeBlockLabel, gitfake.Repos[testRepo].PullRequests[testPRID].Labels[0].Name)
+				require.Equal(t, configs.MergeHoldLabel, gitfake.Repos[testRepo].PullRequests[testPRID].Labels[0].Name)
 			},
 		},
 		"holdCancel": {
+			command: chatops.Command{Type: "hold", Args: []string{"cancel"}},
+			preFunc: func(wh *git.Webhook) {
+				gitfake.Repos[testRepo].PullRequests[testPRID].Labels = append(gitfake.Repos[testRepo].PullRequests[testPRID].Labels, git.IssueLabel{Name: configs.MergeHoldLabel})
+			},
+			verifyFunc: func(t *testing.T) {
+				require.Len(t, gitfake.Repos[testRepo].PullRequests[testPRID].Labels, 0)
+			},
+		},
+		"unhold": {
+			command: chatops.Command{Type: "unhold", Args: []string{}},
+			preFunc: func(wh *git.Webhook) {
+				gitfake.Repos[testRepo].PullRequests[testPRID].Labels = append(gitfake.Repos[testRepo].PullRequests[testPRID].Labels, git.IssueLabel{Name: configs.MergeHoldLabel})
+			},
+			verifyFunc: func(t *testing.T) {
+				require.Len(t, gitfake.Repos[testRepo].PullRequests[testPRID].Labels, 0)
+			},
+		},
+		"holdCancelLegacy": {
 			command: chatops.Command{Type: "hold", Args: []string{"cancel"}},
 			preFunc: func(wh *git.Webhook) {
 				gitfake.Repos[testRepo].PullRequests[testPRID].Labels = append(gitfake.Repos[testRepo].PullRequests[testPRID].Labels, git.IssueLabel{Name: configs.MergeBlockLabel})
@@ -85,13 +104,20 @@ func TestHandler_HandleChatOps(t *testing.T) {
 				require.Len(t, gitfake.Repos[testRepo].PullRequests[testPRID].Labels, 0)
 			},
 		},
+		"failMalformedUnhold": {
+			command: chatops.Command{Type: "unhold", Args: []string{"cancel"}},
+			preFunc: func(wh *git.Webhook) {},
+			verifyFunc: func(t *testing.T) {
+				require.Len(t, gitfake.Repos[testRepo].Comments[testPRID], 1)
+			},
+		},
 		"failMalformed": {
 			command: chatops.Command{Type: "hold", Args: []string{"cancellllll"}},
 			preFunc: func(wh *git.Webhook) {},
 			verifyFunc: func(t *testing.T) {
 				require.Len(t, gitfake.Repos[testRepo].PullRequests[testPRID].Labels, 0)
 				require.Len(t, gitfake.Repos[testRepo].Comments[testPRID], 1)
-				require.Equal(t, "[HOLD ALERT]\n\nHold comment is malformed\n\nYou can hold or cancel hold the pull request by commenting...\n- `/hold`\n- `/hold cancel`\n", gitfake.Repos[testRepo].Comments[testPRID][0].Comment.Body)
+				require.Equal(t, "[HOLD ALERT]\n\nHold comment is malformed\n\nYou can hold or cancel hold the pull request by commenting...\n- `/hold`\n- `/hold cancel` or `/unhold`\n", gitfake.Repos[testRepo].Comments[testPRID][0].Comment.Body)
 			},
 		},
 	}
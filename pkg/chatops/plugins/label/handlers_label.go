@@ -0,0 +1,200 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package label
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/cherrypick"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops/plugins/lgtm"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Command types for label handler
+const (
+	CommandTypeLabel       = "label"
+	CommandTypeRemoveLabel = "remove-label"
+)
+
+// HelpLabel are the usages of the /label command
+var HelpLabel = []chatops.CommandHelp{
+	{Usage: "/label <label> ...", Description: "Adds the labels to the pull request or the issue. Only those who have write access to the repo can call this command."},
+}
+
+// HelpRemoveLabel are the usages of the /remove-label command
+var HelpRemoveLabel = []chatops.CommandHelp{
+	{Usage: "/remove-label <label> ...", Description: "Removes the labels from the pull request or the issue. Only those who have write access to the repo can call this command."},
+}
+
+var log = logf.Log.WithName("label-plugin")
+
+const (
+	approvedLabel   = "approved"
+	sizeLabelPrefix = "size/"
+)
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /label and /remove-label comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issue := &webhook.IssueComment.Issue
+	// Do nothing if it's closed
+	if issue.GetState() != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	// Malformed comment
+	if len(command.Args) == 0 {
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// Check if the labels are allowed
+	if notAllowed := filterNotAllowedLabels(command.Args, config); len(notAllowed) > 0 {
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateLabelNotAllowedComment(notAllowed))
+	}
+
+	// /remove-label
+	if command.Type == CommandTypeRemoveLabel {
+		log.Info(fmt.Sprintf("%s removed labels %v from %s", webhook.Sender.Name, command.Args, issue.GetURL()))
+		for _, l := range command.Args {
+			if err := gitCli.DeleteLabel(issue.GetType(), issue.GetID(), l); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// /label
+	log.Info(fmt.Sprintf("%s added labels %v to %s", webhook.Sender.Name, command.Args, issue.GetURL()))
+	for _, l := range command.Args {
+		if err := gitCli.SetLabel(issue.GetType(), issue.GetID(), l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorize decides if the sender is authorized to add/remove labels
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, gitCli git.Client) error {
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+// filterNotAllowedLabels returns the labels not in the allowlist. If the allowlist is empty, every label is allowed
+// except for the ones managed by the other plugins or the blocker
+func filterNotAllowedLabels(labels []string, cfg *cicdv1.IntegrationConfig) []string {
+	allowed := map[string]struct{}{}
+	for _, l := range configs.PluginLabelAllowlist {
+		allowed[l] = struct{}{}
+	}
+
+	var notAllowed []string
+	for _, l := range labels {
+		if _, ok := allowed[l]; ok {
+			continue
+		}
+		if len(allowed) > 0 || isManagedLabel(l, cfg) {
+			notAllowed = append(notAllowed, l)
+		}
+	}
+	return notAllowed
+}
+
+// isManagedLabel decides if the label is managed by the other plugins or the blocker, e.g., approved, lgtm or the hold
+// label. They should be set by their own commands, not to bypass their rules
+func isManagedLabel(label string, cfg *cicdv1.IntegrationConfig) bool {
+	managed := []string{approvedLabel, lgtm.Label, configs.MergeBlockLabel, configs.MergeHoldLabel, configs.MergeWIPLabel, configs.MergeConflictLabel}
+	if cfg.Spec.MergeConfig != nil && cfg.Spec.MergeConfig.Freeze != nil {
+		managed = append(managed, cfg.Spec.MergeConfig.Freeze.GetOverrideLabel())
+	}
+	if cfg.Spec.Stale != nil {
+		managed = append(managed, cfg.Spec.Stale.GetLabel())
+	}
+	for _, m := range managed {
+		if m != "" && m == label {
+			return true
+		}
+	}
+	for _, prefix := range []string{cherrypick.LabelPrefix, sizeLabelPrefix} {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[LABEL ALERT]\n\nUser `%s` is not allowed to add/remove labels to/from this pull request or issue.\n\n"+
+		"Users who meet the following conditions can add/remove labels.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateLabelNotAllowedComment(labels []string) string {
+	comment := fmt.Sprintf("[LABEL ALERT]\n\nLabel `%s` cannot be added/removed by comments\n\n", strings.Join(labels, "`, `"))
+	if len(configs.PluginLabelAllowlist) == 0 {
+		return comment + "The labels managed by the other plugins (e.g., `approved`, `lgtm`) should be added/removed by their own commands.\n"
+	}
+	comment += "You can add/remove the following labels...\n"
+	for _, l := range configs.PluginLabelAllowlist {
+		comment += fmt.Sprintf("- `%s`\n", l)
+	}
+	return comment
+}
+
+func generateHelpComment() string {
+	return "[LABEL ALERT]\n\nLabel comment is malformed\n\n" +
+		"You can add or remove labels to/from the pull request or the issue by commenting...\n" +
+		"- `/label <label1> <label2>`\n" +
+		"- `/remove-label <label1> <label2>`\n"
+}
@@ -0,0 +1,241 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package label
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForLabel()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	configs.MergeHoldLabel = "do-not-merge/hold"
+	defer func() { configs.MergeHoldLabel = "" }()
+
+	tc := map[string]struct {
+		command   chatops.Command
+		canWrite  bool
+		allowlist []string
+		preLabels []git.IssueLabel
+		issue     bool
+
+		expectedLabels  []string
+		expectedComment string
+	}{
+		"label": {
+			command:        chatops.Command{Type: "label", Args: []string{"kind/bug", "area/ci"}},
+			canWrite:       true,
+			preLabels:      []git.IssueLabel{{Name: "size/S"}},
+			expectedLabels: []string{"size/S", "kind/bug", "area/ci"},
+		},
+		"removeLabel": {
+			command:        chatops.Command{Type: "remove-label", Args: []string{"kind/bug", "kind/feature"}},
+			canWrite:       true,
+			preLabels:      []git.IssueLabel{{Name: "kind/bug"}, {Name: "area/ci"}},
+			expectedLabels: []string{"area/ci"},
+		},
+		"labelIssue": {
+			command:        chatops.Command{Type: "label", Args: []string{"kind/bug"}},
+			canWrite:       true,
+			issue:          true,
+			expectedLabels: []string{"kind/bug"},
+		},
+		"removeLabelIssue": {
+			command:        chatops.Command{Type: "remove-label", Args: []string{"kind/bug"}},
+			canWrite:       true,
+			issue:          true,
+			preLabels:      []git.IssueLabel{{Name: "kind/bug"}, {Name: "area/ci"}},
+			expectedLabels: []string{"area/ci"},
+		},
+		"labelAllowed": {
+			command:        chatops.Command{Type: "label", Args: []string{"kind/bug"}},
+			canWrite:       true,
+			allowlist:      []string{"kind/bug", "kind/feature"},
+			expectedLabels: []string{"kind/bug"},
+		},
+		"failNotAllowed": {
+			command:         chatops.Command{Type: "label", Args: []string{"kind/bug", "approved"}},
+			canWrite:        true,
+			allowlist:       []string{"kind/bug", "kind/feature"},
+			expectedComment: "[LABEL ALERT]\n\nLabel `approved` cannot be added/removed by comments\n\nYou can add/remove the following labels...\n- `kind/bug`\n- `kind/feature`\n",
+		},
+		"failManaged": {
+			command:         chatops.Command{Type: "label", Args: []string{"kind/bug", "approved", "lgtm", "cherry-pick/release"}},
+			canWrite:        true,
+			expectedComment: "[LABEL ALERT]\n\nLabel `approved`, `lgtm`, `cherry-pick/release` cannot be added/removed by comments\n\nThe labels managed by the other plugins (e.g., `approved`, `lgtm`) should be added/removed by their own commands.\n",
+		},
+		"failManagedRemove": {
+			command:         chatops.Command{Type: "remove-label", Args: []string{"do-not-merge/hold"}},
+			canWrite:        true,
+			preLabels:       []git.IssueLabel{{Name: "do-not-merge/hold"}},
+			expectedLabels:  []string{"do-not-merge/hold"},
+			expectedComment: "[LABEL ALERT]\n\nLabel `do-not-merge/hold` cannot be added/removed by comments\n\nThe labels managed by the other plugins (e.g., `approved`, `lgtm`) should be added/removed by their own commands.\n",
+		},
+		"failManagedFreezeOverride": {
+			command:         chatops.Command{Type: "label", Args: []string{"ci/freeze-override"}},
+			canWrite:        true,
+			expectedComment: "[LABEL ALERT]\n\nLabel `ci/freeze-override` cannot be added/removed by comments\n\nThe labels managed by the other plugins (e.g., `approved`, `lgtm`) should be added/removed by their own commands.\n",
+		},
+		"managedAllowlisted": {
+			command:        chatops.Command{Type: "label", Args: []string{"lgtm"}},
+			canWrite:       true,
+			allowlist:      []string{"lgtm"},
+			expectedLabels: []string{"lgtm"},
+		},
+		"failUnauthorized": {
+			command:         chatops.Command{Type: "label", Args: []string{"kind/bug"}},
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "label"},
+			canWrite:        true,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].UserCanWrite[testUser2Name] = c.canWrite
+			gitfake.Repos[testRepo].PullRequests[testPRID].Labels = c.preLabels
+			configs.PluginLabelAllowlist = c.allowlist
+			defer func() { configs.PluginLabelAllowlist = nil }()
+
+			wh := buildTestWebhookCommentLabel()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+			if c.issue {
+				pr := wh.IssueComment.Issue.PullRequest
+				wh.IssueComment.Issue = git.Issue{ID: pr.ID, State: pr.State, Author: pr.Author, URL: pr.URL}
+			}
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			if c.expectedComment == "" {
+				require.Empty(t, repo.Comments[testPRID])
+			} else {
+				require.Len(t, repo.Comments[testPRID], 1)
+				require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			}
+
+			var labels []string
+			for _, l := range repo.PullRequests[testPRID].Labels {
+				labels = append(labels, l.Name)
+			}
+			require.Equal(t, c.expectedLabels, labels)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {},
+			},
+			Comments: map[int][]git.IssueComment{},
+		},
+	}
+}
+
+func buildTestConfigForLabel() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+			MergeConfig: &cicdv1.MergeConfig{
+				Freeze: &cicdv1.MergeFreeze{},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentLabel() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lgtm
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandTypeLGTM is a lgtm command type
+const (
+	CommandTypeLGTM = "lgtm"
+)
+
+// HelpLGTM are the usages of the /lgtm command
+var HelpLGTM = []chatops.CommandHelp{
+	{Usage: "/lgtm", Description: "Says the pull request looks good. Only those who have write access to the repo, except for the author, can call this command."},
+	{Usage: "/lgtm cancel", Description: "Cancels the lgtm of the pull request."},
+}
+
+// Label is a label set by the lgtm plugin. It's removed when new commits are pushed to the pull request
+const Label = "lgtm"
+
+var log = logf.Log.WithName("lgtm-plugin")
+
+// Handler is an implementation of both ChatOps Handler and Webhook Plugin for lgtm
+type Handler struct {
+	Client client.Client
+}
+
+// Name returns a name of the lgtm plugin
+func (h *Handler) Name() string {
+	return "lgtm"
+}
+
+// Handle handles a raw webhook, to remove the lgtm label when new commits are pushed to the pull request
+func (h *Handler) Handle(wh *git.Webhook, ic *cicdv1.IntegrationConfig) error {
+	// Skip if token is empty
+	if ic.Spec.Git.Token == nil {
+		return nil
+	}
+
+	pr := wh.PullRequest
+	if wh.EventType != git.EventTypePullRequest || pr == nil || pr.Action != git.PullRequestActionSynchronize {
+		return nil
+	}
+
+	// Skip if the pull request is not labeled
+	if !hasLabel(pr.Labels) {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(ic, h.Client)
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("New commits are pushed to %s. Removing lgtm label", pr.URL))
+	if err := gitCli.DeleteLabel(git.IssueTypePullRequest, pr.ID, Label); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateLGTMRemovedComment())
+}
+
+// HandleChatOps handles /lgtm and /lgtm cancel comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, issueComment.Issue.PullRequest.Author, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// /lgtm
+	if len(command.Args) == 0 {
+		return h.handleLGTMCommand(issueComment, gitCli)
+	}
+
+	// /lgtm cancel
+	if len(command.Args) == 1 && command.Args[0] == "cancel" {
+		return h.handleLGTMCancelCommand(issueComment, gitCli)
+	}
+
+	// Default - malformed comment
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateHelpComment())
+}
+
+// handleLGTMCommand handles '/lgtm' command
+func (h *Handler) handleLGTMCommand(issueComment *git.IssueComment, gitCli git.Client) error {
+	log.Info(fmt.Sprintf("%s lgtm-ed %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
+	// Register lgtm label
+	if err := gitCli.SetLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, Label); err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateLGTMComment(issueComment.Author.Name))
+}
+
+// handleLGTMCancelCommand handles '/lgtm cancel' command
+func (h *Handler) handleLGTMCancelCommand(issueComment *git.IssueComment, gitCli git.Client) error {
+	log.Info(fmt.Sprintf("%s canceled lgtm on %s", issueComment.Author.Name, issueComment.Issue.PullRequest.URL))
+	// Delete lgtm label
+	if err := gitCli.DeleteLabel(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, Label); err != nil && !strings.Contains(err.Error(), "Label does not exist") {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueComment.Issue.PullRequest.ID, generateLGTMCanceledComment(issueComment.Author.Name))
+}
+
+// authorize decides if the sender is authorized to lgtm the PR
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, author git.User, gitCli git.Client) error {
+	// Check if it's PR's author
+	if sender.ID == author.ID {
+		return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+	}
+
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func hasLabel(labels []git.IssueLabel) bool {
+	for _, l := range labels {
+		if l.Name == Label {
+			return true
+		}
+	}
+	return false
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[LGTM ALERT]\n\nUser `%s` is not allowed to lgtm/cancel lgtm this pull request.\n\n"+
+		"Users who meet the following conditions can lgtm the pull request.\n"+
+		"- Not an author of the pull request\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateLGTMComment(user string) string {
+	return fmt.Sprintf("[LGTM ALERT]\n\nUser `%s` said this pull request looks good to them!", user)
+}
+
+func generateLGTMCanceledComment(user string) string {
+	return fmt.Sprintf("[LGTM ALERT]\n\nUser `%s` canceled the lgtm.", user)
+}
+
+func generateLGTMRemovedComment() string {
+	return "[LGTM ALERT]\n\nNew commits are pushed to this pull request, so the lgtm is removed. Please review it again."
+}
+
+func generateHelpComment() string {
+	return "[LGTM ALERT]\n\nLgtm comment is malformed\n\n" +
+		"You can lgtm or cancel the lgtm of the pull request by commenting...\n" +
+		"- `/lgtm`\n" +
+		"- `/lgtm cancel`\n"
+}
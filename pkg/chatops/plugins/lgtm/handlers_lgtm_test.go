@@ -0,0 +1,243 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lgtm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_Handle(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForLGTM()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		action git.PullRequestAction
+		labels []git.IssueLabel
+
+		expectedLabels   int
+		expectedComments []string
+	}{
+		"synchronize": {
+			action:           git.PullRequestActionSynchronize,
+			labels:           []git.IssueLabel{{Name: Label}, {Name: "approved"}},
+			expectedLabels:   1,
+			expectedComments: []string{generateLGTMRemovedComment()},
+		},
+		"synchronizeNoLabel": {
+			action:         git.PullRequestActionSynchronize,
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			expectedLabels: 1,
+		},
+		"labeled": {
+			action:         git.PullRequestActionLabeled,
+			labels:         []git.IssueLabel{{Name: Label}},
+			expectedLabels: 1,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].PullRequests[testPRID].Labels = c.labels
+
+			wh := &git.Webhook{
+				EventType: git.EventTypePullRequest,
+				Repo:      git.Repository{Name: testRepo},
+				PullRequest: &git.PullRequest{
+					ID:     testPRID,
+					Action: c.action,
+					Labels: c.labels,
+				},
+			}
+			require.NoError(t, handler.Handle(wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			require.Len(t, repo.PullRequests[testPRID].Labels, c.expectedLabels)
+			var comments []string
+			for _, comment := range repo.Comments[testPRID] {
+				comments = append(comments, comment.Comment.Body)
+			}
+			require.Equal(t, c.expectedComments, comments)
+		})
+	}
+}
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForLGTM()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		command   chatops.Command
+		sender    string
+		canWrite  bool
+		preLabels []git.IssueLabel
+
+		expectedLabels  []git.IssueLabel
+		expectedComment string
+	}{
+		"failSameUser": {
+			command:         chatops.Command{Type: "lgtm"},
+			sender:          testUserName,
+			canWrite:        true,
+			expectedComment: generateUserUnauthorizedComment(testUserName),
+		},
+		"failUnauthorized": {
+			command:         chatops.Command{Type: "lgtm"},
+			sender:          testUser2Name,
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "lgtm", Args: []string{"asd"}},
+			sender:          testUser2Name,
+			canWrite:        true,
+			expectedComment: generateHelpComment(),
+		},
+		"successLGTM": {
+			command:         chatops.Command{Type: "lgtm"},
+			sender:          testUser2Name,
+			canWrite:        true,
+			expectedLabels:  []git.IssueLabel{{Name: Label}},
+			expectedComment: generateLGTMComment(testUser2Name),
+		},
+		"successLGTMCancel": {
+			command:         chatops.Command{Type: "lgtm", Args: []string{"cancel"}},
+			sender:          testUser2Name,
+			canWrite:        true,
+			preLabels:       []git.IssueLabel{{Name: Label}},
+			expectedComment: generateLGTMCanceledComment(testUser2Name),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].UserCanWrite[c.sender] = c.canWrite
+			gitfake.Repos[testRepo].PullRequests[testPRID].Labels = c.preLabels
+
+			wh := buildTestWebhookCommentLGTM()
+			wh.Sender = *gitfake.Users[c.sender]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			require.Len(t, repo.Comments[testPRID], 1)
+			require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			require.Len(t, repo.PullRequests[testPRID].Labels, len(c.expectedLabels))
+			for i, l := range c.expectedLabels {
+				require.Equal(t, l.Name, repo.PullRequests[testPRID].Labels[i].Name)
+			}
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {},
+			},
+			Comments: map[int][]git.IssueComment{},
+		},
+	}
+}
+
+func buildTestConfigForLGTM() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentLGTM() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
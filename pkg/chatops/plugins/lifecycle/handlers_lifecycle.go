@@ -0,0 +1,134 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Command types for lifecycle handler
+const (
+	CommandTypeClose  = "close"
+	CommandTypeReopen = "reopen"
+)
+
+// HelpClose are the usages of the /close command
+var HelpClose = []chatops.CommandHelp{
+	{Usage: "/close", Description: "Closes the pull request or the issue. Only the author and those who have write access to the repo can call this command."},
+}
+
+// HelpReopen are the usages of the /reopen command
+var HelpReopen = []chatops.CommandHelp{
+	{Usage: "/reopen", Description: "Reopens the closed pull request or issue. Only the author and those who have write access to the repo can call this command."},
+}
+
+var log = logf.Log.WithName("lifecycle-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /close and /reopen comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issue := &webhook.IssueComment.Issue
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	// Do nothing if it's already in the state
+	state := git.PullRequestStateClosed
+	if command.Type == CommandTypeReopen {
+		state = git.PullRequestStateOpen
+	}
+	if issue.GetState() == state {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	// Malformed comment
+	if len(command.Args) > 0 {
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, issue.GetAuthor(), gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// Merged pull request cannot be reopened
+	if state == git.PullRequestStateOpen && issue.PullRequest != nil && issue.PullRequest.Merged {
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateMergedComment())
+	}
+
+	log.Info(fmt.Sprintf("%s updated the state of %s to %s", webhook.Sender.Name, issue.GetURL(), state))
+	return gitCli.UpdateIssueState(issue.GetType(), issue.GetID(), state)
+}
+
+// authorize decides if the sender is authorized to close/reopen the pull request or the issue
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender, author git.User, gitCli git.Client) error {
+	// Check if it's the author
+	if sender.ID == author.ID {
+		return nil
+	}
+
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[LIFECYCLE ALERT]\n\nUser `%s` is not allowed to close/reopen this pull request or issue.\n\n"+
+		"Users who meet the following conditions can close/reopen the pull request or the issue.\n"+
+		"- Be author of the pull request or the issue\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateMergedComment() string {
+	return "[LIFECYCLE ALERT]\n\nThis pull request is already merged, so it cannot be reopened."
+}
+
+func generateHelpComment() string {
+	return "[LIFECYCLE ALERT]\n\nLifecycle comment is malformed\n\n" +
+		"You can close or reopen the pull request or the issue by commenting...\n" +
+		"- `/close`\n" +
+		"- `/reopen`\n"
+}
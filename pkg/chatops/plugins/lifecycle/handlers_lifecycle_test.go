@@ -0,0 +1,236 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForLifecycle()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	tc := map[string]struct {
+		command  chatops.Command
+		sender   string
+		canWrite bool
+		preState git.PullRequestState
+		merged   bool
+		issue    bool
+
+		expectedState   git.PullRequestState
+		expectedComment string
+	}{
+		"close": {
+			command:       chatops.Command{Type: "close"},
+			sender:        testUser2Name,
+			canWrite:      true,
+			preState:      git.PullRequestStateOpen,
+			expectedState: git.PullRequestStateClosed,
+		},
+		"closeByAuthor": {
+			command:       chatops.Command{Type: "close"},
+			sender:        testUserName,
+			preState:      git.PullRequestStateOpen,
+			expectedState: git.PullRequestStateClosed,
+		},
+		"reopen": {
+			command:       chatops.Command{Type: "reopen"},
+			sender:        testUser2Name,
+			canWrite:      true,
+			preState:      git.PullRequestStateClosed,
+			expectedState: git.PullRequestStateOpen,
+		},
+		"reopenOpen": {
+			command:       chatops.Command{Type: "reopen"},
+			sender:        testUser2Name,
+			canWrite:      true,
+			preState:      git.PullRequestStateOpen,
+			expectedState: git.PullRequestStateOpen,
+		},
+		"failReopenMerged": {
+			command:         chatops.Command{Type: "reopen"},
+			sender:          testUser2Name,
+			canWrite:        true,
+			preState:        git.PullRequestStateClosed,
+			merged:          true,
+			expectedState:   git.PullRequestStateClosed,
+			expectedComment: generateMergedComment(),
+		},
+		"failUnauthorized": {
+			command:         chatops.Command{Type: "close"},
+			sender:          testUser2Name,
+			preState:        git.PullRequestStateOpen,
+			expectedState:   git.PullRequestStateOpen,
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"closeIssueByAuthor": {
+			command:       chatops.Command{Type: "close"},
+			sender:        testUserName,
+			preState:      git.PullRequestStateOpen,
+			issue:         true,
+			expectedState: git.PullRequestStateClosed,
+		},
+		"reopenIssue": {
+			command:       chatops.Command{Type: "reopen"},
+			sender:        testUser2Name,
+			canWrite:      true,
+			preState:      git.PullRequestStateClosed,
+			issue:         true,
+			expectedState: git.PullRequestStateOpen,
+		},
+		"failIssueUnauthorized": {
+			command:         chatops.Command{Type: "close"},
+			sender:          testUser2Name,
+			preState:        git.PullRequestStateOpen,
+			issue:           true,
+			expectedState:   git.PullRequestStateOpen,
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "close", Args: []string{"now"}},
+			sender:          testUser2Name,
+			canWrite:        true,
+			preState:        git.PullRequestStateOpen,
+			expectedState:   git.PullRequestStateOpen,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].UserCanWrite[testUserName] = c.canWrite
+			gitfake.Repos[testRepo].UserCanWrite[testUser2Name] = c.canWrite
+			gitfake.Repos[testRepo].PullRequests[testPRID].State = c.preState
+
+			wh := buildTestWebhookCommentLifecycle()
+			wh.Sender = *gitfake.Users[c.sender]
+			wh.IssueComment.Author = wh.Sender
+			wh.IssueComment.Issue.PullRequest.State = c.preState
+			wh.IssueComment.Issue.PullRequest.Merged = c.merged
+			if c.issue {
+				pr := wh.IssueComment.Issue.PullRequest
+				wh.IssueComment.Issue = git.Issue{ID: pr.ID, State: pr.State, Author: pr.Author, URL: pr.URL}
+			}
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			if c.expectedComment == "" {
+				require.Empty(t, repo.Comments[testPRID])
+			} else {
+				require.Len(t, repo.Comments[testPRID], 1)
+				require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			}
+			require.Equal(t, c.expectedState, repo.PullRequests[testPRID].State)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {},
+			},
+			Comments: map[int][]git.IssueComment{},
+		},
+	}
+}
+
+func buildTestConfigForLifecycle() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentLifecycle() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package merge
+
+import (
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/blocker"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandTypeMerge is a merge command type
+const (
+	CommandTypeMerge = "merge"
+)
+
+// HelpMerge are the usages of the /merge command
+var HelpMerge = []chatops.CommandHelp{
+	{Usage: "/merge [merge|squash|rebase]", Description: "Merges the pull request immediately, if it meets all the conditions of the merge automation and its required checks are successful. Only those who have write access to the repo can call this command."},
+}
+
+var log = logf.Log.WithName("merge-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /merge and /merge <method> comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	prID := issueComment.Issue.PullRequest.ID
+
+	// Malformed comment
+	if len(command.Args) > 1 || (len(command.Args) == 1 && !isValidMethod(git.MergeMethod(command.Args[0]))) {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// Get the latest state of the pull request
+	pr, err := gitCli.GetPullRequest(prID)
+	if err != nil {
+		return err
+	}
+	if pr.State != git.PullRequestStateOpen || !pr.Mergeable {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateNotMergeableComment())
+	}
+
+	// Check the conditions of the merge automation, e.g., labels, approvals and the required checks
+	mergeable, reason, err := blocker.CheckMergeable(h.Client, config, gitCli, pr)
+	if err != nil {
+		return err
+	}
+	if !mergeable {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateConditionsNotMetComment(reason))
+	}
+
+	method := blocker.MergeMethod(config, pr)
+	if len(command.Args) == 1 {
+		method = git.MergeMethod(command.Args[0])
+	}
+
+	log.Info(fmt.Sprintf("%s merged %s (method: %s)", webhook.Sender.Name, pr.URL, method))
+	return gitCli.MergePullRequest(pr.ID, pr.Head.Sha, method, "")
+}
+
+// authorize decides if the sender is authorized to merge the pull request
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, gitCli git.Client) error {
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func isValidMethod(method git.MergeMethod) bool {
+	return method == git.MergeMethodMerge || method == git.MergeMethodSquash || method == git.MergeMethodRebase
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[MERGE ALERT]\n\nUser `%s` is not allowed to merge this pull request.\n\n"+
+		"Users who meet the following conditions can merge the pull request.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateNotMergeableComment() string {
+	return "[MERGE ALERT]\n\nThis pull request is not mergeable. Please resolve the conflicts first."
+}
+
+func generateConditionsNotMetComment(reason string) string {
+	return fmt.Sprintf("[MERGE ALERT]\n\nThis pull request cannot be merged, as it does not meet the merge conditions.\n\n%s", reason)
+}
+
+func generateHelpComment() string {
+	return "[MERGE ALERT]\n\nMerge comment is malformed\n\n" +
+		"You can merge the pull request by commenting...\n" +
+		"- `/merge`\n" +
+		"- `/merge <merge|squash|rebase>`\n"
+}
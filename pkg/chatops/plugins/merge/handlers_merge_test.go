@@ -0,0 +1,268 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package merge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+	testSHA  = "sha"
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForMerge()
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	configs.MergeHoldLabel = "do-not-merge/hold"
+	defer func() { configs.MergeHoldLabel = "" }()
+
+	tc := map[string]struct {
+		command      chatops.Command
+		canWrite     bool
+		notMergeable bool
+		statuses     []git.CommitStatus
+		labels       []git.IssueLabel
+		jobs         []cicdv1.Job
+		mergeConfig  *cicdv1.MergeConfig
+
+		expectedMerged  bool
+		expectedComment string
+	}{
+		"merge": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "blocker", State: git.CommitStatusStatePending},
+			},
+			expectedMerged: true,
+		},
+		"mergeWithMethod": {
+			command:  chatops.Command{Type: "merge", Args: []string{"squash"}},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			expectedMerged: true,
+		},
+		"mergeOptionalCheckFailed": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "test-2", State: git.CommitStatusStateFailure},
+			},
+			mergeConfig:    &cicdv1.MergeConfig{Query: cicdv1.MergeQuery{OptionalChecks: []string{"test-2"}}},
+			expectedMerged: true,
+		},
+		"failChecks": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "test-2", State: git.CommitStatusStateFailure},
+			},
+			expectedComment: generateConditionsNotMetComment("Checks [test-2] are not successful."),
+		},
+		"failRequiredChecks": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "test-2", State: git.CommitStatusStateFailure},
+			},
+			mergeConfig:     &cicdv1.MergeConfig{Query: cicdv1.MergeQuery{Checks: []string{"test-1", "test-3"}}},
+			expectedComment: generateConditionsNotMetComment("Checks [test-3] are not successful."),
+		},
+		"failNoChecks": {
+			command:         chatops.Command{Type: "merge"},
+			canWrite:        true,
+			statuses:        []git.CommitStatus{{Context: "blocker", State: git.CommitStatusStatePending}},
+			expectedComment: generateConditionsNotMetComment("No checks are reported yet."),
+		},
+		"failJobChecksNotReported": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			jobs:            []cicdv1.Job{{Container: corev1.Container{Name: "test-1"}}, {Container: corev1.Container{Name: "test-3"}}},
+			expectedComment: generateConditionsNotMetComment("Checks [test-3] are not reported yet."),
+		},
+		"failHold": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			labels:          []git.IssueLabel{{Name: "do-not-merge/hold"}},
+			expectedComment: generateConditionsNotMetComment("Label [do-not-merge/hold] is blocking the merge."),
+		},
+		"failApproveRequired": {
+			command:  chatops.Command{Type: "merge"},
+			canWrite: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			mergeConfig:     &cicdv1.MergeConfig{Query: cicdv1.MergeQuery{ApproveRequired: true}},
+			expectedComment: generateConditionsNotMetComment("Label [approved] is required."),
+		},
+		"failNotMergeable": {
+			command:         chatops.Command{Type: "merge"},
+			canWrite:        true,
+			notMergeable:    true,
+			expectedComment: generateNotMergeableComment(),
+		},
+		"failUnauthorized": {
+			command:         chatops.Command{Type: "merge"},
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "merge", Args: []string{"fast-forward"}},
+			canWrite:        true,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			initFakeGit()
+			gitfake.Repos[testRepo].UserCanWrite[testUser2Name] = c.canWrite
+			gitfake.Repos[testRepo].PullRequests[testPRID].Mergeable = !c.notMergeable
+			gitfake.Repos[testRepo].PullRequests[testPRID].Labels = c.labels
+			gitfake.Repos[testRepo].CommitStatuses[testSHA] = c.statuses
+			ic.Spec.MergeConfig = c.mergeConfig
+			ic.Spec.Jobs.PreSubmit = c.jobs
+
+			wh := buildTestWebhookCommentMerge()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			if c.expectedComment == "" {
+				require.Empty(t, repo.Comments[testPRID])
+			} else {
+				require.Len(t, repo.Comments[testPRID], 1)
+				require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			}
+			require.Equal(t, c.expectedMerged, repo.PullRequests[testPRID].Merged)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserCanWrite: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Head:  git.Head{Sha: testSHA},
+					Base:  git.Base{Ref: "master"},
+				},
+			},
+			Comments:       map[int][]git.IssueComment{},
+			CommitStatuses: map[string][]git.CommitStatus{},
+			Commits:        map[string][]git.Commit{},
+		},
+	}
+}
+
+func buildTestConfigForMerge() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentMerge() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package milestone
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandTypeMilestone is a milestone command type
+const (
+	CommandTypeMilestone = "milestone"
+)
+
+// HelpMilestone are the usages of the /milestone command
+var HelpMilestone = []chatops.CommandHelp{
+	{Usage: "/milestone <milestone>", Description: "Sets the milestone of the pull request or the issue. Only those who have write access to the repo can call this command."},
+	{Usage: "/milestone clear", Description: "Clears the milestone of the pull request or the issue. Only those who have write access to the repo can call this command."},
+}
+
+var log = logf.Log.WithName("milestone-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// HandleChatOps handles /milestone <milestone> and /milestone clear comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issue := &webhook.IssueComment.Issue
+	// Do nothing if it's closed
+	if issue.GetState() != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	// Malformed comment
+	if len(command.Args) == 0 {
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// /milestone clear
+	if len(command.Args) == 1 && command.Args[0] == "clear" {
+		log.Info(fmt.Sprintf("%s cleared the milestone of %s", webhook.Sender.Name, issue.GetURL()))
+		return gitCli.SetMilestone(issue.GetType(), issue.GetID(), "")
+	}
+
+	// /milestone <milestone>
+	milestone := strings.Join(command.Args, " ")
+	log.Info(fmt.Sprintf("%s set the milestone of %s to %s", webhook.Sender.Name, issue.GetURL(), milestone))
+	if err := gitCli.SetMilestone(issue.GetType(), issue.GetID(), milestone); err != nil {
+		if _, ok := err.(*git.MilestoneNotFoundError); ok {
+			return gitCli.RegisterComment(issue.GetType(), issue.GetID(), generateMilestoneNotFoundComment(milestone))
+		}
+		return err
+	}
+	return nil
+}
+
+// authorize decides if the sender is authorized to set the milestone
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, gitCli git.Client) error {
+	// Check if it's repo's maintainer
+	ok, err := gitCli.CanUserWriteToRepo(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[MILESTONE ALERT]\n\nUser `%s` is not allowed to set/clear the milestone of this pull request or issue.\n\n"+
+		"Users who meet the following conditions can set/clear the milestone.\n"+
+		"- (For GitHub) Have write permission on the repository\n"+
+		"- (For GitLab) Be Developer, Maintainer, or Owner\n", user)
+}
+
+func generateMilestoneNotFoundComment(milestone string) string {
+	return fmt.Sprintf("[MILESTONE ALERT]\n\nMilestone `%s` does not exist in the repository.", milestone)
+}
+
+func generateHelpComment() string {
+	return "[MILESTONE ALERT]\n\nMilestone comment is malformed\n\n" +
+		"You can set or clear the milestone of the pull request or the issue by commenting...\n" +
+		"- `/milestone <milestone>`\n" +
+		"- `/milestone clear`\n"
+}
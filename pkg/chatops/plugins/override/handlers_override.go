@@ -0,0 +1,192 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package override
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandTypeOverride is an override command type
+const (
+	CommandTypeOverride = "override"
+)
+
+// HelpOverride are the usages of the /override command
+var HelpOverride = []chatops.CommandHelp{
+	{Usage: "/override <context> ...", Description: "Overrides the failed commit statuses of the pull request as successful. Only the admins of the repo can call this command."},
+}
+
+var log = logf.Log.WithName("override-plugin")
+
+// Handler is an implementation of a ChatOps Handler
+type Handler struct {
+	Client client.Client
+}
+
+// +kubebuilder:rbac:groups=cicd.tmax.io,resources=statusoverrides,verbs=get;list;watch;create
+
+// HandleChatOps handles /override <context> comment commands
+func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	prID := issueComment.Issue.PullRequest.ID
+
+	// Malformed comment
+	if len(command.Args) == 0 {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateHelpComment())
+	}
+
+	// Authorize or exit
+	if err := h.authorize(config, webhook.Sender, gitCli); err != nil {
+		unAuthErr, ok := err.(*git.UnauthorizedError)
+		if !ok {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateUserUnauthorizedComment(unAuthErr.User))
+	}
+
+	// Get the head commit of the pull request, as the issue comment webhook may not contain it
+	pr, err := gitCli.GetPullRequest(prID)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := gitCli.ListCommitStatuses(pr.Head.Sha)
+	if err != nil {
+		return err
+	}
+	statusMap := map[string]git.CommitStatus{}
+	for _, s := range statuses {
+		statusMap[s.Context] = s
+	}
+
+	var unknownContexts []string
+	for _, c := range command.Args {
+		status, exist := statusMap[c]
+		if !exist {
+			unknownContexts = append(unknownContexts, c)
+			continue
+		}
+		// Nothing to override
+		if status.State == git.CommitStatusStateSuccess {
+			continue
+		}
+		if err := h.override(gitCli, config, pr, status, webhook.Sender); err != nil {
+			return err
+		}
+	}
+
+	if len(unknownContexts) > 0 {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, prID, generateUnknownContextComment(unknownContexts, statusMap))
+	}
+	return nil
+}
+
+// override sets the commit status as success and records it as a StatusOverride
+func (h *Handler) override(gitCli git.Client, cfg *cicdv1.IntegrationConfig, pr *git.PullRequest, status git.CommitStatus, sender git.User) error {
+	log.Info(fmt.Sprintf("%s overrode the status %s of %s", sender.Name, status.Context, pr.URL))
+	if err := gitCli.SetCommitStatus(pr.Head.Sha, git.CommitStatus{
+		Context:     status.Context,
+		State:       git.CommitStatusStateSuccess,
+		Description: fmt.Sprintf("Overridden by %s", sender.Name),
+		TargetURL:   status.TargetURL,
+	}); err != nil {
+		return err
+	}
+
+	return h.Client.Create(context.Background(), &cicdv1.StatusOverride{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d-override-%s", cfg.Name, pr.ID, utils.RandomString(5)),
+			Namespace: cfg.Namespace,
+			Labels: map[string]string{
+				cicdv1.JobLabelConfig:      cfg.Name,
+				cicdv1.JobLabelPullRequest: strconv.Itoa(pr.ID),
+			},
+		},
+		Spec: cicdv1.StatusOverrideSpec{
+			IntegrationConfig:   cfg.Name,
+			PullRequest:         pr.ID,
+			SHA:                 pr.Head.Sha,
+			Context:             status.Context,
+			PreviousState:       status.State,
+			PreviousDescription: status.Description,
+			User:                sender.Name,
+		},
+	})
+}
+
+// authorize decides if the sender is authorized to override the commit statuses
+func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender git.User, gitCli git.Client) error {
+	// Check if it's repo's admin
+	ok, err := gitCli.IsUserRepoAdmin(sender)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+func generateUserUnauthorizedComment(user string) string {
+	return fmt.Sprintf("[OVERRIDE ALERT]\n\nUser `%s` is not allowed to override the commit statuses of this pull request.\n\n"+
+		"Users who meet the following conditions can override the commit statuses.\n"+
+		"- (For GitHub) Have admin permission on the repository\n"+
+		"- (For GitLab) Be Maintainer or Owner\n", user)
+}
+
+func generateUnknownContextComment(contexts []string, statuses map[string]git.CommitStatus) string {
+	var available []string
+	for c := range statuses {
+		available = append(available, fmt.Sprintf("- `%s`", c))
+	}
+	sort.Strings(available)
+	return fmt.Sprintf("[OVERRIDE ALERT]\n\nCommit statuses [%s] do not exist.\n\nAvailable commit statuses are...\n%s\n",
+		strings.Join(contexts, ","), strings.Join(available, "\n"))
+}
+
+func generateHelpComment() string {
+	return "[OVERRIDE ALERT]\n\nOverride comment is malformed\n\n" +
+		"You can override the commit statuses of the pull request by commenting...\n" +
+		"- `/override <context> [<context> ...]`\n"
+}
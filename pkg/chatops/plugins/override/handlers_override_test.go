@@ -0,0 +1,232 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package override
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "test/repo"
+	testPRID = 11
+	testSHA  = "sha"
+
+	testNamespace  = "default"
+	testConfigName = "test-ic"
+
+	testUserID    = 32
+	testUserName  = "test-user"
+	testUserEmail = "test@test.com"
+
+	testUser2ID    = 111
+	testUser2Name  = "new-user"
+	testUser2Email = "new@test.com"
+)
+
+func TestHandler_HandleChatOps(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestConfigForOverride()
+
+	tc := map[string]struct {
+		command  chatops.Command
+		isAdmin  bool
+		statuses []git.CommitStatus
+
+		expectedStatuses  []git.CommitStatus
+		expectedOverrides []cicdv1.StatusOverrideSpec
+		expectedComment   string
+	}{
+		"override": {
+			command: chatops.Command{Type: "override", Args: []string{"test-2", "test-3"}},
+			isAdmin: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "test-2", State: git.CommitStatusStateFailure, Description: "Job failed", TargetURL: "https://test.com/test-2"},
+				{Context: "test-3", State: git.CommitStatusStatePending, Description: "Job is running"},
+			},
+			expectedStatuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+				{Context: "test-2", State: git.CommitStatusStateFailure, Description: "Job failed", TargetURL: "https://test.com/test-2"},
+				{Context: "test-3", State: git.CommitStatusStatePending, Description: "Job is running"},
+				{Context: "test-2", State: git.CommitStatusStateSuccess, Description: "Overridden by new-user", TargetURL: "https://test.com/test-2"},
+				{Context: "test-3", State: git.CommitStatusStateSuccess, Description: "Overridden by new-user"},
+			},
+			expectedOverrides: []cicdv1.StatusOverrideSpec{
+				{IntegrationConfig: testConfigName, PullRequest: testPRID, SHA: testSHA, Context: "test-2", PreviousState: git.CommitStatusStateFailure, PreviousDescription: "Job failed", User: testUser2Name},
+				{IntegrationConfig: testConfigName, PullRequest: testPRID, SHA: testSHA, Context: "test-3", PreviousState: git.CommitStatusStatePending, PreviousDescription: "Job is running", User: testUser2Name},
+			},
+		},
+		"skipSuccess": {
+			command: chatops.Command{Type: "override", Args: []string{"test-1"}},
+			isAdmin: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			expectedStatuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+		},
+		"unknownContext": {
+			command: chatops.Command{Type: "override", Args: []string{"test-3"}},
+			isAdmin: true,
+			statuses: []git.CommitStatus{
+				{Context: "test-2", State: git.CommitStatusStateFailure},
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			expectedStatuses: []git.CommitStatus{
+				{Context: "test-2", State: git.CommitStatusStateFailure},
+				{Context: "test-1", State: git.CommitStatusStateSuccess},
+			},
+			expectedComment: "[OVERRIDE ALERT]\n\nCommit statuses [test-3] do not exist.\n\nAvailable commit statuses are...\n- `test-1`\n- `test-2`\n",
+		},
+		"failUnauthorized": {
+			command: chatops.Command{Type: "override", Args: []string{"test-1"}},
+			statuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateFailure},
+			},
+			expectedStatuses: []git.CommitStatus{
+				{Context: "test-1", State: git.CommitStatusStateFailure},
+			},
+			expectedComment: generateUserUnauthorizedComment(testUser2Name),
+		},
+		"failMalformedCommand": {
+			command:         chatops.Command{Type: "override"},
+			isAdmin:         true,
+			expectedComment: generateHelpComment(),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+			handler := &Handler{Client: fakeCli}
+
+			initFakeGit()
+			gitfake.Repos[testRepo].UserIsAdmin[testUser2Name] = c.isAdmin
+			gitfake.Repos[testRepo].CommitStatuses[testSHA] = c.statuses
+
+			wh := buildTestWebhookCommentOverride()
+			wh.Sender = *gitfake.Users[testUser2Name]
+			wh.IssueComment.Author = wh.Sender
+
+			require.NoError(t, handler.HandleChatOps(c.command, wh, ic))
+
+			repo := gitfake.Repos[testRepo]
+			if c.expectedComment == "" {
+				require.Empty(t, repo.Comments[testPRID])
+			} else {
+				require.Len(t, repo.Comments[testPRID], 1)
+				require.Equal(t, c.expectedComment, repo.Comments[testPRID][0].Comment.Body)
+			}
+			require.Equal(t, c.expectedStatuses, repo.CommitStatuses[testSHA])
+
+			overrides := &cicdv1.StatusOverrideList{}
+			require.NoError(t, fakeCli.List(context.Background(), overrides))
+			var specs []cicdv1.StatusOverrideSpec
+			for _, o := range overrides.Items {
+				require.Equal(t, testNamespace, o.Namespace)
+				require.Equal(t, testConfigName, o.Labels[cicdv1.JobLabelConfig])
+				specs = append(specs, o.Spec)
+			}
+			require.ElementsMatch(t, c.expectedOverrides, specs)
+		})
+	}
+}
+
+func initFakeGit() {
+	gitfake.Users = map[string]*git.User{
+		testUserName:  {ID: testUserID, Name: testUserName, Email: testUserEmail},
+		testUser2Name: {ID: testUser2ID, Name: testUser2Name, Email: testUser2Email},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		testRepo: {
+			UserIsAdmin: map[string]bool{},
+			PullRequests: map[int]*git.PullRequest{
+				testPRID: {
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Head:  git.Head{Sha: testSHA},
+					Base:  git.Base{Ref: "master"},
+				},
+			},
+			Comments:       map[int][]git.IssueComment{},
+			CommitStatuses: map[string][]git.CommitStatus{},
+			Commits:        map[string][]git.Commit{},
+		},
+	}
+}
+
+func buildTestConfigForOverride() *cicdv1.IntegrationConfig {
+	return &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigName,
+			Namespace: testNamespace,
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+}
+
+func buildTestWebhookCommentOverride() *git.Webhook {
+	return &git.Webhook{
+		EventType: git.EventTypeIssueComment,
+		Repo: git.Repository{
+			Name: testRepo,
+		},
+		IssueComment: &git.IssueComment{
+			Comment: git.Comment{
+				CreatedAt: &metav1.Time{Time: time.Now()},
+			},
+			Issue: git.Issue{
+				PullRequest: &git.PullRequest{
+					ID:    testPRID,
+					Title: "test-pull-request",
+					State: git.PullRequestStateOpen,
+					Author: git.User{
+						ID:    testUserID,
+						Name:  testUserName,
+						Email: testUserEmail,
+					},
+					URL: "https://github.com/tmax-cloud/cicd-operator/pulls/1",
+					Base: git.Base{
+						Ref: "master",
+					},
+				},
+			},
+		},
+	}
+}
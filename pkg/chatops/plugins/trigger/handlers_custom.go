@@ -0,0 +1,178 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// HandleCustomCommand handles the custom commands defined in the IntegrationConfig's commands, which trigger the jobs
+// with the parameters set by the arguments
+func (h *Handler) HandleCustomCommand(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	issueComment := webhook.IssueComment
+	// Do nothing if it's not pull request's comment or it's closed
+	if issueComment.Issue.PullRequest == nil || issueComment.Issue.PullRequest.State != git.PullRequestStateOpen {
+		return nil
+	}
+
+	custom := config.Spec.Commands.Find(command.Type)
+	if custom == nil {
+		return nil
+	}
+
+	// Authorize or exit
+	if err := h.authorizeCustomCommand(config, custom, &webhook.Sender, issueComment); err != nil {
+		return h.registerUnauthorizedComment(config, issueComment.Issue.PullRequest.ID, err)
+	}
+
+	// Load jobs from the config file at the pull request's head
+	if config.Spec.Jobs.ConfigFile != nil {
+		gitCli, err := utils.GetGitCli(config, h.Client)
+		if err != nil {
+			return err
+		}
+		config, err = dispatcher.LoadConfigFile(config, gitCli, issueComment.Issue.PullRequest.Head.Sha)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Set the parameters by the arguments
+	params, err := custom.GetParamValues(command.Args, config.Spec.ParamConfig)
+	if err != nil {
+		return h.registerCustomCommandComment(config, issueComment.Issue.PullRequest.ID, generateMalformedCustomCommandComment(custom, err))
+	}
+	config = config.DeepCopy()
+	overrideParams(config, params)
+
+	// Generate IntegrationJob for the PullRequest
+	prs := []git.PullRequest{*issueComment.Issue.PullRequest}
+	job := dispatcher.GeneratePreSubmitWithManualJobs(prs, &webhook.Repo, &webhook.Sender, config)
+	var jobs cicdv1.Jobs
+	if job != nil {
+		jobs = job.Spec.Jobs
+	}
+	for _, j := range custom.Jobs {
+		if !jobExists(j, jobs) {
+			return h.registerCustomCommandComment(config, issueComment.Issue.PullRequest.ID, generateCustomCommandUnknownJobComment(custom, j))
+		}
+	}
+
+	// Filter only the command's (and their dependent) jobs
+	if err := filterDependentJobs(job, custom.Jobs...); err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("%s called /%s for %s", webhook.Sender.Name, custom.Name, issueComment.Issue.PullRequest.URL))
+	return h.Client.Create(context.Background(), job)
+}
+
+// authorizeCustomCommand decides if the sender is authorized to call the custom command
+func (h *Handler) authorizeCustomCommand(cfg *cicdv1.IntegrationConfig, custom *cicdv1.ChatOpsCommand, sender *git.User, issueComment *git.IssueComment) error {
+	// Check if it's one of the allowed users
+	for _, u := range custom.Users {
+		if u == sender.Name {
+			return nil
+		}
+	}
+
+	switch custom.GetPermission() {
+	case cicdv1.ChatOpsCommandPermissionAuthor:
+		return h.authorize(cfg, sender, issueComment)
+	case cicdv1.ChatOpsCommandPermissionAdmin:
+		g, err := utils.GetGitCli(cfg, h.Client)
+		if err != nil {
+			return err
+		}
+		ok, err := g.IsUserRepoAdmin(*sender)
+		if err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+	default:
+		g, err := utils.GetGitCli(cfg, h.Client)
+		if err != nil {
+			return err
+		}
+		ok, err := g.CanUserWriteToRepo(*sender)
+		if err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+	}
+
+	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
+}
+
+// overrideParams overrides the IntegrationConfig's parameter values with the ones set by the arguments
+func overrideParams(cfg *cicdv1.IntegrationConfig, params []cicdv1.ParameterValue) {
+	if len(params) == 0 {
+		return
+	}
+	if cfg.Spec.ParamConfig == nil {
+		cfg.Spec.ParamConfig = &cicdv1.ParameterConfig{}
+	}
+
+	for _, param := range params {
+		overridden := false
+		for i, v := range cfg.Spec.ParamConfig.ParamValue {
+			if v.Name == param.Name {
+				cfg.Spec.ParamConfig.ParamValue[i] = param
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			cfg.Spec.ParamConfig.ParamValue = append(cfg.Spec.ParamConfig.ParamValue, param)
+		}
+	}
+}
+
+// registerCustomCommandComment registers comment about the custom command
+func (h *Handler) registerCustomCommandComment(config *cicdv1.IntegrationConfig, issueID int, comment string) error {
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueID, comment)
+}
+
+func generateMalformedCustomCommandComment(custom *cicdv1.ChatOpsCommand, err error) string {
+	return fmt.Sprintf("[%s ALERT]\n\n`/%s` comment is malformed: %s\n\n"+
+		"You can call the command by commenting...\n"+
+		"- `%s`\n", strings.ToUpper(custom.Name), custom.Name, err.Error(), custom.GetUsage())
+}
+
+func generateCustomCommandUnknownJobComment(custom *cicdv1.ChatOpsCommand, job string) string {
+	return fmt.Sprintf("[%s ALERT]\n\nJob `%s` triggered by `/%s` does not exist for this pull request.",
+		strings.ToUpper(custom.Name), job, custom.Name)
+}
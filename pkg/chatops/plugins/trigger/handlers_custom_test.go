@@ -0,0 +1,157 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHandler_HandleCustomCommand(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	const testRepo = "tmax-cloud/cicd-operator"
+
+	ic := buildTestJobs()
+	ic.Spec.Git = cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: testRepo, Token: &cicdv1.GitToken{Value: "dummy"}}
+	ic.Spec.ParamConfig = &cicdv1.ParameterConfig{
+		ParamDefine: []cicdv1.ParameterDefine{
+			{Name: "env", Type: cicdv1.ParameterTypeString, Enum: []string{"staging", "production"}},
+			{Name: "tags", Type: cicdv1.ParameterTypeArray},
+		},
+		ParamValue: []cicdv1.ParameterValue{{Name: "env", StringVal: "staging"}},
+	}
+	deploy := cicdv1.ChatOpsCommand{
+		Name: "deploy",
+		Jobs: []string{"c-1"},
+		Args: []cicdv1.ChatOpsCommandArg{{Param: "env", Required: true}, {Param: "tags"}},
+	}
+	ic.Spec.Commands = cicdv1.ChatOpsCommands{
+		deploy,
+		{Name: "build", Jobs: []string{"a-2", "b-2"}, Permission: cicdv1.ChatOpsCommandPermissionAuthor},
+		{Name: "release", Jobs: []string{"b-1"}, Permission: cicdv1.ChatOpsCommandPermissionAdmin, Users: []string{testUserName}},
+		{Name: "publish", Jobs: []string{"d-1"}},
+	}
+
+	tc := map[string]struct {
+		command  chatops.Command
+		canWrite bool
+
+		expectedJobs    []string
+		expectedParams  []cicdv1.ParameterValue
+		expectedComment string
+	}{
+		"deploy": {
+			command:      chatops.Command{Type: "deploy", Args: []string{"production", "v1", "v2"}},
+			canWrite:     true,
+			expectedJobs: []string{"c-1"},
+			expectedParams: []cicdv1.ParameterValue{
+				{Name: "env", StringVal: "production"},
+				{Name: "tags", ArrayVal: []string{"v1", "v2"}},
+			},
+		},
+		"deployWithoutOptionalArg": {
+			command:        chatops.Command{Type: "deploy", Args: []string{"production"}},
+			canWrite:       true,
+			expectedJobs:   []string{"c-1"},
+			expectedParams: []cicdv1.ParameterValue{{Name: "env", StringVal: "production"}},
+		},
+		"author": {
+			command:        chatops.Command{Type: "build"},
+			expectedJobs:   []string{"a-1", "a-2", "b-1", "b-2"},
+			expectedParams: []cicdv1.ParameterValue{{Name: "env", StringVal: "staging"}},
+		},
+		"allowedUser": {
+			command:        chatops.Command{Type: "release"},
+			expectedJobs:   []string{"b-1"},
+			expectedParams: []cicdv1.ParameterValue{{Name: "env", StringVal: "staging"}},
+		},
+		"unauthorized": {
+			command:         chatops.Command{Type: "deploy", Args: []string{"production"}},
+			expectedComment: generateUnauthorizedComment(testUserName, testRepo),
+		},
+		"missingArg": {
+			command:         chatops.Command{Type: "deploy"},
+			canWrite:        true,
+			expectedComment: generateMalformedCustomCommandComment(&deploy, fmt.Errorf("argument env is required")),
+		},
+		"invalidArg": {
+			command:         chatops.Command{Type: "deploy", Args: []string{"dev"}},
+			canWrite:        true,
+			expectedComment: generateMalformedCustomCommandComment(&deploy, fmt.Errorf("parameter env should be one of [staging, production], but got \"dev\"")),
+		},
+		"unknownJob": {
+			command:         chatops.Command{Type: "publish"},
+			canWrite:        true,
+			expectedComment: generateCustomCommandUnknownJobComment(&ic.Spec.Commands[3], "d-1"),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+			handler := &Handler{Client: fakeCli}
+
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {
+					UserCanWrite: map[string]bool{testUserName: c.canWrite},
+					Comments:     map[int][]git.IssueComment{},
+				},
+			}
+			wh := buildTestWebhookForTrigger()
+
+			if err := handler.HandleCustomCommand(c.command, wh, ic); err != nil {
+				t.Fatal(err)
+			}
+
+			var ijList cicdv1.IntegrationJobList
+			if err := fakeCli.List(context.Background(), &ijList); err != nil {
+				t.Fatal(err)
+			}
+			comments := gitfake.Repos[testRepo].Comments[wh.IssueComment.Issue.PullRequest.ID]
+			if c.expectedComment != "" {
+				assert.Equal(t, 0, len(ijList.Items))
+				assert.Equal(t, 1, len(comments))
+				assert.Equal(t, c.expectedComment, comments[0].Comment.Body)
+				return
+			}
+
+			assert.Equal(t, 0, len(comments))
+			assert.Equal(t, 1, len(ijList.Items))
+			var jobs []string
+			for _, j := range ijList.Items[0].Spec.Jobs {
+				jobs = append(jobs, j.Name)
+			}
+			assert.Equal(t, c.expectedJobs, jobs)
+			assert.Equal(t, c.expectedParams, ijList.Items[0].Spec.ParamConfig.ParamValue)
+		})
+	}
+
+	// The IntegrationConfig is not modified
+	assert.Equal(t, []cicdv1.ParameterValue{{Name: "env", StringVal: "staging"}}, ic.Spec.ParamConfig.ParamValue)
+}
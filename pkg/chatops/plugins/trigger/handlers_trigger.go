@@ -26,6 +26,7 @@ import (
 	"github.com/tmax-cloud/cicd-operator/pkg/dispatcher"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // Command types for trigger handler
@@ -34,12 +35,32 @@ const (
 	CommandTypeRetest = "retest"
 )
 
+// HelpTest are the usages of the /test command
+var HelpTest = []chatops.CommandHelp{
+	{Usage: "/test", Description: "Triggers all the jobs, except for the manual jobs."},
+	{Usage: "/test <job>", Description: "Triggers a specific job and the jobs it depends on, even if it is a manual job."},
+}
+
+// HelpRetest are the usages of the /retest command
+var HelpRetest = []chatops.CommandHelp{
+	{Usage: "/retest", Description: "Triggers all the jobs. Same as `/test`."},
+	{Usage: "/retest failed", Description: "Triggers only the failed jobs and the jobs they depend on."},
+}
+
+// RetestArgFailed is an argument of /retest, to retest only the failed jobs
+const RetestArgFailed = "failed"
+
+// TestArgAll is an argument of /test, to test all the jobs except for the manual ones
+const TestArgAll = "all"
+
+var log = logf.Log.WithName("trigger-plugin")
+
 // Handler is an implementation of a ChatOps Handler
 type Handler struct {
 	Client client.Client
 }
 
-// HandleChatOps handles /test and /retest comment commands
+// HandleChatOps handles /test, /test all, /test <job>, /retest and /retest failed comment commands
 func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
 	issueComment := webhook.IssueComment
 	// Do nothing if it's not pull request's comment or it's closed
@@ -55,25 +76,49 @@ func (h *Handler) HandleChatOps(command chatops.Command, webhook *git.Webhook, c
 		return nil
 	}
 
+	// Load jobs from the config file at the pull request's head
+	if config.Spec.Jobs.ConfigFile != nil {
+		gitCli, err := utils.GetGitCli(config, h.Client)
+		if err != nil {
+			return err
+		}
+		config, err = dispatcher.LoadConfigFile(config, gitCli, issueComment.Issue.PullRequest.Head.Sha)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Test all (=retest)
-	if len(command.Args) == 0 {
+	if len(command.Args) == 0 || (command.Type == CommandTypeTest && command.Args[0] == TestArgAll) {
 		return h.handleRetestCommand(webhook, config)
 	}
 
+	// Retest only the failed jobs
+	if command.Type == CommandTypeRetest && command.Args[0] == RetestArgFailed {
+		return h.handleRetestFailedCommand(webhook, config)
+	}
+
 	return h.handleTestCommand(command, webhook, config)
 }
 
 // handleTestCommand handles '/test <ARGS>' command
+// The manual jobs can also be triggered by it
 func (h *Handler) handleTestCommand(command chatops.Command, webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
 	// Generate IntegrationJob for the PullRequest
 	prs := []git.PullRequest{*webhook.IssueComment.Issue.PullRequest}
-	job := dispatcher.GeneratePreSubmit(prs, &webhook.Repo, &webhook.Sender, config)
-	if job == nil {
-		return nil
+	job := dispatcher.GeneratePreSubmitWithManualJobs(prs, &webhook.Repo, &webhook.Sender, config)
+
+	// Let the user know the available jobs, if the job does not exist
+	var jobs cicdv1.Jobs
+	if job != nil {
+		jobs = job.Spec.Jobs
+	}
+	if !jobExists(command.Args[0], jobs) {
+		return h.registerUnknownJobComment(config, webhook.IssueComment.Issue.PullRequest.ID, command.Args[0], jobs)
 	}
 
 	// Filter only selected (and its dependent) jobs
-	if err := filterDependentJobs(command.Args[0], job); err != nil {
+	if err := filterDependentJobs(job, command.Args[0]); err != nil {
 		return err
 	}
 
@@ -106,6 +151,26 @@ func (h *Handler) handleRetestCommand(webhook *git.Webhook, config *cicdv1.Integ
 	return nil
 }
 
+// handleRetestFailedCommand handles '/retest failed' command
+func (h *Handler) handleRetestFailedCommand(webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+
+	pr := webhook.IssueComment.Issue.PullRequest
+	job, err := dispatcher.RetestFailed(h.Client, gitCli, pr, &webhook.Repo, &webhook.Sender, config)
+	if err != nil {
+		return err
+	}
+
+	// Let the user know there is nothing to retest. Skip if token is empty
+	if job == nil && config.Spec.Git.Token != nil {
+		return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateNoFailedJobComment())
+	}
+	return nil
+}
+
 // authorize decides if the sender is authorized to trigger the tests
 func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender *git.User, issueComment *git.IssueComment) error {
 	// Check if it's PR's author
@@ -128,11 +193,27 @@ func (h *Handler) authorize(cfg *cicdv1.IntegrationConfig, sender *git.User, iss
 	return &git.UnauthorizedError{User: sender.Name, Repo: cfg.Spec.Git.Repository}
 }
 
+// jobExists checks if the job of the name exists in the jobs
+func jobExists(name string, jobs cicdv1.Jobs) bool {
+	for _, j := range jobs {
+		if j.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // filterDependentJobs filters out unnecessary (not dependent) jobs
-func filterDependentJobs(target string, job *cicdv1.IntegrationJob) error {
-	dependents, err := dependentJobs(target, job.Spec.Jobs)
-	if err != nil {
-		return err
+func filterDependentJobs(job *cicdv1.IntegrationJob, targets ...string) error {
+	dependents := map[string]struct{}{}
+	for _, target := range targets {
+		deps, err := dependentJobs(target, job.Spec.Jobs)
+		if err != nil {
+			return err
+		}
+		for d := range deps {
+			dependents[d] = struct{}{}
+		}
 	}
 
 	filteredJobs := cicdv1.Jobs{}
@@ -187,6 +268,39 @@ func (h *Handler) registerUnauthorizedComment(config *cicdv1.IntegrationConfig,
 	return nil
 }
 
+// registerUnknownJobComment registers comment that the job does not exist, with the list of the available jobs
+func (h *Handler) registerUnknownJobComment(config *cicdv1.IntegrationConfig, issueID int, name string, jobs cicdv1.Jobs) error {
+	// Skip if token is empty
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, h.Client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, issueID, generateUnknownJobComment(name, jobs))
+}
+
+func generateUnknownJobComment(name string, jobs cicdv1.Jobs) string {
+	comment := fmt.Sprintf("[TEST ALERT]\n\nJob `%s` does not exist\n\n"+
+		"You can trigger the jobs by commenting...\n"+
+		"- `/test all` (all the jobs, except for the manual ones)\n", name)
+	for _, j := range jobs {
+		comment += fmt.Sprintf("- `/test %s`", j.Name)
+		if j.Manual {
+			comment += " (manual)"
+		}
+		comment += "\n"
+	}
+	return comment
+}
+
+func generateNoFailedJobComment() string {
+	return "[TEST ALERT]\n\nThere is no failed job to retest\n\n" +
+		"You can trigger all the jobs again by commenting `/retest`\n"
+}
+
 func generateUnauthorizedComment(user, repo string) string {
 	return fmt.Sprintf("User `%s` is not allowed to trigger the test for the repository `%s`\n\n"+
 		"If you want to trigger the test, you need to...\n"+
@@ -25,6 +25,7 @@ import (
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -61,6 +62,17 @@ func TestChatOps_handleTrigger(t *testing.T) {
 		assert.Equal(t, 6, len(ij.Spec.Jobs))
 	})
 
+	// /test all
+	testJobTrigger(t, handler, fakeCli, wh, ic, chatops.Command{Type: "test", Args: []string{"all"}}, func(ij *cicdv1.IntegrationJob) {
+		assert.Equal(t, 6, len(ij.Spec.Jobs))
+	})
+
+	// /test c-1 (manual)
+	testJobTrigger(t, handler, fakeCli, wh, ic, chatops.Command{Type: "test", Args: []string{"c-1"}}, func(ij *cicdv1.IntegrationJob) {
+		assert.Equal(t, 1, len(ij.Spec.Jobs))
+		assert.Equal(t, "c-1", ij.Spec.Jobs[0].Name)
+	})
+
 	// /test a-1
 	testJobTrigger(t, handler, fakeCli, wh, ic, chatops.Command{Type: "test", Args: []string{"a-1"}}, func(ij *cicdv1.IntegrationJob) {
 		assert.Equal(t, 1, len(ij.Spec.Jobs))
@@ -102,6 +114,92 @@ func TestChatOps_handleTrigger(t *testing.T) {
 	})
 }
 
+func TestChatOps_handleTestUnknownJob(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestJobs()
+	ic.Spec.Git = cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "tmax-cloud/cicd-operator", Token: &cicdv1.GitToken{Value: "dummy"}}
+	wh := buildTestWebhookForTrigger()
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"tmax-cloud/cicd-operator": {Comments: map[int][]git.IssueComment{}},
+	}
+
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	// /test unknown
+	if err := handler.HandleChatOps(chatops.Command{Type: "test", Args: []string{"unknown"}}, wh, ic); err != nil {
+		t.Fatal(err)
+	}
+
+	var ijList cicdv1.IntegrationJobList
+	if err := fakeCli.List(context.Background(), &ijList); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(ijList.Items))
+
+	comments := gitfake.Repos["tmax-cloud/cicd-operator"].Comments[wh.IssueComment.Issue.PullRequest.ID]
+	assert.Equal(t, 1, len(comments))
+	assert.Equal(t, "[TEST ALERT]\n\nJob `unknown` does not exist\n\n"+
+		"You can trigger the jobs by commenting...\n"+
+		"- `/test all` (all the jobs, except for the manual ones)\n"+
+		"- `/test a-1`\n- `/test a-2`\n- `/test a-3`\n- `/test a-4`\n- `/test b-1`\n- `/test b-2`\n"+
+		"- `/test c-1` (manual)\n", comments[0].Comment.Body)
+}
+
+func TestChatOps_handleRetestFailed(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := buildTestJobs()
+	ic.Spec.Git = cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "tmax-cloud/cicd-operator", Token: &cicdv1.GitToken{Value: "dummy"}}
+	wh := buildTestWebhookForTrigger()
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"tmax-cloud/cicd-operator": {
+			CommitStatuses: map[string][]git.CommitStatus{
+				wh.IssueComment.Issue.PullRequest.Head.Sha: {
+					{Context: "a-1", State: git.CommitStatusStateSuccess},
+					{Context: "a-2", State: git.CommitStatusStateFailure},
+					{Context: "a-3", State: git.CommitStatusStateSuccess},
+					{Context: "b-1", State: git.CommitStatusStateSuccess},
+					{Context: "b-2", State: git.CommitStatusStateSuccess},
+				},
+			},
+		},
+	}
+
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	handler := &Handler{Client: fakeCli}
+
+	// /retest failed
+	testJobTrigger(t, handler, fakeCli, wh, ic, chatops.Command{Type: "retest", Args: []string{"failed"}}, func(ij *cicdv1.IntegrationJob) {
+		assert.Equal(t, 2, len(ij.Spec.Jobs))
+		assert.Equal(t, "a-1", ij.Spec.Jobs[0].Name)
+		assert.Equal(t, "a-2", ij.Spec.Jobs[1].Name)
+	})
+
+	// /retest failed, without any failed job
+	gitfake.Repos["tmax-cloud/cicd-operator"].CommitStatuses[wh.IssueComment.Issue.PullRequest.Head.Sha] = []git.CommitStatus{{Context: "a-2", State: git.CommitStatusStateSuccess}}
+	gitfake.Repos["tmax-cloud/cicd-operator"].Comments = map[int][]git.IssueComment{}
+	if err := handler.HandleChatOps(chatops.Command{Type: "retest", Args: []string{"failed"}}, wh, ic); err != nil {
+		t.Fatal(err)
+	}
+
+	var ijList cicdv1.IntegrationJobList
+	if err := fakeCli.List(context.Background(), &ijList); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(ijList.Items))
+
+	comments := gitfake.Repos["tmax-cloud/cicd-operator"].Comments[wh.IssueComment.Issue.PullRequest.ID]
+	assert.Equal(t, 1, len(comments))
+	assert.Equal(t, "[TEST ALERT]\n\nThere is no failed job to retest\n\n"+
+		"You can trigger all the jobs again by commenting `/retest`\n", comments[0].Comment.Body)
+}
+
 type testTriggerVerifier func(ij *cicdv1.IntegrationJob)
 
 func testJobTrigger(t *testing.T, handler *Handler, fakeCli client.Client, wh *git.Webhook, ic *cicdv1.IntegrationConfig, command chatops.Command, verifyFunc testTriggerVerifier) {
@@ -126,6 +224,8 @@ func buildTestJobs() *cicdv1.IntegrationConfig {
 		     \->  a-3  -/
 
 		b-1  -->  b-2
+
+		c-1 (manual)
 	*/
 	ic := &cicdv1.IntegrationConfig{
 		ObjectMeta: metav1.ObjectMeta{
@@ -162,6 +262,11 @@ func buildTestJobs() *cicdv1.IntegrationConfig {
 	jobB2.After = []string{"b-1"}
 	ic.Spec.Jobs.PreSubmit = append(ic.Spec.Jobs.PreSubmit, jobB2)
 
+	jobC1 := cicdv1.Job{}
+	jobC1.Name = "c-1"
+	jobC1.Manual = true
+	ic.Spec.Jobs.PreSubmit = append(ic.Spec.Jobs.PreSubmit, jobC1)
+
 	return ic
 }
 
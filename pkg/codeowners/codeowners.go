@@ -0,0 +1,283 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tmax-cloud/cicd-operator/pkg/chatops"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// Paths are the locations of the CODEOWNERS file, searched in order
+var Paths = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwners is a parsed CODEOWNERS file
+type CodeOwners struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Requirement is a group of the changed files, which should be approved by one of the owners
+type Requirement struct {
+	Owners []string
+	Files  []string
+}
+
+// Load loads the CODEOWNERS file of the ref from the repository
+func Load(gitCli git.Client, ref string) (*CodeOwners, error) {
+	var errs []string
+	for _, p := range Paths {
+		raw, err := gitCli.GetFile(p, ref)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return Parse(string(raw)), nil
+	}
+	return nil, fmt.Errorf("cannot get CODEOWNERS: %s", strings.Join(errs, ", "))
+}
+
+// Parse parses the content of a CODEOWNERS file
+// Only the users are supported as owners, i.e., teams and emails are ignored
+func Parse(content string) *CodeOwners {
+	c := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		// Skip comments and sections (of GitLab)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		fields := strings.Fields(line)
+		r := rule{pattern: compilePattern(fields[0])}
+		for _, o := range fields[1:] {
+			if strings.HasPrefix(o, "#") {
+				break
+			}
+			// Skip teams (@org/team) and emails
+			if !strings.HasPrefix(o, "@") || strings.Contains(o, "/") {
+				continue
+			}
+			r.owners = append(r.owners, strings.TrimPrefix(o, "@"))
+		}
+		c.rules = append(c.rules, r)
+	}
+	return c
+}
+
+// compilePattern converts a gitignore-style pattern into a regular expression
+func compilePattern(pattern string) *regexp.Regexp {
+	// Patterns with a slash, except the trailing one, are relative to the root
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "/**/"):
+			// Matches zero or more directories
+			expr.WriteString("/(.*/)?")
+			i += 3
+		case i == 0 && strings.HasPrefix(pattern, "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(/.*)?$")
+	}
+	return regexp.MustCompile(expr.String())
+}
+
+// OwnersOf returns the owners of the file. The last matching rule takes precedence
+func (c *CodeOwners) OwnersOf(file string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(file) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// Missing returns the requirements of the changed files, which are not approved by the approvers
+func (c *CodeOwners) Missing(files []string, approvers []string) []Requirement {
+	approved := map[string]struct{}{}
+	for _, a := range approvers {
+		approved[a] = struct{}{}
+	}
+
+	var reqs []Requirement
+	reqIndex := map[string]int{}
+	for _, f := range files {
+		owners := c.OwnersOf(f)
+		if len(owners) == 0 || containsAny(approved, owners) {
+			continue
+		}
+		key := strings.Join(owners, ",")
+		i, exist := reqIndex[key]
+		if !exist {
+			i = len(reqs)
+			reqIndex[key] = i
+			reqs = append(reqs, Requirement{Owners: owners})
+		}
+		reqs[i].Files = append(reqs[i].Files, f)
+	}
+	return reqs
+}
+
+func containsAny(set map[string]struct{}, items []string) bool {
+	for _, i := range items {
+		if _, exist := set[i]; exist {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangedFiles lists the files changed by the pull request, including the old names of the renamed ones
+func ChangedFiles(gitCli git.Client, id int) ([]string, error) {
+	diff, err := gitCli.GetPullRequestDiff(id)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]struct{}{}
+	for _, c := range diff.Changes {
+		files[c.Filename] = struct{}{}
+		if c.OldFilename != "" {
+			files[c.OldFilename] = struct{}{}
+		}
+	}
+
+	var list []string
+	for f := range files {
+		list = append(list, f)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Approvers lists the users who approved the pull request, using the approve commands (i.e., /approve, /ci-approve),
+// the reviews, and the approvals of the git server. The author of the pull request is excluded
+func Approvers(gitCli git.Client, pr *git.PullRequest) ([]string, error) {
+	comments, err := gitCli.ListComments(pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort oldest comment to latest comment
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].Comment.CreatedAt == nil || comments[j].Comment.CreatedAt == nil {
+			return false
+		}
+		return comments[i].Comment.CreatedAt.Before(comments[j].Comment.CreatedAt)
+	})
+
+	approved := map[string]struct{}{}
+	for _, c := range comments {
+		switch c.ReviewState {
+		case git.PullRequestReviewStateApproved:
+			approved[c.Author.Name] = struct{}{}
+			continue
+		case git.PullRequestReviewStateUnapproved:
+			delete(approved, c.Author.Name)
+			continue
+		}
+		for _, cmd := range chatops.ExtractCommands(c.Comment.Body) {
+			if cmd.Type != "approve" && cmd.Type != "ci-approve" {
+				continue
+			}
+			if len(cmd.Args) == 0 {
+				approved[c.Author.Name] = struct{}{}
+			} else if len(cmd.Args) == 1 && cmd.Args[0] == "cancel" {
+				delete(approved, c.Author.Name)
+			}
+		}
+	}
+
+	serverApprovers, err := gitCli.ListPullRequestApprovers(pr.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range serverApprovers {
+		approved[u.Name] = struct{}{}
+	}
+	delete(approved, pr.Author.Name)
+	delete(approved, "")
+
+	var approvers []string
+	for a := range approved {
+		approvers = append(approvers, a)
+	}
+	sort.Strings(approvers)
+	return approvers, nil
+}
+
+// Check returns the requirements of the pull request not approved yet, using the CODEOWNERS file of its base branch
+func Check(gitCli git.Client, pr *git.PullRequest, extraApprovers ...string) ([]Requirement, error) {
+	owners, err := Load(gitCli, pr.Base.Ref)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ChangedFiles(gitCli, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+	approvers, err := Approvers(gitCli, pr)
+	if err != nil {
+		return nil, err
+	}
+	return owners.Missing(files, append(approvers, extraApprovers...)), nil
+}
+
+// Describe describes the requirements as a markdown list
+func Describe(reqs []Requirement) string {
+	var lines []string
+	for _, r := range reqs {
+		var owners []string
+		for _, o := range r.Owners {
+			owners = append(owners, "@"+o)
+		}
+		lines = append(lines, fmt.Sprintf("- `%s`: %s", strings.Join(r.Files, "`, `"), strings.Join(owners, " or ")))
+	}
+	return strings.Join(lines, "\n")
+}
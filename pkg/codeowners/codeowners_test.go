@@ -0,0 +1,155 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package codeowners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testCodeOwners = `# Default owners
+*       @default-owner
+
+*.go    @go-owner @org/go-team
+/docs/  @doc-owner doc@tmax.co.kr
+api/**/types.go @api-owner
+build/  @build-owner # inline comment
+/Makefile
+**/testdata @test-owner
+`
+
+func TestCodeOwners_OwnersOf(t *testing.T) {
+	owners := Parse(testCodeOwners)
+
+	tc := map[string][]string{
+		"README.md":                    {"default-owner"},
+		"main.go":                      {"go-owner"},
+		"pkg/git/git.go":               {"go-owner"},
+		"docs/README.md":               {"doc-owner"},
+		"docs/images/a.png":            {"doc-owner"},
+		"pkg/docs/a.md":                {"default-owner"},
+		"api/v1/types.go":              {"api-owner"},
+		"api/types.go":                 {"api-owner"},
+		"build/Dockerfile":             {"build-owner"},
+		"hack/build/Dockerfile":        {"build-owner"},
+		"Makefile":                     nil,
+		"testdata/a.json":              {"test-owner"},
+		"pkg/git/testdata/a.json":      {"test-owner"},
+		"pkg/Makefile":                 {"default-owner"},
+		"docs":                         {"default-owner"},
+		"config/release.yaml":          {"default-owner"},
+		"api/v1/integrationconfig.go":  {"go-owner"},
+		"api/v1/integrationconfig.yml": {"default-owner"},
+	}
+
+	for file, expected := range tc {
+		t.Run(file, func(t *testing.T) {
+			require.Equal(t, expected, owners.OwnersOf(file))
+		})
+	}
+}
+
+func TestCodeOwners_Missing(t *testing.T) {
+	owners := Parse(testCodeOwners)
+	files := []string{"Makefile", "README.md", "docs/a.md", "docs/b.md", "main.go"}
+
+	tc := map[string]struct {
+		approvers []string
+		expected  []Requirement
+	}{
+		"noApprovers": {
+			expected: []Requirement{
+				{Owners: []string{"default-owner"}, Files: []string{"README.md"}},
+				{Owners: []string{"doc-owner"}, Files: []string{"docs/a.md", "docs/b.md"}},
+				{Owners: []string{"go-owner"}, Files: []string{"main.go"}},
+			},
+		},
+		"partial": {
+			approvers: []string{"doc-owner", "someone"},
+			expected: []Requirement{
+				{Owners: []string{"default-owner"}, Files: []string{"README.md"}},
+				{Owners: []string{"go-owner"}, Files: []string{"main.go"}},
+			},
+		},
+		"all": {
+			approvers: []string{"doc-owner", "go-owner", "default-owner"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, owners.Missing(files, c.approvers))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ic := &cicdv1.IntegrationConfig{
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+		},
+	}
+	gitCli := &gitfake.Client{IntegrationConfig: ic}
+	pr := &git.PullRequest{ID: 3, Author: git.User{Name: "go-owner"}, Base: git.Base{Ref: "master"}}
+
+	now := time.Now()
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo": {
+			Files: map[string]string{"master:docs/CODEOWNERS": testCodeOwners},
+			PullRequestDiffs: map[int]*git.Diff{
+				3: {Changes: []git.Change{{Filename: "main.go", OldFilename: "docs/main.go"}, {Filename: "README.md"}}},
+			},
+			Comments: map[int][]git.IssueComment{
+				3: {
+					{Comment: git.Comment{Body: "/approve cancel", CreatedAt: &metav1.Time{Time: now.Add(-time.Minute)}}, Author: git.User{Name: "doc-owner"}},
+					{Comment: git.Comment{Body: "/approve", CreatedAt: &metav1.Time{Time: now.Add(-2 * time.Minute)}}, Author: git.User{Name: "doc-owner"}},
+					{Comment: git.Comment{Body: "/approve", CreatedAt: &metav1.Time{Time: now}}, Author: git.User{Name: "go-owner"}},
+					{ReviewState: git.PullRequestReviewStateApproved, Comment: git.Comment{CreatedAt: &metav1.Time{Time: now}}, Author: git.User{Name: "reviewer"}},
+				},
+			},
+			Approvers: map[int][]git.User{3: {{Name: "server-approver"}}},
+		},
+	}
+
+	approvers, err := Approvers(gitCli, pr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"reviewer", "server-approver"}, approvers)
+
+	missing, err := Check(gitCli, pr)
+	require.NoError(t, err)
+	require.Equal(t, []Requirement{
+		{Owners: []string{"default-owner"}, Files: []string{"README.md"}},
+		{Owners: []string{"doc-owner"}, Files: []string{"docs/main.go"}},
+		{Owners: []string{"go-owner"}, Files: []string{"main.go"}},
+	}, missing)
+	require.Equal(t, "- `README.md`: @default-owner\n- `docs/main.go`: @doc-owner\n- `main.go`: @go-owner", Describe(missing))
+
+	missing, err = Check(gitCli, pr, "default-owner", "doc-owner", "go-owner")
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	// No CODEOWNERS
+	pr.Base.Ref = "dev"
+	_, err = Check(gitCli, pr)
+	require.Error(t, err)
+}
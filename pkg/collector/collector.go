@@ -19,11 +19,14 @@ package collector
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"gopkg.in/robfig/cron.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -44,6 +47,9 @@ type collector struct {
 	cronSpec string
 	cronID   cron.EntryID
 
+	auditSpec string
+	auditID   cron.EntryID
+
 	runGc chan struct{}
 }
 
@@ -61,6 +67,9 @@ func New(c client.Client) (*collector, error) {
 		return nil, err
 	}
 	gc.cronID = id
+	if err := gc.scheduleAudit(parseAuditPeriod()); err != nil {
+		return nil, err
+	}
 	return gc, nil
 }
 
@@ -77,6 +86,10 @@ func (c *collector) Start() {
 }
 
 func (c *collector) reconfigure() error {
+	if err := c.reconfigureAudit(); err != nil {
+		return err
+	}
+
 	period := parseGcPeriod()
 	if c.cronSpec == period {
 		return nil
@@ -96,6 +109,41 @@ func (c *collector) reconfigure() error {
 	return nil
 }
 
+// reconfigureAudit reschedules the orphan audit if its period is changed
+func (c *collector) reconfigureAudit() error {
+	period := parseAuditPeriod()
+	if c.auditSpec == period {
+		return nil
+	}
+	if c.auditSpec != "" {
+		c.cron.Remove(c.auditID)
+	}
+	if err := c.scheduleAudit(period); err != nil {
+		return err
+	}
+
+	if c.auditSpec == "" {
+		log.Info("Orphan audit is disabled")
+	} else {
+		log.Info(fmt.Sprintf("Orphan audit runs %s", c.auditSpec))
+	}
+	return nil
+}
+
+// scheduleAudit schedules the orphan audit. It is not scheduled if the period is empty
+func (c *collector) scheduleAudit(period string) error {
+	c.auditSpec = period
+	if period == "" {
+		return nil
+	}
+	id, err := c.cron.AddFunc(period, c.auditOrphans)
+	if err != nil {
+		return err
+	}
+	c.auditID = id
+	return nil
+}
+
 func (c *collector) collect() {
 	log.Info("Garbage collector is running...")
 	jobList := &cicdv1.IntegrationJobList{}
@@ -107,22 +155,73 @@ func (c *collector) collect() {
 	}
 
 	now := time.Now()
-	for _, j := range jobList.Items {
+	histories := map[string][]*cicdv1.IntegrationJob{}
+	for i := range jobList.Items {
+		j := &jobList.Items[i]
 		if j.Status.CompletionTime == nil {
 			continue
 		}
 
-		// Collect if it's ttl is over
-		if j.Status.CompletionTime.Time.Add(time.Duration(configs.IntegrationJobTTL) * time.Hour).Before(now) {
-			log.Info(fmt.Sprintf("Deleting IntegrationJob %s/%s", j.Namespace, j.Name))
-			if err := c.client.Delete(context.Background(), &j); err != nil {
-				log.Error(err, "")
-				continue
-			}
+		// Collect if it's ttl is over. Its PipelineRun is deleted together, as it is owned by the IntegrationJob
+		if j.Status.CompletionTime.Time.Add(j.GetTTLAfterFinished()).Before(now) {
+			c.delete(j)
+			continue
+		}
+
+		key := historyKey(j)
+		histories[key] = append(histories[key], j)
+	}
+
+	c.collectHistories(histories)
+}
+
+// collectHistories deletes the old IntegrationJobs exceeding the history limits of their IntegrationConfigs
+func (c *collector) collectHistories(histories map[string][]*cicdv1.IntegrationJob) {
+	configList := &cicdv1.IntegrationConfigList{}
+	if err := c.client.List(context.Background(), configList); err != nil {
+		log.Error(err, "")
+		return
+	}
+	configMap := map[types.NamespacedName]*cicdv1.IntegrationConfig{}
+	for i := range configList.Items {
+		ic := &configList.Items[i]
+		configMap[types.NamespacedName{Name: ic.Name, Namespace: ic.Namespace}] = ic
+	}
+
+	for _, jobs := range histories {
+		ic, exist := configMap[types.NamespacedName{Name: jobs[0].Spec.ConfigRef.Name, Namespace: jobs[0].Namespace}]
+		if !exist {
+			continue
+		}
+		limit := ic.GetHistoryLimit(jobs[0].Status.State)
+		if limit == nil || len(jobs) <= int(*limit) {
+			continue
+		}
+
+		// Keep the most recent ones
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].Status.CompletionTime.After(jobs[j].Status.CompletionTime.Time)
+		})
+		for _, j := range jobs[*limit:] {
+			c.delete(j)
 		}
 	}
 }
 
+func (c *collector) delete(j *cicdv1.IntegrationJob) {
+	log.Info(fmt.Sprintf("Deleting IntegrationJob %s/%s", j.Namespace, j.Name))
+	if err := c.client.Delete(context.Background(), j, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		log.Error(err, "")
+	}
+}
+
+// historyKey is a key of the IntegrationJob's history, i.e., its IntegrationConfig, type, branch and whether it is
+// successful or not
+func historyKey(j *cicdv1.IntegrationJob) string {
+	successful := j.Status.State == cicdv1.IntegrationJobStateCompleted
+	return fmt.Sprintf("%s/%s/%s/%s/%t", j.Namespace, j.Spec.ConfigRef.Name, j.Spec.ConfigRef.Type, j.GetGroupKey(cicdv1.ConcurrencyGroupBranch), successful)
+}
+
 func parseGcPeriod() string {
 	return fmt.Sprintf("@every %dh", configs.CollectPeriod)
 }
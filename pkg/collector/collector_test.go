@@ -0,0 +1,123 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCollector_collect(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	configs.IntegrationJobTTL = 120
+
+	completedAt := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: time.Now().Add(-d)}
+	}
+	newJob := func(name string, completionTime *metav1.Time, ttl *metav1.Duration) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       cicdv1.IntegrationJobSpec{TTLAfterFinished: ttl},
+			Status:     cicdv1.IntegrationJobStatus{CompletionTime: completionTime},
+		}
+	}
+
+	c := &collector{client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		newJob("running", nil, &metav1.Duration{Duration: time.Minute}),
+		newJob("default-ttl-not-expired", completedAt(time.Hour), nil),
+		newJob("default-ttl-expired", completedAt(121*time.Hour), nil),
+		newJob("ttl-not-expired", completedAt(time.Minute), &metav1.Duration{Duration: time.Hour}),
+		newJob("ttl-expired", completedAt(2*time.Hour), &metav1.Duration{Duration: time.Hour}),
+	).Build()}
+
+	c.collect()
+
+	jobList := &cicdv1.IntegrationJobList{}
+	require.NoError(t, c.client.List(context.Background(), jobList))
+	var names []string
+	for _, j := range jobList.Items {
+		names = append(names, j.Name)
+	}
+	require.ElementsMatch(t, []string{"running", "default-ttl-not-expired", "ttl-not-expired"}, names)
+}
+
+func TestCollector_collectHistories(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	configs.IntegrationJobTTL = 120
+
+	successfulLimit := int32(1)
+	failedLimit := int32(2)
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			IJManageSpec: cicdv1.IntegrationJobManageSpec{
+				SuccessfulJobsHistoryLimit: &successfulLimit,
+				FailedJobsHistoryLimit:     &failedLimit,
+			},
+		},
+	}
+
+	newJob := func(name, branch string, state cicdv1.IntegrationJobState, completedBefore time.Duration) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: cicdv1.GitRef("refs/heads/" + branch)},
+				},
+			},
+			Status: cicdv1.IntegrationJobStatus{
+				State:          state,
+				CompletionTime: &metav1.Time{Time: time.Now().Add(-completedBefore)},
+			},
+		}
+	}
+
+	c := &collector{client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		ic,
+		newJob("master-success-1", "master", cicdv1.IntegrationJobStateCompleted, 3*time.Hour),
+		newJob("master-success-2", "master", cicdv1.IntegrationJobStateCompleted, 2*time.Hour),
+		newJob("master-failed-1", "master", cicdv1.IntegrationJobStateFailed, 3*time.Hour),
+		newJob("master-failed-2", "master", cicdv1.IntegrationJobStateCancelled, 2*time.Hour),
+		newJob("master-failed-3", "master", cicdv1.IntegrationJobStateFailed, time.Hour),
+		newJob("dev-success-1", "dev", cicdv1.IntegrationJobStateCompleted, 3*time.Hour),
+	).Build()}
+
+	c.collect()
+
+	jobList := &cicdv1.IntegrationJobList{}
+	require.NoError(t, c.client.List(context.Background(), jobList))
+	var names []string
+	for _, j := range jobList.Items {
+		names = append(names, j.Name)
+	}
+	require.ElementsMatch(t, []string{"master-success-2", "master-failed-2", "master-failed-3", "dev-success-1"}, names)
+}
@@ -0,0 +1,165 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ownerKindIntegrationConfig = "IntegrationConfig"
+	ownerKindIntegrationJob    = "IntegrationJob"
+
+	orphanKindPipelineRun    = "PipelineRun"
+	orphanKindSecret         = "Secret"
+	orphanKindServiceAccount = "ServiceAccount"
+)
+
+var orphansDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cicd_orphan_resources_deleted_total",
+	Help: "Number of the resources deleted by the orphan audit, as their owner IntegrationConfigs or IntegrationJobs do not exist anymore",
+}, []string{"kind"})
+
+var orphanAuditRuns = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "cicd_orphan_audit_runs_total",
+	Help: "Number of the orphan audit runs",
+})
+
+func init() {
+	metrics.Registry.MustRegister(orphansDeleted, orphanAuditRuns)
+}
+
+// ownerKey identifies an owner object, including its UID, so a re-created (or renamed and re-created) owner with the
+// same name does not keep the resources of the old one alive
+type ownerKey struct {
+	kind      string
+	namespace string
+	name      string
+	uid       types.UID
+}
+
+// auditOrphans deletes PipelineRuns, Secrets and ServiceAccounts, which are owned by IntegrationConfigs or
+// IntegrationJobs not existing anymore
+func (c *collector) auditOrphans() {
+	log.Info("Orphan audit is running...")
+	orphanAuditRuns.Inc()
+
+	owners, err := c.listOwners()
+	if err != nil {
+		if _, ok := err.(*cache.ErrCacheNotStarted); !ok {
+			log.Error(err, "")
+		}
+		return
+	}
+
+	prList := &tektonv1beta1.PipelineRunList{}
+	if err := c.client.List(context.Background(), prList); err != nil {
+		log.Error(err, "")
+	}
+	for i := range prList.Items {
+		c.deleteIfOrphan(orphanKindPipelineRun, &prList.Items[i], owners)
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := c.client.List(context.Background(), secretList); err != nil {
+		log.Error(err, "")
+	}
+	for i := range secretList.Items {
+		c.deleteIfOrphan(orphanKindSecret, &secretList.Items[i], owners)
+	}
+
+	saList := &corev1.ServiceAccountList{}
+	if err := c.client.List(context.Background(), saList); err != nil {
+		log.Error(err, "")
+	}
+	for i := range saList.Items {
+		c.deleteIfOrphan(orphanKindServiceAccount, &saList.Items[i], owners)
+	}
+}
+
+// listOwners lists all the existing IntegrationConfigs and IntegrationJobs
+func (c *collector) listOwners() (map[ownerKey]struct{}, error) {
+	owners := map[ownerKey]struct{}{}
+
+	icList := &cicdv1.IntegrationConfigList{}
+	if err := c.client.List(context.Background(), icList); err != nil {
+		return nil, err
+	}
+	for _, ic := range icList.Items {
+		owners[ownerKey{kind: ownerKindIntegrationConfig, namespace: ic.Namespace, name: ic.Name, uid: ic.UID}] = struct{}{}
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := c.client.List(context.Background(), ijList); err != nil {
+		return nil, err
+	}
+	for _, ij := range ijList.Items {
+		owners[ownerKey{kind: ownerKindIntegrationJob, namespace: ij.Namespace, name: ij.Name, uid: ij.UID}] = struct{}{}
+	}
+
+	return owners, nil
+}
+
+func (c *collector) deleteIfOrphan(kind string, obj client.Object, owners map[ownerKey]struct{}) {
+	if !isOrphan(obj, owners) {
+		return
+	}
+
+	log.Info(fmt.Sprintf("Deleting orphan %s %s/%s", kind, obj.GetNamespace(), obj.GetName()))
+	if err := c.client.Delete(context.Background(), obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		log.Error(err, "")
+		return
+	}
+	orphansDeleted.WithLabelValues(kind).Inc()
+}
+
+// isOrphan checks if the object is owned only by IntegrationConfigs/IntegrationJobs and none of them exists.
+// Objects having any other owner are left to the owner's lifecycle
+func isOrphan(obj client.Object, owners map[ownerKey]struct{}) bool {
+	found := false
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != cicdv1.GroupVersion.Group || (ref.Kind != ownerKindIntegrationConfig && ref.Kind != ownerKindIntegrationJob) {
+			return false
+		}
+		if _, exist := owners[ownerKey{kind: ref.Kind, namespace: obj.GetNamespace(), name: ref.Name, uid: ref.UID}]; exist {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func parseAuditPeriod() string {
+	if configs.OrphanAuditPeriod <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("@every %dm", configs.OrphanAuditPeriod)
+}
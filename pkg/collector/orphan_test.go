@@ -0,0 +1,136 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCollector_auditOrphans(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	ownedBy := func(kind, name string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: cicdv1.GroupVersion.String(), Kind: kind, Name: name, UID: uid}}
+	}
+	meta := func(name string, owners []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: owners}
+	}
+
+	c := &collector{client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&cicdv1.IntegrationConfig{ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default", UID: "ic-uid"}},
+		&cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default", UID: "ij-uid"}},
+		&tektonv1beta1.PipelineRun{ObjectMeta: meta("owned-pr", ownedBy("IntegrationJob", "test-ij", "ij-uid"))},
+		&tektonv1beta1.PipelineRun{ObjectMeta: meta("orphan-pr", ownedBy("IntegrationJob", "deleted-ij", "deleted-uid"))},
+		&tektonv1beta1.PipelineRun{ObjectMeta: meta("not-owned-pr", nil)},
+		&corev1.Secret{ObjectMeta: meta("owned-secret", ownedBy("IntegrationConfig", "test-ic", "ic-uid"))},
+		&corev1.Secret{ObjectMeta: meta("recreated-owner-secret", ownedBy("IntegrationConfig", "test-ic", "old-uid"))},
+		&corev1.Secret{ObjectMeta: meta("other-owner-secret", append(ownedBy("IntegrationConfig", "deleted-ic", "deleted-uid"),
+			metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "test-cm", UID: "cm-uid"}))},
+		&corev1.ServiceAccount{ObjectMeta: meta("owned-sa", ownedBy("IntegrationConfig", "test-ic", "ic-uid"))},
+		&corev1.ServiceAccount{ObjectMeta: meta("orphan-sa", ownedBy("IntegrationConfig", "deleted-ic", "deleted-uid"))},
+	).Build()}
+
+	prDeleted := testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindPipelineRun))
+	secretDeleted := testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindSecret))
+	saDeleted := testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindServiceAccount))
+
+	c.auditOrphans()
+
+	names := func(list client.ObjectList) []string {
+		require.NoError(t, c.client.List(context.Background(), list))
+		var names []string
+		switch l := list.(type) {
+		case *tektonv1beta1.PipelineRunList:
+			for _, i := range l.Items {
+				names = append(names, i.Name)
+			}
+		case *corev1.SecretList:
+			for _, i := range l.Items {
+				names = append(names, i.Name)
+			}
+		case *corev1.ServiceAccountList:
+			for _, i := range l.Items {
+				names = append(names, i.Name)
+			}
+		}
+		return names
+	}
+	require.ElementsMatch(t, []string{"owned-pr", "not-owned-pr"}, names(&tektonv1beta1.PipelineRunList{}))
+	require.ElementsMatch(t, []string{"owned-secret", "other-owner-secret"}, names(&corev1.SecretList{}))
+	require.ElementsMatch(t, []string{"owned-sa"}, names(&corev1.ServiceAccountList{}))
+
+	require.Equal(t, prDeleted+1, testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindPipelineRun)))
+	require.Equal(t, secretDeleted+1, testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindSecret)))
+	require.Equal(t, saDeleted+1, testutil.ToFloat64(orphansDeleted.WithLabelValues(orphanKindServiceAccount)))
+}
+
+func TestCollector_reconfigureAudit(t *testing.T) {
+	tc := map[string]struct {
+		period       int
+		newPeriod    int
+		expectedSpec string
+	}{
+		"enable": {
+			newPeriod:    30,
+			expectedSpec: "@every 30m",
+		},
+		"change": {
+			period:       60,
+			newPeriod:    30,
+			expectedSpec: "@every 30m",
+		},
+		"disable": {
+			period:       60,
+			expectedSpec: "",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.OrphanAuditPeriod = c.period
+			gc, err := New(fake.NewClientBuilder().Build())
+			require.NoError(t, err)
+
+			configs.OrphanAuditPeriod = c.newPeriod
+			require.NoError(t, gc.reconfigureAudit())
+			require.Equal(t, c.expectedSpec, gc.auditSpec)
+
+			expectedEntries := 1
+			if c.expectedSpec != "" {
+				expectedEntries = 2
+			}
+			require.Len(t, gc.cron.Entries(), expectedEntries)
+		})
+	}
+}
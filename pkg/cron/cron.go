@@ -110,8 +110,9 @@ func (c *Cron) addPeriodic(periodic cicdv1.Periodic) error {
 		return nil
 	}
 
+	cronSpec := periodic.GetCronSpec()
 	if job, exist := c.jobs[periodic.Job.Name]; exist {
-		if job.cronStr == periodic.Cron { // 같은 cron으로 등록된 job이 있으면
+		if job.cronStr == cronSpec { // 같은 cron으로 등록된 job이 있으면
 			return nil
 		}
 		// job updated, remove old entry
@@ -120,7 +121,7 @@ func (c *Cron) addPeriodic(periodic cicdv1.Periodic) error {
 		}
 	}
 
-	if err := c.addJob(periodic.Job.Name, periodic.Cron); err != nil {
+	if err := c.addJob(periodic.Job.Name, cronSpec); err != nil {
 		return err
 	}
 
@@ -128,8 +129,9 @@ func (c *Cron) addPeriodic(periodic cicdv1.Periodic) error {
 }
 
 // addJob adds a cron entry for a job to cronAgent
+// cron should be prefixed with the timezone, e.g., TZ=UTC @every 1m
 func (c *Cron) addJob(name, cron string) error {
-	id, err := c.cronAgent.AddFunc("TZ=UTC "+cron, func() {
+	id, err := c.cronAgent.AddFunc(cron, func() {
 		c.lock.Lock()
 		defer c.lock.Unlock()
 
@@ -145,7 +147,7 @@ func (c *Cron) addJob(name, cron string) error {
 		entryID: id,
 		cronStr: cron,
 		// try to kick of a periodic trigger right away
-		triggered: strings.HasPrefix(cron, "@every"),
+		triggered: strings.Contains(cron, "@every"),
 	}
 
 	c.logger.Info("Added new cron job", "name", name, "cron", cron)
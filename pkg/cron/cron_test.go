@@ -96,3 +96,51 @@ func TestSyncIntegrationConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncIntegrationConfigTimezone(t *testing.T) {
+	tc := map[string]struct {
+		timezone string
+
+		errorOccurs     bool
+		expectedCronStr string
+	}{
+		"default": {
+			expectedCronStr: "TZ=UTC 0 0 * * *",
+		},
+		"timezone": {
+			timezone:        "Asia/Seoul",
+			expectedCronStr: "TZ=Asia/Seoul 0 0 * * *",
+		},
+		"invalidTimezone": {
+			timezone:    "Invalid/Timezone",
+			errorOccurs: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cr := New()
+			err := cr.SyncIntegrationConfig(&v1.IntegrationConfig{
+				Spec: v1.IntegrationConfigSpec{
+					Jobs: v1.IntegrationConfigJobs{
+						Periodic: []v1.Periodic{
+							{
+								Job:      v1.Job{Container: corev1.Container{Name: "nightly"}},
+								Cron:     "0 0 * * *",
+								Timezone: c.timezone,
+							},
+						},
+					},
+				},
+			})
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.False(t, cr.HasJob("nightly"))
+			} else {
+				require.NoError(t, err)
+				require.True(t, cr.HasJob("nightly"))
+				require.Equal(t, c.expectedCronStr, cr.jobs["nightly"].cronStr)
+			}
+		})
+	}
+}
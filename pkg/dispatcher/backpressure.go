@@ -0,0 +1,104 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkBackpressure checks if the pending IntegrationJobs of the IntegrationConfig reach the threshold, for the new
+// preSubmit job. If they do, it returns the pending IntegrationJobs of the same pull request to be coalesced into the
+// job, or a reason why the job is rejected if there is nothing to be coalesced (or the action is reject)
+func checkBackpressure(cli client.Client, job *cicdv1.IntegrationJob, config *cicdv1.IntegrationConfig) ([]*cicdv1.IntegrationJob, string, error) {
+	backpressure := config.Spec.IJManageSpec.Backpressure
+	if backpressure == nil || backpressure.MaxPendingJobs <= 0 || job.Spec.ConfigRef.Type != cicdv1.JobTypePreSubmit {
+		return nil, "", nil
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := cli.List(context.Background(), ijList, client.InNamespace(job.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: job.Spec.ConfigRef.Name}); err != nil {
+		return nil, "", err
+	}
+
+	var pending, coalesced []*cicdv1.IntegrationJob
+	groupKey := job.GetGroupKey(cicdv1.ConcurrencyGroupPullRequest)
+	for i := range ijList.Items {
+		ij := &ijList.Items[i]
+		if !isPendingJob(ij) || ij.Name == job.Name {
+			continue
+		}
+		pending = append(pending, ij)
+		if ij.Spec.ConfigRef.Type == cicdv1.JobTypePreSubmit && ij.GetGroupKey(cicdv1.ConcurrencyGroupPullRequest) == groupKey {
+			coalesced = append(coalesced, ij)
+		}
+	}
+	if len(pending) < backpressure.MaxPendingJobs {
+		return nil, "", nil
+	}
+
+	if backpressure.GetAction() == cicdv1.BackpressureActionCoalesce && len(coalesced) > 0 {
+		log.Info(fmt.Sprintf("%d IntegrationJobs are pending for %s, coalescing %d of them into %s", len(pending), config.Name, len(coalesced), job.Name))
+		return coalesced, "", nil
+	}
+	return nil, fmt.Sprintf("Not triggered, as %d IntegrationJobs are pending (limit: %d). Try again later", len(pending), backpressure.MaxPendingJobs), nil
+}
+
+// isPendingJob checks if the IntegrationJob is waiting to be scheduled, and is not going to be cancelled
+func isPendingJob(ij *cicdv1.IntegrationJob) bool {
+	return (ij.Status.State == "" || ij.Status.State == cicdv1.IntegrationJobStatePending) &&
+		!ij.Spec.Cancelled &&
+		ij.Annotations[cicdv1.JobAnnotationSupersededBy] == ""
+}
+
+// coalesceJobs marks the pending IntegrationJobs superseded by the job, for the IntegrationJob controller to cancel them
+func coalesceJobs(cli client.Client, job *cicdv1.IntegrationJob, pending []*cicdv1.IntegrationJob) error {
+	for _, ij := range pending {
+		if err := markSuperseded(cli, ij, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRejectedStatuses sets error commit statuses for the jobs rejected by the backpressure, or an aggregated one if
+// the commit statuses are aggregated
+func setRejectedStatuses(gitCli git.Client, config *cicdv1.IntegrationConfig, sha string, jobs []cicdv1.Job, description string) {
+	if !canReportStatus(gitCli, config) {
+		return
+	}
+	contexts := []string{config.GetAggregateCommitStatusContext()}
+	if contexts[0] == "" {
+		contexts = nil
+		for _, j := range jobs {
+			contexts = append(contexts, j.Name)
+		}
+	}
+	for _, c := range contexts {
+		if err := gitCli.SetCommitStatus(sha, git.CommitStatus{
+			Context:     c,
+			State:       git.CommitStatusStateError,
+			Description: description,
+		}); err != nil {
+			log.Error(err, "cannot set rejected commit status", "context", c)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckBackpressure(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	pullJob := func(name string, id int, sha string, state cicdv1.IntegrationJobState) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "base"},
+					Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: id, Ref: "feat", Sha: sha}},
+				},
+			},
+			Status: cicdv1.IntegrationJobStatus{State: state},
+		}
+	}
+	cancelled := pullJob("cancelled", 1, "sha-c", cicdv1.IntegrationJobStatePending)
+	cancelled.Spec.Cancelled = true
+	existing := []client.Object{
+		pullJob("same-pr", 1, "sha-1", cicdv1.IntegrationJobStatePending),
+		pullJob("other-pr", 2, "sha-2", ""),
+		pullJob("running", 1, "sha-r", cicdv1.IntegrationJobStateRunning),
+		cancelled,
+	}
+
+	tc := map[string]struct {
+		backpressure *cicdv1.Backpressure
+		job          *cicdv1.IntegrationJob
+
+		expectedCoalesced []string
+		expectedReason    string
+	}{
+		"noBackpressure": {
+			job: pullJob("new", 1, "sha-3", ""),
+		},
+		"underThreshold": {
+			backpressure: &cicdv1.Backpressure{MaxPendingJobs: 3},
+			job:          pullJob("new", 1, "sha-3", ""),
+		},
+		"coalesce": {
+			backpressure:      &cicdv1.Backpressure{MaxPendingJobs: 2},
+			job:               pullJob("new", 1, "sha-3", ""),
+			expectedCoalesced: []string{"same-pr"},
+		},
+		"coalesceNothing": {
+			backpressure:   &cicdv1.Backpressure{MaxPendingJobs: 2},
+			job:            pullJob("new", 3, "sha-3", ""),
+			expectedReason: "Not triggered, as 2 IntegrationJobs are pending (limit: 2). Try again later",
+		},
+		"reject": {
+			backpressure:   &cicdv1.Backpressure{MaxPendingJobs: 2, Action: cicdv1.BackpressureActionReject},
+			job:            pullJob("new", 1, "sha-3", ""),
+			expectedReason: "Not triggered, as 2 IntegrationJobs are pending (limit: 2). Try again later",
+		},
+		"postSubmit": {
+			backpressure: &cicdv1.Backpressure{MaxPendingJobs: 1, Action: cicdv1.BackpressureActionReject},
+			job: &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "push", Namespace: "default"},
+				Spec:       cicdv1.IntegrationJobSpec{ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit}},
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing...).Build()
+			ic := &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+				Spec:       cicdv1.IntegrationConfigSpec{IJManageSpec: cicdv1.IntegrationJobManageSpec{Backpressure: c.backpressure}},
+			}
+
+			coalesced, reason, err := checkBackpressure(cli, c.job, ic)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedReason, reason)
+			var names []string
+			for _, ij := range coalesced {
+				names = append(names, ij.Name)
+			}
+			require.Equal(t, c.expectedCoalesced, names)
+		})
+	}
+}
+
+func TestSetRejectedStatuses(t *testing.T) {
+	jobs := []cicdv1.Job{{Container: corev1.Container{Name: "test-1"}}, {Container: corev1.Container{Name: "test-2"}}}
+
+	tc := map[string]struct {
+		aggregate *cicdv1.AggregateCommitStatus
+
+		expectedContexts []string
+	}{
+		"jobs": {
+			expectedContexts: []string{"test-1", "test-2"},
+		},
+		"aggregated": {
+			aggregate:        &cicdv1.AggregateCommitStatus{Context: "ci/all"},
+			expectedContexts: []string{"ci/all"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+				Spec: cicdv1.IntegrationConfigSpec{
+					Git:                   cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+					AggregateCommitStatus: c.aggregate,
+				},
+			}
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {CommitStatuses: map[string][]git.CommitStatus{}},
+			}
+			gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+			setRejectedStatuses(gitCli, ic, "sha", jobs, "Rejected")
+
+			var contexts []string
+			for _, s := range gitfake.Repos["test/repo"].CommitStatuses["sha"] {
+				require.Equal(t, git.CommitStatusStateError, s.State)
+				require.Equal(t, "Rejected", s.Description)
+				contexts = append(contexts, s.Context)
+			}
+			require.Equal(t, c.expectedContexts, contexts)
+		})
+	}
+}
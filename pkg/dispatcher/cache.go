@@ -0,0 +1,184 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"github.com/tmax-cloud/cicd-operator/pkg/pipelinemanager"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// skipCachedJobs removes the jobs which already succeeded for the same contents of their cache.paths from the job
+// Cached jobs are reported as successful commit statuses, and the jobs running after them do not wait for them
+func skipCachedJobs(cli client.Client, job *cicdv1.IntegrationJob, config *cicdv1.IntegrationConfig, gitCli git.Client) {
+	if !hasCacheableJob(job.Spec.Jobs) || !hasRealShas(job) {
+		return
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := cli.List(context.Background(), ijList, client.InNamespace(job.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: job.Spec.ConfigRef.Name}); err != nil {
+		log.Error(err, "cannot list IntegrationJobs for the cache")
+		return
+	}
+
+	var jobs []cicdv1.Job
+	for _, j := range job.Spec.Jobs {
+		if !isCacheable(&j) {
+			jobs = append(jobs, j)
+			continue
+		}
+		candidate := findCacheCandidate(ijList.Items, job, &j)
+		if candidate == nil || !cachePathsUnchanged(gitCli, candidate, job, j.Cache.Paths) {
+			jobs = append(jobs, j)
+			continue
+		}
+		log.Info(fmt.Sprintf("Job %s of %s is cached by IntegrationJob %s", j.Name, job.GetHeadSha(), candidate.Name))
+		setSkippedStatuses(gitCli, config, job.GetHeadSha(), []cicdv1.Job{j}, fmt.Sprintf("Cached, succeeded in IntegrationJob %s", candidate.Name))
+	}
+	job.Spec.Jobs = jobs
+}
+
+// hasCacheableJob checks if any of the jobs can be cached
+func hasCacheableJob(jobs []cicdv1.Job) bool {
+	for i := range jobs {
+		if isCacheable(&jobs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCacheable checks if the job has a cache and is not conditionally executed
+func isCacheable(j *cicdv1.Job) bool {
+	return j.Cache != nil && len(j.TektonWhen) == 0
+}
+
+// hasRealShas checks if the commits of the job are known, i.e., the job is not triggered manually without a sha
+func hasRealShas(job *cicdv1.IntegrationJob) bool {
+	for _, sha := range getCacheShas(job) {
+		if sha == "" || sha == git.FakeSha {
+			return false
+		}
+	}
+	return true
+}
+
+// getCacheShas returns the commits deciding the contents of the job's workspace, i.e., base sha (and head sha for preSubmit)
+func getCacheShas(job *cicdv1.IntegrationJob) []string {
+	shas := []string{job.Spec.Refs.Base.Sha}
+	if job.Spec.ConfigRef.Type == cicdv1.JobTypePreSubmit {
+		shas = append(shas, job.GetHeadSha())
+	}
+	return shas
+}
+
+// findCacheCandidate returns the latest IntegrationJob of the same repository in which the same job succeeded, or nil
+func findCacheCandidate(ijs []cicdv1.IntegrationJob, job *cicdv1.IntegrationJob, j *cicdv1.Job) *cicdv1.IntegrationJob {
+	key := getCacheKey(j, job.Spec.ParamConfig)
+	var candidate *cicdv1.IntegrationJob
+	var latest *metav1.Time
+	for i := range ijs {
+		ij := &ijs[i]
+		if ij.Name == job.Name || ij.Spec.ConfigRef.Type != job.Spec.ConfigRef.Type || !hasRealShas(ij) ||
+			!strings.EqualFold(ij.Spec.Refs.Repository, job.Spec.Refs.Repository) {
+			continue
+		}
+		spec := findJob(ij.Spec.Jobs, j.Name)
+		if spec == nil || getCacheKey(spec, ij.Spec.ParamConfig) != key {
+			continue
+		}
+		status := findJobStatus(ij.Status.Jobs, j.Name)
+		if status == nil || status.State != cicdv1.CommitStatusStateSuccess || status.Message == pipelinemanager.JobMessageSkipped || status.CompletionTime == nil {
+			continue
+		}
+		if latest == nil || latest.Before(status.CompletionTime) {
+			candidate = ij
+			latest = status.CompletionTime
+		}
+	}
+	return candidate
+}
+
+// getCacheKey returns a hash of the job's specification and the parameters, except for the conditions
+func getCacheKey(j *cicdv1.Job, params *cicdv1.ParameterConfig) string {
+	spec := j.DeepCopy()
+	spec.When = nil
+	spec.TektonWhen = nil
+	b, err := json.Marshal(struct {
+		Job    *cicdv1.Job             `json:"job"`
+		Params *cicdv1.ParameterConfig `json:"params"`
+	}{Job: spec, Params: params})
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// cachePathsUnchanged checks if no file matching the paths is changed between the commits of the candidate and the job
+// Commits are compared in both directions, as the comparison is done against their merge base
+func cachePathsUnchanged(gitCli git.Client, candidate, job *cicdv1.IntegrationJob, paths []string) bool {
+	candidateShas := getCacheShas(candidate)
+	for i, sha := range getCacheShas(job) {
+		if candidateShas[i] == sha {
+			continue
+		}
+		if gitCli == nil {
+			return false
+		}
+		for _, pair := range [][2]string{{candidateShas[i], sha}, {sha, candidateShas[i]}} {
+			diff, err := gitCli.CompareCommits(pair[0], pair[1])
+			if err != nil {
+				log.Error(err, "cannot compare commits for the cache")
+				return false
+			}
+			for _, c := range diff.Changes {
+				if matchAnyPath(c.Filename, paths) || (c.OldFilename != "" && matchAnyPath(c.OldFilename, paths)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// findJob returns the job of the name, or nil
+func findJob(jobs []cicdv1.Job, name string) *cicdv1.Job {
+	for i := range jobs {
+		if jobs[i].Name == name {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// findJobStatus returns the status of the job of the name, or nil
+func findJobStatus(statuses []cicdv1.JobStatus, name string) *cicdv1.JobStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
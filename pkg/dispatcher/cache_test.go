@@ -0,0 +1,159 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSkipCachedJobs(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec:       cicdv1.IntegrationConfigSpec{Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}}},
+	}
+
+	buildJob := func(script string) cicdv1.Job {
+		return cicdv1.Job{
+			Container: corev1.Container{Name: "build", Image: "golang:1.17"},
+			Script:    script,
+			Cache:     &cicdv1.JobCache{Paths: []string{"**/*.go", "go.mod"}},
+		}
+	}
+	lintJob := cicdv1.Job{Container: corev1.Container{Name: "lint", Image: "golangci-lint"}, Script: "golangci-lint run"}
+	pushJob := func(name, sha string, jobs ...cicdv1.Job) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+				Jobs:      jobs,
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/master", Sha: sha},
+				},
+			},
+		}
+	}
+	completedJob := func(name, sha string, state cicdv1.CommitStatusState, completion time.Time) *cicdv1.IntegrationJob {
+		ij := pushJob(name, sha, buildJob("go build ./..."), lintJob)
+		ij.Status.Jobs = []cicdv1.JobStatus{
+			{Name: "build", State: state, CompletionTime: &metav1.Time{Time: completion}},
+			{Name: "lint", State: cicdv1.CommitStatusStateSuccess, CompletionTime: &metav1.Time{Time: completion}},
+		}
+		return ij
+	}
+
+	now := time.Now()
+	candidates := []*cicdv1.IntegrationJob{
+		completedJob("succeeded", "1111111111", cicdv1.CommitStatusStateSuccess, now.Add(-time.Hour)),
+		completedJob("failed", "1111111111", cicdv1.CommitStatusStateFailure, now),
+	}
+	// The latest success is of another repository of the same IntegrationConfig, which should not be used
+	otherRepo := completedJob("other-repo", "5555555555", cicdv1.CommitStatusStateSuccess, now.Add(-time.Minute))
+	otherRepo.Spec.Refs.Repository = "test/other"
+	candidates = append(candidates, otherRepo)
+
+	cachedStatus := []git.CommitStatus{{Context: "build", State: git.CommitStatusStateSkipped, Description: "Cached, succeeded in IntegrationJob succeeded"}}
+	tc := map[string]struct {
+		sha    string
+		script string
+
+		expectedJobs     []string
+		expectedStatuses []git.CommitStatus
+	}{
+		"sameSha": {
+			sha:              "1111111111",
+			script:           "go build ./...",
+			expectedJobs:     []string{"lint"},
+			expectedStatuses: cachedStatus,
+		},
+		"unrelatedChanges": {
+			sha:              "2222222222",
+			script:           "go build ./...",
+			expectedJobs:     []string{"lint"},
+			expectedStatuses: cachedStatus,
+		},
+		"relatedChanges": {
+			sha:          "3333333333",
+			script:       "go build ./...",
+			expectedJobs: []string{"build", "lint"},
+		},
+		"compareError": {
+			sha:          "4444444444",
+			script:       "go build ./...",
+			expectedJobs: []string{"build", "lint"},
+		},
+		"specChanged": {
+			sha:          "1111111111",
+			script:       "go build -race ./...",
+			expectedJobs: []string{"build", "lint"},
+		},
+		"otherRepositorySha": {
+			sha:          "5555555555",
+			script:       "go build ./...",
+			expectedJobs: []string{"build", "lint"},
+		},
+		"fakeSha": {
+			sha:          git.FakeSha,
+			script:       "go build ./...",
+			expectedJobs: []string{"build", "lint"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {
+					CommitDiffs: map[string]*git.Diff{
+						"1111111111...2222222222": {Changes: []git.Change{{Filename: "docs/index.md"}}},
+						"2222222222...1111111111": {},
+						"1111111111...3333333333": {Changes: []git.Change{{Filename: "pkg/main.go"}}},
+						"3333333333...1111111111": {},
+					},
+					CommitStatuses: map[string][]git.CommitStatus{},
+				},
+			}
+
+			builder := fake.NewClientBuilder().WithScheme(s)
+			for _, ij := range candidates {
+				builder = builder.WithObjects(ij.DeepCopy())
+			}
+			job := pushJob("new", c.sha, buildJob(c.script), lintJob)
+			skipCachedJobs(builder.Build(), job, ic, &gitfake.Client{IntegrationConfig: ic})
+
+			var names []string
+			for _, j := range job.Spec.Jobs {
+				names = append(names, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, names)
+			require.Equal(t, c.expectedStatuses, gitfake.Repos["test/repo"].CommitStatuses[c.sha])
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigFileStatusContext is a commit status context for reporting the errors of the jobs config file
+const ConfigFileStatusContext = "cicd-config"
+
+// configFile is a content of the jobs config file
+type configFile struct {
+	PreSubmit  cicdv1.Jobs `json:"preSubmit,omitempty"`
+	PostSubmit cicdv1.Jobs `json:"postSubmit,omitempty"`
+}
+
+// LoadConfigFile loads the preSubmit and postSubmit jobs from the config file at the ref, if spec.jobs.configFile is set
+// It returns a copy of the IntegrationConfig having the loaded jobs, or the IntegrationConfig itself if it's not set
+func LoadConfigFile(config *cicdv1.IntegrationConfig, gitCli git.Client, ref string) (*cicdv1.IntegrationConfig, error) {
+	if config.Spec.Jobs.ConfigFile == nil {
+		return config, nil
+	}
+	path := config.Spec.Jobs.ConfigFile.GetPath()
+
+	raw, err := gitCli.GetFile(path, ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get %s: %s", path, err.Error())
+	}
+
+	file := &configFile{}
+	if err := yaml.UnmarshalStrict(raw, file); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %s", path, err.Error())
+	}
+
+	loaded := config.DeepCopy()
+	loaded.Spec.Jobs.PreSubmit = file.PreSubmit
+	loaded.Spec.Jobs.PostSubmit = file.PostSubmit
+	if err := validateLoadedJobs(loaded, config.Spec.Jobs.ConfigFile); err != nil {
+		return nil, fmt.Errorf("%s is invalid: %s", path, err.Error())
+	}
+	return loaded, nil
+}
+
+func validateLoadedJobs(config *cicdv1.IntegrationConfig, configFile *cicdv1.JobsConfigFile) error {
+	jobs := config.Spec.Jobs
+	if err := configFile.ValidateServiceAccounts(jobs.PreSubmit); err != nil {
+		return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+	}
+	if err := configFile.ValidateServiceAccounts(jobs.PostSubmit); err != nil {
+		return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+	}
+	if err := jobs.PreSubmit.Validate(); err != nil {
+		return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+	}
+	if err := jobs.PostSubmit.Validate(); err != nil {
+		return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+	}
+	if err := jobs.PreSubmit.ValidateWorkspaces(config.Spec.Workspaces); err != nil {
+		return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+	}
+	if err := jobs.PostSubmit.ValidateWorkspaces(config.Spec.Workspaces); err != nil {
+		return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+	}
+	if config.Spec.RemoteCluster != nil {
+		if err := jobs.PreSubmit.ValidateRemoteCluster(); err != nil {
+			return fmt.Errorf("preSubmit is invalid: %s", err.Error())
+		}
+		if err := jobs.PostSubmit.ValidateRemoteCluster(); err != nil {
+			return fmt.Errorf("postSubmit is invalid: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// loadConfigFile loads the jobs from the config file at the commit which triggered the webhook
+// If the file cannot be loaded, the error is reported to the commit as a failed commit status
+func loadConfigFile(webhook *git.Webhook, config *cicdv1.IntegrationConfig, gitCli git.Client) (*cicdv1.IntegrationConfig, error) {
+	var ref, sha string
+	switch {
+	case webhook.EventType == git.EventTypePullRequest && webhook.PullRequest != nil:
+		sha = webhook.PullRequest.Head.Sha
+	case webhook.EventType == git.EventTypePush && webhook.Push != nil:
+		sha = webhook.Push.Sha
+	case webhook.EventType == git.EventTypeRelease && webhook.Release != nil:
+		sha = webhook.Release.Sha
+		ref = webhook.Release.Tag
+	}
+	if sha != "" {
+		ref = sha
+	}
+
+	// Nothing to be tested, e.g., a branch is deleted
+	if ref == "" || ref == git.FakeSha {
+		loaded := config.DeepCopy()
+		loaded.Spec.Jobs.PreSubmit = nil
+		loaded.Spec.Jobs.PostSubmit = nil
+		return loaded, nil
+	}
+
+	loaded, err := LoadConfigFile(config, gitCli, ref)
+	if err != nil && sha != "" {
+		if err := gitCli.SetCommitStatus(sha, git.CommitStatus{
+			Context:     ConfigFileStatusContext,
+			State:       git.CommitStatusStateFailure,
+			Description: truncateDescription(err.Error()),
+		}); err != nil {
+			log.Error(err, "cannot set commit status for the config file")
+		}
+	}
+	return loaded, err
+}
+
+// truncateDescription truncates the commit status description, as the git servers limit its length
+func truncateDescription(desc string) string {
+	const maxLength = 140
+	if len(desc) > maxLength {
+		return desc[:maxLength]
+	}
+	return desc
+}
@@ -0,0 +1,290 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testConfigFile = `
+preSubmit:
+- name: lint
+  image: golangci/golangci-lint
+  script: golangci-lint run
+- name: test
+  image: golang:1.17
+  script: go test ./...
+  after:
+  - lint
+postSubmit:
+- name: build
+  image: golang:1.17
+  script: go build ./...
+`
+	testConfigFileInvalid = `
+preSubmit:
+- name: test
+  after:
+  - lint
+`
+	testConfigFileServiceAccount = `
+preSubmit:
+- name: test
+  image: golang:1.17
+  script: go test ./...
+postSubmit:
+- name: deploy
+  image: alpine
+  script: ./deploy.sh
+  serviceAccountName: deployer
+`
+	testConfigFileUnknownField = `
+preSubmit:
+- name: test
+  imag: golang:1.17
+`
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo": {
+			Files: map[string]string{
+				"1111111111:.cicd/config.yaml": testConfigFile,
+				"2222222222:.cicd/config.yaml": testConfigFileInvalid,
+				"3333333333:.cicd/config.yaml": testConfigFileUnknownField,
+				"4444444444:ci/jobs.yaml":      testConfigFile,
+				"5555555555:.cicd/config.yaml": "preSubmit: [",
+				"6666666666:.cicd/config.yaml": testConfigFileServiceAccount,
+			},
+		},
+	}
+
+	tc := map[string]struct {
+		configFile *cicdv1.JobsConfigFile
+		ref        string
+
+		errorOccurs  bool
+		errorMessage string
+		preSubmit    []string
+		postSubmit   []string
+	}{
+		"notSet": {
+			ref:       "1111111111",
+			preSubmit: []string{"spec-job"},
+		},
+		"default": {
+			configFile: &cicdv1.JobsConfigFile{},
+			ref:        "1111111111",
+			preSubmit:  []string{"lint", "test"},
+			postSubmit: []string{"build"},
+		},
+		"path": {
+			configFile: &cicdv1.JobsConfigFile{Path: "ci/jobs.yaml"},
+			ref:        "4444444444",
+			preSubmit:  []string{"lint", "test"},
+			postSubmit: []string{"build"},
+		},
+		"notFound": {
+			configFile:   &cicdv1.JobsConfigFile{},
+			ref:          "4444444444",
+			errorOccurs:  true,
+			errorMessage: "cannot get .cicd/config.yaml: 404 no such file (.cicd/config.yaml) at 4444444444",
+		},
+		"invalid": {
+			configFile:   &cicdv1.JobsConfigFile{},
+			ref:          "2222222222",
+			errorOccurs:  true,
+			errorMessage: ".cicd/config.yaml is invalid: preSubmit is invalid: job test cannot run after lint, which does not exist",
+		},
+		"serviceAccountNotAllowed": {
+			configFile:   &cicdv1.JobsConfigFile{AllowedServiceAccounts: []string{"builder"}},
+			ref:          "6666666666",
+			errorOccurs:  true,
+			errorMessage: ".cicd/config.yaml is invalid: postSubmit is invalid: job deploy cannot run with service account deployer, which is not in configFile.allowedServiceAccounts",
+		},
+		"serviceAccountAllowed": {
+			configFile: &cicdv1.JobsConfigFile{AllowedServiceAccounts: []string{"deployer"}},
+			ref:        "6666666666",
+			preSubmit:  []string{"test"},
+			postSubmit: []string{"deploy"},
+		},
+		"unknownField": {
+			configFile:   &cicdv1.JobsConfigFile{},
+			ref:          "3333333333",
+			errorOccurs:  true,
+			errorMessage: `cannot parse .cicd/config.yaml: error unmarshaling JSON: while decoding JSON: json: unknown field "imag"`,
+		},
+		"syntaxError": {
+			configFile:   &cicdv1.JobsConfigFile{},
+			ref:          "5555555555",
+			errorOccurs:  true,
+			errorMessage: "cannot parse .cicd/config.yaml: error converting YAML to JSON: yaml: line 1: did not find expected node content",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+				Spec: cicdv1.IntegrationConfigSpec{
+					Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo"},
+					Jobs: cicdv1.IntegrationConfigJobs{
+						PreSubmit:  []cicdv1.Job{{Container: corev1.Container{Name: "spec-job"}}},
+						ConfigFile: c.configFile,
+					},
+				},
+			}
+
+			loaded, err := LoadConfigFile(ic, &gitfake.Client{IntegrationConfig: ic}, c.ref)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			var preSubmit, postSubmit []string
+			for _, j := range loaded.Spec.Jobs.PreSubmit {
+				preSubmit = append(preSubmit, j.Name)
+			}
+			for _, j := range loaded.Spec.Jobs.PostSubmit {
+				postSubmit = append(postSubmit, j.Name)
+			}
+			require.Equal(t, c.preSubmit, preSubmit)
+			require.Equal(t, c.postSubmit, postSubmit)
+
+			// IntegrationConfig should not be modified
+			require.Len(t, ic.Spec.Jobs.PreSubmit, 1)
+		})
+	}
+}
+
+func TestDispatcher_Handle_configFile(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				ConfigFile: &cicdv1.JobsConfigFile{},
+			},
+		},
+	}
+
+	prWebhook := func(sha string) *git.Webhook {
+		return &git.Webhook{
+			EventType: git.EventTypePullRequest,
+			PullRequest: &git.PullRequest{
+				ID:     1,
+				Action: git.PullRequestActionOpen,
+				Base:   git.Base{Ref: "master", Sha: "0000000001"},
+				Head:   git.Head{Ref: "feat", Sha: sha},
+			},
+		}
+	}
+
+	tc := map[string]struct {
+		webhook *git.Webhook
+
+		errorOccurs    bool
+		expectedJobs   []string
+		expectedStatus *git.CommitStatus
+	}{
+		"pullRequest": {
+			webhook:      prWebhook("1111111111"),
+			expectedJobs: []string{"lint", "test"},
+		},
+		"push": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/master", Sha: "1111111111"},
+			},
+			expectedJobs: []string{"build"},
+		},
+		"pushDeleted": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/master", Sha: git.FakeSha},
+			},
+		},
+		"invalid": {
+			webhook:     prWebhook("2222222222"),
+			errorOccurs: true,
+			expectedStatus: &git.CommitStatus{
+				Context:     ConfigFileStatusContext,
+				State:       git.CommitStatusStateFailure,
+				Description: ".cicd/config.yaml is invalid: preSubmit is invalid: job test cannot run after lint, which does not exist",
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {
+					Files: map[string]string{
+						"1111111111:.cicd/config.yaml": testConfigFile,
+						"2222222222:.cicd/config.yaml": testConfigFileInvalid,
+					},
+					CommitStatuses: map[string][]git.CommitStatus{},
+				},
+			}
+
+			d := &Dispatcher{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()}
+			err := d.Handle(c.webhook, ic)
+			if c.errorOccurs {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, d.Client.List(context.Background(), ijList))
+			if c.expectedJobs == nil {
+				require.Empty(t, ijList.Items)
+			} else {
+				require.Len(t, ijList.Items, 1)
+				var jobs []string
+				for _, j := range ijList.Items[0].Spec.Jobs {
+					jobs = append(jobs, j.Name)
+				}
+				require.Equal(t, c.expectedJobs, jobs)
+			}
+
+			statuses := gitfake.Repos["test/repo"].CommitStatuses["2222222222"]
+			if c.expectedStatus == nil {
+				require.Empty(t, statuses)
+			} else {
+				require.Equal(t, []git.CommitStatus{*c.expectedStatus}, statuses)
+			}
+		})
+	}
+}
@@ -17,7 +17,6 @@
 package dispatcher
 
 import (
-	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -40,38 +39,133 @@ func (d *Dispatcher) Name() string {
 	return "dispatcher"
 }
 
-// Handle handles pull-request and push events
+// Handle handles pull-request, push, release and re-requested check run events
 func (d Dispatcher) Handle(webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	if webhook.EventType == git.EventTypeCheckRun && webhook.CheckRun != nil {
+		return d.rerunCheckRun(webhook, config)
+	}
+
 	var job *cicdv1.IntegrationJob
 	pr := webhook.PullRequest
 	push := webhook.Push
-	if pr == nil && push == nil {
-		return fmt.Errorf("pull request and push struct is nil")
+	release := webhook.Release
+	if pr == nil && push == nil && release == nil {
+		return fmt.Errorf("pull request, push and release struct is nil")
+	}
+
+	// Load jobs from the config file in the repository
+	if config.Spec.Jobs.ConfigFile != nil {
+		gitCli, err := utils.GetGitCli(config, d.Client)
+		if err != nil {
+			return err
+		}
+		config, err = loadConfigFile(webhook, config, gitCli)
+		if err != nil {
+			return err
+		}
 	}
 
 	if webhook.EventType == git.EventTypePullRequest && pr != nil {
-		if pr.Action == git.PullRequestActionOpen || pr.Action == git.PullRequestActionSynchronize || pr.Action == git.PullRequestActionReOpen {
+		if shouldTriggerPreSubmit(pr, config) {
 			prs := []git.PullRequest{*pr}
 			job = GeneratePreSubmit(prs, &webhook.Repo, &webhook.Sender, config)
 		}
 	} else if webhook.EventType == git.EventTypePush && push != nil {
 		job = GeneratePostSubmit(push, &webhook.Repo, &webhook.Sender, config)
+	} else if webhook.EventType == git.EventTypeRelease && release != nil {
+		job = GenerateRelease(release, &webhook.Repo, &webhook.Sender, config)
 	}
 
 	if job == nil {
 		return nil
 	}
 
-	if err := d.Client.Create(context.Background(), job); err != nil {
+	// The git client is only used to report the skipped jobs and to list the changed files, so the jobs are still
+	// dispatched if it cannot be initialized
+	gitCli, err := utils.GetGitCli(config, d.Client)
+	if err != nil {
+		log.Error(err, "cannot initialize git client")
+		gitCli = nil
+	}
+
+	if checkSkipDirective(job, webhook, config, gitCli) {
+		return nil
+	}
+
+	if err := dropDuplicateJobs(d.Client, job); err != nil {
 		return err
 	}
+	if len(job.Spec.Jobs) < 1 {
+		return nil
+	}
+
+	getChangedFiles := newChangedFilesGetter(webhook, gitCli)
+	filterPaths(job, webhook, config, gitCli, getChangedFiles)
+	evaluateExpressions(job, webhook, getChangedFiles)
+	skipCachedJobs(d.Client, job, config, gitCli)
+	if len(job.Spec.Jobs) < 1 {
+		setAllSkippedStatus(gitCli, config, job.GetHeadSha(), allSkippedDescription)
+		return nil
+	}
+
+	// Coalesce or reject the preSubmit job if too many IntegrationJobs are pending
+	coalesced, rejectReason, err := checkBackpressure(d.Client, job, config)
+	if err != nil {
+		return err
+	}
+	if rejectReason != "" {
+		setRejectedStatuses(gitCli, config, job.GetHeadSha(), job.Spec.Jobs, rejectReason)
+		return nil
+	}
+
+	created, err := createDeduplicated(d.Client, job)
+	if err != nil || !created {
+		return err
+	}
+
+	if err := coalesceJobs(d.Client, job, coalesced); err != nil {
+		return err
+	}
+
+	if config.Spec.CancelSuperseded {
+		if err := cancelSupersededJobs(d.Client, job); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// shouldTriggerPreSubmit decides if the pull request event should trigger the preSubmit jobs
+// If spec.git.skipDraftPR is set, draft pull requests are not tested until they are marked as ready for review
+func shouldTriggerPreSubmit(pr *git.PullRequest, config *cicdv1.IntegrationConfig) bool {
+	skipDraft := config.Spec.Git.SkipDraftPR
+	switch pr.Action {
+	case git.PullRequestActionOpen, git.PullRequestActionSynchronize, git.PullRequestActionReOpen:
+		return !skipDraft || !pr.Draft
+	case git.PullRequestActionReady:
+		return skipDraft
+	}
+	return false
+}
+
 // GeneratePreSubmit generates IntegrationJob for pull request event
+// Manual jobs are not included, as they're triggered only by '/test <job>' comment command
 func GeneratePreSubmit(prs []git.PullRequest, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig) *cicdv1.IntegrationJob {
+	return generatePreSubmit(prs, repo, sender, config, false)
+}
+
+// GeneratePreSubmitWithManualJobs generates IntegrationJob for pull request event, including the manual jobs
+// The jobs should be filtered to the wanted ones afterwards
+func GeneratePreSubmitWithManualJobs(prs []git.PullRequest, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig) *cicdv1.IntegrationJob {
+	return generatePreSubmit(prs, repo, sender, config, true)
+}
+
+func generatePreSubmit(prs []git.PullRequest, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig, includeManual bool) *cicdv1.IntegrationJob {
 	jobs := FilterJobs(config.Spec.Jobs.PreSubmit, git.EventTypePullRequest, prs[0].Base.Ref)
+	if !includeManual {
+		jobs = filterManualJobs(jobs)
+	}
 	if len(jobs) < 1 {
 		return nil
 	}
@@ -83,6 +177,13 @@ func GeneratePreSubmit(prs []git.PullRequest, repo *git.Repository, sender *git.
 		ijName = prs[0].Head.Sha // only one PR Exists
 	}
 
+	webhook := &git.Webhook{
+		EventType:   git.EventTypePullRequest,
+		Repo:        *repo,
+		Sender:      *sender,
+		PullRequest: &prs[0],
+	}
+
 	jobID := utils.RandomString(20)
 	return &cicdv1.IntegrationJob{
 		ObjectMeta: generateMeta(config.Name, config.Namespace, ijName, jobID),
@@ -108,9 +209,18 @@ func GeneratePreSubmit(prs []git.PullRequest, repo *git.Repository, sender *git.
 				},
 				Pulls: generatePulls(prs),
 			},
-			PodTemplate: config.Spec.PodTemplate,
-			Timeout:     config.GetDuration(),
-			ParamConfig: config.Spec.ParamConfig,
+			PodTemplate:      config.Spec.PodTemplate,
+			Env:              config.Spec.Env,
+			VolumeMounts:     config.Spec.VolumeMounts,
+			SecurityContext:  config.Spec.SecurityContext,
+			Checkout:         config.Spec.Checkout,
+			Parallelism:      config.Spec.Parallelism,
+			RemoteCluster:    config.Spec.RemoteCluster,
+			GangScheduling:   config.Spec.IJManageSpec.GangScheduling,
+			Timeout:          config.GetDuration(),
+			TTLAfterFinished: config.Spec.IJManageSpec.TTLAfterFinished,
+			ParamConfig:      renderParamConfig(config.Spec.ParamConfig, webhook),
+			Priority:         evaluatePriority(config, webhook),
 		},
 	}
 }
@@ -118,6 +228,29 @@ func GeneratePreSubmit(prs []git.PullRequest, repo *git.Repository, sender *git.
 // GeneratePostSubmit generates IntegrationJob for push event
 func GeneratePostSubmit(push *git.Push, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig) *cicdv1.IntegrationJob {
 	jobs := FilterJobs(config.Spec.Jobs.PostSubmit, git.EventTypePush, push.Ref)
+	return generatePostSubmit(jobs, push, repo, sender, config, &git.Webhook{
+		EventType: git.EventTypePush,
+		Repo:      *repo,
+		Sender:    *sender,
+		Push:      push,
+	})
+}
+
+// GenerateRelease generates IntegrationJob for release event
+// Only the postSubmit jobs with when.release are triggered, to prevent the tag push event and the release event
+// triggering the same job twice
+func GenerateRelease(release *git.Release, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig) *cicdv1.IntegrationJob {
+	jobs := FilterJobs(config.Spec.Jobs.PostSubmit, git.EventTypeRelease, release.Tag)
+	push := &git.Push{Ref: "refs/tags/" + release.Tag, Sha: release.Sha}
+	return generatePostSubmit(jobs, push, repo, sender, config, &git.Webhook{
+		EventType: git.EventTypeRelease,
+		Repo:      *repo,
+		Sender:    *sender,
+		Release:   release,
+	})
+}
+
+func generatePostSubmit(jobs []cicdv1.Job, push *git.Push, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig, webhook *git.Webhook) *cicdv1.IntegrationJob {
 	if len(jobs) < 1 {
 		return nil
 	}
@@ -145,9 +278,18 @@ func GeneratePostSubmit(push *git.Push, repo *git.Repository, sender *git.User,
 					Sha:  push.Sha,
 				},
 			},
-			PodTemplate: config.Spec.PodTemplate,
-			Timeout:     config.GetDuration(),
-			ParamConfig: config.Spec.ParamConfig,
+			PodTemplate:      config.Spec.PodTemplate,
+			Env:              config.Spec.Env,
+			VolumeMounts:     config.Spec.VolumeMounts,
+			SecurityContext:  config.Spec.SecurityContext,
+			Checkout:         config.Spec.Checkout,
+			Parallelism:      config.Spec.Parallelism,
+			RemoteCluster:    config.Spec.RemoteCluster,
+			GangScheduling:   config.Spec.IJManageSpec.GangScheduling,
+			Timeout:          config.GetDuration(),
+			TTLAfterFinished: config.Spec.IJManageSpec.TTLAfterFinished,
+			ParamConfig:      renderParamConfig(config.Spec.ParamConfig, webhook),
+			Priority:         evaluatePriority(config, webhook),
 		},
 	}
 }
@@ -184,26 +326,73 @@ func generatePull(pr git.PullRequest) cicdv1.IntegrationJobRefsPull {
 	}
 }
 
-// FilterJobs filters job depending on the events, and ref
+// FilterJobs filters job depending on the events, and ref, and expands the matrix jobs
+// ref can be either a full reference (e.g., refs/heads/master) or a short name (e.g., master) of the branch, so that
+// the branch filters are evaluated in the same way for both pre-submit and post-submit jobs
 func FilterJobs(cand []cicdv1.Job, evType git.EventType, ref string) []cicdv1.Job {
 	var filteredJobs []cicdv1.Job
 	var incomingBranch string
 	var incomingTag string
+	var incomingRelease string
 
 	switch evType {
 	case git.EventTypePullRequest:
-		incomingBranch = ref
+		incomingBranch = strings.TrimPrefix(ref, "refs/heads/")
 	case git.EventTypePush:
-		if strings.Contains(ref, "refs/tags/") {
-			incomingTag = strings.Replace(ref, "refs/tags/", "", -1)
+		if strings.HasPrefix(ref, "refs/tags/") {
+			incomingTag = strings.TrimPrefix(ref, "refs/tags/")
 		} else {
-			incomingBranch = strings.Replace(ref, "refs/heads/", "", -1)
+			incomingBranch = strings.TrimPrefix(ref, "refs/heads/")
 		}
+	case git.EventTypeRelease:
+		incomingRelease = ref
+	}
+
+	//release events
+	filteredJobs = filterReleases(cand, incomingRelease)
+	if incomingRelease == "" {
+		//tag push events
+		filteredJobs = filterTags(filteredJobs, incomingTag)
+		filteredJobs = filterBranches(filteredJobs, incomingBranch)
 	}
 
-	//tag push events
-	filteredJobs = filterTags(cand, incomingTag)
-	filteredJobs = filterBranches(filteredJobs, incomingBranch)
+	// Each combination of the matrix jobs runs as a separate job
+	jobs := cicdv1.Jobs(filteredJobs)
+	return jobs.ExpandMatrix()
+}
+
+// filterManualJobs filters out the manual jobs
+func filterManualJobs(jobs []cicdv1.Job) []cicdv1.Job {
+	var filteredJobs []cicdv1.Job
+	for _, j := range jobs {
+		if !j.Manual {
+			filteredJobs = append(filteredJobs, j)
+		}
+	}
+	return filteredJobs
+}
+
+// filterReleases filters jobs for the release events.
+// Jobs with when.release are only triggered by the matching release events, and the others are never triggered by release events.
+func filterReleases(jobs []cicdv1.Job, incomingRelease string) []cicdv1.Job {
+	var filteredJobs []cicdv1.Job
+
+	for _, job := range jobs {
+		if job.When == nil || job.When.Release == nil {
+			if incomingRelease == "" {
+				filteredJobs = append(filteredJobs, job)
+			}
+			continue
+		}
+
+		if incomingRelease == "" {
+			continue
+		}
+
+		if matchAny(incomingRelease, job.When.Release) {
+			filteredJobs = append(filteredJobs, job)
+		}
+	}
 	return filteredJobs
 }
 
@@ -215,36 +404,19 @@ func filterTags(jobs []cicdv1.Job, incomingTag string) []cicdv1.Job {
 			filteredJobs = append(filteredJobs, job)
 			continue
 		}
-		tags := job.When.Tag
-		skipTags := job.When.SkipTag
 
 		// Always run if no tag/skipTag is specified
-		if tags == nil && skipTags == nil {
+		if job.When.Tag == nil && job.When.SkipTag == nil {
 			filteredJobs = append(filteredJobs, job)
+			continue
 		}
 
 		if incomingTag == "" {
 			continue
 		}
 
-		if tags != nil && skipTags == nil {
-			for _, tag := range tags {
-				if match := matchString(incomingTag, tag); match {
-					filteredJobs = append(filteredJobs, job)
-					break
-				}
-			}
-		} else if skipTags != nil && tags == nil {
-			isInValidJob := false
-			for _, tag := range skipTags {
-				if match := matchString(incomingTag, tag); match {
-					isInValidJob = true
-					break
-				}
-			}
-			if !isInValidJob {
-				filteredJobs = append(filteredJobs, job)
-			}
+		if matchIncludeExclude(incomingTag, job.When.Tag, job.When.SkipTag) {
+			filteredJobs = append(filteredJobs, job)
 		}
 	}
 	return filteredJobs
@@ -258,41 +430,41 @@ func filterBranches(jobs []cicdv1.Job, incomingBranch string) []cicdv1.Job {
 			filteredJobs = append(filteredJobs, job)
 			continue
 		}
-		branches := job.When.Branch
-		skipBranches := job.When.SkipBranch
 
 		// Always run if no branch/skipBranch is specified
-		if branches == nil && skipBranches == nil {
+		if job.When.Branch == nil && job.When.SkipBranch == nil {
 			filteredJobs = append(filteredJobs, job)
+			continue
 		}
 
 		if incomingBranch == "" {
 			continue
 		}
 
-		if branches != nil && skipBranches == nil {
-			for _, branch := range branches {
-				if match := matchString(incomingBranch, branch); match {
-					filteredJobs = append(filteredJobs, job)
-					break
-				}
-			}
-		} else if skipBranches != nil && branches == nil {
-			isInValidJob := false
-			for _, branch := range skipBranches {
-				if match := matchString(incomingBranch, branch); match {
-					isInValidJob = true
-					break
-				}
-			}
-			if !isInValidJob {
-				filteredJobs = append(filteredJobs, job)
-			}
+		if matchIncludeExclude(incomingBranch, job.When.Branch, job.When.SkipBranch) {
+			filteredJobs = append(filteredJobs, job)
 		}
 	}
 	return filteredJobs
 }
 
+// matchIncludeExclude decides if incoming matches any of includes (or includes is empty) and does not match any of excludes
+func matchIncludeExclude(incoming string, includes, excludes []string) bool {
+	if len(includes) > 0 && !matchAny(incoming, includes) {
+		return false
+	}
+	return !matchAny(incoming, excludes)
+}
+
+func matchAny(incoming string, targets []string) bool {
+	for _, target := range targets {
+		if matchString(incoming, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func matchString(incoming, target string) bool {
 	re, err := regexp.Compile(target)
 	if err != nil {
@@ -23,8 +23,38 @@ import (
 	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
 )
 
+func TestShouldTriggerPreSubmit(t *testing.T) {
+	tc := map[string]struct {
+		action      git.PullRequestAction
+		draft       bool
+		skipDraftPR bool
+
+		expected bool
+	}{
+		"open":                 {action: git.PullRequestActionOpen, expected: true},
+		"synchronize":          {action: git.PullRequestActionSynchronize, expected: true},
+		"reopen":               {action: git.PullRequestActionReOpen, expected: true},
+		"labeled":              {action: git.PullRequestActionLabeled, expected: false},
+		"openDraft":            {action: git.PullRequestActionOpen, draft: true, expected: true},
+		"openDraftSkip":        {action: git.PullRequestActionOpen, draft: true, skipDraftPR: true, expected: false},
+		"synchronizeDraftSkip": {action: git.PullRequestActionSynchronize, draft: true, skipDraftPR: true, expected: false},
+		"openSkip":             {action: git.PullRequestActionOpen, skipDraftPR: true, expected: true},
+		"ready":                {action: git.PullRequestActionReady, expected: false},
+		"readySkip":            {action: git.PullRequestActionReady, skipDraftPR: true, expected: true},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			pr := &git.PullRequest{Action: c.action, Draft: c.draft}
+			config := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{Git: cicdv1.GitConfig{SkipDraftPR: c.skipDraftPR}}}
+			require.Equal(t, c.expected, shouldTriggerPreSubmit(pr, config))
+		})
+	}
+}
+
 func TestGeneratePreSubmit(t *testing.T) {
 	tc := map[string]struct {
 		prs    []git.PullRequest
@@ -32,8 +62,9 @@ func TestGeneratePreSubmit(t *testing.T) {
 		sender *git.User
 		config *cicdv1.IntegrationConfig
 
-		expectedNil  bool
-		expectedName string
+		expectedNil            bool
+		expectedName           string
+		expectedJobsWithManual []string
 	}{
 		"noPreSubmitJobs": {
 			prs: []git.PullRequest{
@@ -113,6 +144,32 @@ func TestGeneratePreSubmit(t *testing.T) {
 			expectedName: "0kokp",
 			expectedNil:  false,
 		},
+		"manualJobs": {
+			prs: []git.PullRequest{
+				{
+					Head: git.Head{
+						Sha: "0kokpenadiugpowkqe0qlemaogor",
+					},
+					Base: git.Base{
+						Ref: "test",
+					},
+				},
+			},
+			repo:   &git.Repository{},
+			sender: &git.User{},
+			config: &cicdv1.IntegrationConfig{
+				Spec: cicdv1.IntegrationConfigSpec{
+					Jobs: cicdv1.IntegrationConfigJobs{
+						PreSubmit: cicdv1.Jobs{
+							cicdv1.Job{Container: corev1.Container{Name: "test-e2e"}, Manual: true},
+						},
+					},
+				},
+			},
+
+			expectedNil:            true,
+			expectedJobsWithManual: []string{"test-e2e"},
+		},
 	}
 
 	for name, c := range tc {
@@ -123,6 +180,15 @@ func TestGeneratePreSubmit(t *testing.T) {
 			} else {
 				require.Contains(t, ij.Name, c.expectedName)
 			}
+
+			if c.expectedJobsWithManual != nil {
+				ij := GeneratePreSubmitWithManualJobs(c.prs, c.repo, c.sender, c.config)
+				var names []string
+				for _, j := range ij.Spec.Jobs {
+					names = append(names, j.Name)
+				}
+				require.Equal(t, c.expectedJobsWithManual, names)
+			}
 		})
 	}
 }
@@ -191,6 +257,171 @@ func TestGeneratePostSubmit(t *testing.T) {
 	}
 }
 
+func TestGenerateRelease(t *testing.T) {
+	tc := map[string]struct {
+		release *git.Release
+		config  *cicdv1.IntegrationConfig
+
+		expectedNil  bool
+		expectedName string
+		expectedRef  cicdv1.GitRef
+		expectedJobs []string
+	}{
+		"noReleaseJobs": {
+			release: &git.Release{Tag: "v0.1.0", Sha: "0kokpenadiugpowkqe0qlemaogor"},
+			config: &cicdv1.IntegrationConfig{
+				Spec: cicdv1.IntegrationConfigSpec{
+					Jobs: cicdv1.IntegrationConfigJobs{
+						PostSubmit: cicdv1.Jobs{
+							{Container: corev1.Container{Name: "always"}},
+							{Container: corev1.Container{Name: "tag"}, When: &cicdv1.JobWhen{Tag: []string{"v.*"}}},
+						},
+					},
+				},
+			},
+			expectedNil: true,
+		},
+		"existReleaseJobs": {
+			release: &git.Release{Tag: "v0.1.0", Sha: "0kokpenadiugpowkqe0qlemaogor"},
+			config: &cicdv1.IntegrationConfig{
+				Spec: cicdv1.IntegrationConfigSpec{
+					Jobs: cicdv1.IntegrationConfigJobs{
+						PostSubmit: cicdv1.Jobs{
+							{Container: corev1.Container{Name: "always"}},
+							{Container: corev1.Container{Name: "release"}, When: &cicdv1.JobWhen{Release: []string{"v.*"}}},
+							{Container: corev1.Container{Name: "release-rc"}, When: &cicdv1.JobWhen{Release: []string{".*-rc"}}},
+						},
+					},
+				},
+			},
+			expectedName: "0kokp",
+			expectedRef:  "refs/tags/v0.1.0",
+			expectedJobs: []string{"release"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := GenerateRelease(c.release, &git.Repository{}, &git.User{}, c.config)
+			if c.expectedNil {
+				require.Nil(t, ij)
+			} else {
+				require.Contains(t, ij.Name, c.expectedName)
+				require.Equal(t, c.expectedRef, ij.Spec.Refs.Base.Ref)
+				var jobs []string
+				for _, j := range ij.Spec.Jobs {
+					jobs = append(jobs, j.Name)
+				}
+				require.Equal(t, c.expectedJobs, jobs)
+			}
+		})
+	}
+}
+
+func TestFilterJobs(t *testing.T) {
+	cand := []cicdv1.Job{
+		{Container: corev1.Container{Name: "always"}},
+		{Container: corev1.Container{Name: "master"}, When: &cicdv1.JobWhen{Branch: []string{"master"}}},
+		{Container: corev1.Container{Name: "exact-master"}, When: &cicdv1.JobWhen{Branch: []string{"^master$"}}},
+		{Container: corev1.Container{Name: "tag"}, When: &cicdv1.JobWhen{Tag: []string{"v.*"}}},
+		{Container: corev1.Container{Name: "skip-tag"}, When: &cicdv1.JobWhen{SkipTag: []string{".*-rc"}}},
+		{Container: corev1.Container{Name: "release"}, When: &cicdv1.JobWhen{Release: []string{"v.*"}}},
+		{Container: corev1.Container{Name: "release-branch"}, When: &cicdv1.JobWhen{Branch: []string{"release/.*"}, SkipBranch: []string{"release/experimental-.*"}}},
+		{Container: corev1.Container{Name: "skip-feat"}, When: &cicdv1.JobWhen{SkipBranch: []string{"feat/.*"}}},
+	}
+
+	tc := map[string]struct {
+		evType git.EventType
+		ref    string
+
+		expectedJobs []string
+	}{
+		"pullRequest": {
+			evType:       git.EventTypePullRequest,
+			ref:          "master",
+			expectedJobs: []string{"always", "master", "exact-master", "skip-feat"},
+		},
+		"pullRequestFullRef": {
+			evType:       git.EventTypePullRequest,
+			ref:          "refs/heads/master",
+			expectedJobs: []string{"always", "master", "exact-master", "skip-feat"},
+		},
+		"pullRequestPartialMatch": {
+			evType:       git.EventTypePullRequest,
+			ref:          "master-old",
+			expectedJobs: []string{"always", "master", "skip-feat"},
+		},
+		"pullRequestReleaseBranch": {
+			evType:       git.EventTypePullRequest,
+			ref:          "release/v1.0",
+			expectedJobs: []string{"always", "release-branch", "skip-feat"},
+		},
+		"pullRequestExcludedReleaseBranch": {
+			evType:       git.EventTypePullRequest,
+			ref:          "release/experimental-v1.0",
+			expectedJobs: []string{"always", "skip-feat"},
+		},
+		"pullRequestSkippedBranch": {
+			evType:       git.EventTypePullRequest,
+			ref:          "feat/test",
+			expectedJobs: []string{"always"},
+		},
+		"branchPush": {
+			evType:       git.EventTypePush,
+			ref:          "refs/heads/master",
+			expectedJobs: []string{"always", "master", "exact-master", "skip-feat"},
+		},
+		"releaseBranchPush": {
+			evType:       git.EventTypePush,
+			ref:          "refs/heads/release/v1.0",
+			expectedJobs: []string{"always", "release-branch", "skip-feat"},
+		},
+		"excludedReleaseBranchPush": {
+			evType:       git.EventTypePush,
+			ref:          "refs/heads/release/experimental-v1.0",
+			expectedJobs: []string{"always", "skip-feat"},
+		},
+		"tagPush": {
+			evType:       git.EventTypePush,
+			ref:          "refs/tags/v0.1.0",
+			expectedJobs: []string{"always", "tag", "skip-tag"},
+		},
+		"skippedTagPush": {
+			evType:       git.EventTypePush,
+			ref:          "refs/tags/v0.1.0-rc",
+			expectedJobs: []string{"always", "tag"},
+		},
+		"release": {
+			evType:       git.EventTypeRelease,
+			ref:          "v0.1.0",
+			expectedJobs: []string{"release"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			var jobs []string
+			for _, j := range FilterJobs(cand, c.evType, c.ref) {
+				jobs = append(jobs, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, jobs)
+		})
+	}
+}
+
+func TestFilterJobs_matrix(t *testing.T) {
+	cand := []cicdv1.Job{
+		{Container: corev1.Container{Name: "test"}, Matrix: []cicdv1.MatrixParam{{Name: "go", Values: []string{"1.16", "1.17"}}}},
+		{Container: corev1.Container{Name: "release"}, When: &cicdv1.JobWhen{Branch: []string{"release-.*"}}, Matrix: []cicdv1.MatrixParam{{Name: "os", Values: []string{"linux", "darwin"}}}},
+	}
+
+	var names []string
+	for _, j := range FilterJobs(cand, git.EventTypePullRequest, "master") {
+		names = append(names, j.Name)
+	}
+	require.Equal(t, []string{"test-1-16", "test-1-17"}, names)
+}
+
 func TestGeneratePull(t *testing.T) {
 	pr := git.PullRequest{
 		ID:     30,
@@ -0,0 +1,163 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dropDuplicateJobs removes the jobs already triggered for the same commits within configs.DuplicateTriggerWindow from
+// the job, so that the duplicated webhook deliveries (e.g., synchronize and labeled events of a pull request, or the
+// redeliveries of the git server) are coalesced into a single IntegrationJob
+// Manually triggered IntegrationJobs without base or head sha are not coalesced. The jobs which the remaining jobs run
+// after are kept, not to break the dependencies
+func dropDuplicateJobs(cli client.Client, job *cicdv1.IntegrationJob) error {
+	if configs.DuplicateTriggerWindow <= 0 || !hasRealShas(job) {
+		return nil
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := cli.List(context.Background(), ijList, client.InNamespace(job.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: job.Spec.ConfigRef.Name}); err != nil {
+		return err
+	}
+
+	window := time.Duration(configs.DuplicateTriggerWindow) * time.Second
+	triggered := map[string]string{}
+	for i := range ijList.Items {
+		ij := &ijList.Items[i]
+		if !isDuplicate(ij, job) || time.Since(ij.CreationTimestamp.Time) > window {
+			continue
+		}
+		for _, j := range ij.Spec.Jobs {
+			triggered[j.Name] = ij.Name
+		}
+	}
+
+	keepDependencies(job.Spec.Jobs, triggered)
+
+	var jobs cicdv1.Jobs
+	for _, j := range job.Spec.Jobs {
+		if ijName, exist := triggered[j.Name]; exist {
+			log.Info(fmt.Sprintf("Job %s of %s is already triggered by IntegrationJob %s", j.Name, job.GetHeadSha(), ijName))
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	job.Spec.Jobs = jobs
+	return nil
+}
+
+// keepDependencies deletes the jobs which the jobs not dropped run after, transitively, from the dropped ones
+func keepDependencies(jobs cicdv1.Jobs, dropped map[string]string) {
+	byName := map[string]*cicdv1.Job{}
+	for i := range jobs {
+		byName[jobs[i].Name] = &jobs[i]
+	}
+
+	var keep func(j *cicdv1.Job)
+	keep = func(j *cicdv1.Job) {
+		for _, after := range j.After {
+			if _, isDropped := dropped[after]; !isDropped || byName[after] == nil {
+				continue
+			}
+			delete(dropped, after)
+			keep(byName[after])
+		}
+	}
+	for i := range jobs {
+		if _, isDropped := dropped[jobs[i].Name]; !isDropped {
+			keep(&jobs[i])
+		}
+	}
+}
+
+// createDeduplicated creates the IntegrationJob with a name derived from its commits and jobs, so that the duplicated
+// webhook deliveries handled at the same time, which cannot see each other's IntegrationJob in the cache yet, conflict
+// on the creation. It returns false if the same IntegrationJob is already created within configs.DuplicateTriggerWindow
+func createDeduplicated(cli client.Client, job *cicdv1.IntegrationJob) (bool, error) {
+	if configs.DuplicateTriggerWindow <= 0 || !hasRealShas(job) {
+		return true, cli.Create(context.Background(), job)
+	}
+
+	name := job.Name
+	job.Name = deduplicatedName(job)
+	err := cli.Create(context.Background(), job)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	// The existing IntegrationJob is just created if it is not in the cache yet
+	existing := &cicdv1.IntegrationJob{}
+	if err := cli.Get(context.Background(), types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, existing); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	} else if err != nil || isRecentDuplicate(existing, job) {
+		log.Info(fmt.Sprintf("Jobs of %s are already triggered by IntegrationJob %s", job.GetHeadSha(), job.Name))
+		return false, nil
+	}
+
+	// Name is taken by an old or a cancelled IntegrationJob
+	job.Name = name
+	return true, cli.Create(context.Background(), job)
+}
+
+// deduplicatedName replaces the random suffix of the IntegrationJob's name with a hash of its commits and jobs
+func deduplicatedName(job *cicdv1.IntegrationJob) string {
+	var names []string
+	for _, j := range job.Spec.Jobs {
+		names = append(names, j.Name)
+	}
+	sort.Strings(names)
+	key := strings.Join(append([]string{string(job.Spec.ConfigRef.Type), job.Spec.Refs.Base.Sha, job.GetHeadSha()}, names...), "/")
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+	return fmt.Sprintf("%s-%s", strings.TrimSuffix(job.Name, "-"+job.Spec.ID[:5]), hash[:5])
+}
+
+// isRecentDuplicate checks if the IntegrationJob is for the same commits as the job, is not cancelled or superseded, and
+// is created within configs.DuplicateTriggerWindow
+func isRecentDuplicate(ij, job *cicdv1.IntegrationJob) bool {
+	window := time.Duration(configs.DuplicateTriggerWindow) * time.Second
+	return ij.Spec.ConfigRef.Type == job.Spec.ConfigRef.Type &&
+		ij.Spec.Refs.Base.Sha == job.Spec.Refs.Base.Sha &&
+		ij.GetHeadSha() == job.GetHeadSha() &&
+		!ij.Spec.Cancelled &&
+		ij.Annotations[cicdv1.JobAnnotationSupersededBy] == "" &&
+		time.Since(ij.CreationTimestamp.Time) <= window
+}
+
+// isDuplicate checks if the IntegrationJob is for the same commits as the job, and is not cancelled or superseded
+func isDuplicate(ij, job *cicdv1.IntegrationJob) bool {
+	return ij.Name != job.Name &&
+		ij.Spec.ConfigRef.Type == job.Spec.ConfigRef.Type &&
+		ij.Spec.Refs.Base.Sha == job.Spec.Refs.Base.Sha &&
+		ij.GetHeadSha() == job.GetHeadSha() &&
+		!ij.Spec.Cancelled &&
+		ij.Annotations[cicdv1.JobAnnotationSupersededBy] == ""
+}
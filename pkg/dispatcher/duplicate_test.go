@@ -0,0 +1,202 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDropDuplicateJobs(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	pullJob := func(name, baseSha, headSha string, created time.Time, jobs ...string) *cicdv1.IntegrationJob {
+		ij := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.Time{Time: created}, Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: baseSha},
+					Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: 1, Ref: "feat", Sha: headSha}},
+				},
+			},
+		}
+		for _, j := range jobs {
+			ij.Spec.Jobs = append(ij.Spec.Jobs, cicdv1.Job{Container: corev1.Container{Name: j}})
+		}
+		return ij
+	}
+
+	now := time.Now()
+	cancelled := pullJob("cancelled", "base", "head-cancelled", now, "test", "lint")
+	cancelled.Spec.Cancelled = true
+
+	tc := map[string]struct {
+		window  int
+		baseSha string
+		headSha string
+		jobs    cicdv1.Jobs
+
+		expectedJobs []string
+	}{
+		"duplicate": {
+			window:       60,
+			baseSha:      "base",
+			headSha:      "head",
+			expectedJobs: []string{"lint"},
+		},
+		"dependencyKept": {
+			window:  60,
+			baseSha: "base",
+			headSha: "head",
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test"}},
+				{Container: corev1.Container{Name: "deploy"}, After: []string{"test"}},
+			},
+			expectedJobs: []string{"test", "deploy"},
+		},
+		"allDuplicate": {
+			window:  60,
+			baseSha: "base",
+			headSha: "head-all",
+		},
+		"differentBase": {
+			window:       60,
+			baseSha:      "new-base",
+			headSha:      "head",
+			expectedJobs: []string{"test", "lint"},
+		},
+		"outOfWindow": {
+			window:       60,
+			baseSha:      "base",
+			headSha:      "head-old",
+			expectedJobs: []string{"test", "lint"},
+		},
+		"cancelled": {
+			window:       60,
+			baseSha:      "base",
+			headSha:      "head-cancelled",
+			expectedJobs: []string{"test", "lint"},
+		},
+		"manual": {
+			window:       60,
+			baseSha:      git.FakeSha,
+			headSha:      "head-manual",
+			expectedJobs: []string{"test", "lint"},
+		},
+		"disabled": {
+			baseSha:      "base",
+			headSha:      "head",
+			expectedJobs: []string{"test", "lint"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.DuplicateTriggerWindow = c.window
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				pullJob("existing", "base", "head", now.Add(-10*time.Second), "test"),
+				pullJob("existing-all", "base", "head-all", now.Add(-10*time.Second), "test", "lint"),
+				pullJob("old", "base", "head-old", now.Add(-time.Hour), "test", "lint"),
+				pullJob("existing-manual", git.FakeSha, "head-manual", now, "test", "lint"),
+				cancelled,
+			).Build()
+
+			job := pullJob("new", c.baseSha, c.headSha, now, "test", "lint")
+			if c.jobs != nil {
+				job.Spec.Jobs = c.jobs
+			}
+			require.NoError(t, dropDuplicateJobs(cli, job))
+
+			var names []string
+			for _, j := range job.Spec.Jobs {
+				names = append(names, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, names)
+		})
+	}
+	configs.DuplicateTriggerWindow = 0
+}
+
+func TestCreateDeduplicated(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	newJob := func(id string) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ic-head0-" + id[:5], Namespace: "default"},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				ID:        id,
+				Jobs:      cicdv1.Jobs{{Container: corev1.Container{Name: "test"}}},
+				Refs: cicdv1.IntegrationJobRefs{
+					Base:  cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "base"},
+					Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Ref: "feat", Sha: "head0"}},
+				},
+			},
+		}
+	}
+
+	configs.DuplicateTriggerWindow = 60
+	defer func() { configs.DuplicateTriggerWindow = 0 }()
+
+	t.Run("concurrentDeliveries", func(t *testing.T) {
+		cli := fake.NewClientBuilder().WithScheme(s).Build()
+
+		first := newJob("aaaaaaaaaa")
+		first.CreationTimestamp = metav1.Time{Time: time.Now()}
+		created, err := createDeduplicated(cli, first)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, deduplicatedName(newJob("aaaaaaaaaa")), first.Name)
+
+		// Same commits and jobs, with another random id
+		created, err = createDeduplicated(cli, newJob("bbbbbbbbbb"))
+		require.NoError(t, err)
+		require.False(t, created)
+
+		ijList := &cicdv1.IntegrationJobList{}
+		require.NoError(t, cli.List(context.Background(), ijList))
+		require.Len(t, ijList.Items, 1)
+	})
+
+	t.Run("oldJob", func(t *testing.T) {
+		old := newJob("aaaaaaaaaa")
+		old.Name = deduplicatedName(old)
+		old.CreationTimestamp = metav1.Time{Time: time.Now().Add(-time.Hour)}
+		cli := fake.NewClientBuilder().WithScheme(s).WithObjects(old).Build()
+
+		job := newJob("bbbbbbbbbb")
+		created, err := createDeduplicated(cli, job)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, "test-ic-head0-bbbbb", job.Name)
+	})
+}
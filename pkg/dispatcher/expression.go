@@ -0,0 +1,123 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/expression"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// skippedByExpression is a Tekton WhenExpression which never holds
+// Jobs whose when.expression is evaluated to false are guarded by it, so that they are skipped by Tekton but still
+// reported as skipped jobs
+var skippedByExpression = tektonv1beta1.WhenExpression{
+	Input:    "false",
+	Operator: selection.In,
+	Values:   []string{"true"},
+}
+
+// evaluateExpressions evaluates the jobs' when.expression against the webhook
+// Jobs evaluated to false (or failed to be evaluated) are guarded by skippedByExpression
+func evaluateExpressions(job *cicdv1.IntegrationJob, webhook *git.Webhook, getChangedFiles changedFilesGetter) {
+	var vars expression.Variables
+	for i := range job.Spec.Jobs {
+		j := &job.Spec.Jobs[i]
+		if j.When == nil || j.When.Expression == "" {
+			continue
+		}
+
+		e, err := expression.Parse(j.When.Expression)
+		if err != nil {
+			log.Error(err, "cannot parse when.expression, skipping the job", "job", j.Name)
+			skipByExpression(j)
+			continue
+		}
+
+		if vars == nil {
+			vars = generateExpressionVariables(webhook)
+		}
+		if _, exist := vars[cicdv1.JobWhenVarChangedFiles]; !exist && usesVariable(e, cicdv1.JobWhenVarChangedFiles) {
+			vars[cicdv1.JobWhenVarChangedFiles] = -1
+			if changedFiles, ok := getChangedFiles(); ok {
+				vars[cicdv1.JobWhenVarChangedFiles] = len(changedFiles)
+			}
+		}
+
+		result, err := e.Evaluate(vars)
+		if err != nil {
+			log.Error(err, "cannot evaluate when.expression, skipping the job", "job", j.Name)
+		}
+		if !result {
+			skipByExpression(j)
+		}
+	}
+}
+
+// skipByExpression guards the job with skippedByExpression
+// TektonWhen is copied not to modify the IntegrationConfig's job sharing the same array
+func skipByExpression(j *cicdv1.Job) {
+	j.TektonWhen = append(append(tektonv1beta1.WhenExpressions{}, j.TektonWhen...), skippedByExpression)
+}
+
+// generateExpressionVariables generates variables for the when.expression, except for the changed files
+func generateExpressionVariables(webhook *git.Webhook) expression.Variables {
+	vars := expression.Variables{
+		cicdv1.JobWhenVarEvent:  string(webhook.EventType),
+		cicdv1.JobWhenVarBranch: "",
+		cicdv1.JobWhenVarTag:    "",
+		cicdv1.JobWhenVarAuthor: webhook.Sender.Name,
+		cicdv1.JobWhenVarLabels: []string{},
+	}
+
+	switch {
+	case webhook.EventType == git.EventTypePullRequest && webhook.PullRequest != nil:
+		pr := webhook.PullRequest
+		vars[cicdv1.JobWhenVarBranch] = strings.TrimPrefix(pr.Base.Ref, "refs/heads/")
+		vars[cicdv1.JobWhenVarAuthor] = pr.Author.Name
+		var labels []string
+		for _, l := range pr.Labels {
+			labels = append(labels, l.Name)
+		}
+		if labels != nil {
+			vars[cicdv1.JobWhenVarLabels] = labels
+		}
+	case webhook.EventType == git.EventTypePush && webhook.Push != nil:
+		if strings.HasPrefix(webhook.Push.Ref, "refs/tags/") {
+			vars[cicdv1.JobWhenVarTag] = strings.TrimPrefix(webhook.Push.Ref, "refs/tags/")
+		} else {
+			vars[cicdv1.JobWhenVarBranch] = strings.TrimPrefix(webhook.Push.Ref, "refs/heads/")
+		}
+	case webhook.EventType == git.EventTypeRelease && webhook.Release != nil:
+		vars[cicdv1.JobWhenVarTag] = webhook.Release.Tag
+	}
+
+	return vars
+}
+
+func usesVariable(e *expression.Expression, name string) bool {
+	for _, ident := range e.Identifiers() {
+		if ident == name {
+			return true
+		}
+	}
+	return false
+}
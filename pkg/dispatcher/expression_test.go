@@ -0,0 +1,184 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/expression"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateExpressionVariables(t *testing.T) {
+	tc := map[string]struct {
+		webhook *git.Webhook
+
+		expected expression.Variables
+	}{
+		"pullRequest": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePullRequest,
+				Sender:    git.User{Name: "sender"},
+				PullRequest: &git.PullRequest{
+					Author: git.User{Name: "author"},
+					Base:   git.Base{Ref: "master"},
+					Labels: []git.IssueLabel{{Name: "ok-to-test"}, {Name: "kind/bug"}},
+				},
+			},
+			expected: expression.Variables{"event": "pull_request", "branch": "master", "tag": "", "author": "author", "labels": []string{"ok-to-test", "kind/bug"}},
+		},
+		"push": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Sender:    git.User{Name: "sender"},
+				Push:      &git.Push{Ref: "refs/heads/release-1.0"},
+			},
+			expected: expression.Variables{"event": "push", "branch": "release-1.0", "tag": "", "author": "sender", "labels": []string{}},
+		},
+		"tag": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Sender:    git.User{Name: "sender"},
+				Push:      &git.Push{Ref: "refs/tags/v1.0.0"},
+			},
+			expected: expression.Variables{"event": "push", "branch": "", "tag": "v1.0.0", "author": "sender", "labels": []string{}},
+		},
+		"release": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypeRelease,
+				Sender:    git.User{Name: "sender"},
+				Release:   &git.Release{Tag: "v1.0.0"},
+			},
+			expected: expression.Variables{"event": "release", "branch": "", "tag": "v1.0.0", "author": "sender", "labels": []string{}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, generateExpressionVariables(c.webhook))
+		})
+	}
+}
+
+func TestDispatcher_Handle_expression(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "test"}},
+					{Container: corev1.Container{Name: "labeled"}, When: &cicdv1.JobWhen{Expression: `"ok-to-test" in labels`}},
+					{Container: corev1.Container{Name: "small"}, When: &cicdv1.JobWhen{Expression: `changedFiles >= 0 && changedFiles < 2`}},
+					{Container: corev1.Container{Name: "invalid"}, When: &cicdv1.JobWhen{Expression: `branch ==`}},
+				},
+				PostSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "small"}, When: &cicdv1.JobWhen{Expression: `changedFiles >= 0 && changedFiles < 2`}},
+				},
+			},
+		},
+	}
+
+	prWebhook := func(labels ...git.IssueLabel) *git.Webhook {
+		return &git.Webhook{
+			EventType: git.EventTypePullRequest,
+			PullRequest: &git.PullRequest{
+				ID:     1,
+				Action: git.PullRequestActionOpen,
+				Base:   git.Base{Ref: "master", Sha: "1111111111"},
+				Head:   git.Head{Ref: "feat", Sha: "2222222222"},
+				Labels: labels,
+			},
+		}
+	}
+
+	tc := map[string]struct {
+		webhook *git.Webhook
+
+		expectedSkippedJobs []string
+	}{
+		"pullRequest": {
+			webhook:             prWebhook(),
+			expectedSkippedJobs: []string{"labeled", "small", "invalid"},
+		},
+		"pullRequestLabeled": {
+			webhook:             prWebhook(git.IssueLabel{Name: "ok-to-test"}),
+			expectedSkippedJobs: []string{"small", "invalid"},
+		},
+		"push": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/master", Sha: "4444444444", Before: "3333333333"},
+			},
+		},
+		"pushNewBranch": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/new", Sha: "4444444444", Before: git.FakeSha},
+			},
+			expectedSkippedJobs: []string{"small"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {
+					PullRequestDiffs: map[int]*git.Diff{1: {Changes: []git.Change{{Filename: "main.go"}, {Filename: "docs/index.md"}}}},
+					CommitDiffs: map[string]*git.Diff{
+						"3333333333...4444444444": {Changes: []git.Change{{Filename: "docs/index.md"}}},
+					},
+					CommitStatuses: map[string][]git.CommitStatus{},
+				},
+			}
+
+			d := &Dispatcher{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()}
+			require.NoError(t, d.Handle(c.webhook, ic))
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, d.Client.List(context.Background(), ijList))
+			require.Len(t, ijList.Items, 1)
+
+			var skippedJobs []string
+			for _, j := range ijList.Items[0].Spec.Jobs {
+				if len(j.TektonWhen) > 0 {
+					require.Equal(t, tektonv1beta1.WhenExpressions{skippedByExpression}, j.TektonWhen)
+					skippedJobs = append(skippedJobs, j.Name)
+				}
+			}
+			require.Equal(t, c.expectedSkippedJobs, skippedJobs)
+
+			// IntegrationConfig should not be modified
+			for _, j := range ic.Spec.Jobs.PreSubmit {
+				require.Empty(t, j.TektonWhen)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("dispatcher")
+
+// ParamTemplateData is a structure used to render templates of parameter values
+// e.g., {{ .Webhook.PullRequest.Title }}
+type ParamTemplateData struct {
+	Webhook *git.Webhook
+}
+
+var paramTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"labels": func(labels []git.IssueLabel) []string {
+		var names []string
+		for _, l := range labels {
+			names = append(names, l.Name)
+		}
+		return names
+	},
+}
+
+// renderParamConfig renders parameter values of the paramConfig using the webhook
+// The original paramConfig is not modified
+func renderParamConfig(paramConfig *cicdv1.ParameterConfig, webhook *git.Webhook) *cicdv1.ParameterConfig {
+	if paramConfig == nil {
+		return nil
+	}
+
+	rendered := paramConfig.DeepCopy()
+	data := &ParamTemplateData{Webhook: webhook}
+	for i := range rendered.ParamValue {
+		v := &rendered.ParamValue[i]
+		v.StringVal = renderParamTemplate(v.StringVal, data)
+		for j := range v.ArrayVal {
+			v.ArrayVal[j] = renderParamTemplate(v.ArrayVal[j], data)
+		}
+	}
+	return rendered
+}
+
+// renderParamTemplate renders a single value. The raw value is returned if it cannot be rendered.
+func renderParamTemplate(value string, data *ParamTemplateData) string {
+	if !strings.Contains(value, "{{") {
+		return value
+	}
+
+	tmpl, err := template.New("").Funcs(paramTemplateFuncs).Parse(value)
+	if err != nil {
+		log.Info("cannot parse parameter template", "value", value, "error", err.Error())
+		return value
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		log.Info("cannot execute parameter template", "value", value, "error", err.Error())
+		return value
+	}
+	return buf.String()
+}
@@ -0,0 +1,90 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+func TestRenderParamConfig(t *testing.T) {
+	webhook := &git.Webhook{
+		EventType: git.EventTypePullRequest,
+		Sender:    git.User{Name: "sender", Email: "sender@tmax.co.kr"},
+		Push: &git.Push{
+			HeadCommit: git.Commit{Author: git.User{Name: "author", Email: "author@tmax.co.kr"}},
+		},
+		PullRequest: &git.PullRequest{
+			Title:  "Fix bug",
+			Labels: []git.IssueLabel{{Name: "kind/bug"}, {Name: "size/S"}},
+		},
+	}
+
+	tc := map[string]struct {
+		paramConfig *cicdv1.ParameterConfig
+
+		expectedParamConfig *cicdv1.ParameterConfig
+	}{
+		"nil": {},
+		"noTemplate": {
+			paramConfig:         &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{{Name: "p", StringVal: "static"}}},
+			expectedParamConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{{Name: "p", StringVal: "static"}}},
+		},
+		"stringVal": {
+			paramConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "title", StringVal: "{{ .Webhook.PullRequest.Title }}"},
+				{Name: "email", StringVal: "{{ .Webhook.Push.HeadCommit.Author.Email }}"},
+				{Name: "labels", StringVal: "{{ join (labels .Webhook.PullRequest.Labels) \",\" }}"},
+			}},
+			expectedParamConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "title", StringVal: "Fix bug"},
+				{Name: "email", StringVal: "author@tmax.co.kr"},
+				{Name: "labels", StringVal: "kind/bug,size/S"},
+			}},
+		},
+		"arrayVal": {
+			paramConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "users", ArrayVal: []string{"{{ .Webhook.Sender.Name }}", "{{ .Webhook.Sender.Email }}"}},
+			}},
+			expectedParamConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "users", ArrayVal: []string{"sender", "sender@tmax.co.kr"}},
+			}},
+		},
+		"malformed": {
+			paramConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "parse", StringVal: "{{ .Webhook.PullRequest.Title "},
+				{Name: "exec", StringVal: "{{ .Webhook.NoField }}"},
+			}},
+			expectedParamConfig: &cicdv1.ParameterConfig{ParamValue: []cicdv1.ParameterValue{
+				{Name: "parse", StringVal: "{{ .Webhook.PullRequest.Title "},
+				{Name: "exec", StringVal: "{{ .Webhook.NoField }}"},
+			}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			original := c.paramConfig.DeepCopy()
+			rendered := renderParamConfig(c.paramConfig, webhook)
+			require.Equal(t, c.expectedParamConfig, rendered)
+			require.Equal(t, original, c.paramConfig)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"regexp"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+const (
+	skippedPathsDescription = "Skipped, no changed files match the paths"
+	allSkippedDescription   = "All jobs are skipped"
+)
+
+// FilterJobsByPaths filters jobs depending on the changed files
+// Jobs without when.paths/when.skipPaths are always included
+func FilterJobsByPaths(jobs []cicdv1.Job, changedFiles []string) []cicdv1.Job {
+	var filteredJobs []cicdv1.Job
+	for _, job := range jobs {
+		if !hasPathFilter(job) {
+			filteredJobs = append(filteredJobs, job)
+			continue
+		}
+
+		for _, f := range changedFiles {
+			if (len(job.When.Paths) == 0 || matchAnyPath(f, job.When.Paths)) && !matchAnyPath(f, job.When.SkipPaths) {
+				filteredJobs = append(filteredJobs, job)
+				break
+			}
+		}
+	}
+	return filteredJobs
+}
+
+// filterPaths removes the jobs whose path filters do not match the changed files of the webhook
+// The jobs are kept as they are, if the changed files cannot be listed (e.g., for newly pushed branches or tags)
+func filterPaths(job *cicdv1.IntegrationJob, webhook *git.Webhook, config *cicdv1.IntegrationConfig, gitCli git.Client, getChangedFiles changedFilesGetter) {
+	hasFilter := false
+	for _, j := range job.Spec.Jobs {
+		if hasPathFilter(j) {
+			hasFilter = true
+			break
+		}
+	}
+	if !hasFilter {
+		return
+	}
+
+	changedFiles, ok := getChangedFiles()
+	if !ok {
+		return
+	}
+
+	filteredJobs := FilterJobsByPaths(job.Spec.Jobs, changedFiles)
+
+	// Set skipped status for the filtered pre-submit jobs, not to block the pull request waiting for them
+	if webhook.EventType == git.EventTypePullRequest {
+		filtered := map[string]struct{}{}
+		for _, j := range filteredJobs {
+			filtered[j.Name] = struct{}{}
+		}
+		var skippedJobs []cicdv1.Job
+		for _, j := range job.Spec.Jobs {
+			if _, exist := filtered[j.Name]; !exist {
+				skippedJobs = append(skippedJobs, j)
+			}
+		}
+		setSkippedStatuses(gitCli, config, webhook.PullRequest.Head.Sha, skippedJobs, skippedPathsDescription)
+	}
+
+	job.Spec.Jobs = filteredJobs
+}
+
+// changedFilesGetter returns the changed files of the webhook, and whether they could be listed or not
+type changedFilesGetter func() ([]string, bool)
+
+// newChangedFilesGetter returns a changedFilesGetter, which lists the changed files only once
+func newChangedFilesGetter(webhook *git.Webhook, gitCli git.Client) changedFilesGetter {
+	listed := false
+	var changedFiles []string
+	var ok bool
+	return func() ([]string, bool) {
+		if !listed {
+			changedFiles, ok = listChangedFiles(webhook, gitCli)
+			listed = true
+		}
+		return changedFiles, ok
+	}
+}
+
+// listChangedFiles lists the changed files of the pull request or the push event
+// It returns false if the changed files cannot be listed (e.g., for newly pushed branches or tags)
+func listChangedFiles(webhook *git.Webhook, gitCli git.Client) ([]string, bool) {
+	if gitCli == nil {
+		return nil, false
+	}
+	var diff *git.Diff
+	var err error
+	switch {
+	case webhook.EventType == git.EventTypePullRequest && webhook.PullRequest != nil:
+		diff, err = gitCli.GetPullRequestDiff(webhook.PullRequest.ID)
+	case webhook.EventType == git.EventTypePush && webhook.Push != nil:
+		push := webhook.Push
+		if push.Before == "" || push.Before == git.FakeSha || strings.HasPrefix(push.Ref, "refs/tags/") {
+			return nil, false
+		}
+		diff, err = gitCli.CompareCommits(push.Before, push.Sha)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		log.Error(err, "cannot get changed files")
+		return nil, false
+	}
+
+	var changedFiles []string
+	for _, c := range diff.Changes {
+		changedFiles = append(changedFiles, c.Filename)
+		if c.OldFilename != "" && c.OldFilename != c.Filename {
+			changedFiles = append(changedFiles, c.OldFilename)
+		}
+	}
+	return changedFiles, true
+}
+
+func hasPathFilter(job cicdv1.Job) bool {
+	return job.When != nil && (len(job.When.Paths) > 0 || len(job.When.SkipPaths) > 0)
+}
+
+func matchAnyPath(file string, patterns []string) bool {
+	for _, p := range patterns {
+		if matchPath(file, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath matches the file path with the glob pattern
+// '*' matches any sequence of non-separator characters, '?' matches any single non-separator character,
+// and '**' matches any sequence of characters including separators.
+// A pattern ending with '/' matches every file under the directory.
+func matchPath(file, pattern string) bool {
+	re, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(file)
+}
+
+func globToRegexp(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	b := strings.Builder{}
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,209 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatchPath(t *testing.T) {
+	tc := map[string]struct {
+		file    string
+		pattern string
+
+		expected bool
+	}{
+		"exact":                {file: "README.md", pattern: "README.md", expected: true},
+		"exactNotMatch":        {file: "docs/README.md", pattern: "README.md", expected: false},
+		"leadingSlash":         {file: "README.md", pattern: "/README.md", expected: true},
+		"star":                 {file: "docs/index.md", pattern: "docs/*.md", expected: true},
+		"starNotSeparator":     {file: "docs/sub/index.md", pattern: "docs/*.md", expected: false},
+		"doubleStar":           {file: "docs/sub/index.md", pattern: "docs/**", expected: true},
+		"doubleStarPrefix":     {file: "main.go", pattern: "**/*.go", expected: true},
+		"doubleStarPrefixDeep": {file: "pkg/a/b/main.go", pattern: "**/*.go", expected: true},
+		"doubleStarMiddle":     {file: "pkg/a/b/main_test.go", pattern: "pkg/**/*_test.go", expected: true},
+		"directory":            {file: "docs/sub/index.md", pattern: "docs/", expected: true},
+		"directoryNotMatch":    {file: "docsx/index.md", pattern: "docs/", expected: false},
+		"question":             {file: "v1.go", pattern: "v?.go", expected: true},
+		"questionNotSeparator": {file: "v/.go", pattern: "v?.go", expected: false},
+		"regexpChars":          {file: "a+b.txt", pattern: "a+b.txt", expected: true},
+		"regexpCharsNotMatch":  {file: "aab.txt", pattern: "a+b.txt", expected: false},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, matchPath(c.file, c.pattern))
+		})
+	}
+}
+
+func TestFilterJobsByPaths(t *testing.T) {
+	jobs := []cicdv1.Job{
+		{Container: corev1.Container{Name: "always"}},
+		{Container: corev1.Container{Name: "docs"}, When: &cicdv1.JobWhen{Paths: []string{"docs/**", "*.md"}}},
+		{Container: corev1.Container{Name: "code"}, When: &cicdv1.JobWhen{SkipPaths: []string{"docs/**", "*.md"}}},
+		{Container: corev1.Container{Name: "api"}, When: &cicdv1.JobWhen{Paths: []string{"api/**"}, SkipPaths: []string{"**/*_test.go"}}},
+	}
+
+	tc := map[string]struct {
+		changedFiles []string
+
+		expectedJobs []string
+	}{
+		"docsOnly": {
+			changedFiles: []string{"README.md", "docs/index.md"},
+			expectedJobs: []string{"always", "docs"},
+		},
+		"codeOnly": {
+			changedFiles: []string{"pkg/main.go"},
+			expectedJobs: []string{"always", "code"},
+		},
+		"docsAndCode": {
+			changedFiles: []string{"docs/index.md", "pkg/main.go"},
+			expectedJobs: []string{"always", "docs", "code"},
+		},
+		"apiTestOnly": {
+			changedFiles: []string{"api/v1/types_test.go"},
+			expectedJobs: []string{"always", "code"},
+		},
+		"api": {
+			changedFiles: []string{"api/v1/types.go", "api/v1/types_test.go"},
+			expectedJobs: []string{"always", "code", "api"},
+		},
+		"noChanges": {
+			expectedJobs: []string{"always"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			for _, j := range FilterJobsByPaths(jobs, c.changedFiles) {
+				names = append(names, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, names)
+		})
+	}
+}
+
+func TestDispatcher_Handle_paths(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "test"}},
+					{Container: corev1.Container{Name: "docs"}, When: &cicdv1.JobWhen{Paths: []string{"docs/**"}}},
+				},
+				PostSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "docs"}, When: &cicdv1.JobWhen{Paths: []string{"docs/**"}}},
+				},
+			},
+		},
+	}
+
+	tc := map[string]struct {
+		webhook *git.Webhook
+
+		expectedJobs     []string
+		expectedStatuses []git.CommitStatus
+	}{
+		"pullRequest": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePullRequest,
+				PullRequest: &git.PullRequest{
+					ID:     1,
+					Action: git.PullRequestActionOpen,
+					Base:   git.Base{Ref: "master", Sha: "1111111111"},
+					Head:   git.Head{Ref: "feat", Sha: "2222222222"},
+				},
+			},
+			expectedJobs:     []string{"test"},
+			expectedStatuses: []git.CommitStatus{{Context: "docs", State: git.CommitStatusStateSkipped, Description: skippedPathsDescription}},
+		},
+		"push": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/master", Sha: "4444444444", Before: "3333333333"},
+			},
+			expectedJobs: []string{"docs"},
+		},
+		"pushNewBranch": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/new", Sha: "4444444444", Before: git.FakeSha},
+			},
+			expectedJobs: []string{"docs"},
+		},
+		"pushNoMatch": {
+			webhook: &git.Webhook{
+				EventType: git.EventTypePush,
+				Push:      &git.Push{Ref: "refs/heads/master", Sha: "5555555555", Before: "4444444444"},
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {
+					PullRequestDiffs: map[int]*git.Diff{1: {Changes: []git.Change{{Filename: "main.go"}}}},
+					CommitDiffs: map[string]*git.Diff{
+						"3333333333...4444444444": {Changes: []git.Change{{Filename: "docs/index.md"}}},
+						"4444444444...5555555555": {Changes: []git.Change{{Filename: "main.go"}}},
+					},
+					CommitStatuses: map[string][]git.CommitStatus{},
+				},
+			}
+
+			d := &Dispatcher{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()}
+			require.NoError(t, d.Handle(c.webhook, ic))
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, d.Client.List(context.Background(), ijList))
+			if len(c.expectedJobs) == 0 {
+				require.Empty(t, ijList.Items)
+			} else {
+				require.Len(t, ijList.Items, 1)
+				var names []string
+				for _, j := range ijList.Items[0].Spec.Jobs {
+					names = append(names, j.Name)
+				}
+				require.Equal(t, c.expectedJobs, names)
+			}
+
+			if c.webhook.PullRequest != nil {
+				require.Equal(t, c.expectedStatuses, gitfake.Repos["test/repo"].CommitStatuses[c.webhook.PullRequest.Head.Sha])
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/expression"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// evaluatePriority decides the priority of the IntegrationJob triggered by the webhook
+// The highest priority of the matching priority rules is used, defaulting to ijManageSpec.priority
+// Rules failed to be evaluated are regarded as not matching
+func evaluatePriority(config *cicdv1.IntegrationConfig, webhook *git.Webhook) int32 {
+	priority := config.Spec.IJManageSpec.Priority
+	matched := false
+
+	var vars expression.Variables
+	for _, rule := range config.Spec.IJManageSpec.PriorityRules {
+		if matched && rule.Priority <= priority {
+			continue
+		}
+
+		e, err := expression.Parse(rule.Expression)
+		if err != nil {
+			log.Error(err, "cannot parse the priority rule", "expression", rule.Expression)
+			continue
+		}
+
+		if vars == nil {
+			vars = generateExpressionVariables(webhook)
+		}
+		result, err := e.Evaluate(vars)
+		if err != nil {
+			log.Error(err, "cannot evaluate the priority rule", "expression", rule.Expression)
+			continue
+		}
+		if result {
+			priority = rule.Priority
+			matched = true
+		}
+	}
+	return priority
+}
@@ -0,0 +1,93 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+func TestEvaluatePriority(t *testing.T) {
+	rules := []cicdv1.PriorityRule{
+		{Expression: `branch == "master"`, Priority: 100},
+		{Expression: `branch =~ "^release-"`, Priority: 200},
+		{Expression: `"urgent" in labels`, Priority: 300},
+		{Expression: `branch ==`, Priority: 1000},
+	}
+	pr := func(base string, labels ...git.IssueLabel) *git.Webhook {
+		return &git.Webhook{
+			EventType:   git.EventTypePullRequest,
+			PullRequest: &git.PullRequest{Base: git.Base{Ref: base}, Labels: labels},
+		}
+	}
+	push := func(ref string) *git.Webhook {
+		return &git.Webhook{EventType: git.EventTypePush, Push: &git.Push{Ref: ref}}
+	}
+
+	tc := map[string]struct {
+		defaultPriority int32
+		rules           []cicdv1.PriorityRule
+		webhook         *git.Webhook
+
+		expectedPriority int32
+	}{
+		"noRules": {
+			defaultPriority:  10,
+			webhook:          pr("master"),
+			expectedPriority: 10,
+		},
+		"notMatched": {
+			defaultPriority:  10,
+			rules:            rules,
+			webhook:          pr("feat"),
+			expectedPriority: 10,
+		},
+		"pullRequestMaster": {
+			rules:            rules,
+			webhook:          pr("master"),
+			expectedPriority: 100,
+		},
+		"pushRelease": {
+			rules:            rules,
+			webhook:          push("refs/heads/release-1.0"),
+			expectedPriority: 200,
+		},
+		"highestMatched": {
+			rules:            rules,
+			webhook:          pr("release-1.0", git.IssueLabel{Name: "urgent"}),
+			expectedPriority: 300,
+		},
+		"lowerThanDefault": {
+			defaultPriority:  500,
+			rules:            rules,
+			webhook:          pr("master"),
+			expectedPriority: 100,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			config := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{
+				IJManageSpec: cicdv1.IntegrationJobManageSpec{Priority: c.defaultPriority, PriorityRules: c.rules},
+			}}
+			require.Equal(t, c.expectedPriority, evaluatePriority(config, c.webhook))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// rerunCheckRun creates an IntegrationJob for the check run re-requested from the UI, running only the job of the check
+// run, along with the jobs it depends on. All the jobs are run if the check run is the aggregated commit status
+// Check runs for outdated commits of the pull requests are not re-run
+func (d Dispatcher) rerunCheckRun(webhook *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	checkRun := webhook.CheckRun
+	trigger := &git.Webhook{Repo: webhook.Repo, Sender: webhook.Sender}
+	switch {
+	case checkRun.PullRequest != nil:
+		if checkRun.PullRequest.Head.Sha != checkRun.Sha {
+			log.Info(fmt.Sprintf("Check run %s of %s is not re-run, as it's not the head of pull request %d", checkRun.Name, checkRun.Sha, checkRun.PullRequest.ID))
+			return nil
+		}
+		trigger.EventType = git.EventTypePullRequest
+		trigger.PullRequest = checkRun.PullRequest
+	case checkRun.Branch != "":
+		gitCli, err := utils.GetGitCli(config, d.Client)
+		if err != nil {
+			return err
+		}
+		// The check runs of the fork pull requests don't have the pull requests but the fork's branch. They're re-run as
+		// a push only if the commit is the head of the base repository's branch, not to run postSubmit jobs for the forks
+		if branch, err := gitCli.GetBranch(checkRun.Branch); err == nil && branch.CommitID == checkRun.Sha {
+			trigger.EventType = git.EventTypePush
+			trigger.Push = &git.Push{Ref: "refs/heads/" + checkRun.Branch, Sha: checkRun.Sha}
+			break
+		}
+		pr, err := findPullRequestBySha(gitCli, checkRun.Sha)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			log.Info(fmt.Sprintf("Check run %s of %s is not re-run, as it's neither the head of branch %s nor of any pull request", checkRun.Name, checkRun.Sha, checkRun.Branch))
+			return nil
+		}
+		trigger.EventType = git.EventTypePullRequest
+		trigger.PullRequest = pr
+	default:
+		return nil
+	}
+
+	// Load jobs from the config file in the repository
+	if config.Spec.Jobs.ConfigFile != nil {
+		gitCli, err := utils.GetGitCli(config, d.Client)
+		if err != nil {
+			return err
+		}
+		config, err = loadConfigFile(trigger, config, gitCli)
+		if err != nil {
+			return err
+		}
+	}
+
+	var job *cicdv1.IntegrationJob
+	if trigger.PullRequest != nil && checkRun.Name == config.GetAggregateCommitStatusContext() {
+		job = GeneratePreSubmit([]git.PullRequest{*trigger.PullRequest}, &trigger.Repo, &trigger.Sender, config)
+	} else if trigger.PullRequest != nil {
+		// A manual job can be re-run, as it's re-requested explicitly
+		job = GeneratePreSubmitWithManualJobs([]git.PullRequest{*trigger.PullRequest}, &trigger.Repo, &trigger.Sender, config)
+	} else {
+		job = GeneratePostSubmit(trigger.Push, &trigger.Repo, &trigger.Sender, config)
+	}
+	if job == nil {
+		return nil
+	}
+
+	if checkRun.Name != config.GetAggregateCommitStatusContext() {
+		if err := filterJobsWithPres(job, map[string]struct{}{checkRun.Name: {}}); err != nil {
+			return err
+		}
+	}
+	if len(job.Spec.Jobs) == 0 {
+		return nil
+	}
+
+	return d.Client.Create(context.Background(), job)
+}
+
+// findPullRequestBySha finds the open pull request whose head is the sha. Nil is returned if there's none
+func findPullRequestBySha(gitCli git.Client, sha string) (*git.PullRequest, error) {
+	prs, err := gitCli.ListPullRequests(true)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prs {
+		if prs[i].Head.Sha == sha {
+			return &prs[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,133 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDispatcher_Handle_checkRun(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}, CheckRuns: true},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "build"}},
+					{Container: corev1.Container{Name: "lint"}},
+					{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+				},
+				PostSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "deploy"}},
+				},
+			},
+			AggregateCommitStatus: &cicdv1.AggregateCommitStatus{},
+		},
+	}
+	pr := &git.PullRequest{
+		ID:   1,
+		Base: git.Base{Ref: "master", Sha: "1111111111"},
+		Head: git.Head{Ref: "feat", Sha: "2222222222"},
+	}
+
+	tc := map[string]struct {
+		checkRun *git.CheckRun
+
+		expectedType cicdv1.JobType
+		expectedJobs []string
+	}{
+		"pullRequest": {
+			checkRun:     &git.CheckRun{Name: "test", Sha: "2222222222", Branch: "feat", PullRequest: pr},
+			expectedType: cicdv1.JobTypePreSubmit,
+			expectedJobs: []string{"build", "test"},
+		},
+		"pullRequestAggregated": {
+			checkRun:     &git.CheckRun{Name: cicdv1.DefaultAggregateCommitStatusContext, Sha: "2222222222", Branch: "feat", PullRequest: pr},
+			expectedType: cicdv1.JobTypePreSubmit,
+			expectedJobs: []string{"build", "lint", "test"},
+		},
+		"pullRequestOutdated": {
+			checkRun: &git.CheckRun{Name: "test", Sha: "3333333333", Branch: "feat", PullRequest: pr},
+		},
+		"push": {
+			checkRun:     &git.CheckRun{Name: "deploy", Sha: "4444444444", Branch: "master"},
+			expectedType: cicdv1.JobTypePostSubmit,
+			expectedJobs: []string{"deploy"},
+		},
+		"forkPullRequest": {
+			checkRun:     &git.CheckRun{Name: "test", Sha: "5555555555", Branch: "master"},
+			expectedType: cicdv1.JobTypePreSubmit,
+			expectedJobs: []string{"build", "test"},
+		},
+		"notBranchHead": {
+			checkRun: &git.CheckRun{Name: "deploy", Sha: "6666666666", Branch: "master"},
+		},
+		"notJob": {
+			checkRun: &git.CheckRun{Name: "test/coverage", Sha: "2222222222", Branch: "feat", PullRequest: pr},
+		},
+		"noBranch": {
+			checkRun: &git.CheckRun{Name: "deploy", Sha: "4444444444"},
+		},
+	}
+
+	gitfake.Branches = map[string]*git.Branch{
+		"master": {Name: "master", CommitID: "4444444444"},
+	}
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo": {
+			PullRequests: map[int]*git.PullRequest{
+				2: {ID: 2, Base: git.Base{Ref: "master"}, Head: git.Head{Ref: "master", Sha: "5555555555"}, Fork: true},
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			d := &Dispatcher{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()}
+			require.NoError(t, d.Handle(&git.Webhook{EventType: git.EventTypeCheckRun, Repo: git.Repository{Name: "test/repo"}, CheckRun: c.checkRun}, ic))
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, d.Client.List(context.Background(), ijList))
+			if c.expectedJobs == nil {
+				require.Empty(t, ijList.Items)
+				return
+			}
+			require.Len(t, ijList.Items, 1)
+			require.Equal(t, c.expectedType, ijList.Items[0].Spec.ConfigRef.Type)
+			require.Equal(t, c.checkRun.Sha, ijList.Items[0].GetHeadSha())
+			var jobs []string
+			for _, j := range ijList.Items[0].Spec.Jobs {
+				jobs = append(jobs, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, jobs)
+		})
+	}
+}
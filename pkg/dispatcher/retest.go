@@ -0,0 +1,99 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RetestFailed creates an IntegrationJob for the pull request, running only the jobs whose last commit statuses for the
+// head commit are failures or errors, along with the jobs they depend on. Failed manual jobs are also run again
+// The jobs should be loaded from the config file in advance. It returns nil if there is no failed job
+func RetestFailed(cli client.Client, gitCli git.Client, pr *git.PullRequest, repo *git.Repository, sender *git.User, config *cicdv1.IntegrationConfig) (*cicdv1.IntegrationJob, error) {
+	job := GeneratePreSubmitWithManualJobs([]git.PullRequest{*pr}, repo, sender, config)
+	if job == nil {
+		return nil, nil
+	}
+
+	if err := filterFailedJobs(job, gitCli); err != nil {
+		return nil, err
+	}
+	if len(job.Spec.Jobs) == 0 {
+		return nil, nil
+	}
+
+	if err := cli.Create(context.Background(), job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// filterFailedJobs filters the jobs failed for the head commit and the jobs they depend on
+func filterFailedJobs(job *cicdv1.IntegrationJob, gitCli git.Client) error {
+	statuses, err := gitCli.ListCommitStatuses(job.GetHeadSha())
+	if err != nil {
+		return fmt.Errorf("cannot list commit statuses: %s", err.Error())
+	}
+
+	// Statuses are listed from the latest one
+	lastStates := map[string]git.CommitStatusState{}
+	for _, s := range statuses {
+		if _, exist := lastStates[s.Context]; !exist {
+			lastStates[s.Context] = s.State
+		}
+	}
+
+	wanted := map[string]struct{}{}
+	for _, j := range job.Spec.Jobs {
+		state := lastStates[j.Name]
+		if state == git.CommitStatusStateFailure || state == git.CommitStatusStateError {
+			wanted[j.Name] = struct{}{}
+		}
+	}
+	return filterJobsWithPres(job, wanted)
+}
+
+// filterJobsWithPres filters the wanted jobs and the jobs they depend on
+func filterJobsWithPres(job *cicdv1.IntegrationJob, wanted map[string]struct{}) error {
+	graph, err := job.Spec.Jobs.GetGraph()
+	if err != nil {
+		return err
+	}
+
+	pres := map[string]struct{}{}
+	for name := range wanted {
+		for _, p := range graph.GetPres(name) {
+			pres[p] = struct{}{}
+		}
+	}
+
+	filteredJobs := cicdv1.Jobs{}
+	for _, j := range job.Spec.Jobs {
+		_, isWanted := wanted[j.Name]
+		_, isPre := pres[j.Name]
+		if isWanted || isPre {
+			filteredJobs = append(filteredJobs, j)
+		}
+	}
+	job.Spec.Jobs = filteredJobs
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRetestFailed(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit: []cicdv1.Job{
+					{Container: corev1.Container{Name: "build"}},
+					{Container: corev1.Container{Name: "lint"}},
+					{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+					{Container: corev1.Container{Name: "e2e"}, After: []string{"build"}},
+				},
+			},
+		},
+	}
+	pr := &git.PullRequest{
+		ID:   1,
+		Base: git.Base{Ref: "master", Sha: "1111111111"},
+		Head: git.Head{Ref: "feat", Sha: "2222222222"},
+	}
+
+	tc := map[string]struct {
+		statuses []git.CommitStatus
+
+		errorOccurs  bool
+		expectedJobs []string
+	}{
+		"failedWithDependency": {
+			statuses: []git.CommitStatus{
+				{Context: "build", State: git.CommitStatusStateSuccess},
+				{Context: "lint", State: git.CommitStatusStateSuccess},
+				{Context: "test", State: git.CommitStatusStateFailure},
+				{Context: "e2e", State: git.CommitStatusStateSuccess},
+			},
+			expectedJobs: []string{"build", "test"},
+		},
+		"error": {
+			statuses: []git.CommitStatus{
+				{Context: "build", State: git.CommitStatusStateSuccess},
+				{Context: "lint", State: git.CommitStatusStateError},
+				{Context: "test", State: git.CommitStatusStateSuccess},
+				{Context: "e2e", State: git.CommitStatusStatePending},
+			},
+			expectedJobs: []string{"lint"},
+		},
+		"noFailure": {
+			statuses: []git.CommitStatus{
+				{Context: "build", State: git.CommitStatusStateSuccess},
+				{Context: "lint", State: git.CommitStatusStateSuccess},
+			},
+		},
+		"listErr": {
+			errorOccurs: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			statuses := map[string][]git.CommitStatus{}
+			if c.statuses != nil {
+				statuses[pr.Head.Sha] = c.statuses
+			}
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {CommitStatuses: statuses},
+			}
+			gitCli := &gitfake.Client{IntegrationConfig: ic}
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+
+			job, err := RetestFailed(cli, gitCli, pr, &git.Repository{Name: "test/repo"}, &git.User{Name: "tester"}, ic)
+			if c.errorOccurs {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, cli.List(context.Background(), ijList))
+			if c.expectedJobs == nil {
+				require.Nil(t, job)
+				require.Empty(t, ijList.Items)
+				return
+			}
+			require.Len(t, ijList.Items, 1)
+			var names []string
+			for _, j := range ijList.Items[0].Spec.Jobs {
+				names = append(names, j.Name)
+			}
+			require.Equal(t, c.expectedJobs, names)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RetryJob creates an IntegrationJob re-running only the failed job of the completed IntegrationJob
+// It runs for the same commits with the same workspaces and parameters, so that the result is reported under the same
+// commit status context. The jobs the job runs after are not run again
+func RetryJob(cli client.Client, ij *cicdv1.IntegrationJob, jobName string) (*cicdv1.IntegrationJob, error) {
+	if ij.Status.CompletionTime == nil {
+		return nil, fmt.Errorf("IntegrationJob %s is not completed yet", ij.Name)
+	}
+
+	var job *cicdv1.Job
+	for i := range ij.Spec.Jobs {
+		if ij.Spec.Jobs[i].Name == jobName {
+			job = ij.Spec.Jobs[i].DeepCopy()
+			break
+		}
+	}
+	if job == nil {
+		return nil, fmt.Errorf("there is no job %s in IntegrationJob %s", jobName, ij.Name)
+	}
+
+	failed := false
+	for _, s := range ij.Status.Jobs {
+		if s.Name == jobName {
+			failed = s.State == cicdv1.CommitStatusStateFailure || s.State == cicdv1.CommitStatusStateError
+			break
+		}
+	}
+	if !failed {
+		return nil, fmt.Errorf("job %s of IntegrationJob %s did not fail", jobName, ij.Name)
+	}
+
+	// The jobs it runs after are not run, so it should not wait for them
+	job.After = nil
+
+	jobID := utils.RandomString(20)
+	retry := &cicdv1.IntegrationJob{
+		ObjectMeta: generateMeta(ij.Spec.ConfigRef.Name, ij.Namespace, ij.GetHeadSha(), jobID),
+		Spec:       *ij.Spec.DeepCopy(),
+	}
+	retry.Annotations = map[string]string{cicdv1.JobAnnotationRetryOf: ij.Name}
+	retry.Spec.ID = jobID
+	retry.Spec.Jobs = cicdv1.Jobs{*job}
+	retry.Spec.Cancelled = false
+
+	if err := cli.Create(context.Background(), retry); err != nil {
+		return nil, err
+	}
+	return retry, nil
+}
@@ -0,0 +1,124 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRetryJob(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	completed := &metav1.Time{Time: time.Now()}
+	newIJ := func(completion *metav1.Time) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef:  cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				ID:         "test-id",
+				Workspaces: []tektonv1beta1.WorkspaceBinding{{Name: "cache", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}}},
+				Jobs: cicdv1.Jobs{
+					{Container: corev1.Container{Name: "build"}},
+					{Container: corev1.Container{Name: "test"}, After: []string{"build"}},
+					{Container: corev1.Container{Name: "lint"}},
+				},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "1111111111"},
+					Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: 1, Ref: "feat", Sha: "2222222222"}},
+				},
+			},
+			Status: cicdv1.IntegrationJobStatus{
+				CompletionTime: completion,
+				Jobs: []cicdv1.JobStatus{
+					{Name: "build", State: cicdv1.CommitStatusStateSuccess},
+					{Name: "test", State: cicdv1.CommitStatusStateFailure},
+					{Name: "lint", State: cicdv1.CommitStatusStateSuccess},
+				},
+			},
+		}
+	}
+
+	tc := map[string]struct {
+		completion *metav1.Time
+		job        string
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"normal": {
+			completion: completed,
+			job:        "test",
+		},
+		"notCompleted": {
+			job:          "test",
+			errorOccurs:  true,
+			errorMessage: "IntegrationJob test-ij is not completed yet",
+		},
+		"noJob": {
+			completion:   completed,
+			job:          "deploy",
+			errorOccurs:  true,
+			errorMessage: "there is no job deploy in IntegrationJob test-ij",
+		},
+		"notFailed": {
+			completion:   completed,
+			job:          "lint",
+			errorOccurs:  true,
+			errorMessage: "job lint of IntegrationJob test-ij did not fail",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := newIJ(c.completion)
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(ij).Build()
+
+			retry, err := RetryJob(cli, ij, c.job)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			result := &cicdv1.IntegrationJob{}
+			require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: retry.Name, Namespace: "default"}, result))
+			require.NotEqual(t, "test-ij", result.Name)
+			require.NotEqual(t, "test-id", result.Spec.ID)
+			require.Equal(t, "test-ij", result.Annotations[cicdv1.JobAnnotationRetryOf])
+			require.Equal(t, "test-ic", result.Labels[cicdv1.JobLabelConfig])
+			require.Equal(t, ij.Spec.Refs, result.Spec.Refs)
+			require.Equal(t, ij.Spec.Workspaces, result.Spec.Workspaces)
+			require.Equal(t, cicdv1.Jobs{{Container: corev1.Container{Name: "test"}}}, result.Spec.Jobs)
+			require.Empty(t, result.Status.Jobs)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// defaultSkipDirectives are the directives which are always honored, in addition to the IntegrationConfig's skipDirectives
+var defaultSkipDirectives = []string{"[skip ci]", "[ci skip]"}
+
+// findSkipDirective returns the first directive contained in any of the texts (case-insensitive), or an empty string
+func findSkipDirective(directives []string, texts ...string) string {
+	for _, text := range texts {
+		lower := strings.ToLower(text)
+		for _, d := range directives {
+			if d != "" && strings.Contains(lower, strings.ToLower(d)) {
+				return d
+			}
+		}
+	}
+	return ""
+}
+
+// checkSkipDirective checks if the head commit's message or the pull request's title contains a skip directive
+// If it does, it sets skipped commit statuses for the jobs and returns true
+func checkSkipDirective(job *cicdv1.IntegrationJob, webhook *git.Webhook, config *cicdv1.IntegrationConfig, gitCli git.Client) bool {
+	directives := append(append([]string{}, defaultSkipDirectives...), config.Spec.Jobs.SkipDirectives...)
+
+	var sha, directive string
+	switch {
+	case webhook.EventType == git.EventTypePullRequest && webhook.PullRequest != nil:
+		pr := webhook.PullRequest
+		sha = pr.Head.Sha
+		directive = findSkipDirective(directives, pr.Title)
+		if directive == "" && gitCli != nil {
+			directive = findSkipDirective(directives, getHeadCommitMessage(pr, gitCli))
+		}
+	case webhook.EventType == git.EventTypePush && webhook.Push != nil:
+		sha = webhook.Push.Sha
+		directive = findSkipDirective(directives, webhook.Push.HeadCommit.Message)
+	}
+	if directive == "" {
+		return false
+	}
+
+	log.Info(fmt.Sprintf("Skipping jobs of %s for the directive %s", sha, directive))
+	desc := fmt.Sprintf("Skipped by %s directive", directive)
+	setSkippedStatuses(gitCli, config, sha, job.Spec.Jobs, desc)
+	setAllSkippedStatus(gitCli, config, sha, desc)
+	return true
+}
+
+// getHeadCommitMessage returns the message of the pull request's head commit
+func getHeadCommitMessage(pr *git.PullRequest, gitCli git.Client) string {
+	commits, err := gitCli.ListPullRequestCommits(pr.ID)
+	if err != nil {
+		log.Error(err, "cannot list commits of the pull request")
+		return ""
+	}
+	for _, c := range commits {
+		if c.SHA == pr.Head.Sha {
+			return c.Message
+		}
+	}
+	return ""
+}
+
+// canReportStatus checks if the commit statuses can be reported. They cannot be set without a token
+func canReportStatus(gitCli git.Client, config *cicdv1.IntegrationConfig) bool {
+	return gitCli != nil && config.Spec.Git.Token != nil
+}
+
+// setSkippedStatuses sets skipped commit statuses for the skipped jobs, so that they do not block the pull requests
+// The skipped jobs are not reported if the commit statuses are aggregated, as the aggregated commit status only
+// summarizes the jobs which are run
+func setSkippedStatuses(gitCli git.Client, config *cicdv1.IntegrationConfig, sha string, jobs []cicdv1.Job, description string) {
+	if config.GetAggregateCommitStatusContext() != "" || !canReportStatus(gitCli, config) {
+		return
+	}
+	for _, j := range jobs {
+		if err := gitCli.SetCommitStatus(sha, git.CommitStatus{
+			Context:     j.Name,
+			State:       git.CommitStatusStateSkipped,
+			Description: description,
+		}); err != nil {
+			log.Error(err, "cannot set skipped commit status", "job", j.Name)
+		}
+	}
+}
+
+// setAllSkippedStatus sets a skipped aggregated commit status, if the commit statuses are aggregated and all the
+// jobs are skipped, so that no IntegrationJob reports it
+func setAllSkippedStatus(gitCli git.Client, config *cicdv1.IntegrationConfig, sha, description string) {
+	context := config.GetAggregateCommitStatusContext()
+	if context == "" || sha == "" || sha == git.FakeSha || !canReportStatus(gitCli, config) {
+		return
+	}
+	if err := gitCli.SetCommitStatus(sha, git.CommitStatus{
+		Context:     context,
+		State:       git.CommitStatusStateSkipped,
+		Description: description,
+	}); err != nil {
+		log.Error(err, "cannot set skipped commit status", "context", context)
+	}
+}
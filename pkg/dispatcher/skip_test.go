@@ -0,0 +1,244 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFindSkipDirective(t *testing.T) {
+	tc := map[string]struct {
+		texts []string
+
+		expected string
+	}{
+		"skipCI":          {texts: []string{"Fix typo [skip ci]"}, expected: "[skip ci]"},
+		"ciSkip":          {texts: []string{"[ci skip] Fix typo"}, expected: "[ci skip]"},
+		"caseInsensitive": {texts: []string{"Fix typo [SKIP CI]"}, expected: "[skip ci]"},
+		"custom":          {texts: []string{"Update docs", "Fix typo\n\n[no-build]"}, expected: "[no-build]"},
+		"none":            {texts: []string{"Fix typo", ""}, expected: ""},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, findSkipDirective(append(defaultSkipDirectives, "[no-build]"), c.texts...))
+		})
+	}
+}
+
+func TestDispatcher_Handle_skip(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			Jobs: cicdv1.IntegrationConfigJobs{
+				PreSubmit:      []cicdv1.Job{{Container: corev1.Container{Name: "test"}}},
+				PostSubmit:     []cicdv1.Job{{Container: corev1.Container{Name: "deploy"}}},
+				SkipDirectives: []string{"[no-build]"},
+			},
+		},
+	}
+
+	prWebhook := func(title string) *git.Webhook {
+		return &git.Webhook{
+			EventType: git.EventTypePullRequest,
+			PullRequest: &git.PullRequest{
+				ID:     1,
+				Title:  title,
+				Action: git.PullRequestActionOpen,
+				Base:   git.Base{Ref: "master", Sha: "1111111111"},
+				Head:   git.Head{Ref: "feat", Sha: "2222222222"},
+			},
+		}
+	}
+	pushWebhook := func(message string) *git.Webhook {
+		return &git.Webhook{
+			EventType: git.EventTypePush,
+			Push:      &git.Push{Ref: "refs/heads/master", Sha: "3333333333", HeadCommit: git.Commit{Message: message}},
+		}
+	}
+
+	tc := map[string]struct {
+		webhook       *git.Webhook
+		commitMessage string
+		invalidToken  bool
+
+		expectedSkipped     bool
+		expectedDescription string
+	}{
+		"prTitle": {
+			webhook:             prWebhook("Update docs [skip ci]"),
+			expectedSkipped:     true,
+			expectedDescription: "Skipped by [skip ci] directive",
+		},
+		"prCommitMessage": {
+			webhook:             prWebhook("Update docs"),
+			commitMessage:       "Update docs\n\n[ci skip]",
+			expectedSkipped:     true,
+			expectedDescription: "Skipped by [ci skip] directive",
+		},
+		"prNotSkipped": {
+			webhook:       prWebhook("Update docs"),
+			commitMessage: "Update docs",
+		},
+		"push": {
+			webhook:             pushWebhook("Update docs [skip ci]"),
+			expectedSkipped:     true,
+			expectedDescription: "Skipped by [skip ci] directive",
+		},
+		"pushCustom": {
+			webhook:             pushWebhook("Update docs [No-Build]"),
+			expectedSkipped:     true,
+			expectedDescription: "Skipped by [no-build] directive",
+		},
+		"pushNotSkipped": {
+			webhook: pushWebhook("Update docs"),
+		},
+		"invalidToken": {
+			webhook:         pushWebhook("Update docs [skip ci]"),
+			invalidToken:    true,
+			expectedSkipped: true,
+		},
+		"invalidTokenNotSkipped": {
+			webhook:      pushWebhook("Update docs"),
+			invalidToken: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {
+					PullRequestCommits: map[int][]git.Commit{1: {{SHA: "2222222222", Message: c.commitMessage}}},
+					CommitStatuses:     map[string][]git.CommitStatus{},
+				},
+			}
+
+			config := ic.DeepCopy()
+			if c.invalidToken {
+				config.Spec.Git.Token = &cicdv1.GitToken{ValueFrom: &cicdv1.GitTokenFrom{SecretKeyRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "no-secret"}, Key: "token"}}}
+			}
+
+			d := &Dispatcher{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(config).Build()}
+			require.NoError(t, d.Handle(c.webhook, config))
+
+			ijList := &cicdv1.IntegrationJobList{}
+			require.NoError(t, d.Client.List(context.Background(), ijList))
+
+			sha := "3333333333"
+			if c.webhook.PullRequest != nil {
+				sha = c.webhook.PullRequest.Head.Sha
+			}
+			statuses := gitfake.Repos["test/repo"].CommitStatuses[sha]
+			if c.expectedSkipped && c.invalidToken {
+				require.Empty(t, ijList.Items)
+				require.Empty(t, statuses)
+			} else if c.expectedSkipped {
+				require.Empty(t, ijList.Items)
+				require.Len(t, statuses, 1)
+				require.Equal(t, git.CommitStatusStateSkipped, statuses[0].State)
+				require.Equal(t, c.expectedDescription, statuses[0].Description)
+			} else {
+				require.Len(t, ijList.Items, 1)
+				require.Empty(t, statuses)
+			}
+		})
+	}
+}
+
+func TestSetSkippedStatuses_aggregated(t *testing.T) {
+	jobs := []cicdv1.Job{{Container: corev1.Container{Name: "test"}}, {Container: corev1.Container{Name: "lint"}}}
+
+	tc := map[string]struct {
+		aggregate *cicdv1.AggregateCommitStatus
+		sha       string
+		noToken   bool
+
+		expectedContexts []string
+	}{
+		"notAggregated": {
+			sha:              "2222222222",
+			expectedContexts: []string{"test", "lint"},
+		},
+		"aggregated": {
+			aggregate:        &cicdv1.AggregateCommitStatus{},
+			sha:              "2222222222",
+			expectedContexts: []string{cicdv1.DefaultAggregateCommitStatusContext},
+		},
+		"aggregatedCustomContext": {
+			aggregate:        &cicdv1.AggregateCommitStatus{Context: "ci/all"},
+			sha:              "2222222222",
+			expectedContexts: []string{"ci/all"},
+		},
+		"aggregatedFakeSha": {
+			aggregate: &cicdv1.AggregateCommitStatus{},
+			sha:       git.FakeSha,
+		},
+		"noToken": {
+			sha:     "2222222222",
+			noToken: true,
+		},
+		"aggregatedNoToken": {
+			aggregate: &cicdv1.AggregateCommitStatus{},
+			sha:       "2222222222",
+			noToken:   true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ic := &cicdv1.IntegrationConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+				Spec: cicdv1.IntegrationConfigSpec{
+					Git:                   cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+					AggregateCommitStatus: c.aggregate,
+				},
+			}
+			if c.noToken {
+				ic.Spec.Git.Token = nil
+			}
+			gitfake.Repos = map[string]*gitfake.Repo{
+				"test/repo": {CommitStatuses: map[string][]git.CommitStatus{}},
+			}
+			gitCli := &gitfake.Client{IntegrationConfig: ic}
+
+			setSkippedStatuses(gitCli, ic, c.sha, jobs, "Skipped")
+			setAllSkippedStatus(gitCli, ic, c.sha, "Skipped")
+
+			var contexts []string
+			for _, s := range gitfake.Repos["test/repo"].CommitStatuses[c.sha] {
+				require.Equal(t, git.CommitStatusStateSkipped, s.State)
+				contexts = append(contexts, s.Context)
+			}
+			require.Equal(t, c.expectedContexts, contexts)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cancelSupersededJobs requests to cancel the IntegrationJobs superseded by the job, i.e., the ones still running for
+// the older commits of the same pull request or branch
+// IntegrationJob controller cancels the requested IntegrationJobs and their PipelineRuns
+func cancelSupersededJobs(cli client.Client, job *cicdv1.IntegrationJob) error {
+	if job.Spec.ConfigRef.Type != cicdv1.JobTypePreSubmit && job.Spec.ConfigRef.Type != cicdv1.JobTypePostSubmit {
+		return nil
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := cli.List(context.Background(), ijList, client.InNamespace(job.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: job.Spec.ConfigRef.Name}); err != nil {
+		return err
+	}
+
+	groupKey := job.GetGroupKey(cicdv1.ConcurrencyGroupPullRequest)
+	for i := range ijList.Items {
+		ij := &ijList.Items[i]
+		if ij.Name == job.Name || ij.IsCompleted() || ij.Annotations[cicdv1.JobAnnotationSupersededBy] != "" {
+			continue
+		}
+		if ij.Spec.ConfigRef.Type != job.Spec.ConfigRef.Type || ij.GetGroupKey(cicdv1.ConcurrencyGroupPullRequest) != groupKey || ij.GetHeadSha() == job.GetHeadSha() {
+			continue
+		}
+
+		if err := markSuperseded(cli, ij, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markSuperseded marks the IntegrationJob superseded by the job, for the IntegrationJob controller to cancel it
+func markSuperseded(cli client.Client, ij, job *cicdv1.IntegrationJob) error {
+	original := ij.DeepCopy()
+	if ij.Annotations == nil {
+		ij.Annotations = map[string]string{}
+	}
+	ij.Annotations[cicdv1.JobAnnotationSupersededBy] = job.Name
+	if err := cli.Patch(context.Background(), ij, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("IntegrationJob %s/%s is superseded by %s", ij.Namespace, ij.Name, job.Name))
+	return nil
+}
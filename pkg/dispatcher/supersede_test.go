@@ -0,0 +1,92 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCancelSupersededJobs(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	pullJob := func(name string, id int, sha string, completed bool) *cicdv1.IntegrationJob {
+		ij := &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "base"},
+					Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: id, Ref: "feat", Sha: sha}},
+				},
+			},
+		}
+		if completed {
+			ij.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		}
+		return ij
+	}
+	pushJob := func(name, sha string) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Repository: "test/repo",
+					Base:       cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/feat", Sha: sha},
+				},
+			},
+		}
+	}
+
+	newJob := pullJob("new", 1, "sha-3", false)
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		pullJob("old-running", 1, "sha-1", false),
+		pullJob("old-completed", 1, "sha-2", true),
+		pullJob("same-sha", 1, "sha-3", false),
+		pullJob("other-pr", 2, "sha-4", false),
+		pushJob("push", "sha-5"),
+		newJob,
+	).Build()
+
+	require.NoError(t, cancelSupersededJobs(cli, newJob))
+
+	expected := map[string]string{
+		"old-running":   "new",
+		"old-completed": "",
+		"same-sha":      "",
+		"other-pr":      "",
+		"push":          "",
+		"new":           "",
+	}
+	for name, supersededBy := range expected {
+		ij := &cicdv1.IntegrationJob{}
+		require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ij))
+		require.Equal(t, supersededBy, ij.Annotations[cicdv1.JobAnnotationSupersededBy], name)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events recorded for the IntegrationJobs' lifecycle and the IntegrationConfigs
+const (
+	ReasonIntegrationJobCreated   = "Created"
+	ReasonIntegrationJobScheduled = "Scheduled"
+	ReasonIntegrationJobStarted   = "Started"
+	ReasonIntegrationJobSucceeded = "Succeeded"
+	ReasonIntegrationJobFailed    = "Failed"
+	ReasonIntegrationJobCancelled = "Cancelled"
+
+	ReasonWebhookRegisterFailed = "WebhookRegisterFailed"
+)
+
+// RecordStateChange records the lifecycle event of the IntegrationJob, if its state is changed from the old state
+func RecordStateChange(recorder record.EventRecorder, job *cicdv1.IntegrationJob, oldState cicdv1.IntegrationJobState) {
+	if oldState == job.Status.State {
+		return
+	}
+
+	evType, reason, message := corev1.EventTypeNormal, "", job.Status.Message
+	switch job.Status.State {
+	case cicdv1.IntegrationJobStateRunning:
+		reason = ReasonIntegrationJobStarted
+		message = "IntegrationJob is started"
+	case cicdv1.IntegrationJobStateCompleted:
+		reason = ReasonIntegrationJobSucceeded
+	case cicdv1.IntegrationJobStateFailed:
+		evType, reason = corev1.EventTypeWarning, ReasonIntegrationJobFailed
+	case cicdv1.IntegrationJobStateCancelled:
+		reason = ReasonIntegrationJobCancelled
+	default:
+		return
+	}
+	if message == "" {
+		message = "IntegrationJob is " + string(job.Status.State)
+	}
+	recorder.Event(job, evType, reason, message)
+}
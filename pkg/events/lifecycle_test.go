@@ -0,0 +1,90 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordStateChange(t *testing.T) {
+	tc := map[string]struct {
+		oldState cicdv1.IntegrationJobState
+		state    cicdv1.IntegrationJobState
+		message  string
+
+		expected []string
+	}{
+		"started": {
+			oldState: cicdv1.IntegrationJobStatePending,
+			state:    cicdv1.IntegrationJobStateRunning,
+			message:  "Tasks Completed: 0 (Failed: 0, Cancelled 0), Incomplete: 1, Skipped: 0",
+			expected: []string{"Normal Started IntegrationJob is started"},
+		},
+		"succeeded": {
+			oldState: cicdv1.IntegrationJobStateRunning,
+			state:    cicdv1.IntegrationJobStateCompleted,
+			message:  "Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0",
+			expected: []string{"Normal Succeeded Tasks Completed: 1 (Failed: 0, Cancelled 0), Skipped: 0"},
+		},
+		"failed": {
+			oldState: cicdv1.IntegrationJobStateRunning,
+			state:    cicdv1.IntegrationJobStateFailed,
+			message:  "Job test timed out",
+			expected: []string{"Warning Failed Job test timed out"},
+		},
+		"failedNoMessage": {
+			oldState: cicdv1.IntegrationJobStatePending,
+			state:    cicdv1.IntegrationJobStateFailed,
+			expected: []string{"Warning Failed IntegrationJob is Failed"},
+		},
+		"cancelled": {
+			oldState: cicdv1.IntegrationJobStateRunning,
+			state:    cicdv1.IntegrationJobStateCancelled,
+			message:  "IntegrationJob is cancelled",
+			expected: []string{"Normal Cancelled IntegrationJob is cancelled"},
+		},
+		"pending": {
+			oldState: "",
+			state:    cicdv1.IntegrationJobStatePending,
+		},
+		"notChanged": {
+			oldState: cicdv1.IntegrationJobStateRunning,
+			state:    cicdv1.IntegrationJobStateRunning,
+			message:  "Tasks Completed: 1 (Failed: 0, Cancelled 0), Incomplete: 1, Skipped: 0",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			job := &cicdv1.IntegrationJob{Status: cicdv1.IntegrationJobStatus{State: c.state, Message: c.message}}
+
+			RecordStateChange(recorder, job, c.oldState)
+
+			close(recorder.Events)
+			var recorded []string
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			require.Equal(t, c.expected, recorded)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package expression implements a simple boolean expression language, used for the jobs' when.expression
+// e.g., branch =~ "^release-" && "ok-to-test" in labels && changedFiles < 100
+package expression
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Variables are values of the identifiers in an expression
+// Each value should be a string, a number (int or float64), a bool or a list of strings
+type Variables map[string]interface{}
+
+// Expression is a parsed expression
+type Expression struct {
+	root node
+}
+
+// Parse parses the expression
+func Parse(expr string) (*Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, unexpectedToken(t)
+	}
+	return &Expression{root: root}, nil
+}
+
+// Evaluate parses and evaluates the expression
+func Evaluate(expr string, vars Variables) (bool, error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return false, err
+	}
+	return e.Evaluate(vars)
+}
+
+// Evaluate evaluates the expression with the variables
+func (e *Expression) Evaluate(vars Variables) (bool, error) {
+	val, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression is not evaluated to a bool")
+	}
+	return b, nil
+}
+
+// Identifiers returns the sorted names of the identifiers used in the expression
+func (e *Expression) Identifiers() []string {
+	names := map[string]struct{}{}
+	e.root.identifiers(names)
+	var result []string
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+type node interface {
+	eval(vars Variables) (interface{}, error)
+	identifiers(names map[string]struct{})
+}
+
+type literalNode struct {
+	val interface{}
+}
+
+func (n *literalNode) eval(_ Variables) (interface{}, error) {
+	return n.val, nil
+}
+
+func (n *literalNode) identifiers(_ map[string]struct{}) {}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(vars Variables) (interface{}, error) {
+	val, exist := vars[n.name]
+	if !exist {
+		return nil, fmt.Errorf("undefined variable %s", n.name)
+	}
+	switch v := val.(type) {
+	case int:
+		return float64(v), nil
+	case string, float64, bool, []string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("variable %s has an unsupported type %T", n.name, val)
+}
+
+func (n *identNode) identifiers(names map[string]struct{}) {
+	names[n.name] = struct{}{}
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(vars Variables) (interface{}, error) {
+	list := []string{}
+	for _, item := range n.items {
+		val, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("list items should be strings")
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (n *listNode) identifiers(names map[string]struct{}) {
+	for _, item := range n.items {
+		item.identifiers(names)
+	}
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(vars Variables) (interface{}, error) {
+	b, err := evalBool(n.operand, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+func (n *notNode) identifiers(names map[string]struct{}) {
+	n.operand.identifiers(names)
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(vars Variables) (interface{}, error) {
+	left, err := evalBool(n.left, vars, n.op)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return evalBool(n.right, vars, n.op)
+}
+
+func (n *logicalNode) identifiers(names map[string]struct{}) {
+	n.left.identifiers(names)
+	n.right.identifiers(names)
+}
+
+type comparisonNode struct {
+	op          string
+	left, right node
+	re          *regexp.Regexp
+}
+
+func (n *comparisonNode) eval(vars Variables) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		if fmt.Sprintf("%T", left) != fmt.Sprintf("%T", right) {
+			return nil, fmt.Errorf("cannot compare %T with %T", left, right)
+		}
+		equal := fmt.Sprint(left) == fmt.Sprint(right)
+		return equal == (n.op == "=="), nil
+	case "=~", "!~":
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operands of %s should be strings", n.op)
+		}
+		re := n.re
+		if re == nil {
+			if re, err = regexp.Compile(r); err != nil {
+				return nil, err
+			}
+		}
+		return re.MatchString(l) == (n.op == "=~"), nil
+	case "<", "<=", ">", ">=":
+		l, lok := left.(float64)
+		r, rok := right.(float64)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operands of %s should be numbers", n.op)
+		}
+		switch n.op {
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		default:
+			return l >= r, nil
+		}
+	case "in":
+		l, lok := left.(string)
+		r, rok := right.([]string)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operands of in should be a string and a list")
+		}
+		for _, item := range r {
+			if item == l {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func (n *comparisonNode) identifiers(names map[string]struct{}) {
+	n.left.identifiers(names)
+	n.right.identifiers(names)
+}
+
+func evalBool(n node, vars Variables, op string) (bool, error) {
+	val, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("operand of %s should be a bool", op)
+	}
+	return b, nil
+}
@@ -0,0 +1,84 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := Variables{
+		"event":        "pull_request",
+		"branch":       "release-1.0",
+		"author":       "cqbqdd11519",
+		"labels":       []string{"ok-to-test", "kind/bug"},
+		"changedFiles": 12,
+		"draft":        false,
+	}
+
+	tc := map[string]struct {
+		expr string
+
+		expected    bool
+		errorOccurs bool
+		errorMsg    string
+	}{
+		"equal":             {expr: `branch == "release-1.0"`, expected: true},
+		"notEqual":          {expr: `author != 'cqbqdd11519'`, expected: false},
+		"regexp":            {expr: `branch =~ "^release-"`, expected: true},
+		"notRegexp":         {expr: `branch !~ "^release-"`, expected: false},
+		"in":                {expr: `"ok-to-test" in labels`, expected: true},
+		"notIn":             {expr: `!("do-not-test" in labels)`, expected: true},
+		"inLiteralList":     {expr: `author in ["admin", "cqbqdd11519"]`, expected: true},
+		"number":            {expr: `changedFiles < 100 && changedFiles >= 12`, expected: true},
+		"bool":              {expr: `!draft`, expected: true},
+		"boolEqual":         {expr: `draft == false`, expected: true},
+		"precedence":        {expr: `event == "push" || branch == "master" || changedFiles > 10 && author == "cqbqdd11519"`, expected: true},
+		"parentheses":       {expr: `(event == "push" || branch == "master") && changedFiles > 10`, expected: false},
+		"shortCircuit":      {expr: `event == "push" && unknown == "a"`, expected: false},
+		"undefined":         {expr: `unknown == "a"`, errorOccurs: true, errorMsg: "undefined variable unknown"},
+		"typeMismatch":      {expr: `changedFiles == "12"`, errorOccurs: true, errorMsg: "cannot compare float64 with string"},
+		"notBool":           {expr: `branch`, errorOccurs: true, errorMsg: "expression is not evaluated to a bool"},
+		"invalidRegexp":     {expr: `branch =~ "("`, errorOccurs: true, errorMsg: "error parsing regexp: missing closing ): `(`"},
+		"syntaxError":       {expr: `branch == `, errorOccurs: true, errorMsg: "unexpected end of expression"},
+		"unexpectedToken":   {expr: `branch == "a" "b"`, errorOccurs: true, errorMsg: "unexpected token b at 14"},
+		"unterminated":      {expr: `branch == "a`, errorOccurs: true, errorMsg: "unterminated string at 10"},
+		"unknownCharacter":  {expr: `branch == $a`, errorOccurs: true, errorMsg: "unexpected character '$' at 10"},
+		"unclosedParenthes": {expr: `(branch == "a"`, errorOccurs: true, errorMsg: "unexpected end of expression"},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			result, err := Evaluate(c.expr, vars)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expected, result)
+			}
+		})
+	}
+}
+
+func TestExpression_Identifiers(t *testing.T) {
+	e, err := Parse(`branch =~ "^release-" && ("ok-to-test" in labels || author in [owner, "admin"]) && !draft`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"author", "branch", "draft", "labels", "owner"}, e.Identifiers())
+}
@@ -28,3 +28,25 @@ type UnauthorizedError struct {
 func (e *UnauthorizedError) Error() string {
 	return fmt.Sprintf("%s is not authorized for %s", e.User, e.Repo)
 }
+
+// CherryPickConflictError is an error struct for git clients, returned when the commit cannot be cherry-picked cleanly
+type CherryPickConflictError struct {
+	Commit string
+	Branch string
+}
+
+// Error returns error string
+func (e *CherryPickConflictError) Error() string {
+	return fmt.Sprintf("commit %s conflicts with branch %s", e.Commit, e.Branch)
+}
+
+// MilestoneNotFoundError is an error struct for git clients, returned when the milestone does not exist
+type MilestoneNotFoundError struct {
+	Milestone string
+	Repo      string
+}
+
+// Error returns error string
+func (e *MilestoneNotFoundError) Error() string {
+	return fmt.Sprintf("milestone %s does not exist in %s", e.Milestone, e.Repo)
+}
@@ -41,13 +41,23 @@ var (
 type Repo struct {
 	Webhooks     map[int]*git.WebhookEntry
 	UserCanWrite map[string]bool
+	UserIsAdmin  map[string]bool
 
-	PullRequests       map[int]*git.PullRequest
+	PullRequests       map[int]*git.PullRequest // The plain issues are also stored, as they share the ids
 	PullRequestDiffs   map[int]*git.Diff
 	PullRequestCommits map[int][]git.Commit
 	Commits            map[string][]git.Commit
+	CommitDiffs        map[string]*git.Diff // Key is 'base...head'
 	CommitStatuses     map[string][]git.CommitStatus
 	Comments           map[int][]git.IssueComment
+	Files              map[string]string   // Key is 'ref:path'
+	RequiredChecks     map[string][]string // Key is branch name
+	UpdatedBranches    []int               // IDs of the PRs whose branches are updated
+	DeletedBranches    []string
+	Approvers          map[int][]git.User // Key is PR id
+	Milestones         []string
+	CherryPicks        map[string][]string // Key is branch name
+	ConflictCommits    []string            // Commits which conflict when cherry-picked
 }
 
 // Client is a gitlab client struct
@@ -166,6 +176,28 @@ func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 	return nil
 }
 
+// CompareCommits gets diff between the base and the head commits
+func (c *Client) CompareCommits(base, head string) (*git.Diff, error) {
+	if Repos == nil {
+		return nil, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return nil, fmt.Errorf("404 no such repository")
+	}
+
+	if repo.CommitDiffs == nil {
+		return nil, fmt.Errorf("commit diffs not initialized")
+	}
+
+	diff, exist := repo.CommitDiffs[base+"..."+head]
+	if !exist {
+		return nil, fmt.Errorf("404 no such commits")
+	}
+
+	return diff, nil
+}
+
 // GetUserInfo gets a user's information
 func (c *Client) GetUserInfo(userName string) (*git.User, error) {
 	if Users == nil {
@@ -200,6 +232,19 @@ func (c *Client) CanUserWriteToRepo(user git.User) (bool, error) {
 	return privilege, nil
 }
 
+// IsUserRepoAdmin decides if the user is an admin of the repo. The users not in UserIsAdmin are not admins
+func (c *Client) IsUserRepoAdmin(user git.User) (bool, error) {
+	if Repos == nil {
+		return false, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return false, fmt.Errorf("404 no such repository")
+	}
+
+	return repo.UserIsAdmin[user.Name], nil
+}
+
 // RegisterComment registers comment to an issue
 func (c *Client) RegisterComment(_ git.IssueType, issueNo int, body string) error {
 	if Repos == nil {
@@ -296,6 +341,7 @@ func (c *Client) MergePullRequest(id int, _ string, _ git.MergeMethod, message s
 
 	repo.PullRequests[id].Mergeable = false
 	repo.PullRequests[id].State = git.PullRequestStateClosed
+	repo.PullRequests[id].Merged = true
 	commit := git.Commit{
 		SHA:     pr.Head.Sha,
 		Message: message,
@@ -310,6 +356,64 @@ func (c *Client) MergePullRequest(id int, _ string, _ git.MergeMethod, message s
 	return nil
 }
 
+// CreatePullRequest creates a pull request, from the head branch to the base branch
+func (c *Client) CreatePullRequest(title, body, head, base string) (*git.PullRequest, error) {
+	if Repos == nil {
+		return nil, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return nil, fmt.Errorf("404 no such repository")
+	}
+	if repo.PullRequests == nil {
+		repo.PullRequests = map[int]*git.PullRequest{}
+	}
+
+	id := 1
+	for prID := range repo.PullRequests {
+		if prID >= id {
+			id = prID + 1
+		}
+	}
+	pr := &git.PullRequest{
+		ID:    id,
+		Title: title,
+		Body:  body,
+		State: git.PullRequestStateOpen,
+		URL:   fmt.Sprintf("https://github.com/%s/pull/%d", c.IntegrationConfig.Spec.Git.Repository, id),
+		Base:  git.Base{Ref: base},
+		Head:  git.Head{Ref: head},
+	}
+	repo.PullRequests[id] = pr
+	return pr, nil
+}
+
+// UpdatePullRequestBranch updates the pull request's branch with the latest base branch
+func (c *Client) UpdatePullRequestBranch(id int, sha string) error {
+	if Repos == nil {
+		return fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return fmt.Errorf("404 no such repository")
+	}
+
+	pr, exist := repo.PullRequests[id]
+	if !exist {
+		return fmt.Errorf("404 no such pr")
+	}
+	if pr.Head.Sha != sha {
+		return fmt.Errorf("422 expected head sha didn't match current head ref")
+	}
+
+	if branch, ok := Branches[pr.Base.Ref]; ok {
+		pr.Base.Sha = branch.CommitID
+	}
+	repo.UpdatedBranches = append(repo.UpdatedBranches, id)
+
+	return nil
+}
+
 // GetPullRequestDiff gets diff of the pull request
 func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
 	if Repos == nil {
@@ -354,6 +458,38 @@ func (c *Client) ListPullRequestCommits(id int) ([]git.Commit, error) {
 	return commits, nil
 }
 
+// ListPullRequestApprovers lists the users who approved the pull request
+func (c *Client) ListPullRequestApprovers(id int) ([]git.User, error) {
+	if Repos == nil {
+		return nil, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return nil, fmt.Errorf("404 no such repository")
+	}
+
+	return repo.Approvers[id], nil
+}
+
+// CountUserPullRequests counts the pull requests of the repo opened by the user
+func (c *Client) CountUserPullRequests(user git.User) (int, error) {
+	if Repos == nil {
+		return 0, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return 0, fmt.Errorf("404 no such repository")
+	}
+
+	count := 0
+	for _, pr := range repo.PullRequests {
+		if pr.Author.Name == user.Name {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // ListLabels lists labels of pr id
 func (c *Client) ListLabels(id int) ([]git.IssueLabel, error) {
 	if Repos == nil {
@@ -396,6 +532,127 @@ func (c *Client) DeleteLabel(_ git.IssueType, id int, label string) error {
 	return DeleteLabel(c.IntegrationConfig.Spec.Git.Repository, id, label)
 }
 
+// AddAssignees adds the users to the assignees of the issue id
+func (c *Client) AddAssignees(_ git.IssueType, id int, users []string) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if !containsUser(pr.Assignees, u) {
+			pr.Assignees = append(pr.Assignees, git.User{Name: u})
+		}
+	}
+	return nil
+}
+
+// RemoveAssignees removes the users from the assignees of the issue id
+func (c *Client) RemoveAssignees(_ git.IssueType, id int, users []string) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	var assignees []git.User
+	for _, a := range pr.Assignees {
+		if !containsName(users, a.Name) {
+			assignees = append(assignees, a)
+		}
+	}
+	pr.Assignees = assignees
+	return nil
+}
+
+// SetMilestone sets the milestone of the issue id. The milestone is cleared if it's empty
+func (c *Client) SetMilestone(_ git.IssueType, id int, milestone string) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	if milestone != "" && !containsName(Repos[c.IntegrationConfig.Spec.Git.Repository].Milestones, milestone) {
+		return &git.MilestoneNotFoundError{Milestone: milestone, Repo: c.IntegrationConfig.Spec.Git.Repository}
+	}
+	pr.Milestone = milestone
+	return nil
+}
+
+// UpdateIssueState closes or reopens the issue id
+func (c *Client) UpdateIssueState(_ git.IssueType, id int, state git.PullRequestState) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	pr.State = state
+	return nil
+}
+
+// RequestReviewers requests reviews of the pull request id to the users
+func (c *Client) RequestReviewers(id int, users []string) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if !containsUser(pr.Reviewers, u) {
+			pr.Reviewers = append(pr.Reviewers, git.User{Name: u})
+		}
+	}
+	return nil
+}
+
+// RemoveReviewRequests removes the review requests of the pull request id from the users
+func (c *Client) RemoveReviewRequests(id int, users []string) error {
+	pr, err := c.getPullRequest(id)
+	if err != nil {
+		return err
+	}
+	var reviewers []git.User
+	for _, r := range pr.Reviewers {
+		if !containsName(users, r.Name) {
+			reviewers = append(reviewers, r)
+		}
+	}
+	pr.Reviewers = reviewers
+	return nil
+}
+
+func (c *Client) getPullRequest(id int) (*git.PullRequest, error) {
+	if Repos == nil {
+		return nil, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return nil, fmt.Errorf("404 no such repository")
+	}
+
+	if repo.PullRequests == nil {
+		return nil, fmt.Errorf("pull requests not initialized")
+	}
+
+	pr, exist := repo.PullRequests[id]
+	if !exist {
+		return nil, fmt.Errorf("404 no such PR")
+	}
+	return pr, nil
+}
+
+func containsUser(users []git.User, name string) bool {
+	for _, u := range users {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBranch returns branch info
 func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	if Branches == nil {
@@ -408,6 +665,92 @@ func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	return b, nil
 }
 
+// SetRequiredStatusChecks sets the required status checks of the branch
+func (c *Client) SetRequiredStatusChecks(branch string, contexts []string) error {
+	if Repos == nil {
+		return fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return fmt.Errorf("404 no such repository")
+	}
+
+	if repo.RequiredChecks == nil {
+		repo.RequiredChecks = map[string][]string{}
+	}
+	repo.RequiredChecks[branch] = contexts
+	return nil
+}
+
+// DeleteBranch deletes the branch
+func (c *Client) DeleteBranch(branch string) error {
+	if Repos == nil {
+		return fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return fmt.Errorf("404 no such repository")
+	}
+
+	delete(Branches, branch)
+	repo.DeletedBranches = append(repo.DeletedBranches, branch)
+	return nil
+}
+
+// CreateBranch creates a branch pointing to the commit sha
+func (c *Client) CreateBranch(branch, sha string) error {
+	if Branches == nil {
+		return fmt.Errorf("branches not initialized")
+	}
+	if _, exist := Branches[branch]; exist {
+		return fmt.Errorf("422 reference already exists")
+	}
+	Branches[branch] = &git.Branch{Name: branch, CommitID: sha}
+	return nil
+}
+
+// CherryPick cherry-picks the commits onto the branch
+func (c *Client) CherryPick(branch string, commits []string) error {
+	if Repos == nil {
+		return fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return fmt.Errorf("404 no such repository")
+	}
+	if _, exist := Branches[branch]; !exist {
+		return fmt.Errorf("404 no such branch (%s)", branch)
+	}
+
+	if repo.CherryPicks == nil {
+		repo.CherryPicks = map[string][]string{}
+	}
+	for _, sha := range commits {
+		if containsName(repo.ConflictCommits, sha) {
+			return &git.CherryPickConflictError{Commit: sha, Branch: branch}
+		}
+		repo.CherryPicks[branch] = append(repo.CherryPicks[branch], sha)
+	}
+	return nil
+}
+
+// GetFile gets the content of the file at the ref
+func (c *Client) GetFile(path, ref string) ([]byte, error) {
+	if Repos == nil {
+		return nil, fmt.Errorf("repos not initialized")
+	}
+	repo, repoExist := Repos[c.IntegrationConfig.Spec.Git.Repository]
+	if !repoExist {
+		return nil, fmt.Errorf("404 no such repository")
+	}
+
+	content, exist := repo.Files[ref+":"+path]
+	if !exist {
+		return nil, fmt.Errorf("404 no such file (%s) at %s", path, ref)
+	}
+	return []byte(content), nil
+}
+
 // DeleteLabel deletes label from a pull request
 func DeleteLabel(repoName string, id int, label string) error {
 	if Repos == nil {
@@ -36,10 +36,15 @@ type Client interface {
 	ListCommitStatuses(ref string) ([]CommitStatus, error)
 	SetCommitStatus(sha string, status CommitStatus) error
 
+	// Commits
+
+	CompareCommits(base, head string) (*Diff, error)
+
 	// Users
 
 	GetUserInfo(user string) (*User, error)
 	CanUserWriteToRepo(user User) (bool, error)
+	IsUserRepoAdmin(user User) (bool, error)
 
 	// Comments
 
@@ -51,8 +56,12 @@ type Client interface {
 	ListPullRequests(onlyOpen bool) ([]PullRequest, error)
 	GetPullRequest(id int) (*PullRequest, error)
 	MergePullRequest(id int, sha string, method MergeMethod, message string) error
+	UpdatePullRequestBranch(id int, sha string) error
+	CreatePullRequest(title, body, head, base string) (*PullRequest, error)
 	GetPullRequestDiff(id int) (*Diff, error)
 	ListPullRequestCommits(id int) ([]Commit, error)
+	ListPullRequestApprovers(id int) ([]User, error)
+	CountUserPullRequests(user User) (int, error)
 
 	// Issue Labels
 
@@ -60,9 +69,35 @@ type Client interface {
 	ListLabels(id int) ([]IssueLabel, error)
 	DeleteLabel(issueType IssueType, id int, label string) error
 
+	// Issue Assignees
+
+	AddAssignees(issueType IssueType, id int, users []string) error
+	RemoveAssignees(issueType IssueType, id int, users []string) error
+
+	// Issue Milestone
+
+	SetMilestone(issueType IssueType, id int, milestone string) error
+
+	// Issue State
+
+	UpdateIssueState(issueType IssueType, id int, state PullRequestState) error
+
+	// Pull Request Reviewers
+
+	RequestReviewers(id int, users []string) error
+	RemoveReviewRequests(id int, users []string) error
+
 	// Branch
 
 	GetBranch(branch string) (*Branch, error)
+	SetRequiredStatusChecks(branch string, contexts []string) error
+	DeleteBranch(branch string) error
+	CreateBranch(branch, sha string) error
+	CherryPick(branch string, commits []string) error
+
+	// Contents
+
+	GetFile(path, ref string) ([]byte, error)
 }
 
 // IssueType is a type of the issue
@@ -83,6 +118,10 @@ const (
 	CommitStatusStateFailure = CommitStatusState("failure")
 	CommitStatusStateError   = CommitStatusState("error")
 	CommitStatusStatePending = CommitStatusState("pending")
+
+	// CommitStatusStateSkipped is a state of the skipped jobs. It's reported as a success if the git server does not
+	// support it, not to block the pull requests
+	CommitStatusStateSkipped = CommitStatusState("skipped")
 )
 
 // FakeSha is a fake SHA for a commit
@@ -97,4 +136,5 @@ type MergeMethod string
 const (
 	MergeMethodSquash = MergeMethod("squash")
 	MergeMethodMerge  = MergeMethod("merge")
+	MergeMethodRebase = MergeMethod("rebase")
 )
@@ -37,6 +37,8 @@ const (
 	EventTypeIssueComment             = EventType("issue_comment")
 	EventTypePullRequestReview        = EventType("pull_request_review")
 	EventTypePullRequestReviewComment = EventType("pull_request_review_comment")
+	EventTypeRelease                  = EventType("release")
+	EventTypeCheckRun                 = EventType("check_run")
 )
 
 // Pull Request states
@@ -53,6 +55,9 @@ const (
 	PullRequestActionSynchronize = PullRequestAction("synchronize")
 	PullRequestActionLabeled     = PullRequestAction("labeled")
 	PullRequestActionUnlabeled   = PullRequestAction("unlabeled")
+	PullRequestActionReady       = PullRequestAction("ready_for_review")
+	PullRequestActionDraft       = PullRequestAction("converted_to_draft")
+	PullRequestActionEdited      = PullRequestAction("edited")
 )
 
 // Pull Request review state
@@ -71,12 +76,38 @@ type Webhook struct {
 	Push         *Push
 	PullRequest  *PullRequest
 	IssueComment *IssueComment
+	Release      *Release
+	CheckRun     *CheckRun
 }
 
 // Push is a common structure for push events
 type Push struct {
-	Ref string
-	Sha string
+	Ref        string
+	Sha        string
+	HeadCommit Commit
+
+	// Before is a sha of the ref before the push. It is FakeSha if the ref is newly created
+	Before string
+}
+
+// Release is a common structure for release events
+type Release struct {
+	Name string
+	Tag  string
+	Sha  string
+}
+
+// CheckRun is a common structure for check run events, which are sent when a check run is re-requested from the UI
+type CheckRun struct {
+	// Name is a name of the check run, i.e., the context of the commit status
+	Name string
+	Sha  string
+
+	// Branch is the head branch of the check run's commit
+	Branch string
+
+	// PullRequest is the pull request the check run is run for. It is nil if the check run is run for a push
+	PullRequest *PullRequest
 }
 
 // PullRequest is a common structure for pull request events
@@ -91,9 +122,31 @@ type PullRequest struct {
 	Head      Head
 	Labels    []IssueLabel
 	Mergeable bool
+	Draft     bool
+	Merged    bool
+
+	// Conflicted is true if the git server decided the PR has conflicts. Both Mergeable and Conflicted are false if it's
+	// not decided yet, e.g., GitHub is still computing it or the PR is listed without the mergeability
+	Conflicted bool
+
+	// Body is the description of the PR
+	Body string
+
+	// Fork specifies if the head branch is in a forked repository, not in the base repository
+	Fork bool
+
+	// Milestone is the title of the milestone of the PR. It's empty if no milestone is set
+	Milestone string
+	Assignees []User
+
+	// Reviewers are the users who are requested to review the PR
+	Reviewers []User
 
 	// LabelChanged
 	LabelChanged []IssueLabel
+
+	// UpdatedAt is the time of the last activity of the PR. It's only reported when getting or listing the PRs
+	UpdatedAt *metav1.Time
 }
 
 // Diff is a diff between commits or of a pull-request
@@ -138,9 +191,55 @@ type Comment struct {
 	CreatedAt *metav1.Time
 }
 
-// Issue is an issue related to the Comment
+// Issue is an issue related to the Comment. PullRequest is set if it's a pull request, otherwise the other fields are
+// set for the plain issue
 type Issue struct {
 	PullRequest *PullRequest
+
+	ID     int
+	State  PullRequestState
+	Author User
+	URL    string
+}
+
+// GetType returns the type of the issue
+func (i *Issue) GetType() IssueType {
+	if i.PullRequest != nil {
+		return IssueTypePullRequest
+	}
+	return IssueTypeIssue
+}
+
+// GetID returns the id of the pull request or the plain issue
+func (i *Issue) GetID() int {
+	if i.PullRequest != nil {
+		return i.PullRequest.ID
+	}
+	return i.ID
+}
+
+// GetState returns the state of the pull request or the plain issue
+func (i *Issue) GetState() PullRequestState {
+	if i.PullRequest != nil {
+		return i.PullRequest.State
+	}
+	return i.State
+}
+
+// GetAuthor returns the author of the pull request or the plain issue
+func (i *Issue) GetAuthor() User {
+	if i.PullRequest != nil {
+		return i.PullRequest.Author
+	}
+	return i.Author
+}
+
+// GetURL returns the url of the pull request or the plain issue
+func (i *Issue) GetURL() string {
+	if i.PullRequest != nil {
+		return i.PullRequest.URL
+	}
+	return i.URL
 }
 
 // Repository is a repository of the git
@@ -180,6 +279,35 @@ type CommitStatus struct {
 	State       CommitStatusState
 	Description string
 	TargetURL   string
+
+	// Summary is a markdown summary of the status. It is only reported for the check runs
+	Summary string
+
+	// Annotations are the annotations at the lines of the files. They are only reported for the check runs
+	Annotations []Annotation
+
+	// UpdatedAt is the time the status is updated at. It's only reported when listing the statuses, and is nil if the
+	// git server doesn't report it
+	UpdatedAt *metav1.Time
+}
+
+// AnnotationLevel is a level of the annotation
+type AnnotationLevel string
+
+// AnnotationLevels
+const (
+	AnnotationLevelNotice  = AnnotationLevel("notice")
+	AnnotationLevelWarning = AnnotationLevel("warning")
+	AnnotationLevelFailure = AnnotationLevel("failure")
+)
+
+// Annotation is an annotation at a line of a file
+type Annotation struct {
+	Path    string
+	Line    int
+	Column  int
+	Level   AnnotationLevel
+	Message string
 }
 
 // Branch is a branch info
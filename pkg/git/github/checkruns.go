@@ -0,0 +1,171 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// maxCheckRunAnnotations is the maximum number of annotations of a check run request
+const maxCheckRunAnnotations = 50
+
+// Check run statuses and conclusions
+const (
+	checkRunStatusInProgress = "in_progress"
+	checkRunStatusCompleted  = "completed"
+
+	checkRunConclusionSuccess   = "success"
+	checkRunConclusionFailure   = "failure"
+	checkRunConclusionCancelled = "cancelled"
+	checkRunConclusionNeutral   = "neutral"
+	checkRunConclusionSkipped   = "skipped"
+	checkRunConclusionStale     = "stale"
+)
+
+// setCheckRun sets the status as a check run named after the status' context
+// The latest check run of the commit is updated if it's not completed yet, otherwise a new check run is created, so
+// that each run of the job is shown as a separate check run
+func (c *Client) setCheckRun(sha string, status git.CommitStatus) error {
+	latest, err := c.getLatestCheckRun(sha, status.Context)
+	if err != nil {
+		return err
+	}
+
+	body := convertCommitStatusToCheckRun(status)
+	apiURL := c.IntegrationConfig.Spec.Git.GetAPIUrl() + "/repos/" + c.IntegrationConfig.Spec.Git.Repository + "/check-runs"
+	if latest != nil && latest.Status != checkRunStatusCompleted {
+		_, _, err = c.requestHTTP(http.MethodPatch, fmt.Sprintf("%s/%d", apiURL, latest.ID), body)
+		return err
+	}
+
+	body.Name = status.Context
+	body.HeadSha = sha
+	_, _, err = c.requestHTTP(http.MethodPost, apiURL, body)
+	return err
+}
+
+// getLatestCheckRun gets the latest check run of the commit with the name. It returns nil if there is none
+func (c *Client) getLatestCheckRun(sha, name string) (*CheckRunResponse, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/commits/%s/check-runs?filter=latest&check_name=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, sha, url.QueryEscape(name))
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CheckRunsResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return nil, err
+	}
+	for i := range resp.CheckRuns {
+		if resp.CheckRuns[i].Name == name {
+			return &resp.CheckRuns[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// listCheckRuns lists the latest check runs of the commit, as commit statuses
+func (c *Client) listCheckRuns(ref string) ([]git.CommitStatus, error) {
+	apiURL := c.IntegrationConfig.Spec.Git.GetAPIUrl() + "/repos/" + c.IntegrationConfig.Spec.Git.Repository + "/commits/" + ref + "/check-runs?filter=latest"
+
+	var runs []CheckRunResponse
+	tlsConfig := c.IntegrationConfig.GetTLSConfig()
+
+	err := git.GetPaginatedRequest(apiURL, tlsConfig, c.header, func() interface{} {
+		return &CheckRunsResponse{}
+	}, func(i interface{}) {
+		runs = append(runs, i.(*CheckRunsResponse).CheckRuns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []git.CommitStatus
+	for _, r := range runs {
+		statuses = append(statuses, convertCheckRunToCommitStatus(r))
+	}
+	return statuses, nil
+}
+
+// convertCommitStatusToCheckRun converts the commit status to a check run request
+// The description is used as the title of the output, as the merger reads the base sha from it
+func convertCommitStatusToCheckRun(status git.CommitStatus) *CheckRunRequest {
+	body := &CheckRunRequest{DetailsURL: status.TargetURL, Status: checkRunStatusCompleted}
+	switch status.State {
+	case git.CommitStatusStateSuccess:
+		body.Conclusion = checkRunConclusionSuccess
+	case git.CommitStatusStateSkipped:
+		body.Conclusion = checkRunConclusionSkipped
+	case git.CommitStatusStateFailure:
+		body.Conclusion = checkRunConclusionFailure
+	case git.CommitStatusStateError:
+		body.Conclusion = checkRunConclusionCancelled
+	default:
+		body.Status = checkRunStatusInProgress
+	}
+
+	output := &CheckRunOutput{Title: status.Description, Summary: status.Summary}
+	if output.Title == "" {
+		output.Title = status.Context
+	}
+	if output.Summary == "" {
+		output.Summary = output.Title
+	}
+	for i, a := range status.Annotations {
+		if i >= maxCheckRunAnnotations {
+			break
+		}
+		annotation := CheckRunAnnotation{Path: a.Path, StartLine: a.Line, EndLine: a.Line, AnnotationLevel: string(a.Level), Message: a.Message}
+		if a.Column > 0 {
+			annotation.StartColumn = a.Column
+			annotation.EndColumn = a.Column
+		}
+		if annotation.AnnotationLevel == "" {
+			annotation.AnnotationLevel = string(git.AnnotationLevelWarning)
+		}
+		output.Annotations = append(output.Annotations, annotation)
+	}
+	body.Output = output
+	return body
+}
+
+// convertCheckRunToCommitStatus converts the check run to a commit status
+func convertCheckRunToCommitStatus(run CheckRunResponse) git.CommitStatus {
+	status := git.CommitStatus{Context: run.Name, Description: run.Output.Title, TargetURL: run.DetailsURL, State: git.CommitStatusStatePending}
+	status.UpdatedAt = run.StartedAt
+	if run.Status != checkRunStatusCompleted {
+		return status
+	}
+	if run.CompletedAt != nil {
+		status.UpdatedAt = run.CompletedAt
+	}
+	switch run.Conclusion {
+	case checkRunConclusionSuccess, checkRunConclusionNeutral, checkRunConclusionSkipped:
+		status.State = git.CommitStatusStateSuccess
+	case checkRunConclusionCancelled, checkRunConclusionStale:
+		status.State = git.CommitStatusStateError
+	default:
+		status.State = git.CommitStatusStateFailure
+	}
+	return status
+}
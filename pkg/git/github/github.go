@@ -19,9 +19,12 @@ package github
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -72,6 +75,10 @@ func (c *Client) ParseWebhook(header http.Header, jsonString []byte) (*git.Webho
 		return c.parsePullRequestReviewWebhook(jsonString)
 	case git.EventTypePullRequestReviewComment:
 		return c.parsePullRequestReviewCommentWebhook(jsonString)
+	case git.EventTypeRelease:
+		return c.parseReleaseWebhook(jsonString)
+	case git.EventTypeCheckRun:
+		return c.parseCheckRunWebhook(jsonString)
 	}
 	return nil, nil
 }
@@ -133,7 +140,18 @@ func (c *Client) DeleteWebhook(id int) error {
 }
 
 // ListCommitStatuses lists commit status of the specific commit
+// If spec.git.checkRuns is set, the latest check runs are listed first, taking precedence over the commit statuses
+// with the same contexts
 func (c *Client) ListCommitStatuses(ref string) ([]git.CommitStatus, error) {
+	var resp []git.CommitStatus
+	if c.IntegrationConfig.Spec.Git.CheckRuns {
+		runs, err := c.listCheckRuns(ref)
+		if err != nil {
+			return nil, err
+		}
+		resp = runs
+	}
+
 	apiURL := c.IntegrationConfig.Spec.Git.GetAPIUrl() + "/repos/" + c.IntegrationConfig.Spec.Git.Repository + "/commits/" + ref + "/statuses"
 
 	var statuses []CommitStatusResponse
@@ -150,8 +168,10 @@ func (c *Client) ListCommitStatuses(ref string) ([]git.CommitStatus, error) {
 
 	// Temp map for filtering duplicated contexts
 	tmp := map[string]struct{}{}
+	for _, s := range resp {
+		tmp[s.Context] = struct{}{}
+	}
 
-	var resp []git.CommitStatus
 	for _, s := range statuses {
 		_, exist := tmp[s.Context]
 		if exist {
@@ -163,6 +183,7 @@ func (c *Client) ListCommitStatuses(ref string) ([]git.CommitStatus, error) {
 			State:       git.CommitStatusState(s.State),
 			Description: s.Description,
 			TargetURL:   s.TargetURL,
+			UpdatedAt:   s.UpdatedAt,
 		})
 	}
 
@@ -170,6 +191,7 @@ func (c *Client) ListCommitStatuses(ref string) ([]git.CommitStatus, error) {
 }
 
 // SetCommitStatus sets commit status for the specific commit
+// If spec.git.checkRuns is set, it's set as a check run instead
 func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 	var commitStatusBody CommitStatusRequest
 
@@ -178,9 +200,17 @@ func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 		return nil
 	}
 
+	if c.IntegrationConfig.Spec.Git.CheckRuns {
+		return c.setCheckRun(sha, status)
+	}
+
 	apiURL := c.IntegrationConfig.Spec.Git.GetAPIUrl() + "/repos/" + c.IntegrationConfig.Spec.Git.Repository + "/statuses/" + sha
 
 	commitStatusBody.State = string(status.State)
+	// Commit statuses do not have a skipped state
+	if status.State == git.CommitStatusStateSkipped {
+		commitStatusBody.State = string(git.CommitStatusStateSuccess)
+	}
 	commitStatusBody.TargetURL = status.TargetURL
 	commitStatusBody.Description = status.Description
 	commitStatusBody.Context = status.Context
@@ -192,6 +222,22 @@ func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 	return nil
 }
 
+// CompareCommits gets diff between the base and the head commits
+func (c *Client) CompareCommits(base, head string) (*git.Diff, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/compare/%s...%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, base, head)
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CompareResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return nil, err
+	}
+
+	return &git.Diff{Changes: convertDiffFiles(resp.Files)}, nil
+}
+
 // GetUserInfo gets a user's information
 func (c *Client) GetUserInfo(userName string) (*git.User, error) {
 	// userName is string!
@@ -216,20 +262,36 @@ func (c *Client) GetUserInfo(userName string) (*git.User, error) {
 
 // CanUserWriteToRepo decides if the user has write permission on the repo
 func (c *Client) CanUserWriteToRepo(user git.User) (bool, error) {
+	permission, err := c.getUserPermission(user)
+	if err != nil {
+		return false, err
+	}
+	return permission == "admin" || permission == "write", nil
+}
+
+// IsUserRepoAdmin decides if the user has admin permission on the repo
+func (c *Client) IsUserRepoAdmin(user git.User) (bool, error) {
+	permission, err := c.getUserPermission(user)
+	if err != nil {
+		return false, err
+	}
+	return permission == "admin", nil
+}
+
+func (c *Client) getUserPermission(user git.User) (string, error) {
 	// userName is string!
 	apiURL := fmt.Sprintf("%s/repos/%s/collaborators/%s/permission", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, user.Name)
 
 	result, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	var permission UserPermission
 	if err := json.Unmarshal(result, &permission); err != nil {
-		return false, err
+		return "", err
 	}
-
-	return permission.Permission == "admin" || permission.Permission == "write", nil
+	return permission.Permission, nil
 }
 
 // RegisterComment registers comment to an issue
@@ -251,12 +313,14 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 	prCommentApiUrl := fmt.Sprintf("%s/repos/%s/pulls/%d/comments", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, issueNo)
 	prReviewApiUrl := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, issueNo)
 
-	raw, _, err := c.requestHTTP(http.MethodGet, issueApiUrl, nil)
-	if err != nil {
-		return nil, err
-	}
+	// Comments and reviews are paginated
+	tlsConfig := c.IntegrationConfig.GetTLSConfig()
 	var issueComments []CommentResponse
-	if err := json.Unmarshal(raw, &issueComments); err != nil {
+	if err := git.GetPaginatedRequest(issueApiUrl, tlsConfig, c.header, func() interface{} {
+		return &[]CommentResponse{}
+	}, func(i interface{}) {
+		issueComments = append(issueComments, *i.(*[]CommentResponse)...)
+	}); err != nil {
 		return nil, err
 	}
 	for _, issueComment := range issueComments {
@@ -265,15 +329,16 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 				Body:      issueComment.Body,
 				CreatedAt: issueComment.CreatedAt,
 			},
+			Author: git.User{ID: issueComment.User.ID, Name: issueComment.User.Name},
 		})
 	}
 
-	raw, _, err = c.requestHTTP(http.MethodGet, prCommentApiUrl, nil)
-	if err != nil {
-		return nil, err
-	}
 	var prComments []CommentResponse
-	if err = json.Unmarshal(raw, &prComments); err != nil {
+	if err := git.GetPaginatedRequest(prCommentApiUrl, tlsConfig, c.header, func() interface{} {
+		return &[]CommentResponse{}
+	}, func(i interface{}) {
+		prComments = append(prComments, *i.(*[]CommentResponse)...)
+	}); err != nil {
 		return nil, err
 	}
 	for _, prComment := range prComments {
@@ -282,15 +347,16 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 				Body:      prComment.Body,
 				CreatedAt: prComment.CreatedAt,
 			},
+			Author: git.User{ID: prComment.User.ID, Name: prComment.User.Name},
 		})
 	}
 
-	raw, _, err = c.requestHTTP(http.MethodGet, prReviewApiUrl, nil)
-	if err != nil {
-		return nil, err
-	}
 	var prReviews []ReviewResponse
-	if err = json.Unmarshal(raw, &prReviews); err != nil {
+	if err := git.GetPaginatedRequest(prReviewApiUrl, tlsConfig, c.header, func() interface{} {
+		return &[]ReviewResponse{}
+	}, func(i interface{}) {
+		prReviews = append(prReviews, *i.(*[]ReviewResponse)...)
+	}); err != nil {
 		return nil, err
 	}
 	for _, prReview := range prReviews {
@@ -299,6 +365,7 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 				Body:      prReview.Body,
 				CreatedAt: prReview.SubmittedAt,
 			},
+			Author:      git.User{ID: prReview.User.ID, Name: prReview.User.Name},
 			ReviewState: prReview.State,
 		})
 	}
@@ -375,19 +442,53 @@ func (c *Client) MergePullRequest(id int, sha string, method git.MergeMethod, me
 	return nil
 }
 
-// GetPullRequestDiff gets diff of the pull request
-func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
-	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/files", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
-	rawDiffs, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+// UpdatePullRequestBranch merges the latest base branch into the pull request's branch, if its head is still sha
+func (c *Client) UpdatePullRequestBranch(id int, sha string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/update-branch", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodPut, apiURL, &UpdateBranchRequest{ExpectedHeadSha: sha})
 	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreatePullRequest creates a pull request, from the head branch to the base branch
+func (c *Client) CreatePullRequest(title, body, head, base string) (*git.PullRequest, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository)
+
+	raw, _, err := c.requestHTTP(http.MethodPost, apiURL, CreatePullRequestBody{Title: title, Body: body, Head: head, Base: base})
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &PullRequest{}
+	if err := json.Unmarshal(raw, pr); err != nil {
 		return nil, err
 	}
+	return convertPullRequestToShared(pr), nil
+}
 
+// GetPullRequestDiff gets diff of the pull request
+func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/files", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	// Files are paginated (30 files per page by default)
 	diffs := DiffFiles{}
-	if err := json.Unmarshal(rawDiffs, &diffs); err != nil {
+	err := git.GetPaginatedRequest(apiURL, c.IntegrationConfig.GetTLSConfig(), c.header, func() interface{} {
+		return &DiffFiles{}
+	}, func(i interface{}) {
+		diffs = append(diffs, *i.(*DiffFiles)...)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	return &git.Diff{Changes: convertDiffFiles(diffs)}, nil
+}
+
+func convertDiffFiles(diffs DiffFiles) []git.Change {
 	var changes []git.Change
 	for _, d := range diffs {
 		prevName := d.PrevFilename
@@ -402,8 +503,7 @@ func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
 			Changes:     d.Changes,
 		})
 	}
-
-	return &git.Diff{Changes: changes}, nil
+	return changes
 }
 
 // ListPullRequestCommits lists commits list of a pull request
@@ -451,6 +551,58 @@ func (c *Client) SetLabel(_ git.IssueType, id int, label string) error {
 	return nil
 }
 
+// ListPullRequestApprovers lists the users whose latest reviews of the pull request approve it
+func (c *Client) ListPullRequestApprovers(id int) ([]git.User, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	var reviews []ReviewResponse
+	if err := json.Unmarshal(raw, &reviews); err != nil {
+		return nil, err
+	}
+
+	// Reviews are listed in chronological order
+	approved := map[string]git.User{}
+	for _, r := range reviews {
+		switch strings.ToLower(string(r.State)) {
+		case string(git.PullRequestReviewStateApproved):
+			approved[r.User.Name] = git.User{ID: r.User.ID, Name: r.User.Name}
+		case string(git.PullRequestReviewStateUnapproved), "dismissed":
+			delete(approved, r.User.Name)
+		}
+	}
+
+	var approvers []git.User
+	for _, u := range approved {
+		approvers = append(approvers, u)
+	}
+	sort.Slice(approvers, func(i, j int) bool {
+		return approvers[i].Name < approvers[j].Name
+	})
+
+	return approvers, nil
+}
+
+// CountUserPullRequests counts the pull requests of the repo opened by the user, regardless of their states
+func (c *Client) CountUserPullRequests(user git.User) (int, error) {
+	query := fmt.Sprintf("repo:%s type:pr author:%s", c.IntegrationConfig.Spec.Git.Repository, user.Name)
+	apiURL := fmt.Sprintf("%s/search/issues?q=%s&per_page=1", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(query))
+
+	data, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result := &SearchIssuesResponse{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
+
 // ListLabels lists labels of pr id
 func (c *Client) ListLabels(id int) ([]git.IssueLabel, error) {
 	apiURL := fmt.Sprintf("%s/repos/%s/issues/%d/labels", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
@@ -479,6 +631,84 @@ func (c *Client) DeleteLabel(_ git.IssueType, id int, label string) error {
 	return nil
 }
 
+// AddAssignees adds the users to the assignees of the issue id
+// The users who cannot be assigned to the issue are ignored by GitHub
+func (c *Client) AddAssignees(_ git.IssueType, id int, users []string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/issues/%d/assignees", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodPost, apiURL, AssigneesBody{Assignees: users})
+	return err
+}
+
+// RemoveAssignees removes the users from the assignees of the issue id
+func (c *Client) RemoveAssignees(_ git.IssueType, id int, users []string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/issues/%d/assignees", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodDelete, apiURL, AssigneesBody{Assignees: users})
+	return err
+}
+
+// SetMilestone sets the milestone of the issue id. The milestone is cleared if it's empty
+func (c *Client) SetMilestone(_ git.IssueType, id int, milestone string) error {
+	body := MilestoneBody{}
+	if milestone != "" {
+		number, err := c.getMilestoneNumber(milestone)
+		if err != nil {
+			return err
+		}
+		body.Milestone = &number
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/issues/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodPatch, apiURL, body)
+	return err
+}
+
+// UpdateIssueState closes or reopens the issue id
+func (c *Client) UpdateIssueState(_ git.IssueType, id int, state git.PullRequestState) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/issues/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodPatch, apiURL, StateBody{State: string(state)})
+	return err
+}
+
+// getMilestoneNumber gets the number of the milestone with the title
+func (c *Client) getMilestoneNumber(title string) (int, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/milestones?state=all&per_page=100", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	var milestones []Milestone
+	if err := json.Unmarshal(raw, &milestones); err != nil {
+		return 0, err
+	}
+	for _, m := range milestones {
+		if m.Title == title {
+			return m.Number, nil
+		}
+	}
+	return 0, &git.MilestoneNotFoundError{Milestone: title, Repo: c.IntegrationConfig.Spec.Git.Repository}
+}
+
+// RequestReviewers requests reviews of the pull request id to the users
+func (c *Client) RequestReviewers(id int, users []string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodPost, apiURL, ReviewersBody{Reviewers: users})
+	return err
+}
+
+// RemoveReviewRequests removes the review requests of the pull request id from the users
+func (c *Client) RemoveReviewRequests(id int, users []string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, id)
+
+	_, _, err := c.requestHTTP(http.MethodDelete, apiURL, ReviewersBody{Reviewers: users})
+	return err
+}
+
 // GetBranch gets branch info
 func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	apiURL := fmt.Sprintf("%s/repos/%s/branches/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, branch)
@@ -496,12 +726,227 @@ func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	return &git.Branch{Name: resp.Name, CommitID: resp.Commit.Sha}, nil
 }
 
+// SetRequiredStatusChecks sets the required status checks of the branch
+// Only the contexts of the required status checks are updated if the branch is already protected, keeping the other
+// settings (e.g., strict), otherwise the branch is protected only with the required status checks
+func (c *Client) SetRequiredStatusChecks(branch string, contexts []string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/branches/%s/protection", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, url.PathEscape(branch))
+
+	checks := &RequiredStatusChecksBody{Contexts: contexts}
+	if checks.Contexts == nil {
+		checks.Contexts = []string{}
+	}
+
+	_, _, err := c.requestHTTP(http.MethodPatch, apiURL+"/required_status_checks", checks)
+	if err == nil || !strings.Contains(err.Error(), "code 404") {
+		return err
+	}
+
+	// The branch is not protected yet
+	strict := false
+	checks.Strict = &strict
+	_, _, err = c.requestHTTP(http.MethodPut, apiURL, &BranchProtectionBody{RequiredStatusChecks: checks})
+	return err
+}
+
+// DeleteBranch deletes the branch
+func (c *Client) DeleteBranch(branch string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/git/refs/heads/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, escapePath(branch))
+
+	_, _, err := c.requestHTTP(http.MethodDelete, apiURL, nil)
+	return err
+}
+
+// CreateBranch creates a branch pointing to the commit sha
+func (c *Client) CreateBranch(branch, sha string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/git/refs", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository)
+
+	_, _, err := c.requestHTTP(http.MethodPost, apiURL, RefBody{Ref: "refs/heads/" + branch, SHA: sha})
+	return err
+}
+
+// CherryPick cherry-picks the commits onto the branch, in order
+// GitHub does not support cherry-picking, so it's emulated using the git database API. For each commit, a temporary
+// commit having the branch's tree is created on top of the commit's parent, and the commit is merged into it. The
+// merged tree is the branch's tree with the changes of the commit applied, which is then committed onto the branch
+func (c *Client) CherryPick(branch string, commits []string) error {
+	b, err := c.GetBranch(branch)
+	if err != nil {
+		return err
+	}
+	head, err := c.getGitCommit(b.CommitID)
+	if err != nil {
+		return err
+	}
+	headSHA, headTree := head.SHA, head.Tree.SHA
+
+	for _, sha := range commits {
+		commit, err := c.getGitCommit(sha)
+		if err != nil {
+			return err
+		}
+		if len(commit.Parents) != 1 {
+			return fmt.Errorf("commit %s is a merge commit, which cannot be cherry-picked", sha)
+		}
+
+		// Temporary sibling commit
+		sibling, err := c.createGitCommit(CreateGitCommitBody{Message: "Temporary commit for cherry-picking " + sha, Tree: headTree, Parents: []string{commit.Parents[0].SHA}})
+		if err != nil {
+			return err
+		}
+		if err := c.updateBranchRef(branch, sibling.SHA); err != nil {
+			return err
+		}
+
+		tree, err := c.mergeIntoBranch(branch, sha)
+		if err != nil {
+			// Restore the branch
+			_ = c.updateBranchRef(branch, headSHA)
+			return err
+		}
+		if tree == "" {
+			tree = headTree
+		}
+
+		picked, err := c.createGitCommit(CreateGitCommitBody{Message: commit.Message, Tree: tree, Parents: []string{headSHA}, Author: commit.Author})
+		if err != nil {
+			return err
+		}
+		if err := c.updateBranchRef(branch, picked.SHA); err != nil {
+			return err
+		}
+		headSHA, headTree = picked.SHA, tree
+	}
+	return nil
+}
+
+func (c *Client) getGitCommit(sha string) (*GitCommit, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/git/commits/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, sha)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	commit := &GitCommit{}
+	if err := json.Unmarshal(raw, commit); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+func (c *Client) createGitCommit(body CreateGitCommitBody) (*GitCommit, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/git/commits", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository)
+
+	raw, _, err := c.requestHTTP(http.MethodPost, apiURL, body)
+	if err != nil {
+		return nil, err
+	}
+	commit := &GitCommit{}
+	if err := json.Unmarshal(raw, commit); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+func (c *Client) updateBranchRef(branch, sha string) error {
+	apiURL := fmt.Sprintf("%s/repos/%s/git/refs/heads/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, escapePath(branch))
+
+	_, _, err := c.requestHTTP(http.MethodPatch, apiURL, RefBody{SHA: sha, Force: true})
+	return err
+}
+
+// mergeIntoBranch merges the commit into the branch and returns the merged tree. It returns an empty tree if there is
+// nothing to merge
+func (c *Client) mergeIntoBranch(branch, sha string) (string, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/merges", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository)
+
+	raw, _, err := c.requestHTTP(http.MethodPost, apiURL, MergeBody{Base: branch, Head: sha, CommitMessage: "Merge " + sha})
+	if err != nil {
+		if strings.Contains(err.Error(), ", code 409,") {
+			return "", &git.CherryPickConflictError{Commit: sha, Branch: branch}
+		}
+		return "", err
+	}
+	if len(raw) == 0 {
+		return "", nil
+	}
+	resp := &MergeResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return "", err
+	}
+	return resp.Commit.Tree.SHA, nil
+}
+
+// GetFile gets the content of the file at the ref (i.e., branch, tag, or sha)
+func (c *Client) GetFile(path, ref string) ([]byte, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/contents/%s?ref=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, escapePath(path), url.QueryEscape(ref))
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ContentResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return nil, err
+	}
+	if resp.Type != "file" {
+		return nil, fmt.Errorf("%s is not a file but a %s", path, resp.Type)
+	}
+	if resp.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported encoding %s of %s", resp.Encoding, path)
+	}
+
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(resp.Content, "\n", ""))
+}
+
+// escapePath escapes each segment of the path
+func escapePath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// getCommitSha gets a commit sha of the ref (i.e., branch, tag, or sha)
+func (c *Client) getCommitSha(ref string) (string, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/commits/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), c.IntegrationConfig.Spec.Git.Repository, ref)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp := &CommitResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return "", err
+	}
+
+	return resp.SHA, nil
+}
+
 func convertPullRequestToShared(pr *PullRequest) *git.PullRequest {
 	var labels []git.IssueLabel
 	for _, l := range pr.Labels {
 		labels = append(labels, git.IssueLabel{Name: l.Name})
 	}
 
+	var assignees []git.User
+	for _, a := range pr.Assignees {
+		assignees = append(assignees, git.User{ID: a.ID, Name: a.Name})
+	}
+
+	var reviewers []git.User
+	for _, r := range pr.Reviewers {
+		reviewers = append(reviewers, git.User{ID: r.ID, Name: r.Name})
+	}
+
+	milestone := ""
+	if pr.Milestone != nil {
+		milestone = pr.Milestone.Title
+	}
+
 	return &git.PullRequest{
 		ID:    pr.Number,
 		Title: pr.Title,
@@ -510,11 +955,20 @@ func convertPullRequestToShared(pr *PullRequest) *git.PullRequest {
 			ID:   pr.User.ID,
 			Name: pr.User.Name,
 		},
-		URL:       pr.URL,
-		Base:      git.Base{Ref: pr.Base.Ref, Sha: pr.Base.Sha},
-		Head:      git.Head{Ref: pr.Head.Ref, Sha: pr.Head.Sha},
-		Labels:    labels,
-		Mergeable: pr.Mergeable,
+		URL:        pr.URL,
+		Base:       git.Base{Ref: pr.Base.Ref, Sha: pr.Base.Sha},
+		Head:       git.Head{Ref: pr.Head.Ref, Sha: pr.Head.Sha},
+		Labels:     labels,
+		Mergeable:  pr.Mergeable != nil && *pr.Mergeable,
+		Conflicted: pr.Mergeable != nil && !*pr.Mergeable,
+		Draft:      pr.Draft,
+		Merged:     pr.MergedAt != "",
+		Body:       pr.Body,
+		Fork:       pr.Head.Repo.Name != pr.Base.Repo.Name,
+		Milestone:  milestone,
+		Assignees:  assignees,
+		Reviewers:  reviewers,
+		UpdatedAt:  pr.UpdatedAt,
 	}
 }
 
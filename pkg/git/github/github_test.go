@@ -17,10 +17,13 @@
 package github
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,6 +31,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -41,6 +45,7 @@ const (
 	samplePRFiles       = "[{\"filename\":\"Makefile\",\"additions\":1,\"deletions\":1,\"changes\":2,\"patch\":\"@@ -1,5 +1,5 @@\\n # Current Operator version\\n-VERSION ?= v0.3.0\\n+VERSION ?= v0.3.1\\n REGISTRY ?= tmaxcloudck\\n \\n # Image URL to use all building/pushing image targets\"},{\"filename\":\"config/release.yaml\",\"additions\":2,\"deletions\":2,\"changes\":4,\"patch\":\"@@ -82,7 +82,7 @@ spec:\\n       containers:\\n       - command:\\n         - /controller\\n-        image: tmaxcloudck/cicd-operator:v0.3.0\\n+        image: tmaxcloudck/cicd-operator:v0.3.1\\n         imagePullPolicy: Always\\n         name: manager\\n         resources:\\n@@ -145,7 +145,7 @@ spec:\\n       containers:\\n         - command:\\n             - /blocker\\n-          image: tmaxcloudck/cicd-blocker:v0.3.0\\n+          image: tmaxcloudck/cicd-blocker:v0.3.1\\n           imagePullPolicy: Always\\n           name: manager\\n           resources:\"},{\"filename\":\"docs/installation.md\",\"additions\":1,\"deletions\":1,\"changes\":2,\"patch\":\"@@ -12,7 +12,7 @@ This guides to install CI/CD operator. The contents are as follows.\\n ## Installing CI/CD Operator\\n 1. Run the following command to install CI/CD operator  \\n    ```bash\\n-   VERSION=v0.3.0\\n+   VERSION=v0.3.1\\n    kubectl apply -f https://raw.githubusercontent.com/tmax-cloud/cicd-operator/$VERSION/config/release.yaml\\n    ```\\n 2. Enable `CustomTask` feature, disable `Affinity Assistant`\"}]"
 	samplePRCommits     = "[\n  {\n    \"sha\": \"bfa929712952e60d5ad5d3b73376f6ba392f8b50\",\n    \"commit\": {\n      \"author\": {\n        \"name\": \"Sunghyun Kim\",\n        \"email\": \"cqbqdd11519@gmail.com\",\n        \"date\": \"2021-08-24T07:16:13Z\"\n      },\n      \"committer\": {\n        \"name\": \"Sunghyun Kim\",\n        \"email\": \"cqbqdd11519@gmail.com\",\n        \"date\": \"2021-08-25T04:34:17Z\"\n      },\n      \"message\": \"[fix] Batch pull requests properly\\n\\nfix #270\\n\\n- Fix critical typo\\n- Remove a PR from the batch right away after merging it.\\n  This is to avoid an infinite error, when a PR is already merged, but\\n  is still in the CurrentBatch in the next loop (because of one of the\\n  next PRs fails to merge)\"\n    }\n  }\n]"
 	sampleLabelLists    = "[\n  {\n    \"id\": 3048006488,\n    \"node_id\": \"MDU6TGFiZWwzMDQ4MDA2NDg4\",\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/labels/approved\",\n    \"name\": \"approved\",\n    \"color\": \"ededed\",\n    \"default\": false,\n    \"description\": null\n  },\n  {\n    \"id\": 3187077209,\n    \"node_id\": \"MDU6TGFiZWwzMTg3MDc3MjA5\",\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/labels/size/L\",\n    \"name\": \"size/L\",\n    \"color\": \"ededed\",\n    \"default\": false,\n    \"description\": null\n  }\n]"
+	sampleFileContent   = "{\"type\":\"file\",\"encoding\":\"base64\",\"name\":\"config.yaml\",\"path\":\".cicd/config.yaml\",\"content\":\"cHJlU3VibWl0OgotIG5hbWU6IHRl\\nc3QKICBpbWFnZTogZ29sYW5nOjEuMTcK\\n\"}"
 	samplePRComments    = "[\n  {\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771113606\",\n    \"pull_request_review_id\": 834849190,\n    \"id\": 771113606,\n    \"node_id\": \"PRRC_kwDOEm6Tx84t9kKG\",\n    \"diff_hunk\": \"@@ -20,89 +20,10 @@ import (\\n \\t\\\"testing\\\"\\n \\n \\t\\\"github.com/stretchr/testify/require\\\"\\n-\\ttektonv1beta1 \\\"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1\\\"\\n \\t\\\"github.com/tmax-cloud/cicd-operator/internal/configs\\\"\\n \\tmetav1 \\\"k8s.io/apimachinery/pkg/apis/meta/v1\\\"\\n )\\n \\n-func TestConvertToTektonParamSpecs(t *testing.T) {\",\n    \"path\": \"api/v1/integrationjob_types_test.go\",\n    \"position\": 9,\n    \"original_position\": 9,\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\",\n    \"original_commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\",\n    \"user\": {\n      \"login\": \"eddy-kor-92\",\n      \"id\": 33279734,\n      \"node_id\": \"MDQ6VXNlcjMzMjc5NzM0\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/33279734?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/eddy-kor-92\",\n      \"html_url\": \"https://github.com/eddy-kor-92\",\n      \"followers_url\": \"https://api.github.com/users/eddy-kor-92/followers\",\n      \"following_url\": \"https://api.github.com/users/eddy-kor-92/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/eddy-kor-92/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/eddy-kor-92/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/eddy-kor-92/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/eddy-kor-92/orgs\",\n      \"repos_url\": \"https://api.github.com/users/eddy-kor-92/repos\",\n      \"events_url\": \"https://api.github.com/users/eddy-kor-92/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/eddy-kor-92/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"이 Test 함수가 원래 integrationconfig_types_test에 있는게 맞는거죠? 그래서 옮기신거죠?\",\n    \"created_at\": \"2021-12-17T05:29:08Z\",\n    \"updated_at\": \"2021-12-17T05:31:38Z\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771113606\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"NONE\",\n    \"_links\": {\n      \"self\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771113606\"\n      },\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771113606\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"reactions\": {\n      \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771113606/reactions\",\n      \"total_count\": 0,\n      \"+1\": 0,\n      \"-1\": 0,\n      \"laugh\": 0,\n      \"hooray\": 0,\n      \"confused\": 0,\n      \"heart\": 0,\n      \"rocket\": 0,\n      \"eyes\": 0\n    },\n    \"start_line\": null,\n    \"original_start_line\": null,\n    \"start_side\": null,\n    \"line\": 28,\n    \"original_line\": 28,\n    \"side\": \"LEFT\"\n  },\n  {\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771114018\",\n    \"pull_request_review_id\": 834849190,\n    \"id\": 771114018,\n    \"node_id\": \"PRRC_kwDOEm6Tx84t9kQi\",\n    \"diff_hunk\": \"@@ -127,18 +130,33 @@ func (p *pipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.P\\n \\t\\t\\t\\tResources:  specResources,\\n \\t\\t\\t\\tTasks:      tasks,\\n \\t\\t\\t\\tWorkspaces: workspaceDefs,\\n-\\t\\t\\t\\tParams:     cicdv1.ConvertToTektonParamSpecs(job.Spec.ParamConfig.ParamDefine),\\n+\\t\\t\\t\\tParams:     paramDefine,\\n \\t\\t\\t},\\n \\t\\t\\tPodTemplate: job.Spec.PodTemplate,\\n \\t\\t\\tWorkspaces:  job.Spec.Workspaces,\\n \\t\\t\\tTimeout: &metav1.Duration{\\n \\t\\t\\t\\tDuration: job.Spec.Timeout.Duration,\\n \\t\\t\\t},\\n-\\t\\t\\tParams: cicdv1.ConvertToTektonParams(job.Spec.ParamConfig.ParamValue),\\n+\\t\\t\\tParams: paramValue,\\n \\t\\t},\\n \\t}, nil\\n }\\n \\n+func getParams(job *cicdv1.IntegrationJob) ([]tektonv1beta1.ParamSpec, []tektonv1beta1.Param) {\",\n    \"path\": \"pkg/pipelinemanager/pipelinemanager.go\",\n    \"position\": 28,\n    \"original_position\": 28,\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\",\n    \"original_commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\",\n    \"user\": {\n      \"login\": \"eddy-kor-92\",\n      \"id\": 33279734,\n      \"node_id\": \"MDQ6VXNlcjMzMjc5NzM0\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/33279734?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/eddy-kor-92\",\n      \"html_url\": \"https://github.com/eddy-kor-92\",\n      \"followers_url\": \"https://api.github.com/users/eddy-kor-92/followers\",\n      \"following_url\": \"https://api.github.com/users/eddy-kor-92/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/eddy-kor-92/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/eddy-kor-92/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/eddy-kor-92/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/eddy-kor-92/orgs\",\n      \"repos_url\": \"https://api.github.com/users/eddy-kor-92/repos\",\n      \"events_url\": \"https://api.github.com/users/eddy-kor-92/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/eddy-kor-92/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"nil 체크를 하는게 이 함수의 목적인거 같은데, parameter를 직접 사용하는 함수에서 parameter validation을 하는게 더 낫지 않을까요? ConvertToTektonParamSpecs랑 ConvertToTektonParams 함수에서요.\",\n    \"created_at\": \"2021-12-17T05:30:31Z\",\n    \"updated_at\": \"2021-12-17T05:31:38Z\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771114018\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"NONE\",\n    \"_links\": {\n      \"self\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771114018\"\n      },\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771114018\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"reactions\": {\n      \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771114018/reactions\",\n      \"total_count\": 0,\n      \"+1\": 0,\n      \"-1\": 0,\n      \"laugh\": 0,\n      \"hooray\": 0,\n      \"confused\": 0,\n      \"heart\": 0,\n      \"rocket\": 0,\n      \"eyes\": 0\n    },\n    \"start_line\": null,\n    \"original_start_line\": null,\n    \"start_side\": null,\n    \"line\": 145,\n    \"original_line\": 145,\n    \"side\": \"RIGHT\"\n  },\n  {\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771115644\",\n    \"pull_request_review_id\": 834851875,\n    \"id\": 771115644,\n    \"node_id\": \"PRRC_kwDOEm6Tx84t9kp8\",\n    \"diff_hunk\": \"@@ -20,89 +20,10 @@ import (\\n \\t\\\"testing\\\"\\n \\n \\t\\\"github.com/stretchr/testify/require\\\"\\n-\\ttektonv1beta1 \\\"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1\\\"\\n \\t\\\"github.com/tmax-cloud/cicd-operator/internal/configs\\\"\\n \\tmetav1 \\\"k8s.io/apimachinery/pkg/apis/meta/v1\\\"\\n )\\n \\n-func TestConvertToTektonParamSpecs(t *testing.T) {\",\n    \"path\": \"api/v1/integrationjob_types_test.go\",\n    \"position\": 9,\n    \"original_position\": 9,\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\",\n    \"original_commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\",\n    \"user\": {\n      \"login\": \"changjjjjjjj\",\n      \"id\": 56624551,\n      \"node_id\": \"MDQ6VXNlcjU2NjI0NTUx\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/56624551?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/changjjjjjjj\",\n      \"html_url\": \"https://github.com/changjjjjjjj\",\n      \"followers_url\": \"https://api.github.com/users/changjjjjjjj/followers\",\n      \"following_url\": \"https://api.github.com/users/changjjjjjjj/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/changjjjjjjj/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/changjjjjjjj/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/changjjjjjjj/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/changjjjjjjj/orgs\",\n      \"repos_url\": \"https://api.github.com/users/changjjjjjjj/repos\",\n      \"events_url\": \"https://api.github.com/users/changjjjjjjj/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/changjjjjjjj/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"네 잘못 들어가있어서 옮겼습니다\",\n    \"created_at\": \"2021-12-17T05:36:07Z\",\n    \"updated_at\": \"2021-12-17T05:36:07Z\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771115644\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"COLLABORATOR\",\n    \"_links\": {\n      \"self\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771115644\"\n      },\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771115644\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"reactions\": {\n      \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771115644/reactions\",\n      \"total_count\": 0,\n      \"+1\": 0,\n      \"-1\": 0,\n      \"laugh\": 0,\n      \"hooray\": 0,\n      \"confused\": 0,\n      \"heart\": 0,\n      \"rocket\": 0,\n      \"eyes\": 0\n    },\n    \"start_line\": null,\n    \"original_start_line\": null,\n    \"start_side\": null,\n    \"line\": 28,\n    \"original_line\": 28,\n    \"side\": \"LEFT\",\n    \"in_reply_to_id\": 771113606\n  },\n  {\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771122149\",\n    \"pull_request_review_id\": 834860063,\n    \"id\": 771122149,\n    \"node_id\": \"PRRC_kwDOEm6Tx84t9mPl\",\n    \"diff_hunk\": \"@@ -127,18 +130,33 @@ func (p *pipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.P\\n \\t\\t\\t\\tResources:  specResources,\\n \\t\\t\\t\\tTasks:      tasks,\\n \\t\\t\\t\\tWorkspaces: workspaceDefs,\\n-\\t\\t\\t\\tParams:     cicdv1.ConvertToTektonParamSpecs(job.Spec.ParamConfig.ParamDefine),\\n+\\t\\t\\t\\tParams:     paramDefine,\\n \\t\\t\\t},\\n \\t\\t\\tPodTemplate: job.Spec.PodTemplate,\\n \\t\\t\\tWorkspaces:  job.Spec.Workspaces,\\n \\t\\t\\tTimeout: &metav1.Duration{\\n \\t\\t\\t\\tDuration: job.Spec.Timeout.Duration,\\n \\t\\t\\t},\\n-\\t\\t\\tParams: cicdv1.ConvertToTektonParams(job.Spec.ParamConfig.ParamValue),\\n+\\t\\t\\tParams: paramValue,\\n \\t\\t},\\n \\t}, nil\\n }\\n \\n+func getParams(job *cicdv1.IntegrationJob) ([]tektonv1beta1.ParamSpec, []tektonv1beta1.Param) {\",\n    \"path\": \"pkg/pipelinemanager/pipelinemanager.go\",\n    \"position\": 28,\n    \"original_position\": 28,\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\",\n    \"original_commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\",\n    \"user\": {\n      \"login\": \"changjjjjjjj\",\n      \"id\": 56624551,\n      \"node_id\": \"MDQ6VXNlcjU2NjI0NTUx\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/56624551?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/changjjjjjjj\",\n      \"html_url\": \"https://github.com/changjjjjjjj\",\n      \"followers_url\": \"https://api.github.com/users/changjjjjjjj/followers\",\n      \"following_url\": \"https://api.github.com/users/changjjjjjjj/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/changjjjjjjj/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/changjjjjjjj/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/changjjjjjjj/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/changjjjjjjj/orgs\",\n      \"repos_url\": \"https://api.github.com/users/changjjjjjjj/repos\",\n      \"events_url\": \"https://api.github.com/users/changjjjjjjj/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/changjjjjjjj/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"paramConfig nil 은 체크해야 해서 함수는 남겨뒀고 생각해보니까 paramDefine이랑 paramValue는  getParams에서 nil 체크 안해도 돼서 삭제했습니다.\",\n    \"created_at\": \"2021-12-17T05:57:08Z\",\n    \"updated_at\": \"2021-12-17T05:57:08Z\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771122149\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"COLLABORATOR\",\n    \"_links\": {\n      \"self\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771122149\"\n      },\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#discussion_r771122149\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"reactions\": {\n      \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/comments/771122149/reactions\",\n      \"total_count\": 0,\n      \"+1\": 0,\n      \"-1\": 0,\n      \"laugh\": 0,\n      \"hooray\": 0,\n      \"confused\": 0,\n      \"heart\": 0,\n      \"rocket\": 0,\n      \"eyes\": 0\n    },\n    \"start_line\": null,\n    \"original_start_line\": null,\n    \"start_side\": null,\n    \"line\": 145,\n    \"original_line\": 145,\n    \"side\": \"RIGHT\",\n    \"in_reply_to_id\": 771114018\n  }\n]"
 	samplePRReviews     = "[\n  {\n    \"id\": 834849190,\n    \"node_id\": \"PRR_kwDOEm6Tx84xwsmm\",\n    \"user\": {\n      \"login\": \"eddy-kor-92\",\n      \"id\": 33279734,\n      \"node_id\": \"MDQ6VXNlcjMzMjc5NzM0\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/33279734?u=bed3bf0df30f21a34b1d88dac4bdea053d2edafa&v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/eddy-kor-92\",\n      \"html_url\": \"https://github.com/eddy-kor-92\",\n      \"followers_url\": \"https://api.github.com/users/eddy-kor-92/followers\",\n      \"following_url\": \"https://api.github.com/users/eddy-kor-92/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/eddy-kor-92/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/eddy-kor-92/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/eddy-kor-92/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/eddy-kor-92/orgs\",\n      \"repos_url\": \"https://api.github.com/users/eddy-kor-92/repos\",\n      \"events_url\": \"https://api.github.com/users/eddy-kor-92/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/eddy-kor-92/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"\",\n    \"state\": \"COMMENTED\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834849190\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"NONE\",\n    \"_links\": {\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834849190\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"submitted_at\": \"2021-12-17T05:31:38Z\",\n    \"commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\"\n  },\n  {\n    \"id\": 834851875,\n    \"node_id\": \"PRR_kwDOEm6Tx84xwtQj\",\n    \"user\": {\n      \"login\": \"changjjjjjjj\",\n      \"id\": 56624551,\n      \"node_id\": \"MDQ6VXNlcjU2NjI0NTUx\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/56624551?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/changjjjjjjj\",\n      \"html_url\": \"https://github.com/changjjjjjjj\",\n      \"followers_url\": \"https://api.github.com/users/changjjjjjjj/followers\",\n      \"following_url\": \"https://api.github.com/users/changjjjjjjj/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/changjjjjjjj/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/changjjjjjjj/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/changjjjjjjj/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/changjjjjjjj/orgs\",\n      \"repos_url\": \"https://api.github.com/users/changjjjjjjj/repos\",\n      \"events_url\": \"https://api.github.com/users/changjjjjjjj/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/changjjjjjjj/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"\",\n    \"state\": \"COMMENTED\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834851875\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"COLLABORATOR\",\n    \"_links\": {\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834851875\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"submitted_at\": \"2021-12-17T05:36:07Z\",\n    \"commit_id\": \"654761e79f45e62ef8ca4d94c47cf7adc1756122\"\n  },\n  {\n    \"id\": 834860063,\n    \"node_id\": \"PRR_kwDOEm6Tx84xwvQf\",\n    \"user\": {\n      \"login\": \"changjjjjjjj\",\n      \"id\": 56624551,\n      \"node_id\": \"MDQ6VXNlcjU2NjI0NTUx\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/56624551?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/changjjjjjjj\",\n      \"html_url\": \"https://github.com/changjjjjjjj\",\n      \"followers_url\": \"https://api.github.com/users/changjjjjjjj/followers\",\n      \"following_url\": \"https://api.github.com/users/changjjjjjjj/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/changjjjjjjj/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/changjjjjjjj/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/changjjjjjjj/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/changjjjjjjj/orgs\",\n      \"repos_url\": \"https://api.github.com/users/changjjjjjjj/repos\",\n      \"events_url\": \"https://api.github.com/users/changjjjjjjj/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/changjjjjjjj/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"\",\n    \"state\": \"COMMENTED\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834860063\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"COLLABORATOR\",\n    \"_links\": {\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834860063\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"submitted_at\": \"2021-12-17T05:57:08Z\",\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\"\n  },\n  {\n    \"id\": 834871251,\n    \"node_id\": \"PRR_kwDOEm6Tx84xwx_T\",\n    \"user\": {\n      \"login\": \"yxzzzxh\",\n      \"id\": 36444454,\n      \"node_id\": \"MDQ6VXNlcjM2NDQ0NDU0\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/36444454?u=bbc82e004d2e79434274c1fc4ac97c1d2b6f249e&v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/yxzzzxh\",\n      \"html_url\": \"https://github.com/yxzzzxh\",\n      \"followers_url\": \"https://api.github.com/users/yxzzzxh/followers\",\n      \"following_url\": \"https://api.github.com/users/yxzzzxh/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/yxzzzxh/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/yxzzzxh/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/yxzzzxh/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/yxzzzxh/orgs\",\n      \"repos_url\": \"https://api.github.com/users/yxzzzxh/repos\",\n      \"events_url\": \"https://api.github.com/users/yxzzzxh/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/yxzzzxh/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"body\": \"/approve\",\n    \"state\": \"COMMENTED\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834871251\",\n    \"pull_request_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\",\n    \"author_association\": \"CONTRIBUTOR\",\n    \"_links\": {\n      \"html\": {\n        \"href\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#pullrequestreview-834871251\"\n      },\n      \"pull_request\": {\n        \"href\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/pulls/324\"\n      }\n    },\n    \"submitted_at\": \"2021-12-17T06:21:13Z\",\n    \"commit_id\": \"d3b2006b7a2ab28268b248429bc215854a497d24\"\n  }\n]"
 	sampleIssueComments = "[\n  {\n    \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/issues/comments/996468306\",\n    \"html_url\": \"https://github.com/tmax-cloud/cicd-operator/pull/324#issuecomment-996468306\",\n    \"issue_url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/issues/324\",\n    \"id\": 996468306,\n    \"node_id\": \"IC_kwDOEm6Tx847ZOZS\",\n    \"user\": {\n      \"login\": \"tmax-cloud-bot\",\n      \"id\": 76757421,\n      \"node_id\": \"MDQ6VXNlcjc2NzU3NDIx\",\n      \"avatar_url\": \"https://avatars.githubusercontent.com/u/76757421?v=4\",\n      \"gravatar_id\": \"\",\n      \"url\": \"https://api.github.com/users/tmax-cloud-bot\",\n      \"html_url\": \"https://github.com/tmax-cloud-bot\",\n      \"followers_url\": \"https://api.github.com/users/tmax-cloud-bot/followers\",\n      \"following_url\": \"https://api.github.com/users/tmax-cloud-bot/following{/other_user}\",\n      \"gists_url\": \"https://api.github.com/users/tmax-cloud-bot/gists{/gist_id}\",\n      \"starred_url\": \"https://api.github.com/users/tmax-cloud-bot/starred{/owner}{/repo}\",\n      \"subscriptions_url\": \"https://api.github.com/users/tmax-cloud-bot/subscriptions\",\n      \"organizations_url\": \"https://api.github.com/users/tmax-cloud-bot/orgs\",\n      \"repos_url\": \"https://api.github.com/users/tmax-cloud-bot/repos\",\n      \"events_url\": \"https://api.github.com/users/tmax-cloud-bot/events{/privacy}\",\n      \"received_events_url\": \"https://api.github.com/users/tmax-cloud-bot/received_events\",\n      \"type\": \"User\",\n      \"site_admin\": false\n    },\n    \"created_at\": \"2021-12-17T06:21:16Z\",\n    \"updated_at\": \"2021-12-17T06:21:16Z\",\n    \"author_association\": \"NONE\",\n    \"body\": \"[APPROVE ALERT]\\n\\nUser `yxzzzxh` approved this pull request!\",\n    \"reactions\": {\n      \"url\": \"https://api.github.com/repos/tmax-cloud/cicd-operator/issues/comments/996468306/reactions\",\n      \"total_count\": 0,\n      \"+1\": 0,\n      \"-1\": 0,\n      \"laugh\": 0,\n      \"hooray\": 0,\n      \"confused\": 0,\n      \"heart\": 0,\n      \"rocket\": 0,\n      \"eyes\": 0\n    },\n    \"performed_via_github_app\": null\n  }\n]"
@@ -97,6 +102,7 @@ func TestClient_ListCommitStatuses(t *testing.T) {
 	assert.Equal(t, 1, len(statuses))
 	assert.Equal(t, "test-1", statuses[0].Context)
 	assert.Equal(t, "success", string(statuses[0].State))
+	assert.Equal(t, time.Date(2021, 4, 12, 8, 37, 32, 0, time.UTC), statuses[0].UpdatedAt.UTC())
 }
 
 func TestClient_ListComments(t *testing.T) {
@@ -107,7 +113,9 @@ func TestClient_ListComments(t *testing.T) {
 
 	comments, err := c.ListComments(5)
 	require.NoError(t, err)
-	require.Len(t, comments, 9)
+	// Two pages of the issue comments
+	require.Len(t, comments, 10)
+	require.Equal(t, git.User{ID: 36444454, Name: "yxzzzxh"}, comments[9].Author)
 }
 
 func TestClient_ListPullRequests(t *testing.T) {
@@ -128,6 +136,23 @@ func TestClient_ListPullRequests(t *testing.T) {
 	assert.Equal(t, "newnew", prs[1].Title, "Title")
 }
 
+func TestClient_GetPullRequest(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := c.GetPullRequest(25)
+	require.NoError(t, err)
+	require.Equal(t, 25, pr.ID)
+	require.Equal(t, "v0.1.0", pr.Milestone)
+	require.Equal(t, []git.User{{ID: 6166781, Name: "cqbqdd11519"}}, pr.Assignees)
+	require.False(t, pr.Fork)
+	require.Equal(t, "Depends-On: tmax-cloud/cicd-test2#3", pr.Body)
+	require.True(t, pr.Merged)
+	require.Equal(t, "2021-04-13T04:54:17Z", pr.UpdatedAt.UTC().Format(time.RFC3339))
+}
+
 func TestClient_GetPullRequestDiff(t *testing.T) {
 	c, err := testEnv()
 	if err != nil {
@@ -136,7 +161,8 @@ func TestClient_GetPullRequestDiff(t *testing.T) {
 
 	diff, err := c.GetPullRequestDiff(5)
 	require.NoError(t, err)
-	require.Len(t, diff.Changes, 3)
+	// Two pages of the files
+	require.Len(t, diff.Changes, 6)
 	require.Equal(t, "Makefile", diff.Changes[0].Filename)
 	require.Equal(t, "Makefile", diff.Changes[0].OldFilename)
 	require.Equal(t, 1, diff.Changes[0].Additions)
@@ -154,6 +180,20 @@ func TestClient_GetPullRequestDiff(t *testing.T) {
 	require.Equal(t, 2, diff.Changes[2].Changes)
 }
 
+func TestClient_CompareCommits(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := c.CompareCommits("3ec1c4b4f6b6a1bb7ccea5e6b4e8e0b4cd2ec8a7", "bfa929712952e60d5ad5d3b73376f6ba392f8b50")
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 3)
+	require.Equal(t, "Makefile", diff.Changes[0].Filename)
+	require.Equal(t, "config/release.yaml", diff.Changes[1].Filename)
+	require.Equal(t, "docs/installation.md", diff.Changes[2].Filename)
+}
+
 func TestClient_ListPullRequestCommits(t *testing.T) {
 	c, err := testEnv()
 	if err != nil {
@@ -171,6 +211,36 @@ func TestClient_ListPullRequestCommits(t *testing.T) {
 	require.Equal(t, "cqbqdd11519@gmail.com", commits[0].Committer.Email)
 }
 
+func TestClient_ListPullRequestApprovers(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approvers, err := c.ListPullRequestApprovers(30)
+	require.NoError(t, err)
+	require.Equal(t, []git.User{{ID: 1, Name: "user1"}, {ID: 3, Name: "user3"}}, approvers)
+
+	approvers, err = c.ListPullRequestApprovers(324)
+	require.NoError(t, err)
+	require.Empty(t, approvers)
+}
+
+func TestClient_CountUserPullRequests(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := c.CountUserPullRequests(git.User{ID: 1, Name: "user1"})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	count, err = c.CountUserPullRequests(git.User{ID: 2, Name: "user2"})
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
 func TestClient_ListLabels(t *testing.T) {
 	c, err := testEnv()
 	if err != nil {
@@ -184,6 +254,311 @@ func TestClient_ListLabels(t *testing.T) {
 	require.Equal(t, "size/L", labels[1].Name)
 }
 
+func TestClient_GetFile(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := c.GetFile(".cicd/config.yaml", "main")
+	require.NoError(t, err)
+	require.Equal(t, "preSubmit:\n- name: test\n  image: golang:1.17\n", string(content))
+
+	_, err = c.GetFile(".cicd/config.yaml", "dev")
+	require.Error(t, err)
+}
+
+var updateBranchRequests []string
+
+func TestClient_UpdatePullRequestBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateBranchRequests = nil
+	require.NoError(t, c.UpdatePullRequestBranch(25, "head-sha"))
+	require.Equal(t, []string{`PUT /repos/tmax-cloud/cicd-test/pulls/25/update-branch {"expected_head_sha":"head-sha"}`}, updateBranchRequests)
+
+	// Head is changed
+	require.Error(t, c.UpdatePullRequestBranch(25, "old-sha"))
+}
+
+var deleteBranchRequests []string
+
+func TestClient_DeleteBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleteBranchRequests = nil
+	require.NoError(t, c.DeleteBranch("feat/new"))
+	require.Equal(t, []string{"DELETE /repos/tmax-cloud/cicd-test/git/refs/heads/feat/new"}, deleteBranchRequests)
+}
+
+var assigneesRequests []string
+
+func TestClient_AddAssignees(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assigneesRequests = nil
+	require.NoError(t, c.AddAssignees(git.IssueTypePullRequest, 25, []string{"user1", "user2"}))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/issues/25/assignees {"assignees":["user1","user2"]}`}, assigneesRequests)
+}
+
+func TestClient_RemoveAssignees(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assigneesRequests = nil
+	require.NoError(t, c.RemoveAssignees(git.IssueTypePullRequest, 25, []string{"user1"}))
+	require.Equal(t, []string{`DELETE /repos/tmax-cloud/cicd-test/issues/25/assignees {"assignees":["user1"]}`}, assigneesRequests)
+}
+
+var issueRequests []string
+
+func TestClient_SetMilestone(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issueRequests = nil
+	require.NoError(t, c.SetMilestone(git.IssueTypePullRequest, 25, "v0.2.0"))
+	require.NoError(t, c.SetMilestone(git.IssueTypePullRequest, 25, ""))
+	require.Equal(t, []string{
+		`PATCH /repos/tmax-cloud/cicd-test/issues/25 {"milestone":2}`,
+		`PATCH /repos/tmax-cloud/cicd-test/issues/25 {"milestone":null}`,
+	}, issueRequests)
+
+	// Unknown milestone
+	err = c.SetMilestone(git.IssueTypePullRequest, 25, "v0.3.0")
+	require.Error(t, err)
+	_, ok := err.(*git.MilestoneNotFoundError)
+	require.True(t, ok)
+}
+
+func TestClient_UpdateIssueState(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issueRequests = nil
+	require.NoError(t, c.UpdateIssueState(git.IssueTypePullRequest, 25, git.PullRequestStateClosed))
+	require.NoError(t, c.UpdateIssueState(git.IssueTypePullRequest, 25, git.PullRequestStateOpen))
+	require.Equal(t, []string{
+		`PATCH /repos/tmax-cloud/cicd-test/issues/25 {"state":"closed"}`,
+		`PATCH /repos/tmax-cloud/cicd-test/issues/25 {"state":"open"}`,
+	}, issueRequests)
+}
+
+var reviewersRequests []string
+
+func TestClient_RequestReviewers(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewersRequests = nil
+	require.NoError(t, c.RequestReviewers(25, []string{"user1", "user2"}))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/pulls/25/requested_reviewers {"reviewers":["user1","user2"]}`}, reviewersRequests)
+}
+
+func TestClient_RemoveReviewRequests(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewersRequests = nil
+	require.NoError(t, c.RemoveReviewRequests(25, []string{"user1"}))
+	require.Equal(t, []string{`DELETE /repos/tmax-cloud/cicd-test/pulls/25/requested_reviewers {"reviewers":["user1"]}`}, reviewersRequests)
+}
+
+var cherryPickRequests []string
+
+func TestClient_CreateBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cherryPickRequests = nil
+	require.NoError(t, c.CreateBranch("cherry-pick-25-to-release-1.0", "head-sha"))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/git/refs {"ref":"refs/heads/cherry-pick-25-to-release-1.0","sha":"head-sha"}`}, cherryPickRequests)
+}
+
+func TestClient_CherryPick(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cherryPickRequests = nil
+	require.NoError(t, c.CherryPick("release-1.0", []string{"pick-1"}))
+	require.Equal(t, []string{
+		`POST /repos/tmax-cloud/cicd-test/git/commits {"message":"Temporary commit for cherry-picking pick-1","tree":"head-tree","parents":["pick-1-parent"]}`,
+		`PATCH /repos/tmax-cloud/cicd-test/git/refs/heads/release-1.0 {"sha":"new-commit-1","force":true}`,
+		`POST /repos/tmax-cloud/cicd-test/merges {"base":"release-1.0","head":"pick-1","commit_message":"Merge pick-1"}`,
+		`POST /repos/tmax-cloud/cicd-test/git/commits {"message":"Pick 1","tree":"merged-tree","parents":["head-sha"],"author":{"name":"user1","email":"user1@test.com","date":"2021-04-13T04:54:16Z"}}`,
+		`PATCH /repos/tmax-cloud/cicd-test/git/refs/heads/release-1.0 {"sha":"new-commit-2","force":true}`,
+	}, cherryPickRequests)
+
+	// Conflict - the branch is restored
+	cherryPickRequests = nil
+	err = c.CherryPick("release-1.0", []string{"conflict-1"})
+	require.Error(t, err)
+	_, ok := err.(*git.CherryPickConflictError)
+	require.True(t, ok)
+	require.Equal(t, `PATCH /repos/tmax-cloud/cicd-test/git/refs/heads/release-1.0 {"sha":"head-sha","force":true}`, cherryPickRequests[len(cherryPickRequests)-1])
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := c.CreatePullRequest("[release-1.0] newnew", "Cherry-pick", "cherry-pick-25-to-release-1.0", "release-1.0")
+	require.NoError(t, err)
+	require.Equal(t, 26, pr.ID)
+	require.Equal(t, "[release-1.0] newnew", pr.Title)
+	require.Equal(t, "release-1.0", pr.Base.Ref)
+	require.Equal(t, "cherry-pick-25-to-release-1.0", pr.Head.Ref)
+}
+
+var protectionRequests []string
+
+func TestClient_SetRequiredStatusChecks(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Protected branch
+	protectionRequests = nil
+	require.NoError(t, c.SetRequiredStatusChecks("master", []string{"lint", "test"}))
+	require.Equal(t, []string{`PATCH {"contexts":["lint","test"]}`}, protectionRequests)
+
+	// Not protected branch
+	protectionRequests = nil
+	require.NoError(t, c.SetRequiredStatusChecks("dev", nil))
+	require.Equal(t, []string{`PUT {"required_status_checks":{"strict":false,"contexts":[]},"enforce_admins":false,"required_pull_request_reviews":null,"restrictions":null}`}, protectionRequests)
+}
+
+var checkRunRequests []string
+
+func TestClient_SetCommitStatus_checkRuns(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.IntegrationConfig.Spec.Git.CheckRuns = true
+
+	// The check run in progress is updated
+	checkRunRequests = nil
+	require.NoError(t, c.SetCommitStatus("running", git.CommitStatus{
+		Context:     "lint",
+		State:       git.CommitStatusStateFailure,
+		Description: "Jobs failed",
+		TargetURL:   "http://report/lint",
+		Summary:     "Job `lint` is **failure**",
+		Annotations: []git.Annotation{{Path: "a.go", Line: 3, Column: 5, Level: git.AnnotationLevelFailure, Message: "unused variable"}, {Path: "b.go", Line: 7, Message: "missing doc"}},
+	}))
+	require.Equal(t, []string{`PATCH /repos/tmax-cloud/cicd-test/check-runs/11 {"details_url":"http://report/lint","status":"completed","conclusion":"failure","output":{"title":"Jobs failed","summary":"Job ` + "`lint`" + ` is **failure**","annotations":[{"path":"a.go","start_line":3,"end_line":3,"start_column":5,"end_column":5,"annotation_level":"failure","message":"unused variable"},{"path":"b.go","start_line":7,"end_line":7,"annotation_level":"warning","message":"missing doc"}]}}`}, checkRunRequests)
+
+	// A new check run is created if the latest one is completed
+	checkRunRequests = nil
+	require.NoError(t, c.SetCommitStatus("completed", git.CommitStatus{Context: "lint", State: git.CommitStatusStatePending, Description: "Jobs are running"}))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/check-runs {"name":"lint","head_sha":"completed","status":"in_progress","output":{"title":"Jobs are running","summary":"Jobs are running"}}`}, checkRunRequests)
+
+	// A new check run is created if there is none
+	checkRunRequests = nil
+	require.NoError(t, c.SetCommitStatus("none", git.CommitStatus{Context: "lint", State: git.CommitStatusStateError}))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/check-runs {"name":"lint","head_sha":"none","status":"completed","conclusion":"cancelled","output":{"title":"lint","summary":"lint"}}`}, checkRunRequests)
+
+	// Skipped jobs are reported as skipped check runs
+	checkRunRequests = nil
+	require.NoError(t, c.SetCommitStatus("none", git.CommitStatus{Context: "lint", State: git.CommitStatusStateSkipped, Description: "Skipped by [skip ci] directive"}))
+	require.Equal(t, []string{`POST /repos/tmax-cloud/cicd-test/check-runs {"name":"lint","head_sha":"none","status":"completed","conclusion":"skipped","output":{"title":"Skipped by [skip ci] directive","summary":"Skipped by [skip ci] directive"}}`}, checkRunRequests)
+}
+
+func TestClient_ListCommitStatuses_checkRuns(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.IntegrationConfig.Spec.Git.CheckRuns = true
+
+	statuses, err := c.ListCommitStatuses("running")
+	require.NoError(t, err)
+	require.Equal(t, []git.CommitStatus{
+		{Context: "lint", State: git.CommitStatusStatePending, Description: "Jobs are running", TargetURL: "http://report/lint"},
+		{Context: "test-1", State: git.CommitStatusStateFailure, Description: "Jobs failed"},
+	}, statuses)
+}
+
+func TestClient_parseReleaseWebhook(t *testing.T) {
+	cli, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := func(action, tag string) string {
+		return fmt.Sprintf(`{"action":%q,"release":{"name":"Release %s","tag_name":%q},`+
+			`"repository":{"full_name":"tmax-cloud/cicd-test","html_url":"https://github.com/tmax-cloud/cicd-test"},`+
+			`"sender":{"login":"cqbqdd11519","id":6166781}}`, action, tag, tag)
+	}
+
+	tc := map[string]struct {
+		body string
+
+		expectedErr     bool
+		expectedWebhook *git.Webhook
+	}{
+		"published": {
+			body: release("published", "v0.1.0"),
+			expectedWebhook: &git.Webhook{
+				EventType: git.EventTypeRelease,
+				Repo:      git.Repository{Name: "tmax-cloud/cicd-test", URL: "https://github.com/tmax-cloud/cicd-test"},
+				Sender:    git.User{ID: 6166781, Name: "cqbqdd11519", Email: "cqbqdd11519@test.com"},
+				Release:   &git.Release{Name: "Release v0.1.0", Tag: "v0.1.0", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+			},
+		},
+		"created": {
+			body: release("created", "v0.1.0"),
+		},
+		"unknownTag": {
+			body:        release("published", "v0.2.0"),
+			expectedErr: true,
+		},
+		"invalidBody": {
+			body:        `{"action":`,
+			expectedErr: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			wh, err := cli.parseReleaseWebhook([]byte(c.body))
+			if c.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedWebhook, wh)
+		})
+	}
+}
+
 func testEnv() (*Client, error) {
 	r := mux.NewRouter()
 	r.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
@@ -205,30 +580,192 @@ func testEnv() (*Client, error) {
 		_, _ = w.Write([]byte(sampleStatusesList))
 	})
 	r.HandleFunc("/repos/{org}/{repo}/pulls", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			body := &CreatePullRequestBody{}
+			_ = json.NewDecoder(req.Body).Decode(body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"number":26,"title":%q,"state":"open","head":{"ref":%q},"base":{"ref":%q}}`, body.Title, body.Head, body.Base)))
+			return
+		}
 		page := req.URL.Query().Get("page")
 		if page == "" || page == "1" {
 			w.Header().Set("Link", fmt.Sprintf("<%s/%s?state=all&per_page=100&page=2>; rel=\"next\", <%s/%s?state=all&per_page=100&page=3>; rel=\"last\"", serverURL, req.URL.Path, serverURL, req.URL.Path))
 		}
 		_, _ = w.Write([]byte(samplePRList))
 	})
+	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"number":25,"title":"newnew","state":"open","user":{"login":"cqbqdd11519","id":6166781},` +
+			`"head":{"ref":"newnew","sha":"3196ccc37bcae94852079b04fcbfaf928341d6e9","repo":{"full_name":"vingsu/cicd-test"}},` +
+			`"base":{"ref":"master","sha":"22ccae53032027186ba739dfaa473ee61a82b298","repo":{"full_name":"vingsu/cicd-test"}},` +
+			`"milestone":{"title":"v0.1.0"},"assignees":[{"login":"cqbqdd11519","id":6166781}],` +
+			`"body":"Depends-On: tmax-cloud/cicd-test2#3","merged_at":"2021-04-13T04:54:16Z","updated_at":"2021-04-13T04:54:17Z"}`))
+	})
 	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/files", func(w http.ResponseWriter, req *http.Request) {
+		page := req.URL.Query().Get("page")
+		if page == "" || page == "1" {
+			w.Header().Set("Link", fmt.Sprintf("<%s/%s?per_page=100&page=2>; rel=\"next\", <%s/%s?per_page=100&page=2>; rel=\"last\"", serverURL, req.URL.Path, serverURL, req.URL.Path))
+		}
 		_, _ = w.Write([]byte(samplePRFiles))
 	})
+	r.HandleFunc("/repos/{org}/{repo}/compare/{basehead}", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("{\"files\":" + samplePRFiles + "}"))
+	})
 	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/commits", func(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(samplePRCommits))
 	})
 	r.HandleFunc("/repos/{org}/{repo}/issues/{id}/labels", func(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(sampleLabelLists))
 	})
+	r.HandleFunc("/repos/{org}/{repo}/milestones", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`[{"number":1,"title":"v0.1.0"},{"number":2,"title":"v0.2.0"}]`))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/issues/{id}", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		issueRequests = append(issueRequests, req.Method+" "+req.URL.Path+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/requested_reviewers", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		reviewersRequests = append(reviewersRequests, req.Method+" "+req.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.HandleFunc("/repos/{org}/{repo}/issues/{id}/assignees", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assigneesRequests = append(assigneesRequests, req.Method+" "+req.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
 	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/comments", func(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(samplePRComments))
 	})
 	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/reviews", func(w http.ResponseWriter, req *http.Request) {
+		if mux.Vars(req)["id"] == "30" {
+			_, _ = w.Write([]byte(`[{"user":{"login":"user1","id":1},"state":"APPROVED"},{"user":{"login":"user2","id":2},"state":"APPROVED"},` +
+				`{"user":{"login":"user3","id":3},"state":"APPROVED"},{"user":{"login":"user2","id":2},"state":"CHANGES_REQUESTED"},` +
+				`{"user":{"login":"user3","id":3},"state":"COMMENTED"},{"user":{"login":"user0","id":4},"state":"COMMENTED"}]`))
+			return
+		}
 		_, _ = w.Write([]byte(samplePRReviews))
 	})
 	r.HandleFunc("/repos/{org}/{repo}/issues/{id}/comments", func(w http.ResponseWriter, req *http.Request) {
+		page := req.URL.Query().Get("page")
+		if req.Method == http.MethodGet && (page == "" || page == "1") {
+			w.Header().Set("Link", fmt.Sprintf("<%s/%s?per_page=100&page=2>; rel=\"next\", <%s/%s?per_page=100&page=2>; rel=\"last\"", serverURL, req.URL.Path, serverURL, req.URL.Path))
+		}
 		_, _ = w.Write([]byte(sampleIssueComments))
 	})
+	r.HandleFunc("/repos/{org}/{repo}/contents/{path:.+}", func(w http.ResponseWriter, req *http.Request) {
+		if mux.Vars(req)["path"] != ".cicd/config.yaml" || req.URL.Query().Get("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(sampleFileContent))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/pulls/{id}/update-branch", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if req.Method != http.MethodPut || !strings.Contains(string(body), "head-sha") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		updateBranchRequests = append(updateBranchRequests, req.Method+" "+req.URL.Path+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/branches/{branch}", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"name":%q,"commit":{"sha":"head-sha"}}`, mux.Vars(req)["branch"])))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/git/refs", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		cherryPickRequests = append(cherryPickRequests, req.Method+" "+req.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.HandleFunc("/repos/{org}/{repo}/git/commits/{sha}", func(w http.ResponseWriter, req *http.Request) {
+		switch sha := mux.Vars(req)["sha"]; sha {
+		case "head-sha":
+			_, _ = w.Write([]byte(`{"sha":"head-sha","message":"Head","tree":{"sha":"head-tree"},"parents":[{"sha":"head-parent"}]}`))
+		default:
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"sha":%q,"message":"Pick 1","author":{"name":"user1","email":"user1@test.com","date":"2021-04-13T04:54:16Z"},"tree":{"sha":"%s-tree"},"parents":[{"sha":"%s-parent"}]}`, sha, sha, sha)))
+		}
+	})
+	r.HandleFunc("/repos/{org}/{repo}/git/commits", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		cherryPickRequests = append(cherryPickRequests, req.Method+" "+req.URL.Path+" "+string(body))
+		numCommits := 0
+		for _, r := range cherryPickRequests {
+			if strings.Contains(r, "/git/commits ") {
+				numCommits++
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"sha":"new-commit-%d"}`, numCommits)))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/merges", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		cherryPickRequests = append(cherryPickRequests, req.Method+" "+req.URL.Path+" "+string(body))
+		if strings.Contains(string(body), "conflict") {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sha":"merge-sha","commit":{"tree":{"sha":"merged-tree"}}}`))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/git/refs/heads/{branch:.+}", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPatch {
+			body, _ := ioutil.ReadAll(req.Body)
+			cherryPickRequests = append(cherryPickRequests, req.Method+" "+req.URL.Path+" "+string(body))
+			return
+		}
+		deleteBranchRequests = append(deleteBranchRequests, req.Method+" "+req.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.HandleFunc("/repos/{org}/{repo}/branches/{branch}/protection/required_status_checks", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPatch || mux.Vars(req)["branch"] != "master" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		protectionRequests = append(protectionRequests, req.Method+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/branches/{branch}/protection", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		protectionRequests = append(protectionRequests, req.Method+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/commits/{sha}/check-runs", func(w http.ResponseWriter, req *http.Request) {
+		switch mux.Vars(req)["sha"] {
+		case "running":
+			_, _ = w.Write([]byte(`{"total_count":2,"check_runs":[{"id":11,"name":"lint","status":"in_progress","details_url":"http://report/lint","output":{"title":"Jobs are running"}},{"id":12,"name":"test-1","status":"completed","conclusion":"timed_out","output":{"title":"Jobs failed"}}]}`))
+		case "completed":
+			_, _ = w.Write([]byte(`{"total_count":1,"check_runs":[{"id":13,"name":"lint","status":"completed","conclusion":"success"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"total_count":0,"check_runs":[]}`))
+		}
+	})
+	r.HandleFunc("/repos/{org}/{repo}/check-runs", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		checkRunRequests = append(checkRunRequests, req.Method+" "+req.URL.Path+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/check-runs/{id}", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		checkRunRequests = append(checkRunRequests, req.Method+" "+req.URL.Path+" "+string(body))
+	})
+	r.HandleFunc("/repos/{org}/{repo}/commits/{ref}", func(w http.ResponseWriter, req *http.Request) {
+		if mux.Vars(req)["ref"] != "v0.1.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sha":"3196ccc37bcae94852079b04fcbfaf928341d6e9"}`))
+	})
+	r.HandleFunc("/users/{name}", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"login":%q,"id":6166781,"email":"%s@test.com"}`, mux.Vars(req)["name"], mux.Vars(req)["name"])))
+	})
+	r.HandleFunc("/search/issues", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("q") != "repo:tmax-cloud/cicd-test type:pr author:user1" {
+			_, _ = w.Write([]byte(`{"total_count":0,"items":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total_count":3,"items":[{"number":1}]}`))
+	})
 	testSrv := httptest.NewServer(r)
 	serverURL = testSrv.URL
 
@@ -43,10 +43,11 @@ type CommitStatusRequest struct {
 
 // CommitStatusResponse is a response body of getting commit status
 type CommitStatusResponse struct {
-	Context     string `json:"context"`
-	State       string `json:"state"`
-	Description string `json:"description"`
-	TargetURL   string `json:"target_url"`
+	Context     string   `json:"context"`
+	State       string   `json:"state"`
+	Description string   `json:"description"`
+	TargetURL   string   `json:"target_url"`
+	UpdatedAt   *v1.Time `json:"updated_at"`
 }
 
 // CommentBody is a body structure for creating new comment
@@ -59,6 +60,86 @@ type LabelBody struct {
 	Name string `json:"name"`
 }
 
+// CreatePullRequestBody is a body structure for creating a pull request
+type CreatePullRequestBody struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// RefBody is a body structure for creating/updating a git reference
+type RefBody struct {
+	Ref   string `json:"ref,omitempty"`
+	SHA   string `json:"sha"`
+	Force bool   `json:"force,omitempty"`
+}
+
+// GitCommitAuthor is an author of a git commit object
+type GitCommitAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date,omitempty"`
+}
+
+// GitCommit is a git commit object of the git database API
+type GitCommit struct {
+	SHA     string           `json:"sha"`
+	Message string           `json:"message"`
+	Author  *GitCommitAuthor `json:"author"`
+	Tree    struct {
+		SHA string `json:"sha"`
+	} `json:"tree"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
+// CreateGitCommitBody is a body structure for creating a git commit object
+type CreateGitCommitBody struct {
+	Message string           `json:"message"`
+	Tree    string           `json:"tree"`
+	Parents []string         `json:"parents"`
+	Author  *GitCommitAuthor `json:"author,omitempty"`
+}
+
+// MergeBody is a body structure for merging a commit into a branch
+type MergeBody struct {
+	Base          string `json:"base"`
+	Head          string `json:"head"`
+	CommitMessage string `json:"commit_message"`
+}
+
+// MergeResponse is a response body of merging a commit into a branch
+type MergeResponse struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	} `json:"commit"`
+}
+
+// MilestoneBody is a body structure for setting/clearing the milestone of issues/prs
+type MilestoneBody struct {
+	Milestone *int `json:"milestone"`
+}
+
+// StateBody is a body structure for closing/reopening issues
+type StateBody struct {
+	State string `json:"state"`
+}
+
+// ReviewersBody is a body structure for requesting/removing reviews to/from prs
+type ReviewersBody struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// AssigneesBody is a body structure for adding/removing assignees to/from issues/prs
+type AssigneesBody struct {
+	Assignees []string `json:"assignees"`
+}
+
 // BranchResponse is a respond struct for branch request
 type BranchResponse struct {
 	Name   string `json:"name"`
@@ -67,6 +148,13 @@ type BranchResponse struct {
 	} `json:"commit"`
 }
 
+// ContentResponse is a respond struct for file content request
+type ContentResponse struct {
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
 // MergeRequest is a request struct to merge a pull request
 type MergeRequest struct {
 	CommitTitle   string `json:"commit_title,omitempty"`
@@ -75,6 +163,11 @@ type MergeRequest struct {
 	Sha           string `json:"sha"`
 }
 
+// UpdateBranchRequest is a request struct to update a pull request's branch
+type UpdateBranchRequest struct {
+	ExpectedHeadSha string `json:"expected_head_sha"`
+}
+
 // DiffFiles is a list of DiffFile
 type DiffFiles []DiffFile
 
@@ -87,6 +180,11 @@ type DiffFile struct {
 	Changes      int    `json:"changes"`
 }
 
+// CompareResponse is a response of comparing two commits
+type CompareResponse struct {
+	Files DiffFiles `json:"files"`
+}
+
 // CommitResponse is a commits list response
 type CommitResponse struct {
 	SHA    string `json:"sha"`
@@ -105,13 +203,86 @@ type CommitResponse struct {
 
 // CommentResponse is a comment list response
 type CommentResponse struct {
+	User      User     `json:"user"`
 	Body      string   `json:"body"`
 	CreatedAt *v1.Time `json:"created_at"`
 }
 
 // ReviewResponse is a review list response
 type ReviewResponse struct {
+	User        User                       `json:"user"`
 	Body        string                     `json:"body"`
 	SubmittedAt *v1.Time                   `json:"submitted_at"`
 	State       git.PullRequestReviewState `json:"state"`
 }
+
+// RequiredStatusChecksBody is a body for updating the required status checks of a branch
+// Strict is omitted when updating the required status checks, so that the existing setting is kept
+type RequiredStatusChecksBody struct {
+	Strict   *bool    `json:"strict,omitempty"`
+	Contexts []string `json:"contexts"`
+}
+
+// BranchProtectionBody is a body for protecting a branch
+// Other protection rules are explicitly disabled, as they are required by the API
+type BranchProtectionBody struct {
+	RequiredStatusChecks       *RequiredStatusChecksBody `json:"required_status_checks"`
+	EnforceAdmins              bool                      `json:"enforce_admins"`
+	RequiredPullRequestReviews *struct{}                 `json:"required_pull_request_reviews"`
+	Restrictions               *struct{}                 `json:"restrictions"`
+}
+
+// CheckRunRequest is a body for creating or updating a check run
+type CheckRunRequest struct {
+	Name       string          `json:"name,omitempty"`
+	HeadSha    string          `json:"head_sha,omitempty"`
+	DetailsURL string          `json:"details_url,omitempty"`
+	Status     string          `json:"status"`
+	Conclusion string          `json:"conclusion,omitempty"`
+	Output     *CheckRunOutput `json:"output,omitempty"`
+}
+
+// CheckRunOutput is an output of a check run
+type CheckRunOutput struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary"`
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// CheckRunAnnotation is an annotation of a check run, at a line of a file
+// The columns can be set only if the annotation is at a single line
+type CheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	StartColumn     int    `json:"start_column,omitempty"`
+	EndColumn       int    `json:"end_column,omitempty"`
+	AnnotationLevel string `json:"annotation_level"`
+	Message         string `json:"message"`
+}
+
+// CheckRunResponse is a response body of a check run
+type CheckRunResponse struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	HeadSha     string   `json:"head_sha"`
+	Status      string   `json:"status"`
+	Conclusion  string   `json:"conclusion"`
+	DetailsURL  string   `json:"details_url"`
+	StartedAt   *v1.Time `json:"started_at"`
+	CompletedAt *v1.Time `json:"completed_at"`
+	Output      struct {
+		Title string `json:"title"`
+	} `json:"output"`
+}
+
+// SearchIssuesResponse is a response body of searching issues and pull requests
+type SearchIssuesResponse struct {
+	TotalCount int `json:"total_count"`
+}
+
+// CheckRunsResponse is a response body of listing check runs
+type CheckRunsResponse struct {
+	TotalCount int                `json:"total_count"`
+	CheckRuns  []CheckRunResponse `json:"check_runs"`
+}
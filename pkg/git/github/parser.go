@@ -30,7 +30,7 @@ func (c *Client) parsePullRequestWebhook(jsonString []byte) (*git.Webhook, error
 		return nil, err
 	}
 
-	pullRequest := git.PullRequest{ID: data.Number, Title: data.PullRequest.Title, URL: data.Repo.URL, State: git.PullRequestState(data.PullRequest.State), Action: git.PullRequestAction(data.Action)}
+	pullRequest := git.PullRequest{ID: data.Number, Title: data.PullRequest.Title, URL: data.Repo.URL, State: git.PullRequestState(data.PullRequest.State), Action: git.PullRequestAction(data.Action), Draft: data.PullRequest.Draft, Merged: data.PullRequest.MergedAt != ""}
 
 	// Get sender & author
 	sender, author := c.getSenderAuthor(data.Sender, data.PullRequest.User)
@@ -62,7 +62,16 @@ func (c *Client) parsePushWebhook(jsonString []byte) (*git.Webhook, error) {
 		return nil, nil
 	}
 	sender := git.User{Name: data.Sender.Name, ID: data.Sender.ID}
-	push := git.Push{Ref: data.Ref, Sha: data.Sha}
+	push := git.Push{Ref: data.Ref, Sha: data.Sha, Before: data.Before, HeadCommit: git.Commit{
+		SHA:       data.HeadCommit.ID,
+		Message:   data.HeadCommit.Message,
+		Author:    git.User{Name: data.HeadCommit.Author.Name, Email: data.HeadCommit.Author.Email},
+		Committer: git.User{Name: data.HeadCommit.Committer.Name, Email: data.HeadCommit.Committer.Email},
+	}}
+	// For annotated tags, 'after' is a sha of the tag object, not the commit
+	if strings.HasPrefix(data.Ref, "refs/tags/") && data.HeadCommit.ID != "" {
+		push.Sha = data.HeadCommit.ID
+	}
 
 	// Get sender email
 	userInfo, err := c.GetUserInfo(data.Sender.Name)
@@ -73,6 +82,36 @@ func (c *Client) parsePushWebhook(jsonString []byte) (*git.Webhook, error) {
 	return &git.Webhook{EventType: git.EventTypePush, Repo: repo, Sender: sender, Push: &push}, nil
 }
 
+func (c *Client) parseReleaseWebhook(jsonString []byte) (*git.Webhook, error) {
+	var data ReleaseWebhook
+
+	if err := json.Unmarshal(jsonString, &data); err != nil {
+		return nil, err
+	}
+
+	// Only handle publication
+	if data.Action != "published" {
+		return nil, nil
+	}
+
+	sha, err := c.getCommitSha(data.Release.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := git.Repository{Name: data.Repo.Name, URL: data.Repo.URL}
+	sender := git.User{Name: data.Sender.Name, ID: data.Sender.ID}
+	release := git.Release{Name: data.Release.Name, Tag: data.Release.TagName, Sha: sha}
+
+	// Get sender email
+	userInfo, err := c.GetUserInfo(data.Sender.Name)
+	if err == nil {
+		sender.Email = userInfo.Email
+	}
+
+	return &git.Webhook{EventType: git.EventTypeRelease, Repo: repo, Sender: sender, Release: &release}, nil
+}
+
 func (c *Client) parseIssueCommentWebhook(jsonString []byte) (*git.Webhook, error) {
 	issueComment := &IssueCommentWebhook{}
 	if err := json.Unmarshal(jsonString, issueComment); err != nil {
@@ -101,6 +140,14 @@ func (c *Client) parseIssueCommentWebhook(jsonString []byte) (*git.Webhook, erro
 	// Get sender & author
 	sender, author := c.getSenderAuthor(issueComment.Sender, issueComment.Comment.User)
 
+	issue := git.Issue{PullRequest: pr}
+	if pr == nil {
+		issue.ID = issueComment.Issue.Number
+		issue.State = git.PullRequestState(issueComment.Issue.State)
+		issue.Author = git.User{ID: issueComment.Issue.User.ID, Name: issueComment.Issue.User.Name}
+		issue.URL = issueComment.Issue.HTMLURL
+	}
+
 	return &git.Webhook{EventType: git.EventTypeIssueComment, Repo: git.Repository{
 		Name: issueComment.Repo.Name,
 		URL:  issueComment.Repo.URL,
@@ -112,9 +159,7 @@ func (c *Client) parseIssueCommentWebhook(jsonString []byte) (*git.Webhook, erro
 				CreatedAt: issueComment.Comment.CreatedAt,
 			},
 			Author: *author,
-			Issue: git.Issue{
-				PullRequest: pr,
-			},
+			Issue:  issue,
 		}}, nil
 }
 
@@ -181,6 +226,35 @@ func (c *Client) parsePullRequestReviewCommentWebhook(jsonString []byte) (*git.W
 		}}, nil
 }
 
+func (c *Client) parseCheckRunWebhook(jsonString []byte) (*git.Webhook, error) {
+	data := &CheckRunWebhook{}
+	if err := json.Unmarshal(jsonString, data); err != nil {
+		return nil, err
+	}
+
+	// Only handle re-requests from the UI
+	if data.Action != "rerequested" {
+		return nil, nil
+	}
+
+	checkRun := &git.CheckRun{Name: data.CheckRun.Name, Sha: data.CheckRun.HeadSha, Branch: data.CheckRun.CheckSuite.HeadBranch}
+	if len(data.CheckRun.PullRequests) > 0 {
+		pr, err := c.GetPullRequest(data.CheckRun.PullRequests[0].Number)
+		if err != nil {
+			return nil, err
+		}
+		checkRun.PullRequest = pr
+	}
+
+	repo := git.Repository{Name: data.Repo.Name, URL: data.Repo.URL}
+	sender, err := c.GetUserInfo(data.Sender.Name)
+	if err != nil {
+		sender = &git.User{Name: data.Sender.Name, ID: data.Sender.ID}
+	}
+
+	return &git.Webhook{EventType: git.EventTypeCheckRun, Repo: repo, Sender: *sender, CheckRun: checkRun}, nil
+}
+
 func (c *Client) getSenderAuthor(senderPre, authorPre User) (*git.User, *git.User) {
 	// Get sender & email
 	sender, err := c.GetUserInfo(senderPre.Name)
@@ -38,6 +38,34 @@ type PushWebhook struct {
 	Repo   Repo   `json:"repository"`
 	Sender User   `json:"sender"`
 	Sha    string `json:"after"`
+	Before string `json:"before"`
+
+	HeadCommit PushCommit `json:"head_commit"`
+}
+
+// PushCommit is a commit included in the push event webhook body
+type PushCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+	Committer struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"committer"`
+}
+
+// ReleaseWebhook is a github-specific release event webhook body
+type ReleaseWebhook struct {
+	Action  string `json:"action"`
+	Release struct {
+		Name    string `json:"name"`
+		TagName string `json:"tag_name"`
+	} `json:"release"`
+	Repo   Repo `json:"repository"`
+	Sender User `json:"sender"`
 }
 
 // IssueCommentWebhook is a github-specific issue_comment webhook body
@@ -45,6 +73,10 @@ type IssueCommentWebhook struct {
 	Action  string  `json:"action"`
 	Comment Comment `json:"comment"`
 	Issue   struct {
+		Number      int    `json:"number"`
+		State       string `json:"state"`
+		HTMLURL     string `json:"html_url"`
+		User        User   `json:"user"`
 		PullRequest struct {
 			URL string `json:"url"`
 		} `json:"pull_request"`
@@ -76,6 +108,23 @@ type PullRequestReviewCommentWebhook struct {
 	Sender      User        `json:"sender"`
 }
 
+// CheckRunWebhook is a github-specific check_run webhook body
+type CheckRunWebhook struct {
+	Action   string `json:"action"`
+	CheckRun struct {
+		Name       string `json:"name"`
+		HeadSha    string `json:"head_sha"`
+		CheckSuite struct {
+			HeadBranch string `json:"head_branch"`
+		} `json:"check_suite"`
+		PullRequests []struct {
+			Number int `json:"number"`
+		} `json:"pull_requests"`
+	} `json:"check_run"`
+	Repo   Repo `json:"repository"`
+	Sender User `json:"sender"`
+}
+
 // Repo structure for webhook event
 type Repo struct {
 	Name  string `json:"full_name"`
@@ -92,20 +141,35 @@ type PullRequest struct {
 	Number    int    `json:"number"`
 	State     string `json:"state"`
 	URL       string `json:"html_url"`
-	Mergeable bool   `json:"mergeable"`
+	Mergeable *bool  `json:"mergeable"`
 	User      User   `json:"user"`
 	Draft     bool   `json:"draft"`
 	Head      struct {
-		Ref string `json:"ref"`
-		Sha string `json:"sha"`
+		Ref  string `json:"ref"`
+		Sha  string `json:"sha"`
+		Repo Repo   `json:"repo"`
 	} `json:"head"`
 	Base struct {
-		Ref string `json:"ref"`
-		Sha string `json:"sha"`
+		Ref  string `json:"ref"`
+		Sha  string `json:"sha"`
+		Repo Repo   `json:"repo"`
 	} `json:"base"`
 	Labels []struct {
 		Name string `json:"name"`
 	} `json:"labels"`
+	Milestone *Milestone `json:"milestone"`
+	Assignees []User     `json:"assignees"`
+	Reviewers []User     `json:"requested_reviewers"`
+	Body      string     `json:"body"`
+	MergedAt  string     `json:"merged_at"`
+
+	UpdatedAt *metav1.Time `json:"updated_at"`
+}
+
+// Milestone is a milestone of an issue or a pull request
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
 }
 
 // User is a sender of the event
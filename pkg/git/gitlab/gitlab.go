@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -67,6 +68,8 @@ func (c *Client) ParseWebhook(header http.Header, jsonString []byte) (*git.Webho
 		return c.parsePushWebhook(jsonString)
 	case "Note Hook":
 		return c.parseIssueComment(jsonString)
+	case "Release Hook":
+		return c.parseReleaseWebhook(jsonString)
 	}
 
 	return nil, nil
@@ -114,6 +117,7 @@ func (c *Client) RegisterWebhook(uri string) error {
 	registrationBody.NoteEvents = true
 	registrationBody.PipeLineEvents = true
 	registrationBody.PushEvents = true
+	registrationBody.ReleasesEvents = true
 	registrationBody.TagPushEvents = true
 	registrationBody.WikiPageEvents = true
 	registrationBody.URL = uri
@@ -165,11 +169,16 @@ func (c *Client) ListCommitStatuses(ref string) ([]git.CommitStatus, error) {
 		case "failed", "canceled":
 			state = "failure"
 		}
+		updatedAt := s.CreatedAt
+		if s.FinishedAt != nil {
+			updatedAt = s.FinishedAt
+		}
 		resp = append(resp, git.CommitStatus{
 			Context:     s.Name,
 			State:       state,
 			Description: s.Description,
 			TargetURL:   s.TargetURL,
+			UpdatedAt:   updatedAt,
 		})
 	}
 
@@ -192,6 +201,8 @@ func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 		commitStatusBody.State = "running"
 	case cicdv1.CommitStatusStateFailure, cicdv1.CommitStatusStateError:
 		commitStatusBody.State = "failed"
+	case cicdv1.CommitStatusState(git.CommitStatusStateSkipped):
+		commitStatusBody.State = string(git.CommitStatusStateSuccess)
 	default:
 		commitStatusBody.State = string(status.State)
 	}
@@ -207,6 +218,28 @@ func (c *Client) SetCommitStatus(sha string, status git.CommitStatus) error {
 	return nil
 }
 
+// CompareCommits gets diff between the base and the head commits
+func (c *Client) CompareCommits(base, head string) (*git.Diff, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/compare?from=%s&to=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), url.QueryEscape(base), url.QueryEscape(head))
+
+	result, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CompareResponse{}
+	if err := json.Unmarshal(result, resp); err != nil {
+		return nil, err
+	}
+
+	changes, err := convertDiffChanges(resp.Diffs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &git.Diff{Changes: changes}, nil
+}
+
 // GetUserInfo gets a user's information
 func (c *Client) GetUserInfo(userID string) (*git.User, error) {
 	// userID is int!
@@ -236,20 +269,38 @@ func (c *Client) GetUserInfo(userID string) (*git.User, error) {
 
 // CanUserWriteToRepo decides if the user has write permission on the repo
 func (c *Client) CanUserWriteToRepo(user git.User) (bool, error) {
+	accessLevel, err := c.getUserAccessLevel(user)
+	if err != nil {
+		return false, err
+	}
+	// Developer, Maintainer, or Owner
+	return accessLevel >= 30, nil
+}
+
+// IsUserRepoAdmin decides if the user is a maintainer or an owner of the repo
+func (c *Client) IsUserRepoAdmin(user git.User) (bool, error) {
+	accessLevel, err := c.getUserAccessLevel(user)
+	if err != nil {
+		return false, err
+	}
+	// Maintainer or Owner
+	return accessLevel >= 40, nil
+}
+
+func (c *Client) getUserAccessLevel(user git.User) (int, error) {
 	// userID is int!
 	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/members/all/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), user.ID)
 
 	result, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
 	var permission UserPermission
 	if err := json.Unmarshal(result, &permission); err != nil {
-		return false, err
+		return 0, err
 	}
-
-	return permission.AccessLevel >= 30, nil
+	return permission.AccessLevel, nil
 }
 
 // RegisterComment registers comment to an issue
@@ -279,12 +330,13 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 	var comments []git.IssueComment
 	apiUrl := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), issueNo)
 
-	raw, _, err := c.requestHTTP(http.MethodGet, apiUrl, nil)
-	if err != nil {
-		return nil, err
-	}
+	// Notes are paginated
 	var noteResponses []NoteResponse
-	if err := json.Unmarshal(raw, &noteResponses); err != nil {
+	if err := git.GetPaginatedRequest(apiUrl, c.IntegrationConfig.GetTLSConfig(), c.header, func() interface{} {
+		return &[]NoteResponse{}
+	}, func(i interface{}) {
+		noteResponses = append(noteResponses, *i.(*[]NoteResponse)...)
+	}); err != nil {
 		return nil, err
 	}
 	for _, noteResponse := range noteResponses {
@@ -293,6 +345,7 @@ func (c *Client) ListComments(issueNo int) ([]git.IssueComment, error) {
 				Body:      noteResponse.Body,
 				CreatedAt: noteResponse.CreatedAt,
 			},
+			Author: git.User{ID: noteResponse.Author.ID, Name: noteResponse.Author.UserName},
 		})
 	}
 	return comments, nil
@@ -327,10 +380,18 @@ func (c *Client) ListPullRequests(onlyOpen bool) ([]git.PullRequest, error) {
 				ID:   mr.Author.ID,
 				Name: mr.Author.UserName,
 			},
-			URL:    mr.WebURL,
-			Base:   git.Base{Ref: mr.TargetBranch},
-			Head:   git.Head{Ref: mr.SourceBranch, Sha: mr.SHA},
-			Labels: convertLabel(mr.Labels),
+			URL:       mr.WebURL,
+			Base:      git.Base{Ref: mr.TargetBranch},
+			Head:      git.Head{Ref: mr.SourceBranch, Sha: mr.SHA},
+			Labels:    convertLabel(mr.Labels),
+			Draft:     mr.Draft || mr.WorkInProgress,
+			Merged:    mr.State == "merged",
+			Body:      mr.Description,
+			Fork:      mr.SourceProjectID != mr.TargetProjectID,
+			Milestone: convertMilestone(&mr),
+			Assignees: convertAssignees(mr.Assignees),
+			Reviewers: convertAssignees(mr.Reviewers),
+			UpdatedAt: mr.UpdatedAt,
 		})
 	}
 
@@ -365,11 +426,20 @@ func (c *Client) GetPullRequest(id int) (*git.PullRequest, error) {
 			ID:   mr.Author.ID,
 			Name: mr.Author.UserName,
 		},
-		URL:       mr.WebURL,
-		Base:      git.Base{Ref: mr.TargetBranch, Sha: targetBranch.CommitID},
-		Head:      git.Head{Ref: mr.SourceBranch, Sha: mr.SHA},
-		Labels:    convertLabel(mr.Labels),
-		Mergeable: !mr.HasConflicts,
+		URL:        mr.WebURL,
+		Base:       git.Base{Ref: mr.TargetBranch, Sha: targetBranch.CommitID},
+		Head:       git.Head{Ref: mr.SourceBranch, Sha: mr.SHA},
+		Labels:     convertLabel(mr.Labels),
+		Mergeable:  !mr.HasConflicts,
+		Conflicted: mr.HasConflicts,
+		Draft:      mr.Draft || mr.WorkInProgress,
+		Merged:     mr.State == "merged",
+		Body:       mr.Description,
+		Fork:       mr.SourceProjectID != mr.TargetProjectID,
+		Milestone:  convertMilestone(&mr),
+		Assignees:  convertAssignees(mr.Assignees),
+		Reviewers:  convertAssignees(mr.Reviewers),
+		UpdatedAt:  mr.UpdatedAt,
 	}, nil
 }
 
@@ -383,6 +453,7 @@ func (c *Client) MergePullRequest(id int, sha string, method git.MergeMethod, ms
 		RemoveSourceBranch: false,
 	}
 
+	// GitLab merges it using the project's merge method, so the rebase method is the same as the merge method
 	if method == git.MergeMethodSquash {
 		body.SquashCommitMessage = msg
 	} else {
@@ -397,6 +468,47 @@ func (c *Client) MergePullRequest(id int, sha string, method git.MergeMethod, ms
 	return nil
 }
 
+// CreatePullRequest creates a merge request, from the head branch to the base branch
+func (c *Client) CreatePullRequest(title, body, head, base string) (*git.PullRequest, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository))
+
+	raw, _, err := c.requestHTTP(http.MethodPost, apiURL, CreateMergeRequest{Title: title, Description: body, SourceBranch: head, TargetBranch: base})
+	if err != nil {
+		return nil, err
+	}
+	var mr MergeRequest
+	if err := json.Unmarshal(raw, &mr); err != nil {
+		return nil, err
+	}
+
+	return &git.PullRequest{
+		ID:    mr.ID,
+		Title: mr.Title,
+		State: convertState(mr.State),
+		Author: git.User{
+			ID:   mr.Author.ID,
+			Name: mr.Author.UserName,
+		},
+		URL:  mr.WebURL,
+		Base: git.Base{Ref: mr.TargetBranch},
+		Head: git.Head{Ref: mr.SourceBranch, Sha: mr.SHA},
+		Body: mr.Description,
+	}, nil
+}
+
+// UpdatePullRequestBranch rebases the merge request's branch onto the latest target branch
+// GitLab cannot check the head of the merge request, so sha is ignored
+func (c *Client) UpdatePullRequestBranch(id int, _ string) error {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/rebase", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), id)
+
+	_, _, err := c.requestHTTP(http.MethodPut, apiURL, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetPullRequestDiff gets diff of the pull request
 func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
 	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/changes", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), id)
@@ -411,8 +523,17 @@ func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
 		return nil, err
 	}
 
+	changes, err := convertDiffChanges(rawDiff.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &git.Diff{Changes: changes}, nil
+}
+
+func convertDiffChanges(diffs []DiffChange) ([]git.Change, error) {
 	var changes []git.Change
-	for _, d := range rawDiff.Changes {
+	for _, d := range diffs {
 		additions, deletions, err := git.GetChangedLinesFromDiff(d.Diff)
 		if err != nil {
 			return nil, err
@@ -426,8 +547,7 @@ func (c *Client) GetPullRequestDiff(id int) (*git.Diff, error) {
 			Changes:     additions + deletions,
 		})
 	}
-
-	return &git.Diff{Changes: changes}, nil
+	return changes, nil
 }
 
 // ListPullRequestCommits lists commits list of a pull request
@@ -444,8 +564,10 @@ func (c *Client) ListPullRequestCommits(id int) ([]git.Commit, error) {
 		return nil, err
 	}
 
+	// GitLab lists the commits in reverse chronological order, so reverse them to be in chronological order, like GitHub
 	var commits []git.Commit
-	for _, commit := range resp {
+	for i := len(resp) - 1; i >= 0; i-- {
+		commit := resp[i]
 		commits = append(commits, git.Commit{
 			SHA:     commit.ID,
 			Message: commit.Message,
@@ -484,6 +606,52 @@ func (c *Client) SetLabel(issueType git.IssueType, id int, label string) error {
 	return nil
 }
 
+// ListPullRequestApprovers lists the users who approved the merge request
+func (c *Client) ListPullRequestApprovers(id int) ([]git.User, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/approvals", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), id)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	approvals := &MergeRequestApprovals{}
+	if err := json.Unmarshal(raw, approvals); err != nil {
+		return nil, err
+	}
+
+	var approvers []git.User
+	for _, a := range approvals.ApprovedBy {
+		approvers = append(approvers, git.User{ID: a.User.ID, Name: a.User.UserName, Email: a.User.Email})
+	}
+
+	return approvers, nil
+}
+
+// CountUserPullRequests counts the merge requests of the project opened by the user, regardless of their states
+func (c *Client) CountUserPullRequests(user git.User) (int, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests?state=all&author_id=%d&per_page=1", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), user.ID)
+
+	data, header, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if total := header.Get("X-Total"); total != "" {
+		return strconv.Atoi(total)
+	}
+
+	// X-Total header is omitted if there are more than 10,000 merge requests. Count only the first page and whether the
+	// next page exists, in that case
+	var mrs []MergeRequest
+	if err := json.Unmarshal(data, &mrs); err != nil {
+		return 0, err
+	}
+	if header.Get("X-Next-Page") != "" {
+		return len(mrs) + 1, nil
+	}
+	return len(mrs), nil
+}
+
 // ListLabels lists labels of pr id
 func (c *Client) ListLabels(id int) ([]git.IssueLabel, error) {
 	apiUrl := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), id)
@@ -527,6 +695,203 @@ func (c *Client) DeleteLabel(issueType git.IssueType, id int, label string) erro
 	return nil
 }
 
+// AddAssignees adds the users to the assignees of the issue id
+func (c *Client) AddAssignees(issueType git.IssueType, id int, users []string) error {
+	return c.updateAssignees(issueType, id, users, true)
+}
+
+// RemoveAssignees removes the users from the assignees of the issue id
+func (c *Client) RemoveAssignees(issueType git.IssueType, id int, users []string) error {
+	return c.updateAssignees(issueType, id, users, false)
+}
+
+// updateAssignees adds/removes the users to/from the assignees of the issue id
+// GitLab only supports replacing the whole assignees, by their ids
+func (c *Client) updateAssignees(issueType git.IssueType, id int, users []string, add bool) error {
+	var t string
+	switch issueType {
+	case git.IssueTypeIssue:
+		t = "issues"
+	case git.IssueTypePullRequest:
+		t = "merge_requests"
+	default:
+		return fmt.Errorf("issue type %s is not supported", issueType)
+	}
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/%s/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), t, id)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	issue := &MergeRequest{}
+	if err := json.Unmarshal(raw, issue); err != nil {
+		return err
+	}
+
+	ids, err := c.updateUserIDs(issue.Assignees, users, add)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.requestHTTP(http.MethodPut, apiURL, UpdateAssignees{AssigneeIDs: ids})
+	return err
+}
+
+// SetMilestone sets the milestone of the issue id. The milestone is cleared if it's empty
+func (c *Client) SetMilestone(issueType git.IssueType, id int, milestone string) error {
+	var t string
+	switch issueType {
+	case git.IssueTypeIssue:
+		t = "issues"
+	case git.IssueTypePullRequest:
+		t = "merge_requests"
+	default:
+		return fmt.Errorf("issue type %s is not supported", issueType)
+	}
+
+	// Milestone id 0 clears the milestone
+	body := UpdateMilestone{}
+	if milestone != "" {
+		milestoneID, err := c.getMilestoneID(milestone)
+		if err != nil {
+			return err
+		}
+		body.MilestoneID = milestoneID
+	}
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/%s/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), t, id)
+
+	_, _, err := c.requestHTTP(http.MethodPut, apiURL, body)
+	return err
+}
+
+// UpdateIssueState closes or reopens the issue id
+func (c *Client) UpdateIssueState(issueType git.IssueType, id int, state git.PullRequestState) error {
+	var t string
+	switch issueType {
+	case git.IssueTypeIssue:
+		t = "issues"
+	case git.IssueTypePullRequest:
+		t = "merge_requests"
+	default:
+		return fmt.Errorf("issue type %s is not supported", issueType)
+	}
+
+	body := UpdateState{}
+	switch state {
+	case git.PullRequestStateOpen:
+		body.StateEvent = "reopen"
+	case git.PullRequestStateClosed:
+		body.StateEvent = "close"
+	default:
+		return fmt.Errorf("state %s is not supported", state)
+	}
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/%s/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), t, id)
+
+	_, _, err := c.requestHTTP(http.MethodPut, apiURL, body)
+	return err
+}
+
+// getMilestoneID gets the id of the milestone with the title
+func (c *Client) getMilestoneID(title string) (int, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/milestones?title=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), url.QueryEscape(title))
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	var milestones []Milestone
+	if err := json.Unmarshal(raw, &milestones); err != nil {
+		return 0, err
+	}
+	for _, m := range milestones {
+		if m.Title == title {
+			return m.ID, nil
+		}
+	}
+	return 0, &git.MilestoneNotFoundError{Milestone: title, Repo: c.IntegrationConfig.Spec.Git.Repository}
+}
+
+// RequestReviewers requests reviews of the merge request id to the users
+func (c *Client) RequestReviewers(id int, users []string) error {
+	return c.updateReviewers(id, users, true)
+}
+
+// RemoveReviewRequests removes the review requests of the merge request id from the users
+func (c *Client) RemoveReviewRequests(id int, users []string) error {
+	return c.updateReviewers(id, users, false)
+}
+
+// updateReviewers adds/removes the users to/from the reviewers of the merge request id
+// GitLab only supports replacing the whole reviewers, by their ids
+func (c *Client) updateReviewers(id int, users []string, add bool) error {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), id)
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	mr := &MergeRequest{}
+	if err := json.Unmarshal(raw, mr); err != nil {
+		return err
+	}
+
+	ids, err := c.updateUserIDs(mr.Reviewers, users, add)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.requestHTTP(http.MethodPut, apiURL, UpdateReviewers{ReviewerIDs: ids})
+	return err
+}
+
+// updateUserIDs returns a sorted list of ids of the current users, after the users are added/removed
+func (c *Client) updateUserIDs(current []UserInfo, users []string, add bool) ([]int, error) {
+	idMap := map[int]struct{}{}
+	for _, u := range current {
+		idMap[u.ID] = struct{}{}
+	}
+	for _, u := range users {
+		userID, err := c.getUserID(u)
+		if err != nil {
+			return nil, err
+		}
+		if add {
+			idMap[userID] = struct{}{}
+		} else {
+			delete(idMap, userID)
+		}
+	}
+
+	// Empty list removes all the users
+	ids := []int{}
+	for userID := range idMap {
+		ids = append(ids, userID)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// getUserID gets the id of the user with the username
+func (c *Client) getUserID(userName string) (int, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/users?username=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(userName))
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	var users []UserInfo
+	if err := json.Unmarshal(raw, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("user %s does not exist", userName)
+	}
+	return users[0].ID, nil
+}
+
 // GetBranch gets branch info
 func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/branches/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), branch)
@@ -544,6 +909,55 @@ func (c *Client) GetBranch(branch string) (*git.Branch, error) {
 	return &git.Branch{Name: resp.Name, CommitID: resp.Commit.ID}, nil
 }
 
+// SetRequiredStatusChecks is not supported for gitlab, as gitlab does not require specific status checks for merge
+// requests. Use 'Pipelines must succeed' option of the project instead
+func (c *Client) SetRequiredStatusChecks(_ string, _ []string) error {
+	return fmt.Errorf("required status checks are not supported for gitlab")
+}
+
+// DeleteBranch deletes the branch
+func (c *Client) DeleteBranch(branch string) error {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/branches/%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), url.PathEscape(branch))
+
+	_, _, err := c.requestHTTP(http.MethodDelete, apiURL, nil)
+	return err
+}
+
+// CreateBranch creates a branch pointing to the commit sha
+func (c *Client) CreateBranch(branch, sha string) error {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/branches?branch=%s&ref=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), url.QueryEscape(branch), url.QueryEscape(sha))
+
+	_, _, err := c.requestHTTP(http.MethodPost, apiURL, nil)
+	return err
+}
+
+// CherryPick cherry-picks the commits onto the branch, in order
+func (c *Client) CherryPick(branch string, commits []string) error {
+	for _, sha := range commits {
+		apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/commits/%s/cherry_pick", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), sha)
+
+		// GitLab responds with 400 if the commit cannot be cherry-picked cleanly
+		if _, _, err := c.requestHTTP(http.MethodPost, apiURL, CherryPickBody{Branch: branch}); err != nil {
+			if strings.Contains(err.Error(), ", code 400,") {
+				return &git.CherryPickConflictError{Commit: sha, Branch: branch}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFile gets the content of the file at the ref (i.e., branch, tag, or sha)
+func (c *Client) GetFile(path, ref string) ([]byte, error) {
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s", c.IntegrationConfig.Spec.Git.GetAPIUrl(), url.QueryEscape(c.IntegrationConfig.Spec.Git.Repository), url.PathEscape(strings.TrimPrefix(path, "/")), url.QueryEscape(ref))
+
+	raw, _, err := c.requestHTTP(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
 func (c *Client) requestHTTP(method, apiURL string, data interface{}) ([]byte, http.Header, error) {
 	tlsConfig := c.IntegrationConfig.GetTLSConfig()
 
@@ -586,6 +1000,21 @@ func convertLabel(original []string) []git.IssueLabel {
 	return labels
 }
 
+func convertMilestone(mr *MergeRequest) string {
+	if mr.Milestone == nil {
+		return ""
+	}
+	return mr.Milestone.Title
+}
+
+func convertAssignees(original []UserInfo) []git.User {
+	var users []git.User
+	for _, u := range original {
+		users = append(users, git.User{ID: u.ID, Name: u.UserName})
+	}
+	return users
+}
+
 // Validate validates the webhook payload
 func Validate(secret, headerToken string) error {
 	if secret != headerToken {
@@ -17,15 +17,19 @@
 package gitlab
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bmizerany/assert"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -43,6 +47,7 @@ const (
 	sampleMRCommits    = "[\n    {\n        \"id\":\"5f065c6de7dacb91aa5929a5c0ab71ecba5456b0\",\n        \"created_at\":\"2021-04-12T05:07:48.000Z\",\n        \"title\":\"Update index.html\",\n        \"message\":\"Update index.html\",\n        \"author_name\":\"Sunghyun Kim\",\n        \"author_email\":\"cqbqdd11519@gmail.com\",\n        \"authored_date\":\"2021-04-12T05:07:48.000Z\",\n        \"committer_name\":\"Sunghyun Kim\",\n        \"committer_email\":\"cqbqdd11519@gmail.com\",\n        \"committed_date\":\"2021-04-12T05:07:48.000Z\"\n    },\n    {\n        \"id\":\"dace98c2d0437f6ccacd8b9c8094f4dde9162214\",\n        \"created_at\":\"2021-04-12T05:04:54.000Z\",\n        \"title\":\"Update index.html\",\n        \"message\":\"Update index.html\",\n        \"author_name\":\"Sunghyun Kim\",\n        \"author_email\":\"cqbqdd11519@gmail.com\",\n        \"authored_date\":\"2021-04-12T05:04:54.000Z\",\n        \"committer_name\":\"Sunghyun Kim\",\n        \"committer_email\":\"cqbqdd11519@gmail.com\",\n        \"committed_date\":\"2021-04-12T05:04:54.000Z\"\n    },\n    {\n        \"id\":\"e703f64f722f33c4fbb1f326aed08edc81053b0b\",\n        \"created_at\":\"2021-04-12T04:50:34.000Z\",\n        \"title\":\"Update index.html\",\n        \"message\":\"Update index.html\",\n        \"author_name\":\"Sunghyun Kim\",\n        \"author_email\":\"cqbqdd11519@gmail.com\",\n        \"authored_date\":\"2021-04-12T04:50:34.000Z\",\n        \"committer_name\":\"Sunghyun Kim\",\n        \"committer_email\":\"cqbqdd11519@gmail.com\",\n        \"committed_date\":\"2021-04-12T04:50:34.000Z\"\n    },\n    {\n        \"id\":\"3196ccc37bcae94852079b04fcbfaf928341d6e9\",\n        \"created_at\":\"2021-01-22T03:25:50.000Z\",\n        \"title\":\"newnew\",\n        \"message\":\"newnew\\n\",\n        \"author_name\":\"Sunghyun Kim\",\n        \"author_email\":\"cqbqdd11519@gmail.com\",\n        \"authored_date\":\"2021-01-22T03:25:50.000Z\",\n        \"committer_name\":\"Sunghyun Kim\",\n        \"committer_email\":\"cqbqdd11519@gmail.com\",\n        \"committed_date\":\"2021-01-22T03:25:50.000Z\"\n    }\n]"
 	sampleMR           = "{\"id\":133148669,\"iid\":1,\"project_id\":31228574,\"title\":\"Child directory test\",\"description\":\"\",\"state\":\"opened\",\"created_at\":\"2021-12-30T06:58:09.077Z\",\"updated_at\":\"2021-12-30T07:18:33.391Z\",\"merged_by\":null,\"merged_at\":null,\"closed_by\":null,\"closed_at\":null,\"target_branch\":\"main\",\"source_branch\":\"child-directory-test\",\"user_notes_count\":1,\"upvotes\":0,\"downvotes\":0,\"author\":{\"id\":10192010,\"username\":\"changjjjjjjj\",\"name\":\"Changju Kim\",\"state\":\"active\",\"avatar_url\":\"https://secure.gravatar.com/avatar/c9995fef2d5a47e133b9461fea8cf3d3?s=80\\u0026d=identicon\",\"web_url\":\"https://gitlab.com/changjjjjjjj\"},\"assignees\":[],\"assignee\":null,\"reviewers\":[],\"source_project_id\":31228574,\"target_project_id\":31228574,\"labels\":[\"approved\"],\"draft\":false,\"work_in_progress\":false,\"milestone\":null,\"merge_when_pipeline_succeeds\":false,\"merge_status\":\"can_be_merged\",\"sha\":\"d84e251bf2d84b74e2e5161bcf693cdbb7130f23\",\"merge_commit_sha\":null,\"squash_commit_sha\":null,\"discussion_locked\":null,\"should_remove_source_branch\":null,\"force_remove_source_branch\":true,\"reference\":\"!1\",\"references\":{\"short\":\"!1\",\"relative\":\"!1\",\"full\":\"changjjjjjjj/cd-example-apps!1\"},\"web_url\":\"https://gitlab.com/changjjjjjjj/cd-example-apps/-/merge_requests/1\",\"time_stats\":{\"time_estimate\":0,\"total_time_spent\":0,\"human_time_estimate\":null,\"human_total_time_spent\":null},\"squash\":false,\"task_completion_status\":{\"count\":0,\"completed_count\":0},\"has_conflicts\":false,\"blocking_discussions_resolved\":true,\"approvals_before_merge\":null,\"subscribed\":true,\"changes_count\":\"2\",\"latest_build_started_at\":null,\"latest_build_finished_at\":null,\"first_deployed_to_production_at\":null,\"pipeline\":null,\"head_pipeline\":null,\"diff_refs\":{\"base_sha\":\"e1eb6f3829eee63f55e77fdf6cf2b332d3a91ae0\",\"head_sha\":\"d84e251bf2d84b74e2e5161bcf693cdbb7130f23\",\"start_sha\":\"c37271972e2bb9fe7ada89e2e7ae7045da4fffcb\"},\"merge_error\":null,\"first_contribution\":false,\"user\":{\"can_merge\":true}}"
 	sampleMRNotes      = "[{\"id\":797962489,\"type\":null,\"body\":\"test\",\"attachment\":null,\"author\":{\"id\":10192010,\"username\":\"changjjjjjjj\",\"name\":\"Changju Kim\",\"state\":\"active\",\"avatar_url\":\"https://secure.gravatar.com/avatar/c9995fef2d5a47e133b9461fea8cf3d3?s=80\\u0026d=identicon\",\"web_url\":\"https://gitlab.com/changjjjjjjj\"},\"created_at\":\"2021-12-30T06:58:52.936Z\",\"updated_at\":\"2021-12-30T06:58:52.936Z\",\"system\":false,\"noteable_id\":133148669,\"noteable_type\":\"MergeRequest\",\"resolvable\":false,\"confidential\":false,\"noteable_iid\":1,\"commands_changes\":{}}]"
+	sampleCompare      = `{"commit":{"id":"5f065c6de7dacb91aa5929a5c0ab71ecba5456b0"},"commits":[],"diffs":[{"old_path":"docs/index.md","new_path":"docs/main.md","a_mode":"100644","b_mode":"100644","diff":"@@ -1,2 +1,2 @@\n # Title\n-old\n+new\n","new_file":false,"renamed_file":true,"deleted_file":false}],"compare_timeout":false,"compare_same_ref":false}`
 )
 
 var serverURL string
@@ -98,6 +103,8 @@ func TestClient_ListCommitStatuses(t *testing.T) {
 	assert.Equal(t, "pending", string(statuses[0].State))
 	assert.Equal(t, "test-1", statuses[1].Context)
 	assert.Equal(t, "success", string(statuses[1].State))
+	assert.Equal(t, time.Date(2021, 4, 12, 5, 40, 7, 995000000, time.UTC), statuses[0].UpdatedAt.UTC())
+	assert.Equal(t, time.Date(2021, 4, 12, 8, 38, 51, 996000000, time.UTC), statuses[1].UpdatedAt.UTC())
 	assert.Equal(t, "blocker", statuses[2].Context)
 	assert.Equal(t, "pending", string(statuses[2].State))
 	assert.Equal(t, "test-1", statuses[3].Context)
@@ -134,6 +141,8 @@ func TestClient_ListPullRequests(t *testing.T) {
 	assert.Equal(t, "Newnew", prs[1].Title, "PR Title")
 	assert.Equal(t, 1, prs[2].ID, "PR ID")
 	assert.Equal(t, "newnew", prs[2].Title, "PR Title")
+	assert.Equal(t, []git.User{{ID: 7169076, Name: "cqbqdd11519"}}, prs[2].Assignees, "PR Assignees")
+	assert.Equal(t, "", prs[2].Milestone, "PR Milestone")
 	assert.Equal(t, 3, prs[3].ID, "PR ID")
 	assert.Equal(t, "Newnew", prs[3].Title, "PR Title")
 	assert.Equal(t, 2, prs[4].ID, "PR ID")
@@ -158,6 +167,22 @@ func TestClient_GetPullRequestDiff(t *testing.T) {
 	require.Equal(t, 2, diff.Changes[0].Changes)
 }
 
+func TestClient_CompareCommits(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := c.CompareCommits("3ec1c4b4f6b6a1bb7ccea5e6b4e8e0b4cd2ec8a7", "5f065c6de7dacb91aa5929a5c0ab71ecba5456b0")
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 1)
+	require.Equal(t, "docs/main.md", diff.Changes[0].Filename)
+	require.Equal(t, "docs/index.md", diff.Changes[0].OldFilename)
+	require.Equal(t, 1, diff.Changes[0].Additions)
+	require.Equal(t, 1, diff.Changes[0].Deletions)
+	require.Equal(t, 2, diff.Changes[0].Changes)
+}
+
 func TestClient_ListComments(t *testing.T) {
 	c, err := testEnv()
 	if err != nil {
@@ -165,8 +190,10 @@ func TestClient_ListComments(t *testing.T) {
 	}
 	comments, err := c.ListComments(5)
 	require.NoError(t, err)
-	require.Len(t, comments, 1)
+	// Two pages of the notes
+	require.Len(t, comments, 2)
 	require.Equal(t, "test", comments[0].Comment.Body)
+	require.Equal(t, git.User{ID: 10192010, Name: "changjjjjjjj"}, comments[0].Author)
 }
 
 func TestClient_ListPullRequestCommits(t *testing.T) {
@@ -178,12 +205,254 @@ func TestClient_ListPullRequestCommits(t *testing.T) {
 	commits, err := c.ListPullRequestCommits(5)
 	require.NoError(t, err)
 	require.Len(t, commits, 4)
-	require.Equal(t, "5f065c6de7dacb91aa5929a5c0ab71ecba5456b0", commits[0].SHA)
-	require.Equal(t, "Update index.html", commits[0].Message)
-	require.Equal(t, "Sunghyun Kim", commits[0].Author.Name)
-	require.Equal(t, "cqbqdd11519@gmail.com", commits[0].Author.Email)
-	require.Equal(t, "Sunghyun Kim", commits[0].Committer.Name)
-	require.Equal(t, "cqbqdd11519@gmail.com", commits[0].Committer.Email)
+	// Latest commit comes last
+	require.Equal(t, "5f065c6de7dacb91aa5929a5c0ab71ecba5456b0", commits[3].SHA)
+	require.Equal(t, "Update index.html", commits[3].Message)
+	require.Equal(t, "Sunghyun Kim", commits[3].Author.Name)
+	require.Equal(t, "cqbqdd11519@gmail.com", commits[3].Author.Email)
+	require.Equal(t, "Sunghyun Kim", commits[3].Committer.Name)
+	require.Equal(t, "cqbqdd11519@gmail.com", commits[3].Committer.Email)
+}
+
+func TestClient_GetFile(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := c.GetFile(".cicd/config.yaml", "main")
+	require.NoError(t, err)
+	require.Equal(t, "preSubmit:\n- name: test\n", string(content))
+
+	_, err = c.GetFile(".cicd/config.yaml", "dev")
+	require.Error(t, err)
+}
+
+func TestClient_ListPullRequestApprovers(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approvers, err := c.ListPullRequestApprovers(5)
+	require.NoError(t, err)
+	require.Equal(t, []git.User{{ID: 1, Name: "root", Email: "root@example.com"}, {ID: 7, Name: "reviewer"}}, approvers)
+}
+
+func TestClient_CountUserPullRequests(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := c.CountUserPullRequests(git.User{ID: 11, Name: "user1"})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	count, err = c.CountUserPullRequests(git.User{ID: 12, Name: "user2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = c.CountUserPullRequests(git.User{ID: 13, Name: "user3"})
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+var rebaseRequests []string
+
+func TestClient_UpdatePullRequestBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rebaseRequests = nil
+	require.NoError(t, c.UpdatePullRequestBranch(5, "head-sha"))
+	require.Equal(t, []string{"PUT 5"}, rebaseRequests)
+}
+
+var updateMRRequests []string
+
+func TestClient_AddAssignees(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.AddAssignees(git.IssueTypePullRequest, 1, []string{"user1", "user2"}))
+	require.Equal(t, []string{`PUT 1 {"assignee_ids":[11,12]}`}, updateMRRequests)
+
+	// Unknown user
+	require.Error(t, c.AddAssignees(git.IssueTypePullRequest, 1, []string{"unknown"}))
+}
+
+func TestClient_RemoveAssignees(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.RemoveAssignees(git.IssueTypePullRequest, 1, []string{"user1"}))
+	require.Equal(t, []string{`PUT 1 {"assignee_ids":[]}`}, updateMRRequests)
+}
+
+func TestClient_SetMilestone(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.SetMilestone(git.IssueTypePullRequest, 1, "v0.2.0"))
+	require.NoError(t, c.SetMilestone(git.IssueTypePullRequest, 1, ""))
+	require.Equal(t, []string{`PUT 1 {"milestone_id":22}`, `PUT 1 {"milestone_id":0}`}, updateMRRequests)
+
+	// Unknown milestone
+	err = c.SetMilestone(git.IssueTypePullRequest, 1, "v0.3.0")
+	require.Error(t, err)
+	_, ok := err.(*git.MilestoneNotFoundError)
+	require.True(t, ok)
+}
+
+func TestClient_UpdateIssueState(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.UpdateIssueState(git.IssueTypePullRequest, 1, git.PullRequestStateClosed))
+	require.NoError(t, c.UpdateIssueState(git.IssueTypePullRequest, 1, git.PullRequestStateOpen))
+	require.Equal(t, []string{`PUT 1 {"state_event":"close"}`, `PUT 1 {"state_event":"reopen"}`}, updateMRRequests)
+}
+
+func TestClient_RequestReviewers(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.RequestReviewers(1, []string{"user2"}))
+	require.Equal(t, []string{`PUT 1 {"reviewer_ids":[12]}`}, updateMRRequests)
+}
+
+func TestClient_RemoveReviewRequests(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateMRRequests = nil
+	require.NoError(t, c.RemoveReviewRequests(1, []string{"user2"}))
+	require.Equal(t, []string{`PUT 1 {"reviewer_ids":[]}`}, updateMRRequests)
+}
+
+var cherryPickRequests []string
+
+func TestClient_CreateBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cherryPickRequests = nil
+	require.NoError(t, c.CreateBranch("cherry-pick-1-to-release", "head-sha"))
+	require.Equal(t, []string{"POST cherry-pick-1-to-release head-sha"}, cherryPickRequests)
+}
+
+func TestClient_CherryPick(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cherryPickRequests = nil
+	require.NoError(t, c.CherryPick("release", []string{"pick-1", "pick-2"}))
+	require.Equal(t, []string{`POST pick-1 {"branch":"release"}`, `POST pick-2 {"branch":"release"}`}, cherryPickRequests)
+
+	// Conflict
+	err = c.CherryPick("release", []string{"conflict-1"})
+	require.Error(t, err)
+	_, ok := err.(*git.CherryPickConflictError)
+	require.True(t, ok)
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := c.CreatePullRequest("[release] newnew", "Cherry-pick", "cherry-pick-1-to-release", "release")
+	require.NoError(t, err)
+	require.Equal(t, 4, pr.ID)
+	require.Equal(t, "[release] newnew", pr.Title)
+	require.Equal(t, "release", pr.Base.Ref)
+	require.Equal(t, "cherry-pick-1-to-release", pr.Head.Ref)
+}
+
+var deleteBranchRequests []string
+
+func TestClient_DeleteBranch(t *testing.T) {
+	c, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleteBranchRequests = nil
+	require.NoError(t, c.DeleteBranch("newnew"))
+	require.Equal(t, []string{"DELETE newnew"}, deleteBranchRequests)
+}
+
+func TestClient_parseReleaseWebhook(t *testing.T) {
+	cli, err := testEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := func(action string) string {
+		return fmt.Sprintf(`{"object_kind":"release","action":%q,"name":"Release v0.1.0","tag":"v0.1.0",`+
+			`"project":{"path_with_namespace":"tmax-cloud/cicd-test","web_url":"https://gitlab.com/tmax-cloud/cicd-test"},`+
+			`"commit":{"id":"3196ccc37bcae94852079b04fcbfaf928341d6e9"}}`, action)
+	}
+
+	tc := map[string]struct {
+		body string
+
+		expectedErr     bool
+		expectedWebhook *git.Webhook
+	}{
+		"create": {
+			body: release("create"),
+			expectedWebhook: &git.Webhook{
+				EventType: git.EventTypeRelease,
+				Repo:      git.Repository{Name: "tmax-cloud/cicd-test", URL: "https://gitlab.com/tmax-cloud/cicd-test"},
+				Release:   &git.Release{Name: "Release v0.1.0", Tag: "v0.1.0", Sha: "3196ccc37bcae94852079b04fcbfaf928341d6e9"},
+			},
+		},
+		"update": {
+			body: release("update"),
+		},
+		"invalidBody": {
+			body:        `{"action":`,
+			expectedErr: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			wh, err := cli.parseReleaseWebhook([]byte(c.body))
+			if c.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedWebhook, wh)
+		})
+	}
 }
 
 func testEnv() (*Client, error) {
@@ -192,6 +461,13 @@ func testEnv() (*Client, error) {
 		w.WriteHeader(http.StatusNotFound)
 		_, _ = w.Write([]byte(req.URL.String()))
 	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/repository/files/{path:.+}/raw", func(w http.ResponseWriter, req *http.Request) {
+		if mux.Vars(req)["path"] != ".cicd/config.yaml" || req.URL.Query().Get("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("preSubmit:\n- name: test\n"))
+	})
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/hooks", func(w http.ResponseWriter, req *http.Request) {
 		page := req.URL.Query().Get("page")
 		if page == "" || page == "1" {
@@ -207,6 +483,28 @@ func testEnv() (*Client, error) {
 		_, _ = w.Write([]byte(sampleStatusesList))
 	})
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			body := &CreateMergeRequest{}
+			_ = json.NewDecoder(req.Body).Decode(body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"iid":4,"title":%q,"state":"opened","source_branch":%q,"target_branch":%q}`, body.Title, body.SourceBranch, body.TargetBranch)))
+			return
+		}
+		switch req.URL.Query().Get("author_id") {
+		case "":
+		case "11":
+			w.Header().Set("X-Total", "3")
+			_, _ = w.Write([]byte(`[{"iid":1}]`))
+			return
+		case "12":
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = w.Write([]byte(`[{"iid":2}]`))
+			return
+		default:
+			w.Header().Set("X-Total", "0")
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
 		page := req.URL.Query().Get("page")
 		if page == "" || page == "1" {
 			w.Header().Set("Link", fmt.Sprintf("<%s/%s?state=all&per_page=100&page=2>; rel=\"next\", <%s/%s?state=all&per_page=100&page=3>; rel=\"last\"", serverURL, req.URL.Path, serverURL, req.URL.Path))
@@ -216,13 +514,71 @@ func testEnv() (*Client, error) {
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}/changes", func(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(sampleMRChange))
 	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/repository/compare", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(sampleCompare))
+	})
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}/commits", func(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(sampleMRCommits))
 	})
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(req.Body)
+			updateMRRequests = append(updateMRRequests, req.Method+" "+mux.Vars(req)["iid"]+" "+string(body))
+		}
 		_, _ = w.Write([]byte(sampleMR))
 	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/milestones", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("title") != "v0.2.0" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":22,"iid":2,"title":"v0.2.0"}]`))
+	})
+	r.HandleFunc("/api/v4/users", func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("username") {
+		case "user1":
+			_, _ = w.Write([]byte(`[{"id":11,"username":"user1"}]`))
+		case "user2":
+			_, _ = w.Write([]byte(`[{"id":12,"username":"user2"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}/approvals", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"approved":true,"approved_by":[{"user":{"id":1,"username":"root","email":"root@example.com"}},{"user":{"id":7,"username":"reviewer"}}]}`))
+	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}/rebase", func(w http.ResponseWriter, req *http.Request) {
+		rebaseRequests = append(rebaseRequests, req.Method+" "+mux.Vars(req)["iid"])
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"rebase_in_progress":true}`))
+	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/repository/branches", func(w http.ResponseWriter, req *http.Request) {
+		cherryPickRequests = append(cherryPickRequests, req.Method+" "+req.URL.Query().Get("branch")+" "+req.URL.Query().Get("ref"))
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/repository/commits/{sha}/cherry_pick", func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(mux.Vars(req)["sha"], "conflict") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Sorry, we cannot cherry-pick this commit automatically."}`))
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		cherryPickRequests = append(cherryPickRequests, req.Method+" "+mux.Vars(req)["sha"]+" "+string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.HandleFunc("/api/v4/projects/{org}/{repo}/repository/branches/{branch}", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		deleteBranchRequests = append(deleteBranchRequests, req.Method+" "+mux.Vars(req)["branch"])
+		w.WriteHeader(http.StatusNoContent)
+	})
 	r.HandleFunc("/api/v4/projects/{org}/{repo}/merge_requests/{iid}/notes", func(w http.ResponseWriter, req *http.Request) {
+		page := req.URL.Query().Get("page")
+		if req.Method == http.MethodGet && (page == "" || page == "1") {
+			w.Header().Set("Link", fmt.Sprintf("<%s/%s?per_page=100&page=2>; rel=\"next\", <%s/%s?per_page=100&page=2>; rel=\"last\"", serverURL, req.URL.Path, serverURL, req.URL.Path))
+		}
 		_, _ = w.Write([]byte(sampleMRNotes))
 	})
 
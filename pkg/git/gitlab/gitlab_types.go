@@ -41,10 +41,12 @@ type CommitStatusRequest struct {
 
 // CommitStatusResponse is a response body of getting commit status
 type CommitStatusResponse struct {
-	Name        string `json:"name"`
-	Status      string `json:"status"`
-	Description string `json:"description"`
-	TargetURL   string `json:"target_url"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	Description string   `json:"description"`
+	TargetURL   string   `json:"target_url"`
+	CreatedAt   *v1.Time `json:"created_at"`
+	FinishedAt  *v1.Time `json:"finished_at"`
 }
 
 // CommentBody is a body structure for creating new comment
@@ -58,12 +60,52 @@ type UpdateMergeRequest struct {
 	RemoveLabels string `json:"remove_labels"`
 }
 
+// UpdateAssignees is a struct to update the assignees of an issue or a merge request
+type UpdateAssignees struct {
+	AssigneeIDs []int `json:"assignee_ids"`
+}
+
+// CreateMergeRequest is a struct to create a merge request
+type CreateMergeRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+}
+
+// CherryPickBody is a struct to cherry-pick a commit onto a branch
+type CherryPickBody struct {
+	Branch string `json:"branch"`
+}
+
+// UpdateMilestone is a struct to update the milestone of an issue or a merge request
+type UpdateMilestone struct {
+	MilestoneID int `json:"milestone_id"`
+}
+
+// UpdateState is a body structure for closing/reopening issues and merge requests
+type UpdateState struct {
+	StateEvent string `json:"state_event"`
+}
+
+// Milestone is a milestone of a project
+type Milestone struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// UpdateReviewers is a struct to update the reviewers of a merge request
+type UpdateReviewers struct {
+	ReviewerIDs []int `json:"reviewer_ids"`
+}
+
 // MergeRequest is a body struct of a merge request
 type MergeRequest struct {
-	ID     int    `json:"iid"`
-	Title  string `json:"title"`
-	State  string `json:"state"`
-	Author struct {
+	ID          int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Author      struct {
 		ID       int    `json:"id"`
 		UserName string `json:"username"`
 	} `json:"author"`
@@ -73,6 +115,20 @@ type MergeRequest struct {
 	SHA          string   `json:"sha"`
 	Labels       []string `json:"labels"`
 	HasConflicts bool     `json:"has_conflicts"`
+
+	SourceProjectID int `json:"source_project_id"`
+	TargetProjectID int `json:"target_project_id"`
+
+	Draft          bool `json:"draft"`
+	WorkInProgress bool `json:"work_in_progress"`
+
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Assignees []UserInfo `json:"assignees"`
+	Reviewers []UserInfo `json:"reviewers"`
+
+	UpdatedAt *v1.Time `json:"updated_at"`
 }
 
 // BranchResponse is a respond struct for branch request
@@ -93,13 +149,28 @@ type MergeAcceptRequest struct {
 	RemoveSourceBranch  bool   `json:"should_remove_source_branch"`
 }
 
+// MergeRequestApprovals is a body of merge request approvals get API
+type MergeRequestApprovals struct {
+	ApprovedBy []struct {
+		User UserInfo `json:"user"`
+	} `json:"approved_by"`
+}
+
 // MergeRequestChanges is a changed list of the merge request
 type MergeRequestChanges struct {
-	Changes []struct {
-		OldPath string `json:"old_path"`
-		NewPath string `json:"new_path"`
-		Diff    string `json:"diff"`
-	} `json:"changes"`
+	Changes []DiffChange `json:"changes"`
+}
+
+// CompareResponse is a response of comparing two commits
+type CompareResponse struct {
+	Diffs []DiffChange `json:"diffs"`
+}
+
+// DiffChange is a diff of a changed file
+type DiffChange struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+	Diff    string `json:"diff"`
 }
 
 // CommitResponse is a commits list response
@@ -114,6 +185,7 @@ type CommitResponse struct {
 
 // NoteResponse is a note list response
 type NoteResponse struct {
+	Author    UserInfo `json:"author"`
 	Body      string   `json:"body"`
 	CreatedAt *v1.Time `json:"created_at"`
 }
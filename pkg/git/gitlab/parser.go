@@ -35,14 +35,14 @@ func (c *Client) parsePullRequestWebhook(jsonString []byte) (*git.Webhook, error
 		return nil, err
 	}
 
-	pullRequest := git.PullRequest{ID: data.ObjectAttribute.ID, Title: data.ObjectAttribute.Title, URL: data.Project.WebURL}
+	pullRequest := git.PullRequest{ID: data.ObjectAttribute.ID, Title: data.ObjectAttribute.Title, URL: data.Project.WebURL, Draft: data.ObjectAttribute.Draft || data.ObjectAttribute.WorkInProgress}
 	pullRequest.Author = *author
 	pullRequest.Base = git.Base{Ref: data.ObjectAttribute.BaseRef}
 	pullRequest.Head = git.Head{Ref: data.ObjectAttribute.HeadRef, Sha: data.ObjectAttribute.LastCommit.Sha}
 	repo := git.Repository{Name: data.Project.Name, URL: data.Project.WebURL}
 	pullRequest.Action = git.PullRequestAction(data.ObjectAttribute.Action)
 	switch string(pullRequest.Action) {
-	case "close":
+	case "close", "merge":
 		pullRequest.Action = git.PullRequestActionClose
 	case "open":
 		pullRequest.Action = git.PullRequestActionOpen
@@ -51,6 +51,12 @@ func (c *Client) parsePullRequestWebhook(jsonString []byte) (*git.Webhook, error
 	case "update":
 		if data.ObjectAttribute.OldRev != "" {
 			pullRequest.Action = git.PullRequestActionSynchronize
+		} else if isReadyForReview(data.Changes.Draft) || isReadyForReview(data.Changes.WorkInProgress) {
+			pullRequest.Action = git.PullRequestActionReady
+		} else if isConvertedToDraft(data.Changes.Draft) || isConvertedToDraft(data.Changes.WorkInProgress) {
+			pullRequest.Action = git.PullRequestActionDraft
+		} else if data.Changes.Title != nil {
+			pullRequest.Action = git.PullRequestActionEdited
 		} else if data.Changes.Labels != nil {
 			var isUnlabeled bool
 			pullRequest.LabelChanged, isUnlabeled = diffLabels(data.Changes.Labels.Previous, data.Changes.Labels.Current)
@@ -76,6 +82,9 @@ func (c *Client) parsePullRequestWebhook(jsonString []byte) (*git.Webhook, error
 		pullRequest.State = git.PullRequestStateOpen
 	case "closed":
 		pullRequest.State = git.PullRequestStateClosed
+	case "merged":
+		pullRequest.State = git.PullRequestStateClosed
+		pullRequest.Merged = true
 	}
 
 	for _, l := range data.Labels {
@@ -96,7 +105,18 @@ func (c *Client) parsePushWebhook(jsonString []byte) (*git.Webhook, error) {
 		return nil, nil
 	}
 	sender := git.User{Name: data.UserName, ID: data.UserID}
-	push := git.Push{Ref: data.Ref, Sha: data.Sha}
+	push := git.Push{Ref: data.Ref, Sha: data.Sha, Before: data.Before}
+	// For annotated tags, 'after' is a sha of the tag object, not the commit
+	if strings.HasPrefix(data.Ref, "refs/tags/") && data.CheckoutSha != "" {
+		push.Sha = data.CheckoutSha
+	}
+	for _, commit := range data.Commits {
+		if commit.ID != push.Sha {
+			continue
+		}
+		author := git.User{Name: commit.Author.Name, Email: commit.Author.Email}
+		push.HeadCommit = git.Commit{SHA: commit.ID, Message: commit.Message, Author: author, Committer: author}
+	}
 
 	// Get sender email
 	userInfo, err := c.GetUserInfo(strconv.Itoa(data.UserID))
@@ -107,6 +127,24 @@ func (c *Client) parsePushWebhook(jsonString []byte) (*git.Webhook, error) {
 	return &git.Webhook{EventType: git.EventTypePush, Repo: repo, Sender: sender, Push: &push}, nil
 }
 
+func (c *Client) parseReleaseWebhook(jsonString []byte) (*git.Webhook, error) {
+	var data ReleaseWebhook
+
+	if err := json.Unmarshal(jsonString, &data); err != nil {
+		return nil, err
+	}
+
+	// Only handle creation
+	if data.Action != "create" {
+		return nil, nil
+	}
+
+	repo := git.Repository{Name: data.Project.Name, URL: data.Project.WebURL}
+	release := git.Release{Name: data.Name, Tag: data.Tag, Sha: data.Commit.ID}
+
+	return &git.Webhook{EventType: git.EventTypeRelease, Repo: repo, Release: &release}, nil
+}
+
 func (c *Client) parseIssueComment(jsonString []byte) (*git.Webhook, error) {
 	data := &NoteHook{}
 
@@ -118,7 +156,7 @@ func (c *Client) parseIssueComment(jsonString []byte) (*git.Webhook, error) {
 	switch string(mrState) {
 	case "opened":
 		mrState = git.PullRequestStateOpen
-	case "closed":
+	case "closed", "merged":
 		mrState = git.PullRequestStateClosed
 	}
 
@@ -149,6 +187,7 @@ func (c *Client) parseIssueComment(jsonString []byte) (*git.Webhook, error) {
 			ID:     data.MergeRequest.ID,
 			Title:  data.MergeRequest.Title,
 			State:  mrState,
+			Merged: data.MergeRequest.State == "merged",
 			Author: *mrAuthor,
 			URL:    data.MergeRequest.URL,
 			Base: git.Base{
@@ -162,6 +201,22 @@ func (c *Client) parseIssueComment(jsonString []byte) (*git.Webhook, error) {
 		}
 	}
 
+	// Get the plain issue's info
+	issue := git.Issue{PullRequest: pr}
+	if pr == nil && data.Issue.ID != 0 {
+		issueAuthor, err := c.GetUserInfo(strconv.Itoa(data.Issue.AuthorID))
+		if err != nil {
+			issueAuthor = &git.User{ID: data.Issue.AuthorID}
+		}
+		issue.ID = data.Issue.ID
+		issue.State = git.PullRequestStateOpen
+		if data.Issue.State == "closed" {
+			issue.State = git.PullRequestStateClosed
+		}
+		issue.Author = *issueAuthor
+		issue.URL = data.Issue.URL
+	}
+
 	return &git.Webhook{EventType: git.EventTypeIssueComment, Repo: git.Repository{
 		Name: data.Project.Name,
 		URL:  data.Project.WebURL,
@@ -172,9 +227,7 @@ func (c *Client) parseIssueComment(jsonString []byte) (*git.Webhook, error) {
 				Body:      data.ObjectAttributes.Note,
 				CreatedAt: &metav1.Time{Time: data.ObjectAttributes.CreatedAt.Time},
 			},
-			Issue: git.Issue{
-				PullRequest: pr,
-			},
+			Issue:  issue,
 			Author: *author,
 		}}, nil
 }
@@ -283,3 +336,12 @@ func diffLabels(prev, cur []Label) ([]git.IssueLabel, bool) {
 
 	return diff, isUnlabeled
 }
+
+// isReadyForReview decides if the draft status is changed to ready
+func isReadyForReview(change *BoolChange) bool {
+	return change != nil && change.Previous && !change.Current
+}
+
+func isConvertedToDraft(change *BoolChange) bool {
+	return change != nil && !change.Previous && change.Current
+}
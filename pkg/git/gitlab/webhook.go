@@ -32,6 +32,9 @@ type MergeRequestWebhook struct {
 		State  string `json:"state"`
 		Action string `json:"action"`
 		OldRev string `json:"oldrev"`
+
+		Draft          bool `json:"draft"`
+		WorkInProgress bool `json:"work_in_progress"`
 	} `json:"object_attributes"`
 	Project Project `json:"project"`
 	Labels  []Label `json:"labels"`
@@ -40,9 +43,24 @@ type MergeRequestWebhook struct {
 			Previous []Label `json:"previous"`
 			Current  []Label `json:"current"`
 		} `json:"labels,omitempty"`
+		Draft          *BoolChange   `json:"draft,omitempty"`
+		WorkInProgress *BoolChange   `json:"work_in_progress,omitempty"`
+		Title          *StringChange `json:"title,omitempty"`
 	} `json:"changes"`
 }
 
+// BoolChange is a change of a boolean attribute
+type BoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+// StringChange is a change of a string attribute
+type StringChange struct {
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
 // PushWebhook is a gitlab-specific push event webhook body
 type PushWebhook struct {
 	Kind     string  `json:"object_kind"`
@@ -51,6 +69,32 @@ type PushWebhook struct {
 	UserName string  `json:"user_name"`
 	UserID   int     `json:"user_id"`
 	Sha      string  `json:"after"`
+	Before   string  `json:"before"`
+
+	// CheckoutSha is a sha of the commit, which is different from Sha for annotated tags
+	CheckoutSha string       `json:"checkout_sha"`
+	Commits     []PushCommit `json:"commits"`
+}
+
+// ReleaseWebhook is a gitlab-specific release event webhook body
+type ReleaseWebhook struct {
+	Action  string  `json:"action"`
+	Name    string  `json:"name"`
+	Tag     string  `json:"tag"`
+	Project Project `json:"project"`
+	Commit  struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+// PushCommit is a commit included in the push event webhook body
+type PushCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
 }
 
 // NoteHook is a gitlab-specific issue comment webhook body
@@ -75,6 +119,12 @@ type NoteHook struct {
 			ID string `json:"id"`
 		} `json:"last_commit"`
 	} `json:"merge_request"`
+	Issue struct {
+		ID       int    `json:"iid"`
+		State    string `json:"state"`
+		URL      string `json:"url"`
+		AuthorID int    `json:"author_id"`
+	} `json:"issue"`
 }
 
 // Project is a name/url for the repository
@@ -108,6 +158,7 @@ type RegistrationWebhookBody struct {
 	NoteEvents              bool   `json:"note_events"`
 	PipeLineEvents          bool   `json:"pipeline_events"`
 	PushEvents              bool   `json:"push_events"`
+	ReleasesEvents          bool   `json:"releases_events"`
 	TagPushEvents           bool   `json:"tag_push_events"`
 	WikiPageEvents          bool   `json:"wiki_page_events"`
 	URL                     string `json:"url"`
@@ -10,7 +10,9 @@ import (
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
 	"github.com/tmax-cloud/cicd-operator/pkg/cron"
 	"github.com/tmax-cloud/cicd-operator/pkg/interrupts"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -53,6 +55,13 @@ func (pt *PeriodicTrigger) Start() error {
 
 	interrupts.TickLiteral(func() {
 		start := time.Now()
+		// Get the latest IntegrationConfig, as it might have been updated after the trigger started
+		ic := &cicdv1.IntegrationConfig{}
+		if err := pt.Client.Get(pt.Context, types.NamespacedName{Name: pt.ic.Name, Namespace: pt.ic.Namespace}, ic); err != nil {
+			pt.log.Error(err, "Error getting IntegrationConfig.")
+			return
+		}
+		pt.ic = ic
 		if err := sync(pt.Client, pt.Context, pt.ic, pt.cron, start); err != nil {
 			pt.log.Error(err, "Error syncing periodic jobs.")
 		}
@@ -86,20 +95,23 @@ func sync(IntegrationJobClient client.Client, ctx context.Context, ic *cicdv1.In
 	}
 
 	var errs []error
+	original := ic.DeepCopy()
 	for _, p := range ic.Spec.Jobs.Periodic {
 		j, previousFound := latestJobs[p.Name]
 		if p.Cron == "" {
-			logger.Info("There is No Cron String")
-			return nil
+			logger.Info("There is No Cron String", "job", p.Name)
+			continue
 		} else if cronTriggers.Has(p.Name) {
 			shouldTrigger := j.IsCompleted()
 			if !previousFound || shouldTrigger {
-				integrationJob := generatePeriodic(ic, p.Job)
+				integrationJob := generatePeriodic(ic, p, getBranchSha(IntegrationJobClient, ic, p.Branch))
 				integrationJob.Namespace = ic.Namespace
 				logger.Info("Triggering new run of cron periodic.")
 				if err := IntegrationJobClient.Create(ctx, integrationJob); err != nil {
 					errs = append(errs, err)
+					continue
 				}
+				setLastScheduleTime(ic, p.Name, now)
 			} else {
 				logger.Info("skipping cron periodic")
 			}
@@ -110,11 +122,56 @@ func sync(IntegrationJobClient client.Client, ctx context.Context, ic *cicdv1.In
 		return fmt.Errorf("failed to create %d IntegrationJobs: %v", len(errs), errs)
 	}
 
+	if !equality.Semantic.DeepEqual(original.Status.Periodics, ic.Status.Periodics) {
+		if err := IntegrationJobClient.Status().Patch(ctx, ic, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("error updating periodic status: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func generatePeriodic(config *cicdv1.IntegrationConfig, job cicdv1.Job) *cicdv1.IntegrationJob {
+// setLastScheduleTime sets the last schedule time of the periodic job in the IntegrationConfig's status
+func setLastScheduleTime(ic *cicdv1.IntegrationConfig, name string, t time.Time) {
+	for i := range ic.Status.Periodics {
+		if ic.Status.Periodics[i].Name == name {
+			ic.Status.Periodics[i].LastScheduleTime = &metav1.Time{Time: t}
+			return
+		}
+	}
+	ic.Status.Periodics = append(ic.Status.Periodics, cicdv1.PeriodicStatus{Name: name, LastScheduleTime: &metav1.Time{Time: t}})
+}
+
+// getBranchSha gets the sha of the branch's head commit. Empty string is returned if it cannot be fetched
+func getBranchSha(c client.Client, ic *cicdv1.IntegrationConfig, branch string) string {
+	if branch == "" || ic.Spec.Git.Token == nil {
+		return ""
+	}
+	gitCli, err := utils.GetGitCli(ic, c)
+	if err != nil {
+		return ""
+	}
+	b, err := gitCli.GetBranch(branch)
+	if err != nil {
+		return ""
+	}
+	return b.CommitID
+}
+
+func generatePeriodic(config *cicdv1.IntegrationConfig, periodic cicdv1.Periodic, sha string) *cicdv1.IntegrationJob {
 	jobID := utils.RandomString(20)
+	job := periodic.Job
+
+	// Default branch of the repository is checked out if branch is not set
+	ref := cicdv1.GitRef("HEAD")
+	if periodic.Branch != "" {
+		ref = cicdv1.GitRef("refs/heads/" + periodic.Branch)
+	}
+	var link string
+	if gitHost, err := config.Spec.Git.GetGitHost(); err == nil {
+		link = fmt.Sprintf("%s/%s", gitHost, config.Spec.Git.Repository)
+	}
+
 	return &cicdv1.IntegrationJob{
 		ObjectMeta: generatePeriodicMeta(config.Name, config.Namespace, job.Name, jobID),
 		Spec: cicdv1.IntegrationJobSpec{
@@ -127,14 +184,29 @@ func generatePeriodic(config *cicdv1.IntegrationConfig, job cicdv1.Job) *cicdv1.
 			Workspaces: config.Spec.Workspaces,
 			Refs: cicdv1.IntegrationJobRefs{
 				Repository: config.Spec.Git.Repository,
+				Link:       link,
 				Sender: &cicdv1.IntegrationJobSender{ // comment(jh) : Required value임. 우선은 빈 스트링 넣어놓기
 					Name:  "",
 					Email: "",
 				},
+				Base: cicdv1.IntegrationJobRefsBase{
+					Ref:  ref,
+					Link: link,
+					Sha:  sha,
+				},
 			},
-			PodTemplate: config.Spec.PodTemplate,
-			Timeout:     config.GetDuration(),
-			ParamConfig: config.Spec.ParamConfig,
+			PodTemplate:      config.Spec.PodTemplate,
+			Env:              config.Spec.Env,
+			VolumeMounts:     config.Spec.VolumeMounts,
+			SecurityContext:  config.Spec.SecurityContext,
+			Checkout:         config.Spec.Checkout,
+			Parallelism:      config.Spec.Parallelism,
+			RemoteCluster:    config.Spec.RemoteCluster,
+			GangScheduling:   config.Spec.IJManageSpec.GangScheduling,
+			Timeout:          config.GetDuration(),
+			TTLAfterFinished: config.Spec.IJManageSpec.TTLAfterFinished,
+			Priority:         config.Spec.IJManageSpec.Priority,
+			ParamConfig:      config.Spec.ParamConfig,
 		},
 	}
 }
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -49,14 +50,58 @@ func Test_sync(t *testing.T) {
 	utilruntime.Must(corev1.AddToScheme(s))
 	utilruntime.Must(cicdv1.AddToScheme(s))
 
-	fakeCli := fake.NewClientBuilder().WithScheme(s).Build()
-
 	for name, c := range tc {
 		t.Run(name, func(t *testing.T) {
+			fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(c.ic).Build()
 			start := time.Now()
 			cron := cron.New()
 			err := sync(fakeCli, context.Background(), c.ic, cron, start)
 			require.Equal(t, err, nil)
+
+			// Check last schedule time
+			ic := &cicdv1.IntegrationConfig{}
+			require.NoError(t, fakeCli.Get(context.Background(), types.NamespacedName{Name: c.ic.Name, Namespace: c.ic.Namespace}, ic))
+			require.Len(t, ic.Status.Periodics, 1)
+			require.Equal(t, "test-cron", ic.Status.Periodics[0].Name)
+			require.Equal(t, start.Unix(), ic.Status.Periodics[0].LastScheduleTime.Unix())
+		})
+	}
+}
+
+func Test_generatePeriodic(t *testing.T) {
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-config"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeGitHub, Repository: "tmax-cloud/cicd-operator"},
+		},
+	}
+
+	tc := map[string]struct {
+		periodic cicdv1.Periodic
+		sha      string
+
+		expectedRef cicdv1.GitRef
+	}{
+		"defaultBranch": {
+			periodic:    cicdv1.Periodic{Job: cicdv1.Job{Container: corev1.Container{Name: "nightly"}}, Cron: "@daily"},
+			expectedRef: "HEAD",
+		},
+		"branch": {
+			periodic:    cicdv1.Periodic{Job: cicdv1.Job{Container: corev1.Container{Name: "nightly"}}, Cron: "@daily", Branch: "main"},
+			sha:         "ed1d7e2d3f1a",
+			expectedRef: "refs/heads/main",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := generatePeriodic(ic, c.periodic, c.sha)
+			require.Equal(t, cicdv1.JobTypePeriodic, ij.Spec.ConfigRef.Type)
+			require.Equal(t, "https://github.com/tmax-cloud/cicd-operator", ij.Spec.Refs.Link)
+			require.Equal(t, c.expectedRef, ij.Spec.Refs.Base.Ref)
+			require.Equal(t, c.sha, ij.Spec.Refs.Base.Sha)
+			require.Len(t, ij.Spec.Jobs, 1)
+			require.Equal(t, "nightly", ij.Spec.Jobs[0].Name)
 		})
 	}
 }
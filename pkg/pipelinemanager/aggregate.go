@@ -0,0 +1,138 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// aggregatedJob is a job summarized into the aggregated commit status, with the IntegrationJob which ran it
+type aggregatedJob struct {
+	status cicdv1.JobStatus
+	ij     *cicdv1.IntegrationJob
+}
+
+// listAggregatedJobs returns the latest statuses of the jobs run for the commit the IntegrationJob tests, across the
+// IntegrationJobs of the IntegrationConfig for the commit. An IntegrationJob may run only some of the jobs, e.g., by
+// /test <job> or /retest failed, so the statuses of the other jobs are taken from the former IntegrationJobs
+func (p *pipelineManager) listAggregatedJobs(job *cicdv1.IntegrationJob) ([]aggregatedJob, error) {
+	list := &cicdv1.IntegrationJobList{}
+	if err := p.Client.List(context.Background(), list, client.InNamespace(job.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: job.Spec.ConfigRef.Name}); err != nil {
+		return nil, err
+	}
+
+	var ijs []*cicdv1.IntegrationJob
+	for i := range list.Items {
+		ij := &list.Items[i]
+		// The given IntegrationJob is used instead of the listed one, as its status is being updated
+		if ij.Name == job.Name {
+			continue
+		}
+		if ij.Spec.ConfigRef.Type != job.Spec.ConfigRef.Type || len(ij.Spec.Refs.Pulls) != len(job.Spec.Refs.Pulls) || ij.GetHeadSha() != job.GetHeadSha() {
+			continue
+		}
+		ijs = append(ijs, ij)
+	}
+	ijs = append(ijs, job)
+	sort.SliceStable(ijs, func(i, j int) bool {
+		return ijs[i].CreationTimestamp.Before(&ijs[j].CreationTimestamp)
+	})
+
+	// The latest IntegrationJob running each job wins
+	var jobs []aggregatedJob
+	indices := map[string]int{}
+	for _, ij := range ijs {
+		for _, j := range ij.Status.Jobs {
+			if i, exist := indices[j.Name]; exist {
+				jobs[i] = aggregatedJob{status: j, ij: ij}
+				continue
+			}
+			indices[j.Name] = len(jobs)
+			jobs = append(jobs, aggregatedJob{status: j, ij: ij})
+		}
+	}
+	return jobs, nil
+}
+
+// aggregateCommitStatus summarizes the statuses of the jobs into a single commit status for the IntegrationJob
+// It fails as soon as any of the jobs fails, and its target url is the report of the first failed job
+func aggregateCommitStatus(job *cicdv1.IntegrationJob, jobs []aggregatedJob, context string) git.CommitStatus {
+	succeeded, pending := 0, 0
+	var failed []string
+	var firstFailed *aggregatedJob
+	for i, j := range jobs {
+		switch j.status.State {
+		case cicdv1.CommitStatusStateSuccess:
+			succeeded++
+		case cicdv1.CommitStatusStateFailure, cicdv1.CommitStatusStateError:
+			failed = append(failed, j.status.Name)
+			if firstFailed == nil {
+				firstFailed = &jobs[i]
+			}
+		default:
+			pending++
+		}
+	}
+
+	status := git.CommitStatus{Context: context, State: git.CommitStatusStateSuccess}
+	desc := fmt.Sprintf("%d/%d jobs succeeded", succeeded, len(jobs))
+	if pending > 0 {
+		status.State = git.CommitStatusStatePending
+		desc += fmt.Sprintf(", %d pending", pending)
+	}
+	target, targetIJ := "", job
+	if len(jobs) > 0 {
+		target, targetIJ = jobs[0].status.Name, jobs[0].ij
+	}
+	if len(failed) > 0 {
+		status.State = git.CommitStatusStateFailure
+		desc += fmt.Sprintf(", failed: %s", strings.Join(failed, ", "))
+		target, targetIJ = firstFailed.status.Name, firstFailed.ij
+	}
+
+	// Superseded or cancelled IntegrationJobs are not failures of the jobs
+	if job.Status.Reason == cicdv1.IntegrationJobReasonSuperseded || job.Status.State == cicdv1.IntegrationJobStateCancelled {
+		status.State = git.CommitStatusStateError
+		desc = job.Status.Message
+	}
+
+	if job.Spec.Refs.Pulls != nil {
+		desc = appendBaseShaToDescription(desc, job.Spec.Refs.Base.Sha)
+	} else {
+		desc = appendBaseShaToDescription(desc, "")
+	}
+	status.Description = desc
+	status.TargetURL = getTargetURL(targetIJ, target)
+	return status
+}
+
+// anyChanged checks if the state of any of the jobs is changed
+func anyChanged(stateChanged []bool) bool {
+	for _, changed := range stateChanged {
+		if changed {
+			return true
+		}
+	}
+	return false
+}
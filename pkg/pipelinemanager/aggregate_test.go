@@ -0,0 +1,201 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAggregateCommitStatus(t *testing.T) {
+	configs.CurrentExternalHostName = "cicd.test.com"
+	configs.CommitStatusTargetURLTemplate = ""
+
+	tc := map[string]struct {
+		jobs   []cicdv1.JobStatus
+		state  cicdv1.IntegrationJobState
+		reason cicdv1.IntegrationJobReason
+		msg    string
+
+		expectedState  git.CommitStatusState
+		expectedDesc   string
+		expectedTarget string
+	}{
+		"pending": {
+			jobs: []cicdv1.JobStatus{
+				{Name: "build", State: cicdv1.CommitStatusStateSuccess},
+				{Name: "test", State: cicdv1.CommitStatusStatePending},
+			},
+			expectedState:  git.CommitStatusStatePending,
+			expectedDesc:   "1/2 jobs succeeded, 1 pending",
+			expectedTarget: "build",
+		},
+		"succeeded": {
+			jobs: []cicdv1.JobStatus{
+				{Name: "build", State: cicdv1.CommitStatusStateSuccess},
+				{Name: "test", State: cicdv1.CommitStatusStateSuccess},
+			},
+			expectedState:  git.CommitStatusStateSuccess,
+			expectedDesc:   "2/2 jobs succeeded",
+			expectedTarget: "build",
+		},
+		"failed": {
+			jobs: []cicdv1.JobStatus{
+				{Name: "build", State: cicdv1.CommitStatusStateSuccess},
+				{Name: "test", State: cicdv1.CommitStatusStateFailure},
+				{Name: "lint", State: cicdv1.CommitStatusStateError},
+				{Name: "deploy", State: cicdv1.CommitStatusStatePending},
+			},
+			expectedState:  git.CommitStatusStateFailure,
+			expectedDesc:   "1/4 jobs succeeded, 1 pending, failed: test, lint",
+			expectedTarget: "test",
+		},
+		"superseded": {
+			jobs: []cicdv1.JobStatus{
+				{Name: "build", State: cicdv1.CommitStatusStateError, Message: JobMessageSuperseded},
+			},
+			state:          cicdv1.IntegrationJobStateFailed,
+			reason:         cicdv1.IntegrationJobReasonSuperseded,
+			msg:            "Superseded by IntegrationJob new-ij",
+			expectedState:  git.CommitStatusStateError,
+			expectedDesc:   "Superseded by IntegrationJob new-ij",
+			expectedTarget: "build",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+				Status:     cicdv1.IntegrationJobStatus{State: c.state, Reason: c.reason, Message: c.msg, Jobs: c.jobs},
+			}
+			status := aggregateCommitStatus(ij, jobsOf(ij), "cicd/summary")
+			require.Equal(t, "cicd/summary", status.Context)
+			require.Equal(t, c.expectedState, status.State)
+			require.Equal(t, c.expectedDesc, status.Description)
+			require.Equal(t, "http://cicd.test.com/report/default/test-ij/"+c.expectedTarget, status.TargetURL)
+		})
+	}
+}
+
+func TestPipelineManager_updateGitCommitStatus_aggregated(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	cfg := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git:                   cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			AggregateCommitStatus: &cicdv1.AggregateCommitStatus{},
+		},
+	}
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+			Refs:      cicdv1.IntegrationJobRefs{Base: cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/master", Sha: "sha"}},
+			Jobs:      cicdv1.Jobs{{}, {}},
+		},
+		Status: cicdv1.IntegrationJobStatus{Jobs: []cicdv1.JobStatus{
+			{Name: "build", State: cicdv1.CommitStatusStateSuccess},
+			{Name: "test", State: cicdv1.CommitStatusStatePending},
+		}},
+	}
+
+	gitfake.Repos = map[string]*gitfake.Repo{"test/repo": {CommitStatuses: map[string][]git.CommitStatus{}}}
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).Build(), Scheme: s}
+
+	// Not set if nothing is changed
+	require.NoError(t, p.updateGitCommitStatus(cfg, ij, []bool{false, false}))
+	require.Empty(t, gitfake.Repos["test/repo"].CommitStatuses["sha"])
+
+	require.NoError(t, p.updateGitCommitStatus(cfg, ij, []bool{true, false}))
+	statuses := gitfake.Repos["test/repo"].CommitStatuses["sha"]
+	require.Len(t, statuses, 1)
+	require.Equal(t, "cicd/summary", statuses[0].Context)
+	require.Equal(t, git.CommitStatusStatePending, statuses[0].State)
+}
+
+func TestPipelineManager_updateGitCommitStatus_aggregatedPartial(t *testing.T) {
+	configs.CurrentExternalHostName = "cicd.test.com"
+	configs.CommitStatusTargetURLTemplate = ""
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	cfg := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git:                   cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+			AggregateCommitStatus: &cicdv1.AggregateCommitStatus{},
+		},
+	}
+	now := time.Now()
+	newIJ := func(name, sha string, created time.Time, jobs ...cicdv1.JobStatus) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}, CreationTimestamp: metav1.Time{Time: created}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+				Refs: cicdv1.IntegrationJobRefs{
+					Base:  cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/master", Sha: "22ccae53032027186ba739dfaa473ee61a82b298"},
+					Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Sha: sha}},
+				},
+			},
+			Status: cicdv1.IntegrationJobStatus{Jobs: jobs},
+		}
+	}
+
+	// The full IntegrationJob failed test, and lint is run again by /test lint
+	full := newIJ("full", "sha", now.Add(-time.Hour),
+		cicdv1.JobStatus{Name: "lint", State: cicdv1.CommitStatusStateFailure},
+		cicdv1.JobStatus{Name: "test", State: cicdv1.CommitStatusStateFailure})
+	otherCommit := newIJ("other-commit", "other-sha", now.Add(-time.Minute),
+		cicdv1.JobStatus{Name: "build", State: cicdv1.CommitStatusStateFailure})
+	partial := newIJ("partial", "sha", now, cicdv1.JobStatus{Name: "lint", State: cicdv1.CommitStatusStateSuccess})
+	partial.Spec.Jobs = cicdv1.Jobs{{}}
+
+	gitfake.Repos = map[string]*gitfake.Repo{"test/repo": {CommitStatuses: map[string][]git.CommitStatus{}}}
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(full, otherCommit).Build(), Scheme: s}
+
+	require.NoError(t, p.updateGitCommitStatus(cfg, partial, []bool{true}))
+	statuses := gitfake.Repos["test/repo"].CommitStatuses["sha"]
+	require.Len(t, statuses, 1)
+	require.Equal(t, git.CommitStatusStateFailure, statuses[0].State)
+	require.True(t, strings.HasPrefix(statuses[0].Description, "1/2 jobs succeeded, failed: test"), statuses[0].Description)
+	require.Equal(t, "22ccae53032027186ba739dfaa473ee61a82b298", ParseBaseFromDescription(statuses[0].Description))
+	require.Equal(t, "http://cicd.test.com/report/default/full/test", statuses[0].TargetURL)
+}
+
+// jobsOf returns the jobs of the IntegrationJob to be aggregated
+func jobsOf(job *cicdv1.IntegrationJob) []aggregatedJob {
+	var jobs []aggregatedJob
+	for _, j := range job.Status.Jobs {
+		jobs = append(jobs, aggregatedJob{status: j, ij: job})
+	}
+	return jobs
+}
@@ -0,0 +1,148 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"fmt"
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ArtifactsResultName is a name of the task result, where the upload-artifacts step writes the URLs of the artifacts
+const ArtifactsResultName = "cicd-artifacts"
+
+// artifactsScript uploads each of the artifact paths (expanded by the shell) to the storage configured as the rclone
+// remote 'storage', and writes the URLs of the uploaded artifacts to the task result
+const artifactsScript = `#!/bin/sh
+set -e
+
+touch "$(results.` + ArtifactsResultName + `.path)"
+for ARTIFACT_PATH in $ARTIFACT_PATHS; do
+  if [ ! -e "$ARTIFACT_PATH" ]; then
+    echo "artifact $ARTIFACT_PATH does not exist"
+    continue
+  fi
+  ARTIFACT_KEY="$ARTIFACT_PREFIX/${ARTIFACT_PATH#./}"
+  if [ -d "$ARTIFACT_PATH" ]; then
+    rclone copy "$ARTIFACT_PATH" "storage:$ARTIFACT_BUCKET/$ARTIFACT_KEY"
+  else
+    rclone copyto "$ARTIFACT_PATH" "storage:$ARTIFACT_BUCKET/$ARTIFACT_KEY"
+  fi
+  echo "$ARTIFACT_BASE_URL/${ARTIFACT_PATH#./}" >> "$(results.` + ArtifactsResultName + `.path)"
+done
+`
+
+// uploadArtifacts generates a step uploading the job's artifacts
+// rclone is configured by the environment variables, to use the storage as the remote 'storage'
+func uploadArtifacts(job *cicdv1.IntegrationJob, j *cicdv1.Job) tektonv1beta1.Step {
+	storage := &j.Artifacts.Storage
+	prefix := storage.GetPrefix(job, j.Name)
+
+	step := tektonv1beta1.Step{}
+	step.Name = "upload-artifacts"
+	step.Image = configs.ArtifactImage
+	step.WorkingDir = DefaultWorkingDir
+	if j.WorkingDir != "" {
+		step.WorkingDir = j.WorkingDir
+	}
+	step.Script = artifactsScript
+	step.Env = []corev1.EnvVar{
+		{Name: "ARTIFACT_PATHS", Value: strings.Join(j.Artifacts.Paths, " ")},
+		{Name: "ARTIFACT_BUCKET", Value: storage.Bucket},
+		{Name: "ARTIFACT_PREFIX", Value: prefix},
+		{Name: "ARTIFACT_BASE_URL", Value: storage.GetURL(prefix)},
+	}
+
+	switch storage.Type {
+	case cicdv1.ArtifactStorageTypeGCS:
+		step.Env = append(step.Env,
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_TYPE", Value: "google cloud storage"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_BUCKET_POLICY_ONLY", Value: "true"},
+			secretEnv("RCLONE_CONFIG_STORAGE_SERVICE_ACCOUNT_CREDENTIALS", storage.CredentialsSecret, cicdv1.ArtifactCredentialsServiceAccountKey),
+		)
+	default:
+		provider := "AWS"
+		if storage.Endpoint != "" {
+			provider = "Other"
+		}
+		step.Env = append(step.Env,
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_TYPE", Value: "s3"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_PROVIDER", Value: provider},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_ENDPOINT", Value: storage.Endpoint},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_STORAGE_REGION", Value: storage.GetRegion()},
+			secretEnv("RCLONE_CONFIG_STORAGE_ACCESS_KEY_ID", storage.CredentialsSecret, cicdv1.ArtifactCredentialsAccessKeyID),
+			secretEnv("RCLONE_CONFIG_STORAGE_SECRET_ACCESS_KEY", storage.CredentialsSecret, cicdv1.ArtifactCredentialsSecretAccessKey),
+		)
+	}
+
+	return step
+}
+
+func secretEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// getArtifacts parses the URLs of the artifacts from the task results
+func getArtifacts(results []tektonv1beta1.TaskRunResult) []string {
+	var artifacts []string
+	for _, r := range results {
+		if r.Name != ArtifactsResultName {
+			continue
+		}
+		for _, line := range strings.Split(r.Value, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				artifacts = append(artifacts, line)
+			}
+		}
+	}
+	return artifacts
+}
+
+// commentArtifacts posts the URLs of the job's artifacts to the pull request
+func (p *pipelineManager) commentArtifacts(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error {
+	if len(jobStatus.Artifacts) == 0 || len(ij.Spec.Refs.Pulls) != 1 || cfg.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(cfg, p.Client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, ij.Spec.Refs.Pulls[0].ID, generateArtifactsComment(jobStatus, ij))
+}
+
+func generateArtifactsComment(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob) string {
+	comment := fmt.Sprintf("[ARTIFACTS]\n\nArtifacts of job `%s` (IntegrationJob `%s`, commit %s)\n", jobStatus.Name, ij.Name, ij.Spec.Refs.Pulls[0].Sha)
+	for _, a := range jobStatus.Artifacts {
+		comment += fmt.Sprintf("- %s\n", a)
+	}
+	return comment
+}
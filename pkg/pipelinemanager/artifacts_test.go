@@ -0,0 +1,193 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUploadArtifacts(t *testing.T) {
+	tc := map[string]struct {
+		storage cicdv1.ArtifactStorage
+
+		expectedEnvs       map[string]string
+		expectedSecretEnvs map[string]string
+	}{
+		"s3": {
+			storage: cicdv1.ArtifactStorage{Type: cicdv1.ArtifactStorageTypeS3, Bucket: "artifacts", CredentialsSecret: "s3-cred"},
+			expectedEnvs: map[string]string{
+				"ARTIFACT_PATHS":                 "bin/* coverage.out",
+				"ARTIFACT_BUCKET":                "artifacts",
+				"ARTIFACT_PREFIX":                "default/test-ij/build",
+				"ARTIFACT_BASE_URL":              "https://artifacts.s3.us-east-1.amazonaws.com/default/test-ij/build",
+				"RCLONE_CONFIG_STORAGE_TYPE":     "s3",
+				"RCLONE_CONFIG_STORAGE_PROVIDER": "AWS",
+				"RCLONE_CONFIG_STORAGE_ENDPOINT": "",
+				"RCLONE_CONFIG_STORAGE_REGION":   "us-east-1",
+			},
+			expectedSecretEnvs: map[string]string{
+				"RCLONE_CONFIG_STORAGE_ACCESS_KEY_ID":     "accessKeyId",
+				"RCLONE_CONFIG_STORAGE_SECRET_ACCESS_KEY": "secretAccessKey",
+			},
+		},
+		"minio": {
+			storage: cicdv1.ArtifactStorage{Type: cicdv1.ArtifactStorageTypeS3, Bucket: "artifacts", Prefix: "builds", Endpoint: "http://minio:9000", CredentialsSecret: "s3-cred"},
+			expectedEnvs: map[string]string{
+				"ARTIFACT_PATHS":                 "bin/* coverage.out",
+				"ARTIFACT_BUCKET":                "artifacts",
+				"ARTIFACT_PREFIX":                "builds",
+				"ARTIFACT_BASE_URL":              "http://minio:9000/artifacts/builds",
+				"RCLONE_CONFIG_STORAGE_TYPE":     "s3",
+				"RCLONE_CONFIG_STORAGE_PROVIDER": "Other",
+				"RCLONE_CONFIG_STORAGE_ENDPOINT": "http://minio:9000",
+				"RCLONE_CONFIG_STORAGE_REGION":   "us-east-1",
+			},
+			expectedSecretEnvs: map[string]string{
+				"RCLONE_CONFIG_STORAGE_ACCESS_KEY_ID":     "accessKeyId",
+				"RCLONE_CONFIG_STORAGE_SECRET_ACCESS_KEY": "secretAccessKey",
+			},
+		},
+		"gcs": {
+			storage: cicdv1.ArtifactStorage{Type: cicdv1.ArtifactStorageTypeGCS, Bucket: "artifacts", CredentialsSecret: "s3-cred"},
+			expectedEnvs: map[string]string{
+				"ARTIFACT_PATHS":                           "bin/* coverage.out",
+				"ARTIFACT_BUCKET":                          "artifacts",
+				"ARTIFACT_PREFIX":                          "default/test-ij/build",
+				"ARTIFACT_BASE_URL":                        "https://storage.googleapis.com/artifacts/default/test-ij/build",
+				"RCLONE_CONFIG_STORAGE_TYPE":               "google cloud storage",
+				"RCLONE_CONFIG_STORAGE_BUCKET_POLICY_ONLY": "true",
+			},
+			expectedSecretEnvs: map[string]string{
+				"RCLONE_CONFIG_STORAGE_SERVICE_ACCOUNT_CREDENTIALS": "serviceAccountKey",
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ij := &cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}
+			j := &cicdv1.Job{
+				Container: corev1.Container{Name: "build"},
+				Artifacts: &cicdv1.JobArtifacts{Paths: []string{"bin/*", "coverage.out"}, Storage: c.storage},
+			}
+
+			step := uploadArtifacts(ij, j)
+			require.Equal(t, "upload-artifacts", step.Name)
+			require.Equal(t, DefaultWorkingDir, step.WorkingDir)
+
+			envs := map[string]string{}
+			secretEnvs := map[string]string{}
+			for _, e := range step.Env {
+				if e.ValueFrom != nil {
+					require.Equal(t, "s3-cred", e.ValueFrom.SecretKeyRef.Name)
+					secretEnvs[e.Name] = e.ValueFrom.SecretKeyRef.Key
+					continue
+				}
+				envs[e.Name] = e.Value
+			}
+			require.Equal(t, c.expectedEnvs, envs)
+			require.Equal(t, c.expectedSecretEnvs, secretEnvs)
+		})
+	}
+}
+
+func TestGenerateTask_artifacts(t *testing.T) {
+	ij := &cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}
+	j := &cicdv1.Job{
+		Container: corev1.Container{Name: "build", Image: "golang:1.17", WorkingDir: "/src"},
+		Artifacts: &cicdv1.JobArtifacts{Paths: []string{"bin/*"}, Storage: cicdv1.ArtifactStorage{Type: cicdv1.ArtifactStorageTypeS3, Bucket: "artifacts"}},
+	}
+
+	task, _, err := generateTask(ij, j)
+	require.NoError(t, err)
+	require.Len(t, task.TaskSpec.Steps, 3)
+	require.Equal(t, "upload-artifacts", task.TaskSpec.Steps[2].Name)
+	require.Equal(t, "/src", task.TaskSpec.Steps[2].WorkingDir)
+	require.Equal(t, []tektonv1beta1.TaskResult{{Name: ArtifactsResultName, Description: "URLs of the uploaded artifacts"}}, task.TaskSpec.Results)
+}
+
+func TestPipelineManager_reflectJobStatus_artifacts(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	cfg := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+		},
+	}
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+			Refs:      cicdv1.IntegrationJobRefs{Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Sha: "sha"}}},
+		},
+	}
+	j := &cicdv1.Job{
+		Container: corev1.Container{Name: "build"},
+		Artifacts: &cicdv1.JobArtifacts{Paths: []string{"bin/*"}, Comment: true},
+	}
+	now := metav1.Now()
+	pr := &tektonv1beta1.PipelineRun{
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					"build-run": {
+						PipelineTaskName: "build",
+						Status: &tektonv1beta1.TaskRunStatus{
+							TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+								PodName:        "build-run-pod",
+								StartTime:      &now,
+								CompletionTime: &now,
+								TaskRunResults: []tektonv1beta1.TaskRunResult{
+									{Name: ArtifactsResultName, Value: "http://minio/artifacts/app-amd64\nhttp://minio/artifacts/app-arm64\n"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo": {Comments: map[int][]git.IssueComment{}},
+	}
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(cfg).Build(), Scheme: s}
+
+	jStatus := &cicdv1.JobStatus{Name: "build", State: cicdv1.CommitStatusStatePending}
+	p.reflectJobStatus(pr, j, jStatus, ij, cfg)
+	require.Equal(t, []string{"http://minio/artifacts/app-amd64", "http://minio/artifacts/app-arm64"}, jStatus.Artifacts)
+	require.Len(t, gitfake.Repos["test/repo"].Comments[1], 1)
+	require.Equal(t, "[ARTIFACTS]\n\nArtifacts of job `build` (IntegrationJob `test-ij`, commit sha)\n"+
+		"- http://minio/artifacts/app-amd64\n- http://minio/artifacts/app-arm64\n", gitfake.Repos["test/repo"].Comments[1][0].Comment.Body)
+
+	// Comment is posted only once
+	p.reflectJobStatus(pr, j, jStatus, ij, cfg)
+	require.Len(t, gitfake.Repos["test/repo"].Comments[1], 1)
+}
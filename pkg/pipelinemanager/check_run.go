@@ -0,0 +1,103 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"fmt"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+)
+
+// setCheckRunOutput sets the markdown summary of the job and the annotations of its lint issues to the status, which
+// are reported only if spec.git.checkRuns is set
+func setCheckRunOutput(status *git.CommitStatus, cfg *cicdv1.IntegrationConfig, jobStatus *cicdv1.JobStatus) {
+	if !cfg.Spec.Git.CheckRuns {
+		return
+	}
+	status.Summary = generateCheckRunSummary(jobStatus, status.TargetURL)
+	if jobStatus.Lint == nil {
+		return
+	}
+
+	level := git.AnnotationLevelWarning
+	if jobStatus.State == cicdv1.CommitStatusStateFailure {
+		level = git.AnnotationLevelFailure
+	}
+	for _, issue := range jobStatus.Lint.Issues {
+		status.Annotations = append(status.Annotations, git.Annotation{
+			Path:    issue.Path,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Level:   level,
+			Message: issue.Message,
+		})
+	}
+}
+
+// generateCheckRunSummary generates a markdown summary of the job, with its test results, coverage and lint issues
+func generateCheckRunSummary(jobStatus *cicdv1.JobStatus, targetURL string) string {
+	summary := fmt.Sprintf("Job `%s` is **%s**", jobStatus.Name, jobStatus.State)
+	if jobStatus.Message != "" {
+		summary += ": " + jobStatus.Message
+	}
+	summary += "\n"
+
+	if report := jobStatus.TestReport; report != nil {
+		summary += "\n#### Test results\n\n| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n"
+		summary += fmt.Sprintf("| %d | %d | %d | %d |\n", report.Total, report.Passed, report.Failed, report.Skipped)
+		for _, t := range report.FailedTests {
+			summary += fmt.Sprintf("- `%s`\n", t)
+		}
+		if more := report.Failed - len(report.FailedTests); more > 0 {
+			summary += fmt.Sprintf("- ... and %d more\n", more)
+		}
+	}
+
+	if coverage := jobStatus.Coverage; coverage != nil {
+		summary += "\n#### Coverage\n\n" + coverage.String()
+		if delta := coverage.GetDelta(); delta != "" {
+			summary += fmt.Sprintf(" (%s%% versus the base branch)", delta)
+		}
+		summary += "\n"
+	}
+
+	if lint := jobStatus.Lint; lint != nil {
+		summary += fmt.Sprintf("\n#### Lint issues\n\n%d issue(s)", lint.Total)
+		if more := lint.Total - len(lint.Issues); more > 0 {
+			summary += fmt.Sprintf(", %d of them are not annotated", more)
+		}
+		summary += "\n"
+	}
+
+	if targetURL != "" {
+		summary += fmt.Sprintf("\n[Logs](%s)\n", targetURL)
+	}
+	return summary
+}
+
+// generateAggregateCheckRunSummary generates a markdown table of the states of the aggregated jobs, for the aggregated
+// check run
+func generateAggregateCheckRunSummary(jobs []aggregatedJob) string {
+	var lines []string
+	lines = append(lines, "| Job | State | Message |", "|---|---|---|")
+	for _, j := range jobs {
+		lines = append(lines, fmt.Sprintf("| [%s](%s) | %s | %s |", j.status.Name, getTargetURL(j.ij, j.status.Name), j.status.State, strings.ReplaceAll(j.status.Message, "|", "\\|")))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
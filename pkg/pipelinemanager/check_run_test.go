@@ -0,0 +1,104 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCheckRunOutput(t *testing.T) {
+	jobStatus := &cicdv1.JobStatus{
+		Name:       "lint",
+		State:      cicdv1.CommitStatusStateFailure,
+		Message:    "Task lint has failed",
+		TestReport: &cicdv1.JobTestReport{Total: 3, Passed: 1, Failed: 2, FailedTests: []string{"TestA"}},
+		Coverage:   &cicdv1.JobCoverageReport{Covered: 333, Total: 400, Percentage: "83.25", BasePercentage: "81.75"},
+		Lint: &cicdv1.JobLintReport{Total: 3, Issues: []cicdv1.JobLintIssue{
+			{Path: "pkg/a.go", Line: 12, Column: 5, Message: "ineffectual assignment to err"},
+			{Path: "pkg/b.go", Line: 3, Message: "missing doc comment"},
+		}},
+	}
+
+	tc := map[string]struct {
+		checkRuns bool
+		state     cicdv1.CommitStatusState
+
+		expectedSummary     string
+		expectedAnnotations []git.Annotation
+	}{
+		"checkRuns": {
+			checkRuns: true,
+			state:     cicdv1.CommitStatusStateFailure,
+			expectedSummary: "Job `lint` is **failure**: Task lint has failed\n" +
+				"\n#### Test results\n\n| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n| 3 | 1 | 2 | 0 |\n- `TestA`\n- ... and 1 more\n" +
+				"\n#### Coverage\n\n83.25% (333/400 lines) (+1.50% versus the base branch)\n" +
+				"\n#### Lint issues\n\n3 issue(s), 1 of them are not annotated\n" +
+				"\n[Logs](http://report/lint)\n",
+			expectedAnnotations: []git.Annotation{
+				{Path: "pkg/a.go", Line: 12, Column: 5, Level: git.AnnotationLevelFailure, Message: "ineffectual assignment to err"},
+				{Path: "pkg/b.go", Line: 3, Level: git.AnnotationLevelFailure, Message: "missing doc comment"},
+			},
+		},
+		"checkRunsSucceeded": {
+			checkRuns: true,
+			state:     cicdv1.CommitStatusStateSuccess,
+			expectedSummary: "Job `lint` is **success**: Task lint has failed\n" +
+				"\n#### Test results\n\n| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n| 3 | 1 | 2 | 0 |\n- `TestA`\n- ... and 1 more\n" +
+				"\n#### Coverage\n\n83.25% (333/400 lines) (+1.50% versus the base branch)\n" +
+				"\n#### Lint issues\n\n3 issue(s), 1 of them are not annotated\n" +
+				"\n[Logs](http://report/lint)\n",
+			expectedAnnotations: []git.Annotation{
+				{Path: "pkg/a.go", Line: 12, Column: 5, Level: git.AnnotationLevelWarning, Message: "ineffectual assignment to err"},
+				{Path: "pkg/b.go", Line: 3, Level: git.AnnotationLevelWarning, Message: "missing doc comment"},
+			},
+		},
+		"commitStatuses": {
+			state: cicdv1.CommitStatusStateFailure,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cfg := &cicdv1.IntegrationConfig{Spec: cicdv1.IntegrationConfigSpec{Git: cicdv1.GitConfig{CheckRuns: c.checkRuns}}}
+			j := jobStatus.DeepCopy()
+			j.State = c.state
+
+			status := &git.CommitStatus{Context: "lint", TargetURL: "http://report/lint"}
+			setCheckRunOutput(status, cfg, j)
+			require.Equal(t, c.expectedSummary, status.Summary)
+			require.Equal(t, c.expectedAnnotations, status.Annotations)
+		})
+	}
+}
+
+func TestGenerateAggregateCheckRunSummary(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Status: cicdv1.IntegrationJobStatus{Jobs: []cicdv1.JobStatus{
+			{Name: "lint", State: cicdv1.CommitStatusStateSuccess, Message: "All Steps have completed executing"},
+			{Name: "test", State: cicdv1.CommitStatusStateFailure, Message: "a | b"},
+		}},
+	}
+	require.Equal(t, "| Job | State | Message |\n|---|---|---|\n"+
+		"| [lint]("+job.GetReportServerAddress("lint")+") | success | All Steps have completed executing |\n"+
+		"| [test]("+job.GetReportServerAddress("test")+") | failure | a \\| b |\n", generateAggregateCheckRunSummary(jobsOf(job)))
+}
@@ -0,0 +1,214 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CoverageResultName is a name of the task result, where the coverage step writes the line coverage
+const CoverageResultName = "cicd-coverage"
+
+// coverageScript parses the coverage report into the task result, as lines of 'covered <n>' and 'total <n>'
+// Cobertura reports having only the line-rate also give a line of 'rate <rate>'
+const coverageScript = `#!/bin/sh
+touch "$(results.` + CoverageResultName + `.path)"
+
+if [ ! -f "$COVERAGE_PATH" ]; then
+  echo "coverage report $COVERAGE_PATH does not exist"
+elif [ "$COVERAGE_FORMAT" = "lcov" ]; then
+  awk -F: '
+/^LF:/ { total += $2 }
+/^LH:/ { covered += $2 }
+END {
+  print "covered " covered + 0
+  print "total " total + 0
+}
+' "$COVERAGE_PATH" > "$(results.` + CoverageResultName + `.path)" || echo "cannot parse the coverage report"
+  cat "$(results.` + CoverageResultName + `.path)"
+else
+  awk '
+function attr(s, n) {
+  if (match(s, "[ \t\r\n]" n "=\"[^\"]*\"")) {
+    return substr(s, RSTART + length(n) + 3, RLENGTH - length(n) - 4)
+  }
+  return ""
+}
+BEGIN { RS = "<" }
+/^coverage[ \t\r\n]/ {
+  print "covered " attr($0, "lines-covered")
+  print "total " attr($0, "lines-valid")
+  print "rate " attr($0, "line-rate")
+  exit
+}
+' "$COVERAGE_PATH" > "$(results.` + CoverageResultName + `.path)" || echo "cannot parse the coverage report"
+  cat "$(results.` + CoverageResultName + `.path)"
+fi
+`
+
+// parseCoverage generates a step parsing the job's coverage report
+func parseCoverage(j *cicdv1.Job) tektonv1beta1.Step {
+	step := tektonv1beta1.Step{}
+	step.Name = "coverage"
+	step.Image = configs.TestReportImage
+	step.WorkingDir = DefaultWorkingDir
+	if j.WorkingDir != "" {
+		step.WorkingDir = j.WorkingDir
+	}
+	step.Script = coverageScript
+	step.Env = []corev1.EnvVar{
+		{Name: "COVERAGE_PATH", Value: j.Coverage.Path},
+		{Name: "COVERAGE_FORMAT", Value: string(j.Coverage.Format)},
+	}
+	return step
+}
+
+// getCoverage parses the line coverage from the task results
+func getCoverage(results []tektonv1beta1.TaskRunResult) *cicdv1.JobCoverageReport {
+	for _, r := range results {
+		if r.Name != CoverageResultName {
+			continue
+		}
+		report := &cicdv1.JobCoverageReport{}
+		rate := -1.0
+		for _, line := range strings.Split(r.Value, "\n") {
+			tokens := strings.SplitN(strings.TrimSpace(line), " ", 2)
+			if len(tokens) != 2 {
+				continue
+			}
+			switch tokens[0] {
+			case "covered":
+				report.Covered, _ = strconv.Atoi(tokens[1])
+			case "total":
+				report.Total, _ = strconv.Atoi(tokens[1])
+			case "rate":
+				if f, err := strconv.ParseFloat(tokens[1], 64); err == nil {
+					rate = f
+				}
+			}
+		}
+		switch {
+		case report.Total > 0:
+			report.Percentage = cicdv1.FormatCoveragePercentage(float64(report.Covered) * 100 / float64(report.Total))
+		case rate >= 0:
+			report.Percentage = cicdv1.FormatCoveragePercentage(rate * 100)
+		default:
+			return nil
+		}
+		return report
+	}
+	return nil
+}
+
+// getBaseCoverage returns the line coverage of the job of the latest postSubmit IntegrationJob for the pull request's
+// base branch. It returns an empty string if it's not found
+func (p *pipelineManager) getBaseCoverage(ij *cicdv1.IntegrationJob, jobName string) string {
+	if len(ij.Spec.Refs.Pulls) != 1 {
+		return ""
+	}
+
+	ijList := &cicdv1.IntegrationJobList{}
+	if err := p.Client.List(context.Background(), ijList, client.InNamespace(ij.Namespace), client.MatchingLabels{cicdv1.JobLabelConfig: ij.Spec.ConfigRef.Name}); err != nil {
+		log.Error(err, "cannot list IntegrationJobs for the base coverage")
+		return ""
+	}
+
+	branch := ij.Spec.Refs.Base.Ref.GetBranch()
+	var latest *cicdv1.IntegrationJob
+	var coverage string
+	for i := range ijList.Items {
+		base := &ijList.Items[i]
+		if base.Spec.ConfigRef.Type != cicdv1.JobTypePostSubmit || base.Spec.Refs.Base.Ref.GetBranch() != branch || base.Status.CompletionTime == nil {
+			continue
+		}
+		if latest != nil && !latest.Status.CompletionTime.Before(base.Status.CompletionTime) {
+			continue
+		}
+		for _, j := range base.Status.Jobs {
+			if j.Name == jobName && j.Coverage != nil {
+				latest = base
+				coverage = j.Coverage.Percentage
+				break
+			}
+		}
+	}
+	return coverage
+}
+
+// coverageCommitStatus generates a commit status checking the coverage against the job's threshold
+// It returns nil if the threshold is not set or the job is not completed
+func coverageCommitStatus(j *cicdv1.Job, jobStatus *cicdv1.JobStatus) *git.CommitStatus {
+	if j.Coverage == nil || j.Coverage.Threshold == 0 || jobStatus.CompletionTime == nil || jobStatus.Message == JobMessageSkipped || jobStatus.State == cicdv1.CommitStatusStateError {
+		return nil
+	}
+
+	status := &git.CommitStatus{Context: j.Name + "/coverage"}
+	switch {
+	case jobStatus.Coverage == nil:
+		status.State = git.CommitStatusStateFailure
+		status.Description = "Coverage is not reported"
+	case jobStatus.Coverage.IsBelow(j.Coverage.Threshold):
+		status.State = git.CommitStatusStateFailure
+		status.Description = fmt.Sprintf("Coverage %s%% is lower than the threshold %d%%", jobStatus.Coverage.Percentage, j.Coverage.Threshold)
+	default:
+		status.State = git.CommitStatusStateSuccess
+		status.Description = fmt.Sprintf("Coverage %s%% meets the threshold %d%%", jobStatus.Coverage.Percentage, j.Coverage.Threshold)
+	}
+	return status
+}
+
+// commentCoverage posts the line coverage of the job and its delta versus the base branch to the pull request
+func (p *pipelineManager) commentCoverage(j *cicdv1.Job, jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error {
+	if jobStatus.Coverage == nil || len(ij.Spec.Refs.Pulls) != 1 || cfg.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(cfg, p.Client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, ij.Spec.Refs.Pulls[0].ID, generateCoverageComment(j, jobStatus, ij))
+}
+
+func generateCoverageComment(j *cicdv1.Job, jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob) string {
+	coverage := jobStatus.Coverage
+	comment := fmt.Sprintf("[COVERAGE]\n\nCoverage of job `%s` (IntegrationJob `%s`, commit %s)\n\n", jobStatus.Name, ij.Name, ij.Spec.Refs.Pulls[0].Sha)
+	comment += fmt.Sprintf("| Coverage | Base (%s) | Delta |\n|---|---|---|\n", ij.Spec.Refs.Base.Ref.GetBranch())
+
+	base, delta := "-", "-"
+	if d := coverage.GetDelta(); d != "" {
+		base = coverage.BasePercentage + "%"
+		delta = d + "%"
+	}
+	comment += fmt.Sprintf("| %s | %s | %s |\n", coverage.String(), base, delta)
+
+	if j.Coverage != nil && j.Coverage.Threshold > 0 && coverage.IsBelow(j.Coverage.Threshold) {
+		comment += fmt.Sprintf("\nCoverage is lower than the threshold %d%%\n", j.Coverage.Threshold)
+	}
+	return comment
+}
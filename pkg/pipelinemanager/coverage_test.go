@@ -0,0 +1,258 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateSteps_coverage(t *testing.T) {
+	tc := map[string]struct {
+		job *cicdv1.Job
+
+		expectedSteps []string
+	}{
+		"coverage": {
+			job: &cicdv1.Job{
+				Container: corev1.Container{Name: "test", Image: "golang:1.17"},
+				Script:    "go test -coverprofile=cover.out ./... && gocover-cobertura < cover.out > coverage.xml",
+				Coverage:  &cicdv1.JobCoverage{Path: "coverage.xml", Format: cicdv1.CoverageFormatCobertura},
+			},
+			expectedSteps: []string{"git-clone", "step-0", "coverage"},
+		},
+		"testReports": {
+			job: &cicdv1.Job{
+				Container:   corev1.Container{Name: "test", Image: "golang:1.17"},
+				Script:      "make test",
+				TestReports: &cicdv1.JobTestReports{Paths: []string{"report.xml"}},
+				Coverage:    &cicdv1.JobCoverage{Path: "lcov.info", Format: cicdv1.CoverageFormatLcov},
+			},
+			expectedSteps: []string{"git-clone", "step-0", "test-report", "coverage"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			steps, err := generateSteps(&cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}, c.job)
+			require.NoError(t, err)
+
+			var names []string
+			for _, s := range steps {
+				names = append(names, s.Name)
+			}
+			require.Equal(t, c.expectedSteps, names)
+			require.Contains(t, steps[1].Script, deferredExitCodePath)
+
+			// Only the last step exits with the script's exit code
+			last := steps[len(steps)-1]
+			require.True(t, strings.HasSuffix(last.Script, deferredExitScript))
+			for _, s := range steps[2 : len(steps)-1] {
+				require.NotContains(t, s.Script, deferredExitScript)
+			}
+			require.Equal(t, []corev1.EnvVar{{Name: "COVERAGE_PATH", Value: c.job.Coverage.Path}, {Name: "COVERAGE_FORMAT", Value: string(c.job.Coverage.Format)}}, last.Env)
+		})
+	}
+}
+
+func TestGetCoverage(t *testing.T) {
+	tc := map[string]struct {
+		results []tektonv1beta1.TaskRunResult
+
+		expectedCoverage *cicdv1.JobCoverageReport
+	}{
+		"noResult": {
+			results: []tektonv1beta1.TaskRunResult{{Name: "other", Value: "covered 1\ntotal 2"}},
+		},
+		"noReport": {
+			results: []tektonv1beta1.TaskRunResult{{Name: CoverageResultName, Value: ""}},
+		},
+		"noLines": {
+			results: []tektonv1beta1.TaskRunResult{{Name: CoverageResultName, Value: "covered 0\ntotal 0\n"}},
+		},
+		"lines": {
+			results:          []tektonv1beta1.TaskRunResult{{Name: CoverageResultName, Value: "covered 333\ntotal 400\nrate 0.8325\n"}},
+			expectedCoverage: &cicdv1.JobCoverageReport{Covered: 333, Total: 400, Percentage: "83.25"},
+		},
+		"rate": {
+			results:          []tektonv1beta1.TaskRunResult{{Name: CoverageResultName, Value: "covered \ntotal \nrate 0.5\n"}},
+			expectedCoverage: &cicdv1.JobCoverageReport{Percentage: "50.00"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedCoverage, getCoverage(c.results))
+		})
+	}
+}
+
+func TestCoverageCommitStatus(t *testing.T) {
+	now := metav1.Now()
+	tc := map[string]struct {
+		coverage  *cicdv1.JobCoverage
+		jobStatus *cicdv1.JobStatus
+
+		expectedStatus *git.CommitStatus
+	}{
+		"noThreshold": {
+			coverage:  &cicdv1.JobCoverage{Path: "coverage.xml"},
+			jobStatus: &cicdv1.JobStatus{CompletionTime: &now, Coverage: &cicdv1.JobCoverageReport{Percentage: "10.00"}},
+		},
+		"running": {
+			coverage:  &cicdv1.JobCoverage{Path: "coverage.xml", Threshold: 80},
+			jobStatus: &cicdv1.JobStatus{State: cicdv1.CommitStatusStatePending},
+		},
+		"skipped": {
+			coverage:  &cicdv1.JobCoverage{Path: "coverage.xml", Threshold: 80},
+			jobStatus: &cicdv1.JobStatus{State: cicdv1.CommitStatusStateSuccess, Message: JobMessageSkipped, CompletionTime: &now},
+		},
+		"notReported": {
+			coverage:       &cicdv1.JobCoverage{Path: "coverage.xml", Threshold: 80},
+			jobStatus:      &cicdv1.JobStatus{State: cicdv1.CommitStatusStateFailure, CompletionTime: &now},
+			expectedStatus: &git.CommitStatus{Context: "test/coverage", State: git.CommitStatusStateFailure, Description: "Coverage is not reported"},
+		},
+		"below": {
+			coverage:       &cicdv1.JobCoverage{Path: "coverage.xml", Threshold: 80},
+			jobStatus:      &cicdv1.JobStatus{State: cicdv1.CommitStatusStateSuccess, CompletionTime: &now, Coverage: &cicdv1.JobCoverageReport{Percentage: "79.99"}},
+			expectedStatus: &git.CommitStatus{Context: "test/coverage", State: git.CommitStatusStateFailure, Description: "Coverage 79.99% is lower than the threshold 80%"},
+		},
+		"meets": {
+			coverage:       &cicdv1.JobCoverage{Path: "coverage.xml", Threshold: 80},
+			jobStatus:      &cicdv1.JobStatus{State: cicdv1.CommitStatusStateSuccess, CompletionTime: &now, Coverage: &cicdv1.JobCoverageReport{Percentage: "80.00"}},
+			expectedStatus: &git.CommitStatus{Context: "test/coverage", State: git.CommitStatusStateSuccess, Description: "Coverage 80.00% meets the threshold 80%"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			j := &cicdv1.Job{Container: corev1.Container{Name: "test"}, Coverage: c.coverage}
+			require.Equal(t, c.expectedStatus, coverageCommitStatus(j, c.jobStatus))
+		})
+	}
+}
+
+func TestPipelineManager_reflectJobStatus_coverage(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	cfg := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+		},
+	}
+	baseIJ := func(name, branch string, completed time.Time, percentage string) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{cicdv1.JobLabelConfig: "test-ic"}},
+			Spec: cicdv1.IntegrationJobSpec{
+				ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+				Refs:      cicdv1.IntegrationJobRefs{Base: cicdv1.IntegrationJobRefsBase{Ref: cicdv1.GitRef("refs/heads/" + branch)}},
+			},
+			Status: cicdv1.IntegrationJobStatus{
+				CompletionTime: &metav1.Time{Time: completed},
+				Jobs:           []cicdv1.JobStatus{{Name: "test", Coverage: &cicdv1.JobCoverageReport{Percentage: percentage}}},
+			},
+		}
+	}
+	now := time.Now()
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+			Refs: cicdv1.IntegrationJobRefs{
+				Base:  cicdv1.IntegrationJobRefsBase{Ref: "master"},
+				Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Sha: "sha"}},
+			},
+		},
+	}
+	j := &cicdv1.Job{
+		Container: corev1.Container{Name: "test"},
+		Coverage:  &cicdv1.JobCoverage{Path: "coverage.xml", Format: cicdv1.CoverageFormatCobertura, Comment: true, Threshold: 90},
+	}
+	completion := metav1.Now()
+	pr := &tektonv1beta1.PipelineRun{
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					"test-run": {
+						PipelineTaskName: "test",
+						Status: &tektonv1beta1.TaskRunStatus{
+							TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+								PodName:        "test-run-pod",
+								StartTime:      &completion,
+								CompletionTime: &completion,
+								TaskRunResults: []tektonv1beta1.TaskRunResult{
+									{Name: CoverageResultName, Value: "covered 333\ntotal 400\n"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo": {Comments: map[int][]git.IssueComment{}},
+	}
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		cfg,
+		baseIJ("base-old", "master", now.Add(-2*time.Hour), "70.00"),
+		baseIJ("base-latest", "master", now.Add(-time.Hour), "81.75"),
+		baseIJ("base-other-branch", "release", now, "90.00"),
+	).Build(), Scheme: s}
+
+	jStatus := &cicdv1.JobStatus{Name: "test", State: cicdv1.CommitStatusStatePending}
+	p.reflectJobStatus(pr, j, jStatus, ij, cfg)
+	require.Equal(t, &cicdv1.JobCoverageReport{Covered: 333, Total: 400, Percentage: "83.25", BasePercentage: "81.75"}, jStatus.Coverage)
+	require.Len(t, gitfake.Repos["test/repo"].Comments[1], 1)
+	require.Equal(t, "[COVERAGE]\n\nCoverage of job `test` (IntegrationJob `test-ij`, commit sha)\n\n"+
+		"| Coverage | Base (master) | Delta |\n|---|---|---|\n| 83.25% (333/400 lines) | 81.75% | +1.50% |\n"+
+		"\nCoverage is lower than the threshold 90%\n", gitfake.Repos["test/repo"].Comments[1][0].Comment.Body)
+
+	// Base coverage is kept, and comment is posted only once
+	p.reflectJobStatus(pr, j, jStatus, ij, cfg)
+	require.Equal(t, "81.75", jStatus.Coverage.BasePercentage)
+	require.Len(t, gitfake.Repos["test/repo"].Comments[1], 1)
+}
+
+func TestGenerateCoverageComment_noBase(t *testing.T) {
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij"},
+		Spec: cicdv1.IntegrationJobSpec{Refs: cicdv1.IntegrationJobRefs{
+			Base:  cicdv1.IntegrationJobRefsBase{Ref: "master"},
+			Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Sha: "sha"}},
+		}},
+	}
+	j := &cicdv1.Job{Container: corev1.Container{Name: "test"}, Coverage: &cicdv1.JobCoverage{Path: "lcov.info", Threshold: 50}}
+	jobStatus := &cicdv1.JobStatus{Name: "test", Coverage: &cicdv1.JobCoverageReport{Covered: 12, Total: 15, Percentage: "80.00"}}
+	require.Equal(t, "[COVERAGE]\n\nCoverage of job `test` (IntegrationJob `test-ij`, commit sha)\n\n"+
+		"| Coverage | Base (master) | Delta |\n|---|---|---|\n| 80.00% (12/15 lines) | - | - |\n", generateCoverageComment(j, jobStatus, ij))
+}
@@ -27,9 +27,15 @@ import (
 func generateApprovalRunTask(job *cicdv1.IntegrationJob, j *cicdv1.Job, task *tektonv1beta1.PipelineTask) {
 	task.TaskRef = generateCustomTaskRef(cicdv1.CustomTaskKindApproval)
 
+	// Approval gates may not specify the approvers
+	approval := j.Approval
+	if approval == nil {
+		approval = &cicdv1.JobApproval{}
+	}
+
 	// Get approvers
 	var approvers []string
-	for _, approver := range j.Approval.Approvers {
+	for _, approver := range approval.Approvers {
 		param := approver.Name
 		if approver.Email != "" {
 			param += "=" + approver.Email
@@ -38,7 +44,7 @@ func generateApprovalRunTask(job *cicdv1.IntegrationJob, j *cicdv1.Job, task *te
 	}
 
 	// Get message
-	msg := j.Approval.RequestMessage
+	msg := approval.RequestMessage
 
 	// Get sender
 	sender := job.Spec.Refs.Sender
@@ -61,12 +67,26 @@ func generateApprovalRunTask(job *cicdv1.IntegrationJob, j *cicdv1.Job, task *te
 	}...)
 
 	approverCm := ""
-	if j.Approval.ApproversConfigMap != nil && j.Approval.ApproversConfigMap.Name != "" {
-		approverCm = j.Approval.ApproversConfigMap.Name
+	if approval.ApproversConfigMap != nil && approval.ApproversConfigMap.Name != "" {
+		approverCm = approval.ApproversConfigMap.Name
 	}
 	task.Params = append(task.Params, tektonv1beta1.Param{Name: cicdv1.CustomTaskApprovalParamKeyApproversCM, Value: tektonv1beta1.ArrayOrString{Type: tektonv1beta1.ParamTypeString, StringVal: approverCm}})
 }
 
+// generateApprovalGateTask generates an approval task, which the job requiring an approval runs after
+// The approval gate takes over the job's dependencies and when expressions, so that it's skipped with the job
+func generateApprovalGateTask(job *cicdv1.IntegrationJob, j *cicdv1.Job, task *tektonv1beta1.PipelineTask) *tektonv1beta1.PipelineTask {
+	gate := &tektonv1beta1.PipelineTask{
+		Name:            j.GetApprovalGateName(),
+		RunAfter:        task.RunAfter,
+		WhenExpressions: task.WhenExpressions.DeepCopy(),
+	}
+	generateApprovalRunTask(job, j, gate)
+
+	task.RunAfter = []string{gate.Name}
+	return gate
+}
+
 // Email custom tasks
 func generateEmailRunTask(job *cicdv1.IntegrationJob, j *cicdv1.Job, task *tektonv1beta1.PipelineTask) {
 	task.TaskRef = generateCustomTaskRef(cicdv1.CustomTaskKindEmail)
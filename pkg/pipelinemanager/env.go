@@ -37,6 +37,8 @@ func fillDefaultEnvs(tasks []tektonv1beta1.PipelineTask, job *cicdv1.Integration
 			if err != nil {
 				return err
 			}
+			// IntegrationConfig-level env.s come next, so that they can be overridden by the job's env.s
+			defaultEnvs = append(defaultEnvs, job.Spec.Env...)
 			// Default values are prepended, not appended - they might be used from the other env.s
 			steps[j].Env = append(defaultEnvs, steps[j].Env...)
 		}
@@ -45,6 +47,50 @@ func fillDefaultEnvs(tasks []tektonv1beta1.PipelineTask, job *cicdv1.Integration
 	return nil
 }
 
+// fillVolumeMounts mounts the IntegrationConfig-level volumeMounts to every step
+// Volume mounts whose mount path is already used by the step are skipped
+func fillVolumeMounts(tasks []tektonv1beta1.PipelineTask, job *cicdv1.IntegrationJob) {
+	if len(job.Spec.VolumeMounts) == 0 {
+		return
+	}
+	for i := range tasks {
+		if tasks[i].TaskSpec == nil {
+			continue
+		}
+		steps := tasks[i].TaskSpec.Steps
+		for j := range steps {
+			mountPaths := map[string]struct{}{}
+			for _, m := range steps[j].VolumeMounts {
+				mountPaths[m.MountPath] = struct{}{}
+			}
+			for _, m := range job.Spec.VolumeMounts {
+				if _, exist := mountPaths[m.MountPath]; exist {
+					continue
+				}
+				steps[j].VolumeMounts = append(steps[j].VolumeMounts, m)
+			}
+		}
+	}
+}
+
+// fillSecurityContext sets the IntegrationConfig-level securityContext to every step not having its own one
+func fillSecurityContext(tasks []tektonv1beta1.PipelineTask, job *cicdv1.IntegrationJob) {
+	if job.Spec.SecurityContext == nil {
+		return
+	}
+	for i := range tasks {
+		if tasks[i].TaskSpec == nil {
+			continue
+		}
+		steps := tasks[i].TaskSpec.Steps
+		for j := range steps {
+			if steps[j].SecurityContext == nil {
+				steps[j].SecurityContext = job.Spec.SecurityContext.DeepCopy()
+			}
+		}
+	}
+}
+
 func generateDefaultEnvs(job *cicdv1.IntegrationJob) ([]corev1.EnvVar, error) {
 	jobSpec := job.Spec
 	u, err := url.Parse(jobSpec.Refs.Link)
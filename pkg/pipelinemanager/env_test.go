@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -182,3 +183,82 @@ func TestGenerateDefaultEnvs(t *testing.T) {
 		})
 	}
 }
+
+func TestFillDefaultEnvs(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Refs: cicdv1.IntegrationJobRefs{Link: "https://github.com/tmax-cloud/cicd-operator"},
+			Env:  []corev1.EnvVar{{Name: "GOPROXY", Value: "https://proxy.golang.org"}, {Name: "LOG_LEVEL", Value: "info"}},
+		},
+	}
+	tasks := []tektonv1beta1.PipelineTask{
+		{TaskSpec: &tektonv1beta1.EmbeddedTask{TaskSpec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{{Container: corev1.Container{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}}}},
+		}}},
+		{TaskRef: &tektonv1beta1.TaskRef{Name: "catalog-task"}},
+	}
+
+	require.NoError(t, fillDefaultEnvs(tasks, job))
+
+	env := tasks[0].TaskSpec.Steps[0].Env
+	// Job's env.s should come after the IntegrationConfig's env.s, to take precedence
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "GOPROXY", Value: "https://proxy.golang.org"},
+		{Name: "LOG_LEVEL", Value: "info"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}, env[len(env)-3:])
+}
+
+func TestFillVolumeMounts(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}, {Name: "certs", MountPath: "/certs"}},
+		},
+	}
+	tasks := []tektonv1beta1.PipelineTask{
+		{TaskSpec: &tektonv1beta1.EmbeddedTask{TaskSpec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "checkout"}},
+				{Container: corev1.Container{Name: "test", VolumeMounts: []corev1.VolumeMount{{Name: "my-cache", MountPath: "/cache"}}}},
+			},
+		}}},
+		{TaskRef: &tektonv1beta1.TaskRef{Name: "catalog-task"}},
+	}
+
+	fillVolumeMounts(tasks, job)
+
+	require.Equal(t, []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}, {Name: "certs", MountPath: "/certs"}}, tasks[0].TaskSpec.Steps[0].VolumeMounts)
+	require.Equal(t, []corev1.VolumeMount{{Name: "my-cache", MountPath: "/cache"}, {Name: "certs", MountPath: "/certs"}}, tasks[0].TaskSpec.Steps[1].VolumeMounts)
+}
+
+func TestFillSecurityContext(t *testing.T) {
+	nonRoot := true
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			SecurityContext: &corev1.SecurityContext{
+				RunAsNonRoot:   &nonRoot,
+				Capabilities:   &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+		},
+	}
+	user := int64(1000)
+	tasks := []tektonv1beta1.PipelineTask{
+		{TaskSpec: &tektonv1beta1.EmbeddedTask{TaskSpec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "checkout"}},
+				{Container: corev1.Container{Name: "test", SecurityContext: &corev1.SecurityContext{RunAsUser: &user}}},
+			},
+		}}},
+		{TaskRef: &tektonv1beta1.TaskRef{Name: "catalog-task"}},
+	}
+
+	fillSecurityContext(tasks, job)
+
+	require.Equal(t, job.Spec.SecurityContext, tasks[0].TaskSpec.Steps[0].SecurityContext)
+	require.Equal(t, &corev1.SecurityContext{RunAsUser: &user}, tasks[0].TaskSpec.Steps[1].SecurityContext)
+
+	// Steps should not share the IntegrationJob's security context
+	tasks[0].TaskSpec.Steps[0].SecurityContext.Capabilities.Drop = nil
+	require.Equal(t, []corev1.Capability{"ALL"}, job.Spec.SecurityContext.Capabilities.Drop)
+}
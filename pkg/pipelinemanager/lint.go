@@ -0,0 +1,120 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LintResultName is a name of the task result, where the lint step writes the summary of the lint issues
+const LintResultName = "cicd-lint"
+
+// lintScript summarizes the lint output into the task result, as lines of 'total <n>' and 'issue <issue>' (for at most
+// 10 issues, each cut to 200 characters), where an issue is a line in a form of '<file>:<line>[:<column>]: <message>'
+const lintScript = `#!/bin/sh
+touch "$(results.` + LintResultName + `.path)"
+
+if [ ! -f "$LINT_PATH" ]; then
+  echo "lint output $LINT_PATH does not exist"
+else
+  awk '
+/^[^: \t][^:]*:[0-9]+(:[0-9]+)?:/ {
+  total++
+  if (total <= 10) {
+    print "issue " substr($0, 1, 200)
+  }
+}
+END {
+  print "total " total + 0
+}
+' "$LINT_PATH" > "$(results.` + LintResultName + `.path)" || echo "cannot summarize the lint output"
+  cat "$(results.` + LintResultName + `.path)"
+fi
+`
+
+var lintIssueRe = regexp.MustCompile(`^([^:]+):([0-9]+)(?::([0-9]+))?:\s*(.*)$`)
+
+// summarizeLint generates a step summarizing the job's lint output
+func summarizeLint(j *cicdv1.Job) tektonv1beta1.Step {
+	step := tektonv1beta1.Step{}
+	step.Name = "lint"
+	step.Image = configs.TestReportImage
+	step.WorkingDir = DefaultWorkingDir
+	if j.WorkingDir != "" {
+		step.WorkingDir = j.WorkingDir
+	}
+	step.Script = lintScript
+	step.Env = []corev1.EnvVar{
+		{Name: "LINT_PATH", Value: j.Lint.Path},
+	}
+	return step
+}
+
+// getLintReport parses the summary of the lint issues from the task results
+func getLintReport(results []tektonv1beta1.TaskRunResult) *cicdv1.JobLintReport {
+	for _, r := range results {
+		if r.Name != LintResultName {
+			continue
+		}
+		report := &cicdv1.JobLintReport{}
+		found := false
+		for _, line := range strings.Split(r.Value, "\n") {
+			tokens := strings.SplitN(strings.TrimSpace(line), " ", 2)
+			if len(tokens) != 2 {
+				continue
+			}
+			switch tokens[0] {
+			case "total":
+				n, err := strconv.Atoi(tokens[1])
+				if err != nil {
+					continue
+				}
+				report.Total = n
+				found = true
+			case "issue":
+				if issue := parseLintIssue(tokens[1]); issue != nil {
+					report.Issues = append(report.Issues, *issue)
+				}
+			}
+		}
+		if found {
+			return report
+		}
+	}
+	return nil
+}
+
+// parseLintIssue parses a line in a form of '<file>:<line>[:<column>]: <message>'
+func parseLintIssue(line string) *cicdv1.JobLintIssue {
+	matches := lintIssueRe.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	issue := &cicdv1.JobLintIssue{Path: strings.TrimPrefix(matches[1], "./"), Message: matches[4]}
+	issue.Line, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		issue.Column, _ = strconv.Atoi(matches[3])
+	}
+	return issue
+}
@@ -0,0 +1,81 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateSteps_lint(t *testing.T) {
+	job := &cicdv1.Job{
+		Container: corev1.Container{Name: "lint", Image: "golangci/golangci-lint"},
+		Script:    "golangci-lint run --out-format line-number > lint.txt",
+		Lint:      &cicdv1.JobLint{Path: "lint.txt"},
+	}
+	steps, err := generateSteps(&cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}, job)
+	require.NoError(t, err)
+
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.Name)
+	}
+	require.Equal(t, []string{"git-clone", "step-0", "lint"}, names)
+	require.Contains(t, steps[1].Script, deferredExitCodePath)
+	require.Contains(t, steps[2].Script, deferredExitScript)
+	require.Equal(t, []corev1.EnvVar{{Name: "LINT_PATH", Value: "lint.txt"}}, steps[2].Env)
+}
+
+func TestGetLintReport(t *testing.T) {
+	tc := map[string]struct {
+		results []tektonv1beta1.TaskRunResult
+
+		expected *cicdv1.JobLintReport
+	}{
+		"issues": {
+			results: []tektonv1beta1.TaskRunResult{{
+				Name:  LintResultName,
+				Value: "issue ./pkg/a.go:12:5: ineffectual assignment to err\nissue pkg/b.go:3: missing doc comment\nissue invalid line\ntotal 12\n",
+			}},
+			expected: &cicdv1.JobLintReport{Total: 12, Issues: []cicdv1.JobLintIssue{
+				{Path: "pkg/a.go", Line: 12, Column: 5, Message: "ineffectual assignment to err"},
+				{Path: "pkg/b.go", Line: 3, Message: "missing doc comment"},
+			}},
+		},
+		"noIssue": {
+			results:  []tektonv1beta1.TaskRunResult{{Name: LintResultName, Value: "total 0\n"}},
+			expected: &cicdv1.JobLintReport{},
+		},
+		"emptyResult": {
+			results: []tektonv1beta1.TaskRunResult{{Name: LintResultName, Value: ""}},
+		},
+		"noResult": {
+			results: []tektonv1beta1.TaskRunResult{{Name: TestReportResultName, Value: "total 3\n"}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, getLintReport(c.results))
+		})
+	}
+}
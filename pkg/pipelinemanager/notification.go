@@ -30,7 +30,7 @@ import (
 
 func (p *pipelineManager) handleNotification(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error {
 	// Get jobSpec spec
-	jobSpec := getSpecFromStatus(jobStatus, ij.Spec.ConfigRef.Type, cfg)
+	jobSpec := getSpecFromStatus(jobStatus, ij, cfg)
 	if jobSpec == nil {
 		return fmt.Errorf("no jobSpec %s exists in the config", jobStatus.Name)
 	}
@@ -129,10 +129,20 @@ func commonNotiRun(kind, ijName, jobName, ns string) *tektonv1alpha1.Run {
 	}
 }
 
-func getSpecFromStatus(jobStatus *cicdv1.JobStatus, t cicdv1.JobType, cfg *cicdv1.IntegrationConfig) *cicdv1.Job {
-	var jobs []cicdv1.Job
+func getSpecFromStatus(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) *cicdv1.Job {
+	var jobs cicdv1.Jobs
 
-	switch t {
+	// Jobs loaded from the config file are only in the IntegrationJob
+	if cfg.Spec.Jobs.ConfigFile != nil {
+		for _, j := range ij.Spec.Jobs {
+			if j.Name == jobStatus.Name {
+				return &j
+			}
+		}
+		return nil
+	}
+
+	switch ij.Spec.ConfigRef.Type {
 	case cicdv1.JobTypePreSubmit:
 		jobs = cfg.Spec.Jobs.PreSubmit
 	case cicdv1.JobTypePostSubmit:
@@ -141,7 +151,8 @@ func getSpecFromStatus(jobStatus *cicdv1.JobStatus, t cicdv1.JobType, cfg *cicdv
 		return nil
 	}
 
-	for _, j := range jobs {
+	// Jobs in the IntegrationJob are the ones with their matrix expanded
+	for _, j := range jobs.ExpandMatrix() {
 		if j.Name == jobStatus.Name {
 			return &j
 		}
@@ -0,0 +1,115 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+// limitParallelism orders the jobs' tasks into at most parallelism lanes, each of which runs its tasks one by one, so
+// that no more than parallelism jobs run at the same time. Custom tasks (approvals, approval gates, emails and slack
+// messages) are not limited, as they do not run any pod. Tasks with when expressions are not limited either, as a
+// skipped task would skip all the tasks ordered after it.
+// Note that the lanes are dependencies of the tasks, so once a task fails, the tasks ordered after it in its lane are
+// not run, in addition to the tasks not started yet, as Tekton does not start any task once a task fails
+func limitParallelism(tasks []tektonv1beta1.PipelineTask, parallelism int32) {
+	if parallelism <= 0 {
+		return
+	}
+
+	var limited []*tektonv1beta1.PipelineTask
+	for _, i := range sortTasks(tasks) {
+		if isCustomTask(&tasks[i]) || len(tasks[i].WhenExpressions) > 0 {
+			continue
+		}
+		limited = append(limited, &tasks[i])
+	}
+	if len(limited) <= int(parallelism) {
+		return
+	}
+
+	lanes := make([][]*tektonv1beta1.PipelineTask, parallelism)
+	for _, task := range limited {
+		lane := selectLane(lanes, task)
+		if len(lanes[lane]) > 0 {
+			tail := lanes[lane][len(lanes[lane])-1].Name
+			if !containsString(task.RunAfter, tail) {
+				task.RunAfter = append(task.RunAfter, tail)
+			}
+		}
+		lanes[lane] = append(lanes[lane], task)
+	}
+}
+
+// isCustomTask checks if the task is run by the custom task controller of the operator
+func isCustomTask(task *tektonv1beta1.PipelineTask) bool {
+	return task.TaskRef != nil && task.TaskRef.APIVersion == cicdv1.CustomTaskAPIVersion
+}
+
+// selectLane selects the lane of the task. The lane whose last task the task already runs after is preferred, not to
+// add a needless dependency. Otherwise, the shortest lane is selected
+func selectLane(lanes [][]*tektonv1beta1.PipelineTask, task *tektonv1beta1.PipelineTask) int {
+	shortest := 0
+	for i, lane := range lanes {
+		if len(lane) > 0 && containsString(task.RunAfter, lane[len(lane)-1].Name) {
+			return i
+		}
+		if len(lane) < len(lanes[shortest]) {
+			shortest = i
+		}
+	}
+	return shortest
+}
+
+// sortTasks returns the indices of the tasks in a topological order of their dependencies, keeping the original order
+// of the independent ones
+func sortTasks(tasks []tektonv1beta1.PipelineTask) []int {
+	indices := map[string]int{}
+	for i, task := range tasks {
+		indices[task.Name] = i
+	}
+
+	var sorted []int
+	visited := make([]bool, len(tasks))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, after := range tasks[i].RunAfter {
+			if pre, exist := indices[after]; exist {
+				visit(pre)
+			}
+		}
+		sorted = append(sorted, i)
+	}
+	for i := range tasks {
+		visit(i)
+	}
+	return sorted
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
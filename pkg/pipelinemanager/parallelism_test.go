@@ -0,0 +1,128 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+func TestLimitParallelism(t *testing.T) {
+	when := []tektonv1beta1.WhenExpression{{Input: "a", Operator: selection.In, Values: []string{"a"}}}
+
+	tc := map[string]struct {
+		tasks       []tektonv1beta1.PipelineTask
+		parallelism int32
+
+		expectedRunAfter map[string][]string
+	}{
+		"unlimited": {
+			tasks: []tektonv1beta1.PipelineTask{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			expectedRunAfter: map[string][]string{
+				"a": nil, "b": nil, "c": nil,
+			},
+		},
+		"underLimit": {
+			tasks:       []tektonv1beta1.PipelineTask{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			parallelism: 3,
+			expectedRunAfter: map[string][]string{
+				"a": nil, "b": nil, "c": nil,
+			},
+		},
+		"independent": {
+			tasks:       []tektonv1beta1.PipelineTask{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+			parallelism: 2,
+			expectedRunAfter: map[string][]string{
+				"a": nil, "b": nil, "c": {"a"}, "d": {"b"},
+			},
+		},
+		"dependencies": {
+			tasks: []tektonv1beta1.PipelineTask{
+				{Name: "test", RunAfter: []string{"build"}},
+				{Name: "build"},
+				{Name: "lint"},
+			},
+			parallelism: 1,
+			expectedRunAfter: map[string][]string{
+				"build": nil, "test": {"build"}, "lint": {"test"},
+			},
+		},
+		"preferDependency": {
+			tasks: []tektonv1beta1.PipelineTask{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c", RunAfter: []string{"b"}},
+			},
+			parallelism: 2,
+			expectedRunAfter: map[string][]string{
+				"a": nil, "b": nil, "c": {"b"},
+			},
+		},
+		"notLimited": {
+			tasks: []tektonv1beta1.PipelineTask{
+				{Name: "a"},
+				{Name: "b" + cicdv1.ApprovalGateSuffix, TaskRef: generateCustomTaskRef(cicdv1.CustomTaskKindApproval)},
+				{Name: "b", RunAfter: []string{"b" + cicdv1.ApprovalGateSuffix}},
+				{Name: "c", WhenExpressions: when},
+				{Name: "approval", TaskRef: generateCustomTaskRef(cicdv1.CustomTaskKindApproval)},
+				{Name: "email", TaskRef: generateCustomTaskRef(cicdv1.CustomTaskKindEmail)},
+				{Name: "slack", TaskRef: generateCustomTaskRef(cicdv1.CustomTaskKindSlack)},
+				{Name: "d"},
+			},
+			parallelism: 1,
+			expectedRunAfter: map[string][]string{
+				"a":                             nil,
+				"b" + cicdv1.ApprovalGateSuffix: nil,
+				"b":                             {"b" + cicdv1.ApprovalGateSuffix, "a"},
+				"c":                             nil,
+				"approval":                      nil,
+				"email":                         nil,
+				"slack":                         nil,
+				"d":                             {"b"},
+			},
+		},
+		// A failed task skips the tasks ordered after it in its lane, even if they do not depend on it
+		"failureSkipsLane": {
+			tasks: []tektonv1beta1.PipelineTask{
+				{Name: "build"},
+				{Name: "lint"},
+				{Name: "test", RunAfter: []string{"build"}},
+				{Name: "docs"},
+			},
+			parallelism: 2,
+			expectedRunAfter: map[string][]string{
+				"build": nil, "lint": nil, "test": {"build"}, "docs": {"lint"},
+			},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			limitParallelism(c.tasks, c.parallelism)
+
+			runAfter := map[string][]string{}
+			for _, task := range c.tasks {
+				runAfter[task.Name] = task.RunAfter
+			}
+			require.Equal(t, c.expectedRunAfter, runAfter)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"fmt"
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// getPipelineRefJob returns the job referring to an existing pipeline, if the IntegrationJob consists of it
+func getPipelineRefJob(jobs cicdv1.Jobs) *cicdv1.Job {
+	if len(jobs) != 1 || jobs[0].PipelineRef == nil {
+		return nil
+	}
+	return &jobs[0]
+}
+
+// generatePipelineRefRun generates a PipelineRun running the existing pipeline, instead of the generated one
+func generatePipelineRefRun(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.PipelineRun, error) {
+	params, err := generatePipelineRefParams(job, j)
+	if err != nil {
+		return nil, err
+	}
+
+	// Job's timeout is bounded by the IntegrationJob's timeout
+	timeout := job.Spec.Timeout.Duration
+	if j.Timeout != nil && j.Timeout.Duration < timeout {
+		timeout = j.Timeout.Duration
+	}
+
+	serviceAccount := cicdv1.GetServiceAccountName(job.Spec.ConfigRef.Name)
+	if j.ServiceAccountName != "" {
+		serviceAccount = j.ServiceAccountName
+	}
+
+	podTemplate := job.Spec.PodTemplate
+	if t := generateJobPodTemplate(job, j); t != nil {
+		podTemplate = t
+	}
+
+	return &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(job),
+			Namespace: job.Namespace,
+			Labels:    generateLabel(job),
+		},
+		Spec: tektonv1beta1.PipelineRunSpec{
+			ServiceAccountName: serviceAccount,
+			PipelineRef:        &tektonv1beta1.PipelineRef{Name: j.PipelineRef.Name},
+			PodTemplate:        podTemplate,
+			Workspaces:         job.Spec.Workspaces,
+			Timeout:            &metav1.Duration{Duration: timeout},
+			Params:             params,
+		},
+	}, nil
+}
+
+// generatePipelineRefParams generates the pipeline's params, replacing the default environment variables
+// (e.g., $(CI_HEAD_SHA)) with the IntegrationJob's values
+func generatePipelineRefParams(job *cicdv1.IntegrationJob, j *cicdv1.Job) ([]tektonv1beta1.Param, error) {
+	envs, err := generateDefaultEnvs(job)
+	if err != nil {
+		return nil, err
+	}
+	var replacements []string
+	for _, e := range envs {
+		replacements = append(replacements, fmt.Sprintf("$(%s)", e.Name), strings.TrimSpace(e.Value))
+	}
+	r := strings.NewReplacer(replacements...)
+
+	var params []cicdv1.ParameterValue
+	for _, p := range j.PipelineRef.Params {
+		param := *p.DeepCopy()
+		param.StringVal = r.Replace(param.StringVal)
+		for i := range param.ArrayVal {
+			param.ArrayVal[i] = r.Replace(param.ArrayVal[i])
+		}
+		params = append(params, param)
+	}
+	return cicdv1.ConvertToTektonParams(params), nil
+}
+
+// getPipelineRefRunStatus returns the status of the job referring to a pipeline, which is the PipelineRun's status
+func getPipelineRefRunStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job) *cicdv1.JobStatus {
+	jobStatus := &cicdv1.JobStatus{Name: j.Name, State: cicdv1.CommitStatusStatePending}
+	jobStatus.StartTime = pr.Status.StartTime.DeepCopy()
+	jobStatus.CompletionTime = pr.Status.CompletionTime.DeepCopy()
+
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil {
+		return jobStatus
+	}
+	jobStatus.Message = cond.Message
+	switch tektonv1beta1.PipelineRunReason(cond.Reason) {
+	case tektonv1beta1.PipelineRunReasonSuccessful, tektonv1beta1.PipelineRunReasonCompleted:
+		jobStatus.State = cicdv1.CommitStatusStateSuccess
+	case tektonv1beta1.PipelineRunReasonFailed, tektonv1beta1.PipelineRunReasonCancelled:
+		jobStatus.State = cicdv1.CommitStatusStateFailure
+	case tektonv1beta1.PipelineRunReasonTimedOut:
+		jobStatus.State = cicdv1.CommitStatusStateFailure
+		jobStatus.Message = JobMessageTimedOut
+	}
+	return jobStatus
+}
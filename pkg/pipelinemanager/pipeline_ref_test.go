@@ -0,0 +1,135 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPipelineManager_Generate_pipelineRef(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	job := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef:  cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+			ID:         "1234",
+			Workspaces: []tektonv1beta1.WorkspaceBinding{{Name: "source"}},
+			Timeout:    &metav1.Duration{Duration: time.Hour},
+			Refs: cicdv1.IntegrationJobRefs{
+				Repository: "tmax-cloud/cicd-operator",
+				Link:       "https://github.com/tmax-cloud/cicd-operator",
+				Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "1111111111"},
+				Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: 1, Ref: "feat", Sha: "2222222222"}},
+			},
+			Jobs: cicdv1.Jobs{
+				{
+					Container: corev1.Container{Name: "pipeline"},
+					Timeout:   &metav1.Duration{Duration: 30 * time.Minute},
+					PipelineRef: &cicdv1.JobPipelineRef{
+						Name: "build-and-test",
+						Params: []cicdv1.ParameterValue{
+							{Name: "url", StringVal: "$(CI_SERVER_URL)/$(CI_REPOSITORY)"},
+							{Name: "revision", StringVal: "$(CI_HEAD_SHA)"},
+							{Name: "args", ArrayVal: []string{"--base=$(CI_BASE_REF)"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).Build(), Scheme: s}
+	pr, err := p.Generate(job)
+	require.NoError(t, err)
+
+	require.Equal(t, Name(job), pr.Name)
+	require.Nil(t, pr.Spec.PipelineSpec)
+	require.Equal(t, &tektonv1beta1.PipelineRef{Name: "build-and-test"}, pr.Spec.PipelineRef)
+	require.Equal(t, cicdv1.GetServiceAccountName("test-ic"), pr.Spec.ServiceAccountName)
+	require.Equal(t, job.Spec.Workspaces, pr.Spec.Workspaces)
+	require.Equal(t, &metav1.Duration{Duration: 30 * time.Minute}, pr.Spec.Timeout)
+	require.Equal(t, []tektonv1beta1.Param{
+		{Name: "url", Value: tektonv1beta1.ArrayOrString{Type: tektonv1beta1.ParamTypeString, StringVal: "https://github.com/tmax-cloud/cicd-operator"}},
+		{Name: "revision", Value: tektonv1beta1.ArrayOrString{Type: tektonv1beta1.ParamTypeString, StringVal: "2222222222"}},
+		{Name: "args", Value: tektonv1beta1.ArrayOrString{Type: tektonv1beta1.ParamTypeArray, ArrayVal: []string{"--base=master"}}},
+	}, pr.Spec.Params)
+
+	// IntegrationJob should not be modified
+	require.Equal(t, "$(CI_HEAD_SHA)", job.Spec.Jobs[0].PipelineRef.Params[1].StringVal)
+}
+
+func TestGetJobRunStatus_pipelineRef(t *testing.T) {
+	j := &cicdv1.Job{Container: corev1.Container{Name: "pipeline"}, PipelineRef: &cicdv1.JobPipelineRef{Name: "build-and-test"}}
+
+	tc := map[string]struct {
+		reason string
+
+		expectedState   cicdv1.CommitStatusState
+		expectedMessage string
+	}{
+		"running": {
+			reason:          string(tektonv1beta1.PipelineRunReasonRunning),
+			expectedState:   cicdv1.CommitStatusStatePending,
+			expectedMessage: "message",
+		},
+		"succeeded": {
+			reason:          string(tektonv1beta1.PipelineRunReasonSuccessful),
+			expectedState:   cicdv1.CommitStatusStateSuccess,
+			expectedMessage: "message",
+		},
+		"failed": {
+			reason:          string(tektonv1beta1.PipelineRunReasonFailed),
+			expectedState:   cicdv1.CommitStatusStateFailure,
+			expectedMessage: "message",
+		},
+		"timedOut": {
+			reason:          string(tektonv1beta1.PipelineRunReasonTimedOut),
+			expectedState:   cicdv1.CommitStatusStateFailure,
+			expectedMessage: JobMessageTimedOut,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			pr := &tektonv1beta1.PipelineRun{
+				Status: tektonv1beta1.PipelineRunStatus{
+					Status: duckv1beta1.Status{
+						Conditions: duckv1beta1.Conditions{{Type: apis.ConditionSucceeded, Reason: c.reason, Message: "message"}},
+					},
+				},
+			}
+			status := getJobRunStatus(pr, j)
+			require.Equal(t, "pipeline", status.Name)
+			require.Equal(t, c.expectedState, status.State)
+			require.Equal(t, c.expectedMessage, status.Message)
+		})
+	}
+}
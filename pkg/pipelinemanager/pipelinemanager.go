@@ -23,11 +23,12 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	"github.com/tmax-cloud/cicd-operator/internal/utils"
-	"github.com/tmax-cloud/cicd-operator/pkg/events"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +51,13 @@ const (
 	JobMessagePending    = "Job is running"
 	JobMessageSuccessful = "Job succeeded"
 	JobMessageFailure    = "Job failed"
+	JobMessageSkipped    = "Job is skipped"
+	JobMessageTimedOut   = "Job timed out"
+	JobMessageSuperseded = "Job is superseded by a newer commit"
+	JobMessageCancelled  = "Job is cancelled"
+	JobMessageStuck      = "Job is stuck"
+
+	JobMessageWaitingForApproval = "Job is waiting for approval"
 )
 
 const (
@@ -61,18 +69,21 @@ const (
 // PipelineManager manages pipelines
 type PipelineManager interface {
 	Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.PipelineRun, error)
+	ResolveJobs(job *cicdv1.IntegrationJob) (cicdv1.Jobs, error)
 	ReflectStatus(pr *tektonv1beta1.PipelineRun, job *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error
 }
 
 // pipelineManager is an actual implementation
 type pipelineManager struct {
 	Client client.Client
-	Scheme *runtime.Scheme
+	// APIReader reads the objects directly from the API server, for the objects which should not be cached, e.g., Pods
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
 }
 
 // NewPipelineManager initiates a new PipelineManager
-func NewPipelineManager(c client.Client, s *runtime.Scheme) PipelineManager {
-	return &pipelineManager{Client: c, Scheme: s}
+func NewPipelineManager(c client.Client, apiReader client.Reader, s *runtime.Scheme) PipelineManager {
+	return &pipelineManager{Client: c, APIReader: apiReader, Scheme: s}
 }
 
 // Generate generates (but not creates) a PipelineRun object
@@ -92,13 +103,39 @@ func (p *pipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.P
 	var specResources []tektonv1beta1.PipelineDeclaredResource
 	var runResources []tektonv1beta1.PipelineResourceBinding
 
+	// Check jobs' dependencies
+	if _, err := job.Spec.Jobs.GetGraph(); err != nil {
+		return nil, err
+	}
+
+	// Render job templates
+	jobs, err := p.renderTemplates(job)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run the existing pipeline, if the job refers to it
+	if j := getPipelineRefJob(jobs); j != nil {
+		return generatePipelineRefRun(job, j)
+	}
+
 	// Generate Tasks
 	var tasks []tektonv1beta1.PipelineTask
-	for _, j := range job.Spec.Jobs {
+	for _, j := range jobs {
 		taskSpec, resources, err := generateTask(job, &j)
 		if err != nil {
 			return nil, err
 		}
+		if j.TektonTask != nil && j.TektonTask.TaskRef.Resolver != nil {
+			spec, err := p.resolveTask(job, &j)
+			if err != nil {
+				return nil, err
+			}
+			taskSpec.TaskSpec = &tektonv1beta1.EmbeddedTask{TaskSpec: *spec}
+		}
+		if j.ApprovalRequired {
+			tasks = append(tasks, *generateApprovalGateTask(job, &j, taskSpec))
+		}
 		tasks = append(tasks, *taskSpec)
 
 		// Append resources
@@ -112,11 +149,18 @@ func (p *pipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.P
 		}
 	}
 
+	// Limit the number of the jobs running at the same time
+	limitParallelism(tasks, job.Spec.Parallelism)
+
 	// Fill default env.s
 	if err := fillDefaultEnvs(tasks, job); err != nil {
 		return nil, err
 	}
 
+	// Fill IntegrationConfig-level volume mounts and security context
+	fillVolumeMounts(tasks, job)
+	fillSecurityContext(tasks, job)
+
 	return &tektonv1beta1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      Name(job),
@@ -132,8 +176,9 @@ func (p *pipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.P
 				Workspaces: workspaceDefs,
 				Params:     paramDefine,
 			},
-			PodTemplate: job.Spec.PodTemplate,
-			Workspaces:  job.Spec.Workspaces,
+			TaskRunSpecs: generateTaskRunSpecs(job, jobs),
+			PodTemplate:  job.Spec.PodTemplate,
+			Workspaces:   job.Spec.Workspaces,
 			Timeout: &metav1.Duration{
 				Duration: job.Spec.Timeout.Duration,
 			},
@@ -187,7 +232,7 @@ func generateTask(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.Pip
 			return nil, nil, err
 		}
 		resources = append(resources, res...)
-	} else if j.Approval != nil {
+	} else if j.Approval != nil && !j.ApprovalRequired {
 		generateApprovalRunTask(job, j, task)
 	} else if j.Email != nil {
 		generateEmailRunTask(job, j, task)
@@ -195,31 +240,35 @@ func generateTask(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.Pip
 		generateSlackRunTask(job, j, task)
 	} else {
 		// Steps
-		steps, err := generateSteps(j)
+		steps, err := generateSteps(job, j)
 		if err != nil {
 			return nil, nil, err
 		}
+		if j.Retries > 0 && configs.JobRetryBackoff > 0 {
+			steps = append([]tektonv1beta1.Step{retryBackoff()}, steps...)
+		}
 
 		task.TaskSpec = &tektonv1beta1.EmbeddedTask{}
 		task.TaskSpec.Steps = steps
 
 		// Workspaces
-		var wsDefs []tektonv1beta1.WorkspaceDeclaration
-		for _, w := range job.Spec.Workspaces {
-			wsDefs = append(wsDefs, tektonv1beta1.WorkspaceDeclaration{Name: w.Name})
-		}
+		task.TaskSpec.Workspaces, task.Workspaces = generateWorkspaces(job, j)
+	}
 
-		var wsBindings []tektonv1beta1.WorkspacePipelineTaskBinding
-		for _, w := range job.Spec.Workspaces {
-			wsBindings = append(wsBindings, tektonv1beta1.WorkspacePipelineTaskBinding{Name: w.Name, Workspace: w.Name})
+	// After - jobs filtered out from the IntegrationJob (e.g., by when.branch) are not waited for
+	for _, after := range j.After {
+		if hasJob(job, after) {
+			task.RunAfter = append(task.RunAfter, after)
 		}
+	}
 
-		task.TaskSpec.Workspaces = wsDefs
-		task.Workspaces = wsBindings
+	// Timeout
+	if j.Timeout != nil {
+		task.Timeout = j.Timeout.DeepCopy()
 	}
 
-	// After
-	task.RunAfter = append(task.RunAfter, j.After...)
+	// Retries
+	task.Retries = j.Retries
 
 	// TektonWhen
 	task.WhenExpressions = append(task.WhenExpressions, j.TektonWhen...)
@@ -227,16 +276,63 @@ func generateTask(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.Pip
 	// Results
 	if task.TaskSpec != nil {
 		task.TaskSpec.Results = append(task.TaskSpec.Results, j.Results...)
+		if j.Artifacts != nil {
+			task.TaskSpec.Results = append(task.TaskSpec.Results, tektonv1beta1.TaskResult{Name: ArtifactsResultName, Description: "URLs of the uploaded artifacts"})
+		}
+		if j.TestReports != nil {
+			task.TaskSpec.Results = append(task.TaskSpec.Results, tektonv1beta1.TaskResult{Name: TestReportResultName, Description: "Summary of the test reports"})
+		}
+		if j.Coverage != nil {
+			task.TaskSpec.Results = append(task.TaskSpec.Results, tektonv1beta1.TaskResult{Name: CoverageResultName, Description: "Line coverage"})
+		}
+		if j.Lint != nil {
+			task.TaskSpec.Results = append(task.TaskSpec.Results, tektonv1beta1.TaskResult{Name: LintResultName, Description: "Summary of the lint issues"})
+		}
 	}
 
 	return task, resources, nil
 }
 
-func generateSteps(j *cicdv1.Job) ([]tektonv1beta1.Step, error) {
+// generateWorkspaces generates workspace declarations and bindings of the job's task
+// Every workspace of the IntegrationJob is used if the job does not specify its workspaces
+func generateWorkspaces(job *cicdv1.IntegrationJob, j *cicdv1.Job) ([]tektonv1beta1.WorkspaceDeclaration, []tektonv1beta1.WorkspacePipelineTaskBinding) {
+	var wsDefs []tektonv1beta1.WorkspaceDeclaration
+	var wsBindings []tektonv1beta1.WorkspacePipelineTaskBinding
+
+	if len(j.Workspaces) == 0 {
+		for _, w := range job.Spec.Workspaces {
+			wsDefs = append(wsDefs, tektonv1beta1.WorkspaceDeclaration{Name: w.Name})
+			wsBindings = append(wsBindings, tektonv1beta1.WorkspacePipelineTaskBinding{Name: w.Name, Workspace: w.Name})
+		}
+		return wsDefs, wsBindings
+	}
+
+	for _, w := range j.Workspaces {
+		wsDefs = append(wsDefs, tektonv1beta1.WorkspaceDeclaration{Name: w.Name, MountPath: w.MountPath, ReadOnly: w.ReadOnly})
+		wsBindings = append(wsBindings, tektonv1beta1.WorkspacePipelineTaskBinding{Name: w.Name, Workspace: w.Name})
+	}
+	return wsDefs, wsBindings
+}
+
+func hasJob(job *cicdv1.IntegrationJob, name string) bool {
+	for _, j := range job.Spec.Jobs {
+		if j.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSteps(job *cicdv1.IntegrationJob, j *cicdv1.Job) ([]tektonv1beta1.Step, error) {
 	var steps []tektonv1beta1.Step
 
 	if !j.SkipCheckout {
-		steps = append(steps, gitCheckout())
+		checkout := gitCheckout(cicdv1.MergeCheckout(job.Spec.Checkout, j.Checkout))
+		// Checkout step should also satisfy the pod security requirements of the job
+		if j.SecurityContext != nil {
+			checkout.SecurityContext = j.SecurityContext.DeepCopy()
+		}
+		steps = append(steps, checkout)
 	}
 
 	step := tektonv1beta1.Step{}
@@ -249,10 +345,77 @@ func generateSteps(j *cicdv1.Job) ([]tektonv1beta1.Step, error) {
 		step.WorkingDir = DefaultWorkingDir
 	}
 	step.Script = j.Script
+
+	// Test reports, coverage and lint output are parsed even if the script fails
+	var reportSteps []tektonv1beta1.Step
+	if j.TestReports != nil {
+		reportSteps = append(reportSteps, summarizeTestReports(j))
+	}
+	if j.Coverage != nil {
+		reportSteps = append(reportSteps, parseCoverage(j))
+	}
+	if j.Lint != nil {
+		reportSteps = append(reportSteps, summarizeLint(j))
+	}
+	if len(reportSteps) > 0 {
+		if step.Script != "" {
+			deferScriptFailure(&step)
+		}
+		reportSteps[len(reportSteps)-1].Script += deferredExitScript
+	}
 	steps = append(steps, step)
+	steps = append(steps, reportSteps...)
+
+	if j.Artifacts != nil {
+		steps = append(steps, uploadArtifacts(job, j))
+	}
 	return steps, nil
 }
 
+// generateTaskRunSpecs generates the jobs' run specs, for the jobs running with their own service accounts or pod
+// settings
+func generateTaskRunSpecs(job *cicdv1.IntegrationJob, jobs cicdv1.Jobs) []tektonv1beta1.PipelineTaskRunSpec {
+	var specs []tektonv1beta1.PipelineTaskRunSpec
+	for i := range jobs {
+		podTemplate := generateJobPodTemplate(job, &jobs[i])
+		if jobs[i].ServiceAccountName == "" && podTemplate == nil {
+			continue
+		}
+		specs = append(specs, tektonv1beta1.PipelineTaskRunSpec{
+			PipelineTaskName:       jobs[i].Name,
+			TaskServiceAccountName: jobs[i].ServiceAccountName,
+			TaskPodTemplate:        podTemplate,
+		})
+	}
+	return specs
+}
+
+// generateJobPodTemplate generates a pod template for the job, if the job overrides the IntegrationJob's pod template
+// Tekton replaces the PipelineRun's pod template with the task's, so the IntegrationJob's one is copied and modified
+func generateJobPodTemplate(job *cicdv1.IntegrationJob, j *cicdv1.Job) *pod.Template {
+	if j.PodSecurityContext == nil && len(j.NodeSelector) == 0 && len(j.Tolerations) == 0 && j.Affinity == nil {
+		return nil
+	}
+	podTemplate := &pod.Template{}
+	if job.Spec.PodTemplate != nil {
+		podTemplate = job.Spec.PodTemplate.DeepCopy()
+	}
+	if j.PodSecurityContext != nil {
+		podTemplate.SecurityContext = j.PodSecurityContext.DeepCopy()
+	}
+	if len(j.NodeSelector) > 0 && podTemplate.NodeSelector == nil {
+		podTemplate.NodeSelector = map[string]string{}
+	}
+	for k, v := range j.NodeSelector {
+		podTemplate.NodeSelector[k] = v
+	}
+	podTemplate.Tolerations = append(podTemplate.Tolerations, j.Tolerations...)
+	if j.Affinity != nil {
+		podTemplate.Affinity = j.Affinity.DeepCopy()
+	}
+	return podTemplate
+}
+
 func generateLabel(j *cicdv1.IntegrationJob) map[string]string {
 	label := map[string]string{
 		cicdv1.RunLabelJob:   j.Name,
@@ -272,9 +435,6 @@ func generateLabel(j *cicdv1.IntegrationJob) map[string]string {
 // ReflectStatus reflects PipelineRun's status into IntegrationJob's status
 // It also set commit status for remote git server
 func (p *pipelineManager) ReflectStatus(pr *tektonv1beta1.PipelineRun, job *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error {
-	oldState := job.Status.State
-	oldMessage := job.Status.Message
-
 	// If PR is nil but IntegrationJob's status is running, set as error
 	// Also, schedule next pipelineRun
 	if pr == nil && job.Status.State == cicdv1.IntegrationJobStateRunning {
@@ -287,6 +447,7 @@ func (p *pipelineManager) ReflectStatus(pr *tektonv1beta1.PipelineRun, job *cicd
 	// If PR exists, default state is running
 	if pr != nil {
 		job.Status.State = cicdv1.IntegrationJobStateRunning
+		job.Status.Reason = ""
 
 		job.Status.StartTime = pr.CreationTimestamp.DeepCopy()
 		job.Status.CompletionTime = pr.Status.CompletionTime.DeepCopy()
@@ -310,10 +471,39 @@ func (p *pipelineManager) ReflectStatus(pr *tektonv1beta1.PipelineRun, job *cicd
 		for i, j := range job.Spec.Jobs {
 			stateChanged[i] = p.reflectJobStatus(pr, &j, &job.Status.Jobs[i], job, cfg)
 		}
+
+		// Make it clear which job made the IntegrationJob fail, if it timed out
+		if job.Status.State == cicdv1.IntegrationJobStateFailed {
+			for _, j := range job.Status.Jobs {
+				if j.Message == JobMessageTimedOut {
+					job.Status.Message = fmt.Sprintf("Job %s timed out", j.Name)
+					break
+				}
+			}
+		}
+	}
+
+	// Mark the IntegrationJob stuck for too long as failed
+	if pr != nil && job.Status.State == cicdv1.IntegrationJobStateRunning {
+		reason, err := p.findStuckReason(pr, job)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			markStuck(job, reason, stateChanged)
+			p.notifyStuckJobs(job, cfg)
+		}
+	}
+
+	// Mark the superseded IntegrationJob as failed, and its jobs not completed yet as superseded
+	if supersededBy := job.Annotations[cicdv1.JobAnnotationSupersededBy]; supersededBy != "" {
+		markSuperseded(job, supersededBy, stateChanged)
+	} else if job.Spec.Cancelled {
+		markCancelled(job, stateChanged)
 	}
 
 	// If it's start/completed but completion time is not set, set it as now
-	if job.Status.State == cicdv1.IntegrationJobStateFailed || job.Status.State == cicdv1.IntegrationJobStateCompleted {
+	if job.Status.State == cicdv1.IntegrationJobStateFailed || job.Status.State == cicdv1.IntegrationJobStateCompleted || job.Status.State == cicdv1.IntegrationJobStateCancelled {
 		t := &metav1.Time{Time: time.Now()}
 		if job.Status.StartTime == nil {
 			job.Status.StartTime = t
@@ -330,14 +520,38 @@ func (p *pipelineManager) ReflectStatus(pr *tektonv1beta1.PipelineRun, job *cicd
 		}
 	}
 
-	// Emit events
-	if err := p.emitEvents(job, oldState, oldMessage); err != nil {
-		return err
-	}
-
 	return nil
 }
 
+func markSuperseded(job *cicdv1.IntegrationJob, supersededBy string, stateChanged []bool) {
+	job.Status.State = cicdv1.IntegrationJobStateFailed
+	job.Status.Reason = cicdv1.IntegrationJobReasonSuperseded
+	job.Status.Message = fmt.Sprintf("Superseded by IntegrationJob %s", supersededBy)
+	markJobsNotCompleted(job, JobMessageSuperseded, stateChanged)
+}
+
+func markCancelled(job *cicdv1.IntegrationJob, stateChanged []bool) {
+	job.Status.State = cicdv1.IntegrationJobStateCancelled
+	job.Status.Reason = ""
+	job.Status.Message = "IntegrationJob is cancelled"
+	markJobsNotCompleted(job, JobMessageCancelled, stateChanged)
+}
+
+// markJobsNotCompleted sets the jobs not completed yet as errors with the message
+func markJobsNotCompleted(job *cicdv1.IntegrationJob, message string, stateChanged []bool) {
+	now := &metav1.Time{Time: time.Now()}
+	for i := range job.Status.Jobs {
+		j := &job.Status.Jobs[i]
+		if j.CompletionTime != nil {
+			continue
+		}
+		j.State = cicdv1.CommitStatusStateError
+		j.Message = message
+		j.CompletionTime = now
+		stateChanged[i] = true
+	}
+}
+
 func initState(job *cicdv1.IntegrationJob) []bool {
 	stateChanged := make([]bool, len(job.Spec.Jobs))
 	reset := len(job.Status.Jobs) != len(job.Spec.Jobs)
@@ -364,9 +578,43 @@ func (p *pipelineManager) reflectJobStatus(pr *tektonv1beta1.PipelineRun, j *cic
 	// Only update if taskRun's status exists
 	if runStatus != nil {
 		// If something is changed, commit status should be posted (except for message - message is decided by the state)
-		changed = jStatus.State != runStatus.State || !jStatus.StartTime.Equal(runStatus.StartTime) || !jStatus.CompletionTime.Equal(runStatus.CompletionTime)
+		changed = jStatus.State != runStatus.State || !jStatus.StartTime.Equal(runStatus.StartTime) || !jStatus.CompletionTime.Equal(runStatus.CompletionTime) || jStatus.Attempts != runStatus.Attempts
+		// Let the users know the job is waiting for an approval
+		changed = changed || (runStatus.Message == JobMessageWaitingForApproval && jStatus.Message != runStatus.Message)
+		newlyCompleted := jStatus.CompletionTime == nil && runStatus.CompletionTime != nil
+		// The base branch's coverage is looked up only once, when the job is completed
+		if runStatus.Coverage != nil {
+			if newlyCompleted {
+				runStatus.Coverage.BasePercentage = p.getBaseCoverage(ij, j.Name)
+			} else if jStatus.Coverage != nil {
+				runStatus.Coverage.BasePercentage = jStatus.Coverage.BasePercentage
+			}
+		}
+		// A new attempt is started. Set the delay before the next one
+		if j.Retries > 0 && configs.JobRetryBackoff > 0 && runStatus.PodName != "" && runStatus.PodName != jStatus.PodName {
+			if err := p.setRetryBackoff(pr, j, ij); err != nil {
+				log.Error(err, "cannot set the retry backoff", "job", j.Name)
+			}
+		}
 		runStatus.DeepCopyInto(jStatus)
 
+		// Post the artifacts' URLs, the test report and the coverage to the pull request, only once
+		if newlyCompleted && j.Artifacts != nil && j.Artifacts.Comment {
+			if err := p.commentArtifacts(runStatus, ij, cfg); err != nil {
+				log.Error(err, "cannot comment the artifacts", "job", j.Name)
+			}
+		}
+		if newlyCompleted && j.TestReports != nil && j.TestReports.Comment {
+			if err := p.commentTestReport(runStatus, ij, cfg); err != nil {
+				log.Error(err, "cannot comment the test report", "job", j.Name)
+			}
+		}
+		if newlyCompleted && j.Coverage != nil && j.Coverage.Comment {
+			if err := p.commentCoverage(j, runStatus, ij, cfg); err != nil {
+				log.Error(err, "cannot comment the coverage", "job", j.Name)
+			}
+		}
+
 		// Handle post-run notifications for the completed jobs
 		if runStatus.CompletionTime != nil {
 			if err := p.handleNotification(runStatus, ij, cfg); err != nil {
@@ -379,12 +627,17 @@ func (p *pipelineManager) reflectJobStatus(pr *tektonv1beta1.PipelineRun, j *cic
 }
 
 func getJobRunStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job) *cicdv1.JobStatus {
+	if j.PipelineRef != nil {
+		return getPipelineRefRunStatus(pr, j)
+	}
+
 	jobStatus := &cicdv1.JobStatus{Name: j.Name, State: cicdv1.CommitStatusStatePending}
 	// Find in TaskRun first
 	for _, runStatus := range pr.Status.TaskRuns {
 		if runStatus.Status != nil && runStatus.PipelineTaskName == j.Name {
 			rStatus := runStatus.Status
 			jobStatus.PodName = rStatus.PodName
+			jobStatus.Attempts = len(rStatus.RetriesStatus) + 1
 			jobStatus.StartTime = rStatus.StartTime.DeepCopy()
 			jobStatus.CompletionTime = rStatus.CompletionTime.DeepCopy()
 			if len(rStatus.Conditions) > 0 {
@@ -392,10 +645,17 @@ func getJobRunStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job) *cicdv1.JobSt
 				switch tektonv1beta1.TaskRunReason(rStatus.Conditions[0].Reason) {
 				case tektonv1beta1.TaskRunReasonSuccessful:
 					jobStatus.State = cicdv1.CommitStatusStateSuccess
-				case tektonv1beta1.TaskRunReasonFailed, tektonv1beta1.TaskRunReasonCancelled, tektonv1beta1.TaskRunReasonTimedOut:
+				case tektonv1beta1.TaskRunReasonFailed, tektonv1beta1.TaskRunReasonCancelled:
 					jobStatus.State = cicdv1.CommitStatusStateFailure
+				case tektonv1beta1.TaskRunReasonTimedOut:
+					jobStatus.State = cicdv1.CommitStatusStateFailure
+					jobStatus.Message = JobMessageTimedOut
 				}
 			}
+			jobStatus.Artifacts = getArtifacts(rStatus.TaskRunResults)
+			jobStatus.TestReport = getTestReport(rStatus.TaskRunResults)
+			jobStatus.Coverage = getCoverage(rStatus.TaskRunResults)
+			jobStatus.Lint = getLintReport(rStatus.TaskRunResults)
 			jobStatus.Containers = nil
 			for _, s := range rStatus.Steps {
 				stepStatus := s.DeepCopy()
@@ -404,6 +664,14 @@ func getJobRunStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job) *cicdv1.JobSt
 			break
 		}
 	}
+	// Jobs skipped by Tekton's when expressions are reported as successful, not to block the pull requests
+	for _, skipped := range pr.Status.SkippedTasks {
+		if skipped.Name == j.Name {
+			jobStatus.State = cicdv1.CommitStatusStateSuccess
+			jobStatus.Message = JobMessageSkipped
+			return jobStatus
+		}
+	}
 	// Now find in Run
 	if jobStatus.PodName == "" {
 		for _, runStatus := range pr.Status.Runs {
@@ -424,9 +692,33 @@ func getJobRunStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job) *cicdv1.JobSt
 			}
 		}
 	}
+	// Jobs requiring approvals wait for their approval gates
+	if j.ApprovalRequired && jobStatus.StartTime == nil {
+		reflectApprovalGateStatus(pr, j, jobStatus)
+	}
 	return jobStatus
 }
 
+// reflectApprovalGateStatus reflects the status of the approval gate to the job, which is not started yet
+// The job is failed if the approval is rejected, as it never runs
+func reflectApprovalGateStatus(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job, jobStatus *cicdv1.JobStatus) {
+	for _, runStatus := range pr.Status.Runs {
+		if runStatus.Status == nil || runStatus.PipelineTaskName != j.GetApprovalGateName() || len(runStatus.Status.Conditions) == 0 {
+			continue
+		}
+		cond := runStatus.Status.Conditions[0]
+		switch cond.Status {
+		case corev1.ConditionUnknown:
+			jobStatus.Message = JobMessageWaitingForApproval
+		case corev1.ConditionFalse:
+			jobStatus.State = cicdv1.CommitStatusStateFailure
+			jobStatus.Message = cond.Message
+			jobStatus.CompletionTime = runStatus.Status.CompletionTime.DeepCopy()
+		}
+		return
+	}
+}
+
 func (p *pipelineManager) updateGitCommitStatus(cfg *cicdv1.IntegrationConfig, job *cicdv1.IntegrationJob, stateChanged []bool) error {
 	// Skip if token is nil
 	if cfg.Spec.Git.Token == nil {
@@ -442,31 +734,82 @@ func (p *pipelineManager) updateGitCommitStatus(cfg *cicdv1.IntegrationConfig, j
 		return nil
 	}
 
+	// Get SHA of the commit
+	var sha string
+	if job.Spec.Refs.Pulls == nil {
+		sha = job.Spec.Refs.Base.Sha
+	} else {
+		sha = job.Spec.Refs.Pulls[0].Sha
+	}
+
+	// Report a single commit status for all the jobs, if it's aggregated
+	aggregateContext := cfg.GetAggregateCommitStatusContext()
+	if aggregateContext != "" && anyChanged(stateChanged) {
+		jobs, err := p.listAggregatedJobs(job)
+		if err != nil {
+			return err
+		}
+		status := aggregateCommitStatus(job, jobs, aggregateContext)
+		if cfg.Spec.Git.CheckRuns {
+			status.Summary = generateAggregateCheckRunSummary(jobs)
+		}
+		log.Info(fmt.Sprintf("Setting commit status %s:%s to %s's %s", status.Context, status.State, cfg.Spec.Git.Repository, sha))
+		if err := gitCli.SetCommitStatus(sha, status); err != nil {
+			log.Error(err, "")
+		}
+	}
+
 	// If state is changed, update git commit status
 	for i, j := range job.Status.Jobs {
 		if stateChanged[i] {
-			// Set simple message
-			msg := JobMessagePending
-			switch j.State {
-			case cicdv1.CommitStatusStateSuccess:
-				msg = JobMessageSuccessful
-			case cicdv1.CommitStatusStateFailure:
-				msg = JobMessageFailure
-			}
-			if job.Spec.Refs.Pulls != nil {
-				msg = appendBaseShaToDescription(msg, job.Spec.Refs.Base.Sha)
-			}
+			if aggregateContext == "" {
+				// Set simple message
+				msg := JobMessagePending
+				switch j.State {
+				case cicdv1.CommitStatusStatePending:
+					if j.Message == JobMessageWaitingForApproval {
+						msg = JobMessageWaitingForApproval
+					}
+				case cicdv1.CommitStatusStateSuccess:
+					msg = JobMessageSuccessful
+					if j.Message == JobMessageSkipped {
+						msg = JobMessageSkipped
+					}
+				case cicdv1.CommitStatusStateFailure:
+					msg = JobMessageFailure
+					if j.Message == JobMessageTimedOut {
+						msg = JobMessageTimedOut
+					}
+				case cicdv1.CommitStatusStateError:
+					msg = JobMessageFailure
+					if j.Message == JobMessageSuperseded || j.Message == JobMessageCancelled {
+						msg = j.Message
+					}
+				}
+				if j.Attempts > 1 {
+					msg = fmt.Sprintf("%s (attempt %d)", msg, j.Attempts)
+				}
+				if job.Spec.Refs.Pulls != nil {
+					msg = appendBaseShaToDescription(msg, job.Spec.Refs.Base.Sha)
+				}
 
-			// Get SHA of the commit
-			var sha string
-			if job.Spec.Refs.Pulls == nil {
-				sha = job.Spec.Refs.Base.Sha
-			} else {
-				sha = job.Spec.Refs.Pulls[0].Sha
+				status := git.CommitStatus{Context: j.Name, State: git.CommitStatusState(j.State), Description: msg, TargetURL: getTargetURL(job, j.Name)}
+				setCheckRunOutput(&status, cfg, &job.Status.Jobs[i])
+				log.Info(fmt.Sprintf("Setting commit status %s:%s to %s's %s", j.Name, j.State, cfg.Spec.Git.Repository, sha))
+				if err := gitCli.SetCommitStatus(sha, status); err != nil {
+					log.Error(err, "")
+				}
 			}
-			log.Info(fmt.Sprintf("Setting commit status %s:%s to %s's %s", j.Name, j.State, cfg.Spec.Git.Repository, sha))
-			if err := gitCli.SetCommitStatus(sha, git.CommitStatus{Context: j.Name, State: git.CommitStatusState(j.State), Description: msg, TargetURL: job.GetReportServerAddress(j.Name)}); err != nil {
-				log.Error(err, "")
+
+			// Check the coverage against the threshold
+			if status := coverageCommitStatus(&job.Spec.Jobs[i], &j); status != nil {
+				if job.Spec.Refs.Pulls != nil {
+					status.Description = appendBaseShaToDescription(status.Description, job.Spec.Refs.Base.Sha)
+				}
+				status.TargetURL = getTargetURL(job, j.Name)
+				if err := gitCli.SetCommitStatus(sha, *status); err != nil {
+					log.Error(err, "")
+				}
 			}
 		}
 	}
@@ -514,15 +857,6 @@ func ParseBaseFromDescription(desc string) string {
 	return sub[1]
 }
 
-// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
-
-func (p *pipelineManager) emitEvents(job *cicdv1.IntegrationJob, oldState cicdv1.IntegrationJobState, oldMessage string) error {
-	if oldState == job.Status.State && oldMessage == job.Status.Message {
-		return nil
-	}
-	return events.Emit(p.Client, job, corev1.EventTypeNormal, string(job.Status.State), job.Status.Message)
-}
-
 // Name is a PipelineRun's name for the IntegrationJob j
 func Name(j *cicdv1.IntegrationJob) string {
 	return j.Name
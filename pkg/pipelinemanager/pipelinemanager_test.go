@@ -17,13 +17,25 @@
 package pipelinemanager
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bmizerany/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	runv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/run/v1alpha1"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAppendBaseShaToDescription(t *testing.T) {
@@ -133,3 +145,442 @@ func TestGetParams(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateTask_runAfter(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "build", Image: "busybox"}},
+				{Container: corev1.Container{Name: "test", Image: "busybox"}, After: []string{"build", "lint"}},
+			},
+		},
+	}
+
+	task, _, err := generateTask(job, &job.Spec.Jobs[1])
+	require.NoError(t, err)
+	require.Equal(t, []string{"build"}, task.RunAfter)
+}
+
+func TestGetJobRunStatus_skipped(t *testing.T) {
+	pr := &tektonv1beta1.PipelineRun{
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				SkippedTasks: []tektonv1beta1.SkippedTask{{Name: "skipped"}},
+			},
+		},
+	}
+
+	status := getJobRunStatus(pr, &cicdv1.Job{Container: corev1.Container{Name: "skipped"}})
+	require.Equal(t, cicdv1.CommitStatusStateSuccess, status.State)
+	require.Equal(t, JobMessageSkipped, status.Message)
+
+	status = getJobRunStatus(pr, &cicdv1.Job{Container: corev1.Container{Name: "pending"}})
+	require.Equal(t, cicdv1.CommitStatusStatePending, status.State)
+}
+
+func TestGenerateTask_timeout(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "build", Image: "busybox"}},
+				{Container: corev1.Container{Name: "test", Image: "busybox"}, Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			},
+		},
+	}
+
+	task, _, err := generateTask(job, &job.Spec.Jobs[0])
+	require.NoError(t, err)
+	require.Nil(t, task.Timeout)
+
+	task, _, err = generateTask(job, &job.Spec.Jobs[1])
+	require.NoError(t, err)
+	require.Equal(t, &metav1.Duration{Duration: 10 * time.Minute}, task.Timeout)
+}
+
+func TestGenerateTask_workspaces(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Workspaces: []tektonv1beta1.WorkspaceBinding{{Name: "build"}, {Name: "cache"}},
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "lint", Image: "busybox"}},
+				{Container: corev1.Container{Name: "compile", Image: "busybox"}, Workspaces: []cicdv1.JobWorkspace{{Name: "build", MountPath: "/build"}}},
+				{Container: corev1.Container{Name: "test", Image: "busybox"}, Workspaces: []cicdv1.JobWorkspace{{Name: "build", MountPath: "/build", ReadOnly: true}}},
+			},
+		},
+	}
+
+	task, _, err := generateTask(job, &job.Spec.Jobs[0])
+	require.NoError(t, err)
+	require.Equal(t, []tektonv1beta1.WorkspaceDeclaration{{Name: "build"}, {Name: "cache"}}, task.TaskSpec.Workspaces)
+	require.Equal(t, []tektonv1beta1.WorkspacePipelineTaskBinding{{Name: "build", Workspace: "build"}, {Name: "cache", Workspace: "cache"}}, task.Workspaces)
+
+	task, _, err = generateTask(job, &job.Spec.Jobs[1])
+	require.NoError(t, err)
+	require.Equal(t, []tektonv1beta1.WorkspaceDeclaration{{Name: "build", MountPath: "/build"}}, task.TaskSpec.Workspaces)
+	require.Equal(t, []tektonv1beta1.WorkspacePipelineTaskBinding{{Name: "build", Workspace: "build"}}, task.Workspaces)
+
+	task, _, err = generateTask(job, &job.Spec.Jobs[2])
+	require.NoError(t, err)
+	require.Equal(t, []tektonv1beta1.WorkspaceDeclaration{{Name: "build", MountPath: "/build", ReadOnly: true}}, task.TaskSpec.Workspaces)
+}
+
+func TestGetJobRunStatus_timedOut(t *testing.T) {
+	pr := &tektonv1beta1.PipelineRun{
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					"test-run": {
+						PipelineTaskName: "test",
+						Status: &tektonv1beta1.TaskRunStatus{
+							Status: duckv1beta1.Status{
+								Conditions: duckv1beta1.Conditions{{
+									Type:    apis.ConditionSucceeded,
+									Status:  corev1.ConditionFalse,
+									Reason:  string(tektonv1beta1.TaskRunReasonTimedOut),
+									Message: `TaskRun "test-run" failed to finish within "10m0s"`,
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	status := getJobRunStatus(pr, &cicdv1.Job{Container: corev1.Container{Name: "test"}})
+	require.Equal(t, cicdv1.CommitStatusStateFailure, status.State)
+	require.Equal(t, JobMessageTimedOut, status.Message)
+}
+
+func TestGenerateTask_retries(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test", Image: "busybox"}, Retries: 2},
+			},
+		},
+	}
+
+	task, _, err := generateTask(job, &job.Spec.Jobs[0])
+	require.NoError(t, err)
+	require.Equal(t, 2, task.Retries)
+}
+
+func TestGetJobRunStatus_attempts(t *testing.T) {
+	pr := &tektonv1beta1.PipelineRun{
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					"test-run": {
+						PipelineTaskName: "test",
+						Status: &tektonv1beta1.TaskRunStatus{
+							TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+								PodName:       "test-run-pod-retry1",
+								RetriesStatus: []tektonv1beta1.TaskRunStatus{{}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	status := getJobRunStatus(pr, &cicdv1.Job{Container: corev1.Container{Name: "test"}})
+	require.Equal(t, cicdv1.CommitStatusStatePending, status.State)
+	require.Equal(t, 2, status.Attempts)
+}
+
+func TestMarkSuperseded(t *testing.T) {
+	completed := &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	job := &cicdv1.IntegrationJob{
+		Status: cicdv1.IntegrationJobStatus{
+			State: cicdv1.IntegrationJobStateRunning,
+			Jobs: []cicdv1.JobStatus{
+				{Name: "lint", State: cicdv1.CommitStatusStateSuccess, CompletionTime: completed},
+				{Name: "test", State: cicdv1.CommitStatusStatePending},
+			},
+		},
+	}
+	stateChanged := make([]bool, 2)
+
+	markSuperseded(job, "new-ij", stateChanged)
+
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, job.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonSuperseded, job.Status.Reason)
+	require.Equal(t, "Superseded by IntegrationJob new-ij", job.Status.Message)
+	require.Equal(t, []bool{false, true}, stateChanged)
+	require.Equal(t, cicdv1.CommitStatusStateSuccess, job.Status.Jobs[0].State)
+	require.Equal(t, completed, job.Status.Jobs[0].CompletionTime)
+	require.Equal(t, cicdv1.CommitStatusStateError, job.Status.Jobs[1].State)
+	require.Equal(t, JobMessageSuperseded, job.Status.Jobs[1].Message)
+	require.NotNil(t, job.Status.Jobs[1].CompletionTime)
+}
+
+func TestMarkCancelled(t *testing.T) {
+	completed := &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	job := &cicdv1.IntegrationJob{
+		Status: cicdv1.IntegrationJobStatus{
+			State: cicdv1.IntegrationJobStatePending,
+			Jobs: []cicdv1.JobStatus{
+				{Name: "lint", State: cicdv1.CommitStatusStateFailure, CompletionTime: completed},
+				{Name: "test", State: cicdv1.CommitStatusStatePending},
+			},
+		},
+	}
+	stateChanged := make([]bool, 2)
+
+	markCancelled(job, stateChanged)
+
+	require.Equal(t, cicdv1.IntegrationJobStateCancelled, job.Status.State)
+	require.Equal(t, []bool{false, true}, stateChanged)
+	require.Equal(t, cicdv1.CommitStatusStateFailure, job.Status.Jobs[0].State)
+	require.Equal(t, cicdv1.CommitStatusStateError, job.Status.Jobs[1].State)
+	require.Equal(t, JobMessageCancelled, job.Status.Jobs[1].Message)
+	require.NotNil(t, job.Status.Jobs[1].CompletionTime)
+}
+
+func TestGenerateTaskRunSpecs(t *testing.T) {
+	jobs := cicdv1.Jobs{
+		{Container: corev1.Container{Name: "test", Image: "busybox"}},
+		{Container: corev1.Container{Name: "deploy", Image: "busybox"}, ServiceAccountName: "deployer"},
+	}
+
+	job := &cicdv1.IntegrationJob{}
+
+	require.Equal(t, []tektonv1beta1.PipelineTaskRunSpec{{PipelineTaskName: "deploy", TaskServiceAccountName: "deployer"}}, generateTaskRunSpecs(job, jobs))
+	require.Nil(t, generateTaskRunSpecs(job, jobs[:1]))
+}
+
+func TestGenerateTaskRunSpecs_podSecurityContext(t *testing.T) {
+	nonRoot := true
+	fsGroup := int64(1000)
+	podSecurityContext := &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot, FSGroup: &fsGroup}
+	jobs := cicdv1.Jobs{
+		{Container: corev1.Container{Name: "test", Image: "busybox"}},
+		{Container: corev1.Container{Name: "build", Image: "busybox"}, PodSecurityContext: podSecurityContext},
+	}
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			PodTemplate: &pod.Template{NodeSelector: map[string]string{"ci": "true"}},
+		},
+	}
+
+	specs := generateTaskRunSpecs(job, jobs)
+	require.Len(t, specs, 1)
+	require.Equal(t, "build", specs[0].PipelineTaskName)
+	require.Empty(t, specs[0].TaskServiceAccountName)
+	require.Equal(t, &pod.Template{NodeSelector: map[string]string{"ci": "true"}, SecurityContext: podSecurityContext}, specs[0].TaskPodTemplate)
+
+	// IntegrationJob's pod template should not be modified
+	require.Nil(t, job.Spec.PodTemplate.SecurityContext)
+}
+
+func TestGenerateTaskRunSpecs_nodePlacement(t *testing.T) {
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node-type", Operator: corev1.NodeSelectorOpIn, Values: []string{"build"}}},
+		}}},
+	}}
+	jobs := cicdv1.Jobs{
+		{
+			Container:    corev1.Container{Name: "build-arm64", Image: "busybox"},
+			NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+			Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "build", Effect: corev1.TaintEffectNoSchedule}},
+			Affinity:     affinity,
+		},
+	}
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			PodTemplate: &pod.Template{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "amd64", "kubernetes.io/os": "linux"},
+				Tolerations:  []corev1.Toleration{{Key: "ci", Operator: corev1.TolerationOpExists}},
+			},
+		},
+	}
+
+	specs := generateTaskRunSpecs(job, jobs)
+	require.Len(t, specs, 1)
+	require.Equal(t, &pod.Template{
+		NodeSelector: map[string]string{"kubernetes.io/arch": "arm64", "kubernetes.io/os": "linux"},
+		Tolerations: []corev1.Toleration{
+			{Key: "ci", Operator: corev1.TolerationOpExists},
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "build", Effect: corev1.TaintEffectNoSchedule},
+		},
+		Affinity: affinity,
+	}, specs[0].TaskPodTemplate)
+
+	// IntegrationJob's pod template should not be modified
+	require.Equal(t, "amd64", job.Spec.PodTemplate.NodeSelector["kubernetes.io/arch"])
+	require.Len(t, job.Spec.PodTemplate.Tolerations, 1)
+}
+
+func TestGenerateSteps_securityContext(t *testing.T) {
+	nonRoot := true
+	securityContext := &corev1.SecurityContext{RunAsNonRoot: &nonRoot}
+	steps, err := generateSteps(&cicdv1.IntegrationJob{}, &cicdv1.Job{Container: corev1.Container{Name: "test", Image: "busybox", SecurityContext: securityContext}})
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	require.Equal(t, securityContext, steps[0].SecurityContext, "checkout step should have the job's security context")
+	require.Equal(t, securityContext, steps[1].SecurityContext)
+
+	steps, err = generateSteps(&cicdv1.IntegrationJob{}, &cicdv1.Job{Container: corev1.Container{Name: "test", Image: "busybox"}})
+	require.NoError(t, err)
+	require.Nil(t, steps[0].SecurityContext)
+}
+
+func TestGenerateSteps_envFrom(t *testing.T) {
+	envFrom := []corev1.EnvFromSource{
+		{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "test-db"}}},
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "test-config"}}, Prefix: "CFG_"},
+	}
+	steps, err := generateSteps(&cicdv1.IntegrationJob{}, &cicdv1.Job{Container: corev1.Container{Name: "test", Image: "busybox", EnvFrom: envFrom}})
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	require.Empty(t, steps[0].EnvFrom, "checkout step should not be injected")
+	require.Equal(t, envFrom, steps[1].EnvFrom)
+}
+
+func TestGenerateSteps_checkout(t *testing.T) {
+	disabled := false
+	enabled := true
+	depth := 1
+	fullDepth := 0
+
+	tc := map[string]struct {
+		icCheckout  *cicdv1.JobCheckout
+		jobCheckout *cicdv1.JobCheckout
+
+		expectedSubmodules bool
+		expectedLFS        bool
+		expectedEnv        []corev1.EnvVar
+	}{
+		"default": {
+			expectedSubmodules: false,
+		},
+		"shallow": {
+			icCheckout:         &cicdv1.JobCheckout{FetchDepth: &depth},
+			expectedSubmodules: false,
+			expectedEnv:        []corev1.EnvVar{{Name: "CHECKOUT_FETCH_DEPTH", Value: "1"}},
+		},
+		"lfs": {
+			jobCheckout:        &cicdv1.JobCheckout{LFS: &enabled},
+			expectedSubmodules: false,
+			expectedLFS:        true,
+		},
+		"jobFullDepth": {
+			icCheckout:         &cicdv1.JobCheckout{FetchDepth: &depth},
+			jobCheckout:        &cicdv1.JobCheckout{FetchDepth: &fullDepth},
+			expectedSubmodules: false,
+		},
+		"icEnabled": {
+			icCheckout:         &cicdv1.JobCheckout{Submodules: &enabled},
+			expectedSubmodules: true,
+		},
+		"icDisabled": {
+			icCheckout:         &cicdv1.JobCheckout{Submodules: &disabled},
+			expectedSubmodules: false,
+		},
+		"jobOverride": {
+			icCheckout:         &cicdv1.JobCheckout{Submodules: &disabled},
+			jobCheckout:        &cicdv1.JobCheckout{Submodules: &enabled},
+			expectedSubmodules: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			job := &cicdv1.IntegrationJob{Spec: cicdv1.IntegrationJobSpec{Checkout: c.icCheckout}}
+			steps, err := generateSteps(job, &cicdv1.Job{Container: corev1.Container{Name: "test", Image: "busybox"}, Checkout: c.jobCheckout})
+			require.NoError(t, err)
+			require.Len(t, steps, 2)
+			require.Equal(t, c.expectedSubmodules, strings.Contains(steps[0].Script, "git submodule update --init --recursive"))
+			require.Equal(t, c.expectedLFS, strings.Contains(steps[0].Script, "git lfs pull"))
+			require.Equal(t, c.expectedEnv, steps[0].Env)
+		})
+	}
+}
+
+func TestPipelineManager_Generate_approvalRequired(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	job := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePostSubmit},
+			Timeout:   &metav1.Duration{Duration: time.Hour},
+			Refs: cicdv1.IntegrationJobRefs{
+				Repository: "tmax-cloud/cicd-operator",
+				Base:       cicdv1.IntegrationJobRefsBase{Ref: "refs/heads/master", Sha: "1111111111", Link: "https://github.com/tmax-cloud/cicd-operator"},
+				Sender:     &cicdv1.IntegrationJobSender{Name: "sender"},
+			},
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "build", Image: "busybox"}},
+				{
+					Container:        corev1.Container{Name: "deploy", Image: "busybox"},
+					After:            []string{"build"},
+					TektonWhen:       tektonv1beta1.WhenExpressions{{Input: "$(params.env)", Operator: "in", Values: []string{"prod"}}},
+					ApprovalRequired: true,
+					Approval:         &cicdv1.JobApproval{Approvers: []cicdv1.ApprovalUser{{Name: "admin", Email: "admin@tmax.co.kr"}}},
+				},
+			},
+		},
+	}
+
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).Build(), Scheme: s}
+	pr, err := p.Generate(job)
+	require.NoError(t, err)
+
+	tasks := pr.Spec.PipelineSpec.Tasks
+	require.Len(t, tasks, 3)
+
+	gate := tasks[1]
+	require.Equal(t, "deploy-approval-gate", gate.Name)
+	require.Equal(t, cicdv1.CustomTaskKindApproval, string(gate.TaskRef.Kind))
+	require.Equal(t, []string{"build"}, gate.RunAfter)
+	require.Equal(t, job.Spec.Jobs[1].TektonWhen, gate.WhenExpressions)
+	require.Equal(t, []string{"admin=admin@tmax.co.kr"}, gate.Params[0].Value.ArrayVal)
+	require.Equal(t, cicdv1.CustomTaskApprovalParamKeyIntegrationJobJob, gate.Params[3].Name)
+	require.Equal(t, "deploy", gate.Params[3].Value.StringVal)
+
+	deploy := tasks[2]
+	require.Equal(t, "deploy", deploy.Name)
+	require.Nil(t, deploy.TaskRef)
+	require.NotNil(t, deploy.TaskSpec)
+	require.Equal(t, []string{"deploy-approval-gate"}, deploy.RunAfter)
+}
+
+func TestGetJobRunStatus_approvalGate(t *testing.T) {
+	now := &metav1.Time{Time: time.Now()}
+	gateStatus := func(status corev1.ConditionStatus, msg string, completionTime *metav1.Time) *tektonv1beta1.PipelineRun {
+		return &tektonv1beta1.PipelineRun{
+			Status: tektonv1beta1.PipelineRunStatus{
+				PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+					Runs: map[string]*tektonv1beta1.PipelineRunRunStatus{
+						"deploy-approval-gate-run": {
+							PipelineTaskName: "deploy-approval-gate",
+							Status: &runv1alpha1.RunStatus{
+								Status:          duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status, Message: msg}}},
+								RunStatusFields: runv1alpha1.RunStatusFields{StartTime: now, CompletionTime: completionTime},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	j := &cicdv1.Job{Container: corev1.Container{Name: "deploy"}, ApprovalRequired: true}
+
+	status := getJobRunStatus(gateStatus(corev1.ConditionUnknown, "", nil), j)
+	require.Equal(t, cicdv1.CommitStatusStatePending, status.State)
+	require.Equal(t, JobMessageWaitingForApproval, status.Message)
+
+	status = getJobRunStatus(gateStatus(corev1.ConditionFalse, "admin rejected this approval", now), j)
+	require.Equal(t, cicdv1.CommitStatusStateFailure, status.State)
+	require.Equal(t, "admin rejected this approval", status.Message)
+	require.Equal(t, now, status.CompletionTime)
+
+	status = getJobRunStatus(gateStatus(corev1.ConditionTrue, "admin approved this approval", now), j)
+	require.Equal(t, cicdv1.CommitStatusStatePending, status.State)
+	require.Empty(t, status.Message)
+}
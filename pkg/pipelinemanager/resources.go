@@ -0,0 +1,101 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ResolveJobs returns the IntegrationJob's jobs as they are run, i.e., with the job templates rendered and the
+// resources of the Tekton Tasks' steps set to the jobs' resources, so that their resource requests can be calculated
+// The IntegrationJob itself is not modified
+func (p *pipelineManager) ResolveJobs(job *cicdv1.IntegrationJob) (cicdv1.Jobs, error) {
+	jobs, err := p.renderTemplates(job)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		j := &jobs[i]
+		if j.TektonTask == nil {
+			continue
+		}
+		spec, err := p.getTektonTaskSpec(job, j)
+		if err != nil {
+			return nil, err
+		}
+		if spec != nil {
+			j.Resources = stepsResources(spec.Steps)
+		}
+	}
+	return jobs, nil
+}
+
+// getTektonTaskSpec returns the spec of the Tekton Task the job refers to
+// Nil is returned for the tasks in bundles, as they can only be pulled by Tekton
+func (p *pipelineManager) getTektonTaskSpec(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.TaskSpec, error) {
+	ref := j.TektonTask.TaskRef
+	switch {
+	case ref.Local != nil:
+		cli, err := remotecluster.ClientFor(p.Client, job)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Local.Kind == tektonv1beta1.ClusterTaskKind {
+			task := &tektonv1beta1.ClusterTask{}
+			if err := cli.Get(context.Background(), types.NamespacedName{Name: ref.Local.Name}, task); err != nil {
+				return nil, err
+			}
+			return &task.Spec, nil
+		}
+		task := &tektonv1beta1.Task{}
+		if err := cli.Get(context.Background(), types.NamespacedName{Name: ref.Local.Name, Namespace: job.Namespace}, task); err != nil {
+			return nil, err
+		}
+		return &task.Spec, nil
+	case ref.Resolver != nil:
+		return p.resolveTask(job, j)
+	case ref.Catalog != "":
+		return fetchCatalogRef(ref.Catalog)
+	}
+	return nil, nil
+}
+
+// stepsResources returns the resources of a Task's pod, i.e., the largest requests and limits among its steps, as
+// Tekton requests only the largest ones for the steps, which run one by one
+func stepsResources(steps []tektonv1beta1.Step) corev1.ResourceRequirements {
+	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	for _, step := range steps {
+		maxResources(resources.Requests, step.Resources.Requests)
+		maxResources(resources.Limits, step.Resources.Limits)
+	}
+	return resources
+}
+
+func maxResources(max, resources corev1.ResourceList) {
+	for name, q := range resources {
+		if cur, exist := max[name]; !exist || q.Cmp(cur) > 0 {
+			max[name] = q.DeepCopy()
+		}
+	}
+}
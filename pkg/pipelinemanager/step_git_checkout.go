@@ -17,7 +17,10 @@
 package pipelinemanager
 
 import (
+	"strconv"
+
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -47,27 +50,71 @@ else
   CHECKOUT_REF="$CI_BASE_REF"
 fi
 
-git fetch "$CHECKOUT_URL" "$CHECKOUT_REF"
+CHECKOUT_FETCH_OPTS=""
+if [ "$CHECKOUT_FETCH_DEPTH" != "" ]; then
+  # Shallow clone
+  CHECKOUT_FETCH_OPTS="--depth=$CHECKOUT_FETCH_DEPTH"
+fi
+
+git fetch $CHECKOUT_FETCH_OPTS "$CHECKOUT_URL" "$CHECKOUT_REF"
 git checkout FETCH_HEAD
 
 if [ "$CI_BASE_REF" != "" ]; then
   # Pull request event
   for ci_head_ref in $CI_HEAD_REF_ARRAY; do 
-    git fetch "$CHECKOUT_URL" "$ci_head_ref"
-    git merge --no-ff FETCH_HEAD
+    git fetch $CHECKOUT_FETCH_OPTS "$CHECKOUT_URL" "$ci_head_ref"
+    if ! git merge --no-ff FETCH_HEAD; then
+      # Shallow history may not contain the merge base. Retry with the full history
+      [ -f .git/shallow ]
+      git merge --abort || true
+      git fetch --unshallow "$CHECKOUT_URL" "$CHECKOUT_REF"
+      git fetch "$CHECKOUT_URL" "$ci_head_ref"
+      git merge --no-ff FETCH_HEAD
+    fi
   done
 fi
+`
+
+// lfsScript pulls the LFS objects of the checked out commit from the checkout url, so they are fetched with the same
+// credentials as the repository
+const lfsScript = `
+if ! git lfs version; then
+  echo "git-lfs is not installed in the git image"
+  exit 1
+fi
+git config remote.origin.url "$CHECKOUT_URL"
+git lfs install --local
+git lfs pull origin
+`
 
+// submoduleScript initializes the submodules recursively. Relative urls are resolved against the checkout url, and
+// ssh urls of the same git server are rewritten to the server url, so the submodules are fetched with the same
+// credentials as the repository
+const submoduleScript = `
+git config remote.origin.url "$CHECKOUT_URL"
+CI_SERVER_HOST=$(echo "$CI_SERVER_URL" | sed -e 's|^[a-z]*://||' -e 's|/.*$||')
+git config --global url."$CI_SERVER_URL/".insteadOf "git@$CI_SERVER_HOST:"
+git config --global --add url."$CI_SERVER_URL/".insteadOf "ssh://git@$CI_SERVER_HOST/"
+git submodule sync --recursive
 git submodule update --init --recursive
 `
 
-func gitCheckout() tektonv1beta1.Step {
+func gitCheckout(checkout *cicdv1.JobCheckout) tektonv1beta1.Step {
 	step := tektonv1beta1.Step{}
 
 	step.Name = "git-clone"
 	step.Image = configs.GitImage
 	step.WorkingDir = DefaultWorkingDir
 	step.Script = defaultScript
+	if checkout.GetLFS() {
+		step.Script += lfsScript
+	}
+	if checkout.GetSubmodules() {
+		step.Script += submoduleScript
+	}
+	if depth := checkout.GetFetchDepth(); depth > 0 {
+		step.Env = append(step.Env, corev1.EnvVar{Name: "CHECKOUT_FETCH_DEPTH", Value: strconv.Itoa(depth)})
+	}
 
 	cpuReq, err := resource.ParseQuantity(configs.GitCheckoutStepCPURequest)
 	if err != nil {
@@ -61,7 +61,7 @@ func Test_gitCheckout(t *testing.T) {
 			configs.GitCheckoutStepCPURequest = c.cpuReq
 			configs.GitCheckoutStepMemRequest = c.memReq
 
-			step := gitCheckout()
+			step := gitCheckout(nil)
 			require.Equal(t, c.expectedCpu, *step.Resources.Limits.Cpu())
 			require.Equal(t, c.expectedCpu, *step.Resources.Requests.Cpu())
 			require.Equal(t, c.expectedMem, *step.Resources.Limits.Memory())
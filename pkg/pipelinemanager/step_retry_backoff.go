@@ -0,0 +1,98 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxRetryBackoff is the maximum delay before a retry of a job
+const maxRetryBackoff = 5 * time.Minute
+
+// retryBackoffScript sleeps for the delay set in the TaskRun's annotation. The first attempt does not sleep, as the
+// annotation is not set yet
+const retryBackoffScript = `#!/bin/sh
+if [ -n "$RETRY_BACKOFF" ] && [ "$RETRY_BACKOFF" -gt 0 ]; then
+  echo "Retrying in $RETRY_BACKOFF seconds"
+  sleep "$RETRY_BACKOFF"
+fi
+`
+
+// retryBackoff is a step delaying the retries of the job. Tekton retries the failed TaskRun right away, so the delay
+// is read from the TaskRun's annotation, which is copied to the pod of each attempt
+func retryBackoff() tektonv1beta1.Step {
+	step := tektonv1beta1.Step{}
+	step.Name = "retry-backoff"
+	step.Image = configs.GitImage
+	step.Script = retryBackoffScript
+	step.Env = []corev1.EnvVar{{
+		Name: "RETRY_BACKOFF",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.annotations['%s']", cicdv1.TaskRunAnnotationRetryBackoff)},
+		},
+	}}
+	return step
+}
+
+// getRetryBackoff returns the delay before the retry, after the attempts are failed
+// It's configs.JobRetryBackoff for the first retry, and doubled for each retry
+func getRetryBackoff(attempts int) time.Duration {
+	backoff := time.Duration(configs.JobRetryBackoff) * time.Second
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff
+}
+
+// setRetryBackoff sets the delay before the next attempt of the job to its TaskRun, while the current attempt is
+// running. Nothing is done if the job is not retried anymore
+func (p *pipelineManager) setRetryBackoff(pr *tektonv1beta1.PipelineRun, j *cicdv1.Job, job *cicdv1.IntegrationJob) error {
+	for name, runStatus := range pr.Status.TaskRuns {
+		if runStatus.Status == nil || runStatus.PipelineTaskName != j.Name {
+			continue
+		}
+		attempts := len(runStatus.Status.RetriesStatus) + 1
+		if runStatus.Status.PodName == "" || runStatus.Status.CompletionTime != nil || attempts > j.Retries {
+			return nil
+		}
+
+		cli, err := remotecluster.ClientFor(p.Client, job)
+		if err != nil {
+			return err
+		}
+		backoff := strconv.Itoa(int(getRetryBackoff(attempts).Seconds()))
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}}}`, cicdv1.TaskRunAnnotationRetryBackoff, backoff)
+		tr := &tektonv1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pr.Namespace}}
+		return cli.Patch(context.Background(), tr, client.RawPatch(types.MergePatchType, []byte(patch)))
+	}
+	return nil
+}
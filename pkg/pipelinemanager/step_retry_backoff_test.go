@@ -0,0 +1,140 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateTask_retryBackoff(t *testing.T) {
+	configs.JobRetryBackoff = 10
+	defer func() { configs.JobRetryBackoff = 0 }()
+
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test", Image: "busybox"}, Retries: 2, SkipCheckout: true},
+				{Container: corev1.Container{Name: "lint", Image: "busybox"}, SkipCheckout: true},
+			},
+		},
+	}
+
+	task, _, err := generateTask(job, &job.Spec.Jobs[0])
+	require.NoError(t, err)
+	require.Len(t, task.TaskSpec.Steps, 2)
+	require.Equal(t, "retry-backoff", task.TaskSpec.Steps[0].Name)
+	require.Equal(t, "metadata.annotations['cicd.tmax.io/retry-backoff']", task.TaskSpec.Steps[0].Env[0].ValueFrom.FieldRef.FieldPath)
+
+	// Jobs not retried are not delayed
+	task, _, err = generateTask(job, &job.Spec.Jobs[1])
+	require.NoError(t, err)
+	require.Len(t, task.TaskSpec.Steps, 1)
+
+	// Retries are not delayed if the backoff is disabled
+	configs.JobRetryBackoff = 0
+	task, _, err = generateTask(job, &job.Spec.Jobs[0])
+	require.NoError(t, err)
+	require.Len(t, task.TaskSpec.Steps, 1)
+}
+
+func TestGetRetryBackoff(t *testing.T) {
+	configs.JobRetryBackoff = 10
+	defer func() { configs.JobRetryBackoff = 0 }()
+
+	require.Equal(t, 10*time.Second, getRetryBackoff(1))
+	require.Equal(t, 20*time.Second, getRetryBackoff(2))
+	require.Equal(t, 40*time.Second, getRetryBackoff(3))
+	require.Equal(t, maxRetryBackoff, getRetryBackoff(10))
+}
+
+func TestPipelineManager_setRetryBackoff(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	configs.JobRetryBackoff = 10
+	defer func() { configs.JobRetryBackoff = 0 }()
+
+	completed := &metav1.Time{Time: time.Now()}
+
+	tc := map[string]struct {
+		podName        string
+		retriesStatus  []tektonv1beta1.TaskRunStatus
+		completionTime *metav1.Time
+
+		expectedBackoff string
+	}{
+		"firstAttempt": {
+			podName:         "test-run-pod-1",
+			expectedBackoff: "10",
+		},
+		"secondAttempt": {
+			podName:         "test-run-pod-2",
+			retriesStatus:   []tektonv1beta1.TaskRunStatus{{}},
+			expectedBackoff: "20",
+		},
+		"lastAttempt": {
+			podName:       "test-run-pod-3",
+			retriesStatus: []tektonv1beta1.TaskRunStatus{{}, {}},
+		},
+		"notStarted": {},
+		"completed": {
+			podName:        "test-run-pod-1",
+			completionTime: completed,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			tr := &tektonv1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "test-run", Namespace: "default"}}
+			p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(tr).Build()}
+
+			pr := &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "test-pr", Namespace: "default"}}
+			pr.Status.TaskRuns = map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"test-run": {
+					PipelineTaskName: "test",
+					Status: &tektonv1beta1.TaskRunStatus{
+						TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+							PodName:        c.podName,
+							RetriesStatus:  c.retriesStatus,
+							CompletionTime: c.completionTime,
+						},
+					},
+				},
+			}
+			j := &cicdv1.Job{Container: corev1.Container{Name: "test"}, Retries: 2}
+
+			require.NoError(t, p.setRetryBackoff(pr, j, &cicdv1.IntegrationJob{}))
+
+			result := &tektonv1beta1.TaskRun{}
+			require.NoError(t, p.Client.Get(context.Background(), types.NamespacedName{Name: "test-run", Namespace: "default"}, result))
+			require.Equal(t, c.expectedBackoff, result.Annotations[cicdv1.TaskRunAnnotationRetryBackoff])
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pendingTaskRunReasons are the reasons of the TaskRuns whose pods are not running yet, e.g., not scheduled or pulling images
+var pendingTaskRunReasons = map[string]struct{}{
+	"Pending":               {},
+	"ExceededNodeResources": {},
+	"ExceededResourceQuota": {},
+}
+
+// findStuckReason returns a diagnostic message if the PipelineRun is stuck for longer than configs.StuckJobTimeout,
+// i.e., the PipelineRun or one of its TaskRuns has been pending, or the pod of a TaskRun running with no progress is
+// missing. It returns an empty string if it is not stuck
+func (p *pipelineManager) findStuckReason(pr *tektonv1beta1.PipelineRun, job *cicdv1.IntegrationJob) (string, error) {
+	if configs.StuckJobTimeout <= 0 || pr.IsDone() || pr.IsCancelled() {
+		return "", nil
+	}
+	timeout := time.Duration(configs.StuckJobTimeout) * time.Minute
+
+	// PipelineRun is not started by Tekton
+	if pr.Status.StartTime == nil {
+		if time.Since(pr.CreationTimestamp.Time) > timeout {
+			return fmt.Sprintf("PipelineRun %s has been pending for more than %s", pr.Name, timeout), nil
+		}
+		return "", nil
+	}
+
+	// Check TaskRuns in a consistent order
+	var names []string
+	for name := range pr.Status.TaskRuns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tr := pr.Status.TaskRuns[name]
+		if tr.Status == nil {
+			continue
+		}
+		cond := tr.Status.GetCondition(apis.ConditionSucceeded)
+		if cond == nil || cond.Status != corev1.ConditionUnknown || time.Since(cond.LastTransitionTime.Inner.Time) <= timeout {
+			continue
+		}
+
+		// TaskRun's pod is not running
+		if _, pending := pendingTaskRunReasons[cond.Reason]; pending {
+			return fmt.Sprintf("Job %s has been pending for more than %s: %s", tr.PipelineTaskName, timeout, cond.Message), nil
+		}
+
+		// TaskRun's pod is deleted while running
+		if tr.Status.PodName == "" {
+			continue
+		}
+		reader, err := p.podReader(job)
+		if err != nil {
+			return "", err
+		}
+		if err := reader.Get(context.Background(), types.NamespacedName{Name: tr.Status.PodName, Namespace: pr.Namespace}, &corev1.Pod{}); err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Sprintf("Pod %s of job %s is missing, while the job has made no progress for more than %s", tr.Status.PodName, tr.PipelineTaskName, timeout), nil
+			}
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// podReader returns the reader of the IntegrationJob's pods. The pods in the local cluster are read from the API server,
+// not to cache all the pods of the cluster
+func (p *pipelineManager) podReader(job *cicdv1.IntegrationJob) (client.Reader, error) {
+	if job.Spec.RemoteCluster != nil {
+		return remotecluster.ClientFor(p.Client, job)
+	}
+	return p.APIReader, nil
+}
+
+func markStuck(job *cicdv1.IntegrationJob, reason string, stateChanged []bool) {
+	job.Status.State = cicdv1.IntegrationJobStateFailed
+	job.Status.Reason = cicdv1.IntegrationJobReasonStuck
+	job.Status.Message = reason
+	markJobsNotCompleted(job, JobMessageStuck, stateChanged)
+}
+
+// notifyStuckJobs sends the failure notifications of the jobs marked as stuck, as they are not completed by the
+// PipelineRun and are not notified while reflecting the PipelineRun's status
+func (p *pipelineManager) notifyStuckJobs(job *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) {
+	for i := range job.Status.Jobs {
+		j := &job.Status.Jobs[i]
+		if j.Message != JobMessageStuck {
+			continue
+		}
+		if err := p.handleNotification(j, job, cfg); err != nil {
+			log.Error(err, "cannot send the notification", "job", j.Name)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPipelineManager_findStuckReason(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	old := metav1.Time{Time: time.Now().Add(-time.Hour)}
+	recent := metav1.Time{Time: time.Now().Add(-time.Minute)}
+	taskRun := func(reason, podName string, transition metav1.Time) map[string]*tektonv1beta1.PipelineRunTaskRunStatus {
+		return map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+			"test-ij-build": {
+				PipelineTaskName: "build",
+				Status: &tektonv1beta1.TaskRunStatus{
+					Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{{
+						Type:               apis.ConditionSucceeded,
+						Status:             corev1.ConditionUnknown,
+						Reason:             reason,
+						Message:            "pod status \"PodScheduled\":\"False\"",
+						LastTransitionTime: apis.VolatileTime{Inner: transition},
+					}}},
+					TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{PodName: podName},
+				},
+			},
+		}
+	}
+
+	tc := map[string]struct {
+		timeout   int
+		created   metav1.Time
+		started   *metav1.Time
+		taskRuns  map[string]*tektonv1beta1.PipelineRunTaskRunStatus
+		succeeded corev1.ConditionStatus
+
+		expected string
+	}{
+		"pipelineRunPending": {
+			timeout:  30,
+			created:  old,
+			expected: "PipelineRun test-ij has been pending for more than 30m0s",
+		},
+		"pipelineRunPendingShortly": {
+			timeout: 30,
+			created: recent,
+		},
+		"taskRunPending": {
+			timeout:  30,
+			created:  old,
+			started:  &old,
+			taskRuns: taskRun("Pending", "test-ij-build-pod", old),
+			expected: "Job build has been pending for more than 30m0s: pod status \"PodScheduled\":\"False\"",
+		},
+		"taskRunPendingShortly": {
+			timeout:  30,
+			created:  old,
+			started:  &old,
+			taskRuns: taskRun("Pending", "test-ij-build-pod", recent),
+		},
+		"podMissing": {
+			timeout:  30,
+			created:  old,
+			started:  &old,
+			taskRuns: taskRun("Running", "missing-pod", old),
+			expected: "Pod missing-pod of job build is missing, while the job has made no progress for more than 30m0s",
+		},
+		"podExists": {
+			timeout:  30,
+			created:  old,
+			started:  &old,
+			taskRuns: taskRun("Running", "test-ij-build-pod", old),
+		},
+		"completed": {
+			timeout:   30,
+			created:   old,
+			started:   &old,
+			taskRuns:  taskRun("Running", "missing-pod", old),
+			succeeded: corev1.ConditionTrue,
+		},
+		"disabled": {
+			created: old,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.StuckJobTimeout = c.timeout
+			pr := &tektonv1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default", CreationTimestamp: c.created},
+			}
+			pr.Status.StartTime = c.started
+			pr.Status.TaskRuns = c.taskRuns
+			if c.succeeded != "" {
+				pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: c.succeeded})
+			}
+
+			// Pods are read from the API server, not from the cache
+			p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).Build(), APIReader: fake.NewClientBuilder().WithScheme(s).WithObjects(
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-ij-build-pod", Namespace: "default"}},
+			).Build()}
+			reason, err := p.findStuckReason(pr, &cicdv1.IntegrationJob{})
+			require.NoError(t, err)
+			require.Equal(t, c.expected, reason)
+		})
+	}
+	configs.StuckJobTimeout = 0
+}
+
+func TestMarkStuck(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Status: cicdv1.IntegrationJobStatus{
+			State: cicdv1.IntegrationJobStateRunning,
+			Jobs:  []cicdv1.JobStatus{{Name: "build", State: cicdv1.CommitStatusStatePending}},
+		},
+	}
+	stateChanged := make([]bool, 1)
+
+	markStuck(job, "PipelineRun test-ij has been pending for more than 30m0s", stateChanged)
+
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, job.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonStuck, job.Status.Reason)
+	require.Equal(t, "PipelineRun test-ij has been pending for more than 30m0s", job.Status.Message)
+	require.Equal(t, []bool{true}, stateChanged)
+	require.Equal(t, cicdv1.CommitStatusStateError, job.Status.Jobs[0].State)
+	require.Equal(t, JobMessageStuck, job.Status.Jobs[0].Message)
+}
+
+func TestPipelineManager_notifyStuckJobs(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1alpha1.AddToScheme(s))
+
+	onFailure := &cicdv1.Notification{OnFailure: &cicdv1.NotificationMethods{Slack: &cicdv1.NotiSlack{URL: "https://hooks.slack.com/services/test", Message: "$JOB_NAME failed"}}}
+	jobs := cicdv1.Jobs{
+		{Container: corev1.Container{Name: "test"}, Notification: onFailure},
+		{Container: corev1.Container{Name: "deploy"}, Notification: onFailure},
+		{Container: corev1.Container{Name: "no-noti"}},
+	}
+	cfg := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec:       cicdv1.IntegrationConfigSpec{Jobs: cicdv1.IntegrationConfigJobs{PreSubmit: jobs}},
+	}
+	job := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+			Jobs:      jobs,
+		},
+		Status: cicdv1.IntegrationJobStatus{
+			Jobs: []cicdv1.JobStatus{
+				{Name: "test", State: cicdv1.CommitStatusStateFailure, Message: "exit code 1"},
+				{Name: "deploy", State: cicdv1.CommitStatusStateError, Message: JobMessageStuck},
+				{Name: "no-noti", State: cicdv1.CommitStatusStateError, Message: JobMessageStuck},
+			},
+		},
+	}
+
+	p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).Build(), Scheme: s}
+	p.notifyStuckJobs(job, cfg)
+
+	runList := &tektonv1alpha1.RunList{}
+	require.NoError(t, p.Client.List(context.Background(), runList))
+	var names []string
+	for _, r := range runList.Items {
+		names = append(names, r.Name)
+	}
+	require.Equal(t, []string{"test-ij-deploy-slack"}, names)
+}
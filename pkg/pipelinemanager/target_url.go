@@ -0,0 +1,66 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"bytes"
+	"text/template"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+)
+
+// targetURLData is a data for the commit status target url template
+type targetURLData struct {
+	// Namespace is a namespace of the IntegrationJob
+	Namespace string
+	// IntegrationJob is a name of the IntegrationJob, which is also the name of its PipelineRun
+	IntegrationJob string
+	// Job is a name of the job, which is also the name of its Tekton task in the PipelineRun
+	Job string
+	// Host is the external host name of the operator
+	Host string
+	// ReportURL is the default target url, i.e., the report page of the job
+	ReportURL string
+}
+
+// getTargetURL returns the target url of the job's commit status
+// If configs.CommitStatusTargetURLTemplate is set, it is compiled using targetURLData. Otherwise, the report page is used
+func getTargetURL(job *cicdv1.IntegrationJob, jobName string) string {
+	reportURL := job.GetReportServerAddress(jobName)
+	if configs.CommitStatusTargetURLTemplate == "" {
+		return reportURL
+	}
+
+	tmpl, err := template.New("").Parse(configs.CommitStatusTargetURLTemplate)
+	if err != nil {
+		log.Error(err, "cannot parse commit status target url template")
+		return reportURL
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, targetURLData{
+		Namespace:      job.Namespace,
+		IntegrationJob: job.Name,
+		Job:            jobName,
+		Host:           configs.CurrentExternalHostName,
+		ReportURL:      reportURL,
+	}); err != nil {
+		log.Error(err, "cannot execute commit status target url template")
+		return reportURL
+	}
+	return buf.String()
+}
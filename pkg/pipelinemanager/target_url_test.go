@@ -0,0 +1,69 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetTargetURL(t *testing.T) {
+	tc := map[string]struct {
+		template string
+
+		expected string
+	}{
+		"default": {
+			expected: "http://cicd.example.com/report/default/test-ij/test",
+		},
+		"logViewer": {
+			template: "https://dashboard.example.com/{{.Namespace}}/integrationjobs/{{.IntegrationJob}}/logs?task={{.Job}}",
+			expected: "https://dashboard.example.com/default/integrationjobs/test-ij/logs?task=test",
+		},
+		"reportURL": {
+			template: "https://redirect.example.com/?to={{.ReportURL}}",
+			expected: "https://redirect.example.com/?to=http://cicd.example.com/report/default/test-ij/test",
+		},
+		"host": {
+			template: "http://{{.Host}}/logs/{{.Namespace}}/{{.IntegrationJob}}/{{.Job}}",
+			expected: "http://cicd.example.com/logs/default/test-ij/test",
+		},
+		"parseError": {
+			template: "https://dashboard.example.com/{{.Namespace",
+			expected: "http://cicd.example.com/report/default/test-ij/test",
+		},
+		"executeError": {
+			template: "https://dashboard.example.com/{{.Unknown}}",
+			expected: "http://cicd.example.com/report/default/test-ij/test",
+		},
+	}
+
+	configs.CurrentExternalHostName = "cicd.example.com"
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.CommitStatusTargetURLTemplate = c.template
+			job := &cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}
+			require.Equal(t, c.expected, getTargetURL(job, "test"))
+		})
+	}
+	configs.CommitStatusTargetURLTemplate = ""
+	configs.CurrentExternalHostName = ""
+}
@@ -0,0 +1,100 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hubURL is Tekton Hub's API url for a task's yaml (i.e., /resource/<catalog>/task/<name>/<version>/yaml)
+var hubURL = "https://api.hub.tekton.dev/v1/resource/%s/task/%s/%s/yaml"
+
+const defaultHubCatalog = "tekton"
+
+// resolveTask fetches the spec of the task the job refers to, using the job's resolver
+func (p *pipelineManager) resolveTask(job *cicdv1.IntegrationJob, j *cicdv1.Job) (*tektonv1beta1.TaskSpec, error) {
+	r := j.TektonTask.TaskRef.Resolver
+	var spec *tektonv1beta1.TaskSpec
+	var err error
+	switch r.Type {
+	case cicdv1.TaskResolverTypeGit:
+		spec, err = p.resolveGitTask(job, r)
+	case cicdv1.TaskResolverTypeHub:
+		catalog := r.GetParam("catalog")
+		if catalog == "" {
+			catalog = defaultHubCatalog
+		}
+		spec, err = fetchTask(fmt.Sprintf(hubURL, catalog, r.GetParam("name"), r.GetParam("version")))
+	case cicdv1.TaskResolverTypeCluster:
+		spec, err = p.resolveClusterTask(job, r)
+	default:
+		err = fmt.Errorf("resolver type %s is not supported", r.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve task of job %s: %s", j.Name, err.Error())
+	}
+	return spec, nil
+}
+
+// resolveGitTask fetches a task from the repository in the IntegrationConfig's git server
+func (p *pipelineManager) resolveGitTask(job *cicdv1.IntegrationJob, r *cicdv1.JobTaskResolver) (*tektonv1beta1.TaskSpec, error) {
+	cfg := &cicdv1.IntegrationConfig{}
+	if err := p.Client.Get(context.Background(), types.NamespacedName{Name: job.Spec.ConfigRef.Name, Namespace: job.Namespace}, cfg); err != nil {
+		return nil, err
+	}
+
+	repo := r.GetParam("repository")
+	if repo == "" {
+		repo = job.Spec.Refs.Repository
+	}
+	revision := r.GetParam("revision")
+	if revision == "" {
+		revision = job.Spec.Refs.Base.Sha
+		if len(job.Spec.Refs.Pulls) > 0 {
+			revision = job.Spec.Refs.Pulls[0].Sha
+		}
+	}
+
+	gitCli, err := utils.GetGitCli(cfg.ForRepository(repo), p.Client)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := gitCli.GetFile(r.GetParam("pathInRepo"), revision)
+	if err != nil {
+		return nil, err
+	}
+	return parseTask(raw)
+}
+
+// resolveClusterTask gets a Task in the cluster
+// Only the Tasks in the IntegrationConfig's namespace can be referred, not to read the other tenants' Tasks
+func (p *pipelineManager) resolveClusterTask(job *cicdv1.IntegrationJob, r *cicdv1.JobTaskResolver) (*tektonv1beta1.TaskSpec, error) {
+	if ns := r.GetParam("namespace"); ns != "" && ns != job.Namespace {
+		return nil, fmt.Errorf("namespace %s is not allowed, only the Tasks in namespace %s can be referred", ns, job.Namespace)
+	}
+	task := &tektonv1beta1.Task{}
+	if err := p.Client.Get(context.Background(), types.NamespacedName{Name: r.GetParam("name"), Namespace: job.Namespace}, task); err != nil {
+		return nil, err
+	}
+	return &task.Spec, nil
+}
@@ -0,0 +1,184 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testTaskYaml = `apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: %s
+spec:
+  steps:
+  - name: run
+    image: busybox
+`
+
+func TestPipelineManager_resolveTask(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{Type: cicdv1.GitTypeFake, Repository: "test/repo", Token: &cicdv1.GitToken{Value: "dummy"}},
+		},
+	}
+	task := &tektonv1beta1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-task", Namespace: "default"},
+		Spec:       tektonv1beta1.TaskSpec{Steps: []tektonv1beta1.Step{{Container: corev1.Container{Name: "cluster", Image: "busybox"}}}},
+	}
+
+	gitfake.Repos = map[string]*gitfake.Repo{
+		"test/repo":  {Files: map[string]string{"2222222222:.tekton/task.yaml": fmt.Sprintf(testTaskYaml, "git-task")}},
+		"test/tasks": {Files: map[string]string{"v1.0:test.yaml": fmt.Sprintf(testTaskYaml, "other-repo-task")}},
+	}
+
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tekton/task/git-clone/0.5/yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintf(w, testTaskYaml, "git-clone")
+	}))
+	defer hub.Close()
+	hubURL = hub.URL + "/%s/task/%s/%s/yaml"
+
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: cicdv1.JobTypePreSubmit},
+			Refs: cicdv1.IntegrationJobRefs{
+				Repository: "test/repo",
+				Base:       cicdv1.IntegrationJobRefsBase{Ref: "master", Sha: "1111111111"},
+				Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: 1, Ref: "feat", Sha: "2222222222"}},
+			},
+		},
+	}
+
+	tc := map[string]struct {
+		resolver cicdv1.JobTaskResolver
+
+		errorOccurs  bool
+		errorMessage string
+		expectedStep string
+	}{
+		"git": {
+			resolver:     cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeGit, Params: []cicdv1.ParameterValue{{Name: "pathInRepo", StringVal: ".tekton/task.yaml"}}},
+			expectedStep: "run",
+		},
+		"gitOtherRepository": {
+			resolver: cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeGit, Params: []cicdv1.ParameterValue{
+				{Name: "repository", StringVal: "test/tasks"},
+				{Name: "revision", StringVal: "v1.0"},
+				{Name: "pathInRepo", StringVal: "test.yaml"},
+			}},
+			expectedStep: "run",
+		},
+		"gitNotFound": {
+			resolver:     cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeGit, Params: []cicdv1.ParameterValue{{Name: "pathInRepo", StringVal: "task.yaml"}}},
+			errorOccurs:  true,
+			errorMessage: "cannot resolve task of job test: 404 no such file (task.yaml) at 2222222222",
+		},
+		"hub": {
+			resolver: cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeHub, Params: []cicdv1.ParameterValue{
+				{Name: "name", StringVal: "git-clone"},
+				{Name: "version", StringVal: "0.5"},
+			}},
+			expectedStep: "run",
+		},
+		"hubNotFound": {
+			resolver: cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeHub, Params: []cicdv1.ParameterValue{
+				{Name: "name", StringVal: "git-clone"},
+				{Name: "version", StringVal: "0.4"},
+			}},
+			errorOccurs:  true,
+			errorMessage: "cannot resolve task of job test: error: 404, msg: ",
+		},
+		"cluster": {
+			resolver:     cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeCluster, Params: []cicdv1.ParameterValue{{Name: "name", StringVal: "cluster-task"}}},
+			expectedStep: "cluster",
+		},
+		"clusterSameNamespace": {
+			resolver: cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeCluster, Params: []cicdv1.ParameterValue{
+				{Name: "namespace", StringVal: "default"},
+				{Name: "name", StringVal: "cluster-task"},
+			}},
+			expectedStep: "cluster",
+		},
+		"clusterOtherNamespace": {
+			resolver: cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeCluster, Params: []cicdv1.ParameterValue{
+				{Name: "namespace", StringVal: "tasks"},
+				{Name: "name", StringVal: "cluster-task"},
+			}},
+			errorOccurs:  true,
+			errorMessage: "cannot resolve task of job test: namespace tasks is not allowed, only the Tasks in namespace default can be referred",
+		},
+		"clusterNotFound": {
+			resolver:     cicdv1.JobTaskResolver{Type: cicdv1.TaskResolverTypeCluster, Params: []cicdv1.ParameterValue{{Name: "name", StringVal: "other-task"}}},
+			errorOccurs:  true,
+			errorMessage: `cannot resolve task of job test: tasks.tekton.dev "other-task" not found`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			p := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ic, task).Build(), Scheme: s}
+			j := &cicdv1.Job{Container: corev1.Container{Name: "test"}, TektonTask: &cicdv1.TektonTask{TaskRef: cicdv1.JobTaskRef{Resolver: &c.resolver}}}
+
+			spec, err := p.resolveTask(ij, j)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, spec.Steps, 1)
+				require.Equal(t, c.expectedStep, spec.Steps[0].Name)
+			}
+		})
+	}
+}
+
+func TestGenerateTektonTaskRunTask_bundle(t *testing.T) {
+	j := &cicdv1.Job{
+		Container: corev1.Container{Name: "test"},
+		TektonTask: &cicdv1.TektonTask{
+			TaskRef: cicdv1.JobTaskRef{Bundle: &cicdv1.JobTaskBundle{Image: "docker.io/tektoncd/catalog:v0.1", Name: "golang-test"}},
+		},
+	}
+	task := &tektonv1beta1.PipelineTask{Name: "test"}
+	_, err := generateTektonTaskRunTask(j, task)
+	require.NoError(t, err)
+	require.Equal(t, &tektonv1beta1.TaskRef{Name: "golang-test", Bundle: "docker.io/tektoncd/catalog:v0.1"}, task.TaskRef)
+	require.Nil(t, task.TaskSpec)
+}
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
@@ -32,28 +33,28 @@ const (
 	catalogURL = "https://raw.githubusercontent.com/tektoncd/catalog/master/task/%s/%s/%s.yaml"
 )
 
+// taskClient fetches the Tasks from the catalog and the hub
+var taskClient = &http.Client{Timeout: 10 * time.Second}
+
 func generateTektonTaskRunTask(j *cicdv1.Job, target *tektonv1beta1.PipelineTask) ([]tektonv1beta1.TaskResourceBinding, error) {
 	taskSpec := j.TektonTask
 
 	// Ref local or catalog
 	if taskSpec.TaskRef.Local != nil {
 		target.TaskRef = taskSpec.TaskRef.Local
+	} else if taskSpec.TaskRef.Bundle != nil {
+		target.TaskRef = &tektonv1beta1.TaskRef{Name: taskSpec.TaskRef.Bundle.Name, Bundle: taskSpec.TaskRef.Bundle.Image}
+	} else if taskSpec.TaskRef.Resolver != nil {
+		// The task spec is resolved by the pipeline manager, as it needs to access the cluster and the git server
 	} else if taskSpec.TaskRef.Catalog != "" {
-		catTok := strings.Split(taskSpec.TaskRef.Catalog, "@")
-		if len(catTok) != 2 {
-			return nil, fmt.Errorf("catalog reference should be in form of [name]@[version]")
-		}
-		catName := catTok[0]
-		catVer := catTok[1]
-
 		// Fetch from catalog
-		spec, err := fetchCatalog(catName, catVer)
+		spec, err := fetchCatalogRef(taskSpec.TaskRef.Catalog)
 		if err != nil {
 			return nil, err
 		}
 		target.TaskSpec = &tektonv1beta1.EmbeddedTask{TaskSpec: *spec}
 	} else {
-		return nil, fmt.Errorf("both local task and catalog are nil")
+		return nil, fmt.Errorf("local task, catalog, bundle and resolver are all nil")
 	}
 
 	target.Params = append(target.Params, cicdv1.ConvertToTektonParams(taskSpec.Params)...)
@@ -103,9 +104,23 @@ func globalResourceName(jobName, resName string) string {
 	return fmt.Sprintf("%s-%s", jobName, resName)
 }
 
+// fetchCatalogRef fetches a task from the catalog, referred in form of [name]@[version]
+func fetchCatalogRef(ref string) (*tektonv1beta1.TaskSpec, error) {
+	catTok := strings.Split(ref, "@")
+	if len(catTok) != 2 {
+		return nil, fmt.Errorf("catalog reference should be in form of [name]@[version]")
+	}
+	return fetchCatalog(catTok[0], catTok[1])
+}
+
 func fetchCatalog(catName, catVer string) (*tektonv1beta1.TaskSpec, error) {
+	return fetchTask(fmt.Sprintf(catalogURL, catName, catVer, catName))
+}
+
+// fetchTask fetches a Task yaml from the url and returns its spec
+func fetchTask(url string) (*tektonv1beta1.TaskSpec, error) {
 	// Fetch
-	resp, err := http.Get(fmt.Sprintf(catalogURL, catName, catVer, catName))
+	resp, err := taskClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +135,14 @@ func fetchCatalog(catName, catVer string) (*tektonv1beta1.TaskSpec, error) {
 		return nil, fmt.Errorf("error: %d, msg: %s", resp.StatusCode, string(respBody))
 	}
 
+	return parseTask(respBody)
+}
+
+// parseTask parses a Task yaml and returns its spec
+func parseTask(raw []byte) (*tektonv1beta1.TaskSpec, error) {
 	// Unmarshal
 	var body interface{}
-	if err := yaml.Unmarshal(respBody, &body); err != nil {
+	if err := yaml.Unmarshal(raw, &body); err != nil {
 		return nil, err
 	}
 	body = convert(body)
@@ -0,0 +1,92 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"context"
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// renderTemplates returns the IntegrationJob's jobs, with the jobs referring to the job templates rendered
+// The IntegrationJob itself is not modified
+func (p *pipelineManager) renderTemplates(job *cicdv1.IntegrationJob) (cicdv1.Jobs, error) {
+	jobs := make(cicdv1.Jobs, len(job.Spec.Jobs))
+	hasTemplate := false
+	for i := range job.Spec.Jobs {
+		j := &job.Spec.Jobs[i]
+		if j.Template == nil {
+			jobs[i] = *j
+			continue
+		}
+
+		spec, err := p.getTemplateSpec(j.Template, job.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get template %s of job %s: %s", j.Template.Name, j.Name, err.Error())
+		}
+		rendered, err := spec.Render(j)
+		if err != nil {
+			return nil, fmt.Errorf("cannot render template %s of job %s: %s", j.Template.Name, j.Name, err.Error())
+		}
+		jobs[i] = *rendered
+		hasTemplate = true
+	}
+
+	if hasTemplate {
+		if err := p.validateConfigFileServiceAccounts(job, jobs); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// validateConfigFileServiceAccounts checks the ServiceAccounts of the rendered jobs, if they're loaded from the config
+// file. The config file's jobs are validated when they're loaded, but the templates they refer to can set
+// serviceAccountName as well
+func (p *pipelineManager) validateConfigFileServiceAccounts(job *cicdv1.IntegrationJob, jobs cicdv1.Jobs) error {
+	// Periodic jobs are not loaded from the config file
+	if job.Spec.ConfigRef.Type != cicdv1.JobTypePreSubmit && job.Spec.ConfigRef.Type != cicdv1.JobTypePostSubmit {
+		return nil
+	}
+	cfg := &cicdv1.IntegrationConfig{}
+	if err := p.Client.Get(context.Background(), types.NamespacedName{Name: job.Spec.ConfigRef.Name, Namespace: job.Namespace}, cfg); err != nil {
+		return fmt.Errorf("cannot get IntegrationConfig %s: %s", job.Spec.ConfigRef.Name, err.Error())
+	}
+	if cfg.Spec.Jobs.ConfigFile == nil {
+		return nil
+	}
+	return cfg.Spec.Jobs.ConfigFile.ValidateServiceAccounts(jobs)
+}
+
+func (p *pipelineManager) getTemplateSpec(ref *cicdv1.JobTemplateRef, namespace string) (*cicdv1.IntegrationJobTemplateSpec, error) {
+	switch ref.GetKind() {
+	case cicdv1.JobTemplateKindCluster:
+		t := &cicdv1.ClusterIntegrationJobTemplate{}
+		if err := p.Client.Get(context.Background(), types.NamespacedName{Name: ref.Name}, t); err != nil {
+			return nil, err
+		}
+		return &t.Spec, nil
+	default:
+		t := &cicdv1.IntegrationJobTemplate{}
+		if err := p.Client.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, t); err != nil {
+			return nil, err
+		}
+		return &t.Spec, nil
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPipelineManager_renderTemplates(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	templateSpec := cicdv1.IntegrationJobTemplateSpec{
+		Params: []cicdv1.ParameterDefine{{Name: "TARGET", DefaultStr: "./..."}},
+		Job: cicdv1.Job{
+			Container: corev1.Container{Image: "golang:1.17"},
+			Script:    "go test $(template.TARGET)",
+		},
+	}
+	namespaced := &cicdv1.IntegrationJobTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-test", Namespace: "default"},
+		Spec:       templateSpec,
+	}
+	cluster := &cicdv1.ClusterIntegrationJobTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-test"},
+		Spec:       templateSpec,
+	}
+	cluster.Spec.Job.Image = "golang:1.16"
+	privileged := &cicdv1.IntegrationJobTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+		Spec:       cicdv1.IntegrationJobTemplateSpec{Job: cicdv1.Job{Container: corev1.Container{Image: "kubectl"}, ServiceAccountName: "deployer"}},
+	}
+	config := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{Jobs: cicdv1.IntegrationConfigJobs{
+			ConfigFile: &cicdv1.JobsConfigFile{AllowedServiceAccounts: []string{"builder"}},
+		}},
+	}
+
+	tc := map[string]struct {
+		jobs    cicdv1.Jobs
+		jobType cicdv1.JobType
+
+		errorOccurs  bool
+		errorMessage string
+		expectedJobs cicdv1.Jobs
+	}{
+		"noTemplate": {
+			jobs:         cicdv1.Jobs{{Container: corev1.Container{Name: "lint", Image: "golangci"}}},
+			expectedJobs: cicdv1.Jobs{{Container: corev1.Container{Name: "lint", Image: "golangci"}}},
+		},
+		"namespaced": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test"}, Template: &cicdv1.JobTemplateRef{Name: "go-test", Params: []cicdv1.ParameterValue{{Name: "TARGET", StringVal: "./pkg/..."}}}},
+			},
+			expectedJobs: cicdv1.Jobs{{Container: corev1.Container{Name: "test", Image: "golang:1.17"}, Script: "go test ./pkg/..."}},
+		},
+		"cluster": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test"}, Template: &cicdv1.JobTemplateRef{Name: "go-test", Kind: cicdv1.JobTemplateKindCluster}},
+			},
+			expectedJobs: cicdv1.Jobs{{Container: corev1.Container{Name: "test", Image: "golang:1.16"}, Script: "go test ./..."}},
+		},
+		"notFound": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test"}, Template: &cicdv1.JobTemplateRef{Name: "go-build"}},
+			},
+			errorOccurs:  true,
+			errorMessage: `cannot get template go-build of job test: integrationjobtemplates.cicd.tmax.io "go-build" not found`,
+		},
+		"renderError": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test"}, Template: &cicdv1.JobTemplateRef{Name: "go-test", Params: []cicdv1.ParameterValue{{Name: "RACE", StringVal: "true"}}}},
+			},
+			errorOccurs:  true,
+			errorMessage: "cannot render template go-test of job test: parameter RACE is not defined in the template",
+		},
+		"configFileServiceAccount": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "deploy"}, Script: "kubectl apply -f .", Template: &cicdv1.JobTemplateRef{Name: "deploy"}},
+			},
+			jobType:      cicdv1.JobTypePreSubmit,
+			errorOccurs:  true,
+			errorMessage: "job deploy cannot run with service account deployer, which is not in configFile.allowedServiceAccounts",
+		},
+		"configFileServiceAccountPeriodic": {
+			jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "deploy"}, Template: &cicdv1.JobTemplateRef{Name: "deploy"}},
+			},
+			jobType:      cicdv1.JobTypePeriodic,
+			expectedJobs: cicdv1.Jobs{{Container: corev1.Container{Name: "deploy", Image: "kubectl"}, ServiceAccountName: "deployer"}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			pm := &pipelineManager{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(namespaced, cluster, privileged, config).Build(), Scheme: s}
+			job := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+				Spec:       cicdv1.IntegrationJobSpec{Jobs: c.jobs, ConfigRef: cicdv1.IntegrationJobConfigRef{Name: "test-ic", Type: c.jobType}},
+			}
+
+			jobs, err := pm.renderTemplates(job)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expectedJobs, jobs)
+				require.Equal(t, c.jobs, job.Spec.Jobs)
+			}
+		})
+	}
+}
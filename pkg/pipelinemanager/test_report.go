@@ -0,0 +1,253 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestReportResultName is a name of the task result, where the test-report step writes the summary of the test reports
+const TestReportResultName = "cicd-test-report"
+
+const (
+	deferredScriptPath   = "/tekton/home/.cicd-script"
+	deferredExitCodePath = "/tekton/home/.cicd-exit-code"
+)
+
+// deferredScriptHead and deferredScriptTail wrap the job's script, to save its exit code instead of failing the step
+// The test-report and coverage steps run after the script even if it fails, and the last one exits with the saved exit
+// code
+// Scripts without a shebang are run by sh -xe, as Tekton does
+const deferredScriptHead = `#!/bin/sh
+cat > ` + deferredScriptPath + ` <<'CICD_SCRIPT_EOF'
+`
+const deferredScriptTail = `
+CICD_SCRIPT_EOF
+chmod +x ` + deferredScriptPath + `
+IFS= read -r CICD_SCRIPT_FIRST_LINE < ` + deferredScriptPath + ` || true
+case "$CICD_SCRIPT_FIRST_LINE" in
+  '#!'*) ` + deferredScriptPath + ` ;;
+  *) sh -xe ` + deferredScriptPath + ` ;;
+esac
+echo $? > ` + deferredExitCodePath + `
+`
+
+// testReportScript summarizes the JUnit XML reports (expanded by the shell) into the task result, as lines of
+// 'total <n>', 'passed <n>', 'failed <n>', 'skipped <n>' and 'failedTest <name>' (for at most 20 failed tests)
+// The reports are split by '<', so that each record of awk starts with a tag name
+const testReportScript = `#!/bin/sh
+touch "$(results.` + TestReportResultName + `.path)"
+
+CICD_TEST_REPORTS=""
+for CICD_TEST_REPORT in $TEST_REPORT_PATHS; do
+  if [ -f "$CICD_TEST_REPORT" ]; then
+    CICD_TEST_REPORTS="$CICD_TEST_REPORTS $CICD_TEST_REPORT"
+  else
+    echo "test report $CICD_TEST_REPORT does not exist"
+  fi
+done
+
+if [ "$CICD_TEST_REPORTS" != "" ]; then
+  awk '
+function unescape(s) {
+  gsub(/&lt;/, "<", s)
+  gsub(/&gt;/, ">", s)
+  gsub(/&quot;/, "\"", s)
+  gsub(/&amp;/, "\\&", s)
+  return s
+}
+function attr(s, n) {
+  if (match(s, "[ \t\r\n]" n "=\"[^\"]*\"")) {
+    return unescape(substr(s, RSTART + length(n) + 3, RLENGTH - length(n) - 4))
+  }
+  return ""
+}
+function finish() {
+  total++
+  if (state == "failed") {
+    failed++
+    if (failed <= 20) {
+      names[failed] = substr(name, 1, 100)
+    }
+  } else if (state == "skipped") {
+    skipped++
+  } else {
+    passed++
+  }
+  open = 0
+}
+BEGIN { RS = "<" }
+/^testcase[ \t\r\n\/>]/ {
+  if (open) {
+    finish()
+  }
+  name = attr($0, "name")
+  class = attr($0, "classname")
+  if (class != "") {
+    name = class "." name
+  }
+  state = "passed"
+  open = 1
+  end = index($0, ">")
+  if (end > 1 && substr($0, end - 1, 1) == "/") {
+    finish()
+  }
+  next
+}
+/^(failure|error)[ \t\r\n\/>]/ {
+  if (open) {
+    state = "failed"
+  }
+  next
+}
+/^skipped[ \t\r\n\/>]/ {
+  if (open && state == "passed") {
+    state = "skipped"
+  }
+  next
+}
+/^\/testcase[ \t\r\n>]/ {
+  if (open) {
+    finish()
+  }
+  next
+}
+END {
+  if (open) {
+    finish()
+  }
+  print "total " total + 0
+  print "passed " passed + 0
+  print "failed " failed + 0
+  print "skipped " skipped + 0
+  for (i = 1; i <= failed && i <= 20; i++) {
+    print "failedTest " names[i]
+  }
+}
+' $CICD_TEST_REPORTS > "$(results.` + TestReportResultName + `.path)" || echo "cannot summarize the test reports"
+  cat "$(results.` + TestReportResultName + `.path)"
+fi
+`
+
+// deferredExitScript is appended to the last step parsing the reports, to exit with the saved exit code of the script
+const deferredExitScript = `
+if [ -f ` + deferredExitCodePath + ` ]; then
+  exit "$(cat ` + deferredExitCodePath + `)"
+fi
+`
+
+// deferScriptFailure wraps the step's script, so that the following steps run even if the script fails
+func deferScriptFailure(step *tektonv1beta1.Step) {
+	step.Script = deferredScriptHead + step.Script + deferredScriptTail
+}
+
+// summarizeTestReports generates a step summarizing the job's test reports
+func summarizeTestReports(j *cicdv1.Job) tektonv1beta1.Step {
+	step := tektonv1beta1.Step{}
+	step.Name = "test-report"
+	step.Image = configs.TestReportImage
+	step.WorkingDir = DefaultWorkingDir
+	if j.WorkingDir != "" {
+		step.WorkingDir = j.WorkingDir
+	}
+	step.Script = testReportScript
+	step.Env = []corev1.EnvVar{
+		{Name: "TEST_REPORT_PATHS", Value: strings.Join(j.TestReports.Paths, " ")},
+	}
+	return step
+}
+
+// getTestReport parses the summary of the test reports from the task results
+func getTestReport(results []tektonv1beta1.TaskRunResult) *cicdv1.JobTestReport {
+	for _, r := range results {
+		if r.Name != TestReportResultName {
+			continue
+		}
+		report := &cicdv1.JobTestReport{}
+		found := false
+		for _, line := range strings.Split(r.Value, "\n") {
+			tokens := strings.SplitN(strings.TrimSpace(line), " ", 2)
+			if len(tokens) != 2 {
+				continue
+			}
+			if tokens[0] == "failedTest" {
+				report.FailedTests = append(report.FailedTests, tokens[1])
+				continue
+			}
+			n, err := strconv.Atoi(tokens[1])
+			if err != nil {
+				continue
+			}
+			switch tokens[0] {
+			case "total":
+				report.Total = n
+				found = true
+			case "passed":
+				report.Passed = n
+			case "failed":
+				report.Failed = n
+			case "skipped":
+				report.Skipped = n
+			}
+		}
+		if found {
+			return report
+		}
+	}
+	return nil
+}
+
+// commentTestReport posts the summary of the job's test reports to the pull request
+func (p *pipelineManager) commentTestReport(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob, cfg *cicdv1.IntegrationConfig) error {
+	if jobStatus.TestReport == nil || len(ij.Spec.Refs.Pulls) != 1 || cfg.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(cfg, p.Client)
+	if err != nil {
+		return err
+	}
+	return gitCli.RegisterComment(git.IssueTypePullRequest, ij.Spec.Refs.Pulls[0].ID, generateTestReportComment(jobStatus, ij))
+}
+
+func generateTestReportComment(jobStatus *cicdv1.JobStatus, ij *cicdv1.IntegrationJob) string {
+	report := jobStatus.TestReport
+	comment := fmt.Sprintf("[TEST RESULTS]\n\nTest results of job `%s` (IntegrationJob `%s`, commit %s)\n\n", jobStatus.Name, ij.Name, ij.Spec.Refs.Pulls[0].Sha)
+	comment += "| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n"
+	comment += fmt.Sprintf("| %d | %d | %d | %d |\n", report.Total, report.Passed, report.Failed, report.Skipped)
+	if len(report.FailedTests) == 0 {
+		return comment
+	}
+
+	comment += "\nFailed tests\n"
+	for _, t := range report.FailedTests {
+		comment += fmt.Sprintf("- `%s`\n", t)
+	}
+	if more := report.Failed - len(report.FailedTests); more > 0 {
+		comment += fmt.Sprintf("- ... and %d more\n", more)
+	}
+	return comment
+}
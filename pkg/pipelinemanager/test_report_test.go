@@ -0,0 +1,141 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelinemanager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateSteps_testReports(t *testing.T) {
+	tc := map[string]struct {
+		job *cicdv1.Job
+
+		expectedSteps    []string
+		expectedDeferred bool
+	}{
+		"script": {
+			job: &cicdv1.Job{
+				Container:   corev1.Container{Name: "test", Image: "golang:1.17"},
+				Script:      "go test ./... 2>&1 | go-junit-report > report.xml",
+				TestReports: &cicdv1.JobTestReports{Paths: []string{"report.xml"}},
+			},
+			expectedSteps:    []string{"git-clone", "step-0", "test-report"},
+			expectedDeferred: true,
+		},
+		"command": {
+			job: &cicdv1.Job{
+				Container:   corev1.Container{Name: "test", Image: "golang:1.17", Command: []string{"make", "test"}},
+				TestReports: &cicdv1.JobTestReports{Paths: []string{"report.xml"}},
+			},
+			expectedSteps: []string{"git-clone", "step-0", "test-report"},
+		},
+		"artifacts": {
+			job: &cicdv1.Job{
+				Container:   corev1.Container{Name: "test", Image: "golang:1.17"},
+				Script:      "make test",
+				TestReports: &cicdv1.JobTestReports{Paths: []string{"report.xml"}},
+				Artifacts:   &cicdv1.JobArtifacts{Paths: []string{"report.xml"}},
+			},
+			expectedSteps:    []string{"git-clone", "step-0", "test-report", "upload-artifacts"},
+			expectedDeferred: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			steps, err := generateSteps(&cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}, c.job)
+			require.NoError(t, err)
+
+			var names []string
+			for _, s := range steps {
+				names = append(names, s.Name)
+			}
+			require.Equal(t, c.expectedSteps, names)
+			require.Equal(t, c.expectedDeferred, strings.Contains(steps[1].Script, deferredExitCodePath))
+			if c.expectedDeferred {
+				require.Contains(t, steps[1].Script, "\n"+c.job.Script+"\nCICD_SCRIPT_EOF\n")
+			}
+			require.Equal(t, []corev1.EnvVar{{Name: "TEST_REPORT_PATHS", Value: "report.xml"}}, steps[2].Env)
+		})
+	}
+}
+
+func TestGetTestReport(t *testing.T) {
+	tc := map[string]struct {
+		results []tektonv1beta1.TaskRunResult
+
+		expectedReport *cicdv1.JobTestReport
+	}{
+		"noResult": {
+			results: []tektonv1beta1.TaskRunResult{{Name: "other", Value: "total 1"}},
+		},
+		"noReport": {
+			results: []tektonv1beta1.TaskRunResult{{Name: TestReportResultName, Value: ""}},
+		},
+		"report": {
+			results: []tektonv1beta1.TaskRunResult{{
+				Name:  TestReportResultName,
+				Value: "total 5\npassed 2\nfailed 2\nskipped 1\nfailedTest pkg/a.TestFail\nfailedTest pkg/b.TestFail with space\n",
+			}},
+			expectedReport: &cicdv1.JobTestReport{Total: 5, Passed: 2, Failed: 2, Skipped: 1, FailedTests: []string{"pkg/a.TestFail", "pkg/b.TestFail with space"}},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedReport, getTestReport(c.results))
+		})
+	}
+}
+
+func TestGenerateTestReportComment(t *testing.T) {
+	ij := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij"},
+		Spec:       cicdv1.IntegrationJobSpec{Refs: cicdv1.IntegrationJobRefs{Pulls: []cicdv1.IntegrationJobRefsPull{{ID: 1, Sha: "sha"}}}},
+	}
+
+	tc := map[string]struct {
+		report *cicdv1.JobTestReport
+
+		expectedComment string
+	}{
+		"passed": {
+			report: &cicdv1.JobTestReport{Total: 3, Passed: 3},
+			expectedComment: "[TEST RESULTS]\n\nTest results of job `test` (IntegrationJob `test-ij`, commit sha)\n\n" +
+				"| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n| 3 | 3 | 0 | 0 |\n",
+		},
+		"failed": {
+			report: &cicdv1.JobTestReport{Total: 25, Passed: 2, Failed: 22, Skipped: 1, FailedTests: []string{"TestA", "TestB"}},
+			expectedComment: "[TEST RESULTS]\n\nTest results of job `test` (IntegrationJob `test-ij`, commit sha)\n\n" +
+				"| Total | Passed | Failed | Skipped |\n|---|---|---|---|\n| 25 | 2 | 22 | 1 |\n" +
+				"\nFailed tests\n- `TestA`\n- `TestB`\n- ... and 20 more\n",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedComment, generateTestReportComment(&cicdv1.JobStatus{Name: "test", TestReport: c.report}, ij))
+		})
+	}
+}
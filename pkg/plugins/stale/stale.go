@@ -0,0 +1,238 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package stale
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	staleCommentPrefix = "[STALE ALERT]"
+
+	// staleHeadMarker is a hidden marker in the warning comment, recording the head commit of the pull request
+	staleHeadMarker = "<!-- stale-head: %s -->"
+
+	day = 24 * time.Hour
+)
+
+var staleHeadMarkerRegex = regexp.MustCompile(`<!-- stale-head: ([0-9a-zA-Z]+) -->`)
+
+var log = logf.Log.WithName("stale-plugin")
+
+// Stale plugin labels the pull requests inactive for a while as stale, and closes them if they stay inactive.
+// Unlike the other plugins, it's not triggered by webhooks but synchronizes the open pull requests periodically, for
+// each IntegrationConfig with spec.stale
+type Stale struct {
+	client client.Client
+
+	lastSync time.Time
+}
+
+// New creates a new stale plugin
+func New(c client.Client) *Stale {
+	return &Stale{
+		client:   c,
+		lastSync: time.Now(),
+	}
+}
+
+// Start synchronizes the pull requests periodically
+func (s *Stale) Start() {
+	log.Info("Starting stale loop")
+	for {
+		<-time.After(time.Until(s.lastSync.Add(time.Duration(configs.PluginStaleSyncPeriod) * time.Minute)))
+		s.sync()
+	}
+}
+
+func (s *Stale) sync() {
+	s.lastSync = time.Now()
+
+	ics := &cicdv1.IntegrationConfigList{}
+	if err := s.client.List(context.Background(), ics); err != nil {
+		log.Error(err, "")
+		return
+	}
+
+	doneRepos := map[string]struct{}{}
+	for _, ic := range ics.Items {
+		// Skip if token is nil or stale lifecycle is not configured
+		if ic.Spec.Git.Token == nil || ic.Spec.Stale == nil {
+			continue
+		}
+		for _, repo := range ic.Spec.Git.GetRepositories() {
+			repoIC := ic.ForRepository(repo)
+			key := fmt.Sprintf("%s/%s", repoIC.Spec.Git.GetAPIUrl(), repo)
+			if _, done := doneRepos[key]; done {
+				continue
+			}
+			doneRepos[key] = struct{}{}
+
+			if err := s.syncRepository(repoIC, s.lastSync); err != nil {
+				log.Error(err, fmt.Sprintf("cannot sync stale pull requests of %s/%s's %s", ic.Namespace, ic.Name, repo))
+			}
+		}
+	}
+}
+
+// syncRepository labels/closes the stale pull requests of the IntegrationConfig's repository
+func (s *Stale) syncRepository(ic *cicdv1.IntegrationConfig, now time.Time) error {
+	gitCli, err := utils.GetGitCli(ic, s.client)
+	if err != nil {
+		return err
+	}
+
+	prs, err := gitCli.ListPullRequests(true)
+	if err != nil {
+		return err
+	}
+
+	for i := range prs {
+		if err := syncPullRequest(gitCli, ic.Spec.Stale, &prs[i], now); err != nil {
+			log.Error(err, fmt.Sprintf("cannot sync %s/%s's PR#%d", ic.Namespace, ic.Name, prs[i].ID))
+		}
+	}
+	return nil
+}
+
+// syncPullRequest labels the pull request as stale if it's inactive for DaysUntilStale days. If it's already labeled,
+// the label is removed if it's active again, or the pull request is closed if it stays inactive for DaysUntilClose days
+func syncPullRequest(gitCli git.Client, cfg *cicdv1.Stale, pr *git.PullRequest, now time.Time) error {
+	if pr.UpdatedAt == nil || hasAnyLabel(pr.Labels, cfg.ExemptLabels) {
+		return nil
+	}
+	label := cfg.GetLabel()
+	inactive := now.Sub(pr.UpdatedAt.Time)
+
+	// Label it as stale
+	if !hasAnyLabel(pr.Labels, []string{label}) {
+		if inactive < time.Duration(cfg.DaysUntilStale)*day {
+			return nil
+		}
+		log.Info(fmt.Sprintf("Labeling PR#%d as stale", pr.ID))
+		if err := gitCli.SetLabel(git.IssueTypePullRequest, pr.ID, label); err != nil {
+			return err
+		}
+		return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateStaleComment(cfg, pr.Head.Sha))
+	}
+
+	// Check if it's active again since the warning. It's not checked if it's labeled by someone else
+	comments, err := gitCli.ListComments(pr.ID)
+	if err != nil {
+		return err
+	}
+	if w := findWarning(comments); w != nil {
+		if isActiveSince(w, pr, comments) {
+			log.Info(fmt.Sprintf("Removing stale label from PR#%d", pr.ID))
+			return gitCli.DeleteLabel(git.IssueTypePullRequest, pr.ID, label)
+		}
+		// The activities of the bots after the warning are not counted
+		inactive = now.Sub(w.createdAt)
+	}
+
+	// Close it
+	if cfg.DaysUntilClose == 0 || inactive < time.Duration(cfg.DaysUntilClose)*day {
+		return nil
+	}
+	log.Info(fmt.Sprintf("Closing stale PR#%d", pr.ID))
+	if err := gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, generateCloseComment(cfg)); err != nil {
+		return err
+	}
+	return gitCli.UpdateIssueState(git.IssueTypePullRequest, pr.ID, git.PullRequestStateClosed)
+}
+
+// warning is the latest warning comment of the plugin on a pull request
+type warning struct {
+	createdAt time.Time
+
+	// bot is the user who commented the warning, i.e., the user of the plugin's token
+	bot string
+
+	// headSha is the head commit of the pull request when it's warned
+	headSha string
+}
+
+// findWarning returns the latest warning comment among the comments. Nil is returned if there is none
+func findWarning(comments []git.IssueComment) *warning {
+	var w *warning
+	for _, c := range comments {
+		if c.Comment.CreatedAt == nil || !strings.HasPrefix(c.Comment.Body, staleCommentPrefix) {
+			continue
+		}
+		if w != nil && !c.Comment.CreatedAt.Time.After(w.createdAt) {
+			continue
+		}
+		w = &warning{createdAt: c.Comment.CreatedAt.Time, bot: c.Author.Name}
+		if sub := staleHeadMarkerRegex.FindStringSubmatch(c.Comment.Body); len(sub) == 2 {
+			w.headSha = sub[1]
+		}
+	}
+	return w
+}
+
+// isActiveSince decides if the pull request is active since the warning, i.e., a new commit is pushed or someone
+// commented on it. The activities of the bots (e.g., labels, commit statuses and their comments) are not counted, even
+// though they update the pull request
+func isActiveSince(w *warning, pr *git.PullRequest, comments []git.IssueComment) bool {
+	if w.headSha != "" && w.headSha != pr.Head.Sha {
+		return true
+	}
+	for _, c := range comments {
+		if c.Comment.CreatedAt == nil || !c.Comment.CreatedAt.Time.After(w.createdAt) {
+			continue
+		}
+		if c.Author.Name == w.bot || strings.HasSuffix(c.Author.Name, "[bot]") {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func hasAnyLabel(labels []git.IssueLabel, targets []string) bool {
+	for _, l := range labels {
+		for _, t := range targets {
+			if l.Name == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func generateStaleComment(cfg *cicdv1.Stale, headSha string) string {
+	msg := fmt.Sprintf("%s\n\nThis pull request has been inactive for %d days, so it's labeled as `%s`.", staleCommentPrefix, cfg.DaysUntilStale, cfg.GetLabel())
+	if cfg.DaysUntilClose > 0 {
+		msg += fmt.Sprintf(" It will be closed if it stays inactive for %d more days.", cfg.DaysUntilClose)
+	}
+	return msg + "\n\nAny activity on it, e.g., a new commit or a comment, removes the label.\n" + fmt.Sprintf(staleHeadMarker, headSha)
+}
+
+func generateCloseComment(cfg *cicdv1.Stale) string {
+	return fmt.Sprintf("%s\n\nThis pull request is closed, as it has been inactive for %d days since it's labeled as `%s`.\n\nComment `/reopen` to reopen it.", staleCommentPrefix, cfg.DaysUntilClose, cfg.GetLabel())
+}
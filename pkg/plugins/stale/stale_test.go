@@ -0,0 +1,196 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package stale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "tmax-cloud/cicd-operator"
+	testPRID = 1
+)
+
+func TestStale_syncRepository(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "dummy"},
+			},
+		},
+	}
+
+	stale := New(fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build())
+
+	now := time.Now()
+	daysAgo := func(days float64) *metav1.Time {
+		return &metav1.Time{Time: now.Add(-time.Duration(days * float64(day)))}
+	}
+	warning := func(createdAt *metav1.Time, others ...git.IssueComment) []git.IssueComment {
+		return append([]git.IssueComment{
+			{Comment: git.Comment{Body: "/lgtm", CreatedAt: daysAgo(20)}, Author: git.User{Name: "reviewer"}},
+			{Comment: git.Comment{Body: staleCommentPrefix + "\n\nwarning\n<!-- stale-head: sha1 -->", CreatedAt: createdAt}, Author: git.User{Name: "cicd-bot"}},
+		}, others...)
+	}
+
+	tc := map[string]struct {
+		cfg       cicdv1.Stale
+		labels    []git.IssueLabel
+		updatedAt *metav1.Time
+		headSha   string
+		comments  []git.IssueComment
+
+		expectedLabels   []git.IssueLabel
+		expectedState    git.PullRequestState
+		expectedComments []string
+	}{
+		"active": {
+			cfg:           cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			updatedAt:     daysAgo(29),
+			expectedState: git.PullRequestStateOpen,
+		},
+		"stale": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			updatedAt:      daysAgo(31),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/stale"}},
+			expectedState:  git.PullRequestStateOpen,
+			expectedComments: []string{"[STALE ALERT]\n\nThis pull request has been inactive for 30 days, so it's labeled as `lifecycle/stale`. " +
+				"It will be closed if it stays inactive for 7 more days.\n\nAny activity on it, e.g., a new commit or a comment, removes the label.\n<!-- stale-head: sha1 -->"},
+		},
+		"staleNeverClosed": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, Label: "stale"},
+			updatedAt:      daysAgo(31),
+			expectedLabels: []git.IssueLabel{{Name: "stale"}},
+			expectedState:  git.PullRequestStateOpen,
+			expectedComments: []string{"[STALE ALERT]\n\nThis pull request has been inactive for 30 days, so it's labeled as `stale`.\n\n" +
+				"Any activity on it, e.g., a new commit or a comment, removes the label.\n<!-- stale-head: sha1 -->"},
+		},
+		"exempt": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7, ExemptLabels: []string{"lifecycle/frozen"}},
+			labels:         []git.IssueLabel{{Name: "lifecycle/frozen"}},
+			updatedAt:      daysAgo(31),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/frozen"}},
+			expectedState:  git.PullRequestStateOpen,
+		},
+		"inGracePeriod": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:         []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt:      daysAgo(6),
+			comments:       warning(daysAgo(6)),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/stale"}},
+			expectedState:  git.PullRequestStateOpen,
+		},
+		"activeAgain": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:         []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt:      daysAgo(1),
+			comments:       warning(daysAgo(8), git.IssueComment{Comment: git.Comment{Body: "ping", CreatedAt: daysAgo(1)}, Author: git.User{Name: "author"}}),
+			expectedLabels: []git.IssueLabel{},
+			expectedState:  git.PullRequestStateOpen,
+		},
+		"newCommit": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:         []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt:      daysAgo(1),
+			headSha:        "sha2",
+			comments:       warning(daysAgo(8)),
+			expectedLabels: []git.IssueLabel{},
+			expectedState:  git.PullRequestStateOpen,
+		},
+		"botActivityOnly": {
+			cfg:       cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:    []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt: daysAgo(1),
+			comments: warning(daysAgo(8),
+				git.IssueComment{Comment: git.Comment{Body: "test results", CreatedAt: daysAgo(2)}, Author: git.User{Name: "cicd-bot"}},
+				git.IssueComment{Comment: git.Comment{Body: "coverage report", CreatedAt: daysAgo(1)}, Author: git.User{Name: "codecov[bot]"}},
+			),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/stale"}},
+			expectedState:  git.PullRequestStateClosed,
+			expectedComments: []string{"[STALE ALERT]\n\nThis pull request is closed, as it has been inactive for 7 days since it's labeled as `lifecycle/stale`.\n\n" +
+				"Comment `/reopen` to reopen it."},
+		},
+		"close": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:         []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt:      daysAgo(8),
+			comments:       warning(daysAgo(8)),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/stale"}},
+			expectedState:  git.PullRequestStateClosed,
+			expectedComments: []string{"[STALE ALERT]\n\nThis pull request is closed, as it has been inactive for 7 days since it's labeled as `lifecycle/stale`.\n\n" +
+				"Comment `/reopen` to reopen it."},
+		},
+		"closeLabeledManually": {
+			cfg:            cicdv1.Stale{DaysUntilStale: 30, DaysUntilClose: 7},
+			labels:         []git.IssueLabel{{Name: "lifecycle/stale"}},
+			updatedAt:      daysAgo(8),
+			expectedLabels: []git.IssueLabel{{Name: "lifecycle/stale"}},
+			expectedState:  git.PullRequestStateClosed,
+			expectedComments: []string{"[STALE ALERT]\n\nThis pull request is closed, as it has been inactive for 7 days since it's labeled as `lifecycle/stale`.\n\n" +
+				"Comment `/reopen` to reopen it."},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			headSha := c.headSha
+			if headSha == "" {
+				headSha = "sha1"
+			}
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {
+					PullRequests: map[int]*git.PullRequest{
+						testPRID: {ID: testPRID, State: git.PullRequestStateOpen, Labels: c.labels, UpdatedAt: c.updatedAt, Head: git.Head{Sha: headSha}},
+					},
+					Comments: map[int][]git.IssueComment{testPRID: c.comments},
+				},
+			}
+			ic.Spec.Stale = &c.cfg
+
+			require.NoError(t, stale.syncRepository(ic, now))
+
+			pr := gitfake.Repos[testRepo].PullRequests[testPRID]
+			require.Equal(t, c.expectedLabels, pr.Labels)
+			require.Equal(t, c.expectedState, pr.State)
+
+			var comments []string
+			for _, comment := range gitfake.Repos[testRepo].Comments[testPRID][len(c.comments):] {
+				comments = append(comments, comment.Comment.Body)
+			}
+			require.Equal(t, c.expectedComments, comments)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package welcome
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("welcome-plugin")
+
+// messageData is a data for the welcome message template
+type messageData struct {
+	// Author is a name of the pull request's author
+	Author string
+	// Repository is a name of the repository
+	Repository string
+	// PullRequest is an id of the pull request
+	PullRequest int
+}
+
+// Welcome plugin posts a welcome comment to the pull request, if it's the first contribution of its author
+// It's disabled unless the welcome message is configured
+type Welcome struct {
+	Client client.Client
+}
+
+// Name returns a name of welcome plugin
+func (w *Welcome) Name() string {
+	return "welcome"
+}
+
+// Handle handles a pull request event and posts a welcome comment to the pull request
+func (w *Welcome) Handle(wh *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	// Filter only PullRequest event's open action
+	pr := wh.PullRequest
+	if wh.EventType != git.EventTypePullRequest || pr == nil || pr.Action != git.PullRequestActionOpen || configs.PluginWelcomeMessage == "" {
+		return nil
+	}
+	// Comments cannot be posted without the token
+	if config.Spec.Git.Token == nil {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, w.Client)
+	if err != nil {
+		return err
+	}
+
+	// The pull request itself is also counted. It may not be counted yet, due to the delay of the git server's indexing
+	count, err := gitCli.CountUserPullRequests(pr.Author)
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		return nil
+	}
+
+	message, err := generateWelcomeComment(messageData{
+		Author:      pr.Author.Name,
+		Repository:  config.Spec.Git.Repository,
+		PullRequest: pr.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Welcoming %s to %s/%s's PR#%d", pr.Author.Name, config.Namespace, config.Name, pr.ID))
+	return gitCli.RegisterComment(git.IssueTypePullRequest, pr.ID, message)
+}
+
+func generateWelcomeComment(data messageData) (string, error) {
+	tmpl, err := template.New("").Parse(configs.PluginWelcomeMessage)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,167 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package welcome
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "tmax-cloud/cicd-operator"
+	testPRID = 3
+)
+
+func TestWelcome_Handle(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+				Token:      &cicdv1.GitToken{Value: "test-tkn"},
+			},
+		},
+	}
+
+	noTokenIC := ic.DeepCopy()
+	noTokenIC.Name = "no-token"
+	noTokenIC.Spec.Git.Token = nil
+
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic, noTokenIC).Build()
+	welcome := Welcome{Client: fakeCli}
+	testMessage := "Welcome @{{.Author}}! Please read the contributing guide."
+
+	tc := map[string]struct {
+		eventType git.EventType
+		action    git.PullRequestAction
+		author    string
+		message   string
+		noToken   bool
+
+		errorOccurs      bool
+		errorMessage     string
+		expectedComments []string
+	}{
+		"firstContribution": {
+			eventType:        git.EventTypePullRequest,
+			action:           git.PullRequestActionOpen,
+			author:           "new-user",
+			message:          "Welcome @{{.Author}} to {{.Repository}}#{{.PullRequest}}!",
+			expectedComments: []string{"Welcome @new-user to tmax-cloud/cicd-operator#3!"},
+		},
+		"noToken": {
+			eventType: git.EventTypePullRequest,
+			action:    git.PullRequestActionOpen,
+			author:    "new-user",
+			message:   testMessage,
+			noToken:   true,
+		},
+		"notFirstContribution": {
+			eventType: git.EventTypePullRequest,
+			action:    git.PullRequestActionOpen,
+			author:    "test-user",
+			message:   testMessage,
+		},
+		"disabled": {
+			eventType: git.EventTypePullRequest,
+			action:    git.PullRequestActionOpen,
+			author:    "new-user",
+			message:   "",
+		},
+		"otherAction": {
+			eventType: git.EventTypePullRequest,
+			action:    git.PullRequestActionReOpen,
+			author:    "new-user",
+			message:   testMessage,
+		},
+		"otherEvent": {
+			eventType: git.EventTypePush,
+			action:    git.PullRequestActionOpen,
+			author:    "new-user",
+			message:   testMessage,
+		},
+		"malformedMessage": {
+			eventType:    git.EventTypePullRequest,
+			action:       git.PullRequestActionOpen,
+			author:       "new-user",
+			message:      "Welcome @{{.Author}",
+			errorOccurs:  true,
+			errorMessage: "template: :1: bad character U+007D '}'",
+		},
+	}
+
+	defer func() {
+		configs.PluginWelcomeMessage = ""
+	}()
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.PluginWelcomeMessage = c.message
+			author := git.User{ID: 111, Name: c.author}
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {
+					PullRequests: map[int]*git.PullRequest{
+						1:        {ID: 1, Author: git.User{ID: 32, Name: "test-user"}},
+						testPRID: {ID: testPRID, Author: author},
+					},
+					Comments: map[int][]git.IssueComment{},
+				},
+			}
+
+			wh := &git.Webhook{
+				EventType: c.eventType,
+				PullRequest: &git.PullRequest{
+					ID:     testPRID,
+					Author: author,
+					Action: c.action,
+				},
+			}
+			config := ic
+			if c.noToken {
+				config = noTokenIC
+			}
+			err := welcome.Handle(wh, config)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			var comments []string
+			for _, comment := range gitfake.Repos[testRepo].Comments[testPRID] {
+				comments = append(comments, comment.Comment.Body)
+			}
+			require.Equal(t, c.expectedComments, comments)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package wip
+
+import (
+	"fmt"
+	"regexp"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/internal/utils"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// titleRegex matches the titles marked as work-in-progress, e.g., 'WIP: title', '[WIP] title', 'Draft: title'
+var titleRegex = regexp.MustCompile(`(?i)^\W?(WIP|Draft)\b`)
+
+var log = logf.Log.WithName("wip-plugin")
+
+// WIP plugin labels the work-in-progress pull requests, so they are not merged by the blocker.
+// A pull request is work-in-progress if it's a draft or its title starts with a WIP marker
+type WIP struct {
+	Client client.Client
+}
+
+// Name returns a name of wip plugin
+func (w *WIP) Name() string {
+	return "wip"
+}
+
+// Handle handles a pull request event and sets/removes the work-in-progress label to/from the pull request
+func (w *WIP) Handle(wh *git.Webhook, config *cicdv1.IntegrationConfig) error {
+	pr := wh.PullRequest
+	if wh.EventType != git.EventTypePullRequest || pr == nil || configs.MergeWIPLabel == "" {
+		return nil
+	}
+	switch pr.Action {
+	case git.PullRequestActionOpen, git.PullRequestActionReOpen, git.PullRequestActionEdited, git.PullRequestActionReady, git.PullRequestActionDraft:
+	default:
+		return nil
+	}
+
+	isWIP := isWorkInProgress(pr)
+	if isWIP == hasLabel(pr.Labels, configs.MergeWIPLabel) {
+		return nil
+	}
+
+	gitCli, err := utils.GetGitCli(config, w.Client)
+	if err != nil {
+		return err
+	}
+
+	if isWIP {
+		log.Info(fmt.Sprintf("Setting wip label %s to %s/%s's PR#%d", configs.MergeWIPLabel, config.Namespace, config.Name, pr.ID))
+		return gitCli.SetLabel(git.IssueTypePullRequest, pr.ID, configs.MergeWIPLabel)
+	}
+
+	log.Info(fmt.Sprintf("Removing wip label %s from %s/%s's PR#%d", configs.MergeWIPLabel, config.Namespace, config.Name, pr.ID))
+	return gitCli.DeleteLabel(git.IssueTypePullRequest, pr.ID, configs.MergeWIPLabel)
+}
+
+func isWorkInProgress(pr *git.PullRequest) bool {
+	return pr.Draft || titleRegex.MatchString(pr.Title)
+}
+
+func hasLabel(labels []git.IssueLabel, label string) bool {
+	for _, l := range labels {
+		if l.Name == label {
+			return true
+		}
+	}
+	return false
+}
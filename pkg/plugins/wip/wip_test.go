@@ -0,0 +1,161 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package wip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/git"
+	gitfake "github.com/tmax-cloud/cicd-operator/pkg/git/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRepo = "tmax-cloud/cicd-operator"
+	testPRID = 1
+	testWIP  = "do-not-merge/work-in-progress"
+)
+
+func TestWIP_Handle(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Git: cicdv1.GitConfig{
+				Type:       cicdv1.GitTypeFake,
+				Repository: testRepo,
+			},
+		},
+	}
+
+	fakeCli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic).Build()
+	wip := WIP{Client: fakeCli}
+
+	configs.MergeWIPLabel = testWIP
+
+	tc := map[string]struct {
+		eventType git.EventType
+		action    git.PullRequestAction
+		title     string
+		draft     bool
+		labels    []git.IssueLabel
+
+		expectedLabels []git.IssueLabel
+	}{
+		"wipTitle": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionOpen,
+			title:          "WIP: test",
+			expectedLabels: []git.IssueLabel{{Name: testWIP}},
+		},
+		"wipBracketTitle": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionEdited,
+			title:          "[wip] test",
+			labels:         []git.IssueLabel{{Name: "approved"}},
+			expectedLabels: []git.IssueLabel{{Name: "approved"}, {Name: testWIP}},
+		},
+		"draftTitle": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionEdited,
+			title:          "Draft: test",
+			expectedLabels: []git.IssueLabel{{Name: testWIP}},
+		},
+		"draft": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionDraft,
+			title:          "test",
+			draft:          true,
+			expectedLabels: []git.IssueLabel{{Name: testWIP}},
+		},
+		"alreadyLabeled": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionReOpen,
+			title:          "WIP test",
+			labels:         []git.IssueLabel{{Name: testWIP}},
+			expectedLabels: []git.IssueLabel{{Name: testWIP}},
+		},
+		"titleChanged": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionEdited,
+			title:          "test",
+			labels:         []git.IssueLabel{{Name: "approved"}, {Name: testWIP}},
+			expectedLabels: []git.IssueLabel{{Name: "approved"}},
+		},
+		"readyForReview": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionReady,
+			title:          "test",
+			labels:         []git.IssueLabel{{Name: testWIP}},
+			expectedLabels: []git.IssueLabel{},
+		},
+		"notWIP": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionOpen,
+			title:          "Wipe out the cache",
+			expectedLabels: nil,
+		},
+		"otherAction": {
+			eventType:      git.EventTypePullRequest,
+			action:         git.PullRequestActionSynchronize,
+			title:          "WIP: test",
+			expectedLabels: nil,
+		},
+		"otherEvent": {
+			eventType:      git.EventTypePush,
+			action:         git.PullRequestActionOpen,
+			title:          "WIP: test",
+			expectedLabels: nil,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			gitfake.Repos = map[string]*gitfake.Repo{
+				testRepo: {
+					PullRequests: map[int]*git.PullRequest{
+						testPRID: {ID: testPRID, Title: c.title, Draft: c.draft, Labels: c.labels},
+					},
+				},
+			}
+
+			wh := &git.Webhook{
+				EventType: c.eventType,
+				PullRequest: &git.PullRequest{
+					ID:     testPRID,
+					Title:  c.title,
+					Draft:  c.draft,
+					Action: c.action,
+					Labels: c.labels,
+				},
+			}
+			require.NoError(t, wip.Handle(wh, ic))
+			require.Equal(t, c.expectedLabels, gitfake.Repos[testRepo].PullRequests[testPRID].Labels)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remotecluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cachedClient is a client of a remote cluster, built from the kubeconfig secret of the resource version
+type cachedClient struct {
+	resourceVersion string
+	client          client.Client
+	pods            typedcorev1.PodsGetter
+}
+
+var (
+	clients     = map[types.NamespacedName]*cachedClient{}
+	clientsLock sync.Mutex
+
+	// newClient builds a client of a remote cluster. It's replaced in tests
+	newClient = func(config *rest.Config, scheme *runtime.Scheme) (client.Client, error) {
+		return client.New(config, client.Options{Scheme: scheme})
+	}
+
+	// newPodsGetter builds a pods getter of a remote cluster, which can stream the pods' logs. It's replaced in tests
+	newPodsGetter = func(config *rest.Config) (typedcorev1.PodsGetter, error) {
+		return typedcorev1.NewForConfig(config)
+	}
+)
+
+// ClientFor returns a client of the cluster the IntegrationJob's PipelineRun runs in, i.e., the remote cluster if the
+// IntegrationJob specifies it, or the local cluster otherwise
+// Clients of the remote clusters are cached until their kubeconfig secrets are updated
+func ClientFor(local client.Client, job *cicdv1.IntegrationJob) (client.Client, error) {
+	if job.Spec.RemoteCluster == nil {
+		return local, nil
+	}
+	c, err := remoteClientFor(local, job)
+	if err != nil {
+		return nil, err
+	}
+	return c.client, nil
+}
+
+// PodsGetterFor returns a pods getter of the cluster the IntegrationJob's pods run in, like ClientFor does
+// It's for the pods' logs, which cannot be read by the controller-runtime clients
+func PodsGetterFor(local client.Client, localPods typedcorev1.PodsGetter, job *cicdv1.IntegrationJob) (typedcorev1.PodsGetter, error) {
+	if job.Spec.RemoteCluster == nil {
+		return localPods, nil
+	}
+	c, err := remoteClientFor(local, job)
+	if err != nil {
+		return nil, err
+	}
+	return c.pods, nil
+}
+
+// remoteClientFor returns the cached clients of the IntegrationJob's remote cluster, or builds them if the kubeconfig
+// secret is updated
+func remoteClientFor(local client.Client, job *cicdv1.IntegrationJob) (*cachedClient, error) {
+	key := types.NamespacedName{Name: job.Spec.RemoteCluster.KubeconfigSecret, Namespace: job.Namespace}
+	secret := &corev1.Secret{}
+	if err := local.Get(context.Background(), key, secret); err != nil {
+		return nil, fmt.Errorf("cannot get the kubeconfig of the remote cluster: %s", err.Error())
+	}
+
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+
+	if c, exist := clients[key]; exist && c.resourceVersion == secret.ResourceVersion {
+		return c, nil
+	}
+
+	kubeconfig, exist := secret.Data[cicdv1.RemoteClusterKubeconfigKey]
+	if !exist {
+		return nil, fmt.Errorf("secret %s does not have the %s key", key.Name, cicdv1.RemoteClusterKubeconfigKey)
+	}
+	config, err := restConfigFromKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the kubeconfig of the remote cluster: %s", err.Error())
+	}
+	cli, err := newClient(config, local.Scheme())
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the remote cluster: %s", err.Error())
+	}
+	pods, err := newPodsGetter(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the remote cluster: %s", err.Error())
+	}
+	c := &cachedClient{resourceVersion: secret.ResourceVersion, client: cli, pods: pods}
+	clients[key] = c
+	return c, nil
+}
+
+// restConfigFromKubeconfig builds a rest config from the kubeconfig. As the kubeconfig is given by the tenants, only
+// the inline credentials are allowed. Exec plugins, auth providers and file paths are rejected, so that nothing is
+// executed or read in the operator's pod (e.g., its own service account token)
+func restConfigFromKubeconfig(kubeconfig []byte) (*rest.Config, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	for name, c := range cfg.Clusters {
+		if c.CertificateAuthority != "" {
+			return nil, fmt.Errorf("cluster %s cannot use certificate-authority file, use certificate-authority-data instead", name)
+		}
+	}
+	for name, u := range cfg.AuthInfos {
+		switch {
+		case u.Exec != nil:
+			return nil, fmt.Errorf("user %s cannot use exec plugin", name)
+		case u.AuthProvider != nil:
+			return nil, fmt.Errorf("user %s cannot use auth-provider", name)
+		case u.TokenFile != "":
+			return nil, fmt.Errorf("user %s cannot use tokenFile, use token instead", name)
+		case u.ClientCertificate != "":
+			return nil, fmt.Errorf("user %s cannot use client-certificate file, use client-certificate-data instead", name)
+		case u.ClientKey != "":
+			return nil, fmt.Errorf("user %s cannot use client-key file, use client-key-data instead", name)
+		}
+	}
+	return clientcmd.NewDefaultClientConfig(*cfg, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
@@ -0,0 +1,245 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remotecluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+    token: dummy
+`
+
+func TestClientFor(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	tc := map[string]struct {
+		remoteCluster *cicdv1.RemoteCluster
+		secret        *corev1.Secret
+
+		expectedRemote   bool
+		expectedErrorMsg string
+	}{
+		"local": {},
+		"remote": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data:       map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(testKubeconfig)},
+			},
+			expectedRemote: true,
+		},
+		"noSecret": {
+			remoteCluster:    &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			expectedErrorMsg: "cannot get the kubeconfig of the remote cluster: secrets \"remote-kubeconfig\" not found",
+		},
+		"noKey": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data:       map[string][]byte{"config": []byte(testKubeconfig)},
+			},
+			expectedErrorMsg: "secret remote-kubeconfig does not have the kubeconfig key",
+		},
+		"invalidKubeconfig": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data:       map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte("{invalid")},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster",
+		},
+		"tokenFile": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data: map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(strings.Replace(testKubeconfig, "token: dummy",
+					"tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", 1))},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster: user remote cannot use tokenFile, use token instead",
+		},
+		"exec": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data: map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(strings.Replace(testKubeconfig, "token: dummy",
+					"exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: /bin/sh", 1))},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster: user remote cannot use exec plugin",
+		},
+		"authProvider": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data: map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(strings.Replace(testKubeconfig, "token: dummy",
+					"auth-provider:\n      name: gcp", 1))},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster: user remote cannot use auth-provider",
+		},
+		"clientKeyFile": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data: map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(strings.Replace(testKubeconfig, "token: dummy",
+					"client-key: /etc/ssl/key.pem", 1))},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster: user remote cannot use client-key file, use client-key-data instead",
+		},
+		"caFile": {
+			remoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+				Data: map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(strings.Replace(testKubeconfig, "server: https://remote.example.com:6443",
+					"server: https://remote.example.com:6443\n    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", 1))},
+			},
+			expectedErrorMsg: "cannot parse the kubeconfig of the remote cluster: cluster remote cannot use certificate-authority file, use certificate-authority-data instead",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			clients = map[types.NamespacedName]*cachedClient{}
+			remote := fake.NewClientBuilder().WithScheme(s).Build()
+			newClient = func(config *rest.Config, _ *runtime.Scheme) (client.Client, error) {
+				require.Equal(t, "https://remote.example.com:6443", config.Host)
+				return remote, nil
+			}
+
+			builder := fake.NewClientBuilder().WithScheme(s)
+			if c.secret != nil {
+				builder.WithObjects(c.secret)
+			}
+			local := builder.Build()
+
+			job := &cicdv1.IntegrationJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+				Spec:       cicdv1.IntegrationJobSpec{RemoteCluster: c.remoteCluster},
+			}
+			cli, err := ClientFor(local, job)
+			if c.expectedErrorMsg != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			if c.expectedRemote {
+				require.Equal(t, remote, cli)
+			} else {
+				require.Equal(t, local, cli)
+			}
+		})
+	}
+}
+
+func TestClientFor_cache(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	clients = map[types.NamespacedName]*cachedClient{}
+	built := 0
+	newClient = func(_ *rest.Config, _ *runtime.Scheme) (client.Client, error) {
+		built++
+		return fake.NewClientBuilder().WithScheme(s).Build(), nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+		Data:       map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(testKubeconfig)},
+	}
+	local := fake.NewClientBuilder().WithScheme(s).WithObjects(secret).Build()
+	job := &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"},
+		Spec:       cicdv1.IntegrationJobSpec{RemoteCluster: &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"}},
+	}
+
+	first, err := ClientFor(local, job)
+	require.NoError(t, err)
+	second, err := ClientFor(local, job)
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, 1, built)
+
+	// Updating the secret rebuilds the client
+	secret.Data["dummy"] = []byte("updated")
+	require.NoError(t, local.Update(context.Background(), secret))
+	third, err := ClientFor(local, job)
+	require.NoError(t, err)
+	require.NotSame(t, first, third)
+	require.Equal(t, 2, built)
+}
+
+func TestPodsGetterFor(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	clients = map[types.NamespacedName]*cachedClient{}
+	newClient = func(_ *rest.Config, _ *runtime.Scheme) (client.Client, error) {
+		return fake.NewClientBuilder().WithScheme(s).Build(), nil
+	}
+	remotePods := k8sfake.NewSimpleClientset().CoreV1()
+	newPodsGetter = func(config *rest.Config) (typedcorev1.PodsGetter, error) {
+		require.Equal(t, "https://remote.example.com:6443", config.Host)
+		return remotePods, nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+		Data:       map[string][]byte{cicdv1.RemoteClusterKubeconfigKey: []byte(testKubeconfig)},
+	}
+	local := fake.NewClientBuilder().WithScheme(s).WithObjects(secret).Build()
+	localPods := k8sfake.NewSimpleClientset().CoreV1()
+
+	job := &cicdv1.IntegrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test-ij", Namespace: "default"}}
+	pods, err := PodsGetterFor(local, localPods, job)
+	require.NoError(t, err)
+	require.Equal(t, localPods, pods)
+
+	job.Spec.RemoteCluster = &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"}
+	pods, err = PodsGetterFor(local, localPods, job)
+	require.NoError(t, err)
+	require.Equal(t, remotePods, pods)
+}
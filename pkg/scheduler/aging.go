@@ -0,0 +1,57 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+)
+
+// syncAging raises the effective priorities of the pending jobs by one for every priorityAgingInterval they have
+// waited, so that the low-priority jobs are not starved by the high-priority ones
+func (s *scheduler) syncAging() {
+	interval := time.Duration(configs.PriorityAgingInterval) * time.Minute
+	now := time.Now()
+	changed := map[*cicdv1.IntegrationJob]int32{}
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		j, ok := item.(*pool.JobNode)
+		if !ok {
+			return
+		}
+		age := getAge(j.CreationTimestamp.Time, now, interval)
+		if age != j.Age {
+			changed[j.IntegrationJob] = age
+		}
+	})
+
+	// The pending jobs are re-sorted after being iterated
+	for job, age := range changed {
+		s.jobPool.SetAge(job, age)
+	}
+}
+
+// getAge returns the number of the intervals passed since the creation. It returns 0 if the interval is not positive
+func getAge(created, now time.Time, interval time.Duration) int32 {
+	if interval <= 0 || now.Before(created) {
+		return 0
+	}
+	return int32(now.Sub(created) / interval)
+}
@@ -0,0 +1,86 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetAge(t *testing.T) {
+	now := time.Now()
+	tc := map[string]struct {
+		created  time.Time
+		interval time.Duration
+
+		expected int32
+	}{
+		"disabled": {created: now.Add(-time.Hour), interval: 0, expected: 0},
+		"notYet":   {created: now.Add(-9 * time.Minute), interval: 10 * time.Minute, expected: 0},
+		"aged":     {created: now.Add(-25 * time.Minute), interval: 10 * time.Minute, expected: 2},
+		"future":   {created: now.Add(time.Minute), interval: 10 * time.Minute, expected: 0},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, getAge(c.created, now, c.interval))
+		})
+	}
+}
+
+func TestScheduler_run_aging(t *testing.T) {
+	configs.MaxPipelineRun = 1
+	configs.PriorityAgingInterval = 10
+	defer func() { configs.PriorityAgingInterval = 0 }()
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	now := time.Now()
+	high := schedulerTestJob("high", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+	high.Spec.Priority = 2
+	starving := schedulerTestJob("starving", "test-ic", "1", now.Add(-35*time.Minute), cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(high, starving).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, priorityCompare)
+	for _, j := range []*cicdv1.IntegrationJob{high, starving} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "starving", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "high", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "starving", Namespace: "default"}, ij))
+	require.Equal(t, &cicdv1.IntegrationJobQueueStatus{Position: 1, Length: 2, Priority: 3}, ij.Status.Queue)
+}
@@ -0,0 +1,288 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes;resourcequotas,verbs=get;list;watch
+
+// capacityResources are the resources considered for the cluster capacity
+var capacityResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// clusterCapacity is the free resources of the cluster, i.e., the allocatable resources of the schedulable nodes minus
+// the requests of the pods, and the free resources of the namespaces' ResourceQuotas
+type clusterCapacity struct {
+	k8sClient client.Client
+
+	// total is the sum of the free resources of the nodes
+	total corev1.ResourceList
+	// nodes are the free resources of each node
+	nodes []corev1.ResourceList
+	// allocatable are the allocatable resources of each node which is not cordoned, ready or not
+	allocatable []corev1.ResourceList
+	// namespaces are the free resources of the ResourceQuotas of each namespace (nil if there is no ResourceQuota)
+	namespaces map[string]corev1.ResourceList
+}
+
+// newClusterCapacity calculates the free resources of the nodes
+// The pods are listed from the API server, only the ones not terminated, not to cache all the pods of the cluster
+func newClusterCapacity(c client.Client, apiReader client.Reader) (*clusterCapacity, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(context.Background(), nodes); err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	notTerminated := fields.AndSelectors(
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)
+	if err := apiReader.List(context.Background(), pods, client.MatchingFieldsSelector{Selector: notTerminated}); err != nil {
+		return nil, err
+	}
+
+	requests := map[string]corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if requests[pod.Spec.NodeName] == nil {
+			requests[pod.Spec.NodeName] = corev1.ResourceList{}
+		}
+		addResources(requests[pod.Spec.NodeName], podRequests(&pod))
+	}
+
+	capacity := &clusterCapacity{k8sClient: c, total: corev1.ResourceList{}, namespaces: map[string]corev1.ResourceList{}}
+	for _, node := range nodes.Items {
+		if !node.Spec.Unschedulable {
+			capacity.allocatable = append(capacity.allocatable, subtractResources(node.Status.Allocatable, nil))
+		}
+		if !isNodeSchedulable(&node) {
+			continue
+		}
+		free := subtractResources(node.Status.Allocatable, requests[node.Name])
+		capacity.nodes = append(capacity.nodes, free)
+		addResources(capacity.total, free)
+	}
+	// Pods not bound to any node yet also take the capacity
+	capacity.total = subtractResources(capacity.total, requests[""])
+	return capacity, nil
+}
+
+// check returns an error if the job does not fit in the free resources of the cluster or its namespace
+func (c *clusterCapacity) check(job *cicdv1.IntegrationJob) error {
+	requests := capacityRequests(resourceRequests(job))
+	if err := checkCapacity(c.total, requests, "cluster"); err != nil {
+		return err
+	}
+
+	// Each job should fit in a node
+	for _, j := range job.Spec.Jobs {
+		if !c.fitsInNode(jobRequests(j)) {
+			return fmt.Errorf("job %s does not fit in any node", j.Name)
+		}
+	}
+
+	quota, err := c.namespaceCapacity(job.Namespace)
+	if err != nil {
+		return err
+	}
+	if quota == nil {
+		return nil
+	}
+	return checkCapacity(quota, requests, fmt.Sprintf("namespace %s", job.Namespace))
+}
+
+// reserve takes the job's requests from the free resources, as its pods are not created yet
+// The requests of a gang scheduled job are also taken from the nodes its jobs are placed in
+func (c *clusterCapacity) reserve(job *cicdv1.IntegrationJob) {
+	requests := capacityRequests(resourceRequests(job))
+	c.total = subtractResources(c.total, requests)
+	if quota := c.namespaces[job.Namespace]; quota != nil {
+		c.namespaces[job.Namespace] = subtractResources(quota, requests)
+	}
+	if job.Spec.GangScheduling {
+		if nodes := c.pack(job); nodes != nil {
+			c.nodes = nodes
+		}
+	}
+}
+
+// checkFeasible returns an error if any job of the IntegrationJob does not fit in any node even if the node is empty,
+// i.e., the IntegrationJob would never be admitted. It is not checked if there is no node, e.g., the nodes are being
+// provisioned
+func (c *clusterCapacity) checkFeasible(job *cicdv1.IntegrationJob) error {
+	if len(c.allocatable) == 0 {
+		return nil
+	}
+	for _, j := range job.Spec.Jobs {
+		requests := jobRequests(j)
+		fits := false
+		for _, node := range c.allocatable {
+			if checkCapacity(node, requests, "") == nil {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			return fmt.Errorf("job %s requests more resources than any node can allocate", j.Name)
+		}
+	}
+	return nil
+}
+
+func (c *clusterCapacity) fitsInNode(requests corev1.ResourceList) bool {
+	for _, node := range c.nodes {
+		if checkCapacity(node, requests, "") == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceCapacity calculates the free resources of the namespace's ResourceQuotas, i.e., the smallest ones of hard
+// minus used
+func (c *clusterCapacity) namespaceCapacity(namespace string) (corev1.ResourceList, error) {
+	if free, exist := c.namespaces[namespace]; exist {
+		return free, nil
+	}
+	quotas := &corev1.ResourceQuotaList{}
+	if err := c.k8sClient.List(context.Background(), quotas, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var free corev1.ResourceList
+	for _, quota := range quotas.Items {
+		for name, hard := range quota.Status.Hard {
+			resourceName := corev1.ResourceName(strings.TrimPrefix(string(name), "requests."))
+			if !isCapacityResource(resourceName) {
+				continue
+			}
+			used := quota.Status.Used[name]
+			left := hard.DeepCopy()
+			left.Sub(used)
+			if free == nil {
+				free = corev1.ResourceList{}
+			}
+			if cur, exist := free[resourceName]; !exist || left.Cmp(cur) < 0 {
+				free[resourceName] = left
+			}
+		}
+	}
+	c.namespaces[namespace] = free
+	return free, nil
+}
+
+// checkCapacity returns an error if the requests exceed the free resources
+func checkCapacity(free, requests corev1.ResourceList, scope string) error {
+	var names []string
+	for name := range free {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		available := free[corev1.ResourceName(name)]
+		request, exist := requests[corev1.ResourceName(name)]
+		if !exist || request.Cmp(available) <= 0 {
+			continue
+		}
+		if available.Sign() < 0 {
+			available = resource.Quantity{}
+		}
+		return fmt.Errorf("%s request %s exceeds the available %s of the %s", name, request.String(), available.String(), scope)
+	}
+	return nil
+}
+
+// podRequests sums up the resource requests of the pod's containers. Init containers are run one by one, so the
+// largest one is considered, if it is larger than the containers
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResources(requests, c.Resources.Requests)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if cur, exist := requests[name]; !exist || q.Cmp(cur) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+// jobRequests returns the resource requests of a job, considered for the cluster capacity
+func jobRequests(j cicdv1.Job) corev1.ResourceList {
+	return capacityRequests(resourceRequests(&cicdv1.IntegrationJob{Spec: cicdv1.IntegrationJobSpec{Jobs: cicdv1.Jobs{j}}}))
+}
+
+func capacityRequests(requests corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for name, q := range requests {
+		if isCapacityResource(name) {
+			result[name] = q
+		}
+	}
+	return result
+}
+
+func isCapacityResource(name corev1.ResourceName) bool {
+	for _, n := range capacityResources {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func subtractResources(total, resources corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for name, q := range total {
+		if !isCapacityResource(name) {
+			continue
+		}
+		left := q.DeepCopy()
+		if r, exist := resources[name]; exist {
+			left.Sub(r)
+		}
+		result[name] = left
+	}
+	return result
+}
+
+func isNodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterCapacity_check(t *testing.T) {
+	tc := map[string]struct {
+		objects []client.Object
+		cpu     string
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"fits": {
+			objects: []client.Object{capacityTestNode("node-1", "4", true), capacityTestPod("pod-1", "node-1", "1", corev1.PodRunning)},
+			cpu:     "3",
+		},
+		"exceedsCluster": {
+			objects:      []client.Object{capacityTestNode("node-1", "4", true), capacityTestPod("pod-1", "node-1", "2", corev1.PodRunning)},
+			cpu:          "3",
+			errorOccurs:  true,
+			errorMessage: "cpu request 3 exceeds the available 2 of the cluster",
+		},
+		"completedPod": {
+			objects: []client.Object{capacityTestNode("node-1", "4", true), capacityTestPod("pod-1", "node-1", "2", corev1.PodSucceeded)},
+			cpu:     "3",
+		},
+		"unboundPod": {
+			objects:      []client.Object{capacityTestNode("node-1", "4", true), capacityTestPod("pod-1", "", "2", corev1.PodPending)},
+			cpu:          "3",
+			errorOccurs:  true,
+			errorMessage: "cpu request 3 exceeds the available 2 of the cluster",
+		},
+		"notReadyNode": {
+			objects:      []client.Object{capacityTestNode("node-1", "4", true), capacityTestNode("node-2", "4", false)},
+			cpu:          "5",
+			errorOccurs:  true,
+			errorMessage: "cpu request 5 exceeds the available 4 of the cluster",
+		},
+		"notInNode": {
+			objects:      []client.Object{capacityTestNode("node-1", "2", true), capacityTestNode("node-2", "2", true)},
+			cpu:          "3",
+			errorOccurs:  true,
+			errorMessage: "job test does not fit in any node",
+		},
+		"exceedsResourceQuota": {
+			objects: []client.Object{capacityTestNode("node-1", "4", true), &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+					Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+				},
+			}},
+			cpu:          "3",
+			errorOccurs:  true,
+			errorMessage: "cpu request 3 exceeds the available 2 of the namespace default",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(c.objects...).Build()
+			capacity, err := newClusterCapacity(cli, cli)
+			require.NoError(t, err)
+
+			err = capacity.check(schedulerTestJob("test", "test-ic", c.cpu, time.Now(), cicdv1.IntegrationJobStatePending))
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClusterCapacity_checkFeasible(t *testing.T) {
+	tc := map[string]struct {
+		objects []client.Object
+		cpu     string
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"fitsInEmptyNode": {
+			objects: []client.Object{capacityTestNode("node-1", "2", true), capacityTestNode("node-2", "4", true), capacityTestPod("pod-1", "node-2", "4", corev1.PodRunning)},
+			cpu:     "3",
+		},
+		"fitsInNotReadyNode": {
+			objects: []client.Object{capacityTestNode("node-1", "2", true), capacityTestNode("node-2", "4", false)},
+			cpu:     "3",
+		},
+		"neverFits": {
+			objects:      []client.Object{capacityTestNode("node-1", "2", true), capacityTestNode("node-2", "2", true)},
+			cpu:          "3",
+			errorOccurs:  true,
+			errorMessage: "job test requests more resources than any node can allocate",
+		},
+		"noNode": {
+			cpu: "3",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(c.objects...).Build()
+			capacity, err := newClusterCapacity(cli, cli)
+			require.NoError(t, err)
+
+			err = capacity.checkFeasible(schedulerTestJob("test", "test-ic", c.cpu, time.Now(), cicdv1.IntegrationJobStatePending))
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPodRequests(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("1Gi")}}},
+		},
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")}}},
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")}}},
+		},
+	}}
+	requests := podRequests(pod)
+	require.Equal(t, "3", requests.Cpu().String())
+	require.Equal(t, "4Gi", requests.Memory().String())
+}
+
+func TestScheduler_run_capacity(t *testing.T) {
+	configs.MaxPipelineRun = 10
+	configs.CapacityAwareAdmission = true
+	defer func() { configs.CapacityAwareAdmission = false }()
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	now := time.Now()
+	first := schedulerTestJob("first", "test-ic", "2", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending)
+	second := schedulerTestJob("second", "test-ic", "2", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	third := schedulerTestJob("third", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+	huge := schedulerTestJob("huge", "test-ic", "8", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(capacityTestNode("node-1", "4", true), capacityTestPod("pod-1", "node-1", "1", corev1.PodRunning), first, second, third, huge).Build()
+	sch := &scheduler{k8sClient: cli, apiReader: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{first, second, third, huge} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	for name, scheduled := range map[string]bool{"first": true, "second": false, "third": true, "huge": false} {
+		err := cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &tektonv1beta1.PipelineRun{})
+		require.Equal(t, scheduled, err == nil, name)
+	}
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "second", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonWaitingForCapacity, ij.Status.Reason)
+	require.Equal(t, "waiting for capacity: cpu request 2 exceeds the available 1 of the cluster", ij.Status.Message)
+
+	// Never fits in any node
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "huge", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonUnschedulable, ij.Status.Reason)
+	require.Equal(t, "job test requests more resources than any node can allocate", ij.Status.Message)
+}
+
+func capacityTestNode(name, cpu string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("8Gi")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func capacityTestPod(name, node, cpu string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{Name: "test", Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
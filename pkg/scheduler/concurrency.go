@@ -0,0 +1,57 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+// checkConcurrency returns an error if the job exceeds the concurrency limit, in addition to the running jobs
+func checkConcurrency(concurrency *cicdv1.Concurrency, running []*cicdv1.IntegrationJob, job *cicdv1.IntegrationJob) error {
+	if concurrency == nil {
+		return nil
+	}
+	if concurrency.Max > 0 && len(running) >= concurrency.Max {
+		return fmt.Errorf("%d IntegrationJobs are running, while the limit is %d", len(running), concurrency.Max)
+	}
+	if concurrency.Group == "" {
+		return nil
+	}
+
+	key := job.GetGroupKey(concurrency.Group)
+	cnt := 0
+	for _, r := range running {
+		if r.GetGroupKey(concurrency.Group) == key {
+			cnt++
+		}
+	}
+	if cnt >= concurrency.GetMaxPerGroup() {
+		return fmt.Errorf("%d IntegrationJobs of %s are running, while the limit is %d", cnt, key, concurrency.GetMaxPerGroup())
+	}
+	return nil
+}
+
+// checkNamespaceConcurrency returns an error if the running jobs of the namespace have reached its limit. Zero limit
+// means no limit
+func checkNamespaceConcurrency(limit, running int, namespace string) error {
+	if limit > 0 && running >= limit {
+		return fmt.Errorf("%d IntegrationJobs are running in namespace %s, while the limit is %d", running, namespace, limit)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+func TestCheckConcurrency(t *testing.T) {
+	pull := func(id int, ref string) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{Spec: cicdv1.IntegrationJobSpec{Refs: cicdv1.IntegrationJobRefs{
+			Repository: "test/repo",
+			Base:       cicdv1.IntegrationJobRefsBase{Ref: "master"},
+			Pulls:      []cicdv1.IntegrationJobRefsPull{{ID: id, Ref: cicdv1.GitRef(ref)}},
+		}}}
+	}
+	push := func(ref string) *cicdv1.IntegrationJob {
+		return &cicdv1.IntegrationJob{Spec: cicdv1.IntegrationJobSpec{Refs: cicdv1.IntegrationJobRefs{
+			Repository: "test/repo",
+			Base:       cicdv1.IntegrationJobRefsBase{Ref: cicdv1.GitRef(ref)},
+		}}}
+	}
+
+	tc := map[string]struct {
+		concurrency *cicdv1.Concurrency
+		running     []*cicdv1.IntegrationJob
+		job         *cicdv1.IntegrationJob
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"noLimit": {
+			running: []*cicdv1.IntegrationJob{pull(1, "feat"), pull(1, "feat")},
+			job:     pull(1, "feat"),
+		},
+		"max": {
+			concurrency: &cicdv1.Concurrency{Max: 2},
+			running:     []*cicdv1.IntegrationJob{pull(1, "feat"), push("refs/heads/master")},
+			job:         pull(2, "fix"),
+
+			errorOccurs:  true,
+			errorMessage: "2 IntegrationJobs are running, while the limit is 2",
+		},
+		"underMax": {
+			concurrency: &cicdv1.Concurrency{Max: 3},
+			running:     []*cicdv1.IntegrationJob{pull(1, "feat"), push("refs/heads/master")},
+			job:         pull(2, "fix"),
+		},
+		"pullRequestGroup": {
+			concurrency: &cicdv1.Concurrency{Group: cicdv1.ConcurrencyGroupPullRequest},
+			running:     []*cicdv1.IntegrationJob{pull(1, "feat"), pull(2, "fix")},
+			job:         pull(1, "feat"),
+
+			errorOccurs:  true,
+			errorMessage: "1 IntegrationJobs of test/repo#1 are running, while the limit is 1",
+		},
+		"pullRequestGroupOther": {
+			concurrency: &cicdv1.Concurrency{Group: cicdv1.ConcurrencyGroupPullRequest},
+			running:     []*cicdv1.IntegrationJob{pull(2, "fix"), push("refs/heads/master")},
+			job:         pull(1, "feat"),
+		},
+		"branchGroup": {
+			concurrency: &cicdv1.Concurrency{Group: cicdv1.ConcurrencyGroupBranch, MaxPerGroup: 2},
+			running:     []*cicdv1.IntegrationJob{push("refs/heads/master"), push("refs/heads/master"), pull(1, "master")},
+			job:         push("refs/heads/master"),
+
+			errorOccurs:  true,
+			errorMessage: "3 IntegrationJobs of test/repo@master are running, while the limit is 2",
+		},
+		"branchGroupOther": {
+			concurrency: &cicdv1.Concurrency{Group: cicdv1.ConcurrencyGroupBranch},
+			running:     []*cicdv1.IntegrationJob{push("refs/heads/master")},
+			job:         push("refs/heads/release"),
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := checkConcurrency(c.concurrency, c.running, c.job)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckNamespaceConcurrency(t *testing.T) {
+	tc := map[string]struct {
+		limit   int
+		running int
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"noLimit": {
+			limit:   0,
+			running: 10,
+		},
+		"underLimit": {
+			limit:   2,
+			running: 1,
+		},
+		"limited": {
+			limit:   2,
+			running: 2,
+
+			errorOccurs:  true,
+			errorMessage: "2 IntegrationJobs are running in namespace default, while the limit is 2",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := checkNamespaceConcurrency(c.limit, c.running, "default")
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+// SchedulingPolicyFairShare schedules the pending jobs round-robin across the namespaces
+const SchedulingPolicyFairShare = "fairShare"
+
+// fairSharePolicy schedules the pending jobs round-robin across the namespaces, so that a busy namespace cannot take
+// all the available PipelineRun slots. The namespace with the fewest running jobs is served first, and the jobs in a
+// namespace (and the namespaces with the same number of running jobs) are served in the queue order
+type fairSharePolicy struct{}
+
+func (f *fairSharePolicy) Name() string {
+	return SchedulingPolicyFairShare
+}
+
+func (f *fairSharePolicy) Schedule(snapshot *Snapshot, admit AdmitFunc) {
+	var namespaces []string
+	queues := map[string][]*cicdv1.IntegrationJob{}
+	for _, j := range snapshot.Pending {
+		if _, exist := queues[j.Namespace]; !exist {
+			namespaces = append(namespaces, j.Namespace)
+		}
+		queues[j.Namespace] = append(queues[j.Namespace], j)
+	}
+
+	shares := map[string]int{}
+	for _, j := range snapshot.Running {
+		shares[j.Namespace]++
+	}
+
+	for snapshot.Available > 0 {
+		ns := nextFairShareNamespace(namespaces, queues, shares)
+		if ns == "" {
+			return
+		}
+		job := queues[ns][0]
+		queues[ns] = queues[ns][1:]
+
+		if admit(job) {
+			shares[ns]++
+		}
+	}
+}
+
+// nextFairShareNamespace returns the namespace having pending jobs with the fewest running jobs. The namespaces are
+// given in the order of their first pending jobs in the queue, which breaks the ties
+func nextFairShareNamespace(namespaces []string, queues map[string][]*cicdv1.IntegrationJob, shares map[string]int) string {
+	next := ""
+	for _, ns := range namespaces {
+		if len(queues[ns]) == 0 {
+			continue
+		}
+		if next == "" || shares[ns] < shares[next] {
+			next = ns
+		}
+	}
+	return next
+}
@@ -0,0 +1,154 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScheduler_run_fairShare(t *testing.T) {
+	tc := map[string]struct {
+		policy    string
+		scheduled []string
+	}{
+		"priority": {
+			policy:    SchedulingPolicyPriority,
+			scheduled: []string{"a-1", "a-2"},
+		},
+		"fairShare": {
+			policy:    SchedulingPolicyFairShare,
+			scheduled: []string{"b-1", "c-1"},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.MaxPipelineRun = 3
+			configs.SchedulerPolicy = c.policy
+			defer func() { configs.SchedulerPolicy = "" }()
+
+			s := runtime.NewScheme()
+			utilruntime.Must(cicdv1.AddToScheme(s))
+			utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+			now := time.Now()
+			jobs := []*cicdv1.IntegrationJob{
+				fairShareTestJob("a-running", "ns-a", now.Add(-10*time.Minute), cicdv1.IntegrationJobStateRunning),
+				fairShareTestJob("a-1", "ns-a", now.Add(-6*time.Minute), cicdv1.IntegrationJobStatePending),
+				fairShareTestJob("a-2", "ns-a", now.Add(-5*time.Minute), cicdv1.IntegrationJobStatePending),
+				fairShareTestJob("a-3", "ns-a", now.Add(-4*time.Minute), cicdv1.IntegrationJobStatePending),
+				fairShareTestJob("b-1", "ns-b", now.Add(-3*time.Minute), cicdv1.IntegrationJobStatePending),
+				fairShareTestJob("b-2", "ns-b", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending),
+				fairShareTestJob("c-1", "ns-c", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending),
+			}
+
+			// The running job actually has its PipelineRun
+			builder := fake.NewClientBuilder().WithScheme(s).WithObjects(&tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "a-running", Namespace: "ns-a"}})
+			for _, j := range jobs {
+				builder = builder.WithObjects(j)
+			}
+			cli := builder.Build()
+			sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+			sch.jobPool = pool.New(sch.caller, priorityCompare)
+			for _, j := range jobs {
+				sch.jobPool.SyncJob(j)
+			}
+
+			sch.run()
+
+			for _, j := range jobs[1:] {
+				err := cli.Get(context.Background(), types.NamespacedName{Name: j.Name, Namespace: j.Namespace}, &tektonv1beta1.PipelineRun{})
+				require.Equal(t, contains(c.scheduled, j.Name), err == nil, j.Name)
+			}
+		})
+	}
+}
+
+func TestNextFairShareNamespace(t *testing.T) {
+	job := &cicdv1.IntegrationJob{}
+	tc := map[string]struct {
+		queues map[string]int
+		shares map[string]int
+
+		expected string
+	}{
+		"noPending": {
+			queues:   map[string]int{"ns-a": 0, "ns-b": 0},
+			expected: "",
+		},
+		"fewestShares": {
+			queues:   map[string]int{"ns-a": 2, "ns-b": 1},
+			shares:   map[string]int{"ns-a": 2, "ns-b": 1},
+			expected: "ns-b",
+		},
+		"tieQueueOrder": {
+			queues:   map[string]int{"ns-a": 1, "ns-b": 1},
+			shares:   map[string]int{"ns-a": 1, "ns-b": 1},
+			expected: "ns-a",
+		},
+		"skipEmpty": {
+			queues:   map[string]int{"ns-a": 0, "ns-b": 1},
+			shares:   map[string]int{"ns-b": 3},
+			expected: "ns-b",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			queues := map[string][]*cicdv1.IntegrationJob{}
+			for ns, n := range c.queues {
+				for i := 0; i < n; i++ {
+					queues[ns] = append(queues[ns], job)
+				}
+			}
+			shares := c.shares
+			if shares == nil {
+				shares = map[string]int{}
+			}
+			require.Equal(t, c.expected, nextFairShareNamespace([]string{"ns-a", "ns-b"}, queues, shares))
+		})
+	}
+}
+
+func fairShareTestJob(name, namespace string, created time.Time, state cicdv1.IntegrationJobState) *cicdv1.IntegrationJob {
+	job := schedulerTestJob(name, "test-ic", "1", created, state)
+	job.Namespace = namespace
+	return job
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -18,10 +18,38 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
 	"github.com/tmax-cloud/cicd-operator/pkg/structs"
 )
 
+// SchedulingPolicyFIFO schedules the pending jobs in the order of creation, regardless of their priorities
+const SchedulingPolicyFIFO = "fifo"
+
+// fifoPolicy schedules the pending jobs in the order of creation
+type fifoPolicy struct{}
+
+func (f *fifoPolicy) Name() string {
+	return SchedulingPolicyFIFO
+}
+
+func (f *fifoPolicy) Schedule(snapshot *Snapshot, admit AdmitFunc) {
+	jobs := make([]*cicdv1.IntegrationJob, len(snapshot.Pending))
+	copy(jobs, snapshot.Pending)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return fifoLess(jobs[i], jobs[j])
+	})
+
+	for _, j := range jobs {
+		if snapshot.Available <= 0 {
+			return
+		}
+		admit(j)
+	}
+}
+
 func fifoCompare(_a, _b structs.Item) bool {
 	if _a == nil || _b == nil {
 		return false
@@ -32,5 +60,14 @@ func fifoCompare(_a, _b structs.Item) bool {
 		return false
 	}
 
-	return a.CreationTimestamp.Time.Before(b.CreationTimestamp.Time) || fmt.Sprintf("%s_%s", a.Namespace, a.Name) < fmt.Sprintf("%s_%s", b.Namespace, b.Name)
+	return fifoLess(a.IntegrationJob, b.IntegrationJob)
+}
+
+// fifoLess returns if a entered the queue before b. The ones entered at the same time are ordered by their namespaces
+// and names
+func fifoLess(a, b *cicdv1.IntegrationJob) bool {
+	if seqA, seqB := a.GetQueueSequence(), b.GetQueueSequence(); seqA != seqB {
+		return seqA < seqB
+	}
+	return fmt.Sprintf("%s_%s", a.Namespace, a.Name) < fmt.Sprintf("%s_%s", b.Namespace, b.Name)
 }
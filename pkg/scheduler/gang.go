@@ -0,0 +1,85 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// checkGang returns an error if the jobs of the IntegrationJob do not fit in the free resources of the nodes at once,
+// in addition to the check, so that the IntegrationJob is admitted only when all of its jobs can run
+func (c *clusterCapacity) checkGang(job *cicdv1.IntegrationJob) error {
+	if err := c.check(job); err != nil {
+		return err
+	}
+	if c.pack(job) == nil {
+		return fmt.Errorf("jobs of %s do not fit in the nodes at once", job.Name)
+	}
+	return nil
+}
+
+// pack places the jobs of the IntegrationJob in the nodes, the larger ones first, each in the first node it fits in
+// It returns the free resources of the nodes after the jobs are placed, or nil if any of the jobs does not fit
+func (c *clusterCapacity) pack(job *cicdv1.IntegrationJob) []corev1.ResourceList {
+	var requests []corev1.ResourceList
+	for _, j := range job.Spec.Jobs {
+		requests = append(requests, jobRequests(j))
+	}
+	sort.SliceStable(requests, func(a, b int) bool {
+		for _, name := range capacityResources {
+			qa, qb := requests[a][name], requests[b][name]
+			if cmp := qa.Cmp(qb); cmp != 0 {
+				return cmp > 0
+			}
+		}
+		return false
+	})
+
+	nodes := make([]corev1.ResourceList, len(c.nodes))
+	copy(nodes, c.nodes)
+	for _, r := range requests {
+		placed := false
+		for i, node := range nodes {
+			if checkCapacity(node, r, "") == nil {
+				nodes[i] = subtractResources(node, r)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return nil
+		}
+	}
+	return nodes
+}
+
+// hasPendingGangJobs checks if any of the pending jobs is gang scheduled, which needs the cluster capacity
+func (s *scheduler) hasPendingGangJobs() bool {
+	found := false
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok && j.Spec.GangScheduling {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,147 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterCapacity_checkGang(t *testing.T) {
+	tc := map[string]struct {
+		nodes []string
+		jobs  []string
+
+		expectedErrorMsg string
+	}{
+		"fit": {
+			nodes: []string{"4", "2"},
+			jobs:  []string{"1", "2", "3"},
+		},
+		"fitLargerFirst": {
+			nodes: []string{"3", "3"},
+			jobs:  []string{"1", "1", "2", "2"},
+		},
+		"notAtOnce": {
+			nodes:            []string{"4", "2"},
+			jobs:             []string{"3", "3"},
+			expectedErrorMsg: "jobs of test do not fit in the nodes at once",
+		},
+		"notInTotal": {
+			nodes:            []string{"4", "2"},
+			jobs:             []string{"4", "4"},
+			expectedErrorMsg: "cpu request 8 exceeds the available 6 of the cluster",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			capacity := &clusterCapacity{total: corev1.ResourceList{}, namespaces: map[string]corev1.ResourceList{"default": nil}}
+			for _, cpu := range c.nodes {
+				node := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+				capacity.nodes = append(capacity.nodes, node)
+				addResources(capacity.total, node)
+			}
+
+			err := capacity.checkGang(gangTestJob("test", c.jobs...))
+			if c.expectedErrorMsg != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expectedErrorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClusterCapacity_reserve_gang(t *testing.T) {
+	capacity := &clusterCapacity{
+		total:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+		nodes:      []corev1.ResourceList{{corev1.ResourceCPU: resource.MustParse("3")}, {corev1.ResourceCPU: resource.MustParse("3")}},
+		namespaces: map[string]corev1.ResourceList{"default": nil},
+	}
+
+	first := gangTestJob("first", "2", "2")
+	require.NoError(t, capacity.checkGang(first))
+	capacity.reserve(first)
+
+	// Only 1 cpu is left in each node
+	require.Error(t, capacity.checkGang(gangTestJob("second", "2")))
+	require.NoError(t, capacity.checkGang(gangTestJob("third", "1", "1")))
+}
+
+func TestScheduler_run_gang(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	now := time.Now()
+	gang := gangTestJob("gang", "2", "2", "2")
+	gang.CreationTimestamp.Time = now.Add(-1 * time.Minute)
+	normal := gangTestJob("normal", "2", "2", "2")
+	normal.Spec.GangScheduling = false
+	normal.CreationTimestamp.Time = now
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(capacityTestNode("node-1", "3", true), capacityTestNode("node-2", "3", true), gang, normal).Build()
+	sch := &scheduler{k8sClient: cli, apiReader: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{gang, normal} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	// Capacity aware admission is disabled, so only the gang scheduled job is checked
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "gang", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "normal", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "gang", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonWaitingForCapacity, ij.Status.Reason)
+	require.Equal(t, "waiting for capacity: jobs of gang do not fit in the nodes at once", ij.Status.Message)
+}
+
+func gangTestJob(name string, cpus ...string) *cicdv1.IntegrationJob {
+	job := schedulerTestJob(name, "test-ic", "0", time.Now(), cicdv1.IntegrationJobStatePending)
+	job.Spec.GangScheduling = true
+	job.Spec.Jobs = nil
+	for i, cpu := range cpus {
+		job.Spec.Jobs = append(job.Spec.Jobs, cicdv1.Job{Container: corev1.Container{Name: fmt.Sprintf("job-%d", i), Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}}})
+	}
+	return job
+}
@@ -0,0 +1,81 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var queueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cicd_scheduler_queue_depth",
+	Help: "Number of the pending IntegrationJobs waiting to be scheduled",
+})
+
+var runningJobsCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cicd_scheduler_running_jobs",
+	Help: "Number of the running IntegrationJobs",
+})
+
+var pendingJobsCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cicd_scheduler_pending_jobs",
+	Help: "Number of the pending IntegrationJobs of each namespace",
+}, []string{"namespace"})
+
+var admissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cicd_scheduler_admissions_total",
+	Help: "Number of the IntegrationJobs admitted, i.e., whose PipelineRuns are created by the scheduler",
+}, []string{"namespace"})
+
+var waitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    "cicd_scheduler_wait_seconds",
+	Help:    "Time (in second) from the creation of the IntegrationJobs to their admission",
+	Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200},
+})
+
+func init() {
+	metrics.Registry.MustRegister(queueDepth, runningJobsCount, pendingJobsCount, admissions, waitSeconds)
+}
+
+// updateQueueMetrics exports the number of the pending jobs (of each namespace) and the running jobs
+func (s *scheduler) updateQueueMetrics() {
+	queueDepth.Set(float64(s.jobPool.Pending().Len()))
+	runningJobsCount.Set(float64(s.jobPool.Running().Len()))
+
+	pending := map[string]int{}
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok {
+			pending[j.Namespace]++
+		}
+	})
+	// Reset not to keep exporting the namespaces without pending jobs
+	pendingJobsCount.Reset()
+	for ns, cnt := range pending {
+		pendingJobsCount.WithLabelValues(ns).Set(float64(cnt))
+	}
+}
+
+// observeAdmission records the admission of the job and how long it waited
+func observeAdmission(job *cicdv1.IntegrationJob) {
+	admissions.WithLabelValues(job.Namespace).Inc()
+	waitSeconds.Observe(time.Since(job.CreationTimestamp.Time).Seconds())
+}
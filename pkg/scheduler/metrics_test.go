@@ -0,0 +1,73 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScheduler_run_metrics(t *testing.T) {
+	configs.MaxPipelineRun = 1
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	now := time.Now()
+	first := schedulerTestJob("first", "test-ic", "1", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending)
+	second := schedulerTestJob("second", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	other := schedulerTestJob("other", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+	other.Namespace = "other"
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(first, second, other).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{first, second, other} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	admitted := testutil.ToFloat64(admissions.WithLabelValues("default"))
+	waitCount := histogramCount(t)
+
+	sch.run()
+
+	require.Equal(t, float64(3), testutil.ToFloat64(queueDepth))
+	require.Equal(t, float64(0), testutil.ToFloat64(runningJobsCount))
+	require.Equal(t, float64(2), testutil.ToFloat64(pendingJobsCount.WithLabelValues("default")))
+	require.Equal(t, float64(1), testutil.ToFloat64(pendingJobsCount.WithLabelValues("other")))
+	require.Equal(t, admitted+1, testutil.ToFloat64(admissions.WithLabelValues("default")))
+	require.Equal(t, waitCount+1, histogramCount(t))
+}
+
+func histogramCount(t *testing.T) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, waitSeconds.Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,80 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+// Policy decides the order in which the pending IntegrationJobs are admitted, i.e., their PipelineRuns are created
+type Policy interface {
+	// Name is the name of the policy, which is selected by the operator config schedulerPolicy
+	Name() string
+
+	// Schedule admits the pending jobs of the snapshot in the policy's order, by calling admit for each of them
+	// admit returns if the job is admitted, and updates the snapshot's running jobs and available slots. It admits no
+	// job if there is no available slot, or the job does not meet the concurrency limits, quotas or capacity
+	Schedule(snapshot *Snapshot, admit AdmitFunc)
+}
+
+// AdmitFunc tries admitting the job and returns if it is admitted
+type AdmitFunc func(job *cicdv1.IntegrationJob) bool
+
+// Snapshot is a snapshot of the queued and running IntegrationJobs given to the policies. It should not be modified by
+// the policies
+type Snapshot struct {
+	// Pending are the pending IntegrationJobs in the queue order, i.e., higher effective priorities first and then
+	// older ones first
+	Pending []*cicdv1.IntegrationJob
+
+	// Running are the running IntegrationJobs, including the ones admitted while scheduling
+	Running []*cicdv1.IntegrationJob
+
+	// Available is the number of the PipelineRuns which can be created more
+	Available int
+}
+
+var policies = map[string]Policy{}
+var policiesLock sync.Mutex
+
+func init() {
+	RegisterPolicy(&fifoPolicy{})
+	RegisterPolicy(&priorityPolicy{})
+	RegisterPolicy(&fairSharePolicy{})
+}
+
+// RegisterPolicy registers a scheduling policy, so it can be selected by its name. It replaces the policy registered
+// with the same name
+func RegisterPolicy(p Policy) {
+	policiesLock.Lock()
+	defer policiesLock.Unlock()
+	policies[p.Name()] = p
+}
+
+// getPolicy returns the policy of the name. It returns an error with the priority policy if it is not registered
+func getPolicy(name string) (Policy, error) {
+	policiesLock.Lock()
+	defer policiesLock.Unlock()
+	p, exist := policies[name]
+	if !exist {
+		return policies[SchedulingPolicyPriority], fmt.Errorf("scheduling policy %s is not registered", name)
+	}
+	return p, nil
+}
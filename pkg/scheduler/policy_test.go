@@ -0,0 +1,134 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetPolicy(t *testing.T) {
+	tc := map[string]struct {
+		name string
+
+		expected    string
+		errorOccurs bool
+	}{
+		"fifo":       {name: SchedulingPolicyFIFO, expected: SchedulingPolicyFIFO},
+		"priority":   {name: SchedulingPolicyPriority, expected: SchedulingPolicyPriority},
+		"fairShare":  {name: SchedulingPolicyFairShare, expected: SchedulingPolicyFairShare},
+		"notFound":   {name: "not-found", expected: SchedulingPolicyPriority, errorOccurs: true},
+		"notDefined": {name: "", expected: SchedulingPolicyPriority, errorOccurs: true},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			p, err := getPolicy(c.name)
+			if c.errorOccurs {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.expected, p.Name())
+		})
+	}
+}
+
+// reversePolicy admits the newest pending job first, and tries admitting jobs which should not be admitted
+type reversePolicy struct {
+	admitted []bool
+}
+
+func (r *reversePolicy) Name() string {
+	return "reverse"
+}
+
+func (r *reversePolicy) Schedule(snapshot *Snapshot, admit AdmitFunc) {
+	last := snapshot.Pending[len(snapshot.Pending)-1]
+	r.admitted = append(r.admitted, admit(last))
+	r.admitted = append(r.admitted, admit(last))
+	r.admitted = append(r.admitted, admit(&cicdv1.IntegrationJob{}))
+	r.admitted = append(r.admitted, admit(snapshot.Pending[0]))
+}
+
+func TestScheduler_run_policy(t *testing.T) {
+	reverse := &reversePolicy{}
+	RegisterPolicy(reverse)
+	defer func() {
+		policiesLock.Lock()
+		delete(policies, reverse.Name())
+		policiesLock.Unlock()
+	}()
+
+	tc := map[string]struct {
+		policy    string
+		scheduled string
+	}{
+		"priority": {policy: SchedulingPolicyPriority, scheduled: "high"},
+		"fifo":     {policy: SchedulingPolicyFIFO, scheduled: "old"},
+		"custom":   {policy: "reverse", scheduled: "new"},
+		"unknown":  {policy: "unknown", scheduled: "high"},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			configs.MaxPipelineRun = 1
+			configs.SchedulerPolicy = c.policy
+			defer func() { configs.SchedulerPolicy = "" }()
+
+			s := runtime.NewScheme()
+			utilruntime.Must(cicdv1.AddToScheme(s))
+			utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+			now := time.Now()
+			old := schedulerTestJob("old", "test-ic", "1", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending)
+			high := schedulerTestJob("high", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+			high.Spec.Priority = 10
+			newJob := schedulerTestJob("new", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+			jobs := []*cicdv1.IntegrationJob{old, high, newJob}
+
+			cli := fake.NewClientBuilder().WithScheme(s).WithObjects(old, high, newJob).Build()
+			sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+			sch.jobPool = pool.New(sch.caller, priorityCompare)
+			for _, j := range jobs {
+				sch.jobPool.SyncJob(j)
+			}
+
+			sch.run()
+
+			for _, j := range jobs {
+				err := cli.Get(context.Background(), types.NamespacedName{Name: j.Name, Namespace: j.Namespace}, &tektonv1beta1.PipelineRun{})
+				require.Equal(t, c.scheduled == j.Name, err == nil, j.Name)
+			}
+		})
+	}
+
+	// The same job is not admitted twice, and the jobs not pending or exceeding the available slots are not admitted
+	require.Equal(t, []bool{true, false, false, false}, reverse.admitted)
+}
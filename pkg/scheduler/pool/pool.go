@@ -18,6 +18,7 @@ package pool
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -42,6 +43,8 @@ type JobPool interface {
 	Lock()
 	Unlock()
 	SyncJob(job *v1.IntegrationJob)
+	SetWeight(job *v1.IntegrationJob, weight int32)
+	SetAge(job *v1.IntegrationJob, age int32)
 	Running() structs.SortedUniqueList
 	Pending() structs.SortedUniqueList
 }
@@ -86,6 +89,8 @@ func (j *jobPool) SyncJob(job *v1.IntegrationJob) {
 
 	oldStatus := v1.IntegrationJobState("")
 	newStatus := job.Status.State
+	var oldPriority int32
+	var oldPaused bool
 
 	// Make / fetch node pointer
 	var node *JobNode
@@ -93,11 +98,16 @@ func (j *jobPool) SyncJob(job *v1.IntegrationJob) {
 	if exist {
 		node = candidate
 		oldStatus = candidate.Status.State
+		oldPriority = candidate.Spec.Priority
+		oldPaused = candidate.Spec.Paused
+		oldSequence := candidate.Annotations[v1.JobAnnotationQueueSequence]
 		candidate.IntegrationJob = job.DeepCopy()
+		setQueueSequence(candidate, oldSequence)
 	} else {
 		node = &JobNode{
 			IntegrationJob: job.DeepCopy(),
 		}
+		setQueueSequence(node, "")
 	}
 	j.jobMap[nodeID] = node
 
@@ -110,8 +120,16 @@ func (j *jobPool) SyncJob(job *v1.IntegrationJob) {
 		return
 	}
 
-	// If status is not changed, do nothing
+	// If status is not changed, do nothing, except for re-sorting the pending job whose priority is changed and
+	// scheduling the pending job which is resumed
 	if exist && oldStatus == newStatus {
+		if newStatus == v1.IntegrationJobStatePending && oldPriority != job.Spec.Priority {
+			j.pending.Delete(node)
+			j.pending.Add(node)
+			j.sendSchedule()
+		} else if newStatus == v1.IntegrationJobStatePending && oldPaused && !job.Spec.Paused {
+			j.sendSchedule()
+		}
 		return
 	}
 
@@ -152,6 +170,33 @@ func (j *jobPool) SyncJob(job *v1.IntegrationJob) {
 	}
 }
 
+// SetWeight sets the scheduling weight of the pending job, re-sorting it if the weight is changed
+func (j *jobPool) SetWeight(job *v1.IntegrationJob, weight int32) {
+	node, exist := j.jobMap[getNodeID(job)]
+	if !exist || node.Weight == weight {
+		return
+	}
+	node.Weight = weight
+	j.resort(node)
+}
+
+// SetAge sets the aging bonus of the pending job, re-sorting it if the bonus is changed
+func (j *jobPool) SetAge(job *v1.IntegrationJob, age int32) {
+	node, exist := j.jobMap[getNodeID(job)]
+	if !exist || node.Age == age {
+		return
+	}
+	node.Age = age
+	j.resort(node)
+}
+
+func (j *jobPool) resort(node *JobNode) {
+	if node.Status.State == v1.IntegrationJobStatePending {
+		j.pending.Delete(node)
+		j.pending.Add(node)
+	}
+}
+
 func (j *jobPool) manageTimeout(timeout time.Duration, job *v1.IntegrationJob) {
 	time.Sleep(timeout)
 	j.sendSchedule()
@@ -166,6 +211,20 @@ func (j *jobPool) sendSchedule() {
 // JobNode is a node to be stored in jobMap and jobPool
 type JobNode struct {
 	*v1.IntegrationJob
+
+	// Weight is the scheduling weight of the job's IntegrationConfig
+	Weight int32
+
+	// Age is the priority bonus the job has gained by waiting in the queue
+	Age int32
+
+	// SequencePersisted is whether the job's queue sequence annotation is stored in the api server
+	SequencePersisted bool
+}
+
+// GetPriority returns the effective priority of the job, i.e., its priority plus its scheduling weight and aging bonus
+func (f *JobNode) GetPriority() int32 {
+	return f.Spec.Priority + f.Weight + f.Age
 }
 
 // Equals implements Item's method
@@ -183,8 +242,41 @@ func (f *JobNode) Equals(another structs.Item) bool {
 // DeepCopy implements Item's method
 func (f *JobNode) DeepCopy() structs.Item {
 	return &JobNode{
-		IntegrationJob: f.IntegrationJob.DeepCopy(),
+		IntegrationJob:    f.IntegrationJob.DeepCopy(),
+		Weight:            f.Weight,
+		Age:               f.Age,
+		SequencePersisted: f.SequencePersisted,
+	}
+}
+
+// setQueueSequence keeps the queue sequence of the node, or assigns a new one if it enters the queue for the first time
+func setQueueSequence(node *JobNode, oldSequence string) {
+	if _, exist := node.Annotations[v1.JobAnnotationQueueSequence]; exist {
+		node.SequencePersisted = true
+		return
+	}
+	node.SequencePersisted = false
+	if oldSequence == "" {
+		oldSequence = strconv.FormatInt(newQueueSequence(node.CreationTimestamp.Time, time.Now()), 10)
+	}
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[v1.JobAnnotationQueueSequence] = oldSequence
+}
+
+// newQueueSequence returns a sequence for the job entering the queue now. The creation timestamp is only in seconds, so
+// the time passed since then is added for the order within the second. It's clamped to the second not to be ordered
+// after the jobs created later, e.g., when the queue is rebuilt by a restart
+func newQueueSequence(created, now time.Time) int64 {
+	elapsed := now.Sub(created)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed >= time.Second {
+		elapsed = time.Second - 1
 	}
+	return created.UnixNano() + int64(elapsed)
 }
 
 func getNodeID(j *v1.IntegrationJob) string {
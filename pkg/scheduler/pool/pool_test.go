@@ -65,6 +65,154 @@ func TestJobPool_SyncJob(t *testing.T) {
 	assert.Equal(t, 0, p.running.Len(), "state transition isn't done properly")
 }
 
+func TestJobPool_SyncJob_priority(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	p := New(ch, func(a, b structs.Item) bool {
+		return a.(*JobNode).Spec.Priority > b.(*JobNode).Spec.Priority
+	})
+
+	now := time.Now()
+	testJob1 := jobForTest("1", "default", now)
+	testJob2 := jobForTest("2", "default", now)
+	p.SyncJob(testJob1)
+	p.SyncJob(testJob2)
+	assert.Equal(t, "1", p.pending.First().(*JobNode).Name)
+
+	// Priority of 2 is raised
+	testJob2.Spec.Priority = 100
+	p.SyncJob(testJob2)
+	assert.Equal(t, 2, p.pending.Len())
+	assert.Equal(t, "2", p.pending.First().(*JobNode).Name)
+}
+
+func TestJobPool_SetWeight(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	p := New(ch, func(a, b structs.Item) bool {
+		return a.(*JobNode).GetPriority() > b.(*JobNode).GetPriority()
+	})
+
+	now := time.Now()
+	testJob1 := jobForTest("1", "default", now)
+	testJob2 := jobForTest("2", "default", now)
+	testJob2.Spec.Priority = -10
+	p.SyncJob(testJob1)
+	p.SyncJob(testJob2)
+	assert.Equal(t, "1", p.pending.First().(*JobNode).Name)
+
+	// Weight of 2 is raised
+	p.SetWeight(testJob2, 20)
+	assert.Equal(t, 2, p.pending.Len())
+	assert.Equal(t, "2", p.pending.First().(*JobNode).Name)
+	assert.Equal(t, int32(10), p.pending.First().(*JobNode).GetPriority())
+
+	// Weight is kept when the job is synced again
+	testJob2.Spec.Priority = -5
+	p.SyncJob(testJob2)
+	assert.Equal(t, int32(15), p.pending.First().(*JobNode).GetPriority())
+
+	// Unknown job
+	p.SetWeight(jobForTest("3", "default", now), 100)
+	assert.Equal(t, 2, p.pending.Len())
+}
+
+func TestJobPool_SetAge(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	p := New(ch, func(a, b structs.Item) bool {
+		return a.(*JobNode).GetPriority() > b.(*JobNode).GetPriority()
+	})
+
+	now := time.Now()
+	testJob1 := jobForTest("1", "default", now)
+	testJob1.Spec.Priority = 10
+	testJob2 := jobForTest("2", "default", now)
+	p.SyncJob(testJob1)
+	p.SyncJob(testJob2)
+	p.SetWeight(testJob2, 5)
+	assert.Equal(t, "1", p.pending.First().(*JobNode).Name)
+
+	// Job 2 has aged
+	p.SetAge(testJob2, 6)
+	assert.Equal(t, 2, p.pending.Len())
+	assert.Equal(t, "2", p.pending.First().(*JobNode).Name)
+	assert.Equal(t, int32(11), p.pending.First().(*JobNode).GetPriority())
+	assert.Equal(t, int32(6), p.pending.First().DeepCopy().(*JobNode).Age)
+
+	// Unknown job
+	p.SetAge(jobForTest("3", "default", now), 100)
+	assert.Equal(t, 2, p.pending.Len())
+}
+
+func TestJobPool_SyncJob_queueSequence(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	p := New(ch, testCompare)
+
+	created := time.Now().Truncate(time.Second)
+	newJob := jobForTest("new", "default", created)
+	persistedJob := jobForTest("persisted", "default", created)
+	persistedJob.Annotations = map[string]string{cicdv1.JobAnnotationQueueSequence: "100"}
+	p.SyncJob(newJob)
+	p.SyncJob(persistedJob)
+
+	// A new job is assigned a sequence within its creation second
+	node := p.jobMap[getNodeID(newJob)]
+	assert.Equal(t, false, node.SequencePersisted)
+	seq := node.GetQueueSequence()
+	assert.Equal(t, true, seq >= created.UnixNano() && seq < created.Add(time.Second).UnixNano())
+
+	// The sequence is kept when it's synced again without the annotation, and persisted when it has the annotation
+	p.SyncJob(newJob)
+	assert.Equal(t, seq, p.jobMap[getNodeID(newJob)].GetQueueSequence())
+	persisted := newJob.DeepCopy()
+	persisted.Annotations = map[string]string{cicdv1.JobAnnotationQueueSequence: fmt.Sprintf("%d", seq)}
+	p.SyncJob(persisted)
+	assert.Equal(t, true, p.jobMap[getNodeID(newJob)].SequencePersisted)
+	assert.Equal(t, seq, p.jobMap[getNodeID(newJob)].GetQueueSequence())
+
+	// The persisted sequence is kept
+	node = p.jobMap[getNodeID(persistedJob)]
+	assert.Equal(t, true, node.SequencePersisted)
+	assert.Equal(t, int64(100), node.GetQueueSequence())
+	assert.Equal(t, true, node.DeepCopy().(*JobNode).SequencePersisted)
+}
+
+func TestNewQueueSequence(t *testing.T) {
+	created := time.Now().Truncate(time.Second)
+	tc := map[string]struct {
+		now      time.Time
+		expected int64
+	}{
+		"sameSecond":     {now: created.Add(300 * time.Millisecond), expected: created.Add(300 * time.Millisecond).UnixNano()},
+		"clamped":        {now: created.Add(time.Hour), expected: created.Add(time.Second).UnixNano() - 1},
+		"beforeCreation": {now: created.Add(-time.Second), expected: created.UnixNano()},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, newQueueSequence(created, c.now))
+		})
+	}
+}
+
+func TestJobPool_SyncJob_resumed(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	p := New(ch, testCompare)
+
+	testJob := jobForTest("1", "default", time.Now())
+	testJob.Spec.Timeout = &metav1.Duration{Duration: time.Hour}
+	testJob.Spec.Paused = true
+	p.SyncJob(testJob)
+	<-ch
+
+	// Paused job is not scheduled again until it's resumed
+	p.SyncJob(testJob)
+	assert.Equal(t, 0, len(ch))
+
+	testJob.Spec.Paused = false
+	p.SyncJob(testJob)
+	assert.Equal(t, 1, len(ch))
+	assert.Equal(t, 1, p.pending.Len())
+}
+
 func testCompare(_a, _b structs.Item) bool {
 	if _a == nil || _b == nil {
 		return false
@@ -0,0 +1,146 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SchedulingPolicyPriority schedules the pending jobs in the queue order
+const SchedulingPolicyPriority = "priority"
+
+// priorityPolicy schedules the pending jobs in the queue order, i.e., higher effective priorities first and then older
+// ones first
+type priorityPolicy struct{}
+
+func (p *priorityPolicy) Name() string {
+	return SchedulingPolicyPriority
+}
+
+func (p *priorityPolicy) Schedule(snapshot *Snapshot, admit AdmitFunc) {
+	for _, j := range snapshot.Pending {
+		if snapshot.Available <= 0 {
+			return
+		}
+		admit(j)
+	}
+}
+
+// priorityCompare sorts the IntegrationJobs with higher effective priorities (i.e., priorities plus the scheduling
+// weights of their IntegrationConfigs) first, and the ones with the same effective priority in FIFO order
+func priorityCompare(_a, _b structs.Item) bool {
+	if _a == nil || _b == nil {
+		return false
+	}
+	a, aOk := _a.(*pool.JobNode)
+	b, bOk := _b.(*pool.JobNode)
+	if !aOk || !bOk {
+		return false
+	}
+
+	if a.GetPriority() != b.GetPriority() {
+		return a.GetPriority() > b.GetPriority()
+	}
+	return fifoCompare(_a, _b)
+}
+
+// syncWeights sets the scheduling weights of the IntegrationConfigs to their pending jobs
+func (s *scheduler) syncWeights() {
+	weights := map[string]int32{}
+	changed := map[*cicdv1.IntegrationJob]int32{}
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		j, ok := item.(*pool.JobNode)
+		if !ok {
+			return
+		}
+		key := configKey(j.IntegrationJob)
+		weight, exist := weights[key]
+		if !exist {
+			weight = s.getSchedulingWeight(j.IntegrationJob)
+			weights[key] = weight
+		}
+		if weight != j.Weight {
+			changed[j.IntegrationJob] = weight
+		}
+	})
+
+	// The pending jobs are re-sorted after being iterated
+	for job, weight := range changed {
+		s.jobPool.SetWeight(job, weight)
+	}
+}
+
+// getSchedulingWeight gets the scheduling weight of the job's IntegrationConfig. It returns 0 if it's not found
+func (s *scheduler) getSchedulingWeight(job *cicdv1.IntegrationJob) int32 {
+	config := &cicdv1.IntegrationConfig{}
+	if err := s.k8sClient.Get(context.Background(), types.NamespacedName{Name: job.Spec.ConfigRef.Name, Namespace: job.Namespace}, config); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "")
+		}
+		return 0
+	}
+	return config.Spec.IJManageSpec.SchedulingWeight
+}
+
+// updateQueueStatus records the positions of the pending jobs in the queue to their statuses, if they are changed
+func (s *scheduler) updateQueueStatus() {
+	length := int32(s.jobPool.Pending().Len())
+	position := int32(0)
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		j, ok := item.(*pool.JobNode)
+		if !ok {
+			return
+		}
+		position++
+		queue := &cicdv1.IntegrationJobQueueStatus{Position: position, Length: length, Priority: j.GetPriority()}
+		if j.Status.Queue != nil && *j.Status.Queue == *queue {
+			return
+		}
+
+		original := j.IntegrationJob.DeepCopy()
+		j.Status.Queue = queue
+		if err := s.k8sClient.Status().Patch(context.Background(), j.IntegrationJob, client.MergeFrom(original)); err != nil {
+			log.Error(err, "")
+		}
+	})
+}
+
+// persistQueueSequences stores the queue sequences of the pending jobs to their annotations, so the order of the queue
+// is kept when it is rebuilt after a restart
+func (s *scheduler) persistQueueSequences() {
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		j, ok := item.(*pool.JobNode)
+		if !ok || j.SequencePersisted {
+			return
+		}
+
+		original := j.IntegrationJob.DeepCopy()
+		delete(original.Annotations, cicdv1.JobAnnotationQueueSequence)
+		if err := s.k8sClient.Patch(context.Background(), j.IntegrationJob, client.MergeFrom(original)); err != nil {
+			log.Error(err, "")
+			return
+		}
+		j.SequencePersisted = true
+	})
+}
@@ -0,0 +1,52 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPriorityCompare(t *testing.T) {
+	now := time.Now()
+	node := func(name string, priority int32, created time.Time) *pool.JobNode {
+		return &pool.JobNode{IntegrationJob: &cicdv1.IntegrationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.Time{Time: created}},
+			Spec:       cicdv1.IntegrationJobSpec{Priority: priority},
+		}}
+	}
+
+	q := structs.NewSortedUniqueQueue(priorityCompare)
+	q.Add(node("pr-old", 0, now.Add(-3*time.Minute)))
+	q.Add(node("pr-new", 0, now))
+	q.Add(node("release", 200, now.Add(-1*time.Minute)))
+	q.Add(node("master-new", 100, now))
+	q.Add(node("master-old", 100, now.Add(-2*time.Minute)))
+	q.Add(node("low", -10, now.Add(-5*time.Minute)))
+
+	var names []string
+	q.ForEach(func(item structs.Item) {
+		names = append(names, item.(*pool.JobNode).Name)
+	})
+	require.Equal(t, []string{"release", "master-old", "master-new", "pr-old", "pr-new", "low"}, names)
+}
@@ -0,0 +1,219 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// resourceRequests sums up the resource requests of the jobs of the IntegrationJob
+// Limits are used for the resources without requests, just as Kubernetes does
+// The jobs should be resolved first (see scheduler.resolve), for the jobs using job templates or Tekton Tasks
+func resourceRequests(job *cicdv1.IntegrationJob) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, j := range job.Spec.Jobs {
+		addResources(requests, j.Resources.Requests)
+		for name, q := range j.Resources.Limits {
+			if _, exist := j.Resources.Requests[name]; !exist {
+				addResources(requests, corev1.ResourceList{name: q})
+			}
+		}
+	}
+	return requests
+}
+
+// usedResources sums up the resource requests of the running IntegrationJobs
+func usedResources(running []*cicdv1.IntegrationJob) corev1.ResourceList {
+	used := corev1.ResourceList{}
+	for _, job := range running {
+		addResources(used, resourceRequests(job))
+	}
+	return used
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, q := range resources {
+		sum := total[name]
+		sum.Add(q)
+		total[name] = sum
+	}
+}
+
+// checkQuota returns an error if the requests exceed the quota, in addition to the used resources
+func checkQuota(quota, used, requests corev1.ResourceList) error {
+	var names []string
+	for name := range quota {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		limit := quota[corev1.ResourceName(name)]
+		request := requests[corev1.ResourceName(name)]
+		inUse := used[corev1.ResourceName(name)]
+		total := inUse.DeepCopy()
+		total.Add(request)
+		if total.Cmp(limit) <= 0 {
+			continue
+		}
+		if inUse.IsZero() {
+			return fmt.Errorf("%s request %s exceeds the quota %s", name, request.String(), limit.String())
+		}
+		return fmt.Errorf("%s request %s exceeds the quota %s, with %s in use", name, request.String(), limit.String(), inUse.String())
+	}
+	return nil
+}
+
+// resolveTimeout is how long the scheduler waits for the jobs of an IntegrationJob to be resolved. The resolution goes on
+// in the background after the timeout, and its result is used once it is done
+var resolveTimeout = 10 * time.Second
+
+const (
+	// resolveBackoff is the initial delay before retrying a failed resolution, doubled for each consecutive failure
+	resolveBackoff = 10 * time.Second
+	// resolveMaxBackoff is the maximum delay before retrying a failed resolution
+	resolveMaxBackoff = 5 * time.Minute
+)
+
+// resolutions caches the resolutions of the jobs of the IntegrationJobs, by the IntegrationJobs' names
+type resolutions struct {
+	items map[types.NamespacedName]*resolution
+	lock  sync.Mutex
+}
+
+func newResolutions() *resolutions {
+	return &resolutions{items: map[types.NamespacedName]*resolution{}}
+}
+
+// resolution is a resolution of the jobs of an IntegrationJob by the pipeline manager
+type resolution struct {
+	// uid is the uid of the IntegrationJob, not to use the jobs of another IntegrationJob with the same name
+	uid types.UID
+	// done is closed when the resolution is finished, successfully or not
+	done chan struct{}
+	// deadline is the time until which the scheduler waits for the resolution
+	deadline time.Time
+
+	jobs cicdv1.Jobs
+	err  error
+
+	// failures is the number of the consecutive failures of the IntegrationJob's resolutions
+	failures int
+	// retryAt is the time after which a failed resolution is retried
+	retryAt time.Time
+}
+
+func (r *resolution) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// resolve returns a copy of the IntegrationJob whose jobs are resolved by the pipeline manager, i.e., the job templates
+// are rendered and the resources of the Tekton Tasks are reflected. The resolved jobs are cached, as the jobs of an
+// IntegrationJob never change. The resolution, which may fetch remote Tasks, runs in the background and is waited for
+// at most resolveTimeout. Failures are cached as well and retried with an exponential backoff.
+// The IntegrationJob itself is returned if its jobs are not resolved (yet)
+func (s *scheduler) resolve(job *cicdv1.IntegrationJob) *cicdv1.IntegrationJob {
+	key := types.NamespacedName{Name: job.Name, Namespace: job.Namespace}
+
+	s.resolutions.lock.Lock()
+	r, exist := s.resolutions.items[key]
+	if !exist || r.uid != job.UID || (r.finished() && r.err != nil && time.Now().After(r.retryAt)) {
+		failures := 0
+		if exist && r.uid == job.UID {
+			failures = r.failures
+		}
+		r = &resolution{uid: job.UID, done: make(chan struct{}), deadline: time.Now().Add(resolveTimeout), failures: failures}
+		s.resolutions.items[key] = r
+		go s.runResolution(job.DeepCopy(), r)
+	}
+	s.resolutions.lock.Unlock()
+
+	if !r.finished() {
+		select {
+		case <-r.done:
+		case <-time.After(time.Until(r.deadline)):
+			return job
+		}
+	}
+	if r.err != nil {
+		return job
+	}
+	resolved := *job
+	resolved.Spec.Jobs = r.jobs
+	return &resolved
+}
+
+// runResolution resolves the jobs of the IntegrationJob and finishes the resolution. The scheduler is called again
+// if the resolution is finished after the scheduler stopped waiting for it
+func (s *scheduler) runResolution(job *cicdv1.IntegrationJob, r *resolution) {
+	jobs, err := s.pm.ResolveJobs(job)
+
+	s.resolutions.lock.Lock()
+	r.jobs, r.err = jobs, err
+	if err != nil {
+		backoff := resolveMaxBackoff
+		if r.failures < 5 {
+			backoff = resolveBackoff << r.failures
+		}
+		r.failures++
+		r.retryAt = time.Now().Add(backoff)
+		log.Error(err, fmt.Sprintf("cannot resolve the jobs of %s/%s, their resource requests may not be counted, retrying after %s", job.Namespace, job.Name, backoff))
+	} else {
+		r.failures = 0
+	}
+	late := time.Now().After(r.deadline)
+	close(r.done)
+	s.resolutions.lock.Unlock()
+
+	if late && err == nil && len(s.caller) < cap(s.caller) {
+		s.caller <- struct{}{}
+	}
+}
+
+// pruneResolvedJobs deletes the resolutions of the IntegrationJobs which are not pending nor running anymore
+func (s *scheduler) pruneResolvedJobs() {
+	inPool := map[types.NamespacedName]struct{}{}
+	addToPool := func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok {
+			inPool[types.NamespacedName{Name: j.Name, Namespace: j.Namespace}] = struct{}{}
+		}
+	}
+	s.jobPool.Pending().ForEach(addToPool)
+	s.jobPool.Running().ForEach(addToPool)
+
+	s.resolutions.lock.Lock()
+	defer s.resolutions.lock.Unlock()
+	for key := range s.resolutions.items {
+		if _, exist := inPool[key]; !exist {
+			delete(s.resolutions.items, key)
+		}
+	}
+}
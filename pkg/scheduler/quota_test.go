@@ -0,0 +1,164 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestResourceRequests(t *testing.T) {
+	job := &cicdv1.IntegrationJob{
+		Spec: cicdv1.IntegrationJobSpec{
+			Jobs: cicdv1.Jobs{
+				{Container: corev1.Container{Name: "test", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				}}},
+				{Container: corev1.Container{Name: "build", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+				}}},
+				{Container: corev1.Container{Name: "lint"}},
+			},
+		},
+	}
+
+	requests := resourceRequests(job)
+	cpu := requests[corev1.ResourceCPU]
+	memory := requests[corev1.ResourceMemory]
+	require.Equal(t, "2", cpu.String())
+	require.Equal(t, "1536Mi", memory.String())
+}
+
+func TestCheckQuota(t *testing.T) {
+	quota := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("8Gi")}
+
+	tc := map[string]struct {
+		used     corev1.ResourceList
+		requests corev1.ResourceList
+
+		errorOccurs  bool
+		errorMessage string
+	}{
+		"fits": {
+			used:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+		},
+		"noRequests": {
+			used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+		"exceedsWithUsage": {
+			used:         corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+			requests:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			errorOccurs:  true,
+			errorMessage: "cpu request 2 exceeds the quota 4, with 3 in use",
+		},
+		"exceedsByItself": {
+			requests:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+			errorOccurs:  true,
+			errorMessage: "memory request 16Gi exceeds the quota 8Gi",
+		},
+		"notInQuota": {
+			requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := checkQuota(quota, c.used, c.requests)
+			if c.errorOccurs {
+				require.Error(t, err)
+				require.Equal(t, c.errorMessage, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestScheduler_resolve(t *testing.T) {
+	job := schedulerTestJob("test", "config", "1", time.Now(), cicdv1.IntegrationJobStatePending)
+	job.UID = "test-uid"
+	key := types.NamespacedName{Name: job.Name, Namespace: job.Namespace}
+	resolvedJobs := cicdv1.Jobs{{Container: corev1.Container{Name: "resolved"}}}
+
+	t.Run("cached", func(t *testing.T) {
+		pm := &fakePipelineManager{resolvedJobs: map[string]cicdv1.Jobs{job.Name: resolvedJobs}}
+		sch := &scheduler{caller: make(chan struct{}, 1), pm: pm, resolutions: newResolutions()}
+
+		require.Equal(t, resolvedJobs, sch.resolve(job).Spec.Jobs)
+		require.Equal(t, resolvedJobs, sch.resolve(job).Spec.Jobs)
+		require.Equal(t, int32(1), atomic.LoadInt32(&pm.resolveCalls))
+	})
+
+	t.Run("failureBackoff", func(t *testing.T) {
+		pm := &fakePipelineManager{resolveErr: fmt.Errorf("cannot fetch task")}
+		sch := &scheduler{caller: make(chan struct{}, 1), pm: pm, resolutions: newResolutions()}
+
+		require.Equal(t, job, sch.resolve(job))
+		require.Equal(t, job, sch.resolve(job))
+		require.Equal(t, int32(1), atomic.LoadInt32(&pm.resolveCalls), "failure should be cached")
+		r := sch.resolutions.items[key]
+		require.Equal(t, 1, r.failures)
+		require.WithinDuration(t, time.Now().Add(resolveBackoff), r.retryAt, time.Second)
+
+		// Retried after the backoff, with a doubled backoff
+		r.retryAt = time.Now().Add(-time.Second)
+		require.Equal(t, job, sch.resolve(job))
+		require.Equal(t, int32(2), atomic.LoadInt32(&pm.resolveCalls))
+		r = sch.resolutions.items[key]
+		require.Equal(t, 2, r.failures)
+		require.WithinDuration(t, time.Now().Add(2*resolveBackoff), r.retryAt, time.Second)
+
+		// Succeeds after the failures
+		pm.resolveErr = nil
+		r.retryAt = time.Now().Add(-time.Second)
+		require.Equal(t, job.Spec.Jobs, sch.resolve(job).Spec.Jobs)
+		require.Equal(t, 0, sch.resolutions.items[key].failures)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		timeout := resolveTimeout
+		resolveTimeout = 10 * time.Millisecond
+		defer func() { resolveTimeout = timeout }()
+
+		pm := &fakePipelineManager{resolvedJobs: map[string]cicdv1.Jobs{job.Name: resolvedJobs}, resolveBlock: make(chan struct{})}
+		sch := &scheduler{caller: make(chan struct{}, 1), pm: pm, resolutions: newResolutions()}
+
+		// Not resolved yet
+		require.Equal(t, job, sch.resolve(job))
+		require.Equal(t, job, sch.resolve(job))
+
+		// Scheduler is called once resolved, and the resolved jobs are used
+		close(pm.resolveBlock)
+		select {
+		case <-sch.caller:
+		case <-time.After(time.Second):
+			require.Fail(t, "scheduler is not called")
+		}
+		require.Equal(t, resolvedJobs, sch.resolve(job).Spec.Jobs)
+		require.Equal(t, int32(1), atomic.LoadInt32(&pm.resolveCalls))
+	})
+}
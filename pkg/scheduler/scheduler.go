@@ -24,13 +24,17 @@ import (
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
 	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/events"
 	"github.com/tmax-cloud/cicd-operator/pkg/pipelinemanager"
+	"github.com/tmax-cloud/cicd-operator/pkg/remotecluster"
 	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
 	"github.com/tmax-cloud/cicd-operator/pkg/structs"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -41,15 +45,20 @@ import (
 var log = logf.Log.WithName("job-scheduler")
 
 // New is a constructor for a scheduler
-func New(c client.Client, s *runtime.Scheme, pm pipelinemanager.PipelineManager) *scheduler {
+func New(c client.Client, apiReader client.Reader, s *runtime.Scheme, recorder record.EventRecorder, pm pipelinemanager.PipelineManager) *scheduler {
 	log.Info("New scheduler")
 	sch := &scheduler{
 		k8sClient: c,
+		apiReader: apiReader,
 		scheme:    s,
+		recorder:  recorder,
 		caller:    make(chan struct{}, 1),
 		pm:        pm,
+		wakeUps:   newWakeUps(),
+
+		resolutions: newResolutions(),
 	}
-	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	sch.jobPool = pool.New(sch.caller, priorityCompare)
 	go sch.start()
 	return sch
 }
@@ -63,7 +72,10 @@ type Scheduler interface {
 // running (in a jobPool)
 type scheduler struct {
 	k8sClient client.Client
+	// apiReader reads the objects not cached by the manager, e.g., Pods
+	apiReader client.Reader
 	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
 
 	pm pipelinemanager.PipelineManager
 
@@ -73,6 +85,12 @@ type scheduler struct {
 	// Since scheduler lists resources by itself, the actual scheduling logic should be executed only once even when
 	// Schedule is called for several times
 	caller chan struct{}
+
+	// wakeUps runs the scheduling logic at the times, e.g., when the execution windows open
+	wakeUps *wakeUps
+
+	// resolutions caches the jobs of the IntegrationJobs resolved by the pipeline manager, for their resource requests
+	resolutions *resolutions
 }
 
 // Notify notifies scheduler to sync
@@ -96,20 +114,56 @@ func (s scheduler) run() {
 	log.Info("scheduling...")
 	availableCnt := configs.MaxPipelineRun - s.jobPool.Running().Len()
 
+	// Keep the order of the pending jobs across restarts
+	s.persistQueueSequences()
+
 	// Check if running jobs are actually running (has pipelineRun, pipelineRun is running)
 	s.jobPool.Running().ForEach(s.filterOutRunning(&availableCnt))
 
 	// Check if pending jobs are timeouted
 	s.jobPool.Pending().ForEach(s.filterOutPending())
 
+	// Forget the resolved jobs of the IntegrationJobs not in the pool anymore
+	s.pruneResolvedJobs()
+
+	// Order the pending jobs with the IntegrationConfigs' scheduling weights and the aging, and record their positions
+	s.syncWeights()
+	s.syncAging()
+	s.updateQueueStatus()
+	s.updateQueueMetrics()
+
 	// If the number of running jobs is greater or equals to the max pipeline run, no scheduling is allowed
 	if availableCnt <= 0 {
 		log.Info("Max number of PipelineRuns already exist")
 		return
 	}
 
-	// Schedule if available
-	s.jobPool.Pending().ForEach(s.schedulePending(&availableCnt))
+	// Group the running jobs by their IntegrationConfigs, for the IntegrationConfigs' concurrency limits and resource
+	// quotas
+	running := runningJobs{}
+	s.jobPool.Running().ForEach(func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok {
+			running.add(j.IntegrationJob)
+		}
+	})
+
+	// Get the free resources of the cluster, not to create PipelineRuns whose pods would just be pending
+	var capacity *clusterCapacity
+	if configs.CapacityAwareAdmission || s.hasPendingGangJobs() {
+		c, err := newClusterCapacity(s.k8sClient, s.apiReader)
+		if err != nil {
+			log.Error(err, "cannot get the cluster capacity, scheduling without it")
+		}
+		capacity = c
+	}
+
+	// Schedule with the configured policy
+	policy, err := getPolicy(configs.SchedulerPolicy)
+	if err != nil {
+		log.Error(err, "scheduling with the priority policy")
+	}
+	snapshot := s.snapshot(availableCnt)
+	policy.Schedule(snapshot, s.admitFunc(snapshot, running, capacity))
 }
 
 func (s *scheduler) filterOutRunning(availableCnt *int) func(structs.Item) {
@@ -118,8 +172,13 @@ func (s *scheduler) filterOutRunning(availableCnt *int) func(structs.Item) {
 		if !ok {
 			return
 		}
+		cli, err := remotecluster.ClientFor(s.k8sClient, j.IntegrationJob)
+		if err != nil {
+			log.Error(err, "")
+			return
+		}
 		pr := &tektonv1beta1.PipelineRun{}
-		err := s.k8sClient.Get(context.Background(), types.NamespacedName{Name: pipelinemanager.Name(j.IntegrationJob), Namespace: j.Namespace}, pr)
+		err = cli.Get(context.Background(), types.NamespacedName{Name: pipelinemanager.Name(j.IntegrationJob), Namespace: j.Namespace}, pr)
 		// If PipelineRun is not found or is already completed, is not actually running
 		if (err != nil && errors.IsNotFound(err)) || (err == nil && pr.Status.CompletionTime != nil) {
 			*availableCnt = *availableCnt + 1
@@ -133,78 +192,291 @@ func (s *scheduler) filterOutPending() func(structs.Item) {
 		if !ok {
 			return
 		}
-		now := time.Now()
-		if j.CreationTimestamp.Time.Add(j.Spec.Timeout.Duration).Before(now) {
-			msg := fmt.Errorf("integration job %s_%s is failed due to timeout", j.Namespace, j.Name)
-			if err := s.patchJobScheduleFailed(j.IntegrationJob, msg.Error()); err != nil {
+		if msg := getPendingTimeoutMessage(j.IntegrationJob, time.Now()); msg != "" {
+			if err := s.patchJobScheduleFailed(j.IntegrationJob, cicdv1.IntegrationJobReasonTimedOut, msg); err != nil {
 				log.Error(err, "")
 			}
 		}
 	}
 }
 
-func (s *scheduler) schedulePending(availableCnt *int) func(structs.Item) {
-	return func(item structs.Item) {
-		if *availableCnt <= 0 {
-			return
+// getPendingTimeoutMessage returns why the pending job timed out, or an empty string if it did not
+// The pending job times out if it's not scheduled within its timeout, or any of its jobs' timeout
+func getPendingTimeoutMessage(job *cicdv1.IntegrationJob, now time.Time) string {
+	created := job.CreationTimestamp.Time
+	if job.Spec.Timeout != nil && created.Add(job.Spec.Timeout.Duration).Before(now) {
+		return fmt.Sprintf("IntegrationJob timed out, not scheduled within %s", job.Spec.Timeout.Duration)
+	}
+	for _, j := range job.Spec.Jobs {
+		if j.Timeout != nil && created.Add(j.Timeout.Duration).Before(now) {
+			return fmt.Sprintf("Job %s timed out, not scheduled within %s", j.Name, j.Timeout.Duration)
 		}
-		jobNode, ok := item.(*pool.JobNode)
-		if !ok {
-			return
+	}
+	return ""
+}
+
+// schedulePending creates a PipelineRun for the pending job, if it can be admitted. It returns if the job takes a
+// PipelineRun slot, i.e., a PipelineRun is created or already exists
+func (s *scheduler) schedulePending(job *cicdv1.IntegrationJob, running runningJobs, capacity *clusterCapacity) bool {
+	// Cancelled or superseded jobs are to be cancelled by the IntegrationJob controller
+	if job.Spec.Cancelled || job.Annotations[cicdv1.JobAnnotationSupersededBy] != "" {
+		return false
+	}
+
+	// Jobs failed while pending (e.g., timed out) are removed from the pool by the IntegrationJob controller
+	if job.Status.State == cicdv1.IntegrationJobStateFailed {
+		return false
+	}
+
+	// Paused jobs wait until they are resumed
+	if job.Spec.Paused {
+		if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonPaused, "IntegrationJob is paused"); err != nil {
+			log.Error(err, "")
 		}
+		return false
+	}
 
-		// Check if PipelineRun already exists
-		testPr := &tektonv1beta1.PipelineRun{}
-		if err := s.k8sClient.Get(context.Background(), types.NamespacedName{Name: pipelinemanager.Name(jobNode.IntegrationJob), Namespace: jobNode.Namespace}, testPr); err != nil {
-			// Not found error is expected
-			if !errors.IsNotFound(err) {
-				log.Error(err, "")
-				return
-			}
-		} else {
-			// PipelineRun already exists...
-			*availableCnt = *availableCnt - 1
-			return
+	// Get the client of the cluster the PipelineRun runs in
+	cli, err := remotecluster.ClientFor(s.k8sClient, job)
+	if err != nil {
+		if err := s.patchJobScheduleFailed(job, "", err.Error()); err != nil {
+			log.Error(err, "")
 		}
+		log.Error(err, "")
+		return false
+	}
 
-		// Generate and create PipelineRun
-		pr, err := s.pm.Generate(jobNode.IntegrationJob)
-		if err != nil {
-			if err := s.patchJobScheduleFailed(jobNode.IntegrationJob, err.Error()); err != nil {
-				log.Error(err, "")
-			}
+	// Check if PipelineRun already exists
+	testPr := &tektonv1beta1.PipelineRun{}
+	if err := cli.Get(context.Background(), types.NamespacedName{Name: pipelinemanager.Name(job), Namespace: job.Namespace}, testPr); err != nil {
+		// Not found error is expected
+		if !errors.IsNotFound(err) {
 			log.Error(err, "")
-			return
+			return false
 		}
-		if err := controllerutil.SetControllerReference(jobNode.IntegrationJob, pr, s.scheme); err != nil {
-			if err := s.patchJobScheduleFailed(jobNode.IntegrationJob, err.Error()); err != nil {
+	} else {
+		// PipelineRun already exists...
+		return true
+	}
+
+	// Check the IntegrationConfig's concurrency limit and resource quota, and the cluster capacity
+	if !s.admit(job, running, capacity) {
+		return false
+	}
+
+	// Generate and create PipelineRun
+	pr, err := s.pm.Generate(job)
+	if err != nil {
+		if err := s.patchJobScheduleFailed(job, "", err.Error()); err != nil {
+			log.Error(err, "")
+		}
+		log.Error(err, "")
+		return false
+	}
+	// PipelineRuns in remote clusters cannot be owned by the IntegrationJobs, so they're deleted by the IntegrationJob
+	// controller when the IntegrationJobs are deleted
+	if job.Spec.RemoteCluster == nil {
+		if err := controllerutil.SetControllerReference(job, pr, s.scheme); err != nil {
+			if err := s.patchJobScheduleFailed(job, "", err.Error()); err != nil {
 				log.Error(err, "")
 			}
 			log.Error(err, "")
-			return
+			return false
+		}
+	}
+
+	log.Info(fmt.Sprintf("Scheduled %s / %s / %s", job.Name, job.Namespace, job.CreationTimestamp))
+	// Create PipelineRun only when there is no Pipeline exists
+	if err := cli.Create(context.Background(), pr); err != nil {
+		if err := s.patchJobScheduleFailed(job, "", err.Error()); err != nil {
+			log.Error(err, "")
 		}
+		log.Error(err, "")
+		return false
+	}
+	s.recorder.Event(job, corev1.EventTypeNormal, events.ReasonIntegrationJobScheduled, fmt.Sprintf("PipelineRun %s is created", pr.Name))
+	observeAdmission(job)
+
+	running.add(job)
+	if capacity != nil && job.Spec.RemoteCluster == nil {
+		capacity.reserve(s.resolve(job))
+	}
+	return true
+}
 
-		log.Info(fmt.Sprintf("Scheduled %s / %s / %s", jobNode.Name, jobNode.Namespace, jobNode.CreationTimestamp))
-		// Create PipelineRun only when there is no Pipeline exists
-		if err := s.k8sClient.Create(context.Background(), pr); err != nil {
-			if err := s.patchJobScheduleFailed(jobNode.IntegrationJob, err.Error()); err != nil {
+// snapshot takes a snapshot of the pending and running jobs of the job pool, for the scheduling policy
+func (s *scheduler) snapshot(availableCnt int) *Snapshot {
+	snapshot := &Snapshot{Available: availableCnt}
+	s.jobPool.Pending().ForEach(func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok {
+			snapshot.Pending = append(snapshot.Pending, j.IntegrationJob)
+		}
+	})
+	s.jobPool.Running().ForEach(func(item structs.Item) {
+		if j, ok := item.(*pool.JobNode); ok {
+			snapshot.Running = append(snapshot.Running, j.IntegrationJob)
+		}
+	})
+	return snapshot
+}
+
+// admitFunc returns an AdmitFunc for the policy, which schedules the pending jobs of the snapshot, each at most once,
+// within the available slots
+func (s *scheduler) admitFunc(snapshot *Snapshot, running runningJobs, capacity *clusterCapacity) AdmitFunc {
+	pending := map[*cicdv1.IntegrationJob]struct{}{}
+	for _, j := range snapshot.Pending {
+		pending[j] = struct{}{}
+	}
+	return func(job *cicdv1.IntegrationJob) bool {
+		if snapshot.Available <= 0 {
+			return false
+		}
+		if _, exist := pending[job]; !exist {
+			return false
+		}
+		if !s.schedulePending(job, running, capacity) {
+			return false
+		}
+		delete(pending, job)
+		snapshot.Available--
+		snapshot.Running = append(snapshot.Running, job)
+		return true
+	}
+}
+
+// admit checks if the job can be scheduled within its namespace's concurrency limit, its IntegrationConfig's
+// execution windows, concurrency limit and resource quota, and the cluster capacity (if it's given)
+// The job waits with the reason if the running jobs have reached the limits, or fails if it exceeds the resource quota
+// by itself
+func (s *scheduler) admit(job *cicdv1.IntegrationJob, running runningJobs, capacity *clusterCapacity) bool {
+	if err := checkNamespaceConcurrency(configs.GetNamespaceMaxPipelineRun(job.Namespace), running.namespaceCount(job.Namespace), job.Namespace); err != nil {
+		if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonConcurrencyLimited, fmt.Sprintf("waiting for concurrency limit: %s", err.Error())); err != nil {
+			log.Error(err, "")
+		}
+		return false
+	}
+
+	config := &cicdv1.IntegrationConfig{}
+	if err := s.k8sClient.Get(context.Background(), types.NamespacedName{Name: job.Spec.ConfigRef.Name, Namespace: job.Namespace}, config); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "")
+			return false
+		}
+		return s.admitCapacity(job, capacity)
+	}
+
+	if !s.admitWindow(job, config) {
+		return false
+	}
+
+	others := running[configKey(job)]
+
+	if err := checkConcurrency(config.Spec.Concurrency, others, job); err != nil {
+		if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonConcurrencyLimited, fmt.Sprintf("waiting for concurrency limit: %s", err.Error())); err != nil {
+			log.Error(err, "")
+		}
+		return false
+	}
+
+	if len(config.Spec.ResourceQuota) != 0 {
+		requests := resourceRequests(s.resolve(job))
+		if err := checkQuota(config.Spec.ResourceQuota, nil, requests); err != nil {
+			if err := s.patchJobScheduleFailed(job, cicdv1.IntegrationJobReasonQuotaExceeded, err.Error()); err != nil {
 				log.Error(err, "")
 			}
-			log.Error(err, "")
-			return
+			return false
+		}
+		var resolvedOthers []*cicdv1.IntegrationJob
+		for _, o := range others {
+			resolvedOthers = append(resolvedOthers, s.resolve(o))
+		}
+		if err := checkQuota(config.Spec.ResourceQuota, usedResources(resolvedOthers), requests); err != nil {
+			if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonQuotaExceeded, fmt.Sprintf("waiting for resource quota: %s", err.Error())); err != nil {
+				log.Error(err, "")
+			}
+			return false
 		}
+	}
+
+	return s.admitCapacity(job, capacity)
+}
 
-		*availableCnt = *availableCnt - 1
+// admitCapacity checks if the job fits in the cluster capacity, or if all of its jobs fit at once if it's gang
+// scheduled. The job waits with the reason if it does not, or fails if any of its jobs never fits in any node
+// Jobs running in remote clusters are not limited by the capacity of the operator's cluster
+func (s *scheduler) admitCapacity(job *cicdv1.IntegrationJob, capacity *clusterCapacity) bool {
+	if capacity == nil || job.Spec.RemoteCluster != nil {
+		return true
+	}
+	check := capacity.check
+	if job.Spec.GangScheduling {
+		check = capacity.checkGang
+	} else if !configs.CapacityAwareAdmission {
+		return true
 	}
+	resolved := s.resolve(job)
+	if err := capacity.checkFeasible(resolved); err != nil {
+		if err := s.patchJobScheduleFailed(job, cicdv1.IntegrationJobReasonUnschedulable, err.Error()); err != nil {
+			log.Error(err, "")
+		}
+		return false
+	}
+	if err := check(resolved); err != nil {
+		if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonWaitingForCapacity, fmt.Sprintf("waiting for capacity: %s", err.Error())); err != nil {
+			log.Error(err, "")
+		}
+		return false
+	}
+	return true
 }
 
-func (s *scheduler) patchJobScheduleFailed(job *cicdv1.IntegrationJob, msg string) error {
+func (s *scheduler) patchJobScheduleFailed(job *cicdv1.IntegrationJob, reason cicdv1.IntegrationJobReason, msg string) error {
 	original := job.DeepCopy()
 
 	job.Status.State = cicdv1.IntegrationJobStateFailed
+	job.Status.Reason = reason
 	job.Status.Message = msg
 	job.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 
 	p := client.MergeFrom(original)
 	return s.k8sClient.Status().Patch(context.Background(), job, p)
 }
+
+// patchJobWaiting sets the reason why the pending job is not scheduled yet
+func (s *scheduler) patchJobWaiting(job *cicdv1.IntegrationJob, reason cicdv1.IntegrationJobReason, msg string) error {
+	if job.Status.Reason == reason && job.Status.Message == msg {
+		return nil
+	}
+	original := job.DeepCopy()
+
+	job.Status.Reason = reason
+	job.Status.Message = msg
+
+	p := client.MergeFrom(original)
+	return s.k8sClient.Status().Patch(context.Background(), job, p)
+}
+
+// runningJobs are the running IntegrationJobs of each IntegrationConfig
+type runningJobs map[string][]*cicdv1.IntegrationJob
+
+func (r runningJobs) add(job *cicdv1.IntegrationJob) {
+	key := configKey(job)
+	r[key] = append(r[key], job)
+}
+
+// namespaceCount counts the running IntegrationJobs of the namespace
+func (r runningJobs) namespaceCount(namespace string) int {
+	cnt := 0
+	for _, jobs := range r {
+		for _, j := range jobs {
+			if j.Namespace == namespace {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+func configKey(job *cicdv1.IntegrationJob) string {
+	return fmt.Sprintf("%s_%s", job.Namespace, job.Spec.ConfigRef.Name)
+}
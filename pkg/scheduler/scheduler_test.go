@@ -0,0 +1,400 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/pipelinemanager"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScheduler_run_resourceQuota(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			ResourceQuota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+	}
+	now := time.Now()
+	running := schedulerTestJob("running", "test-ic", "1", now.Add(-3*time.Minute), cicdv1.IntegrationJobStateRunning)
+	waiting := schedulerTestJob("waiting", "test-ic", "2", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending)
+	tooLarge := schedulerTestJob("too-large", "test-ic", "3", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	templated := schedulerTestJob("templated", "test-ic", "0", now.Add(-30*time.Second), cicdv1.IntegrationJobStatePending)
+	fitting := schedulerTestJob("fitting", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+	noQuota := schedulerTestJob("no-quota", "other-ic", "8", now, cicdv1.IntegrationJobStatePending)
+
+	// Resources of the templated job are known only after its template is rendered
+	pm := &fakePipelineManager{resolvedJobs: map[string]cicdv1.Jobs{
+		"templated": schedulerTestJob("templated", "test-ic", "4", now, cicdv1.IntegrationJobStatePending).Spec.Jobs,
+	}}
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic, running, waiting, tooLarge, templated, fitting, noQuota).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: pm, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{running, waiting, tooLarge, templated, fitting, noQuota} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	for name, scheduled := range map[string]bool{"waiting": false, "too-large": false, "templated": false, "fitting": true, "no-quota": true} {
+		pr := &tektonv1beta1.PipelineRun{}
+		err := cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, pr)
+		require.Equal(t, scheduled, err == nil, name)
+	}
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "waiting", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonQuotaExceeded, ij.Status.Reason)
+	require.Contains(t, ij.Status.Message, "waiting for resource quota: cpu request 2 exceeds the quota 2, with ")
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "too-large", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonQuotaExceeded, ij.Status.Reason)
+	require.Equal(t, "cpu request 3 exceeds the quota 2", ij.Status.Message)
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "templated", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, ij.Status.State)
+	require.Equal(t, "cpu request 4 exceeds the quota 2", ij.Status.Message)
+}
+
+func TestScheduler_run_concurrency(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	ic := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ic", Namespace: "default"},
+		Spec: cicdv1.IntegrationConfigSpec{
+			Concurrency: &cicdv1.Concurrency{Max: 2},
+		},
+	}
+	now := time.Now()
+	running := schedulerTestJob("running", "test-ic", "1", now.Add(-2*time.Minute), cicdv1.IntegrationJobStateRunning)
+	first := schedulerTestJob("first", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	second := schedulerTestJob("second", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(ic, running, first, second).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{running, first, second} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "first", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "second", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonConcurrencyLimited, ij.Status.Reason)
+	require.Equal(t, "waiting for concurrency limit: 2 IntegrationJobs are running, while the limit is 2", ij.Status.Message)
+}
+
+func TestScheduler_run_namespaceConcurrency(t *testing.T) {
+	configs.MaxPipelineRun = 10
+	configs.NamespaceMaxPipelineRun = map[string]int{"*": 2}
+	defer func() { configs.NamespaceMaxPipelineRun = nil }()
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	now := time.Now()
+	running := schedulerTestJob("running", "test-ic", "1", now.Add(-2*time.Minute), cicdv1.IntegrationJobStateRunning)
+	first := schedulerTestJob("first", "other-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	second := schedulerTestJob("second", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(running, first, second).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{running, first, second} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "first", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "second", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonConcurrencyLimited, ij.Status.Reason)
+	require.Equal(t, "waiting for concurrency limit: 2 IntegrationJobs are running in namespace default, while the limit is 2", ij.Status.Message)
+}
+
+func TestScheduler_run_paused(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	now := time.Now()
+	paused := schedulerTestJob("paused", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	paused.Spec.Paused = true
+	normal := schedulerTestJob("normal", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(paused, normal).Build()
+	recorder := record.NewFakeRecorder(10)
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: recorder, caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{paused, normal} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "normal", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "paused", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, "Normal Scheduled PipelineRun normal is created", <-recorder.Events)
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "paused", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonPaused, ij.Status.Reason)
+	require.Equal(t, "IntegrationJob is paused", ij.Status.Message)
+}
+
+func TestScheduler_run_pendingTimeout(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	now := time.Now()
+	ijTimedOut := schedulerTestJob("ij-timed-out", "test-ic", "1", now.Add(-2*time.Hour), cicdv1.IntegrationJobStatePending)
+	jobTimedOut := schedulerTestJob("job-timed-out", "test-ic", "1", now.Add(-20*time.Minute), cicdv1.IntegrationJobStatePending)
+	jobTimedOut.Spec.Jobs[0].Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+	normal := schedulerTestJob("normal", "test-ic", "1", now.Add(-5*time.Minute), cicdv1.IntegrationJobStatePending)
+	normal.Spec.Jobs[0].Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(ijTimedOut, jobTimedOut, normal).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{ijTimedOut, jobTimedOut, normal} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "normal", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	for name, msg := range map[string]string{
+		"ij-timed-out":  "IntegrationJob timed out, not scheduled within 1h0m0s",
+		"job-timed-out": "Job test timed out, not scheduled within 10m0s",
+	} {
+		require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+		ij := &cicdv1.IntegrationJob{}
+		require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ij))
+		require.Equal(t, cicdv1.IntegrationJobStateFailed, ij.Status.State)
+		require.Equal(t, cicdv1.IntegrationJobReasonTimedOut, ij.Status.Reason)
+		require.Equal(t, msg, ij.Status.Message)
+	}
+}
+
+func TestScheduler_run_remoteCluster(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+	utilruntime.Must(corev1.AddToScheme(s))
+
+	now := time.Now()
+	remote := schedulerTestJob("remote", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	remote.Spec.RemoteCluster = &cicdv1.RemoteCluster{KubeconfigSecret: "remote-kubeconfig"}
+	local := schedulerTestJob("local", "test-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(remote, local).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{remote, local} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "local", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "remote", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "remote", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStateFailed, ij.Status.State)
+	require.Equal(t, "cannot get the kubeconfig of the remote cluster: secrets \"remote-kubeconfig\" not found", ij.Status.Message)
+}
+
+func TestScheduler_run_weight(t *testing.T) {
+	configs.MaxPipelineRun = 1
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	weighted := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "weighted-ic", Namespace: "default"},
+		Spec:       cicdv1.IntegrationConfigSpec{IJManageSpec: cicdv1.IntegrationJobManageSpec{SchedulingWeight: 50}},
+	}
+	now := time.Now()
+	high := schedulerTestJob("high", "test-ic", "1", now.Add(-2*time.Minute), cicdv1.IntegrationJobStatePending)
+	high.Spec.Priority = 10
+	low := schedulerTestJob("low", "test-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	weightedLow := schedulerTestJob("weighted-low", "weighted-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(weighted, high, low, weightedLow).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, priorityCompare)
+	for _, j := range []*cicdv1.IntegrationJob{high, low, weightedLow} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "weighted-low", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "high", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	for name, expected := range map[string]cicdv1.IntegrationJobQueueStatus{
+		"weighted-low": {Position: 1, Length: 3, Priority: 50},
+		"high":         {Position: 2, Length: 3, Priority: 10},
+		"low":          {Position: 3, Length: 3, Priority: 0},
+	} {
+		ij := &cicdv1.IntegrationJob{}
+		require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ij))
+		require.Equal(t, &expected, ij.Status.Queue, name)
+	}
+}
+
+func TestScheduler_run_restart(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	// Jobs created within the same second, b entering the queue before a
+	created := time.Now().Truncate(time.Second)
+	b := schedulerTestJob("b", "test-ic", "1", created, cicdv1.IntegrationJobStatePending)
+	a := schedulerTestJob("a", "test-ic", "1", created, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(b, a).Build()
+	newScheduler := func() *scheduler {
+		sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, resolutions: newResolutions()}
+		sch.jobPool = pool.New(sch.caller, priorityCompare)
+		return sch
+	}
+	sch := newScheduler()
+	sch.jobPool.SyncJob(b)
+	time.Sleep(time.Millisecond)
+	sch.jobPool.SyncJob(a)
+
+	// No slot is available, but the queue sequences are persisted
+	configs.MaxPipelineRun = 0
+	sch.run()
+
+	for _, name := range []string{"a", "b"} {
+		ij := &cicdv1.IntegrationJob{}
+		require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ij))
+		require.Contains(t, ij.Annotations, cicdv1.JobAnnotationQueueSequence)
+	}
+
+	// Restarted scheduler rebuilds the queue in the same order, regardless of the sync order
+	restarted := newScheduler()
+	for _, name := range []string{"a", "b"} {
+		ij := &cicdv1.IntegrationJob{}
+		require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, ij))
+		restarted.jobPool.SyncJob(ij)
+	}
+	configs.MaxPipelineRun = 1
+	restarted.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "b", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "a", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+}
+
+func schedulerTestJob(name, config, cpu string, created time.Time, state cicdv1.IntegrationJobState) *cicdv1.IntegrationJob {
+	return &cicdv1.IntegrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.Time{Time: created}},
+		Spec: cicdv1.IntegrationJobSpec{
+			ConfigRef: cicdv1.IntegrationJobConfigRef{Name: config},
+			Jobs: cicdv1.Jobs{{Container: corev1.Container{Name: "test", Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}}},
+			Timeout: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: cicdv1.IntegrationJobStatus{State: state},
+	}
+}
+
+type fakePipelineManager struct {
+	// resolvedJobs are the resolved jobs of the IntegrationJobs, by their names
+	resolvedJobs map[string]cicdv1.Jobs
+	// resolveErr is returned by ResolveJobs, if set
+	resolveErr error
+	// resolveBlock blocks ResolveJobs until it is closed, if set
+	resolveBlock chan struct{}
+	// resolveCalls is the number of the calls of ResolveJobs
+	resolveCalls int32
+}
+
+func (f *fakePipelineManager) Generate(job *cicdv1.IntegrationJob) (*tektonv1beta1.PipelineRun, error) {
+	return &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: pipelinemanager.Name(job), Namespace: job.Namespace}}, nil
+}
+
+func (f *fakePipelineManager) ResolveJobs(job *cicdv1.IntegrationJob) (cicdv1.Jobs, error) {
+	atomic.AddInt32(&f.resolveCalls, 1)
+	if f.resolveBlock != nil {
+		<-f.resolveBlock
+	}
+	if f.resolveErr != nil {
+		return nil, f.resolveErr
+	}
+	if jobs, exist := f.resolvedJobs[job.Name]; exist {
+		return jobs, nil
+	}
+	return job.Spec.Jobs, nil
+}
+
+func (f *fakePipelineManager) ReflectStatus(_ *tektonv1beta1.PipelineRun, _ *cicdv1.IntegrationJob, _ *cicdv1.IntegrationConfig) error {
+	return nil
+}
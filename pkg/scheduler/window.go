@@ -0,0 +1,77 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+)
+
+// admitWindow checks if any execution window of the IntegrationConfig is open. The job waits with the reason if not,
+// and the scheduler is woken up when the next window opens
+func (s *scheduler) admitWindow(job *cicdv1.IntegrationJob, config *cicdv1.IntegrationConfig) bool {
+	now := time.Now()
+	if config.Spec.ExecutionWindows.IsOpen(now) {
+		return true
+	}
+
+	msg := "waiting for the execution window"
+	if next := config.Spec.ExecutionWindows.NextOpening(now); !next.IsZero() {
+		msg = fmt.Sprintf("waiting for the execution window opening at %s", next.UTC().Format(time.RFC3339))
+		s.wakeUps.at(next, s.caller)
+	}
+	if err := s.patchJobWaiting(job, cicdv1.IntegrationJobReasonWaitingForWindow, msg); err != nil {
+		log.Error(err, "")
+	}
+	return false
+}
+
+// wakeUps wakes up the scheduler at the times, such as the openings of the execution windows
+type wakeUps struct {
+	times map[time.Time]struct{}
+	lock  sync.Mutex
+}
+
+func newWakeUps() *wakeUps {
+	return &wakeUps{times: map[time.Time]struct{}{}}
+}
+
+// at sends a signal to the channel at the time, only once for the same time
+func (w *wakeUps) at(t time.Time, ch chan struct{}) {
+	if w == nil {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, exist := w.times[t]; exist {
+		return
+	}
+	w.times[t] = struct{}{}
+
+	time.AfterFunc(time.Until(t), func() {
+		w.lock.Lock()
+		delete(w.times, t)
+		w.lock.Unlock()
+
+		if len(ch) < cap(ch) {
+			ch <- struct{}{}
+		}
+	})
+}
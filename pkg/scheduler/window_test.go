@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	cicdv1 "github.com/tmax-cloud/cicd-operator/api/v1"
+	"github.com/tmax-cloud/cicd-operator/internal/configs"
+	"github.com/tmax-cloud/cicd-operator/pkg/scheduler/pool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScheduler_run_window(t *testing.T) {
+	configs.MaxPipelineRun = 10
+
+	s := runtime.NewScheme()
+	utilruntime.Must(cicdv1.AddToScheme(s))
+	utilruntime.Must(tektonv1beta1.AddToScheme(s))
+
+	// Daily windows opening 30 minutes ago and 2 hours later, each for an hour
+	now := time.Now().UTC()
+	window := func(opening time.Time) cicdv1.ExecutionWindow {
+		return cicdv1.ExecutionWindow{Cron: fmt.Sprintf("%d %d * * *", opening.Minute(), opening.Hour()), Duration: metav1.Duration{Duration: time.Hour}}
+	}
+	nextOpening := now.Add(2 * time.Hour).Truncate(time.Minute)
+	openIC := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "open-ic", Namespace: "default"},
+		Spec:       cicdv1.IntegrationConfigSpec{ExecutionWindows: cicdv1.ExecutionWindows{window(now.Add(-30 * time.Minute))}},
+	}
+	closedIC := &cicdv1.IntegrationConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "closed-ic", Namespace: "default"},
+		Spec:       cicdv1.IntegrationConfigSpec{ExecutionWindows: cicdv1.ExecutionWindows{window(nextOpening)}},
+	}
+	open := schedulerTestJob("open", "open-ic", "1", now.Add(-1*time.Minute), cicdv1.IntegrationJobStatePending)
+	closed := schedulerTestJob("closed", "closed-ic", "1", now, cicdv1.IntegrationJobStatePending)
+
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(openIC, closedIC, open, closed).Build()
+	sch := &scheduler{k8sClient: cli, scheme: s, recorder: record.NewFakeRecorder(10), caller: make(chan struct{}, 1), pm: &fakePipelineManager{}, wakeUps: newWakeUps(), resolutions: newResolutions()}
+	sch.jobPool = pool.New(sch.caller, fifoCompare)
+	for _, j := range []*cicdv1.IntegrationJob{open, closed} {
+		sch.jobPool.SyncJob(j)
+	}
+
+	sch.run()
+
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "open", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+	require.Error(t, cli.Get(context.Background(), types.NamespacedName{Name: "closed", Namespace: "default"}, &tektonv1beta1.PipelineRun{}))
+
+	ij := &cicdv1.IntegrationJob{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Name: "closed", Namespace: "default"}, ij))
+	require.Equal(t, cicdv1.IntegrationJobStatePending, ij.Status.State)
+	require.Equal(t, cicdv1.IntegrationJobReasonWaitingForWindow, ij.Status.Reason)
+	require.Equal(t, fmt.Sprintf("waiting for the execution window opening at %s", nextOpening.Format(time.RFC3339)), ij.Status.Message)
+
+	// The scheduler is to be woken up when the window opens
+	sch.wakeUps.lock.Lock()
+	require.Len(t, sch.wakeUps.times, 1)
+	sch.wakeUps.lock.Unlock()
+}
+
+func TestWakeUps_at(t *testing.T) {
+	w := newWakeUps()
+	ch := make(chan struct{}, 1)
+	at := time.Now().Add(50 * time.Millisecond)
+
+	w.at(at, ch)
+	w.at(at, ch)
+	w.lock.Lock()
+	require.Len(t, w.times, 1)
+	w.lock.Unlock()
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("not woken up")
+	}
+	require.Eventually(t, func() bool {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		return len(w.times) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Nil wakeUps does nothing
+	var nilWakeUps *wakeUps
+	nilWakeUps.at(at, ch)
+}
@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -65,6 +66,17 @@ func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Route the event to the repository it came from, if the IntegrationConfig has multiple repositories
+	if len(config.Spec.Git.Repositories) > 0 {
+		repo := getWebhookRepository(body)
+		if !config.Spec.Git.HasRepository(repo) {
+			_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("req: %s, repository %s is not configured in IntegrationConfig %s/%s", reqID, repo, ns, configName))
+			log.Info("Unknown repository", "repository", repo)
+			return
+		}
+		config = config.ForRepository(repo)
+	}
+
 	gitCli, err := utils.GetGitCli(config, h.k8sClient)
 	if err != nil {
 		log.Info("Cannot initialize git cli", "error", err.Error())
@@ -89,3 +101,26 @@ func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		log.Error(err, "")
 	}
 }
+
+// webhookRepository is a common part of the webhook bodies, containing the repository name
+// Github sends repository.full_name, while gitlab sends project.path_with_namespace
+type webhookRepository struct {
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
+
+// getWebhookRepository extracts the repository name from the webhook body
+func getWebhookRepository(body []byte) string {
+	repo := &webhookRepository{}
+	if err := json.Unmarshal(body, repo); err != nil {
+		return ""
+	}
+	if repo.Repository.FullName != "" {
+		return repo.Repository.FullName
+	}
+	return repo.Project.PathWithNamespace
+}
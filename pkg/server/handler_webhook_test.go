@@ -0,0 +1,52 @@
+/*
+ Copyright 2021 The CI/CD Operator Authors
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWebhookRepository(t *testing.T) {
+	tc := map[string]struct {
+		body string
+
+		expectedRepo string
+	}{
+		"github": {
+			body:         `{"action": "opened", "repository": {"name": "cicd-operator", "full_name": "tmax-cloud/cicd-operator"}}`,
+			expectedRepo: "tmax-cloud/cicd-operator",
+		},
+		"gitlab": {
+			body:         `{"object_kind": "push", "project": {"name": "cicd-operator", "path_with_namespace": "tmax-cloud/cicd-operator"}}`,
+			expectedRepo: "tmax-cloud/cicd-operator",
+		},
+		"noRepository": {
+			body: `{"action": "opened"}`,
+		},
+		"invalidBody": {
+			body: `{"action": `,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expectedRepo, getWebhookRepository([]byte(c.body)))
+		})
+	}
+}